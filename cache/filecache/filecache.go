@@ -22,6 +22,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gohugoio/hugo/common/hugio"
@@ -42,12 +43,20 @@ const (
 // Cache caches a set of files in a directory. This is usually a file on
 // disk, but since this is backed by an Afero file system, it can be anything.
 type Cache struct {
+	// The number of lookups found and not found in the cache since it was
+	// created. Keep these first for 64-bit alignment.
+	hits   uint64
+	misses uint64
+
 	Fs afero.Fs
 
 	// Max age for items in this cache. Negative duration means forever,
 	// 0 is effectively turning this cache off.
 	maxAge time.Duration
 
+	// Max total size in bytes of the files in this cache, 0 means no limit.
+	maxSize int64
+
 	// When set, we just remove this entire root directory on expiration.
 	pruneAllRootDir string
 
@@ -266,6 +275,16 @@ func (c *Cache) Get(id string) (ItemInfo, io.ReadCloser, error) {
 // getOrRemove gets the file with the given id. If it's expired, it will
 // be removed.
 func (c *Cache) getOrRemove(id string) hugio.ReadSeekCloser {
+	r := c.getOrRemoveNoCount(id)
+	if r == nil {
+		atomic.AddUint64(&c.misses, 1)
+	} else {
+		atomic.AddUint64(&c.hits, 1)
+	}
+	return r
+}
+
+func (c *Cache) getOrRemoveNoCount(id string) hugio.ReadSeekCloser {
 	if c.maxAge == 0 {
 		// No caching.
 		return nil
@@ -374,6 +393,7 @@ func NewCaches(p *helpers.PathSpec) (Caches, error) {
 		}
 
 		m[k] = NewCache(bfs, v.MaxAge, pruneAllRootDir)
+		m[k].maxSize = v.MaxSize
 	}
 
 	return m, nil
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 
 	"errors"
 
+	"github.com/dustin/go-humanize"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/afero"
 )
@@ -51,6 +53,7 @@ const (
 	cacheKeyAssets      = "assets"
 	cacheKeyModules     = "modules"
 	cacheKeyGetResource = "getresource"
+	cacheKeyEnrichment  = "enrichment"
 )
 
 type Configs map[string]Config
@@ -78,6 +81,7 @@ var defaultCacheConfigs = Configs{
 		MaxAge: -1, // Never expire
 		Dir:    cacheDirProject,
 	},
+	cacheKeyEnrichment: defaultCacheConfig,
 }
 
 type Config struct {
@@ -86,6 +90,11 @@ type Config struct {
 	// a negative value means forever, 0 means cache is disabled.
 	MaxAge time.Duration
 
+	// The max total size in bytes of the files in this cache, e.g. "500MB".
+	// The least recently written files are removed first when pruning.
+	// Zero means no limit.
+	MaxSize int64
+
 	// The directory where files are stored.
 	Dir string
 
@@ -124,6 +133,12 @@ func (f Caches) GetResourceCache() *Cache {
 	return f[cacheKeyGetResource]
 }
 
+// EnrichmentCache gets the file cache for the page params computed by the
+// enrichment source.
+func (f Caches) EnrichmentCache() *Cache {
+	return f[cacheKeyEnrichment]
+}
+
 func DecodeConfig(fs afero.Fs, cfg config.Provider) (Configs, error) {
 	c := make(Configs)
 	valid := make(map[string]bool)
@@ -144,8 +159,11 @@ func DecodeConfig(fs afero.Fs, cfg config.Provider) (Configs, error) {
 		cc := defaultCacheConfig
 
 		dc := &mapstructure.DecoderConfig{
-			Result:           &cc,
-			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			Result: &cc,
+			DecodeHook: mapstructure.ComposeDecodeHookFunc(
+				mapstructure.StringToTimeDurationHookFunc(),
+				stringToByteSizeHookFunc,
+			),
 			WeaklyTypedInput: true,
 		}
 
@@ -158,6 +176,10 @@ func DecodeConfig(fs afero.Fs, cfg config.Provider) (Configs, error) {
 			return nil, fmt.Errorf("failed to decode filecache config: %w", err)
 		}
 
+		if cc.MaxSize < 0 {
+			return nil, fmt.Errorf("failed to decode filecache config: maxSize must be positive, got %d", cc.MaxSize)
+		}
+
 		if cc.Dir == "" {
 			return c, errors.New("must provide cache Dir")
 		}
@@ -230,6 +252,19 @@ func DecodeConfig(fs afero.Fs, cfg config.Provider) (Configs, error) {
 	return c, nil
 }
 
+// stringToByteSizeHookFunc decodes sizes such as "500MB" or "1GiB" into
+// an int64 number of bytes.
+func stringToByteSizeHookFunc(f reflect.Type, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.String || t != reflect.TypeOf(int64(0)) {
+		return data, nil
+	}
+	size, err := humanize.ParseBytes(data.(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid size %q", data)
+	}
+	return int64(size), nil
+}
+
 // Resolves :resourceDir => /myproject/resources etc., :cacheDir => ...
 func resolveDirPlaceholder(fs afero.Fs, cfg config.Provider, placeholder string) (cacheDir string, isResource bool, err error) {
 	workingDir := cfg.GetString("workingDir")
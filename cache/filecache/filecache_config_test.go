@@ -47,8 +47,10 @@ maxAge = "10m"
 dir = "/path/to/c1"
 [caches.getCSV]
 maxAge = "11h"
+maxSize = 2048
 dir = "/path/to/c2"
 [caches.images]
+maxSize = "1.5GB"
 dir = "/path/to/c3"
 [caches.getResource]
 dir = "/path/to/c4"
@@ -60,7 +62,15 @@ dir = "/path/to/c4"
 	decoded, err := DecodeConfig(fs, cfg)
 	c.Assert(err, qt.IsNil)
 
-	c.Assert(len(decoded), qt.Equals, 6)
+	c.Assert(len(decoded), qt.Equals, 7)
+
+	c.Assert(decoded["getjson"].MaxSize, qt.Equals, int64(0))
+	c.Assert(decoded["getcsv"].MaxSize, qt.Equals, int64(2048))
+	c.Assert(decoded["images"].MaxSize, qt.Equals, int64(1500000000))
+
+	cfg.Set("caches", map[string]any{"getjson": map[string]any{"maxSize": "lots", "dir": "/path"}})
+	_, err = DecodeConfig(fs, cfg)
+	c.Assert(err, qt.ErrorMatches, `(?s)failed to decode filecache config: .*MaxSize.*invalid size "lots".*`)
 
 	c2 := decoded["getcsv"]
 	c.Assert(c2.MaxAge.String(), qt.Equals, "11h0m0s")
@@ -109,7 +119,7 @@ dir = "/path/to/c4"
 	decoded, err := DecodeConfig(fs, cfg)
 	c.Assert(err, qt.IsNil)
 
-	c.Assert(len(decoded), qt.Equals, 6)
+	c.Assert(len(decoded), qt.Equals, 7)
 
 	for _, v := range decoded {
 		c.Assert(v.MaxAge, qt.Equals, time.Duration(0))
@@ -135,7 +145,7 @@ func TestDecodeConfigDefault(t *testing.T) {
 
 	c.Assert(err, qt.IsNil)
 
-	c.Assert(len(decoded), qt.Equals, 6)
+	c.Assert(len(decoded), qt.Equals, 7)
 
 	imgConfig := decoded[cacheKeyImages]
 	jsonConfig := decoded[cacheKeyGetJSON]
@@ -17,6 +17,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"time"
 
 	"github.com/gohugoio/hugo/hugofs"
 
@@ -47,7 +49,8 @@ func (c Caches) Prune() (int, error) {
 	return counter, nil
 }
 
-// Prune removes expired and unused items from this cache.
+// Prune removes expired and unused items from this cache, then the least
+// recently written items until the cache is within its max size.
 // If force is set, everything will be removed not considering expiry time.
 func (c *Cache) Prune(force bool) (int, error) {
 	if c.pruneAllRootDir != "" {
@@ -55,6 +58,7 @@ func (c *Cache) Prune(force bool) (int, error) {
 	}
 
 	counter := 0
+	var kept []keptFile
 
 	err := afero.Walk(c.Fs, "", func(name string, info os.FileInfo, err error) error {
 		if info == nil {
@@ -101,12 +105,51 @@ func (c *Cache) Prune(force bool) (int, error) {
 				return err
 			}
 
+		} else {
+			kept = append(kept, keptFile{name: name, size: info.Size(), modTime: info.ModTime()})
 		}
 
 		return nil
 	})
 
-	return counter, err
+	if err != nil || c.maxSize <= 0 {
+		return counter, err
+	}
+
+	var size int64
+	for _, f := range kept {
+		size += f.size
+	}
+	if size <= c.maxSize {
+		return counter, nil
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].modTime.Before(kept[j].modTime)
+	})
+
+	for _, f := range kept {
+		if size <= c.maxSize {
+			break
+		}
+		err := c.Fs.Remove(f.name)
+		if err != nil && !os.IsNotExist(err) {
+			return counter, err
+		}
+		if err == nil {
+			counter++
+		}
+		size -= f.size
+	}
+
+	return counter, nil
+}
+
+// keptFile is a file left after the expired and unused files are pruned.
+type keptFile struct {
+	name    string
+	size    int64
+	modTime time.Time
 }
 
 func (c *Cache) pruneRootDir(force bool) (int, error) {
@@ -119,7 +162,14 @@ func (c *Cache) pruneRootDir(force bool) (int, error) {
 	}
 
 	if !force && !c.isExpired(info.ModTime()) {
-		return 0, nil
+		if c.maxSize <= 0 {
+			return 0, nil
+		}
+		// The module cache is removed as a whole when too big.
+		stats, err := c.statsDir(c.pruneAllRootDir)
+		if err != nil || stats.Size <= c.maxSize {
+			return 0, err
+		}
 	}
 
 	return hugofs.MakeReadableAndRemoveAllModulePkgDir(c.Fs, c.pruneAllRootDir)
@@ -108,3 +108,99 @@ dir = ":resourceDir/_gen"
 
 	}
 }
+
+func TestPruneMaxSize(t *testing.T) {
+	t.Parallel()
+
+	c := qt.New(t)
+
+	configStr := `
+resourceDir = "myresources"
+contentDir = "content"
+dataDir = "data"
+i18nDir = "i18n"
+layoutDir = "layouts"
+assetDir = "assets"
+archeTypedir = "archetypes"
+
+[caches]
+[caches.getjson]
+maxAge = -1
+maxSize = 10
+dir = "/cache/c"
+`
+
+	p := newPathsSpec(t, afero.NewMemMapFs(), configStr)
+	caches, err := NewCaches(p)
+	c.Assert(err, qt.IsNil)
+	cache := caches[cacheKeyGetJSON]
+	c.Assert(cache.MaxSize(), qt.Equals, int64(10))
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("i%d", i)
+		_, _, err := cache.GetOrCreateBytes(id, func() ([]byte, error) {
+			return []byte("abc"), nil
+		})
+		c.Assert(err, qt.IsNil)
+		// i0 is the oldest.
+		modTime := now.Add(time.Duration(i-5) * time.Minute)
+		c.Assert(cache.Fs.Chtimes(cleanID(id), modTime, modTime), qt.IsNil)
+	}
+
+	c.Assert(cache.HitStats(), qt.DeepEquals, HitStats{Misses: 5})
+	c.Assert(cache.getString("i4"), qt.Equals, "abc")
+
+	stats, err := cache.Stats()
+	c.Assert(err, qt.IsNil)
+	c.Assert(stats, qt.DeepEquals, Stats{Files: 5, Size: 15})
+
+	count, err := caches.Prune()
+	c.Assert(err, qt.IsNil)
+	c.Assert(count, qt.Equals, 2)
+
+	for i := 0; i < 5; i++ {
+		id := fmt.Sprintf("i%d", i)
+		v := cache.getString(id)
+		if i < 2 {
+			c.Assert(v, qt.Equals, "")
+		} else {
+			c.Assert(v, qt.Equals, "abc")
+		}
+	}
+
+	stats, err = cache.Stats()
+	c.Assert(err, qt.IsNil)
+	c.Assert(stats, qt.DeepEquals, Stats{Files: 3, Size: 9})
+}
+
+func TestBuildStats(t *testing.T) {
+	t.Parallel()
+
+	c := qt.New(t)
+
+	fs := afero.NewMemMapFs()
+	filename := "/cache/myproject/filecache_stats.json"
+
+	stats, err := ReadBuildStats(fs, filename)
+	c.Assert(err, qt.IsNil)
+	c.Assert(stats.Time.IsZero(), qt.IsTrue)
+
+	p := newPathsSpec(t, fs, "")
+	caches, err := NewCaches(p)
+	c.Assert(err, qt.IsNil)
+	cache := caches[cacheKeyGetJSON]
+	for i := 0; i < 2; i++ {
+		_, _, err := cache.GetOrCreateBytes("a", func() ([]byte, error) {
+			return []byte("abc"), nil
+		})
+		c.Assert(err, qt.IsNil)
+	}
+
+	c.Assert(WriteBuildStats(fs, filename, caches.BuildStats()), qt.IsNil)
+	stats, err = ReadBuildStats(fs, filename)
+	c.Assert(err, qt.IsNil)
+	c.Assert(stats.Time.IsZero(), qt.IsFalse)
+	c.Assert(stats.Caches[cacheKeyGetJSON], qt.DeepEquals, HitStats{Hits: 1, Misses: 1})
+	c.Assert(stats.Caches[cacheKeyImages], qt.DeepEquals, HitStats{})
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filecache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/spf13/afero"
+)
+
+const buildStatsFilename = "filecache_stats.json"
+
+// Stats holds the number of files in a cache and their total size.
+type Stats struct {
+	Files int
+	Size  int64
+}
+
+// Stats walks this cache and returns its number of files and their size.
+func (c *Cache) Stats() (Stats, error) {
+	return c.statsDir("")
+}
+
+func (c *Cache) statsDir(dir string) (Stats, error) {
+	var stats Stats
+	err := afero.Walk(c.Fs, dir, func(name string, info os.FileInfo, err error) error {
+		if info == nil || info.IsDir() {
+			return nil
+		}
+		stats.Files++
+		stats.Size += info.Size()
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	return stats, err
+}
+
+// MaxAge returns the max age of the items in this cache, negative means
+// forever.
+func (c *Cache) MaxAge() time.Duration {
+	return c.maxAge
+}
+
+// MaxSize returns the max total size in bytes of this cache, 0 means no limit.
+func (c *Cache) MaxSize() int64 {
+	return c.maxSize
+}
+
+// HitStats holds the number of lookups found and not found in a cache.
+type HitStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// HitStats returns the lookups in this cache since it was created.
+func (c *Cache) HitStats() HitStats {
+	return HitStats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+	}
+}
+
+// BuildStats are the lookups in the caches during a build.
+type BuildStats struct {
+	Time   time.Time           `json:"time"`
+	Caches map[string]HitStats `json:"caches"`
+}
+
+// BuildStats returns the lookups in the caches since they were created,
+// which is the last build when not running the server.
+func (f Caches) BuildStats() BuildStats {
+	stats := BuildStats{Time: time.Now(), Caches: make(map[string]HitStats)}
+	for k, c := range f {
+		stats.Caches[k] = c.HitStats()
+	}
+	return stats
+}
+
+// BuildStatsFilename returns the filename of the build stats of the project
+// in cfg, in the project's cache dir.
+func BuildStatsFilename(fs afero.Fs, cfg config.Provider) (string, error) {
+	cacheDir, _, err := resolveDirPlaceholder(fs, cfg, ":cacheDir")
+	if err != nil {
+		return "", err
+	}
+	project, _, _ := resolveDirPlaceholder(fs, cfg, ":project")
+	return filepath.Join(cacheDir, project, buildStatsFilename), nil
+}
+
+// WriteBuildStats writes stats to filename.
+func WriteBuildStats(fs afero.Fs, filename string, stats BuildStats) error {
+	b, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := helpers.OpenFileForWriting(fs, filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(b)
+	return err
+}
+
+// ReadBuildStats reads the build stats written to filename, the zero value
+// if there are none.
+func ReadBuildStats(fs afero.Fs, filename string) (BuildStats, error) {
+	var stats BuildStats
+	b, err := afero.ReadFile(fs, filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return stats, nil
+		}
+		return stats, err
+	}
+	if err := json.Unmarshal(b, &stats); err != nil {
+		return stats, fmt.Errorf("failed to read cache stats from %q: %w", filename, err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gohugoio/hugo/cache/filecache"
+	"github.com/spf13/cobra"
+)
+
+var _ cmder = (*cacheCmd)(nil)
+
+type cacheCmd struct {
+	*baseBuilderCmd
+}
+
+func (b *commandsBuilder) newCacheCmd() *cacheCmd {
+	cc := &cacheCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and prune the file caches",
+		Long: `Inspect and prune the file caches of the current project.
+
+The caches are configured in the "caches" section of the site config, where
+every cache can have a maxAge and a maxSize.`,
+		RunE: nil,
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "stats",
+			Short: "Print the size of the file caches and their hits and misses in the last build",
+			RunE:  cc.printStats,
+		},
+		&cobra.Command{
+			Use:   "prune",
+			Short: "Remove the expired files and the files above the max size from the file caches",
+			Long: `Remove the expired files and the files above the max size from the file caches.
+
+The least recently written files are removed first until a cache is within its
+max size. Run "hugo --gc" to also remove the files not used in the build.`,
+			RunE: cc.prune,
+		},
+	)
+
+	cmd.PersistentFlags().StringP("cacheDir", "", "", "filesystem path to cache directory. Defaults: $TMPDIR/hugo_cache/")
+
+	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
+
+	return cc
+}
+
+func (c *cacheCmd) initConfig() (*commandeer, error) {
+	return initializeConfig(true, false, false, &c.hugoBuilderCommon, c, nil)
+}
+
+func (c *cacheCmd) printStats(cmd *cobra.Command, args []string) error {
+	com, err := c.initConfig()
+	if err != nil {
+		return err
+	}
+
+	h := com.hugo()
+
+	filename, err := filecache.BuildStatsFilename(h.Fs.Source, h.Cfg)
+	if err != nil {
+		return err
+	}
+	buildStats, err := filecache.ReadBuildStats(h.Fs.Source, filename)
+	if err != nil {
+		return err
+	}
+
+	configs, _ := h.Cfg.Get("filecacheConfigs").(filecache.Configs)
+	resourceDir := h.Cfg.GetString("resourceDir")
+	if !filepath.IsAbs(resourceDir) {
+		resourceDir = filepath.Join(h.Cfg.GetString("workingDir"), resourceDir)
+	}
+
+	var rows []cacheStatsRow
+	for name, cache := range h.FileCaches {
+		stats, err := cache.Stats()
+		if err != nil {
+			return fmt.Errorf("failed to read cache %q: %w", name, err)
+		}
+		dir := configs[name].Dir
+		if dir != "" && !filepath.IsAbs(dir) {
+			dir = filepath.Join(resourceDir, dir)
+		}
+		rows = append(rows, cacheStatsRow{
+			name:     name,
+			dir:      dir,
+			stats:    stats,
+			hitStats: buildStats.Caches[name],
+			maxAge:   cache.MaxAge(),
+			maxSize:  cache.MaxSize(),
+		})
+	}
+
+	return printCacheStats(os.Stdout, rows, buildStats.Time)
+}
+
+func (c *cacheCmd) prune(cmd *cobra.Command, args []string) error {
+	com, err := c.initConfig()
+	if err != nil {
+		return err
+	}
+
+	count, err := com.hugo().FileCaches.Prune()
+	com.logger.Printf("Removed %d files from the file caches.", count)
+	return err
+}
+
+type cacheStatsRow struct {
+	name     string
+	dir      string
+	stats    filecache.Stats
+	hitStats filecache.HitStats
+	maxAge   time.Duration
+	maxSize  int64
+}
+
+func printCacheStats(w io.Writer, rows []cacheStatsRow, buildTime time.Time) error {
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].name < rows[j].name
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "CACHE\tFILES\tSIZE\tMAX AGE\tMAX SIZE\tHITS\tMISSES\tDIR")
+
+	var files int
+	var size int64
+	for _, r := range rows {
+		maxAge := "never"
+		switch {
+		case r.maxAge == 0:
+			maxAge = "disabled"
+		case r.maxAge > 0:
+			maxAge = r.maxAge.String()
+		}
+		maxSize := "none"
+		if r.maxSize > 0 {
+			maxSize = humanize.Bytes(uint64(r.maxSize))
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%d\t%d\t%s\n", r.name, r.stats.Files, humanize.Bytes(uint64(r.stats.Size)), maxAge, maxSize, r.hitStats.Hits, r.hitStats.Misses, r.dir)
+		files += r.stats.Files
+		size += r.stats.Size
+	}
+	fmt.Fprintf(tw, "total\t%d\t%s\n", files, humanize.Bytes(uint64(size)))
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if buildTime.IsZero() {
+		_, err := fmt.Fprintln(w, "\nNo build stats found, run hugo to record the hits and misses.")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\nHits and misses from the build at %s.\n", buildTime.Format(time.RFC3339))
+	return err
+}
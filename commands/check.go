@@ -0,0 +1,179 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+var _ cmder = (*checkCmd)(nil)
+
+type checkCmd struct {
+	*baseBuilderCmd
+}
+
+func (cc *checkCmd) buildSites(config map[string]any) (*hugolib.HugoSites, error) {
+	return cc.build(config, hugolib.BuildCfg{SkipRender: true})
+}
+
+// renderSites builds and renders the sites to memory.
+func (cc *checkCmd) renderSites(config map[string]any) (*hugolib.HugoSites, error) {
+	config["renderToMemory"] = true
+	return cc.build(config, hugolib.BuildCfg{})
+}
+
+func (cc *checkCmd) build(config map[string]any, buildCfg hugolib.BuildCfg) (*hugolib.HugoSites, error) {
+	cfgInit := func(c *commandeer) error {
+		for key, value := range config {
+			c.Set(key, value)
+		}
+		return nil
+	}
+
+	c, err := initializeConfig(true, true, false, &cc.hugoBuilderCommon, cc, cfgInit)
+	if err != nil {
+		return nil, err
+	}
+
+	sites, err := hugolib.NewHugoSites(*c.DepsCfg)
+	if err != nil {
+		return nil, newSystemError("Error creating sites", err)
+	}
+
+	if err := sites.Build(buildCfg); err != nil {
+		return nil, newSystemError("Error Processing Source Content", err)
+	}
+
+	return sites, nil
+}
+
+func (b *commandsBuilder) newCheckCmd() *checkCmd {
+	cc := &checkCmd{}
+	var a11yFormat string
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Check the site for common problems",
+		Long: `Check the site for common problems.
+
+Check requires a subcommand, e.g. ` + "`hugo check content`.",
+		RunE: nil,
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "content",
+			Short: "Validate front matter against the configured schemas",
+			Long: `Validate the front matter of all content, including drafts, future and expired pages,
+against the schemas configured in frontmatter.schemas and declared next to the
+archetypes, e.g. archetypes/posts.schema.toml.
+
+The problems found are logged with their file and line, and the command fails
+if any are found.`,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				sites, err := cc.buildSites(map[string]any{
+					"buildExpired": true,
+					"buildDrafts":  true,
+					"buildFuture":  true,
+				})
+				if err != nil {
+					return newSystemError("Error building sites", err)
+				}
+
+				if n := sites.NumLogErrors(); n > 0 {
+					return fmt.Errorf("found %d problems in content", n)
+				}
+
+				jww.FEEDBACK.Printf("Checked %d pages, no problems found.\n", len(sites.Pages()))
+
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "prose",
+			Short: "Check the spelling and style of the content",
+			Long: `Check the plain text of all content, including drafts, future and expired pages,
+with the checks configured in prose for its language: spelling with Hunspell
+dictionaries, the max sentence length and banned words from a data file.
+
+The problems found are printed as file:line:column: message (rule), and the
+command fails if any are found.`,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				sites, err := cc.buildSites(map[string]any{
+					"buildExpired": true,
+					"buildDrafts":  true,
+					"buildFuture":  true,
+				})
+				if err != nil {
+					return newSystemError("Error building sites", err)
+				}
+
+				n, err := checkProse(sites, cmd.OutOrStdout())
+				if err != nil {
+					return err
+				}
+				if n > 0 {
+					return fmt.Errorf("found %d problems in prose", n)
+				}
+
+				jww.FEEDBACK.Printf("Checked %d pages, no problems found.\n", len(sites.Pages()))
+
+				return nil
+			},
+		},
+	)
+
+	a11yCmd := &cobra.Command{
+		Use:   "a11y",
+		Short: "Check the published HTML for accessibility problems",
+		Long: `Render the site to memory and check the published HTML with static
+accessibility checks: images without alt text, heading level jumps, form
+controls without a label and the contrast between the text and background
+colors set in inline styles.
+
+The severity of each check is configured in a11y.rules. The problems found are
+printed as file:line: severity: message (rule), or as JSON with --format json,
+and the command fails if any has severity error.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sites, err := cc.renderSites(map[string]any{})
+			if err != nil {
+				return newSystemError("Error building sites", err)
+			}
+
+			errs, warnings, err := checkA11y(sites, cmd.OutOrStdout(), a11yFormat)
+			if err != nil {
+				return err
+			}
+			if errs > 0 {
+				return fmt.Errorf("found %d accessibility errors and %d warnings", errs, warnings)
+			}
+
+			if a11yFormat != "json" {
+				jww.FEEDBACK.Printf("Checked the HTML files, found %d warnings.\n", warnings)
+			}
+
+			return nil
+		},
+	}
+	a11yCmd.Flags().StringVar(&a11yFormat, "format", "text", "output format, text or json")
+	cmd.AddCommand(a11yCmd)
+
+	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
+
+	return cc
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/publisher/a11y"
+	"github.com/spf13/afero"
+)
+
+// a11yFileIssue is an accessibility issue in a published file.
+type a11yFileIssue struct {
+	File string `json:"file"`
+	a11y.Issue
+}
+
+// checkA11y checks the published HTML files of sites for accessibility
+// problems, writes them to w in the given format, text or json, and returns
+// the number of errors and warnings.
+func checkA11y(sites *hugolib.HugoSites, w io.Writer, format string) (int, int, error) {
+	if format != "text" && format != "json" {
+		return 0, 0, fmt.Errorf("invalid format %q, must be text or json", format)
+	}
+
+	cfg, err := a11y.DecodeConfig(sites.Cfg.Get("a11y"))
+	if err != nil {
+		return 0, 0, err
+	}
+	checker := a11y.NewChecker(cfg)
+
+	var (
+		issues   []a11yFileIssue
+		errs     int
+		warnings int
+	)
+
+	fs := sites.BaseFs.PublishFs
+	err = afero.Walk(fs, "", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.EqualFold(filepath.Ext(path), ".html") {
+			return nil
+		}
+		f, err := fs.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		fileIssues, err := checker.Check(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, issue := range fileIssues {
+			if issue.Severity == a11y.SeverityError {
+				errs++
+			} else {
+				warnings++
+			}
+			issues = append(issues, a11yFileIssue{File: filepath.ToSlash(strings.TrimPrefix(path, string(filepath.Separator))), Issue: issue})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if format == "json" {
+		if issues == nil {
+			issues = []a11yFileIssue{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return errs, warnings, enc.Encode(issues)
+	}
+
+	for _, issue := range issues {
+		fmt.Fprintf(w, "%s:%s\n", issue.File, issue.Issue)
+	}
+
+	return errs, warnings, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestCheckA11y(t *testing.T) {
+	c := qt.New(t)
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "home", "section"]
+[a11y.rules]
+heading-order = "error"
+-- content/p1.md --
+---
+title: "P1"
+---
+-- layouts/_default/single.html --
+<html><body>
+<h2>{{ .Title }}</h2>
+<h4>Sub</h4>
+<img src="a.png">
+<p style="color: #777; background-color: #888">Low</p>
+</body></html>
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	var buf bytes.Buffer
+	errs, warnings, err := checkA11y(b.H, &buf, "text")
+	c.Assert(err, qt.IsNil)
+	c.Assert(errs, qt.Equals, 2)
+	c.Assert(warnings, qt.Equals, 1)
+	c.Assert(buf.String(), qt.Equals, `p1/index.html:3: error: heading level jumps from h2 to h4: <h4> (heading-order)
+p1/index.html:4: error: <img> without alt text: <img src="a.png"> (image-alt)
+p1/index.html:5: warning: contrast ratio 1.26 between text and background is less than 4.5: <p style="color: #777; background-color: #888"> (contrast)
+`)
+
+	buf.Reset()
+	_, _, err = checkA11y(b.H, &buf, "json")
+	c.Assert(err, qt.IsNil)
+	c.Assert(buf.String(), qt.Contains, `"file": "p1/index.html",
+    "rule": "heading-order",
+    "severity": "error",`)
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/markup/prose"
+	"github.com/spf13/afero"
+)
+
+// checkProse checks the plain text of every page with a file in sites with
+// the prose checks configured for its language, writes the problems found to
+// w and returns their number.
+func checkProse(sites *hugolib.HugoSites, w io.Writer) (int, error) {
+	var (
+		workingDir   = sites.Cfg.GetString("workingDir")
+		dictionaries = make(map[string]*prose.Dictionary)
+		count        int
+	)
+
+	loadDictionary := func(name string) (*prose.Dictionary, error) {
+		if d, found := dictionaries[name]; found {
+			return d, nil
+		}
+		filename := name
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(workingDir, filename)
+		}
+		dic, err := sites.Fs.Source.Open(filename + ".dic")
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dictionary %q: %w", name, err)
+		}
+		defer dic.Close()
+		aff, err := sites.Fs.Source.Open(filename + ".aff")
+		if err != nil {
+			return nil, fmt.Errorf("failed to open dictionary %q: %w", name, err)
+		}
+		defer aff.Close()
+		d, err := prose.ParseDictionary(dic, aff)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dictionary %q: %w", name, err)
+		}
+		dictionaries[name] = d
+		return d, nil
+	}
+
+	for _, s := range sites.Sites {
+		cfg, err := prose.DecodeConfig(s.Language().Get("prose"))
+		if err != nil {
+			return count, err
+		}
+
+		var banned map[string]string
+		if cfg.BannedWords != "" {
+			var v any = sites.Data()
+			for _, key := range strings.FieldsFunc(cfg.BannedWords, func(r rune) bool { return r == '.' || r == '/' }) {
+				m, _ := v.(map[string]any)
+				v = m[key]
+			}
+			if v == nil {
+				return count, fmt.Errorf("banned words %q not found in data", cfg.BannedWords)
+			}
+			if banned, err = prose.DecodeBannedWords(v); err != nil {
+				return count, err
+			}
+		}
+
+		var ds []*prose.Dictionary
+		for _, name := range cfg.Spelling.Dictionaries {
+			d, err := loadDictionary(name)
+			if err != nil {
+				return count, err
+			}
+			ds = append(ds, d)
+		}
+
+		checker := prose.NewChecker(cfg, banned, ds...)
+
+		for _, p := range s.Pages() {
+			if p.File().IsZero() {
+				continue
+			}
+
+			text := p.Plain()
+			diagnostics := checker.Check(text)
+			if len(diagnostics) == 0 {
+				continue
+			}
+
+			filename := p.File().Filename()
+			b, err := afero.ReadFile(sites.Fs.Source, filename)
+			if err != nil {
+				return count, err
+			}
+			source := string(b)
+			raw := p.RawContent()
+			contentStart := len(source) - len(raw)
+			if contentStart < 0 || !strings.HasSuffix(source, raw) {
+				contentStart = 0
+				raw = source
+			}
+
+			for _, d := range diagnostics {
+				line, col := 0, 0
+				if offset := prose.Locate(text, raw, d); offset != -1 {
+					line, col = lineCol(source, contentStart+offset)
+				}
+				fmt.Fprintf(w, "%s:%d:%d: %s (%s)\n", p.File().Path(), line, col, d.Message, d.Rule)
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// lineCol returns the 1-based line and column (in runes) of offset in s.
+func lineCol(s string, offset int) (int, int) {
+	before := s[:offset]
+	line := strings.Count(before, "\n") + 1
+	col := len([]rune(before[strings.LastIndex(before, "\n")+1:])) + 1
+	return line, col
+}
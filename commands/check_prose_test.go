@@ -0,0 +1,84 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestCheckProse(t *testing.T) {
+	c := qt.New(t)
+
+	files := `
+-- config.toml --
+defaultContentLanguage = "en"
+[prose]
+maxSentenceLength = 6
+bannedWords = "banned"
+[prose.spelling]
+dictionaries = ["dictionaries/en"]
+[languages.en]
+weight = 1
+[languages.de]
+weight = 2
+[languages.de.prose]
+maxSentenceLength = 3
+-- data/banned.toml --
+utilize = "use"
+-- dictionaries/en.dic --
+4
+we
+page/S
+this
+is
+-- dictionaries/en.aff --
+SFX S Y 1
+SFX S 0 s .
+-- content/p1.md --
+---
+title: "P1"
+---
+This is **wrng**.
+
+We utilize pages.
+-- content/p1.de.md --
+---
+title: "P1"
+---
+Das ist ein langer Satz.
+-- layouts/_default/single.html --
+{{ .Content }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	var buf bytes.Buffer
+	n, err := checkProse(b.H, &buf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 4)
+	c.Assert(buf.String(), qt.Equals, `p1.md:4:11: unknown word "wrng" (spelling)
+p1.md:6:4: unknown word "utilize" (spelling)
+p1.md:6:4: avoid "utilize", use "use" instead (banned-word)
+p1.de.md:4:1: sentence has 5 words, more than 3 (sentence-length)
+`)
+}
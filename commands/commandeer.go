@@ -30,6 +30,7 @@ import (
 
 	"github.com/gohugoio/hugo/common/herrors"
 	"github.com/gohugoio/hugo/common/htime"
+	"github.com/gohugoio/hugo/common/htrace"
 	"github.com/gohugoio/hugo/common/hugo"
 	"github.com/gohugoio/hugo/common/paths"
 
@@ -42,6 +43,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/publisher/destination"
 	"github.com/spf13/afero"
 
 	"github.com/bep/clock"
@@ -83,11 +85,23 @@ type commandeer struct {
 	publishDirFs       afero.Fs
 	publishDirServerFs afero.Fs
 
+	// Set when publishing to e.g. a tarball or a bucket instead of publishDir.
+	publishDestination destination.Destination
+
 	h    *hugoBuilderCommon
 	ftch flagsToConfigHandler
 
 	visitedURLs *types.EvictingStringQueue
 
+	// The most recent rebuilds in the server.
+	rebuildLog rebuildLog
+
+	// The metrics served on the server's metrics port.
+	serverMetrics serverMetrics
+
+	// Broadcasts the rebuilds to the clients of the change events endpoint.
+	changeBroker changeBroker
+
 	cfgInit func(c *commandeer) error
 
 	// We watch these for changes.
@@ -201,7 +215,9 @@ func newCommandeer(mustHaveConfigFile, failOnInitErr, running bool, h *hugoBuild
 	}
 
 	out := ioutil.Discard
-	if !h.quiet {
+	if h.rpc {
+		out = os.Stderr
+	} else if !h.quiet {
 		out = os.Stdout
 	}
 
@@ -288,7 +304,12 @@ func (f *fileChangeDetector) PrepareNew() {
 	f.current = make(map[string]string)
 }
 
-func (c *commandeer) loadConfig() error {
+func (c *commandeer) loadConfig() (err error) {
+	span := htrace.StartInBuild("loadConfig")
+	defer func() {
+		htrace.End(span, err)
+	}()
+
 	if c.DepsCfg == nil {
 		c.DepsCfg = &deps.DepsCfg{}
 	}
@@ -310,8 +331,12 @@ func (c *commandeer) loadConfig() error {
 	}
 
 	var sourceFs afero.Fs = hugofs.Os
+	var destinationFs afero.Fs = hugofs.Os
 	if c.DepsCfg.Fs != nil {
 		sourceFs = c.DepsCfg.Fs.Source
+		destinationFs = sourceFs
+	} else if c.h.profilesSourceFs != nil {
+		sourceFs = c.h.profilesSourceFs
 	}
 
 	environment := c.h.getEnvironment(c.running)
@@ -348,6 +373,7 @@ func (c *commandeer) loadConfig() error {
 			Filename:     c.h.cfgFile,
 			AbsConfigDir: c.h.getConfigDir(dir),
 			Environment:  environment,
+			Profile:      c.h.profile,
 		},
 		cfgSetAndInit,
 		doWithConfig)
@@ -408,7 +434,15 @@ func (c *commandeer) loadConfig() error {
 	createMemFs := config.GetBool("renderToMemory")
 	c.renderStaticToDisk = config.GetBool("renderStaticToDisk")
 
-	if createMemFs {
+	// The server and --renderToMemory take precedence over publishDestination.
+	if uri := config.GetString("publishDestination"); uri != "" && !createMemFs && !c.renderStaticToDisk && c.publishDestination == nil {
+		c.publishDestination, err = destination.New(uri, config.GetString("workingDir"))
+		if err != nil {
+			return err
+		}
+	}
+
+	if createMemFs || c.publishDestination != nil {
 		// Rendering to memoryFS, publish to Root regardless of publishDir.
 		config.Set("publishDir", "/")
 		config.Set("publishDirStatic", "/")
@@ -420,8 +454,9 @@ func (c *commandeer) loadConfig() error {
 	}
 
 	c.fsCreate.Do(func() {
-		// Assume both source and destination are using same filesystem.
-		fs := hugofs.NewFromSourceAndDestination(sourceFs, sourceFs, config)
+		// Assume both source and destination are using same filesystem,
+		// except when building profiles, where the source reads are cached.
+		fs := hugofs.NewFromSourceAndDestination(sourceFs, destinationFs, config)
 
 		if c.publishDirFs != nil {
 			// Need to reuse the destination on server rebuilds.
@@ -456,6 +491,9 @@ func (c *commandeer) loadConfig() error {
 			} else if createMemFs {
 				// Hugo writes the output to memory instead of the disk.
 				fs = hugofs.NewFromSourceAndDestination(sourceFs, afero.NewMemMapFs(), config)
+			} else if c.publishDestination != nil {
+				// Hugo writes the output to the destination, flushed after every build.
+				fs = hugofs.NewFromSourceAndDestination(sourceFs, c.publishDestination.Fs(), config)
 			}
 		}
 
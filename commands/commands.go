@@ -23,6 +23,7 @@ import (
 	hpaths "github.com/gohugoio/hugo/common/paths"
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/helpers"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
@@ -51,10 +52,13 @@ func (b *commandsBuilder) addAll() *commandsBuilder {
 		b.newConvertCmd(),
 		b.newNewCmd(),
 		b.newListCmd(),
+		b.newRenderCmd(),
+		b.newCheckCmd(),
 		newImportCmd(),
-		newGenCmd(),
+		b.newGenCmd(),
 		createReleaser(),
 		b.newModCmd(),
+		b.newCacheCmd(),
 	)
 
 	return b
@@ -162,16 +166,32 @@ Complete documentation is available at https://gohugo.io/.`,
 			// prevent cobra printing error so it can be handled here (before the timeTrack prints)
 			cmd.SilenceErrors = true
 
+			if cc.rpc {
+				err := cc.serveRPC(cmd.InOrStdin(), cmd.OutOrStdout())
+				if err != nil {
+					cc.printErr(cmd, err)
+				}
+				return err
+			}
+
+			if len(cc.profiles) > 0 {
+				err := cc.buildProfiles(cfgInit)
+				if err != nil {
+					cc.printErr(cmd, err)
+				}
+				return err
+			}
+
 			c, err := initializeConfig(true, true, cc.buildWatch, &cc.hugoBuilderCommon, cc, cfgInit)
 			if err != nil {
-				cmd.PrintErrln("Error:", err.Error())
+				cc.printErr(cmd, err)
 				return err
 			}
 			cc.c = c
 
 			err = c.build()
 			if err != nil {
-				cmd.PrintErrln("Error:", err.Error())
+				cc.printErr(cmd, err)
 			}
 			return err
 		},
@@ -189,10 +209,14 @@ Complete documentation is available at https://gohugo.io/.`,
 	cc.cmd.PersistentFlags().BoolVar(&cc.logging, "log", false, "enable Logging")
 	cc.cmd.PersistentFlags().StringVar(&cc.logFile, "logFile", "", "log File path (if set, logging enabled automatically)")
 	cc.cmd.PersistentFlags().BoolVar(&cc.verboseLog, "verboseLog", false, "verbose logging")
+	cc.cmd.PersistentFlags().StringVar(&cc.logFormat, "logFormat", loggers.LogFormatText, "log format, one of text or json (one diagnostic record per line)")
 
 	cc.cmd.Flags().BoolVarP(&cc.buildWatch, "watch", "w", false, "watch filesystem for changes and recreate as needed")
+	cc.cmd.Flags().BoolVar(&cc.rpc, "rpc", false, "serve JSON-RPC requests from editors on stdin/stdout instead of building the site")
+	cc.cmd.Flags().StringSliceVar(&cc.profiles, "profiles", nil, "build the site once per profile in the profiles config, e.g. --profiles brandA,brandB")
 
 	cc.cmd.Flags().Bool("renderToMemory", false, "render to memory (only useful for benchmark testing)")
+	cc.cmd.Flags().String("publishDestination", "", "publish to memory, a tarball (tar:site.tar.gz) or a bucket URL (e.g. s3://bucket?region=us-east-1) instead of the destination dir")
 
 	// Set bash-completion
 	_ = cc.cmd.PersistentFlags().SetAnnotation("logFile", cobra.BashCompFilenameExt, []string{})
@@ -209,8 +233,18 @@ type hugoBuilderCommon struct {
 	environment string
 
 	buildWatch bool
-	poll       string
-	clock      string
+	rpc        bool
+
+	// The profiles to build, and the one currently being built.
+	profiles []string
+	profile  string
+
+	// The source filesystem shared by the profile builds.
+	profilesSourceFs afero.Fs
+
+	poll    string
+	watcher string
+	clock   string
 
 	gc bool
 
@@ -228,13 +262,30 @@ type hugoBuilderCommon struct {
 	debug      bool
 	quiet      bool
 
-	cfgFile string
-	cfgDir  string
-	logFile string
+	cfgFile   string
+	cfgDir    string
+	logFile   string
+	logFormat string
+}
+
+// printFeedback returns whether to print human readable build
+// feedback (banners, timings, stats) to stdout.
+func (cc *hugoBuilderCommon) printFeedback() bool {
+	return !cc.quiet && !cc.rpc && cc.logFormat != loggers.LogFormatJSON
+}
+
+// printErr prints err to the command's error output,
+// as a JSON diagnostic record if that log format is selected.
+func (cc *hugoBuilderCommon) printErr(cmd *cobra.Command, err error) {
+	if cc.logFormat == loggers.LogFormatJSON {
+		loggers.WriteDiagnostic(cmd.ErrOrStderr(), loggers.NewDiagnosticFromError("error", err))
+		return
+	}
+	cmd.PrintErrln("Error:", err.Error())
 }
 
 func (cc *hugoBuilderCommon) timeTrack(start time.Time, name string) {
-	if cc.quiet {
+	if !cc.printFeedback() {
 		return
 	}
 	elapsed := time.Since(start)
@@ -299,6 +350,7 @@ func (cc *hugoBuilderCommon) handleFlags(cmd *cobra.Command) {
 	cmd.Flags().Bool("enableGitInfo", false, "add Git revision, date, author, and CODEOWNERS info to the pages")
 	cmd.Flags().BoolVar(&cc.gc, "gc", false, "enable to run some cleanup tasks (remove unused cache files) after the build")
 	cmd.Flags().StringVar(&cc.poll, "poll", "", "set this to a poll interval, e.g --poll 700ms, to use a poll based approach to watch for file system changes")
+	cmd.Flags().StringVar(&cc.watcher, "watcher", "", "the file watcher to use, one of auto, native or poll (for e.g. network file systems, Docker on macOS and WSL)")
 	cmd.Flags().BoolVar(&loggers.PanicOnWarning, "panicOnWarning", false, "panic on first WARNING log")
 	cmd.Flags().Bool("templateMetrics", false, "display metrics about template executions")
 	cmd.Flags().Bool("templateMetricsHints", false, "calculate some improvement hints when combined with --templateMetrics")
@@ -309,6 +361,7 @@ func (cc *hugoBuilderCommon) handleFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolP("printI18nWarnings", "", false, "print missing translations")
 	cmd.Flags().BoolP("printPathWarnings", "", false, "print warnings on duplicate target paths etc.")
 	cmd.Flags().BoolP("printUnusedTemplates", "", false, "print warnings on unused templates.")
+	cmd.Flags().BoolP("printEscapeAudit", "", false, "print the uses of safeHTML, safeJS, safeURL etc. in the templates and where their values come from")
 	cmd.Flags().StringVarP(&cc.cpuprofile, "profile-cpu", "", "", "write cpu profile to `file`")
 	cmd.Flags().StringVarP(&cc.memprofile, "profile-mem", "", "", "write memory profile to `file`")
 	cmd.Flags().BoolVarP(&cc.printm, "printMemoryUsage", "", false, "print memory usage to screen at intervals")
@@ -321,6 +374,8 @@ func (cc *hugoBuilderCommon) handleFlags(cmd *cobra.Command) {
 	cmd.Flags().MarkHidden("profile-mutex")
 
 	cmd.Flags().StringSlice("disableKinds", []string{}, "disable different kind of pages (home, RSS etc.)")
+	cmd.Flags().StringSlice("contentInclude", []string{}, "only read the content files matching these globs, relative to the content dir (e.g. blog/**)")
+	cmd.Flags().StringSlice("contentExclude", []string{}, "do not read the content files matching these globs, relative to the content dir")
 
 	cmd.Flags().Bool("minify", false, "minify any supported output format (HTML, XML etc.)")
 
@@ -15,6 +15,7 @@ package commands
 
 import (
 	"fmt"
+	"html/template"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -59,6 +60,50 @@ func TestExecute(t *testing.T) {
 		c.Assert(result.Sites[0].Info.Params()["myparam"], qt.Equals, "paramstaging")
 	})
 
+	c.Run("hugo, profiles", func(c *qt.C) {
+		dir := createSimpleTestSite(t, testSiteConfig{configTOML: `
+baseURL = "https://example.org"
+[params]
+brand = "none"
+[profiles.a.params]
+brand = "A"
+[profiles.b]
+baseURL = "https://b.example.org"
+[profiles.b.params]
+brand = "B"
+`})
+		resp := Execute([]string{"-s=" + dir, "--profiles=a,b", "--quiet"})
+		c.Assert(resp.Err, qt.IsNil)
+		result := resp.Result
+		c.Assert(result.Sites[0].Info.Params()["brand"], qt.Equals, "B")
+		c.Assert(result.Sites[0].Info.BaseURL(), qt.Equals, template.URL("https://b.example.org"))
+		c.Assert(result.Cfg.GetString("profile"), qt.Equals, "b")
+		_, isShared := result.Fs.Source.(profilesSourceFs)
+		c.Assert(isShared, qt.IsTrue)
+		c.Assert(hugofs.IsOsFs(result.Fs.Source), qt.IsTrue)
+		for _, profile := range []string{"a", "b"} {
+			_, err := os.Stat(filepath.Join(dir, "public", profile, "p1", "index.html"))
+			c.Assert(err, qt.IsNil)
+		}
+
+		resp = Execute([]string{"-s=" + dir, "--profiles=c", "--quiet"})
+		c.Assert(resp.Err, qt.ErrorMatches, `.*profile "c" not found.*`)
+	})
+
+	c.Run("hugo, printEscapeAudit", func(c *qt.C) {
+		dir := createSite(c)
+		writeFile(t, filepath.Join(dir, "layouts", "_default", "single.html"), `
+{{ .Params.banner | safeHTML }}
+{{ "<hr>" | safeHTML }}
+`)
+		out, err := captureStdout(func() error {
+			return Execute([]string{"-s=" + dir, "--quiet", "--printEscapeAudit"}).Err
+		})
+		c.Assert(err, qt.IsNil)
+		c.Assert(out, qt.Matches, `(?s).*param\s+safeHTML\s+layouts/_default/single.html:2:21\s+.Params.banner \| safeHTML\nliteral\s+safeHTML.*`)
+		c.Assert(out, qt.Contains, "Total: 2 (1 param, 1 literal)")
+	})
+
 	c.Run("convert toJSON", func(c *qt.C) {
 		dir := createSite(c)
 		output := filepath.Join(dir, "myjson")
@@ -68,6 +113,35 @@ func TestExecute(t *testing.T) {
 		c.Assert(converted, qt.Equals, "{\n   \"title\": \"P1\",\n   \"weight\": 1\n}\n\nContent\n\n", qt.Commentf(converted))
 	})
 
+	c.Run("convert migrate", func(c *qt.C) {
+		dir := createSite(c)
+		writeFile(t, filepath.Join(dir, "migrate.toml"), `
+[[rules]]
+op = "move"
+key = "weight"
+to = "params.order"
+[[rules]]
+op = "default"
+key = "tags"
+value = ["untagged"]
+`)
+
+		out, err := captureStdout(func() error {
+			return Execute([]string{"convert", "migrate", "-s=" + dir, "--rules=migrate.toml", "--dryRun"}).Err
+		})
+		c.Assert(err, qt.IsNil)
+		c.Assert(out, qt.Contains, "--- p1.md")
+		c.Assert(out, qt.Contains, "-weight: 1")
+		c.Assert(out, qt.Contains, "+params:")
+		c.Assert(readFileFrom(c, filepath.Join(dir, "content", "p1.md")), qt.Contains, "weight: 1")
+
+		output := filepath.Join(dir, "migrated")
+		resp := Execute([]string{"convert", "migrate", "-s=" + dir, "--rules=migrate.toml", "-o=" + output})
+		c.Assert(resp.Err, qt.IsNil)
+		converted := readFileFrom(c, filepath.Join(output, "content", "p1.md"))
+		c.Assert(converted, qt.Equals, "---\nparams:\n  order: 1\ntags:\n- untagged\ntitle: P1\n---\n\nContent\n\n", qt.Commentf(converted))
+	})
+
 	c.Run("config, set environment", func(c *qt.C) {
 		dir := createSite(c)
 		out, err := captureStdout(func() error {
@@ -85,6 +159,21 @@ func TestExecute(t *testing.T) {
 		c.Assert(resp.Err.Error(), qt.Contains, `no driver registered for "hugocloud"`)
 	})
 
+	c.Run("cache", func(c *qt.C) {
+		dir := createSite(c)
+		cacheDir := filepath.Join(dir, "cache")
+		resp := Execute([]string{"-s=" + dir, "--cacheDir=" + cacheDir, "--quiet"})
+		c.Assert(resp.Err, qt.IsNil)
+		out, err := captureStdout(func() error {
+			return Execute([]string{"cache", "stats", "-s=" + dir, "--cacheDir=" + cacheDir}).Err
+		})
+		c.Assert(err, qt.IsNil)
+		c.Assert(out, qt.Contains, "CACHE")
+		c.Assert(out, qt.Matches, `(?s).*\ngetjson\s+0\s+0 B\s+never\s+none\s+0\s+0\s+.*`)
+		c.Assert(out, qt.Contains, filepath.Join(cacheDir, filepath.Base(dir), "filecache", "getjson"))
+		c.Assert(out, qt.Contains, "Hits and misses from the build at")
+	})
+
 	c.Run("list", func(c *qt.C) {
 		dir := createSite(c)
 		out, err := captureStdout(func() error {
@@ -179,6 +268,9 @@ func TestFlags(t *testing.T) {
 				"--navigateToChanged",
 				"--disableLiveReload",
 				"--noHTTPCache",
+				"--noHTTPCacheFor=/fonts/**",
+				"--throttle=slow-3g",
+				"--throttleFor=/images/**,**.woff2",
 				"--printI18nWarnings",
 				"--destination=/tmp/mydestination",
 				"-b=https://example.com/b/",
@@ -193,6 +285,9 @@ func TestFlags(t *testing.T) {
 				c.Assert(sc.navigateToChanged, qt.Equals, true)
 				c.Assert(sc.disableLiveReload, qt.Equals, true)
 				c.Assert(sc.noHTTPCache, qt.Equals, true)
+				c.Assert(sc.noHTTPCacheFor, qt.DeepEquals, []string{"/fonts/**"})
+				c.Assert(sc.throttle, qt.Equals, "slow-3g")
+				c.Assert(sc.throttleFor, qt.DeepEquals, []string{"/images/**", "**.woff2"})
 				c.Assert(sc.renderToDisk, qt.Equals, true)
 				c.Assert(sc.serverPort, qt.Equals, 1366)
 				c.Assert(sc.environment, qt.Equals, "testing")
@@ -258,6 +353,7 @@ func TestCommandsExecute(t *testing.T) {
 		// no args = hugo build
 		{nil, []string{sourceFlag}, ""},
 		{nil, []string{sourceFlag, "--renderToMemory"}, ""},
+		{nil, []string{sourceFlag, "--publishDestination=tar:" + filepath.Join(dirOut, "site.tar")}, ""},
 		{[]string{"completion", "bash"}, nil, ""},
 		{[]string{"completion", "fish"}, nil, ""},
 		{[]string{"completion", "powershell"}, nil, ""},
@@ -269,10 +365,16 @@ func TestCommandsExecute(t *testing.T) {
 		{[]string{"gen", "chromastyles"}, []string{"--style=manni"}, ""},
 		{[]string{"gen", "doc"}, []string{"--dir=" + filepath.Join(dirOut, "doc")}, ""},
 		{[]string{"gen", "man"}, []string{"--dir=" + filepath.Join(dirOut, "man")}, ""},
+		{[]string{"gen", "epub", "/"}, []string{sourceFlag, "--output=" + filepath.Join(dirOut, "book.epub")}, ""},
+		{[]string{"cache", "stats"}, []string{sourceFlag}, ""},
+		{[]string{"cache", "prune"}, []string{sourceFlag}, ""},
 		{[]string{"list", "drafts"}, []string{sourceFlag}, ""},
 		{[]string{"list", "expired"}, []string{sourceFlag}, ""},
 		{[]string{"list", "future"}, []string{sourceFlag}, ""},
+		{[]string{"check", "content"}, []string{sourceFlag}, ""},
+		{[]string{"check", "prose"}, []string{sourceFlag}, ""},
 		{[]string{"new", "new-page.md"}, []string{sourceFlag}, ""},
+		{[]string{"new", "content", "new-page-2.md"}, []string{sourceFlag}, ""},
 		{[]string{"new", "site", filepath.Join(dirOut, "new-site")}, nil, ""},
 		{[]string{"unknowncommand"}, nil, "unknown command"},
 		// TODO(bep) cli refactor fix https://github.com/gohugoio/hugo/issues/4450
@@ -33,6 +33,7 @@ import (
 
 	"github.com/gohugoio/hugo/hugolib"
 
+	"github.com/kylelemons/godebug/diff"
 	"github.com/spf13/cobra"
 )
 
@@ -42,6 +43,14 @@ type convertCmd struct {
 	outputDir string
 	unsafe    bool
 
+	// For content.
+	to string
+
+	// For migrate.
+	rulesFile string
+	dryRun    bool
+	rules     *migrationRules
+
 	*baseBuilderCmd
 }
 
@@ -53,7 +62,7 @@ func (b *commandsBuilder) newConvertCmd() *convertCmd {
 		Short: "Convert your content to different formats",
 		Long: `Convert your content (e.g. front matter) to different formats.
 
-See convert's subcommands toJSON, toTOML and toYAML for more information.`,
+See convert's subcommands toJSON, toTOML, toYAML, migrate and content for more information.`,
 		RunE: nil,
 	}
 
@@ -87,6 +96,44 @@ to use YAML for the front matter.`,
 		},
 	)
 
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate front matter with rules",
+		Long: `migrate applies the migration rules in the file given with --rules,
+e.g. to rename keys, move values into nested params, coerce types and apply
+defaults, to all front matter in the content directory. The front matter
+format of each file is kept.
+
+Use --dryRun to print the changes as diffs without writing any files.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cc.rulesFile == "" {
+				return newUserError("the --rules flag is required")
+			}
+			return cc.convertContents("")
+		},
+	}
+	migrateCmd.Flags().StringVar(&cc.rulesFile, "rules", "", "the file with the migration rules")
+	migrateCmd.Flags().BoolVar(&cc.dryRun, "dryRun", false, "print the changes without writing any files")
+	cmd.AddCommand(migrateCmd)
+
+	contentCmd := &cobra.Command{
+		Use:   "content",
+		Short: "Convert content files to another markup format",
+		Long: `content converts all content files in the content directory not already in
+the format given with --to, e.g. AsciiDoc, Org and reStructuredText files to
+Markdown, with pandoc, and asciidoctor for AsciiDoc. Both must be installed
+and allowed in the security.exec config.
+
+The front matter and the shortcodes are kept as is. The converted files get
+the extension of the new format; with --unsafe, the original files are
+removed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cc.convertContentFormats(cc.to)
+		},
+	}
+	contentCmd.Flags().StringVar(&cc.to, "to", "markdown", "the markup format to convert to: markdown, pandoc, org, rst, asciidoc or html")
+	cmd.AddCommand(contentCmd)
+
 	cmd.PersistentFlags().StringVarP(&cc.outputDir, "output", "o", "", "filesystem path to write files to")
 	cmd.PersistentFlags().BoolVar(&cc.unsafe, "unsafe", false, "enable less safe operations, please backup first")
 
@@ -95,8 +142,10 @@ to use YAML for the front matter.`,
 	return cc
 }
 
+// convertContents converts the front matter to format, or keeps the format
+// if empty.
 func (cc *convertCmd) convertContents(format metadecoders.Format) error {
-	if cc.outputDir == "" && !cc.unsafe {
+	if cc.outputDir == "" && !cc.unsafe && !cc.dryRun {
 		return newUserError("Unsafe operation not allowed, use --unsafe or set a different output path")
 	}
 
@@ -105,6 +154,17 @@ func (cc *convertCmd) convertContents(format metadecoders.Format) error {
 		return err
 	}
 
+	if cc.rulesFile != "" {
+		filename := cc.rulesFile
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(c.Cfg.GetString("workingDir"), filename)
+		}
+		cc.rules, err = loadMigrationRules(hugofs.Os, filename)
+		if err != nil {
+			return newUserError(err)
+		}
+	}
+
 	c.Cfg.Set("buildDrafts", true)
 
 	h, err := hugolib.NewHugoSites(*c.DepsCfg)
@@ -161,14 +221,22 @@ func (cc *convertCmd) convertAndSavePage(p page.Page, site *hugolib.Site, target
 
 	file.Close()
 
-	// better handling of dates in formats that don't have support for them
-	if pf.FrontMatterFormat == metadecoders.JSON || pf.FrontMatterFormat == metadecoders.YAML || pf.FrontMatterFormat == metadecoders.TOML {
-		for k, v := range pf.FrontMatter {
-			switch vv := v.(type) {
-			case time.Time:
-				pf.FrontMatter[k] = vv.Format(time.RFC3339)
-			}
+	if targetFormat == "" {
+		targetFormat = pf.FrontMatterFormat
+	}
+
+	formatDates(pf)
+
+	var oldFrontMatter bytes.Buffer
+	if cc.rules != nil {
+		if err := parser.InterfaceToFrontMatter(pf.FrontMatter, targetFormat, &oldFrontMatter); err != nil {
+			site.Log.Errorln(errMsg)
+			return err
 		}
+		if err := cc.rules.apply(p.File().Path(), pf.FrontMatter); err != nil {
+			return fmt.Errorf("%s: %w", p.File().Path(), err)
+		}
+		formatDates(pf)
 	}
 
 	var newContent bytes.Buffer
@@ -178,6 +246,17 @@ func (cc *convertCmd) convertAndSavePage(p page.Page, site *hugolib.Site, target
 		return err
 	}
 
+	if cc.rules != nil {
+		if oldFrontMatter.String() == newContent.String() {
+			// Nothing to migrate.
+			return nil
+		}
+		if cc.dryRun {
+			fmt.Printf("--- %s\n+++ %s\n%s\n", p.File().Path(), p.File().Path(), diff.Diff(oldFrontMatter.String(), newContent.String()))
+			return nil
+		}
+	}
+
 	newContent.Write(pf.Content)
 
 	newFilename := p.File().Filename()
@@ -197,6 +276,19 @@ func (cc *convertCmd) convertAndSavePage(p page.Page, site *hugolib.Site, target
 	return nil
 }
 
+// formatDates formats the dates as strings for the front matter formats
+// that don't have support for them.
+func formatDates(pf pageparser.ContentFrontMatter) {
+	if pf.FrontMatterFormat == metadecoders.JSON || pf.FrontMatterFormat == metadecoders.YAML || pf.FrontMatterFormat == metadecoders.TOML {
+		for k, v := range pf.FrontMatter {
+			switch vv := v.(type) {
+			case time.Time:
+				pf.FrontMatter[k] = vv.Format(time.RFC3339)
+			}
+		}
+	}
+}
+
 type parsedFile struct {
 	frontMatterFormat metadecoders.Format
 	frontMatterSource []byte
@@ -0,0 +1,256 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/parser"
+	"github.com/gohugoio/hugo/parser/pageparser"
+	"github.com/gohugoio/hugo/resources/page"
+)
+
+// contentFormat is a content format hugo convert content can convert from
+// or to.
+type contentFormat struct {
+	// The pandoc reader and writer names.
+	reader, writer string
+
+	// The extension of the converted files.
+	ext string
+}
+
+// The content formats by markup name, see ContentSpec.ResolveMarkup.
+var contentFormats = map[string]contentFormat{
+	"markdown":    {reader: "gfm", writer: "gfm", ext: "md"},
+	"goldmark":    {reader: "gfm", writer: "gfm", ext: "md"},
+	"pandoc":      {reader: "markdown", writer: "markdown", ext: "pdc"},
+	"org":         {reader: "org", writer: "org", ext: "org"},
+	"rst":         {reader: "rst", writer: "rst", ext: "rst"},
+	"asciidocext": {reader: "docbook", writer: "asciidoc", ext: "adoc"},
+	"html":        {reader: "html", writer: "html", ext: "html"},
+}
+
+// The aliases accepted by --to.
+var contentFormatAliases = map[string]string{
+	"md":       "markdown",
+	"asciidoc": "asciidocext",
+	"adoc":     "asciidocext",
+}
+
+func resolveContentFormat(name string) (string, contentFormat, bool) {
+	name = strings.ToLower(name)
+	if alias, found := contentFormatAliases[name]; found {
+		name = alias
+	}
+	f, found := contentFormats[name]
+	return name, f, found
+}
+
+// The shortcodes, including their closing tags, are replaced with
+// placeholders the external converters leave as is.
+var (
+	shortcodeRe            = regexp.MustCompile(`(?s)\{\{[<%].*?[%>]\}\}`)
+	shortcodePlaceholderRe = regexp.MustCompile(`(?i)hugoshortcode(\d+)x`)
+)
+
+func protectShortcodes(src []byte) ([]byte, [][]byte) {
+	var shortcodes [][]byte
+	protected := shortcodeRe.ReplaceAllFunc(src, func(b []byte) []byte {
+		shortcodes = append(shortcodes, b)
+		return []byte(fmt.Sprintf("hugoshortcode%dx", len(shortcodes)-1))
+	})
+	return protected, shortcodes
+}
+
+func restoreShortcodes(src []byte, shortcodes [][]byte) []byte {
+	return shortcodePlaceholderRe.ReplaceAllFunc(src, func(b []byte) []byte {
+		i, err := strconv.Atoi(string(shortcodePlaceholderRe.FindSubmatch(b)[1]))
+		if err == nil && i < len(shortcodes) {
+			return shortcodes[i]
+		}
+		return b
+	})
+}
+
+// contentRunner runs the external command name with the given args and
+// stdin, returning its stdout.
+type contentRunner func(name string, args []string, in []byte) ([]byte, error)
+
+func newExecContentRunner(ex *hexec.Exec) contentRunner {
+	return func(name string, args []string, in []byte) ([]byte, error) {
+		var out, stderr bytes.Buffer
+		argsv := make([]any, 0, len(args)+3)
+		for _, arg := range args {
+			argsv = append(argsv, arg)
+		}
+		argsv = append(argsv, hexec.WithStdin(bytes.NewReader(in)), hexec.WithStdout(&out), hexec.WithStderr(&stderr))
+		cmd, err := ex.New(name, argsv...)
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+		}
+		return bytes.ReplaceAll(out.Bytes(), []byte("\r"), nil), nil
+	}
+}
+
+// convertContentFormat converts src from one content format to another with
+// pandoc, via asciidoctor for AsciiDoc, keeping the shortcodes.
+func convertContentFormat(run contentRunner, src []byte, from, to contentFormat) ([]byte, error) {
+	protected, shortcodes := protectShortcodes(src)
+
+	var err error
+	if from.reader == "docbook" {
+		// pandoc can not read AsciiDoc.
+		protected, err = run("asciidoctor", []string{"--backend", "docbook5", "--out-file", "-", "-"}, protected)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	converted, err := run("pandoc", []string{"--from", from.reader, "--to", to.writer, "--wrap", "preserve"}, protected)
+	if err != nil {
+		return nil, err
+	}
+
+	return restoreShortcodes(converted, shortcodes), nil
+}
+
+func (cc *convertCmd) convertContentFormats(to string) error {
+	if cc.outputDir == "" && !cc.unsafe {
+		return newUserError("Unsafe operation not allowed, use --unsafe or set a different output path")
+	}
+
+	toName, toFormat, found := resolveContentFormat(to)
+	if !found {
+		return newUserError(fmt.Sprintf("unsupported content format %q, must be one of markdown, pandoc, org, rst, asciidoc or html", to))
+	}
+
+	c, err := initializeConfig(true, false, false, &cc.hugoBuilderCommon, cc, nil)
+	if err != nil {
+		return err
+	}
+
+	c.Cfg.Set("buildDrafts", true)
+
+	h, err := hugolib.NewHugoSites(*c.DepsCfg)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Build(hugolib.BuildCfg{SkipRender: true}); err != nil {
+		return err
+	}
+
+	site := h.Sites[0]
+	run := newExecContentRunner(h.Deps.ExecHelper)
+
+	var pages page.Pages
+	for _, p := range site.AllPages() {
+		pages = append(pages, p)
+		// The resources are not in .Site.AllPages.
+		for _, r := range p.Resources().ByType("page") {
+			pages = append(pages, r.(page.Page))
+		}
+	}
+
+	var count int
+	for _, p := range pages {
+		if p.File().IsZero() {
+			continue
+		}
+		fromName := site.ContentSpec.ResolveMarkup(p.File().Ext())
+		if fromName == "goldmark" {
+			fromName = "markdown"
+		}
+		fromFormat, found := contentFormats[fromName]
+		if !found || fromName == toName {
+			continue
+		}
+		if err := cc.convertPageContentFormat(p, run, fromFormat, toFormat); err != nil {
+			return err
+		}
+		count++
+	}
+
+	site.Log.Println("converted", count, "content files to", toName)
+
+	return nil
+}
+
+func (cc *convertCmd) convertPageContentFormat(p page.Page, run contentRunner, from, to contentFormat) error {
+	f, err := p.File().FileInfo().Meta().Open()
+	if err != nil {
+		return err
+	}
+	pf, err := pageparser.ParseFrontMatterAndContent(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to parse %q: %w", p.File().Path(), err)
+	}
+
+	converted, err := convertContentFormat(run, pf.Content, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to convert %q: %w", p.File().Path(), err)
+	}
+
+	formatDates(pf)
+
+	var newContent bytes.Buffer
+	if len(pf.FrontMatter) > 0 {
+		// The markup is given by the new extension.
+		for k := range pf.FrontMatter {
+			if strings.EqualFold(k, "markup") {
+				delete(pf.FrontMatter, k)
+			}
+		}
+		if err := parser.InterfaceToFrontMatter(pf.FrontMatter, pf.FrontMatterFormat, &newContent); err != nil {
+			return err
+		}
+		newContent.WriteString("\n")
+	}
+	newContent.Write(converted)
+
+	filename := p.File().Filename()
+	newPath := strings.TrimSuffix(p.File().Path(), "."+p.File().Ext()) + "." + to.ext
+	newFilename := strings.TrimSuffix(filename, "."+p.File().Ext()) + "." + to.ext
+
+	if cc.outputDir != "" {
+		contentDir := filepath.Base(strings.TrimSuffix(filename, p.File().Path()))
+		newFilename = filepath.Join(cc.outputDir, contentDir, newPath)
+	}
+
+	if err := helpers.WriteToDisk(newFilename, &newContent, hugofs.Os); err != nil {
+		return fmt.Errorf("Failed to save file %q:: %w", newFilename, err)
+	}
+
+	if cc.outputDir == "" {
+		// Converted in place.
+		return os.Remove(filename)
+	}
+
+	return nil
+}
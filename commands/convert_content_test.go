@@ -0,0 +1,57 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestConvertContentFormat(t *testing.T) {
+	c := qt.New(t)
+
+	var calls []string
+	run := func(name string, args []string, in []byte) ([]byte, error) {
+		calls = append(calls, name+" "+strings.Join(args, " "))
+		c.Assert(string(in), qt.Not(qt.Contains), "{{")
+		return bytes.ToUpper(in), nil
+	}
+
+	src := []byte("* Heading\n{{< figure src=\"a.jpg\" >}}\nText {{% note %}}inner{{% /note %}}\n")
+
+	_, org, _ := resolveContentFormat("org")
+	name, md, found := resolveContentFormat("MD")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(name, qt.Equals, "markdown")
+	c.Assert(md.ext, qt.Equals, "md")
+
+	converted, err := convertContentFormat(run, src, org, md)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(converted), qt.Equals, "* HEADING\n{{< figure src=\"a.jpg\" >}}\nTEXT {{% note %}}INNER{{% /note %}}\n")
+	c.Assert(calls, qt.DeepEquals, []string{"pandoc --from org --to gfm --wrap preserve"})
+
+	calls = nil
+	_, adoc, _ := resolveContentFormat("asciidoc")
+	_, err = convertContentFormat(run, src, adoc, md)
+	c.Assert(err, qt.IsNil)
+	c.Assert(calls, qt.HasLen, 2)
+	c.Assert(calls[0], qt.Equals, "asciidoctor --backend docbook5 --out-file - -")
+	c.Assert(calls[1], qt.Equals, "pandoc --from docbook --to gfm --wrap preserve")
+
+	_, _, found = resolveContentFormat("docx")
+	c.Assert(found, qt.IsFalse)
+}
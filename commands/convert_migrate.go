@@ -0,0 +1,288 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/gohugoio/hugo/common/maps"
+	hglob "github.com/gohugoio/hugo/hugofs/glob"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/afero"
+	"github.com/spf13/cast"
+)
+
+// The operations of a front matter migration rule.
+const (
+	migrateOpRename  = "rename"
+	migrateOpMove    = "move"
+	migrateOpCoerce  = "coerce"
+	migrateOpDefault = "default"
+	migrateOpDelete  = "delete"
+)
+
+// migrationRule is a front matter migration rule, e.g.:
+//
+//	[[rules]]
+//	op = "move"
+//	key = "rating"
+//	to = "params.rating"
+//	path = "reviews/**"
+type migrationRule struct {
+	// One of rename, move, coerce, default or delete.
+	Op string
+
+	// The key to migrate, with dots for nested keys, e.g. params.rating.
+	Key string
+
+	// The new key for rename and move.
+	To string
+
+	// The type for coerce: string, int, float, bool, slice or date.
+	Type string
+
+	// The value for default, set if the key is missing.
+	Value any
+
+	// An optional Glob pattern matched against the path of the content file,
+	// relative to the content dir, e.g. blog/**.
+	Path string
+
+	pathGlob glob.Glob
+}
+
+type migrationRules struct {
+	Rules []migrationRule
+}
+
+func loadMigrationRules(fs afero.Fs, filename string) (*migrationRules, error) {
+	m, err := metadecoders.Default.UnmarshalFileToMap(fs, filename)
+	if err != nil {
+		return nil, err
+	}
+	return decodeMigrationRules(m)
+}
+
+func decodeMigrationRules(m map[string]any) (*migrationRules, error) {
+	var rules migrationRules
+	if err := mapstructure.WeakDecode(m, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode migration rules: %w", err)
+	}
+	if len(rules.Rules) == 0 {
+		return nil, fmt.Errorf("no migration rules found")
+	}
+
+	for i, r := range rules.Rules {
+		r.Op = strings.ToLower(r.Op)
+		errorf := func(format string, a ...any) error {
+			return fmt.Errorf("migration rule %d: %s", i+1, fmt.Sprintf(format, a...))
+		}
+		if r.Key == "" {
+			return nil, errorf("key is required")
+		}
+		switch r.Op {
+		case migrateOpRename, migrateOpMove:
+			if r.To == "" {
+				return nil, errorf("to is required for %s", r.Op)
+			}
+		case migrateOpCoerce:
+			r.Type = strings.ToLower(r.Type)
+			switch r.Type {
+			case "string", "int", "float", "bool", "slice", "date":
+			default:
+				return nil, errorf("invalid type %q, must be one of string, int, float, bool, slice or date", r.Type)
+			}
+		case migrateOpDefault:
+			if r.Value == nil {
+				return nil, errorf("value is required for default")
+			}
+		case migrateOpDelete:
+		default:
+			return nil, errorf("invalid op %q, must be one of rename, move, coerce, default or delete", r.Op)
+		}
+		if r.Path != "" {
+			g, err := hglob.GetGlob(r.Path)
+			if err != nil {
+				return nil, errorf("invalid path: %s", err)
+			}
+			r.pathGlob = g
+		}
+		rules.Rules[i] = r
+	}
+
+	return &rules, nil
+}
+
+// apply applies the rules matching path, the path of the content file
+// relative to the content dir, to fm.
+func (m *migrationRules) apply(path string, fm map[string]any) error {
+	path = filepath.ToSlash(path)
+	for i, r := range m.Rules {
+		if r.pathGlob != nil && !r.pathGlob.Match(path) {
+			continue
+		}
+		if err := r.apply(fm); err != nil {
+			return fmt.Errorf("migration rule %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (r migrationRule) apply(fm map[string]any) error {
+	v, found := lookupFrontMatterKey(fm, r.Key)
+
+	switch r.Op {
+	case migrateOpRename, migrateOpMove:
+		if !found {
+			return nil
+		}
+		deleteFrontMatterKey(fm, r.Key)
+		return setFrontMatterKey(fm, r.To, v)
+	case migrateOpCoerce:
+		if !found {
+			return nil
+		}
+		vv, err := coerceFrontMatterValue(v, r.Type)
+		if err != nil {
+			return fmt.Errorf("failed to coerce %q to %s: %w", r.Key, r.Type, err)
+		}
+		return setFrontMatterKey(fm, r.Key, vv)
+	case migrateOpDefault:
+		if found {
+			return nil
+		}
+		return setFrontMatterKey(fm, r.Key, r.Value)
+	case migrateOpDelete:
+		deleteFrontMatterKey(fm, r.Key)
+	}
+
+	return nil
+}
+
+func coerceFrontMatterValue(v any, typ string) (any, error) {
+	switch typ {
+	case "string":
+		return cast.ToStringE(v)
+	case "int":
+		return cast.ToIntE(v)
+	case "float":
+		return cast.ToFloat64E(v)
+	case "bool":
+		return cast.ToBoolE(v)
+	case "date":
+		return cast.ToTimeE(v)
+	case "slice":
+		switch vv := v.(type) {
+		case []any:
+			return vv, nil
+		case []string:
+			return vv, nil
+		case string:
+			var s []string
+			for _, part := range strings.Split(vv, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					s = append(s, part)
+				}
+			}
+			return s, nil
+		default:
+			return []any{v}, nil
+		}
+	}
+	return v, nil
+}
+
+// lookupFrontMatterKey looks up the, possibly nested, key, e.g.
+// params.rating, matching the key parts case insensitively.
+func lookupFrontMatterKey(fm map[string]any, key string) (any, bool) {
+	parts := strings.Split(key, ".")
+	m := fm
+	for i, part := range parts {
+		k, found := lookupKeyEqualFold(m, part)
+		if !found {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return m[k], true
+		}
+		next, err := maps.ToStringMapE(m[k])
+		if err != nil {
+			return nil, false
+		}
+		m = next
+	}
+	return nil, false
+}
+
+func deleteFrontMatterKey(fm map[string]any, key string) {
+	parts := strings.Split(key, ".")
+	m := fm
+	for i, part := range parts {
+		k, found := lookupKeyEqualFold(m, part)
+		if !found {
+			return
+		}
+		if i == len(parts)-1 {
+			delete(m, k)
+			return
+		}
+		next, err := maps.ToStringMapE(m[k])
+		if err != nil {
+			return
+		}
+		m[k] = next
+		m = next
+	}
+}
+
+func setFrontMatterKey(fm map[string]any, key string, v any) error {
+	parts := strings.Split(key, ".")
+	m := fm
+	for i, part := range parts {
+		k, found := lookupKeyEqualFold(m, part)
+		if !found {
+			k = part
+		}
+		if i == len(parts)-1 {
+			m[k] = v
+			return nil
+		}
+		if !found {
+			m[k] = make(map[string]any)
+		}
+		next, err := maps.ToStringMapE(m[k])
+		if err != nil {
+			return fmt.Errorf("can not set %q: %q is not a map", key, strings.Join(parts[:i+1], "."))
+		}
+		m[k] = next
+		m = next
+	}
+	return nil
+}
+
+func lookupKeyEqualFold(m map[string]any, key string) (string, bool) {
+	if _, found := m[key]; found {
+		return key, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, key) {
+			return k, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestMigrationRules(t *testing.T) {
+	c := qt.New(t)
+
+	rules, err := decodeMigrationRules(map[string]any{
+		"rules": []any{
+			map[string]any{"op": "rename", "key": "Author", "to": "authors"},
+			map[string]any{"op": "coerce", "key": "authors", "type": "slice"},
+			map[string]any{"op": "move", "key": "rating", "to": "params.review.rating"},
+			map[string]any{"op": "coerce", "key": "params.review.rating", "type": "int"},
+			map[string]any{"op": "coerce", "key": "published", "type": "date"},
+			map[string]any{"op": "default", "key": "draft", "value": true, "path": "drafts/**"},
+			map[string]any{"op": "delete", "key": "legacy"},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+
+	fm := map[string]any{
+		"title":     "P1",
+		"author":    "Jane, John",
+		"rating":    "4",
+		"published": "2022-06-01",
+		"legacy":    true,
+		"params":    map[string]any{"color": "red"},
+	}
+	c.Assert(rules.apply("posts/p1.md", fm), qt.IsNil)
+	c.Assert(fm, qt.DeepEquals, map[string]any{
+		"title":     "P1",
+		"authors":   []string{"Jane", "John"},
+		"published": time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC),
+		"params": map[string]any{
+			"color":  "red",
+			"review": map[string]any{"rating": 4},
+		},
+	})
+
+	fm = map[string]any{"title": "D1"}
+	c.Assert(rules.apply("drafts/d1.md", fm), qt.IsNil)
+	c.Assert(fm, qt.DeepEquals, map[string]any{"title": "D1", "draft": true})
+
+	fm = map[string]any{"rating": 3, "params": "not a map"}
+	c.Assert(rules.apply("p.md", fm), qt.ErrorMatches, `migration rule 3: can not set "params.review.rating": "params" is not a map`)
+
+	for _, test := range []struct {
+		rule map[string]any
+		err  string
+	}{
+		{map[string]any{"op": "rename", "key": "a"}, "migration rule 1: to is required for rename"},
+		{map[string]any{"op": "coerce", "key": "a", "type": "map"}, `migration rule 1: invalid type "map".*`},
+		{map[string]any{"op": "default", "key": "a"}, "migration rule 1: value is required for default"},
+		{map[string]any{"op": "copy", "key": "a"}, `migration rule 1: invalid op "copy".*`},
+		{map[string]any{"op": "delete"}, "migration rule 1: key is required"},
+	} {
+		_, err := decodeMigrationRules(map[string]any{"rules": []any{test.rule}})
+		c.Assert(err, qt.ErrorMatches, test.err)
+	}
+}
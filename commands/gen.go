@@ -23,7 +23,7 @@ type genCmd struct {
 	*baseCmd
 }
 
-func newGenCmd() *genCmd {
+func (b *commandsBuilder) newGenCmd() *genCmd {
 	cc := &genCmd{}
 	cc.baseCmd = newBaseCmd(&cobra.Command{
 		Use:   "gen",
@@ -34,7 +34,9 @@ func newGenCmd() *genCmd {
 		newGenDocCmd().getCommand(),
 		newGenManCmd().getCommand(),
 		createGenDocsHelper().getCommand(),
-		createGenChromaStyles().getCommand())
+		createGenChromaStyles().getCommand(),
+		b.newGenEpubCmd().getCommand(),
+		b.newGenShortcodeDocsCmd().getCommand())
 
 	return cc
 }
@@ -0,0 +1,406 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/common/epub"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/spf13/afero"
+	"github.com/spf13/cast"
+	"github.com/spf13/cobra"
+	jww "github.com/spf13/jwalterweatherman"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var _ cmder = (*genEpubCmd)(nil)
+
+type genEpubCmd struct {
+	output string
+	cover  string
+
+	*baseBuilderCmd
+}
+
+func (cc *genEpubCmd) buildSites() (*hugolib.HugoSites, error) {
+	c, err := initializeConfig(true, true, false, &cc.hugoBuilderCommon, cc, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sites, err := hugolib.NewHugoSites(*c.DepsCfg)
+	if err != nil {
+		return nil, newSystemError("Error creating sites", err)
+	}
+
+	if err := sites.Build(hugolib.BuildCfg{SkipRender: true}); err != nil {
+		return nil, newSystemError("Error Processing Source Content", err)
+	}
+
+	return sites, nil
+}
+
+func (b *commandsBuilder) newGenEpubCmd() *genEpubCmd {
+	cc := &genEpubCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "epub <section>",
+		Short: "Generate an EPUB of a section",
+		Long: `Generate an EPUB of the regular pages in a section, e.g. "books/my-book",
+in weight order.
+
+The book's title, description and authors are taken from the section's
+front matter. The cover image is the --cover file, else the section's
+resource or static file in the cover front matter param, else the first
+resource of the section named cover.*.
+
+Links to pages in the book point to its chapters, other links to the site
+are made absolute.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sites, err := cc.buildSites()
+			if err != nil {
+				return newSystemError("Error building sites", err)
+			}
+
+			var cover []byte
+			if cc.cover != "" {
+				if cover, err = os.ReadFile(cc.cover); err != nil {
+					return err
+				}
+			}
+
+			book, err := newEpub(sites.Sites[0], args[0], cc.cover, cover)
+			if err != nil {
+				return err
+			}
+
+			output := cc.output
+			if output == "" {
+				output = path.Base(strings.Trim(filepath.ToSlash(args[0]), "/"))
+				if output == "." || output == "" {
+					output = "book"
+				}
+				output += ".epub"
+			}
+
+			var buf bytes.Buffer
+			if err := book.Write(&buf); err != nil {
+				return err
+			}
+			if err := os.WriteFile(output, buf.Bytes(), 0666); err != nil {
+				return err
+			}
+
+			jww.FEEDBACK.Printf("Wrote %q with %d chapters to %s\n", book.Title, len(book.Chapters), output)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&cc.output, "output", "", "the EPUB file to write, default the section name with .epub extension")
+	cmd.Flags().StringVar(&cc.cover, "cover", "", "the cover image")
+
+	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
+
+	return cc
+}
+
+// newEpub creates an EPUB of the regular pages in the section in s.
+// If coverFilename is set, cover is its content.
+func newEpub(s *hugolib.Site, section, coverFilename string, cover []byte) (*epub.Book, error) {
+	sect, err := s.Info.GetPage(section)
+	if err != nil {
+		return nil, err
+	}
+	if sect == nil || !sect.IsNode() {
+		return nil, fmt.Errorf("section %q not found", section)
+	}
+
+	baseURL := s.PathSpec.BaseURL.URL()
+
+	book := &epub.Book{
+		Identifier:  sect.Permalink(),
+		Title:       sect.Title(),
+		Language:    s.Info.LanguageCode,
+		Description: sect.Description(),
+		Authors:     epubAuthors(sect, s),
+		Modified:    sect.Lastmod(),
+	}
+	if book.Language == "" {
+		book.Language = s.Language().Lang
+	}
+	if book.Title == "" {
+		book.Title = s.Info.Title()
+	}
+
+	var pages page.Pages
+	content, err := sect.Content()
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(cast.ToString(content)) != "" {
+		pages = append(pages, sect)
+	}
+	pages = append(pages, sect.RegularPagesRecursive()...)
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("section %q has no pages", section)
+	}
+
+	// The chapters and resources by URL path.
+	chapters := make(map[string]string)
+	resources := make(map[string]resource.Resource)
+	for i, p := range pages {
+		chapters[p.RelPermalink()] = fmt.Sprintf("chapter%03d.xhtml", i+1)
+		for _, r := range p.Resources() {
+			resources[r.RelPermalink()] = r
+		}
+		if p.Lastmod().After(book.Modified) {
+			book.Modified = p.Lastmod()
+		}
+	}
+	for _, r := range sect.Resources() {
+		resources[r.RelPermalink()] = r
+	}
+	if book.Modified.IsZero() {
+		book.Modified = time.Now()
+	}
+
+	// read reads the site file with the URL path p.
+	read := func(p string) (*epub.File, error) {
+		if r, found := resources[p]; found {
+			rr, ok := r.(resource.ReadSeekCloserResource)
+			if !ok {
+				return nil, nil
+			}
+			rc, err := rr.ReadSeekCloser()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			b, err := io.ReadAll(rc)
+			if err != nil {
+				return nil, err
+			}
+			return &epub.File{Name: path.Base(p), MediaType: r.MediaType().Type(), Content: b}, nil
+		}
+
+		filename := strings.TrimPrefix(p, strings.TrimSuffix(baseURL.Path, "/"))
+		filename = filepath.FromSlash(strings.TrimPrefix(filename, "/"))
+		for _, fs := range []afero.Fs{s.BaseFs.PublishFs, s.BaseFs.StaticFs(s.Language().Lang)} {
+			if b, err := afero.ReadFile(fs, filename); err == nil {
+				return &epub.File{Name: path.Base(p), MediaType: mime.TypeByExtension(path.Ext(p)), Content: b}, nil
+			}
+		}
+		return nil, nil
+	}
+
+	// The images by URL path.
+	images := make(map[string]string)
+	imageNames := make(map[string]bool)
+	addImage := func(p string) (string, error) {
+		if name, found := images[p]; found {
+			return name, nil
+		}
+		f, err := read(p)
+		if err != nil || f == nil {
+			return "", err
+		}
+		name := "images/" + f.Name
+		ext := path.Ext(name)
+		for i := 1; imageNames[name]; i++ {
+			name = fmt.Sprintf("images/%s-%d%s", strings.TrimSuffix(f.Name, ext), i, ext)
+		}
+		imageNames[name] = true
+		images[p] = name
+		f.Name = name
+		book.Files = append(book.Files, *f)
+		return name, nil
+	}
+
+	for i, p := range pages {
+		content, err := p.Content()
+		if err != nil {
+			return nil, err
+		}
+
+		pageURL, err := url.Parse(p.Permalink())
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := epubBody(cast.ToString(content), func(n *html.Node, attr *html.Attribute) error {
+			ref, err := url.Parse(attr.Val)
+			if err != nil {
+				return nil
+			}
+			u := pageURL.ResolveReference(ref)
+			if u.Host != baseURL.Host || (u.Scheme != "http" && u.Scheme != "https") {
+				return nil
+			}
+
+			switch n.DataAtom {
+			case atom.A:
+				if chapter, found := chapters[u.Path]; found {
+					attr.Val = chapter
+					if u.Fragment != "" {
+						attr.Val += "#" + u.Fragment
+					}
+					return nil
+				}
+			case atom.Img:
+				name, err := addImage(u.Path)
+				if err != nil {
+					return err
+				}
+				if name != "" {
+					attr.Val = name
+					return nil
+				}
+			}
+
+			attr.Val = u.String()
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Path(), err)
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString("<h1>")
+		xml.EscapeText(&buf, []byte(p.Title()))
+		buf.WriteString("</h1>\n")
+		buf.WriteString(body)
+
+		book.Chapters = append(book.Chapters, epub.Chapter{
+			Name:  fmt.Sprintf("chapter%03d.xhtml", i+1),
+			Title: p.Title(),
+			Body:  buf.String(),
+		})
+	}
+
+	switch {
+	case coverFilename != "":
+		book.Cover = &epub.File{
+			Name:      filepath.Base(coverFilename),
+			MediaType: mime.TypeByExtension(filepath.Ext(coverFilename)),
+			Content:   cover,
+		}
+	case sect.Params()["cover"] != nil:
+		ref := cast.ToString(sect.Params()["cover"])
+		var f *epub.File
+		if r := sect.Resources().GetMatch(ref); r != nil {
+			f, err = read(r.RelPermalink())
+		} else {
+			u, _ := url.Parse(sect.Permalink())
+			f, err = read(u.ResolveReference(&url.URL{Path: ref}).Path)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			return nil, fmt.Errorf("cover %q not found", ref)
+		}
+		book.Cover = f
+	default:
+		if r := sect.Resources().GetMatch("cover.*"); r != nil {
+			if book.Cover, err = read(r.RelPermalink()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if book.Cover != nil {
+		book.Cover.Name = "cover" + path.Ext(book.Cover.Name)
+	}
+
+	return book, nil
+}
+
+func epubAuthors(sect page.Page, s *hugolib.Site) []string {
+	for _, key := range []string{"authors", "author"} {
+		if v, found := sect.Params()[key]; found {
+			return cast.ToStringSlice(v)
+		}
+	}
+	if v, ok := s.Info.Params()["author"].(string); ok {
+		return []string{v}
+	}
+	return nil
+}
+
+// epubBody converts the HTML content to XHTML, calling rewrite on the
+// href attributes of links and the src attributes of images.
+func epubBody(content string, rewrite func(n *html.Node, attr *html.Attribute) error) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(content), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var walk func(n *html.Node) error
+	walk = func(n *html.Node) error {
+		if n.Type == html.ElementNode {
+			var attrs []html.Attribute
+			for _, attr := range n.Attr {
+				attr := attr
+				switch {
+				case n.DataAtom == atom.A && attr.Key == "href", n.DataAtom == atom.Img && attr.Key == "src":
+					if err := rewrite(n, &attr); err != nil {
+						return err
+					}
+				case n.DataAtom == atom.Img && attr.Key == "srcset":
+					// The sources are site URLs, the src is used instead.
+					continue
+				}
+				attrs = append(attrs, attr)
+			}
+			n.Attr = attrs
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		if err := walk(n); err != nil {
+			return "", err
+		}
+		if err := html.Render(&buf, n); err != nil {
+			return "", err
+		}
+	}
+
+	return buf.String(), nil
+}
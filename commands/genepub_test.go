@@ -0,0 +1,109 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestGenEpub(t *testing.T) {
+	c := qt.New(t)
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+languageCode = "en-us"
+[markup.goldmark.renderer]
+unsafe = true
+-- content/about.md --
+---
+title: "About"
+---
+-- content/books/mybook/_index.md --
+---
+title: "My Book"
+description: "A book."
+authors: ["Jane Doe", "John Doe"]
+---
+-- content/books/mybook/cover.png --
+Q09WRVI=
+-- content/books/mybook/a/index.md --
+---
+title: "Last Chapter"
+weight: 2
+---
+See [the start](../b/#start), [about](/about/) and [Hugo](https://gohugo.io/).
+
+![Logo](logo.png)<br>
+-- content/books/mybook/a/logo.png --
+TE9HTw==
+-- content/books/mybook/b.md --
+---
+title: "First Chapter"
+weight: 1
+---
+## Start
+
+Text & more.
+-- layouts/_default/single.html --
+{{ .Content }}
+-- layouts/_default/list.html --
+{{ .Title }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	book, err := newEpub(b.H.Sites[0], "books/mybook", "", nil)
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(book.Title, qt.Equals, "My Book")
+	c.Assert(book.Description, qt.Equals, "A book.")
+	c.Assert(book.Identifier, qt.Equals, "https://example.org/books/mybook/")
+	c.Assert(book.Language, qt.Equals, "en-us")
+	c.Assert(book.Authors, qt.DeepEquals, []string{"Jane Doe", "John Doe"})
+
+	c.Assert(book.Cover, qt.Not(qt.IsNil))
+	c.Assert(book.Cover.Name, qt.Equals, "cover.png")
+	c.Assert(string(book.Cover.Content), qt.Equals, "COVER")
+
+	c.Assert(book.Chapters, qt.HasLen, 2)
+	c.Assert(book.Chapters[0].Name, qt.Equals, "chapter001.xhtml")
+	c.Assert(book.Chapters[0].Title, qt.Equals, "First Chapter")
+	c.Assert(book.Chapters[0].Body, qt.Contains, `<h2 id="start">Start</h2>`)
+	c.Assert(book.Chapters[0].Body, qt.Contains, `Text &amp; more.`)
+
+	c.Assert(book.Chapters[1].Title, qt.Equals, "Last Chapter")
+	body := book.Chapters[1].Body
+	c.Assert(body, qt.Contains, "<h1>Last Chapter</h1>")
+	c.Assert(body, qt.Contains, `<a href="chapter001.xhtml#start">the start</a>`)
+	c.Assert(body, qt.Contains, `<a href="https://example.org/about/">about</a>`)
+	c.Assert(body, qt.Contains, `<a href="https://gohugo.io/">Hugo</a>`)
+	c.Assert(body, qt.Contains, `<img src="images/logo.png" alt="Logo"/><br/>`)
+
+	c.Assert(book.Files, qt.HasLen, 1)
+	c.Assert(book.Files[0].Name, qt.Equals, "images/logo.png")
+	c.Assert(book.Files[0].MediaType, qt.Equals, "image/png")
+	c.Assert(string(book.Files[0].Content), qt.Equals, "LOGO")
+
+	_, err = newEpub(b.H.Sites[0], "books/nobook", "", nil)
+	c.Assert(err, qt.ErrorMatches, `section "books/nobook" not found`)
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/spf13/cobra"
+)
+
+var _ cmder = (*genShortcodeDocsCmd)(nil)
+
+type genShortcodeDocsCmd struct {
+	format string
+	output string
+
+	*baseBuilderCmd
+}
+
+// shortcodeDoc is the documentation of a shortcode.
+type shortcodeDoc struct {
+	Name             string   `json:"name"`
+	Filename         string   `json:"filename"`
+	Doc              string   `json:"doc"`
+	Params           []string `json:"params"`
+	PositionalParams int      `json:"positionalParams"`
+	Inner            bool     `json:"inner"`
+}
+
+// partialDoc is the documentation of a partial.
+type partialDoc struct {
+	Name     string   `json:"name"`
+	Filename string   `json:"filename"`
+	Doc      string   `json:"doc"`
+	Params   []string `json:"params"`
+}
+
+type templateDocs struct {
+	Shortcodes []shortcodeDoc `json:"shortcodes"`
+	Partials   []partialDoc   `json:"partials"`
+}
+
+func (b *commandsBuilder) newGenShortcodeDocsCmd() *genShortcodeDocsCmd {
+	cc := &genShortcodeDocsCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "shortcodedocs",
+		Short: "Generate documentation for the shortcodes and partials",
+		Long: `Generate documentation for the shortcodes and partials in the project
+and its themes, e.g. for the users of a theme or for editor autocompletion.
+
+The documentation of a template is the comment it starts with:
+
+{{/* Renders a note. */}}
+
+The parameters are those the template reads: for shortcodes with .Get and
+.Params, for partials from the context, e.g. the keys of the dict passed
+to the partial.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := initializeConfig(true, true, false, &cc.hugoBuilderCommon, cc, nil)
+			if err != nil {
+				return err
+			}
+
+			sites, err := hugolib.NewHugoSites(*c.DepsCfg)
+			if err != nil {
+				return newSystemError("Error creating sites", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if cc.output != "" {
+				f, err := os.Create(cc.output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+
+			return writeTemplateDocs(out, sites, cc.format)
+		},
+	}
+
+	cmd.Flags().StringVar(&cc.format, "format", "markdown", "the output format, one of markdown or json")
+	cmd.Flags().StringVar(&cc.output, "output", "", "the file to write to instead of stdout")
+
+	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
+
+	return cc
+}
+
+// writeTemplateDocs writes the documentation of the shortcodes and partials
+// loaded from files in sites to w in the given format.
+func writeTemplateDocs(w io.Writer, sites *hugolib.HugoSites, format string) error {
+	lister, ok := sites.Tmpl().(tpl.TemplatesLister)
+	if !ok {
+		return errors.New("templates cannot be listed")
+	}
+
+	rel := func(filename string) string {
+		if r, err := filepath.Rel(sites.WorkingDir, filename); err == nil {
+			return filepath.ToSlash(r)
+		}
+		return filename
+	}
+
+	docs := templateDocs{
+		Shortcodes: make([]shortcodeDoc, 0),
+		Partials:   make([]partialDoc, 0),
+	}
+	for _, sc := range lister.Shortcodes() {
+		if sc.Filename == "" {
+			// Embedded.
+			continue
+		}
+		sc.Filename = rel(sc.Filename)
+		docs.Shortcodes = append(docs.Shortcodes, shortcodeDoc(sc))
+	}
+	for _, p := range lister.Partials() {
+		p.Filename = rel(p.Filename)
+		docs.Partials = append(docs.Partials, partialDoc(p))
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(docs)
+	case "markdown", "md":
+		return writeTemplateDocsMarkdown(w, docs)
+	default:
+		return fmt.Errorf("unsupported format %q, must be one of markdown or json", format)
+	}
+}
+
+func writeTemplateDocsMarkdown(w io.Writer, docs templateDocs) error {
+	var b strings.Builder
+
+	section := func(name, filename, doc string, lines ...string) {
+		fmt.Fprintf(&b, "\n### %s\n\n", name)
+		if doc != "" {
+			b.WriteString(doc + "\n\n")
+		}
+		for _, line := range lines {
+			if line != "" {
+				b.WriteString(line + "\n\n")
+			}
+		}
+		fmt.Fprintf(&b, "Source: `%s`\n", filename)
+	}
+
+	params := func(title string, names []string) string {
+		if len(names) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s: `%s`", title, strings.Join(names, "`, `"))
+	}
+
+	b.WriteString("## Shortcodes\n")
+	for _, sc := range docs.Shortcodes {
+		var positional, inner string
+		if sc.PositionalParams > 0 {
+			positional = fmt.Sprintf("Positional parameters: %d", sc.PositionalParams)
+		}
+		if sc.Inner {
+			inner = "Takes inner content."
+		}
+		section(sc.Name, sc.Filename, sc.Doc, params("Parameters", sc.Params), positional, inner)
+	}
+
+	b.WriteString("\n## Partials\n")
+	for _, p := range docs.Partials {
+		section(p.Name, p.Filename, p.Doc, params("Parameters", p.Params))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
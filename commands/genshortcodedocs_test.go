@@ -0,0 +1,61 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestGenShortcodeDocs(t *testing.T) {
+	c := qt.New(t)
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+-- layouts/shortcodes/note.html --
+{{/* Renders a note. */}}
+<aside class="{{ .Get "type" | default "info" }}">{{ .Inner }}</aside>
+-- layouts/shortcodes/pos.html --
+{{ .Get 0 }}{{ .Get 1 }}
+-- layouts/partials/card.html --
+{{/* Renders a card. */}}
+<div>{{ .title }}{{ with .image }}{{ .alt }}{{ end }}</div>
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	var buf bytes.Buffer
+	c.Assert(writeTemplateDocs(&buf, b.H, "markdown"), qt.IsNil)
+	c.Assert(buf.String(), qt.Equals, "## Shortcodes\n"+
+		"\n### note\n\nRenders a note.\n\nParameters: `type`\n\nTakes inner content.\n\nSource: `layouts/shortcodes/note.html`\n"+
+		"\n### pos\n\nPositional parameters: 2\n\nSource: `layouts/shortcodes/pos.html`\n"+
+		"\n## Partials\n"+
+		"\n### card.html\n\nRenders a card.\n\nParameters: `image`, `title`\n\nSource: `layouts/partials/card.html`\n")
+
+	buf.Reset()
+	c.Assert(writeTemplateDocs(&buf, b.H, "json"), qt.IsNil)
+	c.Assert(buf.String(), qt.Contains, `"name": "note"`)
+	c.Assert(buf.String(), qt.Contains, `"positionalParams": 2`)
+
+	c.Assert(writeTemplateDocs(&buf, b.H, "xml"), qt.ErrorMatches, `unsupported format "xml".*`)
+}
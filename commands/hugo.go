@@ -35,6 +35,7 @@ import (
 
 	"github.com/gohugoio/hugo/common/herrors"
 	"github.com/gohugoio/hugo/common/htime"
+	"github.com/gohugoio/hugo/common/htrace"
 	"github.com/gohugoio/hugo/common/types"
 
 	"github.com/gohugoio/hugo/hugofs"
@@ -85,14 +86,25 @@ func (r Response) IsUserError() bool {
 // Execute adds all child commands to the root command HugoCmd and sets flags appropriately.
 // The args are usually filled with os.Args[1:].
 func Execute(args []string) Response {
+	var resp Response
+
+	shutdownTracing, err := htrace.Init(context.Background())
+	if err != nil {
+		resp.Err = fmt.Errorf("failed to set up tracing: %w", err)
+		return resp
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			fmt.Fprintln(os.Stderr, "failed to export traces:", err)
+		}
+	}()
+
 	hugoCmd := newCommandsBuilder().addAll().build()
 	cmd := hugoCmd.getCommand()
 	cmd.SetArgs(args)
 
 	c, err := cmd.ExecuteC()
 
-	var resp Response
-
 	if c == cmd && hugoCmd.c != nil {
 		// Root command executed
 		resp.Result = hugoCmd.c.hugo()
@@ -139,7 +151,10 @@ func (c *commandeer) createLogger(cfg config.Provider) (loggers.Logger, error) {
 		stdoutThreshold = jww.LevelWarn
 	)
 
-	if !c.h.quiet {
+	if c.h.rpc {
+		// stdout is reserved for the JSON-RPC responses.
+		outHandle = os.Stderr
+	} else if !c.h.quiet {
 		outHandle = os.Stdout
 	}
 
@@ -171,10 +186,18 @@ func (c *commandeer) createLogger(cfg config.Provider) (loggers.Logger, error) {
 		}
 	}
 
-	loggers.InitGlobalLogger(stdoutThreshold, logThreshold, outHandle, logHandle)
-	helpers.InitLoggers()
-
-	return loggers.NewLogger(stdoutThreshold, logThreshold, outHandle, logHandle, c.running), nil
+	switch logFormat := c.h.logFormat; logFormat {
+	case "", loggers.LogFormatText:
+		loggers.InitGlobalLogger(stdoutThreshold, logThreshold, outHandle, logHandle)
+		helpers.InitLoggers()
+		return loggers.NewLogger(stdoutThreshold, logThreshold, outHandle, logHandle, c.running), nil
+	case loggers.LogFormatJSON:
+		loggers.InitGlobalDiagnosticsLogger(stdoutThreshold, logThreshold, outHandle, logHandle)
+		helpers.InitLoggers()
+		return loggers.NewDiagnosticsLogger(stdoutThreshold, logThreshold, outHandle, logHandle, c.running), nil
+	default:
+		return nil, newUserError(fmt.Sprintf("invalid logFormat %q, must be one of %q or %q", logFormat, loggers.LogFormatText, loggers.LogFormatJSON))
+	}
 }
 
 func initializeFlags(cmd *cobra.Command, cfg config.Provider) {
@@ -212,6 +235,8 @@ func initializeFlags(cmd *cobra.Command, cfg config.Provider) {
 		"cfgFile",
 		"confirm",
 		"contentDir",
+		"contentExclude",
+		"contentInclude",
 		"debug",
 		"destination",
 		"disableKinds",
@@ -220,12 +245,14 @@ func initializeFlags(cmd *cobra.Command, cfg config.Provider) {
 		"gc",
 		"printI18nWarnings",
 		"printUnusedTemplates",
+		"printEscapeAudit",
 		"invalidateCDN",
 		"layoutDir",
 		"logFile",
 		"maxDeletes",
 		"quiet",
 		"renderToMemory",
+		"publishDestination",
 		"source",
 		"target",
 		"theme",
@@ -288,7 +315,11 @@ func (c *commandeer) fullBuild(noBuildLock bool) error {
 		langCount map[string]uint64
 	)
 
-	if !c.h.quiet {
+	ctx, span := htrace.Start(context.Background(), "fullBuild")
+	defer span.End()
+	defer htrace.SetBuildContext(ctx)()
+
+	if c.h.printFeedback() {
 		fmt.Println("Start building sites … ")
 		fmt.Println(hugo.BuildVersionString())
 		if isTerminal() {
@@ -299,7 +330,9 @@ func (c *commandeer) fullBuild(noBuildLock bool) error {
 	}
 
 	copyStaticFunc := func() error {
+		span := htrace.StartInBuild("copyStatic")
 		cnt, err := c.copyStatic()
+		htrace.End(span, err)
 		if err != nil {
 			return fmt.Errorf("Error copying static files: %w", err)
 		}
@@ -504,11 +537,21 @@ func (c *commandeer) build() error {
 		return err
 	}
 
-	if !c.h.quiet {
+	if err := c.flushPublishDestination(); err != nil {
+		return err
+	}
+
+	if err := c.hugo().WriteFileCacheStats(); err != nil {
+		c.logger.Warnf("Failed to write the file cache stats: %s", err)
+	}
+
+	if c.h.printFeedback() {
 		fmt.Println()
 		c.hugo().PrintProcessingStats(os.Stdout)
 		fmt.Println()
+	}
 
+	if !c.h.quiet {
 		if createCounter, ok := c.publishDirFs.(hugofs.DuplicatesReporter); ok {
 			dupes := createCounter.ReportDuplicates()
 			if dupes != "" {
@@ -522,6 +565,12 @@ func (c *commandeer) build() error {
 		}
 	}
 
+	if c.Cfg.GetBool("printEscapeAudit") {
+		if err := c.printEscapeAudit(os.Stdout); err != nil {
+			return err
+		}
+	}
+
 	if c.h.buildWatch {
 		watchDirs, err := c.getDirList()
 		if err != nil {
@@ -563,7 +612,7 @@ func (c *commandeer) serverBuild() error {
 	}
 
 	// TODO(bep) Feedback?
-	if !c.h.quiet {
+	if c.h.printFeedback() {
 		fmt.Println()
 		c.hugo().PrintProcessingStats(os.Stdout)
 		fmt.Println()
@@ -760,7 +809,22 @@ func (c *commandeer) rebuildSites(events []fsnotify.Event) error {
 			visited[home] = true
 		}
 	}
-	return c.hugo().Build(hugolib.BuildCfg{NoBuildLock: true, RecentlyVisited: visited, ErrRecovery: c.wasError}, events...)
+	if err := c.hugo().Build(hugolib.BuildCfg{NoBuildLock: true, RecentlyVisited: visited, ErrRecovery: c.wasError}, events...); err != nil {
+		return err
+	}
+	return c.flushPublishDestination()
+}
+
+// flushPublishDestination writes the published files to the configured
+// publishDestination, if any.
+func (c *commandeer) flushPublishDestination() error {
+	if c.publishDestination == nil {
+		return nil
+	}
+	if err := c.publishDestination.Flush(context.Background()); err != nil {
+		return fmt.Errorf("failed to publish to %q: %w", c.publishDestination, err)
+	}
+	return nil
 }
 
 func (c *commandeer) partialReRender(urls ...string) error {
@@ -803,13 +867,26 @@ func (c *commandeer) fullRebuild(changeType string) {
 
 		defer c.timeTrack(time.Now(), "Rebuilt")
 
+		trigger := changeType
+		if trigger == "" {
+			trigger = "full rebuild"
+		}
+		var buildErr error
+		defer func(start time.Time) {
+			var pages []hugolib.RenderedPage
+			if buildErr == nil {
+				pages = c.renderedPages()
+			}
+			c.recordRebuild(start, trigger, true, nil, pages, buildErr)
+		}(time.Now())
+
 		c.commandeerHugoState = newCommandeerHugoState()
 		err := c.loadConfig()
 		if err != nil {
 			// Set the processing on pause until the state is recovered.
 			c.paused = true
 			c.handleBuildErr(err, "Failed to reload config")
-
+			buildErr = err
 		} else {
 			c.paused = false
 		}
@@ -818,10 +895,12 @@ func (c *commandeer) fullRebuild(changeType string) {
 			_, err := c.copyStatic()
 			if err != nil {
 				c.logger.Errorln(err)
+				buildErr = err
 				return
 			}
 
 			err = c.buildSites(true)
+			buildErr = err
 			if err != nil {
 				c.logger.Errorln(err)
 			} else if !c.h.buildWatch && !c.Cfg.GetBool("disableLiveReload") {
@@ -842,21 +921,33 @@ func (c *commandeer) newWatcher(pollIntervalStr string, dirList ...string) (*wat
 		return nil, err
 	}
 
-	var pollInterval time.Duration
-	poll := pollIntervalStr != ""
-	if poll {
-		pollInterval, err = types.ToDurationE(pollIntervalStr)
+	conf, err := watcher.DecodeConfig(c.Cfg)
+	if err != nil {
+		return nil, err
+	}
+	if c.h.watcher != "" {
+		conf.Type = c.h.watcher
+	}
+	if pollIntervalStr != "" {
+		conf.Type = watcher.TypePoll
+		conf.Interval, err = types.ToDurationE(pollIntervalStr)
 		if err != nil {
 			return nil, fmt.Errorf("invalid value for flag poll: %s", err)
 		}
-		c.logger.Printf("Use watcher with poll interval %v", pollInterval)
 	}
-
-	if pollInterval == 0 {
-		pollInterval = 500 * time.Millisecond
+	if conf.Include != nil {
+		// Always watch the config files.
+		for _, configFile := range c.configFiles {
+			if rel, err := filepath.Rel(c.Cfg.GetString("workingDir"), configFile); err == nil {
+				conf.Include = append(conf.Include, rel)
+			}
+		}
+	}
+	if conf.Type == watcher.TypePoll {
+		c.logger.Printf("Use watcher with poll interval %v", conf.Interval)
 	}
 
-	watcher, err := watcher.New(500*time.Millisecond, pollInterval, poll)
+	watcher, err := watcher.NewFromConfig(conf)
 	if err != nil {
 		return nil, err
 	}
@@ -932,6 +1023,8 @@ func (c *commandeer) handleEvents(watcher *watcher.Batcher,
 		c.wasError = false
 	}()
 
+	c.serverMetrics.addWatcherEvents(evs)
+
 	var isHandled bool
 
 	for _, ev := range evs {
@@ -990,6 +1083,7 @@ func (c *commandeer) handleEvents(watcher *watcher.Batcher,
 	if len(evs) > 50 {
 		// This is probably a mass edit of the content dir.
 		// Schedule a full rebuild for when it slows down.
+		c.logger.Infof("Received %d file system events, scheduling a full rebuild", len(evs))
 		c.debounce(func() {
 			c.fullRebuild("")
 		})
@@ -1108,6 +1202,7 @@ func (c *commandeer) handleEvents(watcher *watcher.Batcher,
 
 	if len(staticEvents) > 0 {
 		c.printChangeDetected("Static files")
+		defer c.recordRebuild(time.Now(), "static files", false, staticEvents, nil, nil)
 
 		if c.Cfg.GetBool("forceSyncStatic") {
 			c.logger.Printf("Syncing all static files\n")
@@ -1152,9 +1247,12 @@ func (c *commandeer) handleEvents(watcher *watcher.Batcher,
 
 		func() {
 			defer c.timeTrack(time.Now(), "Total")
-			if err := c.rebuildSites(dynamicEvents); err != nil {
+			start := time.Now()
+			err := c.rebuildSites(dynamicEvents)
+			if err != nil {
 				c.handleBuildErr(err, "Rebuild failed")
 			}
+			c.recordRebuild(start, "content, templates or assets", false, dynamicEvents, c.renderedPages(), err)
 		}()
 
 		if doLiveReload {
@@ -0,0 +1,71 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gohugoio/hugo/tpl"
+)
+
+// printEscapeAudit writes the uses of safeHTML, safeJS, safeURL etc. in the
+// templates to w, the most risky first.
+func (c *commandeer) printEscapeAudit(w io.Writer) error {
+	auditor, ok := c.hugo().Tmpl().(tpl.EscapeAuditor)
+	if !ok {
+		return nil
+	}
+	entries, err := auditor.EscapeAudit()
+	if err != nil {
+		return err
+	}
+	return writeEscapeAudit(w, c.Cfg.GetString("workingDir"), entries)
+}
+
+func writeEscapeAudit(w io.Writer, workingDir string, entries []tpl.EscapeAuditEntry) error {
+	fmt.Fprintf(w, "\nEscape Audit:\n\n")
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No uses of safeHTML, safeJS, safeURL etc. found.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tFUNC\tTEMPLATE\tEXPRESSION")
+	counts := make(map[string]int)
+	for _, e := range entries {
+		filename := e.Filename
+		if rel, err := filepath.Rel(workingDir, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			filename = rel
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s:%d:%d\t%s\n", e.Source, e.Func, filepath.ToSlash(filename), e.Line, e.Column, e.Expr)
+		counts[e.Source]++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var summary []string
+	for _, source := range []string{tpl.EscapeSourceRemote, tpl.EscapeSourceParam, tpl.EscapeSourceData, tpl.EscapeSourceContent, tpl.EscapeSourceOther, tpl.EscapeSourceLiteral} {
+		if counts[source] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[source], source))
+		}
+	}
+	fmt.Fprintf(w, "\nTotal: %d (%s)\n", len(entries), strings.Join(summary, ", "))
+
+	return nil
+}
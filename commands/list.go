@@ -15,12 +15,17 @@ package commands
 
 import (
 	"encoding/csv"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/resources/page"
 	"github.com/gohugoio/hugo/resources/resource"
 	"github.com/spf13/cobra"
 	jww "github.com/spf13/jwalterweatherman"
@@ -204,7 +209,144 @@ List requires a subcommand, e.g. ` + "`hugo list drafts`.",
 		},
 	)
 
+	cmd.AddCommand(cc.newLayoutsCmd())
+
 	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
 
 	return cc
 }
+
+func (lc *listCmd) newLayoutsCmd() *cobra.Command {
+	var ref string
+
+	cmd := &cobra.Command{
+		Use:   "layouts",
+		Short: "List the template lookup order of a page",
+		Long: `List the templates evaluated, in lookup order, when rendering a page in
+each of its output formats, which one is used and the module it comes from.
+
+The page is given with --for, either as the path to its content file or as a
+reference as accepted by .GetPage, e.g. /posts/my-post.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ref == "" {
+				return newUserError("the --for flag is required")
+			}
+
+			sites, err := lc.buildSites(nil)
+			if err != nil {
+				return newSystemError("Error building sites", err)
+			}
+
+			pages, err := findPagesForLayouts(sites, ref)
+			if err != nil {
+				return err
+			}
+
+			return writeLayoutLookups(os.Stdout, sites, pages)
+		},
+	}
+
+	cmd.Flags().StringVar(&ref, "for", "", "the page to list the layouts for")
+
+	return cmd
+}
+
+// findPagesForLayouts finds the page, in all languages, given a content
+// filename or a page reference.
+func findPagesForLayouts(sites *hugolib.HugoSites, ref string) (page.Pages, error) {
+	filename := ref
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(sites.WorkingDir, filename)
+	}
+	if fi, err := os.Stat(filename); err == nil && !fi.IsDir() {
+		if p := sites.GetContentPage(filename); p != nil {
+			return page.Pages{p}, nil
+		}
+		return nil, newUserError(fmt.Sprintf("no page found for %q", ref))
+	}
+
+	var pages page.Pages
+	for _, s := range sites.Sites {
+		p, err := s.Info.GetPage(ref)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil {
+			pages = append(pages, p)
+		}
+	}
+	if len(pages) == 0 {
+		return nil, newUserError(fmt.Sprintf("no page found for %q", ref))
+	}
+
+	return pages, nil
+}
+
+func writeLayoutLookups(w io.Writer, sites *hugolib.HugoSites, pages page.Pages) error {
+	relFilename := func(filename string) string {
+		if rel, err := filepath.Rel(sites.WorkingDir, filename); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+		return filename
+	}
+
+	for i, p := range pages {
+		lookups, err := sites.ExplainLayouts(p)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		var title string
+		if p.File().IsZero() {
+			title = p.Path()
+		} else {
+			title = relFilename(p.File().Filename())
+		}
+		fmt.Fprintf(w, "Page %q (kind %s, language %s)\n", title, p.Kind(), p.Language().Lang)
+
+		for _, l := range lookups {
+			fmt.Fprintf(w, "\nOutput format %s:\n", l.OutputFormat.Name)
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			winner := l.Winner()
+			for _, c := range l.Candidates {
+				status := "-"
+				if c.Found {
+					status = "shadowed"
+					if winner != nil && c.Name == winner.Name {
+						status = "used"
+					}
+				}
+				source := ""
+				if c.Found {
+					switch {
+					case c.Filename == "":
+						source = "embedded"
+					case c.Module != "":
+						source = relFilename(c.Filename) + " (" + c.Module + ")"
+					default:
+						source = relFilename(c.Filename)
+					}
+					if c.Baseof != "" {
+						source += " with " + relFilename(c.Baseof)
+					}
+				}
+				if source == "" {
+					fmt.Fprintf(tw, "  %s\t%s\n", c.Name, status)
+				} else {
+					fmt.Fprintf(tw, "  %s\t%s\t%s\n", c.Name, status, source)
+				}
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			if winner == nil {
+				fmt.Fprintln(w, "  No template found.")
+			}
+		}
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -66,3 +67,27 @@ func TestListAll(t *testing.T) {
 		"false", "https://example.org/p1/",
 	})
 }
+
+func TestListLayouts(t *testing.T) {
+	c := qt.New(t)
+	dir := createSimpleTestSite(t, testSiteConfig{})
+	writeFile(t, filepath.Join(dir, "layouts", "page", "single.html"), `Page single`)
+
+	for _, ref := range []string{filepath.Join("content", "p1.md"), "/p1"} {
+		hugoCmd := newCommandsBuilder().addAll().build()
+		cmd := hugoCmd.getCommand()
+		cmd.SetArgs([]string{"-s=" + dir, "list", "layouts", "--for", ref})
+
+		out, err := captureStdout(func() error {
+			_, err := cmd.ExecuteC()
+			return err
+		})
+		c.Assert(err, qt.IsNil)
+
+		c.Assert(out, qt.Contains, `Page "`+filepath.Join("content", "p1.md")+`" (kind page, language en)`)
+		c.Assert(out, qt.Contains, "Output format HTML:")
+		c.Assert(out, qt.Matches, `(?s).*page/single.html\s+used\s+`+regexp.QuoteMeta(filepath.Join("layouts", "page", "single.html"))+`\n.*`)
+		c.Assert(out, qt.Matches, `(?s).*_default/single.html\s+shadowed\s+`+regexp.QuoteMeta(filepath.Join("layouts", "_default", "single.html"))+`\n.*`)
+		c.Assert(out, qt.Matches, `(?s).*page/single.html.html\s+-\s*\n.*`)
+	}
+}
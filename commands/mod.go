@@ -214,6 +214,21 @@ Note that for vendored modules, that is the version listed and not the one from
 				})
 			},
 		},
+		&cobra.Command{
+			Use:   "layouts",
+			Short: "Print the templates provided by more than one module.",
+			Long: `Print the templates provided by more than one module, the module whose template is used
+and the modules it overrides.
+
+A module provides the templates in its layouts mounts and the layouts declared in the
+module.provides section of its config.
+`,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return c.withModsClient(true, func(c *modules.Client) error {
+					return c.Layouts(os.Stdout)
+				})
+			},
+		},
 		&cobra.Command{
 			Use:   "init",
 			Short: "Initialize this project as a Hugo Module.",
@@ -32,6 +32,7 @@ var _ cmder = (*newCmd)(nil)
 type newCmd struct {
 	contentEditor string
 	contentType   string
+	fromURL       string
 
 	*baseBuilderCmd
 }
@@ -45,18 +46,45 @@ It will guess which kind of file to create based on the path provided.
 
 You can also specify the kind with ` + "`-k KIND`" + `.
 
+With ` + "`--fromURL URL`" + ` the web page at URL is fetched, its main content
+converted to Markdown and stored, with its images, in a page bundle in [path].
+
 If archetypes are provided in your theme or site, they will be used.
 
 Ensure you run this within the root directory of your site.`,
 	}
 
+	cc := b.newNewContentCmdFrom(cmd)
+
+	cmd.AddCommand(b.newNewContentCmd().getCommand())
+	cmd.AddCommand(b.newNewSiteCmd().getCommand())
+	cmd.AddCommand(b.newNewThemeCmd().getCommand())
+
+	return cc
+}
+
+func (b *commandsBuilder) newNewContentCmd() *newCmd {
+	cmd := &cobra.Command{
+		Use:   "content [path]",
+		Short: "Create new content for your site",
+		Long: `Create a new content file and automatically set the date and title.
+It will guess which kind of file to create based on the path provided.
+
+You can also specify the kind with ` + "`-k KIND`" + `.
+
+With ` + "`--fromURL URL`" + ` the web page at URL is fetched, its main content
+converted to Markdown and stored, with its images, in a page bundle in [path].`,
+	}
+
+	return b.newNewContentCmdFrom(cmd)
+}
+
+func (b *commandsBuilder) newNewContentCmdFrom(cmd *cobra.Command) *newCmd {
 	cc := &newCmd{baseBuilderCmd: b.newBuilderCmd(cmd)}
 
 	cmd.Flags().StringVarP(&cc.contentType, "kind", "k", "", "content type to create")
 	cmd.Flags().StringVar(&cc.contentEditor, "editor", "", "edit new content with this editor, if provided")
-
-	cmd.AddCommand(b.newNewSiteCmd().getCommand())
-	cmd.AddCommand(b.newNewThemeCmd().getCommand())
+	cmd.Flags().StringVar(&cc.fromURL, "fromURL", "", "create a page bundle from the web page at this URL")
 
 	cmd.RunE = cc.newContent
 
@@ -80,6 +108,10 @@ func (n *newCmd) newContent(cmd *cobra.Command, args []string) error {
 		return newUserError("path needs to be provided")
 	}
 
+	if n.fromURL != "" {
+		return create.NewContentFromURL(c.hugo(), n.fromURL, args[0])
+	}
+
 	return create.NewContent(c.hugo(), n.contentType, args[0])
 }
 
@@ -0,0 +1,89 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/spf13/afero"
+)
+
+// buildProfiles builds the site once per profile, in sequence, in the same
+// process. The profiles share the file caches and the resources dir, so e.g.
+// images processed and remote resources fetched for one profile are reused
+// by the next. The profiles also share a source filesystem that keeps the
+// files read in memory, so content, data and templates are read from disk
+// once. Everything else, including the content map, is rebuilt per profile,
+// as the profile settings may change how the content is parsed and rendered.
+func (cc *hugoCmd) buildProfiles(cfgInit func(c *commandeer) error) error {
+	if cc.buildWatch {
+		return newUserError("--profiles can not be combined with --watch")
+	}
+
+	cc.profilesSourceFs = newProfilesSourceFs(hugofs.Os)
+	defer func() {
+		cc.profile = ""
+		cc.profilesSourceFs = nil
+	}()
+
+	seen := make(map[string]bool)
+	for _, profile := range cc.profiles {
+		if profile == "" || seen[profile] {
+			continue
+		}
+		seen[profile] = true
+
+		if cc.printFeedback() {
+			fmt.Printf("Building profile %q\n", profile)
+		}
+
+		start := time.Now()
+		cc.profile = profile
+		c, err := initializeConfig(true, true, false, &cc.hugoBuilderCommon, cc, cfgInit)
+		if err != nil {
+			return fmt.Errorf("profile %q: %w", profile, err)
+		}
+		cc.c = c
+
+		if err := c.build(); err != nil {
+			return fmt.Errorf("profile %q: %w", profile, err)
+		}
+		cc.timeTrack(start, fmt.Sprintf("Profile %q", profile))
+	}
+
+	return nil
+}
+
+// profilesSourceFs caches the files read from the base filesystem in memory.
+// Writes go to both, so the files written by one profile build, e.g. to
+// the resources dir, are seen by the next.
+type profilesSourceFs struct {
+	afero.Fs
+	base afero.Fs
+}
+
+func newProfilesSourceFs(base afero.Fs) afero.Fs {
+	return profilesSourceFs{
+		Fs:   afero.NewCacheOnReadFs(base, afero.NewMemMapFs(), 0),
+		base: base,
+	}
+}
+
+// UnwrapFilesystem returns the base filesystem, so e.g. hugofs.IsOsFs
+// reports on where the files are stored.
+func (fs profilesSourceFs) UnwrapFilesystem() afero.Fs {
+	return fs.base
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+var _ cmder = (*renderCmd)(nil)
+
+type renderCmd struct {
+	*baseBuilderCmd
+
+	format string
+	target string
+}
+
+func (b *commandsBuilder) newRenderCmd() *renderCmd {
+	cc := &renderCmd{}
+
+	cmd := &cobra.Command{
+		Use:   "render [path]",
+		Short: "Render a single page",
+		Long: `Render a single content page, including drafts, future and expired pages,
+in one output format.
+
+The path is either the content file, e.g. content/blog/my-post.md, or
+the path of the page, e.g. /blog/my-post.
+
+The page is written to stdout, or with --target, written with its resources
+and the resources its templates publish to the target directory.
+Nothing is written to the publish directory.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cc.render(cmd.OutOrStdout(), args[0])
+		},
+	}
+
+	cmd.Flags().StringVarP(&cc.format, "format", "f", "html", "the output format to render")
+	cmd.Flags().StringVar(&cc.target, "target", "", "the directory to write the page and its resources to instead of stdout")
+
+	cc.baseBuilderCmd = b.newBuilderBasicCmd(cmd)
+
+	return cc
+}
+
+func (cc *renderCmd) render(out io.Writer, ref string) error {
+	cfgInit := func(c *commandeer) error {
+		c.Set("buildDrafts", true)
+		c.Set("buildFuture", true)
+		c.Set("buildExpired", true)
+		c.Set("renderToMemory", true)
+		return nil
+	}
+
+	c, err := initializeConfig(true, true, false, &cc.hugoBuilderCommon, cc, cfgInit)
+	if err != nil {
+		return err
+	}
+
+	sites, err := hugolib.NewHugoSites(*c.DepsCfg)
+	if err != nil {
+		return newSystemError("Error creating sites", err)
+	}
+
+	if err := sites.Build(hugolib.BuildCfg{SkipRender: true}); err != nil {
+		return newSystemError("Error Processing Source Content", err)
+	}
+
+	p, err := findRenderPage(sites, ref)
+	if err != nil {
+		return err
+	}
+
+	filename, err := sites.RenderPage(p, cc.format)
+	if err != nil {
+		return err
+	}
+
+	publishFs := sites.Fs.PublishDir
+
+	if cc.target == "" {
+		f, err := publishFs.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(out, f)
+		return err
+	}
+
+	return afero.Walk(publishFs, "/", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		f, err := publishFs.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return helpers.WriteToDisk(filepath.Join(cc.target, filepath.FromSlash(path)), f, hugofs.Os)
+	})
+}
+
+// findRenderPage finds the page for ref, a content filename relative to
+// the working dir or absolute, or a page path.
+func findRenderPage(sites *hugolib.HugoSites, ref string) (page.Page, error) {
+	for _, filename := range []string{filepath.Join(sites.WorkingDir, ref), ref} {
+		if !filepath.IsAbs(filename) {
+			continue
+		}
+		if p := sites.GetContentPage(filepath.Clean(filename)); p != nil {
+			return p, nil
+		}
+	}
+
+	for _, s := range sites.Sites {
+		p, err := s.Info.GetPage(ref)
+		if err != nil {
+			return nil, err
+		}
+		if p != nil && p != page.NilPage {
+			return p, nil
+		}
+	}
+
+	return nil, fmt.Errorf("page %q not found", ref)
+}
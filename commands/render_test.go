@@ -0,0 +1,66 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRender(t *testing.T) {
+	c := qt.New(t)
+	dir := createSimpleTestSite(t, testSiteConfig{})
+	writeFile(t, filepath.Join(dir, "content", "drafts", "d1.md"), `
+---
+title: "D1"
+draft: true
+---
+`)
+
+	render := func(args ...string) (string, error) {
+		hugoCmd := newCommandsBuilder().addAll().build()
+		cmd := hugoCmd.getCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(append([]string{"render", "-s=" + dir}, args...))
+		_, err := cmd.ExecuteC()
+		return out.String(), err
+	}
+
+	out, err := render(filepath.Join("content", "p1.md"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(out, qt.Contains, "Single: P1")
+
+	out, err = render("/drafts/d1")
+	c.Assert(err, qt.IsNil)
+	c.Assert(out, qt.Contains, "Single: D1")
+
+	_, err = render("/nope")
+	c.Assert(err, qt.ErrorMatches, `page "/nope" not found`)
+
+	target := t.TempDir()
+	_, err = render("--target", target, "/p1")
+	c.Assert(err, qt.IsNil)
+	b, err := os.ReadFile(filepath.Join(target, "p1", "index.html"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Contains, "Single: P1")
+
+	// Nothing is published to the publish dir.
+	_, err = os.Stat(filepath.Join(dir, "public", "p1"))
+	c.Assert(os.IsNotExist(err), qt.IsTrue)
+}
@@ -0,0 +1,493 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/resources/page/pagemeta"
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/spf13/afero"
+)
+
+// The JSON-RPC 2.0 error codes.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+
+	// The site could not be built.
+	rpcBuildError = -32000
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+func newRPCError(code int, err error) *rpcError {
+	return &rpcError{Code: code, Message: err.Error()}
+}
+
+// rpcServer serves JSON-RPC 2.0 requests framed with Content-Length headers,
+// as in the Language Server Protocol, one at a time.
+type rpcServer struct {
+	c *commandeer
+
+	r *bufio.Reader
+	w io.Writer
+
+	// The error from the last build, reported by all methods that
+	// need the site until the next successful build.
+	buildErr error
+}
+
+type rpcRenderParams struct {
+	// A content filename or a page path.
+	Path string `json:"path"`
+
+	// The output format, default html.
+	Format string `json:"format"`
+}
+
+type rpcRenderResult struct {
+	// The filename relative to the publish dir.
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+type rpcTemplate struct {
+	Name     string `json:"name"`
+	Filename string `json:"filename"`
+}
+
+type rpcShortcodeParams struct {
+	Name string `json:"name"`
+}
+
+type rpcValidateFrontMatterParams struct {
+	// The content filename.
+	Path string `json:"path"`
+
+	// The content, e.g. with unsaved changes. Read from Path if not set.
+	Content *string `json:"content"`
+}
+
+type rpcViolation struct {
+	Key     string `json:"key"`
+	Missing bool   `json:"missing"`
+	Message string `json:"message"`
+}
+
+type rpcChangedParams struct {
+	// The changed, created or removed files.
+	Files []string `json:"files"`
+}
+
+func (cc *hugoCmd) serveRPC(in io.Reader, out io.Writer) error {
+	cfgInit := func(c *commandeer) error {
+		c.Set("buildDrafts", true)
+		c.Set("buildFuture", true)
+		c.Set("buildExpired", true)
+		c.Set("renderToMemory", true)
+		c.Set("disableLiveReload", true)
+		return nil
+	}
+
+	c, err := initializeConfig(true, true, true, &cc.hugoBuilderCommon, cc, cfgInit)
+	if err != nil {
+		return err
+	}
+	cc.c = c
+
+	s := &rpcServer{c: c, r: bufio.NewReader(in), w: out}
+	s.buildErr = c.hugo().Build(hugolib.BuildCfg{SkipRender: true})
+	if s.buildErr != nil {
+		c.logger.Errorln("Error building site:", s.buildErr)
+	}
+
+	return s.serve()
+}
+
+func (s *rpcServer) serve() error {
+	for {
+		b, err := s.readMessage()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		var req rpcRequest
+		if err := json.Unmarshal(b, &req); err != nil {
+			if err := s.writeResponse(rpcResponse{ID: json.RawMessage("null"), Error: newRPCError(rpcParseError, err)}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result, err := s.handle(req)
+
+		if req.Method == "exit" {
+			return nil
+		}
+
+		if req.ID == nil {
+			// A notification.
+			continue
+		}
+
+		resp := rpcResponse{ID: req.ID}
+		if err != nil {
+			var rerr *rpcError
+			if !errors.As(err, &rerr) {
+				rerr = newRPCError(rpcInternalError, err)
+			}
+			resp.Error = rerr
+		} else if resp.Result, err = json.Marshal(result); err != nil {
+			resp.Result = nil
+			resp.Error = newRPCError(rpcInternalError, err)
+		}
+
+		if err := s.writeResponse(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *rpcServer) handle(req rpcRequest) (any, error) {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return nil, &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+	}
+
+	switch req.Method {
+	case "render":
+		var params rpcRenderParams
+		if err := s.decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.render(params)
+	case "templates":
+		return s.templates()
+	case "shortcodes":
+		return s.shortcodes()
+	case "partials":
+		return s.partials()
+	case "shortcode":
+		var params rpcShortcodeParams
+		if err := s.decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.shortcode(params)
+	case "validateFrontMatter":
+		var params rpcValidateFrontMatterParams
+		if err := s.decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return s.validateFrontMatter(params)
+	case "changed":
+		var params rpcChangedParams
+		if err := s.decodeParams(req, &params); err != nil {
+			return nil, err
+		}
+		return nil, s.changed(params)
+	case "shutdown", "exit":
+		return nil, nil
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+func (s *rpcServer) decodeParams(req rpcRequest, v any) error {
+	if len(req.Params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(req.Params, v); err != nil {
+		return newRPCError(rpcInvalidParams, err)
+	}
+	return nil
+}
+
+func (s *rpcServer) sites() (*hugolib.HugoSites, error) {
+	if s.buildErr != nil {
+		return nil, newRPCError(rpcBuildError, s.buildErr)
+	}
+	return s.c.hugo(), nil
+}
+
+func (s *rpcServer) render(params rpcRenderParams) (any, error) {
+	if params.Path == "" {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "path: missing"}
+	}
+	if params.Format == "" {
+		params.Format = "html"
+	}
+
+	sites, err := s.sites()
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := findRenderPage(sites, params.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	filename, err := sites.RenderPage(p, params.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := afero.ReadFile(sites.Fs.PublishDir, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return rpcRenderResult{Filename: filepath.ToSlash(filename), Content: string(b)}, nil
+}
+
+func (s *rpcServer) templatesLister() (tpl.TemplatesLister, error) {
+	sites, err := s.sites()
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := sites.Tmpl().(tpl.TemplatesLister)
+	if !ok {
+		return nil, errors.New("templates cannot be listed")
+	}
+	return lister, nil
+}
+
+func (s *rpcServer) templates() (any, error) {
+	lister, err := s.templatesLister()
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]rpcTemplate, 0)
+	for _, t := range lister.Templates() {
+		templates = append(templates, rpcTemplate{Name: t.Name(), Filename: t.Filename()})
+	}
+
+	return templates, nil
+}
+
+func (s *rpcServer) shortcodes() (any, error) {
+	lister, err := s.templatesLister()
+	if err != nil {
+		return nil, err
+	}
+
+	shortcodes := make([]shortcodeDoc, 0)
+	for _, sc := range lister.Shortcodes() {
+		shortcodes = append(shortcodes, shortcodeDoc(sc))
+	}
+
+	return shortcodes, nil
+}
+
+func (s *rpcServer) partials() (any, error) {
+	lister, err := s.templatesLister()
+	if err != nil {
+		return nil, err
+	}
+
+	partials := make([]partialDoc, 0)
+	for _, p := range lister.Partials() {
+		partials = append(partials, partialDoc(p))
+	}
+
+	return partials, nil
+}
+
+func (s *rpcServer) shortcode(params rpcShortcodeParams) (any, error) {
+	lister, err := s.templatesLister()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sc := range lister.Shortcodes() {
+		if sc.Name == params.Name {
+			return shortcodeDoc(sc), nil
+		}
+	}
+
+	return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("shortcode %q not found", params.Name)}
+}
+
+func (s *rpcServer) validateFrontMatter(params rpcValidateFrontMatterParams) (any, error) {
+	if params.Path == "" {
+		return nil, &rpcError{Code: rpcInvalidParams, Message: "path: missing"}
+	}
+
+	sites, err := s.sites()
+	if err != nil {
+		return nil, err
+	}
+
+	filename := s.absPath(params.Path)
+
+	var content []byte
+	if params.Content != nil {
+		content = []byte(*params.Content)
+	} else if content, err = os.ReadFile(filename); err != nil {
+		return nil, err
+	}
+
+	var violations []pagemeta.FrontMatterViolation
+	violations, err = sites.ValidateFrontMatter(filename, content)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]rpcViolation, 0)
+	for _, v := range violations {
+		result = append(result, rpcViolation(v))
+	}
+
+	return result, nil
+}
+
+// changed rebuilds the site after the files changed, reloading the
+// configuration first if a config file changed.
+func (s *rpcServer) changed(params rpcChangedParams) error {
+	c := s.c
+
+	configSet := make(map[string]bool)
+	for _, filename := range c.configFiles {
+		configSet[filename] = true
+	}
+
+	var events []fsnotify.Event
+	configChanged := false
+	for _, filename := range params.Files {
+		filename = s.absPath(filename)
+		if configSet[filename] {
+			configChanged = true
+		}
+		op := fsnotify.Write
+		if _, err := os.Stat(filename); os.IsNotExist(err) {
+			op = fsnotify.Remove
+		}
+		events = append(events, fsnotify.Event{Name: filename, Op: op})
+	}
+
+	if configChanged || s.buildErr != nil {
+		// Rebuild from scratch, as we do not know what state
+		// a failed build left the site in.
+		c.commandeerHugoState = newCommandeerHugoState()
+		if err := c.loadConfig(); err != nil {
+			s.buildErr = err
+			return newRPCError(rpcBuildError, err)
+		}
+		s.buildErr = c.hugo().Build(hugolib.BuildCfg{SkipRender: true})
+	} else {
+		s.buildErr = c.hugo().Build(hugolib.BuildCfg{SkipRender: true}, events...)
+	}
+
+	if s.buildErr != nil {
+		return newRPCError(rpcBuildError, s.buildErr)
+	}
+
+	return nil
+}
+
+func (s *rpcServer) absPath(filename string) string {
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(s.c.Cfg.GetString("workingDir"), filename)
+	}
+	return filepath.Clean(filename)
+}
+
+func (s *rpcServer) readMessage() ([]byte, error) {
+	length := -1
+	for {
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length == -1 {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("failed to read header: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid header %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+
+	if length < 0 {
+		return nil, errors.New("missing Content-Length header")
+	}
+
+	b := make([]byte, length)
+	if _, err := io.ReadFull(s.r, b); err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	return b, nil
+}
+
+func (s *rpcServer) writeResponse(resp rpcResponse) error {
+	resp.JSONRPC = "2.0"
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n", len(b)); err != nil {
+		return err
+	}
+	_, err = s.w.Write(b)
+	return err
+}
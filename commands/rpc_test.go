@@ -0,0 +1,89 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRPC(t *testing.T) {
+	c := qt.New(t)
+	dir := createSimpleTestSite(t, testSiteConfig{})
+	writeFile(t, filepath.Join(dir, "config", "_default", "frontmatter.toml"), `
+[schemas.page]
+title = { type = "string", required = true }
+`)
+	writeFile(t, filepath.Join(dir, "layouts", "shortcodes", "note.html"), `{{/* Renders a note. */}}<aside>{{ .Inner }}</aside>`)
+
+	var in bytes.Buffer
+	write := func(v string) {
+		fmt.Fprintf(&in, "Content-Length: %d\r\n\r\n%s", len(v), v)
+	}
+	write(`{"jsonrpc":"2.0","id":1,"method":"render","params":{"path":"content/p1.md"}}`)
+	write(`{"jsonrpc":"2.0","id":2,"method":"shortcode","params":{"name":"note"}}`)
+	write(`{"jsonrpc":"2.0","id":3,"method":"validateFrontMatter","params":{"path":"content/p3.md","content":"---\ndraft: true\n---\n"}}`)
+	write(`{"jsonrpc":"2.0","id":4,"method":"templates"}`)
+	write(`{"jsonrpc":"2.0","method":"changed","params":{"files":["content/p1.md"]}}`)
+	write(`{"jsonrpc":"2.0","id":5,"method":"nope"}`)
+	write(`{"jsonrpc":"2.0","id":6,"method":"render","params":{"path":"/nope"}}`)
+	write(`{invalid`)
+	write(`{"jsonrpc":"2.0","id":7,"method":"shutdown"}`)
+	write(`{"jsonrpc":"2.0","method":"exit"}`)
+	write(`{"jsonrpc":"2.0","id":8,"method":"templates"}`)
+
+	hugoCmd := newCommandsBuilder().addAll().build()
+	cmd := hugoCmd.getCommand()
+	var out bytes.Buffer
+	cmd.SetIn(&in)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"--rpc", "-s=" + dir})
+	_, err := cmd.ExecuteC()
+	c.Assert(err, qt.IsNil)
+
+	var responses []rpcResponse
+	r := &rpcServer{r: bufio.NewReader(&out)}
+	for {
+		b, err := r.readMessage()
+		if err != nil {
+			break
+		}
+		var resp rpcResponse
+		c.Assert(json.Unmarshal(b, &resp), qt.IsNil)
+		responses = append(responses, resp)
+	}
+
+	c.Assert(responses, qt.HasLen, 8)
+
+	var rendered rpcRenderResult
+	c.Assert(json.Unmarshal(responses[0].Result, &rendered), qt.IsNil)
+	c.Assert(rendered.Filename, qt.Equals, "/p1/index.html")
+	c.Assert(rendered.Content, qt.Contains, "Single: P1")
+
+	c.Assert(string(responses[1].Result), qt.Contains, `"doc":"Renders a note."`)
+	c.Assert(string(responses[2].Result), qt.Equals, `[{"key":"title","missing":true,"message":"front matter field \"title\" is required"}]`)
+	c.Assert(string(responses[3].Result), qt.Contains, `"name":"shortcodes/note.html"`)
+
+	c.Assert(responses[4].Error.Code, qt.Equals, rpcMethodNotFound)
+	c.Assert(responses[5].Error.Message, qt.Equals, `page "/nope" not found`)
+	c.Assert(responses[6].Error.Code, qt.Equals, rpcParseError)
+	c.Assert(string(responses[7].ID), qt.Equals, "7")
+	c.Assert(string(responses[7].Result), qt.Equals, "null")
+}
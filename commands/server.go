@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"mime"
 	"net"
 	"net/http"
 	"net/url"
@@ -53,6 +54,10 @@ type serverCmd struct {
 	stop chan bool
 
 	disableLiveReload  bool
+	enableGraphQL      bool
+	changeEvents       bool
+	adminAPI           bool
+	adminAPIToken      string
 	navigateToChanged  bool
 	renderToDisk       bool
 	renderStaticToDisk bool
@@ -60,8 +65,16 @@ type serverCmd struct {
 	serverInterface    string
 	serverPort         int
 	liveReloadPort     int
+	metricsPort        int
 	serverWatch        bool
 	noHTTPCache        bool
+	noHTTPCacheFor     []string
+	throttle           string
+	throttleFor        []string
+
+	noHTTPCacheMatcher requestMatcher
+	throttleMatcher    requestMatcher
+	throttleCfg        throttle
 
 	disableFastRender   bool
 	disableBrowserError bool
@@ -103,11 +116,19 @@ of a second, you will be able to save and see your changes nearly instantly.`,
 
 	cc.cmd.Flags().IntVarP(&cc.serverPort, "port", "p", 1313, "port on which the server will listen")
 	cc.cmd.Flags().IntVar(&cc.liveReloadPort, "liveReloadPort", -1, "port for live reloading (i.e. 443 in HTTPS proxy situations)")
+	cc.cmd.Flags().IntVar(&cc.metricsPort, "metricsPort", 0, "serve Prometheus metrics at /metrics on this port (default is disabled)")
 	cc.cmd.Flags().StringVarP(&cc.serverInterface, "bind", "", "127.0.0.1", "interface to which the server will bind")
 	cc.cmd.Flags().BoolVarP(&cc.serverWatch, "watch", "w", true, "watch filesystem for changes and recreate as needed")
 	cc.cmd.Flags().BoolVar(&cc.noHTTPCache, "noHTTPCache", false, "prevent HTTP caching")
+	cc.cmd.Flags().StringSliceVar(&cc.noHTTPCacheFor, "noHTTPCacheFor", nil, "prevent HTTP caching of the requests matching these globs, e.g. /fonts/**")
+	cc.cmd.Flags().StringVar(&cc.throttle, "throttle", "", "simulate a slow network, one of slow-3g, fast-3g or 4g, or a bandwidth and latency, e.g. 500kbps,300ms")
+	cc.cmd.Flags().StringSliceVar(&cc.throttleFor, "throttleFor", nil, "only throttle the requests matching these globs, e.g. /images/** (default is all)")
 	cc.cmd.Flags().BoolVarP(&cc.serverAppend, "appendPort", "", true, "append port to baseURL")
 	cc.cmd.Flags().BoolVar(&cc.disableLiveReload, "disableLiveReload", false, "watch without enabling live browser reload on rebuild")
+	cc.cmd.Flags().BoolVar(&cc.enableGraphQL, "enableGraphQL", false, "serve a GraphQL endpoint at /__graphql to query the pages, taxonomies and menus")
+	cc.cmd.Flags().BoolVar(&cc.changeEvents, "changeEvents", false, "stream the rebuilds with the changed pages as server-sent events at /__changes")
+	cc.cmd.Flags().BoolVar(&cc.adminAPI, "adminAPI", false, "serve an API at /__admin/ to list pages, edit front matter, create content and rebuild")
+	cc.cmd.Flags().StringVar(&cc.adminAPIToken, "adminAPIToken", "", "the bearer token required by the admin API (default is a random token printed on start)")
 	cc.cmd.Flags().BoolVar(&cc.navigateToChanged, "navigateToChanged", false, "navigate to changed content file on live browser reload")
 	cc.cmd.Flags().BoolVar(&cc.renderToDisk, "renderToDisk", false, "serve all files from disk (default is from memory)")
 	cc.cmd.Flags().BoolVar(&cc.renderStaticToDisk, "renderStaticToDisk", false, "serve static files from disk and dynamic files from memory")
@@ -147,6 +168,17 @@ func (sc *serverCmd) server(cmd *cobra.Command, args []string) error {
 		sc.renderToDisk = true
 	}
 
+	var err error
+	if sc.throttleCfg, err = parseThrottle(sc.throttle); err != nil {
+		return newUserError(err)
+	}
+	if sc.throttleMatcher, err = newRequestMatcher("throttleFor", sc.throttleFor); err != nil {
+		return newUserError(err)
+	}
+	if sc.noHTTPCacheMatcher, err = newRequestMatcher("noHTTPCacheFor", sc.noHTTPCacheFor); err != nil {
+		return newUserError(err)
+	}
+
 	var serverCfgInit sync.Once
 
 	cfgInit := func(c *commandeer) (rerr error) {
@@ -158,6 +190,21 @@ func (sc *serverCmd) server(cmd *cobra.Command, args []string) error {
 		if cmd.Flags().Changed("disableLiveReload") {
 			c.Set("disableLiveReload", sc.disableLiveReload)
 		}
+		if cmd.Flags().Changed("enableGraphQL") {
+			c.Set("enableGraphQL", sc.enableGraphQL)
+		}
+		if cmd.Flags().Changed("changeEvents") {
+			c.Set("changeEvents", sc.changeEvents)
+		}
+		if cmd.Flags().Changed("metricsPort") {
+			c.Set("metricsPort", sc.metricsPort)
+		}
+		if cmd.Flags().Changed("adminAPI") {
+			c.Set("adminAPI", sc.adminAPI)
+		}
+		if cmd.Flags().Changed("adminAPIToken") {
+			c.Set("adminAPIToken", sc.adminAPIToken)
+		}
 		if cmd.Flags().Changed("disableFastRender") {
 			c.Set("disableFastRender", sc.disableFastRender)
 		}
@@ -256,7 +303,7 @@ func (sc *serverCmd) server(cmd *cobra.Command, args []string) error {
 
 	c, err := initializeConfig(true, true, true, &sc.hugoBuilderCommon, sc, cfgInit)
 	if err != nil {
-		cmd.PrintErrln("Error:", err.Error())
+		sc.printErr(cmd, err)
 		return err
 	}
 
@@ -264,7 +311,7 @@ func (sc *serverCmd) server(cmd *cobra.Command, args []string) error {
 		defer c.timeTrack(time.Now(), "Built")
 		err := c.serverBuild()
 		if err != nil {
-			cmd.PrintErrln("Error:", err.Error())
+			sc.printErr(cmd, err)
 		}
 		return err
 	}()
@@ -316,6 +363,7 @@ type fileServer struct {
 	errorTemplate func(err any) (io.Reader, error)
 	c             *commandeer
 	s             *serverCmd
+	deployFiles   deployFiles
 }
 
 func (f *fileServer) rewriteRequest(r *http.Request, toPath string) *http.Request {
@@ -329,6 +377,47 @@ func (f *fileServer) rewriteRequest(r *http.Request, toPath string) *http.Reques
 	return r2
 }
 
+// serveFileWithStatus writes the file at name in fs with the given status,
+// which is how Netlify serves e.g. a custom 404 page for a section.
+func serveFileWithStatus(w http.ResponseWriter, fs http.FileSystem, name string, status int) {
+	if !strings.HasPrefix(name, "/") {
+		name = "/" + name
+	}
+	if strings.HasSuffix(name, "/") {
+		name += "index.html"
+	}
+
+	file, err := fs.Open(name)
+	if err != nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	defer file.Close()
+
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	w.WriteHeader(status)
+	io.Copy(w, file)
+}
+
+// publishedFileExists returns whether requestURI, relative to u,
+// maps to a file in the publish dir.
+func (f *fileServer) publishedFileExists(u *url.URL, requestURI string) bool {
+	path := filepath.Clean(strings.TrimPrefix(requestURI, u.Path))
+	fi, err := f.c.hugo().BaseFs.PublishFs.Stat(path)
+	if err != nil {
+		return false
+	}
+	if fi.IsDir() {
+		// There will be overlapping directories, so we
+		// need to check for a file.
+		_, err = f.c.hugo().BaseFs.PublishFs.Stat(filepath.Join(path, "index.html"))
+		return err == nil
+	}
+	return true
+}
+
 func (f *fileServer) createEndpoint(i int) (*http.ServeMux, net.Listener, string, string, error) {
 	baseURL := f.baseURLs[i]
 	root := f.roots[i]
@@ -367,6 +456,10 @@ func (f *fileServer) createEndpoint(i int) (*http.ServeMux, net.Listener, string
 		jww.FEEDBACK.Println("Running in Fast Render Mode. For full rebuilds on change: hugo server --disableFastRender")
 	}
 
+	if i == 0 && !f.s.throttleCfg.isZero() {
+		jww.FEEDBACK.Printf("Throttling responses to %s", f.s.throttleCfg)
+	}
+
 	// We're only interested in the path
 	u, err := url.Parse(baseURL)
 	if err != nil {
@@ -398,35 +491,57 @@ func (f *fileServer) createEndpoint(i int) (*http.ServeMux, net.Listener, string
 				}
 			}
 
-			if f.s.noHTTPCache {
-				w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate, max-age=0")
-				w.Header().Set("Pragma", "no-cache")
-			}
-
 			// Ignore any query params for the operations below.
 			requestURI := strings.TrimSuffix(r.RequestURI, "?"+r.URL.RawQuery)
 
+			if f.s.noHTTPCache || (len(f.s.noHTTPCacheMatcher) > 0 && f.s.noHTTPCacheMatcher.Match(requestURI)) {
+				setNoHTTPCache(w, r)
+			}
+
+			var deployRules config.DeployRules
+			if !f.c.serverConfig.DisableDeployFiles {
+				deployRules = f.deployFiles.rules(f.c.publishDirServerFs, root, f.c.logger.Warnf)
+			}
+
+			for _, header := range deployRules.MatchHeaders(requestURI) {
+				w.Header().Set(header.Key, header.Value)
+			}
+
 			for _, header := range f.c.serverConfig.MatchHeaders(requestURI) {
 				w.Header().Set(header.Key, header.Value)
 			}
 
+			rule, to, found := f.c.hugo().MatchRedirect(root, requestURI, r.URL.RawQuery)
+			if !found {
+				rule, to, found = deployRules.MatchRedirect(requestURI, r.URL.RawQuery)
+			}
+			if found {
+				if rule.Force || !f.publishedFileExists(u, requestURI) {
+					if rule.IsRewrite() {
+						// The request's query is kept as is.
+						to, _, _ = strings.Cut(to, "?")
+						if r2 := f.rewriteRequest(r, strings.TrimPrefix(to, u.Path)); r2 != nil {
+							requestURI = to
+							r = r2
+						}
+					} else if rule.IsErrorPage() {
+						to, _, _ = strings.Cut(to, "?")
+						serveFileWithStatus(w, fs, strings.TrimPrefix(to, u.Path), rule.Status)
+						return
+					} else {
+						w.Header().Set("Content-Type", "")
+						http.Redirect(w, r, to, rule.Status)
+						return
+					}
+				}
+			}
+
 			if redirect := f.c.serverConfig.MatchRedirect(requestURI); !redirect.IsZero() {
 				doRedirect := true
 				// This matches Netlify's behaviour and is needed for SPA behaviour.
 				// See https://docs.netlify.com/routing/redirects/rewrites-proxies/
 				if !redirect.Force {
-					path := filepath.Clean(strings.TrimPrefix(requestURI, u.Path))
-					fi, err := f.c.hugo().BaseFs.PublishFs.Stat(path)
-					if err == nil {
-						if fi.IsDir() {
-							// There will be overlapping directories, so we
-							// need to check for a file.
-							_, err = f.c.hugo().BaseFs.PublishFs.Stat(filepath.Join(path, "index.html"))
-							doRedirect = err != nil
-						} else {
-							doRedirect = false
-						}
-					}
+					doRedirect = !f.publishedFileExists(u, requestURI)
 				}
 
 				if doRedirect {
@@ -445,10 +560,11 @@ func (f *fileServer) createEndpoint(i int) (*http.ServeMux, net.Listener, string
 			}
 
 			if f.c.fastRenderMode && f.c.buildErr == nil {
-				if strings.HasSuffix(requestURI, "/") || strings.HasSuffix(requestURI, "html") || strings.HasSuffix(requestURI, "htm") {
-					if !f.c.visitedURLs.Contains(requestURI) {
-						// If not already on stack, re-render that single page.
-						if err := f.c.partialReRender(requestURI); err != nil {
+				if h := f.c.hugoTry(); h != nil {
+					if pageURL, stale := h.StaleURL(requestURI); stale {
+						// The page was left out of a rebuild it may depend on,
+						// re-render that single page.
+						if err := f.c.partialReRender(pageURL); err != nil {
 							f.c.handleBuildErr(err, fmt.Sprintf("Failed to render %q", requestURI))
 							if f.c.showErrorInBrowser {
 								http.Redirect(w, r, requestURI, http.StatusMovedPermanently)
@@ -456,9 +572,18 @@ func (f *fileServer) createEndpoint(i int) (*http.ServeMux, net.Listener, string
 							}
 						}
 					}
+				}
 
+				if strings.HasSuffix(requestURI, "/") || strings.HasSuffix(requestURI, "html") || strings.HasSuffix(requestURI, "htm") {
 					f.c.visitedURLs.Add(requestURI)
+				}
+			}
 
+			if !f.s.throttleCfg.isZero() && f.s.throttleMatcher.Match(requestURI) {
+				var ok bool
+				if w, ok = f.s.throttleCfg.wrap(w, r); !ok {
+					// The request was canceled.
+					return
 				}
 			}
 
@@ -573,6 +698,18 @@ func (c *commandeer) serve(s *serverCmd) error {
 		livereload.Initialize()
 	}
 
+	var adminAPIToken string
+	if c.Cfg.GetBool("adminAPI") {
+		adminAPIToken = c.Cfg.GetString("adminAPIToken")
+		if adminAPIToken == "" {
+			var err error
+			if adminAPIToken, err = newAdminAPIToken(); err != nil {
+				return err
+			}
+			jww.FEEDBACK.Printf("Admin API token: %s\n", adminAPIToken)
+		}
+	}
+
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 	var servers []*http.Server
@@ -587,15 +724,30 @@ func (c *commandeer) serve(s *serverCmd) error {
 		}
 		servers = append(servers, srv)
 
-		if doLiveReload {
-			u, err := url.Parse(helpers.SanitizeURL(baseURLs[i]))
-			if err != nil {
-				return err
-			}
+		u, err := url.Parse(helpers.SanitizeURL(baseURLs[i]))
+		if err != nil {
+			return err
+		}
 
+		if doLiveReload {
 			mu.HandleFunc(u.Path+"/livereload.js", livereload.ServeJS)
 			mu.HandleFunc(u.Path+"/livereload", livereload.Handler)
 		}
+
+		mu.HandleFunc(u.Path+rebuildsPath, c.rebuildsHandler)
+
+		if c.Cfg.GetBool("enableGraphQL") {
+			mu.HandleFunc(u.Path+"/__graphql", c.graphQLHandler)
+		}
+
+		if c.Cfg.GetBool("changeEvents") {
+			mu.HandleFunc(u.Path+changesPath, c.changesHandler)
+		}
+
+		if adminAPIToken != "" {
+			prefix := u.Path + adminAPIPath
+			mu.Handle(prefix, c.adminAPIHandler(prefix, adminAPIToken))
+		}
 		jww.FEEDBACK.Printf("Web Server is available at %s (bind address %s)\n", serverURL, s.serverInterface)
 		wg1.Go(func() error {
 			err = srv.Serve(listener)
@@ -606,6 +758,28 @@ func (c *commandeer) serve(s *serverCmd) error {
 		})
 	}
 
+	if port := c.Cfg.GetInt("metricsPort"); port > 0 {
+		mu := http.NewServeMux()
+		mu.HandleFunc(metricsPath, c.metricsHandler)
+		srv := &http.Server{
+			Addr:    net.JoinHostPort(s.serverInterface, strconv.Itoa(port)),
+			Handler: mu,
+		}
+		servers = append(servers, srv)
+		listener, err := net.Listen("tcp", srv.Addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on the metrics port: %w", err)
+		}
+		jww.FEEDBACK.Printf("Metrics are available at http://%s%s\n", srv.Addr, metricsPath)
+		wg1.Go(func() error {
+			err := srv.Serve(listener)
+			if err != nil && err != http.ErrServerClosed {
+				return err
+			}
+			return nil
+		})
+	}
+
 	jww.FEEDBACK.Println("Press Ctrl+C to stop")
 
 	err := func() error {
@@ -636,6 +810,8 @@ func (c *commandeer) serve(s *serverCmd) error {
 		jww.ERROR.Println("Error:", err)
 	}
 
+	c.changeBroker.close()
+
 	if h := c.hugoTry(); h != nil {
 		h.Close()
 	}
@@ -0,0 +1,361 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/create"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/parser"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/gohugoio/hugo/parser/pageparser"
+	"github.com/gohugoio/hugo/resources/page"
+)
+
+const adminAPIPath = "/__admin/"
+
+// errAdminNotFound is returned when a content file does not exist.
+var errAdminNotFound = errors.New("file not found")
+
+// newAdminAPIToken creates a random token to use if none is configured.
+func newAdminAPIToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// adminAPIHandler returns the handler of the admin API mounted at prefix,
+// which requires the token as a bearer token in the Authorization header.
+//
+// The API provides:
+//
+//	GET    pages               list the pages, filtered by lang, kind and section
+//	GET    frontmatter?file=   read the front matter of a content file
+//	PUT    frontmatter?file=   replace the front matter of a content file
+//	PATCH  frontmatter?file=   merge into the front matter, null removes a key
+//	POST   content             create content as with hugo new, e.g. {"path": "posts/my-post.md"}
+//	POST   rebuild             force a full rebuild
+//
+// The file is relative to the content dir, e.g. posts/my-post.md.
+func (c *commandeer) adminAPIHandler(prefix, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="hugo"`)
+			writeAdminError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+			return
+		}
+
+		var (
+			v   any
+			err error
+		)
+
+		switch endpoint := strings.TrimPrefix(r.URL.Path, prefix); endpoint {
+		case "pages":
+			if !checkAdminMethod(w, r, http.MethodGet) {
+				return
+			}
+			v, err = c.adminListPages(r)
+		case "frontmatter":
+			if !checkAdminMethod(w, r, http.MethodGet, http.MethodPut, http.MethodPatch) {
+				return
+			}
+			v, err = c.adminFrontMatter(r)
+		case "content":
+			if !checkAdminMethod(w, r, http.MethodPost) {
+				return
+			}
+			v, err = c.adminNewContent(r)
+			if err == nil {
+				w.WriteHeader(http.StatusCreated)
+			}
+		case "rebuild":
+			if !checkAdminMethod(w, r, http.MethodPost) {
+				return
+			}
+			c.fullRebuild("")
+			w.WriteHeader(http.StatusAccepted)
+			v = map[string]string{"status": "rebuilding"}
+		default:
+			writeAdminError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %q", endpoint))
+			return
+		}
+
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, errAdminNotFound) {
+				status = http.StatusNotFound
+			}
+			writeAdminError(w, status, err)
+			return
+		}
+
+		writeAdminJSON(w, v)
+	})
+}
+
+func checkAdminMethod(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+func writeAdminJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeAdminError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+type adminPage struct {
+	Title        string    `json:"title"`
+	Kind         string    `json:"kind"`
+	Lang         string    `json:"lang"`
+	Section      string    `json:"section"`
+	File         string    `json:"file,omitempty"`
+	RelPermalink string    `json:"relPermalink"`
+	Draft        bool      `json:"draft"`
+	Date         time.Time `json:"date"`
+}
+
+func (c *commandeer) adminListPages(r *http.Request) ([]adminPage, error) {
+	h := c.hugoTry()
+	if h == nil {
+		return nil, errors.New("site not built yet")
+	}
+
+	if c.buildLock != nil {
+		unlock, err := c.buildLock()
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	q := r.URL.Query()
+	lang, kind, section := q.Get("lang"), q.Get("kind"), q.Get("section")
+
+	pages := []adminPage{}
+	for _, p := range h.Pages() {
+		if (lang != "" && p.Lang() != lang) || (kind != "" && p.Kind() != kind) || (section != "" && p.Section() != section) {
+			continue
+		}
+		pages = append(pages, newAdminPage(p))
+	}
+
+	return pages, nil
+}
+
+func newAdminPage(p page.Page) adminPage {
+	ap := adminPage{
+		Title:        p.Title(),
+		Kind:         p.Kind(),
+		Lang:         p.Lang(),
+		Section:      p.Section(),
+		RelPermalink: p.RelPermalink(),
+		Draft:        p.Draft(),
+		Date:         p.Date(),
+	}
+	if !p.File().IsZero() {
+		ap.File = filepath.ToSlash(p.File().Path())
+	}
+	return ap
+}
+
+type adminFrontMatter struct {
+	File        string         `json:"file"`
+	Format      string         `json:"format"`
+	FrontMatter map[string]any `json:"frontMatter"`
+}
+
+func (c *commandeer) adminFrontMatter(r *http.Request) (*adminFrontMatter, error) {
+	h := c.hugoTry()
+	if h == nil {
+		return nil, errors.New("site not built yet")
+	}
+
+	file := r.URL.Query().Get("file")
+	filename, err := adminContentFilename(h, file)
+	if err != nil {
+		return nil, err
+	}
+
+	fs := h.Fs.Source
+	f, err := fs.Open(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%q: %w", file, errAdminNotFound)
+		}
+		return nil, err
+	}
+	pf, err := pageparser.ParseFrontMatterAndContent(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", file, err)
+	}
+
+	if pf.FrontMatterFormat == "" {
+		pf.FrontMatterFormat = metadecoders.YAML
+	}
+	if pf.FrontMatter == nil {
+		pf.FrontMatter = make(map[string]any)
+	}
+
+	if r.Method != http.MethodGet {
+		var in map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			return nil, fmt.Errorf("invalid front matter: %w", err)
+		}
+
+		if r.Method == http.MethodPut {
+			pf.FrontMatter = make(map[string]any)
+		}
+		for k, v := range in {
+			if v == nil {
+				delete(pf.FrontMatter, k)
+				continue
+			}
+			pf.FrontMatter[k] = fromJSONValue(v)
+		}
+
+		var b bytes.Buffer
+		if len(pf.FrontMatter) > 0 {
+			if err := parser.InterfaceToFrontMatter(pf.FrontMatter, pf.FrontMatterFormat, &b); err != nil {
+				return nil, err
+			}
+		}
+		b.Write(pf.Content)
+
+		if err := helpers.WriteToDisk(filename, &b, fs); err != nil {
+			return nil, fmt.Errorf("failed to write %q: %w", file, err)
+		}
+	}
+
+	return &adminFrontMatter{
+		File:        file,
+		Format:      string(pf.FrontMatterFormat),
+		FrontMatter: pf.FrontMatter,
+	}, nil
+}
+
+// fromJSONValue converts whole numbers, decoded as float64, to int, so
+// they're written as e.g. weight = 10 and not weight = 10.0.
+func fromJSONValue(v any) any {
+	switch vv := v.(type) {
+	case float64:
+		if vv == math.Trunc(vv) && math.Abs(vv) < 1<<53 {
+			return int(vv)
+		}
+	case []any:
+		for i, v := range vv {
+			vv[i] = fromJSONValue(v)
+		}
+	case map[string]any:
+		for k, v := range vv {
+			vv[k] = fromJSONValue(v)
+		}
+	}
+	return v
+}
+
+// adminContentFilename returns the filename of file in the first content
+// dir it exists in, else in the first content dir.
+func adminContentFilename(h *hugolib.HugoSites, file string) (string, error) {
+	if file == "" {
+		return "", errors.New("missing file")
+	}
+
+	clean := path.Clean("/" + filepath.ToSlash(file))[1:]
+	if clean == "" || clean != strings.TrimPrefix(filepath.ToSlash(file), "/") {
+		return "", fmt.Errorf("invalid file %q", file)
+	}
+
+	dirs := h.BaseFs.Content.Dirs
+	if len(dirs) == 0 {
+		return "", errors.New("no content directory configured")
+	}
+
+	for _, dir := range dirs {
+		filename := filepath.Join(dir.Meta().Filename, filepath.FromSlash(clean))
+		if _, err := h.Fs.Source.Stat(filename); err == nil {
+			return filename, nil
+		}
+	}
+
+	return filepath.Join(dirs[0].Meta().Filename, filepath.FromSlash(clean)), nil
+}
+
+type adminNewContentRequest struct {
+	// The path relative to the content dir, e.g. posts/my-post.md.
+	Path string `json:"path"`
+
+	// The archetype to use, defaults to the section.
+	Kind string `json:"kind"`
+}
+
+func (c *commandeer) adminNewContent(r *http.Request) (map[string]string, error) {
+	h := c.hugoTry()
+	if h == nil {
+		return nil, errors.New("site not built yet")
+	}
+
+	var req adminNewContentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	filename, err := adminContentFilename(h, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.Fs.Source.Stat(filename); err == nil {
+		return nil, fmt.Errorf("%q already exists", req.Path)
+	}
+
+	if err := create.NewContent(h, req.Kind, filepath.FromSlash(req.Path)); err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"file": req.Path}, nil
+}
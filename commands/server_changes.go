@@ -0,0 +1,149 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+const changesPath = "/__changes"
+
+// The interval between the keep-alive comments sent to the clients.
+const changesKeepAliveInterval = 15 * time.Second
+
+// changeBroker broadcasts the rebuilds to the clients of the change events
+// endpoint.
+type changeBroker struct {
+	mu      sync.Mutex
+	clients map[chan []byte]bool
+
+	// Closed when the server shuts down.
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (b *changeBroker) doneChan() chan struct{} {
+	if b.done == nil {
+		b.done = make(chan struct{})
+	}
+	return b.done
+}
+
+// subscribe returns a channel receiving the events and a channel closed on
+// shutdown.
+func (b *changeBroker) subscribe() (chan []byte, chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.clients == nil {
+		b.clients = make(map[chan []byte]bool)
+	}
+	ch := make(chan []byte, 10)
+	b.clients[ch] = true
+	return ch, b.doneChan()
+}
+
+// close ends the event streams so the server can shut down.
+func (b *changeBroker) close() {
+	b.mu.Lock()
+	done := b.doneChan()
+	b.mu.Unlock()
+	b.closeOnce.Do(func() {
+		close(done)
+	})
+}
+
+func (b *changeBroker) unsubscribe(ch chan []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.clients, ch)
+}
+
+// publish sends r to all clients. The pages rendered because all pages were
+// rendered are left out, clients should refresh all pages if r.Full is set
+// or r.Reasons has hugolib.RenderReasonAll.
+func (b *changeBroker) publish(r rebuild) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.clients) == 0 {
+		return
+	}
+
+	var pages []hugolib.RenderedPage
+	for _, p := range r.Pages {
+		if p.Reason != hugolib.RenderReasonAll {
+			pages = append(pages, p)
+		}
+	}
+	r.Pages = pages
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+
+	for ch := range b.clients {
+		select {
+		case ch <- data:
+		default:
+			// The client is too slow, drop the event.
+		}
+	}
+}
+
+// changesHandler streams the rebuilds as server-sent events of type
+// rebuild, with the same data as the rebuilds endpoint.
+func (c *commandeer) changesHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkAdminMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAdminError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	ch, done := c.changeBroker.subscribe()
+	defer c.changeBroker.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(changesKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-done:
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case data := <-ch:
+			fmt.Fprintf(w, "event: rebuild\ndata: %s\n\n", data)
+		}
+		flusher.Flush()
+	}
+}
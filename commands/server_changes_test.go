@@ -0,0 +1,76 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestChangesHandler(t *testing.T) {
+	c := qt.New(t)
+
+	cm := &commandeer{}
+	srv := httptest.NewServer(http.HandlerFunc(cm.changesHandler))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	c.Assert(err, qt.IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.Header.Get("Content-Type"), qt.Equals, "text/event-stream")
+
+	r := bufio.NewReader(resp.Body)
+	line, err := r.ReadString('\n')
+	c.Assert(err, qt.IsNil)
+	c.Assert(line, qt.Equals, ": connected\n")
+
+	cm.changeBroker.publish(rebuild{
+		Time:    time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC),
+		Trigger: "content, templates or assets",
+		Files:   []rebuildFile{{Path: "content/p1.md", Op: "WRITE"}},
+		Pages: []hugolib.RenderedPage{
+			{Path: "/", Lang: "en", Reason: hugolib.RenderReasonAll},
+			{Path: "/p1/", File: "p1.md", Lang: "en", Reason: hugolib.RenderReasonChanged},
+		},
+	})
+
+	var event []string
+	for {
+		line, err := r.ReadString('\n')
+		c.Assert(err, qt.IsNil)
+		if line == "\n" {
+			if len(event) > 0 {
+				break
+			}
+			continue
+		}
+		event = append(event, strings.TrimSuffix(line, "\n"))
+	}
+
+	c.Assert(event, qt.DeepEquals, []string{
+		"event: rebuild",
+		`data: {"time":"2022-05-01T10:00:00Z","duration":"","trigger":"content, templates or assets","full":false,"files":[{"path":"content/p1.md","op":"WRITE"}],"pages":[{"path":"/p1/","file":"p1.md","lang":"en","reason":"content file changed"}]}`,
+	})
+
+	cm.changeBroker.close()
+	_, err = r.ReadString('\n')
+	c.Assert(err, qt.IsNotNil)
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/spf13/afero"
+)
+
+// deployFiles holds the headers and redirects read from the hosting
+// provider files, e.g. _headers and _redirects, in the publish dir,
+// re-read when any of them changes.
+type deployFiles struct {
+	mu      sync.Mutex
+	stamps  map[string]string
+	entries map[string]config.DeployRules
+}
+
+// rules returns the rules for the files in root in fs.
+func (d *deployFiles) rules(fs afero.Fs, root string, logf func(format string, v ...any)) config.DeployRules {
+	var stamp strings.Builder
+	for _, name := range config.DefaultDeployFiles {
+		if fi, err := fs.Stat(filepath.Join(root, name)); err == nil && !fi.IsDir() {
+			fmt.Fprintf(&stamp, "%s:%d:%d;", name, fi.ModTime().UnixNano(), fi.Size())
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stamps == nil {
+		d.stamps = make(map[string]string)
+		d.entries = make(map[string]config.DeployRules)
+	}
+
+	if s, found := d.stamps[root]; found && s == stamp.String() {
+		return d.entries[root]
+	}
+
+	var rules config.DeployRules
+	for _, name := range config.DefaultDeployFiles {
+		filename := filepath.Join(root, name)
+		f, err := fs.Open(filename)
+		if err != nil {
+			continue
+		}
+		r, err := config.ParseDeployFile(filename, f)
+		f.Close()
+		if err != nil {
+			logf("%s", err)
+			continue
+		}
+		rules.Merge(r)
+	}
+
+	d.stamps[root] = stamp.String()
+	d.entries[root] = rules
+
+	return rules
+}
@@ -0,0 +1,437 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/gohugoio/hugo/common/graphql"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/navigation"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/spf13/cast"
+)
+
+// graphQLHandler serves GraphQL queries against the sites currently in
+// memory, e.g.:
+//
+//	{ site(lang: "en") { pages(kind: "page", first: 10) { title relPermalink } } }
+//
+// Queries are accepted with GET (query, operationName and variables as
+// JSON in the URL) and with POST (a JSON body or application/graphql).
+func (c *commandeer) graphQLHandler(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				http.Error(w, "invalid variables: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
+		if r.Header.Get("Content-Type") == "application/graphql" {
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			req.Query = string(b)
+		} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if req.Query == "" {
+		http.Error(w, "missing query", http.StatusBadRequest)
+		return
+	}
+
+	h := c.hugoTry()
+	if h == nil {
+		http.Error(w, "site not built yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	// Make sure we don't read the sites while they're being rebuilt.
+	if c.buildLock != nil {
+		unlock, err := c.buildLock()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer unlock()
+	}
+
+	resp := graphql.Execute(graphQLQuery{h: h}, req)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(resp)
+}
+
+type graphQLQuery struct {
+	h *hugolib.HugoSites
+}
+
+func (q graphQLQuery) TypeName() string { return "Query" }
+
+func (q graphQLQuery) Field(name string, args graphql.Args) (any, error) {
+	switch name {
+	case "site":
+		lang := args.String("lang")
+		for _, s := range q.h.Sites {
+			if lang == "" || s.Language().Lang == lang {
+				return graphQLSite{s: s}, nil
+			}
+		}
+		return nil, nil
+	case "sites":
+		sites := make([]graphQLSite, len(q.h.Sites))
+		for i, s := range q.h.Sites {
+			sites[i] = graphQLSite{s: s}
+		}
+		return sites, nil
+	}
+	return nil, graphql.ErrUnknownField{Name: name}
+}
+
+type graphQLSite struct {
+	s *hugolib.Site
+}
+
+func (s graphQLSite) TypeName() string { return "Site" }
+
+func (s graphQLSite) Field(name string, args graphql.Args) (any, error) {
+	info := s.s.Info
+	switch name {
+	case "title":
+		return info.Title(), nil
+	case "language":
+		return s.s.Language().Lang, nil
+	case "baseURL":
+		return string(info.BaseURL()), nil
+	case "params":
+		return info.Params(), nil
+	case "home":
+		return newGraphQLPage(info.Home()), nil
+	case "page":
+		p, err := info.GetPage(args.String("path"))
+		if err != nil {
+			return nil, err
+		}
+		return newGraphQLPage(p), nil
+	case "pages":
+		pages := info.Pages()
+		if kind := args.String("kind"); kind != "" {
+			pages = filterGraphQLPages(pages, func(p page.Page) bool { return p.Kind() == kind })
+		}
+		if section := args.String("section"); section != "" {
+			pages = filterGraphQLPages(pages, func(p page.Page) bool { return p.Section() == section })
+		}
+		if typ := args.String("type"); typ != "" {
+			pages = filterGraphQLPages(pages, func(p page.Page) bool { return p.Type() == typ })
+		}
+		return graphQLPages(pages, args), nil
+	case "taxonomies":
+		taxonomies := s.s.Taxonomies()
+		names := make([]string, 0, len(taxonomies))
+		for name := range taxonomies {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result := make([]graphQLTaxonomy, len(names))
+		for i, name := range names {
+			result[i] = graphQLTaxonomy{name: name, t: taxonomies[name]}
+		}
+		return result, nil
+	case "taxonomy":
+		name := args.String("name")
+		t, found := s.s.Taxonomies()[name]
+		if !found {
+			return nil, nil
+		}
+		return graphQLTaxonomy{name: name, t: t}, nil
+	case "menus":
+		menus := s.s.Menus()
+		names := make([]string, 0, len(menus))
+		for name := range menus {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result := make([]graphQLMenu, len(names))
+		for i, name := range names {
+			result[i] = graphQLMenu{name: name, m: menus[name]}
+		}
+		return result, nil
+	case "menu":
+		name := args.String("name")
+		m, found := s.s.Menus()[name]
+		if !found {
+			return nil, nil
+		}
+		return graphQLMenu{name: name, m: m}, nil
+	}
+	return nil, graphql.ErrUnknownField{Name: name}
+}
+
+type graphQLPage struct {
+	p page.Page
+}
+
+func newGraphQLPage(p page.Page) any {
+	if p == nil || p == page.NilPage {
+		return nil
+	}
+	return graphQLPage{p: p}
+}
+
+// graphQLPages applies the first argument to pages.
+func graphQLPages(pages page.Pages, args graphql.Args) []graphQLPage {
+	if first := args.Int("first", -1); first >= 0 && first < len(pages) {
+		pages = pages[:first]
+	}
+	result := make([]graphQLPage, len(pages))
+	for i, p := range pages {
+		result[i] = graphQLPage{p: p}
+	}
+	return result
+}
+
+func filterGraphQLPages(pages page.Pages, keep func(p page.Page) bool) page.Pages {
+	var filtered page.Pages
+	for _, p := range pages {
+		if keep(p) {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+func (p graphQLPage) TypeName() string { return "Page" }
+
+func (p graphQLPage) Field(name string, args graphql.Args) (any, error) {
+	switch name {
+	case "title":
+		return p.p.Title(), nil
+	case "linkTitle":
+		return p.p.LinkTitle(), nil
+	case "kind":
+		return p.p.Kind(), nil
+	case "type":
+		return p.p.Type(), nil
+	case "section":
+		return p.p.Section(), nil
+	case "path":
+		return p.p.Path(), nil
+	case "file":
+		if p.p.File().IsZero() {
+			return nil, nil
+		}
+		return p.p.File().Filename(), nil
+	case "relPermalink":
+		return p.p.RelPermalink(), nil
+	case "permalink":
+		return p.p.Permalink(), nil
+	case "date":
+		return p.p.Date(), nil
+	case "lastmod":
+		return p.p.Lastmod(), nil
+	case "draft":
+		return p.p.Draft(), nil
+	case "weight":
+		return p.p.Weight(), nil
+	case "lang":
+		return p.p.Lang(), nil
+	case "params":
+		return p.p.Params(), nil
+	case "summary":
+		return p.p.Summary(), nil
+	case "content":
+		c, err := p.p.Content()
+		if err != nil {
+			return nil, err
+		}
+		return cast.ToStringE(c)
+	case "plain":
+		return p.p.Plain(), nil
+	case "rawContent":
+		return p.p.RawContent(), nil
+	case "wordCount":
+		return p.p.WordCount(), nil
+	case "parent":
+		return newGraphQLPage(p.p.Parent()), nil
+	case "pages":
+		return graphQLPages(p.p.Pages(), args), nil
+	case "regularPages":
+		return graphQLPages(p.p.RegularPages(), args), nil
+	case "sections":
+		return graphQLPages(p.p.Sections(), args), nil
+	case "translations":
+		return graphQLPages(p.p.Translations(), args), nil
+	case "terms":
+		return graphQLPages(p.p.GetTerms(args.String("taxonomy")), args), nil
+	case "resources":
+		resources := p.p.Resources()
+		result := make([]graphQLResource, len(resources))
+		for i, r := range resources {
+			result[i] = graphQLResource{r: r}
+		}
+		return result, nil
+	}
+	return nil, graphql.ErrUnknownField{Name: name}
+}
+
+type graphQLResource struct {
+	r resource.Resource
+}
+
+func (r graphQLResource) TypeName() string { return "Resource" }
+
+func (r graphQLResource) Field(name string, args graphql.Args) (any, error) {
+	switch name {
+	case "name":
+		return r.r.Name(), nil
+	case "title":
+		return r.r.Title(), nil
+	case "resourceType":
+		return r.r.ResourceType(), nil
+	case "mediaType":
+		return r.r.MediaType().Type(), nil
+	case "relPermalink":
+		return r.r.RelPermalink(), nil
+	case "permalink":
+		return r.r.Permalink(), nil
+	case "params":
+		return r.r.Params(), nil
+	}
+	return nil, graphql.ErrUnknownField{Name: name}
+}
+
+type graphQLTaxonomy struct {
+	name string
+	t    hugolib.Taxonomy
+}
+
+func (t graphQLTaxonomy) TypeName() string { return "Taxonomy" }
+
+func (t graphQLTaxonomy) Field(name string, args graphql.Args) (any, error) {
+	switch name {
+	case "name":
+		return t.name, nil
+	case "terms":
+		entries := t.t.Alphabetical()
+		result := make([]graphQLTerm, len(entries))
+		for i, e := range entries {
+			result[i] = graphQLTerm{e: e}
+		}
+		return result, nil
+	}
+	return nil, graphql.ErrUnknownField{Name: name}
+}
+
+type graphQLTerm struct {
+	e hugolib.OrderedTaxonomyEntry
+}
+
+func (t graphQLTerm) TypeName() string { return "Term" }
+
+func (t graphQLTerm) Field(name string, args graphql.Args) (any, error) {
+	switch name {
+	case "name":
+		return t.e.Name, nil
+	case "count":
+		return t.e.Count(), nil
+	case "page":
+		if len(t.e.WeightedPages) == 0 {
+			return nil, nil
+		}
+		return newGraphQLPage(t.e.WeightedPages.Page()), nil
+	case "pages":
+		return graphQLPages(t.e.Pages(), args), nil
+	}
+	return nil, graphql.ErrUnknownField{Name: name}
+}
+
+type graphQLMenu struct {
+	name string
+	m    navigation.Menu
+}
+
+func (m graphQLMenu) TypeName() string { return "Menu" }
+
+func (m graphQLMenu) Field(name string, args graphql.Args) (any, error) {
+	switch name {
+	case "name":
+		return m.name, nil
+	case "entries":
+		return newGraphQLMenuEntries(m.m), nil
+	}
+	return nil, graphql.ErrUnknownField{Name: name}
+}
+
+func newGraphQLMenuEntries(m navigation.Menu) []graphQLMenuEntry {
+	result := make([]graphQLMenuEntry, len(m))
+	for i, e := range m {
+		result[i] = graphQLMenuEntry{e: e}
+	}
+	return result
+}
+
+type graphQLMenuEntry struct {
+	e *navigation.MenuEntry
+}
+
+func (e graphQLMenuEntry) TypeName() string { return "MenuEntry" }
+
+func (e graphQLMenuEntry) Field(name string, args graphql.Args) (any, error) {
+	switch name {
+	case "identifier":
+		return e.e.Identifier, nil
+	case "name":
+		return e.e.Name, nil
+	case "title":
+		return e.e.Title(), nil
+	case "url":
+		return e.e.URL(), nil
+	case "weight":
+		return e.e.Weight, nil
+	case "params":
+		return e.e.Params, nil
+	case "page":
+		if p, ok := e.e.Page.(page.Page); ok {
+			return newGraphQLPage(p), nil
+		}
+		return nil, nil
+	case "children":
+		return newGraphQLMenuEntries(e.e.Children), nil
+	}
+	return nil, graphql.ErrUnknownField{Name: name}
+}
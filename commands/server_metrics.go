@@ -0,0 +1,167 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const metricsPath = "/metrics"
+
+// The upper bounds in seconds of the rebuild duration histogram buckets.
+var rebuildDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+var watcherOps = []fsnotify.Op{fsnotify.Create, fsnotify.Write, fsnotify.Remove, fsnotify.Rename, fsnotify.Chmod}
+
+type rebuildKey struct {
+	full   bool
+	failed bool
+}
+
+// serverMetrics collects the metrics of the server served in the
+// Prometheus text format.
+type serverMetrics struct {
+	mu sync.Mutex
+
+	rebuilds map[rebuildKey]uint64
+
+	// The cumulative counts of the rebuild duration buckets, the +Inf
+	// bucket last.
+	durationBuckets []uint64
+	durationSum     float64
+
+	watcherEvents map[string]uint64
+}
+
+func (m *serverMetrics) observeRebuild(full bool, d time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.rebuilds == nil {
+		m.rebuilds = make(map[rebuildKey]uint64)
+		m.durationBuckets = make([]uint64, len(rebuildDurationBuckets)+1)
+	}
+	m.rebuilds[rebuildKey{full: full, failed: err != nil}]++
+
+	seconds := d.Seconds()
+	m.durationSum += seconds
+	for i, le := range rebuildDurationBuckets {
+		if seconds <= le {
+			m.durationBuckets[i]++
+		}
+	}
+	m.durationBuckets[len(rebuildDurationBuckets)]++
+}
+
+func (m *serverMetrics) addWatcherEvents(evs []fsnotify.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.watcherEvents == nil {
+		m.watcherEvents = make(map[string]uint64)
+	}
+	for _, ev := range evs {
+		for _, op := range watcherOps {
+			if ev.Op&op == op {
+				m.watcherEvents[strings.ToLower(op.String())]++
+			}
+		}
+	}
+}
+
+// write writes the metrics to w, with the partialCached cache hits and
+// misses of the current sites.
+func (m *serverMetrics) write(w io.Writer, partialCacheHits, partialCacheMisses uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metric := func(name, typ, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+	}
+
+	metric("hugo_rebuilds_total", "counter", "The number of rebuilds.")
+	for _, full := range []bool{false, true} {
+		for _, failed := range []bool{false, true} {
+			fmt.Fprintf(w, "hugo_rebuilds_total{full=%q,failed=%q} %d\n", strconv.FormatBool(full), strconv.FormatBool(failed), m.rebuilds[rebuildKey{full: full, failed: failed}])
+		}
+	}
+
+	metric("hugo_rebuild_duration_seconds", "histogram", "The duration of the rebuilds.")
+	var count uint64
+	for i, le := range rebuildDurationBuckets {
+		var n uint64
+		if m.durationBuckets != nil {
+			n = m.durationBuckets[i]
+		}
+		fmt.Fprintf(w, "hugo_rebuild_duration_seconds_bucket{le=%q} %d\n", strconv.FormatFloat(le, 'g', -1, 64), n)
+	}
+	if m.durationBuckets != nil {
+		count = m.durationBuckets[len(rebuildDurationBuckets)]
+	}
+	fmt.Fprintf(w, "hugo_rebuild_duration_seconds_bucket{le=\"+Inf\"} %d\n", count)
+	fmt.Fprintf(w, "hugo_rebuild_duration_seconds_sum %s\n", strconv.FormatFloat(m.durationSum, 'g', -1, 64))
+	fmt.Fprintf(w, "hugo_rebuild_duration_seconds_count %d\n", count)
+
+	metric("hugo_watcher_events_total", "counter", "The number of file system events by operation.")
+	ops := make([]string, 0, len(m.watcherEvents))
+	for op := range m.watcherEvents {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		fmt.Fprintf(w, "hugo_watcher_events_total{op=%q} %d\n", op, m.watcherEvents[op])
+	}
+
+	metric("hugo_partial_cache_hits_total", "counter", "The number of partialCached calls served from the cache since the sites were created.")
+	fmt.Fprintf(w, "hugo_partial_cache_hits_total %d\n", partialCacheHits)
+	metric("hugo_partial_cache_misses_total", "counter", "The number of partialCached calls that executed the partial since the sites were created.")
+	fmt.Fprintf(w, "hugo_partial_cache_misses_total %d\n", partialCacheMisses)
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	metric("hugo_memory_heap_alloc_bytes", "gauge", "The bytes of allocated heap objects.")
+	fmt.Fprintf(w, "hugo_memory_heap_alloc_bytes %d\n", ms.HeapAlloc)
+	metric("hugo_memory_sys_bytes", "gauge", "The bytes of memory obtained from the OS.")
+	fmt.Fprintf(w, "hugo_memory_sys_bytes %d\n", ms.Sys)
+	metric("hugo_gc_cycles_total", "counter", "The number of completed GC cycles.")
+	fmt.Fprintf(w, "hugo_gc_cycles_total %d\n", ms.NumGC)
+	metric("hugo_goroutines", "gauge", "The number of goroutines.")
+	fmt.Fprintf(w, "hugo_goroutines %d\n", runtime.NumGoroutine())
+}
+
+// metricsHandler serves the metrics in the Prometheus text format.
+func (c *commandeer) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkAdminMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	var hits, misses uint64
+	if h := c.hugoTry(); h != nil {
+		hits, misses = h.BuildState.PartialCacheStats()
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.serverMetrics.write(w, hits, misses)
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+const rebuildsPath = "/__rebuilds"
+
+// The number of rebuilds to keep.
+const rebuildLogSize = 20
+
+// rebuild describes a rebuild triggered by file changes in the server.
+type rebuild struct {
+	Time     time.Time `json:"time"`
+	Duration string    `json:"duration"`
+
+	// What changed, e.g. "config file", "static files" or "content and templates".
+	Trigger string `json:"trigger"`
+
+	// Whether the config was reloaded and all sites rebuilt from scratch.
+	Full bool `json:"full"`
+
+	// The changed files, relative to the working dir.
+	Files []rebuildFile `json:"files,omitempty"`
+
+	// The number of pages rendered by reason.
+	Reasons map[string]int `json:"reasons,omitempty"`
+
+	// The pages rendered.
+	Pages []hugolib.RenderedPage `json:"pages,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+type rebuildFile struct {
+	Path string `json:"path"`
+	Op   string `json:"op"`
+}
+
+// rebuildLog holds the most recent rebuilds.
+type rebuildLog struct {
+	mu       sync.Mutex
+	rebuilds []rebuild
+}
+
+func (l *rebuildLog) add(r rebuild) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rebuilds = append(l.rebuilds, r)
+	if len(l.rebuilds) > rebuildLogSize {
+		l.rebuilds = l.rebuilds[len(l.rebuilds)-rebuildLogSize:]
+	}
+}
+
+// list returns the rebuilds, most recent first.
+func (l *rebuildLog) list() []rebuild {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rebuilds := make([]rebuild, len(l.rebuilds))
+	for i, r := range l.rebuilds {
+		rebuilds[len(l.rebuilds)-1-i] = r
+	}
+	return rebuilds
+}
+
+// renderedPages returns the pages rendered in the last build.
+func (c *commandeer) renderedPages() []hugolib.RenderedPage {
+	if h := c.hugoTry(); h != nil {
+		return h.RenderedPages()
+	}
+	return nil
+}
+
+// recordRebuild adds a rebuild that started at start to the rebuild log
+// and logs it with --verbose.
+func (c *commandeer) recordRebuild(start time.Time, trigger string, full bool, evs []fsnotify.Event, pages []hugolib.RenderedPage, err error) {
+	r := rebuild{
+		Time:     start,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+		Trigger:  trigger,
+		Full:     full,
+		Pages:    pages,
+	}
+
+	workingDir := c.Cfg.GetString("workingDir")
+	for _, ev := range evs {
+		name := ev.Name
+		if rel, err := filepath.Rel(workingDir, name); err == nil && !strings.HasPrefix(rel, "..") {
+			name = rel
+		}
+		r.Files = append(r.Files, rebuildFile{Path: filepath.ToSlash(name), Op: ev.Op.String()})
+	}
+
+	if len(r.Pages) > 0 {
+		r.Reasons = make(map[string]int)
+		for _, p := range r.Pages {
+			r.Reasons[p.Reason]++
+		}
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	c.rebuildLog.add(r)
+	c.serverMetrics.observeRebuild(full, time.Since(start), err)
+	c.changeBroker.publish(r)
+
+	c.logger.Infof("Rebuild triggered by %s (full: %t) in %s", r.Trigger, r.Full, r.Duration)
+	for _, f := range r.Files {
+		c.logger.Infof("  changed: %s (%s)", f.Path, f.Op)
+	}
+	reasons := make([]string, 0, len(r.Reasons))
+	for reason := range r.Reasons {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		c.logger.Infof("  rendered %d pages: %s", r.Reasons[reason], reason)
+	}
+	for _, p := range r.Pages {
+		if p.Reason != hugolib.RenderReasonAll {
+			c.logger.Infof("  rendered: %s (%s)", p.Path, p.Reason)
+		}
+	}
+}
+
+// rebuildsHandler serves the most recent rebuilds as JSON, most recent
+// first. Set pages=false to leave out the rendered pages.
+func (c *commandeer) rebuildsHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkAdminMethod(w, r, http.MethodGet) {
+		return
+	}
+	rebuilds := c.rebuildLog.list()
+	if r.URL.Query().Get("pages") == "false" {
+		for i := range rebuilds {
+			rebuilds[i].Pages = nil
+		}
+	}
+	writeAdminJSON(w, rebuilds)
+}
@@ -19,6 +19,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -70,18 +71,31 @@ func TestServerFlags(t *testing.T) {
 		{"--renderStaticToDisk", func(c *qt.C, r serverTestResult) {
 			assertPublic(c, r, true)
 		}},
+		{"--enableGraphQL", func(c *qt.C, r serverTestResult) {
+			assertPublic(c, r, false)
+			c.Assert(r.graphQL, qt.Contains, `"title": "P1"`)
+			c.Assert(r.graphQL, qt.Contains, `"relPermalink": "/p1/"`)
+		}},
+		{"--adminAPI --adminAPIToken secret", func(c *qt.C, r serverTestResult) {
+			assertPublic(c, r, false)
+			c.Assert(r.adminAPI, qt.Contains, `"file": "p1.md"`)
+			c.Assert(r.adminAPI, qt.Contains, `"relPermalink": "/p1/"`)
+			c.Assert(r.adminAPIUnauthorized, qt.Equals, http.StatusUnauthorized)
+		}},
+		{"--metricsPort port", func(c *qt.C, r serverTestResult) {
+			assertPublic(c, r, false)
+			c.Assert(r.metrics, qt.Contains, "# TYPE hugo_rebuilds_total counter\nhugo_rebuilds_total{full=\"false\",failed=\"false\"} 0\n")
+			c.Assert(r.metrics, qt.Contains, "hugo_rebuild_duration_seconds_count 0\n")
+			c.Assert(r.metrics, qt.Contains, "hugo_partial_cache_misses_total 0\n")
+			c.Assert(r.metrics, qt.Contains, "# TYPE hugo_memory_heap_alloc_bytes gauge")
+		}},
 	} {
 		c.Run(test.flag, func(c *qt.C) {
 			config := `
 baseURL="https://example.org"
 `
 
-			var args []string
-			if test.flag != "" {
-				args = strings.Split(test.flag, "=")
-			}
-
-			r := runServerTest(c, 1, config, args...)
+			r := runServerTest(c, 1, config, strings.Fields(test.flag)...)
 
 			test.assert(c, r)
 
@@ -149,14 +163,66 @@ baseURL="https://example.org"
 
 }
 
+func TestServerErrorPages(t *testing.T) {
+	c := qt.New(t)
+
+	config := `
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term"]
+[errorPages]
+sections = true
+formats = ["netlify"]
+`
+
+	files := map[string]string{
+		"content/docs/_index.md": "---\ntitle: Docs\n---\n",
+		"content/docs/d1.md":     "---\ntitle: D1\n---\n",
+		"layouts/404.html":       "Not found: {{ .Params.section }}|",
+	}
+
+	r := runServerTestWithFiles(c, 0, config, files, []string{"/docs/d1/", "/docs/missing/", "/missing/"})
+	c.Assert(r.err, qt.IsNil)
+
+	c.Assert(r.responses["/docs/d1/"].status, qt.Equals, http.StatusOK)
+	c.Assert(r.responses["/docs/d1/"].body, qt.Contains, "Single: D1")
+
+	c.Assert(r.responses["/docs/missing/"].status, qt.Equals, http.StatusNotFound)
+	c.Assert(r.responses["/docs/missing/"].body, qt.Contains, "Not found: docs|")
+
+	c.Assert(r.responses["/missing/"].status, qt.Equals, http.StatusNotFound)
+}
+
 type serverTestResult struct {
 	err            error
 	homesContent   []string
 	publicDirnames map[string]bool
+	graphQL        string
+
+	adminAPI             string
+	adminAPIUnauthorized int
+
+	metrics string
+
+	// The responses to the paths requested, by path.
+	responses map[string]serverTestResponse
+}
+
+type serverTestResponse struct {
+	status int
+	body   string
 }
 
 func runServerTest(c *qt.C, getNumHomes int, config string, args ...string) (result serverTestResult) {
+	return runServerTestWithFiles(c, getNumHomes, config, nil, nil, args...)
+}
+
+// runServerTestWithFiles runs the server for the simple test site with the
+// given files added, and requests paths on the first server.
+func runServerTestWithFiles(c *qt.C, getNumHomes int, config string, files map[string]string, paths []string, args ...string) (result serverTestResult) {
 	dir := createSimpleTestSite(c, testSiteConfig{configTOML: config})
+	for filename, content := range files {
+		writeFile(c, filepath.Join(dir, filepath.FromSlash(filename)), content)
+	}
 
 	sp, err := helpers.FindAvailablePort()
 	c.Assert(err, qt.IsNil)
@@ -166,6 +232,17 @@ func runServerTest(c *qt.C, getNumHomes int, config string, args ...string) (res
 		os.RemoveAll(dir)
 	}()
 
+	// The metrics port placeholder is replaced with an available port.
+	var metricsPort int
+	for i, arg := range args {
+		if arg == "--metricsPort" && i+1 < len(args) {
+			mp, err := helpers.FindAvailablePort()
+			c.Assert(err, qt.IsNil)
+			metricsPort = mp.Port
+			args[i+1] = strconv.Itoa(metricsPort)
+		}
+	}
+
 	stop := make(chan bool)
 
 	b := newCommandsBuilder()
@@ -202,6 +279,60 @@ func runServerTest(c *qt.C, getNumHomes int, config string, args ...string) (res
 		}
 	}
 
+	for _, arg := range args {
+		if arg == "--enableGraphQL" {
+			resp, err := http.Post(fmt.Sprintf("http://localhost:%d/__graphql", port), "application/json", strings.NewReader(`{"query": "{ site { pages(kind: \"page\") { title relPermalink } } }"}`))
+			c.Check(err, qt.IsNil)
+			if err == nil {
+				defer resp.Body.Close()
+				result.graphQL = helpers.ReaderToString(resp.Body)
+			}
+		}
+		if arg == "--adminAPI" {
+			adminURL := fmt.Sprintf("http://localhost:%d/__admin/pages?kind=page", port)
+			resp, err := http.Get(adminURL)
+			c.Check(err, qt.IsNil)
+			if err == nil {
+				resp.Body.Close()
+				result.adminAPIUnauthorized = resp.StatusCode
+			}
+			req, _ := http.NewRequest("GET", adminURL, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			resp, err = http.DefaultClient.Do(req)
+			c.Check(err, qt.IsNil)
+			if err == nil {
+				defer resp.Body.Close()
+				result.adminAPI = helpers.ReaderToString(resp.Body)
+			}
+		}
+	}
+
+	if len(paths) > 0 {
+		if getNumHomes == 0 {
+			time.Sleep(567 * time.Millisecond)
+		}
+		result.responses = make(map[string]serverTestResponse)
+		for _, p := range paths {
+			func() {
+				resp, err := http.Get(fmt.Sprintf("http://localhost:%d%s", port, p))
+				c.Check(err, qt.IsNil)
+				if err == nil {
+					defer resp.Body.Close()
+					result.responses[p] = serverTestResponse{status: resp.StatusCode, body: helpers.ReaderToString(resp.Body)}
+				}
+			}()
+		}
+	}
+
+	if metricsPort != 0 {
+		resp, err := http.Get(fmt.Sprintf("http://localhost:%d/metrics", metricsPort))
+		c.Check(err, qt.IsNil)
+		if err == nil {
+			defer resp.Body.Close()
+			result.metrics = helpers.ReaderToString(resp.Body)
+		}
+	}
+
 	time.Sleep(1 * time.Second)
 
 	select {
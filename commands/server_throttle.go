@@ -0,0 +1,198 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+)
+
+// The network profiles that can be passed to --throttle, similar to the
+// ones in the browsers' developer tools.
+var throttleProfiles = map[string]throttle{
+	"slow-3g": {bandwidth: 400 * 1000 / 8, latency: 2000 * time.Millisecond},
+	"fast-3g": {bandwidth: 1600 * 1000 / 8, latency: 560 * time.Millisecond},
+	"4g":      {bandwidth: 9000 * 1000 / 8, latency: 170 * time.Millisecond},
+}
+
+// throttle simulates a slow network for the server responses.
+type throttle struct {
+	// Bytes per second, 0 for no limit.
+	bandwidth int
+
+	// The delay before the response is sent.
+	latency time.Duration
+}
+
+func (t throttle) isZero() bool {
+	return t.bandwidth == 0 && t.latency == 0
+}
+
+func (t throttle) String() string {
+	bandwidth := "unlimited bandwidth"
+	if t.bandwidth > 0 {
+		bandwidth = fmt.Sprintf("%d kbit/s", t.bandwidth*8/1000)
+	}
+	return fmt.Sprintf("%s and %s latency", bandwidth, t.latency)
+}
+
+// parseThrottle parses the --throttle flag, a profile name, e.g. slow-3g, or
+// a bandwidth in kbit/s and a latency, e.g. 500kbps,300ms. Either part can be
+// left out.
+func parseThrottle(s string) (throttle, error) {
+	var t throttle
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return t, nil
+	}
+	if p, found := throttleProfiles[s]; found {
+		return p, nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if kbps := strings.TrimSuffix(part, "kbps"); kbps != part {
+			n, err := strconv.ParseFloat(kbps, 64)
+			if err != nil || n <= 0 {
+				return t, fmt.Errorf("invalid --throttle bandwidth %q", part)
+			}
+			t.bandwidth = int(n * 1000 / 8)
+			continue
+		}
+		d, err := time.ParseDuration(part)
+		if err != nil || d < 0 {
+			return t, fmt.Errorf("invalid --throttle %q: must be one of slow-3g, fast-3g or 4g, or a bandwidth and latency, e.g. 500kbps,300ms", s)
+		}
+		t.latency = d
+	}
+
+	return t, nil
+}
+
+// requestMatcher matches the request paths against the globs passed to
+// --throttleFor and --noHTTPCacheFor.
+type requestMatcher []glob.Glob
+
+func newRequestMatcher(flag string, patterns []string) (requestMatcher, error) {
+	var m requestMatcher
+	for _, p := range patterns {
+		if !strings.HasPrefix(p, "/") {
+			p = "/" + p
+		}
+		g, err := glob.Compile(p, '/')
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s pattern %q: %w", flag, p, err)
+		}
+		m = append(m, g)
+	}
+	return m, nil
+}
+
+// Match reports whether the request URI matches any of the globs. All
+// requests match if there are none.
+func (m requestMatcher) Match(requestURI string) bool {
+	if len(m) == 0 {
+		return true
+	}
+	for _, g := range m {
+		if g.Match(requestURI) {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap delays the request by the latency and returns a ResponseWriter that
+// writes at most the bandwidth per second.
+func (t throttle) wrap(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, bool) {
+	if !sleep(r.Context(), t.latency) {
+		return w, false
+	}
+	if t.bandwidth == 0 {
+		return w, true
+	}
+	return &throttledResponseWriter{ResponseWriter: w, ctx: r.Context(), bandwidth: t.bandwidth}, true
+}
+
+// The number of chunks sent per second.
+const throttleChunksPerSecond = 10
+
+type throttledResponseWriter struct {
+	http.ResponseWriter
+	ctx       context.Context
+	bandwidth int
+}
+
+func (w *throttledResponseWriter) Write(b []byte) (int, error) {
+	chunkSize := w.bandwidth / throttleChunksPerSecond
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+
+	var written int
+	for len(b) > 0 {
+		n := chunkSize
+		if n > len(b) {
+			n = len(b)
+		}
+		m, err := w.ResponseWriter.Write(b[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+		w.Flush()
+		if !sleep(w.ctx, time.Duration(n)*time.Second/time.Duration(w.bandwidth)) {
+			return written, w.ctx.Err()
+		}
+	}
+
+	return written, nil
+}
+
+func (w *throttledResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// sleep sleeps for d, returning false if ctx is done before.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// setNoHTTPCache sets the headers that prevent HTTP caching and removes the
+// conditional request headers, so the full response is always sent.
+func setNoHTTPCache(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store, no-cache, must-revalidate, max-age=0")
+	w.Header().Set("Pragma", "no-cache")
+	r.Header.Del("If-Modified-Since")
+	r.Header.Del("If-None-Match")
+}
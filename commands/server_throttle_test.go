@@ -0,0 +1,102 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestParseThrottle(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		in     string
+		expect throttle
+	}{
+		{"", throttle{}},
+		{"slow-3g", throttleProfiles["slow-3g"]},
+		{"Fast-3G", throttleProfiles["fast-3g"]},
+		{"500kbps,300ms", throttle{bandwidth: 62500, latency: 300 * time.Millisecond}},
+		{"800kbps", throttle{bandwidth: 100000}},
+		{"1s", throttle{latency: time.Second}},
+	} {
+		got, err := parseThrottle(test.in)
+		c.Assert(err, qt.IsNil, qt.Commentf(test.in))
+		c.Assert(got, qt.Equals, test.expect, qt.Commentf(test.in))
+	}
+
+	for _, in := range []string{"5g", "0kbps", "fast,100ms"} {
+		_, err := parseThrottle(in)
+		c.Assert(err, qt.IsNotNil, qt.Commentf(in))
+	}
+
+	c.Assert(throttle{bandwidth: 62500, latency: 300 * time.Millisecond}.String(), qt.Equals, "500 kbit/s and 300ms latency")
+}
+
+func TestRequestMatcher(t *testing.T) {
+	c := qt.New(t)
+
+	m, err := newRequestMatcher("throttleFor", []string{"/images/**", "**.woff2"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(m.Match("/images/a/b.jpg"), qt.IsTrue)
+	c.Assert(m.Match("/fonts/inter.woff2"), qt.IsTrue)
+	c.Assert(m.Match("/posts/"), qt.IsFalse)
+
+	var all requestMatcher
+	c.Assert(all.Match("/posts/"), qt.IsTrue)
+
+	_, err = newRequestMatcher("throttleFor", []string{"/images/[a"})
+	c.Assert(err, qt.ErrorMatches, `invalid --throttleFor pattern.*`)
+}
+
+func TestThrottle(t *testing.T) {
+	c := qt.New(t)
+
+	th := throttle{bandwidth: 1000, latency: 50 * time.Millisecond}
+	r := httptest.NewRequest("GET", "/", nil)
+	rec := httptest.NewRecorder()
+
+	start := time.Now()
+	w, ok := th.wrap(rec, r)
+	c.Assert(ok, qt.IsTrue)
+	n, err := w.Write([]byte(strings.Repeat("a", 300)))
+	c.Assert(err, qt.IsNil)
+	c.Assert(n, qt.Equals, 300)
+	c.Assert(rec.Body.Len(), qt.Equals, 300)
+	c.Assert(time.Since(start) >= 300*time.Millisecond, qt.IsTrue)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok = th.wrap(httptest.NewRecorder(), r.WithContext(ctx))
+	c.Assert(ok, qt.IsFalse)
+}
+
+func TestSetNoHTTPCache(t *testing.T) {
+	c := qt.New(t)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("If-None-Match", `"abc"`)
+	rec := httptest.NewRecorder()
+
+	setNoHTTPCache(rec, r)
+
+	c.Assert(rec.Header().Get("Cache-Control"), qt.Equals, "no-store, no-cache, must-revalidate, max-age=0")
+	c.Assert(r.Header.Get("If-None-Match"), qt.Equals, "")
+}
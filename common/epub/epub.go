@@ -0,0 +1,273 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package epub writes EPUB 3 publications.
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+const mimetype = "application/epub+zip"
+
+// Book is an EPUB publication.
+type Book struct {
+	// A unique identifier, e.g. the URL of the book.
+	Identifier string
+
+	Title       string
+	Language    string
+	Description string
+	Authors     []string
+
+	// The last modification of the content.
+	Modified time.Time
+
+	// The cover image, optional.
+	Cover *File
+
+	// The chapters in reading order.
+	Chapters []Chapter
+
+	// Additional files referenced from the chapters, e.g. images.
+	Files []File
+}
+
+// Chapter is an XHTML content document in a Book.
+type Chapter struct {
+	// The file name, e.g. "chapter001.xhtml".
+	Name string
+
+	Title string
+
+	// The XHTML content of the body element.
+	Body string
+}
+
+// File is a file in a Book.
+type File struct {
+	// The file name relative to the chapters, e.g. "images/cover.jpg".
+	Name string
+
+	MediaType string
+	Content   []byte
+}
+
+type manifestItem struct {
+	id, href, mediaType, properties string
+}
+
+// Write writes b as an EPUB to w.
+func (b Book) Write(w io.Writer) error {
+	if b.Title == "" {
+		return errors.New("epub: book has no title")
+	}
+	if len(b.Chapters) == 0 {
+		return errors.New("epub: book has no chapters")
+	}
+
+	language := b.Language
+	if language == "" {
+		language = "en"
+	}
+	if b.Modified.IsZero() {
+		b.Modified = time.Now()
+	}
+
+	var (
+		items []manifestItem
+		spine []string
+		names = make(map[string]bool)
+		files = make(map[string][]byte)
+	)
+
+	addFile := func(id, name, mediaType, properties string, content []byte) error {
+		if name == "" || path.IsAbs(name) || strings.HasPrefix(path.Clean(name), "..") {
+			return fmt.Errorf("epub: invalid file name %q", name)
+		}
+		if names[name] {
+			return fmt.Errorf("epub: duplicate file %q", name)
+		}
+		names[name] = true
+		files[name] = content
+		items = append(items, manifestItem{id: id, href: name, mediaType: mediaType, properties: properties})
+		return nil
+	}
+
+	var nav bytes.Buffer
+	nav.WriteString(`<nav epub:type="toc" id="toc"><h1>`)
+	xml.EscapeText(&nav, []byte(b.Title))
+	nav.WriteString("</h1><ol>")
+
+	if b.Cover != nil {
+		if err := addFile("cover-image", b.Cover.Name, b.Cover.MediaType, "cover-image", b.Cover.Content); err != nil {
+			return err
+		}
+		var body bytes.Buffer
+		body.WriteString(`<div class="cover"><img src="`)
+		xml.EscapeText(&body, []byte(b.Cover.Name))
+		body.WriteString(`" alt="`)
+		xml.EscapeText(&body, []byte(b.Title))
+		body.WriteString(`"/></div>`)
+		if err := addFile("cover", "cover.xhtml", "application/xhtml+xml", "", xhtmlDocument(language, b.Title, body.String())); err != nil {
+			return err
+		}
+		spine = append(spine, "cover")
+	}
+
+	for i, c := range b.Chapters {
+		id := fmt.Sprintf("chapter%03d", i+1)
+		if err := addFile(id, c.Name, "application/xhtml+xml", "", xhtmlDocument(language, c.Title, c.Body)); err != nil {
+			return err
+		}
+		spine = append(spine, id)
+
+		nav.WriteString(`<li><a href="`)
+		xml.EscapeText(&nav, []byte(c.Name))
+		nav.WriteString(`">`)
+		xml.EscapeText(&nav, []byte(c.Title))
+		nav.WriteString("</a></li>")
+	}
+	nav.WriteString("</ol></nav>")
+
+	for i, f := range b.Files {
+		if err := addFile(fmt.Sprintf("file%03d", i+1), f.Name, f.MediaType, "", f.Content); err != nil {
+			return err
+		}
+	}
+
+	if err := addFile("nav", "nav.xhtml", "application/xhtml+xml", "nav", xhtmlDocument(language, b.Title, nav.String())); err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+
+	// The mimetype must be the first file, stored uncompressed and
+	// without extra fields.
+	fw, err := zw.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(mimetype)),
+		CompressedSize64:   uint64(len(mimetype)),
+		UncompressedSize64: uint64(len(mimetype)),
+		Modified:           b.Modified,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(fw, mimetype); err != nil {
+		return err
+	}
+
+	write := func(name string, content []byte) error {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: b.Modified,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(content)
+		return err
+	}
+
+	if err := write("META-INF/container.xml", []byte(containerXML)); err != nil {
+		return err
+	}
+	if err := write("EPUB/package.opf", b.packageDocument(language, items, spine)); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := write("EPUB/"+item.href, files[item.href]); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="EPUB/package.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+func (b Book) packageDocument(language string, items []manifestItem, spine []string) []byte {
+	var buf bytes.Buffer
+	text := func(s string) string {
+		var b bytes.Buffer
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="pub-id" xml:lang="` + text(language) + `">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+`)
+	fmt.Fprintf(&buf, "    <dc:identifier id=\"pub-id\">%s</dc:identifier>\n", text(b.Identifier))
+	fmt.Fprintf(&buf, "    <dc:title>%s</dc:title>\n", text(b.Title))
+	fmt.Fprintf(&buf, "    <dc:language>%s</dc:language>\n", text(language))
+	for _, author := range b.Authors {
+		fmt.Fprintf(&buf, "    <dc:creator>%s</dc:creator>\n", text(author))
+	}
+	if b.Description != "" {
+		fmt.Fprintf(&buf, "    <dc:description>%s</dc:description>\n", text(b.Description))
+	}
+	fmt.Fprintf(&buf, "    <meta property=\"dcterms:modified\">%s</meta>\n", b.Modified.UTC().Format("2006-01-02T15:04:05Z"))
+	if b.Cover != nil {
+		buf.WriteString("    <meta name=\"cover\" content=\"cover-image\"/>\n")
+	}
+	buf.WriteString("  </metadata>\n  <manifest>\n")
+	for _, item := range items {
+		fmt.Fprintf(&buf, "    <item id=\"%s\" href=\"%s\" media-type=\"%s\"", item.id, text(item.href), text(item.mediaType))
+		if item.properties != "" {
+			fmt.Fprintf(&buf, " properties=\"%s\"", item.properties)
+		}
+		buf.WriteString("/>\n")
+	}
+	buf.WriteString("  </manifest>\n  <spine>\n")
+	for _, id := range spine {
+		fmt.Fprintf(&buf, "    <itemref idref=\"%s\"/>\n", id)
+	}
+	buf.WriteString("  </spine>\n</package>\n")
+
+	return buf.Bytes()
+}
+
+func xhtmlDocument(language, title, body string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="`)
+	xml.EscapeText(&buf, []byte(language))
+	buf.WriteString(`" lang="`)
+	xml.EscapeText(&buf, []byte(language))
+	buf.WriteString("\">\n<head>\n<meta charset=\"UTF-8\"/>\n<title>")
+	xml.EscapeText(&buf, []byte(title))
+	buf.WriteString("</title>\n</head>\n<body>\n")
+	buf.WriteString(body)
+	buf.WriteString("\n</body>\n</html>\n")
+	return buf.Bytes()
+}
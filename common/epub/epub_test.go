@@ -0,0 +1,111 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestBookWrite(t *testing.T) {
+	c := qt.New(t)
+
+	b := Book{
+		Identifier: "https://example.org/book/",
+		Title:      "The Book & Co",
+		Language:   "en",
+		Authors:    []string{"Jane Doe"},
+		Modified:   time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC),
+		Cover:      &File{Name: "images/cover.png", MediaType: "image/png", Content: []byte("PNG")},
+		Chapters: []Chapter{
+			{Name: "chapter001.xhtml", Title: "One", Body: `<h1>One</h1><p><a href="chapter002.xhtml#intro">Next</a></p>`},
+			{Name: "chapter002.xhtml", Title: "Two", Body: `<h1 id="intro">Two</h1><p><img src="images/p.png" alt=""/></p>`},
+		},
+		Files: []File{{Name: "images/p.png", MediaType: "image/png", Content: []byte("P")}},
+	}
+
+	var buf bytes.Buffer
+	c.Assert(b.Write(&buf), qt.IsNil)
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	c.Assert(err, qt.IsNil)
+
+	first := zr.File[0]
+	c.Assert(first.Name, qt.Equals, "mimetype")
+	c.Assert(first.Method, qt.Equals, zip.Store)
+	c.Assert(first.Extra, qt.HasLen, 0)
+
+	files := make(map[string]string)
+	var names []string
+	for _, f := range zr.File {
+		r, err := f.Open()
+		c.Assert(err, qt.IsNil)
+		b, err := io.ReadAll(r)
+		c.Assert(err, qt.IsNil)
+		r.Close()
+		files[f.Name] = string(b)
+		names = append(names, f.Name)
+
+		if strings.HasSuffix(f.Name, ".xml") || strings.HasSuffix(f.Name, ".opf") || strings.HasSuffix(f.Name, ".xhtml") {
+			d := xml.NewDecoder(bytes.NewReader(b))
+			for {
+				_, err := d.Token()
+				if err == io.EOF {
+					break
+				}
+				c.Assert(err, qt.IsNil, qt.Commentf(f.Name))
+			}
+		}
+	}
+
+	c.Assert(names, qt.DeepEquals, []string{
+		"mimetype",
+		"META-INF/container.xml",
+		"EPUB/package.opf",
+		"EPUB/images/cover.png",
+		"EPUB/cover.xhtml",
+		"EPUB/chapter001.xhtml",
+		"EPUB/chapter002.xhtml",
+		"EPUB/images/p.png",
+		"EPUB/nav.xhtml",
+	})
+
+	c.Assert(files["mimetype"], qt.Equals, "application/epub+zip")
+
+	opf := files["EPUB/package.opf"]
+	for _, s := range []string{
+		`<dc:identifier id="pub-id">https://example.org/book/</dc:identifier>`,
+		`<dc:title>The Book &amp; Co</dc:title>`,
+		`<dc:creator>Jane Doe</dc:creator>`,
+		`<meta property="dcterms:modified">2022-06-01T12:00:00Z</meta>`,
+		`<item id="cover-image" href="images/cover.png" media-type="image/png" properties="cover-image"/>`,
+		`<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>`,
+		"<itemref idref=\"cover\"/>\n    <itemref idref=\"chapter001\"/>\n    <itemref idref=\"chapter002\"/>",
+	} {
+		c.Assert(opf, qt.Contains, s)
+	}
+
+	c.Assert(files["EPUB/nav.xhtml"], qt.Contains, `<li><a href="chapter001.xhtml">One</a></li><li><a href="chapter002.xhtml">Two</a></li>`)
+	c.Assert(files["EPUB/chapter002.xhtml"], qt.Contains, "<title>Two</title>")
+
+	b.Files = append(b.Files, File{Name: "chapter001.xhtml"})
+	c.Assert(b.Write(io.Discard), qt.ErrorMatches, `epub: duplicate file "chapter001.xhtml"`)
+}
@@ -0,0 +1,329 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphql implements a small GraphQL query executor for read-only
+// schemas defined in Go, without type validation or introspection.
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"reflect"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// Object is a GraphQL object type.
+type Object interface {
+	// TypeName returns the name of the type, e.g. Page.
+	TypeName() string
+
+	// Field resolves the field name with the given arguments. The result
+	// is either a scalar, an Object or a slice of those.
+	Field(name string, args Args) (any, error)
+}
+
+// ErrUnknownField is returned by Object.Field for unknown fields.
+type ErrUnknownField struct {
+	Name string
+}
+
+func (e ErrUnknownField) Error() string {
+	return fmt.Sprintf("unknown field %q", e.Name)
+}
+
+// Args holds the arguments of a field, with variables resolved.
+type Args map[string]any
+
+// String returns the string argument name, or an empty string if not set.
+func (a Args) String(name string) string {
+	return cast.ToString(a[name])
+}
+
+// Int returns the int argument name, or def if not set.
+func (a Args) Int(name string, def int) int {
+	v, found := a[name]
+	if !found || v == nil {
+		return def
+	}
+	return cast.ToInt(v)
+}
+
+// Bool returns the bool argument name, or def if not set.
+func (a Args) Bool(name string, def bool) bool {
+	v, found := a[name]
+	if !found || v == nil {
+		return def
+	}
+	return cast.ToBool(v)
+}
+
+// Request is a GraphQL request as sent over HTTP.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Response is a GraphQL response.
+type Response struct {
+	Data   any     `json:"data"`
+	Errors []Error `json:"errors,omitempty"`
+}
+
+// Error is an error in a GraphQL response.
+type Error struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+// Execute executes the query in req with root as the query type.
+// Errors resolving a field are returned in the Response with the field set to null.
+func Execute(root Object, req Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	var op *operation
+	for _, o := range doc.operations {
+		if req.OperationName == "" || o.name == req.OperationName {
+			if op != nil {
+				return &Response{Errors: []Error{{Message: "operationName is required for documents with multiple operations"}}}
+			}
+			op = o
+		}
+	}
+	if op == nil {
+		return &Response{Errors: []Error{{Message: fmt.Sprintf("unknown operation %q", req.OperationName)}}}
+	}
+
+	variables := make(map[string]any)
+	for k, v := range op.variables {
+		variables[k] = v
+	}
+	for k, v := range req.Variables {
+		variables[k] = v
+	}
+
+	e := &executor{doc: doc, variables: variables}
+	data := e.executeObject(root, op.selections, nil)
+
+	return &Response{Data: data, Errors: e.errors}
+}
+
+type executor struct {
+	doc       *document
+	variables map[string]any
+	errors    []Error
+}
+
+func (e *executor) addError(path []any, err error) {
+	e.errors = append(e.errors, Error{Message: err.Error(), Path: append([]any(nil), path...)})
+}
+
+func (e *executor) executeObject(o Object, sels []*selection, path []any) *orderedMap {
+	m := &orderedMap{}
+	e.collectFields(o, sels, path, m)
+	return m
+}
+
+func (e *executor) collectFields(o Object, sels []*selection, path []any, m *orderedMap) {
+	for _, sel := range sels {
+		if !e.include(sel) {
+			continue
+		}
+
+		if sel.fragment != "" || sel.inline {
+			typeName, sels := sel.typeName, sel.selections
+			if sel.fragment != "" {
+				f, found := e.doc.fragments[sel.fragment]
+				if !found {
+					e.addError(path, fmt.Errorf("unknown fragment %q", sel.fragment))
+					continue
+				}
+				typeName, sels = f.typeName, f.selections
+			}
+			if typeName == "" || typeName == o.TypeName() {
+				e.collectFields(o, sels, path, m)
+			}
+			continue
+		}
+
+		key := sel.key()
+		if m.has(key) {
+			continue
+		}
+		fieldPath := append(path[:len(path):len(path)], key)
+
+		if sel.name == "__typename" {
+			m.set(key, o.TypeName())
+			continue
+		}
+
+		v, err := o.Field(sel.name, e.resolveArgs(sel.args))
+		if err != nil {
+			if _, ok := err.(ErrUnknownField); ok {
+				err = fmt.Errorf("cannot query field %q on type %q", sel.name, o.TypeName())
+			}
+			e.addError(fieldPath, err)
+			m.set(key, nil)
+			continue
+		}
+
+		m.set(key, e.completeValue(v, sel, fieldPath))
+	}
+}
+
+func (e *executor) completeValue(v any, sel *selection, path []any) any {
+	if v == nil {
+		return nil
+	}
+
+	if o, ok := v.(Object); ok {
+		if isNil(o) {
+			return nil
+		}
+		if len(sel.selections) == 0 {
+			e.addError(path, fmt.Errorf("field %q of type %q must have a selection of subfields", sel.name, o.TypeName()))
+			return nil
+		}
+		return e.executeObject(o, sel.selections, path)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		list := make([]any, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			list[i] = e.completeValue(rv.Index(i).Interface(), sel, append(path[:len(path):len(path)], i))
+		}
+		return list
+	}
+
+	if len(sel.selections) > 0 {
+		e.addError(path, fmt.Errorf("field %q is a scalar and can not have a selection of subfields", sel.name))
+		return nil
+	}
+
+	switch vv := v.(type) {
+	case time.Time:
+		if vv.IsZero() {
+			return nil
+		}
+		return vv.Format(time.RFC3339)
+	case template.HTML:
+		return string(vv)
+	case fmt.Stringer:
+		if _, ok := v.(json.Marshaler); !ok {
+			return vv.String()
+		}
+	}
+
+	return v
+}
+
+func (e *executor) include(sel *selection) bool {
+	if args, found := sel.directives["skip"]; found && cast.ToBool(e.resolveValue(args["if"])) {
+		return false
+	}
+	if args, found := sel.directives["include"]; found && !cast.ToBool(e.resolveValue(args["if"])) {
+		return false
+	}
+	return true
+}
+
+func (e *executor) resolveArgs(args map[string]any) Args {
+	resolved := make(Args, len(args))
+	for k, v := range args {
+		resolved[k] = e.resolveValue(v)
+	}
+	return resolved
+}
+
+func (e *executor) resolveValue(v any) any {
+	switch vv := v.(type) {
+	case variable:
+		return e.variables[string(vv)]
+	case enum:
+		return string(vv)
+	case []any:
+		list := make([]any, len(vv))
+		for i, v := range vv {
+			list[i] = e.resolveValue(v)
+		}
+		return list
+	case map[string]any:
+		m := make(map[string]any, len(vv))
+		for k, v := range vv {
+			m[k] = e.resolveValue(v)
+		}
+		return m
+	}
+	return v
+}
+
+func isNil(v any) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// orderedMap is a JSON object that keeps the order of the selected fields.
+type orderedMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func (m *orderedMap) has(key string) bool {
+	_, found := m.values[key]
+	return found
+}
+
+func (m *orderedMap) set(key string, v any) {
+	if m.values == nil {
+		m.values = make(map[string]any)
+	}
+	if _, found := m.values[key]; !found {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = v
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(kb)
+		b.WriteByte(':')
+		vb, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(vb)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+type testQuery struct{}
+
+func (testQuery) TypeName() string { return "Query" }
+
+func (testQuery) Field(name string, args Args) (any, error) {
+	switch name {
+	case "hello":
+		return "Hello " + args.String("name"), nil
+	case "books":
+		books := []testBook{{"Hugo", 10}, {"Go", 20}, {"GraphQL", 30}}
+		if first := args.Int("first", -1); first >= 0 && first < len(books) {
+			books = books[:first]
+		}
+		return books, nil
+	case "date":
+		return time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC), nil
+	case "failing":
+		return nil, errors.New("failed")
+	}
+	return nil, ErrUnknownField{Name: name}
+}
+
+type testBook struct {
+	title string
+	pages int
+}
+
+func (testBook) TypeName() string { return "Book" }
+
+func (b testBook) Field(name string, args Args) (any, error) {
+	switch name {
+	case "title":
+		return b.title, nil
+	case "pages":
+		return b.pages, nil
+	}
+	return nil, ErrUnknownField{Name: name}
+}
+
+func TestExecute(t *testing.T) {
+	c := qt.New(t)
+
+	execute := func(query string, variables map[string]any) string {
+		b, err := json.Marshal(Execute(testQuery{}, Request{Query: query, Variables: variables}))
+		c.Assert(err, qt.IsNil)
+		return string(b)
+	}
+
+	for _, test := range []struct {
+		name      string
+		query     string
+		variables map[string]any
+		expect    string
+	}{
+		{"Shorthand", `{ hello(name: "World") }`, nil, `{"data":{"hello":"Hello World"}}`},
+		{"Alias and order", `{ b: hello(name: "B") a: hello(name: "A") }`, nil, `{"data":{"b":"Hello B","a":"Hello A"}}`},
+		{"List", `query { books(first: 2) { title pages } }`, nil, `{"data":{"books":[{"title":"Hugo","pages":10},{"title":"Go","pages":20}]}}`},
+		{"Variables", `query Q($n: Int = 3, $name: String!) { books(first: $n) { title } hello(name: $name) }`, map[string]any{"name": "V"}, `{"data":{"books":[{"title":"Hugo"},{"title":"Go"},{"title":"GraphQL"}],"hello":"Hello V"}}`},
+		{"Fragments", `{ books(first: 1) { ...f ... on Book { pages } ... on Page { x } } } fragment f on Book { title __typename }`, nil, `{"data":{"books":[{"title":"Hugo","__typename":"Book","pages":10}]}}`},
+		{"Directives", `query($yes: Boolean) { books(first: 1) { title @include(if: $yes) pages @skip(if: true) } }`, map[string]any{"yes": false}, `{"data":{"books":[{}]}}`},
+		{"Time", `{ date }`, nil, `{"data":{"date":"2022-01-02T03:04:05Z"}}`},
+		{"Field error", `{ failing hello }`, nil, `{"data":{"failing":null,"hello":"Hello "},"errors":[{"message":"failed","path":["failing"]}]}`},
+		{"Unknown field", `{ books(first: 1) { isbn } }`, nil, `{"data":{"books":[{"isbn":null}]},"errors":[{"message":"cannot query field \"isbn\" on type \"Book\"","path":["books",0,"isbn"]}]}`},
+		{"Missing selection", `{ books }`, nil, `{"data":{"books":[null,null,null]},"errors":[{"message":"field \"books\" of type \"Book\" must have a selection of subfields","path":["books",0]},{"message":"field \"books\" of type \"Book\" must have a selection of subfields","path":["books",1]},{"message":"field \"books\" of type \"Book\" must have a selection of subfields","path":["books",2]}]}`},
+		{"Scalar selection", `{ hello { x } }`, nil, `{"data":{"hello":null},"errors":[{"message":"field \"hello\" is a scalar and can not have a selection of subfields","path":["hello"]}]}`},
+	} {
+		c.Run(test.name, func(c *qt.C) {
+			c.Assert(execute(test.query, test.variables), qt.Equals, test.expect)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		query  string
+		expect string
+	}{
+		{`{ hello `, "syntax error at 1:9"},
+		{`mutation { hello }`, "only queries are supported"},
+		{`{ hello(name: "unterminated) }`, "unterminated string"},
+	} {
+		resp := Execute(testQuery{}, Request{Query: test.query})
+		c.Assert(resp.Data, qt.IsNil)
+		c.Assert(resp.Errors, qt.HasLen, 1)
+		c.Assert(strings.Contains(resp.Errors[0].Message, test.expect), qt.IsTrue, qt.Commentf("%s: %s", test.query, resp.Errors[0].Message))
+	}
+}
@@ -0,0 +1,648 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// document is a parsed GraphQL query document.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	name       string
+	variables  map[string]any // The default values.
+	selections []*selection
+}
+
+type fragment struct {
+	name       string
+	typeName   string
+	selections []*selection
+}
+
+// selection is a field or a fragment spread.
+type selection struct {
+	alias      string
+	name       string
+	args       map[string]any
+	directives map[string]map[string]any
+	selections []*selection
+
+	// Set for fragment spreads, e.g. ...PageFields.
+	fragment string
+	// Set for inline fragments, e.g. ... on Page { title }.
+	inline   bool
+	typeName string
+}
+
+func (s *selection) key() string {
+	if s.alias != "" {
+		return s.alias
+	}
+	return s.name
+}
+
+// variable is a reference to a variable in a value, e.g. $path.
+type variable string
+
+// enum is an enum value, e.g. DESC.
+type enum string
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+type parser struct {
+	input string
+	pos   int
+	tok   token
+}
+
+func parse(input string) (*document, error) {
+	p := &parser{input: input}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: make(map[string]*fragment)}
+
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.isPunct("{"):
+			sels, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{selections: sels})
+		case p.isName("query"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.isName("fragment"):
+			f, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			doc.fragments[f.name] = f
+		case p.isName("mutation"), p.isName("subscription"):
+			return nil, p.errorf("only queries are supported")
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, p.errorf("no operation")
+	}
+
+	return doc, nil
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{variables: make(map[string]any)}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind == tokenName {
+		op.name = p.tok.val
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isPunct("(") {
+		if err := p.parseVariableDefinitions(op); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	sels, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selections = sels
+
+	return op, nil
+}
+
+func (p *parser) parseVariableDefinitions(op *operation) error {
+	if err := p.expectPunct("("); err != nil {
+		return err
+	}
+	for !p.isPunct(")") {
+		if err := p.expectPunct("$"); err != nil {
+			return err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return err
+		}
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		op.variables[name] = nil
+		if p.isPunct("=") {
+			if err := p.next(); err != nil {
+				return err
+			}
+			v, err := p.parseValue()
+			if err != nil {
+				return err
+			}
+			op.variables[name] = v
+		}
+	}
+	return p.next()
+}
+
+// skipType skips a type reference, e.g. [String!]!, as we don't validate types.
+func (p *parser) skipType() error {
+	if p.isPunct("[") {
+		if err := p.next(); err != nil {
+			return err
+		}
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return err
+	}
+	if p.isPunct("!") {
+		return p.next()
+	}
+	return nil
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isName("on") {
+		return nil, p.unexpected()
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	typeName, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	sels, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return &fragment{name: name, typeName: typeName, selections: sels}, nil
+}
+
+func (p *parser) parseSelectionSet() ([]*selection, error) {
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	var sels []*selection
+	for !p.isPunct("}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.unexpected()
+		}
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+	}
+
+	if len(sels) == 0 {
+		return nil, p.errorf("empty selection set")
+	}
+
+	return sels, p.next()
+}
+
+func (p *parser) parseSelection() (*selection, error) {
+	sel := &selection{}
+
+	if p.isPunct("...") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokenName && !p.isName("on") {
+			sel.fragment = p.tok.val
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			directives, err := p.parseDirectives()
+			sel.directives = directives
+			return sel, err
+		}
+		sel.inline = true
+		if p.isName("on") {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			typeName, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			sel.typeName = typeName
+		}
+		directives, err := p.parseDirectives()
+		if err != nil {
+			return nil, err
+		}
+		sel.directives = directives
+		sel.selections, err = p.parseSelectionSet()
+		return sel, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	sel.name = name
+
+	if p.isPunct(":") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		sel.alias = sel.name
+		if sel.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.isPunct("(") {
+		if sel.args, err = p.parseArguments(); err != nil {
+			return nil, err
+		}
+	}
+
+	if sel.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	if p.isPunct("{") {
+		if sel.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return sel, nil
+}
+
+func (p *parser) parseArguments() (map[string]any, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	args := make(map[string]any)
+	for !p.isPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = v
+	}
+	return args, p.next()
+}
+
+func (p *parser) parseDirectives() (map[string]map[string]any, error) {
+	var directives map[string]map[string]any
+	for p.isPunct("@") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		var args map[string]any
+		if p.isPunct("(") {
+			if args, err = p.parseArguments(); err != nil {
+				return nil, err
+			}
+		}
+		if directives == nil {
+			directives = make(map[string]map[string]any)
+		}
+		directives[name] = args
+	}
+	return directives, nil
+}
+
+func (p *parser) parseValue() (any, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokenInt:
+		v, err := strconv.Atoi(tok.val)
+		if err != nil {
+			return nil, p.errorf("invalid int %q", tok.val)
+		}
+		return v, p.next()
+	case tokenFloat:
+		v, err := strconv.ParseFloat(tok.val, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %q", tok.val)
+		}
+		return v, p.next()
+	case tokenString:
+		return tok.val, p.next()
+	case tokenName:
+		var v any
+		switch tok.val {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = enum(tok.val)
+		}
+		return v, p.next()
+	case tokenPunct:
+		switch tok.val {
+		case "$":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			return variable(name), err
+		case "[":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			list := []any{}
+			for !p.isPunct("]") {
+				if p.tok.kind == tokenEOF {
+					return nil, p.unexpected()
+				}
+				v, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			}
+			return list, p.next()
+		case "{":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+			m := make(map[string]any)
+			for !p.isPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				v, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				m[name] = v
+			}
+			return m, p.next()
+		}
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) isPunct(s string) bool {
+	return p.tok.kind == tokenPunct && p.tok.val == s
+}
+
+func (p *parser) isName(s string) bool {
+	return p.tok.kind == tokenName && p.tok.val == s
+}
+
+func (p *parser) expectPunct(s string) error {
+	if !p.isPunct(s) {
+		return p.unexpected()
+	}
+	return p.next()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.unexpected()
+	}
+	name := p.tok.val
+	return name, p.next()
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokenEOF {
+		return p.errorf("unexpected end of query")
+	}
+	return p.errorf("unexpected %q", p.tok.val)
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	line := 1 + strings.Count(p.input[:p.tok.pos], "\n")
+	col := p.tok.pos - strings.LastIndex(p.input[:p.tok.pos], "\n")
+	return fmt.Errorf("syntax error at %d:%d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+// next reads the next token into p.tok.
+func (p *parser) next() error {
+	// Skip whitespace, commas and comments.
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c == '#' {
+			for p.pos < len(p.input) && p.input[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		}
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+			continue
+		}
+		if strings.HasPrefix(p.input[p.pos:], "\ufeff") {
+			p.pos += len("\ufeff")
+			continue
+		}
+		break
+	}
+
+	start := p.pos
+	p.tok = token{pos: start}
+
+	if p.pos >= len(p.input) {
+		p.tok.kind = tokenEOF
+		return nil
+	}
+
+	c := p.input[p.pos]
+	switch {
+	case strings.HasPrefix(p.input[p.pos:], "..."):
+		p.pos += 3
+		p.tok.kind, p.tok.val = tokenPunct, "..."
+	case strings.IndexByte("!$():=@[]{|}&", c) >= 0:
+		p.pos++
+		p.tok.kind, p.tok.val = tokenPunct, string(c)
+	case isNameStart(c):
+		for p.pos < len(p.input) && isNameContinue(p.input[p.pos]) {
+			p.pos++
+		}
+		p.tok.kind, p.tok.val = tokenName, p.input[start:p.pos]
+	case c == '-' || isDigit(c):
+		p.tok.kind = tokenInt
+		p.pos++
+		for p.pos < len(p.input) {
+			c := p.input[p.pos]
+			if isDigit(c) {
+				p.pos++
+				continue
+			}
+			if c == '.' || c == 'e' || c == 'E' || ((c == '+' || c == '-') && p.tok.kind == tokenFloat) {
+				p.tok.kind = tokenFloat
+				p.pos++
+				continue
+			}
+			break
+		}
+		p.tok.val = p.input[start:p.pos]
+	case c == '"':
+		s, err := p.readString()
+		if err != nil {
+			return err
+		}
+		p.tok.kind, p.tok.val = tokenString, s
+	default:
+		r, _ := utf8.DecodeRuneInString(p.input[p.pos:])
+		p.tok.val = string(r)
+		return p.errorf("unexpected character %q", r)
+	}
+
+	return nil
+}
+
+func (p *parser) readString() (string, error) {
+	if strings.HasPrefix(p.input[p.pos:], `"""`) {
+		end := strings.Index(p.input[p.pos+3:], `"""`)
+		if end == -1 {
+			return "", p.errorf("unterminated string")
+		}
+		s := p.input[p.pos+3 : p.pos+3+end]
+		p.pos += end + 6
+		return strings.TrimSpace(s), nil
+	}
+
+	var b strings.Builder
+	p.pos++
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return b.String(), nil
+		case '\n':
+			return "", p.errorf("unterminated string")
+		case '\\':
+			if p.pos+1 >= len(p.input) {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.input[p.pos+1]
+			p.pos += 2
+			switch e {
+			case '"', '\\', '/':
+				b.WriteByte(e)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if p.pos+4 > len(p.input) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.input[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				return "", p.errorf("invalid escape \\%c", e)
+			}
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameContinue(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -26,7 +26,9 @@ import (
 	"os/exec"
 
 	"github.com/cli/safeexec"
+	"github.com/gohugoio/hugo/common/htrace"
 	"github.com/gohugoio/hugo/config"
+	"go.opentelemetry.io/otel/attribute"
 	"github.com/gohugoio/hugo/config/security"
 )
 
@@ -167,7 +169,10 @@ func (e *NotFoundError) Error() string {
 // Runner wraps a *os.Cmd.
 type Runner interface {
 	Run() error
+	Start() error
+	Wait() error
 	StdinPipe() (io.WriteCloser, error)
+	StdoutPipe() (io.ReadCloser, error)
 }
 
 type cmdWrapper struct {
@@ -179,8 +184,13 @@ type cmdWrapper struct {
 
 var notFoundRe = regexp.MustCompile(`(?s)not found:|could not determine executable`)
 
-func (c *cmdWrapper) Run() error {
-	err := c.c.Run()
+func (c *cmdWrapper) Run() (err error) {
+	span := htrace.StartInBuild("exec "+c.name, attribute.StringSlice("exec.args", c.c.Args[1:]))
+	defer func() {
+		htrace.End(span, err)
+	}()
+
+	err = c.c.Run()
 	if err == nil {
 		return nil
 	}
@@ -190,10 +200,26 @@ func (c *cmdWrapper) Run() error {
 	return fmt.Errorf("failed to execute binary %q with args %v: %s", c.name, c.c.Args[1:], c.outerr.String())
 }
 
+func (c *cmdWrapper) Start() error {
+	return c.c.Start()
+}
+
+func (c *cmdWrapper) Wait() error {
+	err := c.c.Wait()
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("binary %q with args %v failed: %s", c.name, c.c.Args[1:], c.outerr.String())
+}
+
 func (c *cmdWrapper) StdinPipe() (io.WriteCloser, error) {
 	return c.c.StdinPipe()
 }
 
+func (c *cmdWrapper) StdoutPipe() (io.ReadCloser, error) {
+	return c.c.StdoutPipe()
+}
+
 type commandeer struct {
 	stdout io.Writer
 	stderr io.Writer
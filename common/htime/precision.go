@@ -0,0 +1,117 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package htime
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	toml "github.com/pelletier/go-toml/v2"
+)
+
+// The precisions of a parsed date, from the least to the most precise.
+const (
+	PrecisionYear  = "year"
+	PrecisionMonth = "month"
+	PrecisionDay   = "day"
+	PrecisionTime  = "time"
+)
+
+// DefaultDateLayouts are the layouts tried, in addition to the configured
+// ones, before the formats supported by ToTimeInDefaultLocationE.
+var DefaultDateLayouts = []string{
+	"January 2006",
+	"Jan 2006",
+	"2 January 2006",
+	"January 2 2006",
+}
+
+var (
+	partialYearRe  = regexp.MustCompile(`^\d{4}$`)
+	partialMonthRe = regexp.MustCompile(`^\d{4}-\d{2}$`)
+)
+
+// ToTimeWithPrecisionE converts i to a time, as ToTimeInDefaultLocationE,
+// also accepting partial dates, i.e. a year (2024) or a year and a month
+// (2024-05), and strings in one of the given Go layouts or the
+// DefaultDateLayouts. It returns the precision of the date, one of year,
+// month, day or time.
+func ToTimeWithPrecisionE(i any, location *time.Location, layouts []string) (time.Time, string, error) {
+	switch vv := i.(type) {
+	case int:
+		if vv >= 1000 && vv <= 9999 {
+			return time.Date(vv, time.January, 1, 0, 0, 0, 0, location), PrecisionYear, nil
+		}
+	case int64:
+		if vv >= 1000 && vv <= 9999 {
+			return time.Date(int(vv), time.January, 1, 0, 0, 0, 0, location), PrecisionYear, nil
+		}
+	case toml.LocalDate:
+		return vv.AsTime(location), PrecisionDay, nil
+	case string:
+		s := strings.TrimSpace(vv)
+		switch {
+		case partialYearRe.MatchString(s):
+			t, err := time.ParseInLocation("2006", s, location)
+			return t, PrecisionYear, err
+		case partialMonthRe.MatchString(s):
+			t, err := time.ParseInLocation("2006-01", s, location)
+			return t, PrecisionMonth, err
+		}
+		for _, layouts := range [][]string{layouts, DefaultDateLayouts} {
+			for _, layout := range layouts {
+				if t, err := time.ParseInLocation(layout, s, location); err == nil {
+					return t, LayoutPrecision(layout), nil
+				}
+			}
+		}
+		t, err := ToTimeInDefaultLocationE(s, location)
+		if err != nil {
+			return t, "", err
+		}
+		if !strings.Contains(s, ":") && t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+			return t, PrecisionDay, nil
+		}
+		return t, PrecisionTime, nil
+	}
+
+	t, err := ToTimeInDefaultLocationE(i, location)
+	return t, PrecisionTime, err
+}
+
+// LayoutPrecision returns the precision of the dates parsed with the Go
+// layout, e.g. month for "January 2006".
+func LayoutPrecision(layout string) string {
+	// Remove the year so its digits are not taken for other elements.
+	layout = strings.NewReplacer("2006", "", "06", "").Replace(layout)
+	switch {
+	case containsAny(layout, "15", "03", "3:", "04", "05", "PM", "pm"):
+		return PrecisionTime
+	case containsAny(layout, "02", "_2", "2", "Mon"):
+		return PrecisionDay
+	case containsAny(layout, "01", "1", "Jan"):
+		return PrecisionMonth
+	}
+	return PrecisionYear
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
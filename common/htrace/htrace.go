@@ -0,0 +1,142 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package htrace instruments the build with OpenTelemetry spans, exported
+// via OTLP over HTTP when an endpoint is configured with the standard
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT
+// environment variables.
+package htrace
+
+import (
+	"context"
+	"os"
+	"runtime/trace"
+	"sync"
+
+	"github.com/gohugoio/hugo/common/hugo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/gohugoio/hugo"
+
+// The environment variables that enable the exporter.
+var endpointEnvs = []string{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "OTEL_EXPORTER_OTLP_ENDPOINT"}
+
+var (
+	buildCtxMu sync.RWMutex
+	buildCtx   = context.Background()
+)
+
+// Enabled reports whether an OTLP endpoint is configured.
+func Enabled() bool {
+	for _, env := range endpointEnvs {
+		if os.Getenv(env) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Init sets up the OTLP exporter if Enabled and returns a function that
+// flushes the spans and shuts it down.
+// The exporter is configured with the standard OTEL_* environment variables,
+// e.g. OTEL_EXPORTER_OTLP_HEADERS; the service name defaults to hugo.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := []attribute.KeyValue{semconv.ServiceVersionKey.String(hugo.CurrentVersion.String())}
+	if os.Getenv("OTEL_SERVICE_NAME") == "" {
+		attrs = append(attrs, semconv.ServiceNameKey.String("hugo"))
+	}
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, attrs...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// Start starts a span with the given name as a child of the span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, oteltrace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, oteltrace.WithAttributes(attrs...))
+}
+
+// StartInBuild starts a span with the given name as a child of the span of
+// the running build, see SetBuildContext.
+func StartInBuild(name string, attrs ...attribute.KeyValue) oteltrace.Span {
+	_, span := Start(BuildContext(), name, attrs...)
+	return span
+}
+
+// End records err, if not nil, on span and ends it.
+func End(span oteltrace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WithRegion runs f in a span and a runtime/trace region with the given name.
+func WithRegion(ctx context.Context, name string, f func(ctx context.Context)) {
+	ctx, span := Start(ctx, name)
+	defer span.End()
+	trace.WithRegion(ctx, name, func() {
+		f(ctx)
+	})
+}
+
+// SetBuildContext sets the context with the span of the running build, the
+// parent of spans started from code without a context, e.g. image processing
+// and external commands. It returns a function restoring the previous one.
+func SetBuildContext(ctx context.Context) func() {
+	buildCtxMu.Lock()
+	defer buildCtxMu.Unlock()
+	prev := buildCtx
+	buildCtx = ctx
+	return func() {
+		buildCtxMu.Lock()
+		defer buildCtxMu.Unlock()
+		buildCtx = prev
+	}
+}
+
+// BuildContext returns the context set in SetBuildContext.
+func BuildContext() context.Context {
+	buildCtxMu.RLock()
+	defer buildCtxMu.RUnlock()
+	return buildCtx
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package htrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpans(t *testing.T) {
+	c := qt.New(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer otel.SetTracerProvider(prev)
+
+	ctx, build := Start(context.Background(), "build")
+	restore := SetBuildContext(ctx)
+	WithRegion(ctx, "render", func(ctx context.Context) {
+		_, span := Start(ctx, "page")
+		span.End()
+	})
+	End(StartInBuild("exec"), errors.New("failed"))
+	restore()
+	build.End()
+
+	c.Assert(BuildContext(), qt.Equals, context.Background())
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	c.Assert(spans, qt.HasLen, 4)
+
+	buildID := spans["build"].SpanContext().SpanID()
+	c.Assert(spans["render"].Parent().SpanID(), qt.Equals, buildID)
+	c.Assert(spans["page"].Parent().SpanID(), qt.Equals, spans["render"].SpanContext().SpanID())
+	c.Assert(spans["exec"].Parent().SpanID(), qt.Equals, buildID)
+	c.Assert(spans["exec"].Status().Code, qt.Equals, codes.Error)
+}
+
+func TestEnabled(t *testing.T) {
+	c := qt.New(t)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	c.Assert(Enabled(), qt.IsFalse)
+	shutdown, err := Init(context.Background())
+	c.Assert(err, qt.IsNil)
+	c.Assert(shutdown(context.Background()), qt.IsNil)
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318")
+	c.Assert(Enabled(), qt.IsTrue)
+}
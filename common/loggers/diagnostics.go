@@ -0,0 +1,197 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/common/herrors"
+
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+const (
+	// LogFormatText is the default, human readable, log format.
+	LogFormatText = "text"
+	// LogFormatJSON writes one JSON Diagnostic per line.
+	LogFormatJSON = "json"
+)
+
+// Diagnostic is a structured log record, used when the log format is JSON.
+type Diagnostic struct {
+	// One of error, warning, info or debug.
+	Severity string `json:"severity"`
+
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+
+	// Code is a stable identifier for the message, if any,
+	// e.g. the statement ID to use in ignoreErrors or ignoreWarnings.
+	Code string `json:"code,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// NewDiagnosticFromError creates a Diagnostic from err, using the position
+// information from the first herrors.FileError found in the error chain.
+func NewDiagnosticFromError(severity string, err error) Diagnostic {
+	return newDiagnostic(severity, "", err.Error(), []any{err})
+}
+
+// newDiagnostic creates a Diagnostic with the given message. The position is
+// taken from the first argument wrapping a herrors.FileError, if any.
+func newDiagnostic(severity, code, message string, args []any) Diagnostic {
+	d := Diagnostic{
+		Severity: severity,
+		Code:     code,
+		Message:  strings.TrimRight(RemoveANSIColours(message), "\n"),
+	}
+	for _, arg := range args {
+		err, ok := arg.(error)
+		if !ok {
+			continue
+		}
+		if fe := herrors.UnwrapFileError(err); fe != nil {
+			pos := fe.Position()
+			d.File = pos.Filename
+			d.Line = pos.LineNumber
+			d.Column = pos.ColumnNumber
+			break
+		}
+	}
+	return d
+}
+
+// WriteDiagnostic writes d as a single line of JSON to w.
+func WriteDiagnostic(w io.Writer, d Diagnostic) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	_, err = w.Write(b)
+	return err
+}
+
+func severity(t jww.Threshold) string {
+	switch {
+	case t >= jww.LevelError:
+		return "error"
+	case t == jww.LevelWarn:
+		return "warning"
+	case t == jww.LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
+
+// diagnostics writes Diagnostic records to the writers of each log level.
+type diagnostics struct {
+	mu      sync.Mutex
+	out     io.Writer
+	writers [jww.LevelFatal + 1]io.Writer
+}
+
+// newDiagnostics routes the log levels to outHandle and logHandle the same way
+// as jww.Notepad, but without any prefix, so every write is one Diagnostic.
+func newDiagnostics(stdoutThreshold, logThreshold jww.Threshold, outHandle, logHandle io.Writer, listeners ...jww.LogListener) *diagnostics {
+	d := &diagnostics{out: outHandle}
+	for t := jww.LevelTrace; t <= jww.LevelFatal; t++ {
+		var writers []io.Writer
+		if t >= stdoutThreshold {
+			writers = append(writers, outHandle)
+		}
+		if t >= logThreshold {
+			writers = append(writers, logHandle)
+		}
+		for _, l := range listeners {
+			if w := l(t); w != nil {
+				writers = append(writers, w)
+			}
+		}
+		d.writers[t] = io.MultiWriter(writers...)
+	}
+	return d
+}
+
+func (d *diagnostics) write(t jww.Threshold, diag Diagnostic) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	WriteDiagnostic(d.writers[t], diag)
+}
+
+// notepad creates a jww.Notepad writing Diagnostic records. The feedback,
+// e.g. build progress, is written to stdout as info records.
+func (d *diagnostics) notepad() *jww.Notepad {
+	n := jww.NewNotepad(jww.LevelFatal, jww.LevelFatal, diagnosticsFeedbackWriter{d: d}, ioutil.Discard, "", 0)
+	n.TRACE = d.logger(jww.LevelTrace)
+	n.DEBUG = d.logger(jww.LevelDebug)
+	n.INFO = d.logger(jww.LevelInfo)
+	n.WARN = d.logger(jww.LevelWarn)
+	n.ERROR = d.logger(jww.LevelError)
+	n.CRITICAL = d.logger(jww.LevelCritical)
+	n.FATAL = d.logger(jww.LevelFatal)
+	return n
+}
+
+func (d *diagnostics) logf(t jww.Threshold, code, format string, v ...any) {
+	d.write(t, newDiagnostic(severity(t), code, fmt.Sprintf(format, v...), v))
+}
+
+func (d *diagnostics) logln(t jww.Threshold, v ...any) {
+	d.write(t, newDiagnostic(severity(t), "", fmt.Sprintln(v...), v))
+}
+
+// logger returns a log.Logger for threshold t for the callers logging
+// directly to e.g. Logger.Warn().
+func (d *diagnostics) logger(t jww.Threshold) *log.Logger {
+	return log.New(diagnosticsLevelWriter{d: d, t: t}, "", 0)
+}
+
+type diagnosticsLevelWriter struct {
+	d *diagnostics
+	t jww.Threshold
+}
+
+func (w diagnosticsLevelWriter) Write(p []byte) (n int, err error) {
+	w.d.write(w.t, newDiagnostic(severity(w.t), "", string(p), nil))
+	return len(p), nil
+}
+
+type diagnosticsFeedbackWriter struct {
+	d *diagnostics
+}
+
+func (w diagnosticsFeedbackWriter) Write(p []byte) (n int, err error) {
+	w.d.mu.Lock()
+	defer w.d.mu.Unlock()
+	if err := WriteDiagnostic(w.d.out, newDiagnostic("info", "", string(p), nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// statementLogger is implemented by loggers that can log a statement ID as
+// part of the record instead of as a hint in the message.
+type statementLogger interface {
+	logStatementf(t jww.Threshold, statementID, format string, v ...any) bool
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loggers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/herrors"
+	"github.com/gohugoio/hugo/common/text"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+func TestNewDiagnosticFromError(t *testing.T) {
+	c := qt.New(t)
+
+	fe := herrors.NewFileErrorFromPos(errors.New("oops"), text.Position{Filename: "content/p1.md", LineNumber: 4, ColumnNumber: 2})
+	d := NewDiagnosticFromError("error", fmt.Errorf("build failed: %w", fe))
+
+	c.Assert(d.Severity, qt.Equals, "error")
+	c.Assert(d.File, qt.Equals, "content/p1.md")
+	c.Assert(d.Line, qt.Equals, 4)
+	c.Assert(d.Column, qt.Equals, 2)
+}
+
+func TestDiagnosticsLogger(t *testing.T) {
+	c := qt.New(t)
+
+	var b bytes.Buffer
+	l := NewDiagnosticsLogger(jww.LevelWarn, jww.LevelError, &b, ioutil.Discard, false)
+	fe := herrors.NewFileErrorFromPos(errors.New("oops"), text.Position{Filename: "layouts/index.html", LineNumber: 3, ColumnNumber: 5})
+
+	l.Warnln("A warning")
+	l.Errorf("An error\nwith two lines")
+	l.Errorf("render failed: %s", fe)
+	NewIgnorableLogger(l, nil, nil).Warnsf("theme-old-shortcode", "Old shortcode")
+	l.Error().Println("Direct")
+	l.Printf("Feedback")
+	l.Infoln("Not logged")
+
+	var diagnostics []Diagnostic
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		var d Diagnostic
+		c.Assert(json.Unmarshal([]byte(line), &d), qt.IsNil)
+		diagnostics = append(diagnostics, d)
+	}
+
+	c.Assert(diagnostics, qt.DeepEquals, []Diagnostic{
+		{Severity: "warning", Message: "A warning"},
+		{Severity: "error", Message: "An error\nwith two lines"},
+		{Severity: "error", File: "layouts/index.html", Line: 3, Column: 5, Message: `render failed: "layouts/index.html:3:5": oops`},
+		{Severity: "warning", Code: "theme-old-shortcode", Message: "Old shortcode"},
+		{Severity: "error", Message: "Direct"},
+		{Severity: "info", Message: "Feedback"},
+	})
+
+	c.Assert(l.LogCounters().ErrorCounter.Count(), qt.Equals, uint64(3))
+	c.Assert(l.LogCounters().WarnCounter.Count(), qt.Equals, uint64(5))
+}
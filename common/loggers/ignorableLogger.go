@@ -16,38 +16,51 @@ package loggers
 import (
 	"fmt"
 	"strings"
+
+	jww "github.com/spf13/jwalterweatherman"
 )
 
 // IgnorableLogger is a logger that ignores certain log statements.
 type IgnorableLogger interface {
 	Logger
 	Errorsf(statementID, format string, v ...any)
+	Warnsf(statementID, format string, v ...any)
 	Apply(logger Logger) IgnorableLogger
 }
 
 type ignorableLogger struct {
 	Logger
-	statements map[string]bool
+	errors   map[string]bool
+	warnings map[string]bool
+}
+
+// NewIgnorableLogger wraps the given logger and ignores the ERROR and WARNING
+// log statement IDs given.
+func NewIgnorableLogger(logger Logger, ignoreErrors, ignoreWarnings []string) IgnorableLogger {
+	return ignorableLogger{
+		Logger:   logger,
+		errors:   toStatementsSet(ignoreErrors),
+		warnings: toStatementsSet(ignoreWarnings),
+	}
 }
 
-// NewIgnorableLogger wraps the given logger and ignores the log statement IDs given.
-func NewIgnorableLogger(logger Logger, statements ...string) IgnorableLogger {
+func toStatementsSet(statements []string) map[string]bool {
 	statementsSet := make(map[string]bool)
 	for _, s := range statements {
 		statementsSet[strings.ToLower(s)] = true
 	}
-	return ignorableLogger{
-		Logger:     logger,
-		statements: statementsSet,
-	}
+	return statementsSet
 }
 
 // Errorsf logs statementID as an ERROR if not configured as ignoreable.
 func (l ignorableLogger) Errorsf(statementID, format string, v ...any) {
-	if l.statements[statementID] {
+	if l.errors[strings.ToLower(statementID)] {
 		// Ignore.
 		return
 	}
+	if sl, ok := l.Logger.(statementLogger); ok && sl.logStatementf(jww.LevelError, statementID, format, v...) {
+		return
+	}
 	ignoreMsg := fmt.Sprintf(`
 If you feel that this should not be logged as an ERROR, you can ignore it by adding this to your site config:
 ignoreErrors = [%q]`, statementID)
@@ -57,9 +70,31 @@ ignoreErrors = [%q]`, statementID)
 	l.Errorf(format, v...)
 }
 
+// Warnsf logs statementID as a WARNING if not configured as ignoreable.
+func (l ignorableLogger) Warnsf(statementID, format string, v ...any) {
+	if l.warnings[strings.ToLower(statementID)] {
+		// Ignore.
+		return
+	}
+	if sl, ok := l.Logger.(statementLogger); ok && sl.logStatementf(jww.LevelWarn, statementID, format, v...) {
+		if PanicOnWarning {
+			panic(panicOnWarningMessage)
+		}
+		return
+	}
+	ignoreMsg := fmt.Sprintf(`
+You can suppress this warning by adding this to your site config:
+ignoreWarnings = [%q]`, statementID)
+
+	format += ignoreMsg
+
+	l.Warnf(format, v...)
+}
+
 func (l ignorableLogger) Apply(logger Logger) IgnorableLogger {
 	return ignorableLogger{
-		Logger:     logger,
-		statements: l.statements,
+		Logger:   logger,
+		errors:   l.errors,
+		warnings: l.warnings,
 	}
 }
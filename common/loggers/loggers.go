@@ -99,6 +99,9 @@ type logger struct {
 
 	// This is only set in server mode.
 	errors *bytes.Buffer
+
+	// Set when the log format is JSON.
+	diagnostics *diagnostics
 }
 
 func (l *logger) Printf(format string, v ...any) {
@@ -114,19 +117,19 @@ func (l *logger) Debug() *log.Logger {
 }
 
 func (l *logger) Debugf(format string, v ...any) {
-	l.DEBUG.Printf(format, v...)
+	l.logf(jww.LevelDebug, format, v...)
 }
 
 func (l *logger) Debugln(v ...any) {
-	l.DEBUG.Println(v...)
+	l.logln(jww.LevelDebug, v...)
 }
 
 func (l *logger) Infof(format string, v ...any) {
-	l.INFO.Printf(format, v...)
+	l.logf(jww.LevelInfo, format, v...)
 }
 
 func (l *logger) Infoln(v ...any) {
-	l.INFO.Println(v...)
+	l.logln(jww.LevelInfo, v...)
 }
 
 func (l *logger) Info() *log.Logger {
@@ -136,14 +139,14 @@ func (l *logger) Info() *log.Logger {
 const panicOnWarningMessage = "Warning trapped. Remove the --panicOnWarning flag to continue."
 
 func (l *logger) Warnf(format string, v ...any) {
-	l.WARN.Printf(format, v...)
+	l.logf(jww.LevelWarn, format, v...)
 	if PanicOnWarning {
 		panic(panicOnWarningMessage)
 	}
 }
 
 func (l *logger) Warnln(v ...any) {
-	l.WARN.Println(v...)
+	l.logln(jww.LevelWarn, v...)
 	if PanicOnWarning {
 		panic(panicOnWarningMessage)
 	}
@@ -154,11 +157,50 @@ func (l *logger) Warn() *log.Logger {
 }
 
 func (l *logger) Errorf(format string, v ...any) {
-	l.ERROR.Printf(format, v...)
+	l.logf(jww.LevelError, format, v...)
 }
 
 func (l *logger) Errorln(v ...any) {
-	l.ERROR.Println(v...)
+	l.logln(jww.LevelError, v...)
+}
+
+func (l *logger) logf(t jww.Threshold, format string, v ...any) {
+	if l.diagnostics != nil {
+		l.diagnostics.logf(t, "", format, v...)
+		return
+	}
+	l.levelLogger(t).Printf(format, v...)
+}
+
+func (l *logger) logln(t jww.Threshold, v ...any) {
+	if l.diagnostics != nil {
+		l.diagnostics.logln(t, v...)
+		return
+	}
+	l.levelLogger(t).Println(v...)
+}
+
+// logStatementf logs statementID in the Code field of the Diagnostic
+// when the log format is JSON.
+func (l *logger) logStatementf(t jww.Threshold, statementID, format string, v ...any) bool {
+	if l.diagnostics == nil {
+		return false
+	}
+	l.diagnostics.logf(t, statementID, format, v...)
+	return true
+}
+
+func (l *logger) levelLogger(t jww.Threshold) *log.Logger {
+	switch t {
+	case jww.LevelDebug:
+		return l.DEBUG
+	case jww.LevelInfo:
+		return l.INFO
+	case jww.LevelWarn:
+		return l.WARN
+	default:
+		return l.ERROR
+	}
 }
 
 func (l *logger) Error() *log.Logger {
@@ -207,7 +249,13 @@ func (l *logger) Reset() {
 
 //  NewLogger creates a new Logger for the given thresholds
 func NewLogger(stdoutThreshold, logThreshold jww.Threshold, outHandle, logHandle io.Writer, saveErrors bool) Logger {
-	return newLogger(stdoutThreshold, logThreshold, outHandle, logHandle, saveErrors)
+	return newLogger(stdoutThreshold, logThreshold, outHandle, logHandle, saveErrors, false)
+}
+
+// NewDiagnosticsLogger creates a new Logger for the given thresholds that
+// writes every log record as a JSON Diagnostic.
+func NewDiagnosticsLogger(stdoutThreshold, logThreshold jww.Threshold, outHandle, logHandle io.Writer, saveErrors bool) Logger {
+	return newLogger(stdoutThreshold, logThreshold, outHandle, logHandle, saveErrors, true)
 }
 
 // NewDebugLogger is a convenience function to create a debug logger.
@@ -232,12 +280,12 @@ func NewErrorLogger() Logger {
 
 // NewBasicLogger creates a new basic logger writing to Stdout.
 func NewBasicLogger(t jww.Threshold) Logger {
-	return newLogger(t, jww.LevelError, os.Stdout, ioutil.Discard, false)
+	return newLogger(t, jww.LevelError, os.Stdout, ioutil.Discard, false, false)
 }
 
 // NewBasicLoggerForWriter creates a new basic logger writing to w.
 func NewBasicLoggerForWriter(t jww.Threshold, w io.Writer) Logger {
-	return newLogger(t, jww.LevelError, w, ioutil.Discard, false)
+	return newLogger(t, jww.LevelError, w, ioutil.Discard, false, false)
 }
 
 // RemoveANSIColours removes all ANSI colours from the given string.
@@ -289,6 +337,26 @@ func InitGlobalLogger(stdoutThreshold, logThreshold jww.Threshold, outHandle, lo
 	jww.SetStdoutThreshold(stdoutThreshold)
 }
 
+// InitGlobalDiagnosticsLogger initializes the global logger to write every
+// log record as a JSON Diagnostic.
+func InitGlobalDiagnosticsLogger(stdoutThreshold, logThreshold jww.Threshold, outHandle, logHandle io.Writer) {
+	jww.SetStdoutOutput(outHandle)
+	jww.SetLogOutput(logHandle)
+	jww.SetLogThreshold(logThreshold)
+	jww.SetStdoutThreshold(stdoutThreshold)
+
+	n := newDiagnostics(stdoutThreshold, logThreshold, outHandle, logHandle, jww.LogCounter(GlobalErrorCounter, jww.LevelError)).notepad()
+	jww.TRACE = n.TRACE
+	jww.DEBUG = n.DEBUG
+	jww.INFO = n.INFO
+	jww.WARN = n.WARN
+	jww.ERROR = n.ERROR
+	jww.CRITICAL = n.CRITICAL
+	jww.FATAL = n.FATAL
+	jww.LOG = n.LOG
+	jww.FEEDBACK = n.FEEDBACK
+}
+
 func getLogWriters(outHandle, logHandle io.Writer) (io.Writer, io.Writer) {
 	isTerm := terminal.IsTerminal(os.Stdout)
 	if logHandle != ioutil.Discard && isTerm {
@@ -323,10 +391,12 @@ var fatalLogListener = func(t jww.Threshold) io.Writer {
 	return new(fatalLogWriter)
 }
 
-func newLogger(stdoutThreshold, logThreshold jww.Threshold, outHandle, logHandle io.Writer, saveErrors bool) *logger {
+func newLogger(stdoutThreshold, logThreshold jww.Threshold, outHandle, logHandle io.Writer, saveErrors, diagnosticsFormat bool) *logger {
 	errorCounter := &jww.Counter{}
 	warnCounter := &jww.Counter{}
-	outHandle, logHandle = getLogWriters(outHandle, logHandle)
+	if !diagnosticsFormat {
+		outHandle, logHandle = getLogWriters(outHandle, logHandle)
+	}
 
 	listeners := []jww.LogListener{jww.LogCounter(errorCounter, jww.LevelError), jww.LogCounter(warnCounter, jww.LevelWarn)}
 	var errorBuff *bytes.Buffer
@@ -343,13 +413,21 @@ func newLogger(stdoutThreshold, logThreshold jww.Threshold, outHandle, logHandle
 		listeners = append(listeners, errorCapture)
 	}
 
-	return &logger{
-		Notepad: jww.NewNotepad(stdoutThreshold, logThreshold, outHandle, logHandle, "", log.Ldate|log.Ltime, listeners...),
-		out:     outHandle,
+	l := &logger{
+		out: outHandle,
 		logCounters: &LogCounters{
 			ErrorCounter: errorCounter,
 			WarnCounter:  warnCounter,
 		},
 		errors: errorBuff,
 	}
+
+	if diagnosticsFormat {
+		l.diagnostics = newDiagnostics(stdoutThreshold, logThreshold, outHandle, logHandle, listeners...)
+		l.Notepad = l.diagnostics.notepad()
+	} else {
+		l.Notepad = jww.NewNotepad(stdoutThreshold, logThreshold, outHandle, logHandle, "", log.Ldate|log.Ltime, listeners...)
+	}
+
+	return l
 }
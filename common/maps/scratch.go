@@ -50,34 +50,67 @@ func NewScratcher() Scratcher {
 // Supports numeric values and strings.
 //
 // If the first add for a key is an array or slice, then the next value(s) will be appended.
+//
+// The read and the write are done under the same lock, so concurrent Adds
+// to the same key are not lost.
 func (c *Scratch) Add(key string, newAddend any) (string, error) {
-	var newVal any
-	c.mu.RLock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	newVal, err := c.add(key, newAddend)
+	if err != nil {
+		return "", err
+	}
+	c.values[key] = newVal
+	return "", nil // have to return something to make it work with the Go templates
+}
+
+func (c *Scratch) add(key string, newAddend any) (any, error) {
 	existingAddend, found := c.values[key]
-	c.mu.RUnlock()
-	if found {
-		var err error
-
-		addendV := reflect.TypeOf(existingAddend)
-
-		if addendV.Kind() == reflect.Slice || addendV.Kind() == reflect.Array {
-			newVal, err = collections.Append(existingAddend, newAddend)
-			if err != nil {
-				return "", err
-			}
-		} else {
-			newVal, err = math.DoArithmetic(existingAddend, newAddend, '+')
-			if err != nil {
-				return "", err
-			}
-		}
-	} else {
-		newVal = newAddend
+	if !found {
+		return newAddend, nil
 	}
+
+	addendV := reflect.TypeOf(existingAddend)
+	if addendV.Kind() == reflect.Slice || addendV.Kind() == reflect.Array {
+		return collections.Append(existingAddend, newAddend)
+	}
+	return math.DoArithmetic(existingAddend, newAddend, '+')
+}
+
+// Incr atomically increments the counter with the given key by 1, or by delta
+// if provided, and returns the new value. A missing counter starts at 0.
+func (c *Scratch) Incr(key string, delta ...any) (any, error) {
+	var d any = 1
+	if len(delta) > 0 {
+		d = delta[0]
+	}
+
 	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	existing, found := c.values[key]
+	if !found {
+		existing = 0
+	}
+	newVal, err := math.DoArithmetic(existing, d, '+')
+	if err != nil {
+		return nil, err
+	}
 	c.values[key] = newVal
-	c.mu.Unlock()
-	return "", nil // have to return something to make it work with the Go templates
+	return newVal, nil
+}
+
+// SetIfAbsent stores value with the given key if the key is not set and
+// returns the value stored with the key.
+func (c *Scratch) SetIfAbsent(key string, value any) any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, found := c.values[key]; found {
+		return existing
+	}
+	c.values[key] = value
+	return value
 }
 
 // Set stores a value with the given key in the Node context.
@@ -143,14 +176,13 @@ func (c *Scratch) DeleteInMap(key string, mapKey string) string {
 // GetSortedMapValues returns a sorted map previously filled with SetInMap.
 func (c *Scratch) GetSortedMapValues(key string) any {
 	c.mu.RLock()
+	defer c.mu.RUnlock()
 
 	if c.values[key] == nil {
-		c.mu.RUnlock()
 		return nil
 	}
 
 	unsortedMap := c.values[key].(map[string]any)
-	c.mu.RUnlock()
 	var keys []string
 	for mapKey := range unsortedMap {
 		keys = append(keys, mapKey)
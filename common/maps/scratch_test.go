@@ -219,3 +219,76 @@ func BenchmarkScratchGet(b *testing.B) {
 		scratch.Get("A")
 	}
 }
+
+func TestScratchAddInParallel(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	var wg sync.WaitGroup
+	scratch := NewScratch()
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := scratch.Add("counter", 1); err != nil {
+					t.Errorf("Got err %s", err)
+				}
+				if _, err := scratch.Add("items", []any{j}); err != nil {
+					t.Errorf("Got err %s", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.Assert(scratch.Get("counter"), qt.Equals, int64(1000))
+	c.Assert(scratch.Get("items"), qt.HasLen, 1000)
+}
+
+func TestScratchIncr(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	var wg sync.WaitGroup
+	scratch := NewScratch()
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := scratch.Incr("counter"); err != nil {
+					t.Errorf("Got err %s", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.Assert(scratch.Get("counter"), qt.Equals, int64(1000))
+
+	v, err := scratch.Incr("counter", -10)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, int64(990))
+
+	v, err = scratch.Incr("sum", 2.5)
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, 2.5)
+
+	scratch.Set("name", "Hugo")
+	_, err = scratch.Incr("name")
+	c.Assert(err, qt.Not(qt.IsNil))
+	c.Assert(scratch.Get("name"), qt.Equals, "Hugo")
+}
+
+func TestScratchSetIfAbsent(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	scratch := NewScratch()
+	c.Assert(scratch.SetIfAbsent("key", "first"), qt.Equals, "first")
+	c.Assert(scratch.SetIfAbsent("key", "second"), qt.Equals, "first")
+	c.Assert(scratch.Get("key"), qt.Equals, "first")
+}
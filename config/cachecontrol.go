@@ -0,0 +1,153 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/gohugoio/hugo/common/types"
+	"github.com/mitchellh/mapstructure"
+)
+
+// The formats the cache control headers can be published in.
+const (
+	CacheControlFormatNetlify = "netlify"
+	CacheControlFormatVercel  = "vercel"
+)
+
+// DefaultCacheControl holds the default cache control configuration.
+var DefaultCacheControl = CacheControl{
+	Fingerprinted: "public, max-age=31536000, immutable",
+}
+
+// CacheControl configures the HTTP headers of the published resources and
+// static files, e.g.:
+//
+//	[cacheControl]
+//	formats = ["netlify"]
+//	[[cacheControl.rules]]
+//	for = "/images/**"
+//	cacheControl = "public, max-age=604800"
+//	[cacheControl.rules.headers]
+//	X-Content-Type-Options = "nosniff"
+type CacheControl struct {
+	// The hosting provider formats to publish the headers in, any of
+	// netlify (_headers) and vercel (vercel.json).
+	Formats []string
+
+	// The Cache-Control header of the fingerprinted resources, which takes
+	// precedence over the rules. Empty to only use the rules.
+	Fingerprinted string
+
+	Rules []CacheControlRule
+}
+
+// CacheControlRule sets the headers of the files matching a glob.
+type CacheControlRule struct {
+	// The glob matching the slash separated path relative to the publish
+	// dir, starting with a slash, e.g. "/images/**" or "/**.woff2".
+	For string
+
+	// The Cache-Control header.
+	CacheControl string
+
+	// Any other headers.
+	Headers map[string]string
+
+	g glob.Glob
+}
+
+// DecodeCacheControl decodes the cacheControl section in site config.
+func DecodeCacheControl(in map[string]any) (CacheControl, error) {
+	c := DefaultCacheControl
+	if in == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode cacheControl config: %w", err)
+	}
+
+	for i, f := range c.Formats {
+		f = strings.ToLower(f)
+		switch f {
+		case CacheControlFormatNetlify, CacheControlFormatVercel:
+		default:
+			return c, fmt.Errorf("invalid cacheControl format %q, must be one of netlify or vercel", f)
+		}
+		c.Formats[i] = f
+	}
+
+	for i, rule := range c.Rules {
+		if !strings.HasPrefix(rule.For, "/") {
+			return c, fmt.Errorf("cacheControl for %q must start with a slash", rule.For)
+		}
+		g, err := glob.Compile(rule.For, '/')
+		if err != nil {
+			return c, fmt.Errorf("cacheControl for %q: %w", rule.For, err)
+		}
+		rule.g = g
+		c.Rules[i] = rule
+	}
+
+	return c, nil
+}
+
+// IsEnabled returns whether the headers should be published in any format.
+func (c CacheControl) IsEnabled() bool {
+	return len(c.Formats) > 0
+}
+
+// HasFormat returns whether the headers should be published in format f.
+func (c CacheControl) HasFormat(f string) bool {
+	return contains(c.Formats, f)
+}
+
+// MatchHeaders returns the headers of the file published at path, sorted by
+// key. The later rules win over the earlier ones.
+func (c CacheControl) MatchHeaders(path string, fingerprinted bool) []types.KeyValueStr {
+	headers := make(map[string]string)
+	for _, rule := range c.Rules {
+		if rule.g == nil || !rule.g.Match(path) {
+			continue
+		}
+		for k, v := range rule.Headers {
+			headers[http.CanonicalHeaderKey(k)] = v
+		}
+		if rule.CacheControl != "" {
+			headers["Cache-Control"] = rule.CacheControl
+		}
+	}
+	if fingerprinted && c.Fingerprinted != "" {
+		headers["Cache-Control"] = c.Fingerprinted
+	}
+
+	if len(headers) == 0 {
+		return nil
+	}
+
+	matches := make([]types.KeyValueStr, 0, len(headers))
+	for k, v := range headers {
+		matches = append(matches, types.KeyValueStr{Key: k, Value: v})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Key < matches[j].Key
+	})
+
+	return matches
+}
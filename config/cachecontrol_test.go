@@ -0,0 +1,53 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/types"
+)
+
+func TestDecodeCacheControl(t *testing.T) {
+	c := qt.New(t)
+
+	cc, err := DecodeCacheControl(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(cc.IsEnabled(), qt.IsFalse)
+	c.Assert(cc.MatchHeaders("/css/main.123.css", true), qt.DeepEquals, []types.KeyValueStr{{Key: "Cache-Control", Value: "public, max-age=31536000, immutable"}})
+	c.Assert(cc.MatchHeaders("/css/main.css", false), qt.IsNil)
+
+	cc, err = DecodeCacheControl(map[string]any{
+		"formats": []any{"Netlify"},
+		"rules": []any{
+			map[string]any{"for": "/**", "cacheControl": "no-cache", "headers": map[string]any{"x-frame-options": "DENY"}},
+			map[string]any{"for": "/images/*", "cacheControl": "max-age=60"},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(cc.HasFormat(CacheControlFormatNetlify), qt.IsTrue)
+	c.Assert(cc.MatchHeaders("/images/a.png", false), qt.DeepEquals, []types.KeyValueStr{
+		{Key: "Cache-Control", Value: "max-age=60"},
+		{Key: "X-Frame-Options", Value: "DENY"},
+	})
+	c.Assert(cc.MatchHeaders("/images/sub/a.png", false)[0].Value, qt.Equals, "no-cache")
+	c.Assert(cc.MatchHeaders("/images/a.123.png", true)[0].Value, qt.Equals, "public, max-age=31536000, immutable")
+
+	_, err = DecodeCacheControl(map[string]any{"formats": []any{"apache"}})
+	c.Assert(err, qt.ErrorMatches, `invalid cacheControl format "apache".*`)
+
+	_, err = DecodeCacheControl(map[string]any{"rules": []any{map[string]any{"for": "images/*"}}})
+	c.Assert(err, qt.ErrorMatches, `.*must start with a slash`)
+}
@@ -41,9 +41,18 @@ type Build struct {
 	// related aggregated data (e.g. CSS class names).
 	WriteStats bool
 
+	// When enabled, will write a build-manifest.json with all the published
+	// files, their sources and content hashes.
+	WriteManifest bool
+
 	// Can be used to toggle off writing of the intellinsense /assets/jsconfig.js
 	// file.
 	NoJSConfigInAssets bool
+
+	// When enabled, PostCSS and Babel run in one Node.js process kept
+	// running for the build or server session, instead of one process per
+	// resource. Requires node to be allowed in security.exec.allow.
+	UseNodeDaemon bool
 }
 
 func (b Build) UseResourceCache(err error) bool {
@@ -84,6 +93,9 @@ type Sitemap struct {
 	ChangeFreq string
 	Priority   float64
 	Filename   string
+
+	// Whether to exclude the page from the sitemap.
+	Disable bool
 }
 
 func DecodeSitemap(prototype Sitemap, input map[string]any) Sitemap {
@@ -95,6 +107,8 @@ func DecodeSitemap(prototype Sitemap, input map[string]any) Sitemap {
 			prototype.Priority = cast.ToFloat64(value)
 		case "filename":
 			prototype.Filename = cast.ToString(value)
+		case "disable":
+			prototype.Disable = cast.ToBool(value)
 		default:
 			jww.WARN.Printf("Unknown Sitemap field: %s\n", key)
 		}
@@ -108,6 +122,10 @@ type Server struct {
 	Headers   []Headers
 	Redirects []Redirect
 
+	// Whether to ignore the hosting provider files in the publish dir,
+	// see DefaultDeployFiles.
+	DisableDeployFiles bool
+
 	compiledInit      sync.Once
 	compiledHeaders   []glob.Glob
 	compiledRedirects []glob.Glob
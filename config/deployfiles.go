@@ -0,0 +1,268 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/types"
+)
+
+// DefaultDeployFiles are the hosting provider files in the publish dir
+// the Hugo server reads headers and redirects from.
+var DefaultDeployFiles = []string{"_headers", "_redirects", "vercel.json"}
+
+// DeployRules are the headers and redirects read from hosting provider
+// files, e.g. Netlify's _headers and _redirects, to simulate them in the
+// Hugo server.
+type DeployRules struct {
+	Headers   []DeployHeaders
+	Redirects []RedirectRule
+}
+
+// DeployHeaders are the headers to set for the paths matching For.
+type DeployHeaders struct {
+	// The path pattern, with the same syntax as RedirectRule.From.
+	For    string
+	Values []types.KeyValueStr
+
+	re *regexp.Regexp
+}
+
+// MatchHeaders returns the headers for the paths matching path.
+func (r DeployRules) MatchHeaders(path string) []types.KeyValueStr {
+	var matches []types.KeyValueStr
+	for _, h := range r.Headers {
+		if h.re.MatchString(path) {
+			matches = append(matches, h.Values...)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Key < matches[j].Key
+	})
+
+	return matches
+}
+
+// MatchRedirect returns the first redirect rule matching path and its target.
+func (r DeployRules) MatchRedirect(path, rawQuery string) (RedirectRule, string, bool) {
+	return Redirects{Rules: r.Redirects}.Match(path, rawQuery)
+}
+
+// Merge appends the rules in other to r.
+func (r *DeployRules) Merge(other DeployRules) {
+	r.Headers = append(r.Headers, other.Headers...)
+	r.Redirects = append(r.Redirects, other.Redirects...)
+}
+
+// ParseDeployFile parses the hosting provider file filename, detected by its name,
+// one of Netlify's _headers and _redirects or vercel.json.
+func ParseDeployFile(filename string, r io.Reader) (DeployRules, error) {
+	base := path.Base(filename)
+	var (
+		rules DeployRules
+		err   error
+	)
+	switch {
+	case base == "_headers":
+		rules, err = parseNetlifyHeaders(r)
+	case base == "_redirects":
+		rules, err = parseNetlifyRedirects(r)
+	case base == "vercel.json":
+		rules, err = parseVercelConfig(r)
+	default:
+		return rules, fmt.Errorf("unsupported deploy file %q", filename)
+	}
+	if err != nil {
+		return rules, fmt.Errorf("failed to parse %q: %w", filename, err)
+	}
+	return rules, nil
+}
+
+func parseNetlifyHeaders(r io.Reader) (DeployRules, error) {
+	var (
+		rules   DeployRules
+		current *DeployHeaders
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if line == trimmed {
+			// A path, followed by indented headers.
+			rules.Headers = append(rules.Headers, newDeployHeaders(trimmed))
+			current = &rules.Headers[len(rules.Headers)-1]
+			continue
+		}
+
+		if current == nil {
+			return rules, fmt.Errorf("header %q without a path", trimmed)
+		}
+
+		k, v, found := strings.Cut(trimmed, ":")
+		if !found {
+			return rules, fmt.Errorf("invalid header %q", trimmed)
+		}
+		current.Values = append(current.Values, types.KeyValueStr{Key: strings.TrimSpace(k), Value: strings.TrimSpace(v)})
+	}
+
+	return rules, scanner.Err()
+}
+
+func parseNetlifyRedirects(r io.Reader) (DeployRules, error) {
+	var rules DeployRules
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 2 {
+			return rules, fmt.Errorf("invalid redirect %q", scanner.Text())
+		}
+		if !strings.HasPrefix(fields[0], "/") {
+			// Domain level redirects can not be simulated.
+			continue
+		}
+
+		rule := RedirectRule{From: fields[0], To: fields[1], Status: http.StatusMovedPermanently}
+
+		// The status is optional, and may be followed by conditions
+		// and query params we don't support.
+		for _, f := range fields[2:] {
+			s := strings.TrimSuffix(f, "!")
+			if status, err := strconv.Atoi(s); err == nil {
+				rule.Status = status
+				rule.Force = s != f
+				break
+			}
+		}
+		if !isRedirectStatus(rule.Status) && !rule.IsErrorPage() {
+			return rules, fmt.Errorf("redirect from %q: unsupported status %d", rule.From, rule.Status)
+		}
+
+		rules.Redirects = append(rules.Redirects, rule)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return rules, err
+	}
+
+	return rules, compileDeployRedirects(rules.Redirects)
+}
+
+var vercelSplatRe = regexp.MustCompile(`(:[a-zA-Z_][a-zA-Z0-9_]*\*|\(\.\*\))$`)
+
+func parseVercelConfig(r io.Reader) (DeployRules, error) {
+	type route struct {
+		Source      string
+		Destination string
+		Permanent   *bool
+		StatusCode  int
+		Headers     []struct {
+			Key   string
+			Value string
+		}
+	}
+
+	var cfg struct {
+		Headers   []route
+		Redirects []route
+		Rewrites  []route
+	}
+
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return DeployRules{}, err
+	}
+
+	// Vercel names the trailing wildcard, e.g. :path*, or uses (.*).
+	convert := func(rt route) (string, string) {
+		from, to := rt.Source, rt.Destination
+		if m := vercelSplatRe.FindString(from); m != "" {
+			from = strings.TrimSuffix(from, m) + "*"
+			if strings.HasPrefix(m, ":") {
+				name := strings.TrimSuffix(m, "*")
+				to = strings.ReplaceAll(strings.ReplaceAll(to, m, ":splat"), name, ":splat")
+			} else {
+				to = strings.ReplaceAll(to, "$1", ":splat")
+			}
+		}
+		return from, to
+	}
+
+	var rules DeployRules
+
+	for _, rt := range cfg.Headers {
+		from, _ := convert(rt)
+		h := newDeployHeaders(from)
+		for _, kv := range rt.Headers {
+			h.Values = append(h.Values, types.KeyValueStr{Key: kv.Key, Value: kv.Value})
+		}
+		rules.Headers = append(rules.Headers, h)
+	}
+
+	for _, rt := range cfg.Redirects {
+		from, to := convert(rt)
+		status := rt.StatusCode
+		if status == 0 {
+			// Vercel defaults to a permanent redirect.
+			status = http.StatusPermanentRedirect
+			if rt.Permanent != nil && !*rt.Permanent {
+				status = http.StatusTemporaryRedirect
+			}
+		}
+		// Vercel redirects even if a file exists at the path.
+		rules.Redirects = append(rules.Redirects, RedirectRule{From: from, To: to, Status: status, Force: true})
+	}
+
+	for _, rt := range cfg.Rewrites {
+		from, to := convert(rt)
+		// Vercel only rewrites if no file exists at the path.
+		rules.Redirects = append(rules.Redirects, RedirectRule{From: from, To: to, Status: http.StatusOK})
+	}
+
+	return rules, compileDeployRedirects(rules.Redirects)
+}
+
+func newDeployHeaders(pattern string) DeployHeaders {
+	re, _ := compileRedirectPattern(pattern)
+	return DeployHeaders{For: pattern, re: re}
+}
+
+func compileDeployRedirects(rules []RedirectRule) error {
+	for i, rule := range rules {
+		if !strings.HasPrefix(rule.From, "/") {
+			return fmt.Errorf("redirect from %q must start with a slash", rule.From)
+		}
+		rule.re, rule.params = compileRedirectPattern(rule.From)
+		rules[i] = rule
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/types"
+)
+
+func TestParseDeployFile(t *testing.T) {
+	c := qt.New(t)
+
+	var rules DeployRules
+
+	r, err := ParseDeployFile("public/_headers", strings.NewReader(`
+# A comment.
+/*
+  X-Frame-Options: DENY
+/docs/*
+  Cache-Control: max-age=3600
+  X-Robots-Tag: noindex
+`))
+	c.Assert(err, qt.IsNil)
+	rules.Merge(r)
+
+	r, err = ParseDeployFile("public/_redirects", strings.NewReader(`
+/old/*      /new/:splat
+/blog/:year/:slug  /posts/:year/:slug/  302!
+/app/*      /app/index.html  200
+/gone/*     /410.html  410
+/docs/*     /docs/404.html  404
+https://old.example.org/* https://example.org/:splat 301!
+`))
+	c.Assert(err, qt.IsNil)
+	rules.Merge(r)
+
+	r, err = ParseDeployFile("public/vercel.json", strings.NewReader(`{
+  "redirects": [
+    { "source": "/v/:path*", "destination": "/vercel/:path*", "permanent": false }
+  ],
+  "rewrites": [
+    { "source": "/api/(.*)", "destination": "/api.html" }
+  ]
+}`))
+	c.Assert(err, qt.IsNil)
+	rules.Merge(r)
+
+	c.Assert(rules.MatchHeaders("/docs/intro/"), qt.DeepEquals, []types.KeyValueStr{
+		{Key: "Cache-Control", Value: "max-age=3600"},
+		{Key: "X-Frame-Options", Value: "DENY"},
+		{Key: "X-Robots-Tag", Value: "noindex"},
+	})
+	c.Assert(rules.MatchHeaders("/"), qt.DeepEquals, []types.KeyValueStr{{Key: "X-Frame-Options", Value: "DENY"}})
+
+	rule, to, found := rules.MatchRedirect("/old/a/b/", "")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.Status, qt.Equals, 301)
+	c.Assert(rule.Force, qt.IsFalse)
+	c.Assert(to, qt.Equals, "/new/a/b/")
+
+	rule, to, found = rules.MatchRedirect("/blog/2022/hugo", "")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.Status, qt.Equals, 302)
+	c.Assert(rule.Force, qt.IsTrue)
+	c.Assert(to, qt.Equals, "/posts/2022/hugo/")
+
+	rule, to, found = rules.MatchRedirect("/app/route", "")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.IsRewrite(), qt.IsTrue)
+	c.Assert(to, qt.Equals, "/app/index.html")
+
+	rule, to, found = rules.MatchRedirect("/v/a/b", "")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.Status, qt.Equals, 307)
+	c.Assert(to, qt.Equals, "/vercel/a/b")
+
+	rule, to, found = rules.MatchRedirect("/api/users", "")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.IsRewrite(), qt.IsTrue)
+	c.Assert(to, qt.Equals, "/api.html")
+
+	rule, to, found = rules.MatchRedirect("/gone/page/", "")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.IsErrorPage(), qt.IsTrue)
+	c.Assert(rule.Status, qt.Equals, 410)
+	c.Assert(to, qt.Equals, "/410.html")
+
+	rule, to, found = rules.MatchRedirect("/docs/missing/", "")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.IsErrorPage(), qt.IsTrue)
+	c.Assert(rule.IsRewrite(), qt.IsFalse)
+	c.Assert(to, qt.Equals, "/docs/404.html")
+
+	_, err = ParseDeployFile("public/_redirects", strings.NewReader("/a /b 500\n"))
+	c.Assert(err, qt.ErrorMatches, `.*redirect from "/a": unsupported status 500`)
+	_, err = ParseDeployFile("public/_headers", strings.NewReader("  X-Foo: bar\n"))
+	c.Assert(err, qt.Not(qt.IsNil))
+	_, err = ParseDeployFile("public/netlify.toml", strings.NewReader(""))
+	c.Assert(err, qt.Not(qt.IsNil))
+}
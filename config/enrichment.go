@@ -0,0 +1,79 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Enrichment configures the source of the computed params added to the
+// pages after their content is read, e.g.:
+//
+//	[enrichment]
+//	command = "node"
+//	args = ["scripts/enrich.js"]
+//
+// The command must be allowed in security.exec.allow, the URL in
+// security.http.urls.
+type Enrichment struct {
+	// The command to run for each page. It gets the page as JSON on stdin
+	// and writes the params as a JSON object to stdout.
+	Command string
+
+	// The arguments to Command.
+	Args []string
+
+	// The HTTP endpoint to POST the page as JSON to, responding with the
+	// params as a JSON object.
+	URL string
+}
+
+// IsZero returns whether no enrichment source is configured.
+func (c Enrichment) IsZero() bool {
+	return c.Command == "" && c.URL == ""
+}
+
+// Key returns a key identifying the source, used to cache its results.
+func (c Enrichment) Key() string {
+	if c.URL != "" {
+		return c.URL
+	}
+	return c.Command + " " + strings.Join(c.Args, " ")
+}
+
+// DecodeEnrichment decodes the enrichment section in site config.
+func DecodeEnrichment(in map[string]any) (Enrichment, error) {
+	var c Enrichment
+	if in == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode enrichment config: %w", err)
+	}
+
+	if c.Command != "" && c.URL != "" {
+		return c, errors.New("invalid enrichment config: set either command or url, not both")
+	}
+
+	if len(c.Args) > 0 && c.Command == "" {
+		return c, errors.New("invalid enrichment config: args set without a command")
+	}
+
+	return c, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// The formats the error page wiring can be published in.
+const (
+	ErrorPagesFormatNetlify    = "netlify"
+	ErrorPagesFormatCloudFront = "cloudfront"
+)
+
+// DefaultErrorPages holds the default error pages configuration.
+var DefaultErrorPages = ErrorPages{
+	Codes: []int{404},
+}
+
+// ErrorPages configures the error pages to render, e.g.:
+//
+//	[errorPages]
+//	codes = [404, 410, 500]
+//	sections = true
+//	formats = ["netlify"]
+type ErrorPages struct {
+	// The HTTP status codes to render an error page for, e.g. 404.html.
+	Codes []int
+
+	// Whether to also render the error pages for each top level section,
+	// e.g. docs/404.html.
+	Sections bool
+
+	// The hosting provider formats to publish the wiring of the error pages
+	// in, any of netlify (in _redirects) and cloudfront.
+	// Vercel only serves the 404.html in the root, which needs no wiring,
+	// so there is no vercel format.
+	Formats []string
+}
+
+// DecodeErrorPages decodes the errorPages section in site config.
+func DecodeErrorPages(in map[string]any) (ErrorPages, error) {
+	c := DefaultErrorPages
+	if in == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode errorPages config: %w", err)
+	}
+
+	for _, code := range c.Codes {
+		if code < 400 || code > 599 {
+			return c, fmt.Errorf("invalid error page code %d, must be in the range 400-599", code)
+		}
+	}
+
+	for i, f := range c.Formats {
+		f = strings.ToLower(f)
+		switch f {
+		case ErrorPagesFormatNetlify, ErrorPagesFormatCloudFront:
+		default:
+			return c, fmt.Errorf("invalid errorPages format %q, must be one of netlify or cloudfront", f)
+		}
+		c.Formats[i] = f
+	}
+
+	return c, nil
+}
+
+// HasFormat returns whether the error page wiring should be published in format f.
+func (c ErrorPages) HasFormat(f string) bool {
+	return contains(c.Formats, f)
+}
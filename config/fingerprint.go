@@ -0,0 +1,89 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Where the hash is put in the fingerprinted filenames.
+const (
+	// E.g. main.1a2b3c.css.
+	FingerprintPositionSuffix = "suffix"
+	// E.g. 1a2b3c.main.css.
+	FingerprintPositionPrefix = "prefix"
+	// E.g. 1a2b3c/main.css.
+	FingerprintPositionDir = "dir"
+)
+
+// DefaultFingerprint holds the default fingerprint configuration.
+var DefaultFingerprint = Fingerprint{
+	Algorithm: "sha256",
+	Position:  FingerprintPositionSuffix,
+}
+
+// Fingerprint configures resources.Fingerprint, e.g.:
+//
+//	[fingerprint]
+//	algorithm = "sha384"
+//	length = 12
+//	position = "prefix"
+//	renameMap = "fingerprints.json"
+type Fingerprint struct {
+	// The hash algorithm used when none is given to resources.Fingerprint,
+	// one of md5, sha256, sha384 or sha512.
+	Algorithm string
+
+	// The number of hex characters of the hash in the filenames, 0 for all.
+	// The Subresource Integrity hash is never shortened.
+	Length int
+
+	// Where to put the hash in the filenames, one of suffix, prefix or dir.
+	Position string
+
+	// The filename, relative to the publish dir, of the JSON map of the
+	// filenames of previous builds to the current fingerprinted filenames.
+	// Empty to disable.
+	RenameMap string
+}
+
+// DecodeFingerprint decodes the fingerprint section in site config.
+func DecodeFingerprint(in map[string]any) (Fingerprint, error) {
+	c := DefaultFingerprint
+	if in == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode fingerprint config: %w", err)
+	}
+
+	c.Algorithm = strings.ToLower(c.Algorithm)
+	c.Position = strings.ToLower(c.Position)
+
+	switch c.Position {
+	case FingerprintPositionSuffix, FingerprintPositionPrefix, FingerprintPositionDir:
+	default:
+		return c, fmt.Errorf("invalid fingerprint position %q, must be one of suffix, prefix or dir", c.Position)
+	}
+
+	if c.Length < 0 {
+		return c, fmt.Errorf("invalid fingerprint length %d", c.Length)
+	}
+
+	return c, nil
+}
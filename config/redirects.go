@@ -0,0 +1,240 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// The formats the redirects can be published in.
+const (
+	RedirectsFormatNetlify = "netlify"
+	RedirectsFormatVercel  = "vercel"
+	RedirectsFormatNginx   = "nginx"
+)
+
+// Redirects configures the site's redirects and rewrites, e.g.:
+//
+//	[redirects]
+//	formats = ["netlify"]
+//	[[redirects.rules]]
+//	from = "/blog/:year/*"
+//	to = "/posts/:year/:splat"
+//	status = 301
+//
+// Unlike page aliases, these are published in the hosting provider's format
+// and honored by the Hugo server.
+type Redirects struct {
+	// The formats to publish the rules in, any of netlify, vercel and nginx.
+	Formats []string
+
+	Rules []RedirectRule
+}
+
+// RedirectRule is a redirect, or a rewrite if Status is 200.
+type RedirectRule struct {
+	// The path to match. A path segment starting with a colon, e.g. ":year",
+	// matches any one segment, and a trailing "*" matches the rest of the path.
+	From string
+
+	// The target path or URL, where ":year" and ":splat" are replaced with
+	// the matched values.
+	To string
+
+	// The HTTP status code. Defaults to 301.
+	Status int
+
+	// Whether to append the request's query string to the target.
+	PreserveQuery bool
+
+	// Whether to redirect even if a file exists at the path.
+	Force bool
+
+	re     *regexp.Regexp
+	params []string
+}
+
+// NewRedirectRule creates a redirect rule from a path to a target.
+func NewRedirectRule(from, to string, status int) RedirectRule {
+	r := RedirectRule{From: from, To: to, Status: status}
+	r.re, r.params = compileRedirectPattern(from)
+	return r
+}
+
+// IsRewrite returns whether this is a rewrite, i.e. the content at the
+// target is served without changing the URL.
+func (r RedirectRule) IsRewrite() bool {
+	return r.Status == http.StatusOK
+}
+
+// IsErrorPage returns whether the content at the target is served with
+// Status without changing the URL, e.g. a custom 404 page.
+func (r RedirectRule) IsErrorPage() bool {
+	return r.Status == http.StatusNotFound || r.Status == http.StatusGone
+}
+
+// Params returns the names of the placeholders in From, in order,
+// with the trailing "*" named splat.
+func (r RedirectRule) Params() []string {
+	return r.params
+}
+
+// RegexpSource returns the anchored regular expression matching From,
+// with one capturing group per param.
+func (r RedirectRule) RegexpSource() string {
+	return r.re.String()
+}
+
+// Match returns the target for path and true if path matches this rule.
+func (r RedirectRule) Match(path, rawQuery string) (string, bool) {
+	m := r.re.FindStringSubmatch(path)
+	if m == nil {
+		return "", false
+	}
+
+	// Replace the longest names first, e.g. :years before :year.
+	idx := make([]int, len(r.params))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return len(r.params[idx[i]]) > len(r.params[idx[j]])
+	})
+
+	to := r.To
+	for _, i := range idx {
+		to = strings.ReplaceAll(to, ":"+r.params[i], m[i+1])
+	}
+
+	if r.PreserveQuery && rawQuery != "" {
+		if strings.Contains(to, "?") {
+			to += "&" + rawQuery
+		} else {
+			to += "?" + rawQuery
+		}
+	}
+
+	return to, true
+}
+
+// DecodeRedirects decodes the redirects section in site config.
+func DecodeRedirects(in map[string]any) (Redirects, error) {
+	var r Redirects
+	if in == nil {
+		return r, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &r); err != nil {
+		return r, fmt.Errorf("failed to decode redirects config: %w", err)
+	}
+
+	for i, f := range r.Formats {
+		f = strings.ToLower(f)
+		switch f {
+		case RedirectsFormatNetlify, RedirectsFormatVercel, RedirectsFormatNginx:
+		default:
+			return r, fmt.Errorf("invalid redirects format %q, must be one of netlify, vercel or nginx", f)
+		}
+		r.Formats[i] = f
+	}
+
+	for i, rule := range r.Rules {
+		if !strings.HasPrefix(rule.From, "/") {
+			return r, fmt.Errorf("redirect from %q must start with a slash", rule.From)
+		}
+		if rule.To == "" {
+			return r, fmt.Errorf("redirect from %q: to must be set", rule.From)
+		}
+		if rule.Status == 0 {
+			rule.Status = http.StatusMovedPermanently
+		}
+		if !isRedirectStatus(rule.Status) {
+			return r, fmt.Errorf("redirect from %q: unsupported status %d", rule.From, rule.Status)
+		}
+		rule.re, rule.params = compileRedirectPattern(rule.From)
+		r.Rules[i] = rule
+	}
+
+	return r, nil
+}
+
+// Match returns the first rule matching path and its target.
+func (r Redirects) Match(path, rawQuery string) (RedirectRule, string, bool) {
+	path = strings.TrimSuffix(path, "index.html")
+	for _, rule := range r.Rules {
+		if to, ok := rule.Match(path, rawQuery); ok {
+			if to == path {
+				// No redirect to self.
+				return RedirectRule{}, "", false
+			}
+			return rule, to, true
+		}
+	}
+	return RedirectRule{}, "", false
+}
+
+// HasFormat returns whether the redirects should be published in format f.
+func (r Redirects) HasFormat(f string) bool {
+	return contains(r.Formats, f)
+}
+
+// isRedirectStatus returns whether status is supported in a redirect rule,
+// a redirect or a rewrite.
+func isRedirectStatus(status int) bool {
+	switch status {
+	case http.StatusOK, http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+func compileRedirectPattern(from string) (*regexp.Regexp, []string) {
+	var (
+		b      strings.Builder
+		params []string
+	)
+
+	b.WriteString("^")
+
+	splat := strings.HasSuffix(from, "*")
+	from = strings.TrimSuffix(from, "*")
+
+	for i, seg := range strings.Split(from, "/") {
+		if i > 0 {
+			b.WriteString("/")
+		}
+		if strings.HasPrefix(seg, ":") && len(seg) > 1 {
+			params = append(params, seg[1:])
+			b.WriteString("([^/]+)")
+		} else {
+			b.WriteString(regexp.QuoteMeta(seg))
+		}
+	}
+
+	if splat {
+		params = append(params, "splat")
+		b.WriteString("(.*)")
+	}
+
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String()), params
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeRedirects(t *testing.T) {
+	c := qt.New(t)
+
+	r, err := DecodeRedirects(map[string]any{
+		"formats": []any{"Netlify"},
+		"rules": []any{
+			map[string]any{"from": "/blog/:year/:years/*", "to": "/posts/:years/:year/:splat"},
+			map[string]any{"from": "/search", "to": "/find/", "status": 302, "preserveQuery": true},
+			map[string]any{"from": "/app/*", "to": "/app/index.html", "status": 200},
+			map[string]any{"from": "/self/", "to": "/self/"},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(r.HasFormat(RedirectsFormatNetlify), qt.IsTrue)
+	c.Assert(r.Rules[0].Status, qt.Equals, 301)
+	c.Assert(r.Rules[0].Params(), qt.DeepEquals, []string{"year", "years", "splat"})
+
+	rule, to, found := r.Match("/blog/2022/a/my/post/index.html", "")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.From, qt.Equals, "/blog/:year/:years/*")
+	c.Assert(to, qt.Equals, "/posts/a/2022/my/post/")
+
+	rule, to, found = r.Match("/search", "q=hugo")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.Status, qt.Equals, 302)
+	c.Assert(to, qt.Equals, "/find/?q=hugo")
+
+	rule, to, found = r.Match("/app/some/route", "")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(rule.IsRewrite(), qt.IsTrue)
+	c.Assert(to, qt.Equals, "/app/index.html")
+
+	_, _, found = r.Match("/self/", "")
+	c.Assert(found, qt.IsFalse)
+	_, _, found = r.Match("/blog/2022/", "")
+	c.Assert(found, qt.IsFalse)
+
+	_, err = DecodeRedirects(map[string]any{"formats": []any{"foo"}})
+	c.Assert(err, qt.Not(qt.IsNil))
+	_, err = DecodeRedirects(map[string]any{"rules": []any{map[string]any{"from": "/a", "to": "/b", "status": 404}}})
+	c.Assert(err, qt.Not(qt.IsNil))
+	_, err = DecodeRedirects(map[string]any{"rules": []any{map[string]any{"from": "a", "to": "/b"}}})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
@@ -0,0 +1,185 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/mitchellh/mapstructure"
+)
+
+// robotsTextDefault is the robots.txt content when no rules or sitemaps apply.
+const robotsTextDefault = "User-agent: *"
+
+// Robots configures the robots.txt file, e.g.:
+//
+//	[robots]
+//	sitemap = true
+//	disallowEnvironments = ["staging"]
+//	disallowBaseURLs = ["*.netlify.app"]
+//	[[robots.rules]]
+//	userAgent = "*"
+//	disallow = ["/private/"]
+//	crawlDelay = 10
+//	environments = ["production"]
+type Robots struct {
+	// The rule groups, in order.
+	Rules []RobotsRule
+
+	// Whether to add links to the sitemaps of all languages.
+	Sitemap bool
+
+	// Disallow all crawling in these environments.
+	DisallowEnvironments []string
+
+	// Disallow all crawling when the host in baseURL matches any of these
+	// glob patterns, e.g. "*.netlify.app" for preview deploys.
+	DisallowBaseURLs []string
+
+	disallowBaseURLs []glob.Glob
+}
+
+// RobotsRule is a group of rules for one or more user agents.
+type RobotsRule struct {
+	// The user agents, defaults to "*".
+	UserAgent []string
+
+	Allow    []string
+	Disallow []string
+
+	// The crawl delay in seconds, 0 means not set.
+	CrawlDelay int
+
+	// The environments to apply this rule in. Empty means all.
+	Environments []string
+}
+
+// DecodeRobots decodes the robots section in site config.
+func DecodeRobots(in map[string]any) (Robots, error) {
+	var r Robots
+	if in == nil {
+		return r, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &r); err != nil {
+		return r, fmt.Errorf("failed to decode robots config: %w", err)
+	}
+
+	for _, pattern := range r.DisallowBaseURLs {
+		g, err := glob.Compile(strings.ToLower(pattern))
+		if err != nil {
+			return r, fmt.Errorf("failed to compile robots.disallowBaseURLs pattern %q: %w", pattern, err)
+		}
+		r.disallowBaseURLs = append(r.disallowBaseURLs, g)
+	}
+
+	return r, nil
+}
+
+// DisallowAll returns whether all crawling should be disallowed for the
+// given environment and base URL.
+func (r Robots) DisallowAll(environment, baseURL string) bool {
+	if contains(r.DisallowEnvironments, environment) {
+		return true
+	}
+
+	if len(r.disallowBaseURLs) == 0 {
+		return false
+	}
+
+	host := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	host = strings.ToLower(host)
+
+	for _, g := range r.disallowBaseURLs {
+		if g.Match(host) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Text creates the robots.txt content for the given environment and base URL.
+// The sitemaps are only added if Sitemap is enabled.
+func (r Robots) Text(environment, baseURL string, sitemaps []string) string {
+	var b strings.Builder
+
+	if r.DisallowAll(environment, baseURL) {
+		b.WriteString("User-agent: *\nDisallow: /\n")
+		return b.String()
+	}
+
+	var rules []RobotsRule
+	for _, rule := range r.Rules {
+		if len(rule.Environments) == 0 || contains(rule.Environments, environment) {
+			rules = append(rules, rule)
+		}
+	}
+
+	addSitemaps := r.Sitemap && len(sitemaps) > 0
+
+	if len(rules) == 0 {
+		if !addSitemaps {
+			return robotsTextDefault
+		}
+		// Allow all.
+		rules = []RobotsRule{{}}
+	}
+
+	for i, rule := range rules {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		userAgents := rule.UserAgent
+		if len(userAgents) == 0 {
+			userAgents = []string{"*"}
+		}
+		for _, ua := range userAgents {
+			fmt.Fprintf(&b, "User-agent: %s\n", ua)
+		}
+		if rule.CrawlDelay > 0 {
+			fmt.Fprintf(&b, "Crawl-delay: %d\n", rule.CrawlDelay)
+		}
+		for _, v := range rule.Allow {
+			fmt.Fprintf(&b, "Allow: %s\n", v)
+		}
+		for _, v := range rule.Disallow {
+			fmt.Fprintf(&b, "Disallow: %s\n", v)
+		}
+	}
+
+	if addSitemaps {
+		b.WriteString("\n")
+		for _, v := range sitemaps {
+			fmt.Fprintf(&b, "Sitemap: %s\n", v)
+		}
+	}
+
+	return b.String()
+}
+
+func contains(s []string, v string) bool {
+	for _, vv := range s {
+		if vv == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeRobots(t *testing.T) {
+	c := qt.New(t)
+
+	r, err := DecodeRobots(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(r.Text("production", "https://example.org/", []string{"https://example.org/sitemap.xml"}), qt.Equals, "User-agent: *")
+
+	r, err = DecodeRobots(map[string]any{
+		"sitemap":          true,
+		"disallowBaseURLs": []any{"staging.*", "*.netlify.app"},
+		"rules": []any{
+			map[string]any{"userAgent": "Googlebot", "allow": "/"},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(r.DisallowAll("production", "https://staging.example.org/"), qt.IsTrue)
+	c.Assert(r.DisallowAll("production", "https://Foo.netlify.app/docs/"), qt.IsTrue)
+	c.Assert(r.DisallowAll("production", "https://example.org/"), qt.IsFalse)
+	c.Assert(r.Text("production", "https://example.org/", []string{"https://example.org/sitemap.xml"}), qt.Equals,
+		"User-agent: Googlebot\nAllow: /\n\nSitemap: https://example.org/sitemap.xml\n")
+
+	r, err = DecodeRobots(map[string]any{"sitemap": true})
+	c.Assert(err, qt.IsNil)
+	c.Assert(r.Text("production", "https://example.org/", []string{"https://example.org/sitemap.xml"}), qt.Equals,
+		"User-agent: *\n\nSitemap: https://example.org/sitemap.xml\n")
+
+	_, err = DecodeRobots(map[string]any{"disallowBaseURLs": []any{"[a-"}})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
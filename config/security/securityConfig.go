@@ -45,12 +45,14 @@ var DefaultConfig = Config{
 		OsEnv: NewWhitelist("(?i)^(PATH|PATHEXT|APPDATA|TMP|TEMP|TERM)$"),
 	},
 	Funcs: Funcs{
-		Getenv: NewWhitelist("^HUGO_"),
+		Getenv:  NewWhitelist("^HUGO_"),
+		Plugins: NewWhitelist(),
 	},
 	HTTP: HTTP{
 		URLs:    NewWhitelist(".*"),
 		Methods: NewWhitelist("(?i)GET|POST"),
 	},
+	Symlinks: NewWhitelist(),
 }
 
 // Config is the top level security config.
@@ -64,6 +66,11 @@ type Config struct {
 	// Restricts access to resources.Get, getJSON, getCSV.
 	HTTP HTTP `json:"http"`
 
+	// Symlinks to allow in themes and modules, and symlinked directories to
+	// allow in static, matched against their path relative to the project
+	// or module root, e.g. "^content/shared$".
+	Symlinks Whitelist `json:"symlinks"`
+
 	// Allow inline shortcodes
 	EnableInlineShortcodes bool `json:"enableInlineShortcodes"`
 }
@@ -78,6 +85,10 @@ type Exec struct {
 type Funcs struct {
 	// OS env keys allowed to query in os.Getenv.
 	Getenv Whitelist `json:"getenv"`
+
+	// Template func namespaces modules are allowed to provide or override
+	// with plugins, e.g. "^sci$".
+	Plugins Whitelist `json:"plugins"`
 }
 
 type HTTP struct {
@@ -124,6 +135,17 @@ func (c Config) CheckAllowedGetEnv(name string) error {
 	return nil
 }
 
+func (c Config) CheckAllowedFuncsPlugin(namespace string) error {
+	if !c.Funcs.Plugins.Accept(namespace) {
+		return &AccessDeniedError{
+			name:     namespace,
+			path:     "security.funcs.plugins",
+			policies: c.ToTOML(),
+		}
+	}
+	return nil
+}
+
 func (c Config) CheckAllowedHTTPURL(url string) error {
 	if !c.HTTP.URLs.Accept(url) {
 		return &AccessDeniedError{
@@ -0,0 +1,72 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Strict configures the categories of problems reported as errors, failing
+// the build, instead of as warnings or not at all, e.g.:
+//
+//	[strict]
+//	missingTranslations = true
+//	deprecated = true
+//
+// Set strict = true to enable all of them.
+type Strict struct {
+	// Missing i18n translations.
+	MissingTranslations bool
+
+	// Raw HTML omitted by Goldmark as markup.goldmark.renderer.unsafe is
+	// not enabled.
+	RawHTMLOmitted bool
+
+	// Deprecated functions, methods and settings.
+	Deprecated bool
+
+	// Images without alt text in the rendered content.
+	MissingAltText bool
+
+	// Failed remote fetches with getJSON, getCSV and resources.GetRemote.
+	RemoteFetch bool
+}
+
+// DecodeStrict decodes the strict setting in site config.
+func DecodeStrict(in any) (Strict, error) {
+	var c Strict
+	switch v := in.(type) {
+	case nil:
+		return c, nil
+	case bool:
+		if v {
+			c = Strict{
+				MissingTranslations: true,
+				RawHTMLOmitted:      true,
+				Deprecated:          true,
+				MissingAltText:      true,
+				RemoteFetch:         true,
+			}
+		}
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode strict config: %w", err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeStrict(t *testing.T) {
+	c := qt.New(t)
+
+	s, err := DecodeStrict(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, Strict{})
+
+	s, err = DecodeStrict(true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.MissingTranslations && s.RawHTMLOmitted && s.Deprecated && s.MissingAltText && s.RemoteFetch, qt.IsTrue)
+
+	s, err = DecodeStrict(map[string]any{"missingtranslations": true, "remoteFetch": "true"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.Equals, Strict{MissingTranslations: true, RemoteFetch: true})
+
+	_, err = DecodeStrict("foo")
+	c.Assert(err, qt.Not(qt.IsNil))
+}
@@ -35,10 +35,15 @@ import (
 
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/resources/page"
 	"github.com/spf13/afero"
 )
 
 const (
+	// Files in a directory archetype with this suffix are executed as templates
+	// and the result is stored in a file with the suffix removed.
+	archetypeResourceTemplateSuffix = ".gotmpl"
+
 	// DefaultArchetypeTemplateTemplate is the template used in 'hugo new site'
 	// and the template we use as a fall back.
 	DefaultArchetypeTemplateTemplate = `---
@@ -181,38 +186,90 @@ func (b *contentBuilder) buildDir() error {
 		}
 	}
 
-	// Copy the rest as is.
+	// The bundle's main page is used as context when
+	// executing file name and resource templates.
+	var bundlePage page.Page
+	for _, filename := range contentTargetFilenames {
+		p := b.h.GetContentPage(filename)
+		if p == nil {
+			continue
+		}
+		if bundlePage == nil || strings.HasSuffix(p.File().TranslationBaseName(), "index") {
+			bundlePage = p
+		}
+	}
+
+	// Copy the rest as is, or, for *.gotmpl files, the result of
+	// executing them as templates.
 	for _, f := range b.dirMap.otherFiles {
 		meta := f.Meta()
 		filename := meta.Path
 
-		in, err := meta.Open()
-		if err != nil {
-			return fmt.Errorf("failed to open non-content file: %w", err)
+		relFilename := strings.TrimPrefix(filename, b.archetypeFilename)
+		isTemplate := strings.HasSuffix(relFilename, archetypeResourceTemplateSuffix)
+		if isTemplate {
+			relFilename = strings.TrimSuffix(relFilename, archetypeResourceTemplateSuffix)
 		}
 
-		targetFilename := filepath.Join(baseDir, b.targetPath, strings.TrimPrefix(filename, b.archetypeFilename))
+		if isTemplate || strings.Contains(relFilename, "{{") {
+			if bundlePage == nil {
+				return fmt.Errorf("archetype file %q is a template, but there are no content files in %q", filename, b.archetypeFilename)
+			}
+		}
+
+		if strings.Contains(relFilename, "{{") {
+			var name strings.Builder
+			if err := b.cf.ExecuteArchetypeTemplate(&name, bundlePage, b.kind, "archetype_filename", filepath.ToSlash(relFilename)); err != nil {
+				return fmt.Errorf("failed to execute file name template %q: %w", relFilename, err)
+			}
+			relFilename = filepath.FromSlash(name.String())
+		}
+
+		targetFilename := filepath.Join(baseDir, b.targetPath, relFilename)
 		targetDir := filepath.Dir(targetFilename)
 
 		if err := b.sourceFs.MkdirAll(targetDir, 0o777); err != nil && !os.IsExist(err) {
 			return fmt.Errorf("failed to create target directory for %q: %w", targetDir, err)
 		}
 
-		out, err := b.sourceFs.Create(targetFilename)
-		if err != nil {
+		if err := b.copyArcheTypeFile(meta, bundlePage, isTemplate, targetFilename); err != nil {
 			return err
 		}
+	}
+
+	b.h.Log.Printf("Content dir %q created", filepath.Join(baseDir, b.targetPath))
+
+	return nil
+}
 
+func (b *contentBuilder) copyArcheTypeFile(meta *hugofs.FileMeta, p page.Page, isTemplate bool, targetFilename string) error {
+	in, err := meta.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open non-content file: %w", err)
+	}
+	defer in.Close()
+
+	out, err := b.sourceFs.Create(targetFilename)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	if !isTemplate {
 		_, err = io.Copy(out, in)
-		if err != nil {
-			return err
-		}
+		return err
+	}
 
-		in.Close()
-		out.Close()
+	templateSource, err := io.ReadAll(in)
+	if err != nil {
+		return err
 	}
 
-	b.h.Log.Printf("Content dir %q created", filepath.Join(baseDir, b.targetPath))
+	// Note that the template may, e.g., fetch and process remote resources,
+	// so the result may be binary.
+	if err := b.cf.ExecuteArchetypeTemplate(out, p, b.kind, filepath.ToSlash(meta.Path), string(templateSource)); err != nil {
+		return fmt.Errorf("failed to execute archetype template %q: %w", meta.Path, err)
+	}
 
 	return nil
 }
@@ -316,6 +373,12 @@ func (b *contentBuilder) mapArcheTypeDir() error {
 		}
 
 		m.otherFiles = append(m.otherFiles, fil)
+		if !m.siteUsed && strings.HasSuffix(path, archetypeResourceTemplateSuffix) {
+			m.siteUsed, err = b.usesSiteVar(path)
+			if err != nil {
+				return err
+			}
+		}
 
 		return nil
 	}
@@ -384,7 +447,8 @@ func (b *contentBuilder) usesSiteVar(filename string) (bool, error) {
 type archetypeMap struct {
 	// These needs to be parsed and executed as Go templates.
 	contentFiles []hugofs.FileMetaInfo
-	// These are just copied to destination.
+	// These are just copied to destination, or, if suffixed with .gotmpl,
+	// executed as templates.
 	otherFiles []hugofs.FileMetaInfo
 	// If the templates needs a fully built site. This can potentially be
 	// expensive, so only do when needed.
@@ -15,6 +15,8 @@ package create_test
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -252,6 +254,80 @@ i18n: {{ T "hugo" }}
 	cContains(c, readFileFromFs(t, fs.Source, filepath.Join("content", "post/my-theme-post/resources/hugo1.json")), `hugo1: {{ printf "no template handling in here" }}`)
 }
 
+func TestNewContentFromDirTemplates(t *testing.T) {
+	mm := afero.NewMemMapFs()
+	c := qt.New(t)
+
+	archetypeDir := filepath.Join("archetypes", "my-bundle")
+	archetypeThemeDir := filepath.Join("themes", "mytheme", "archetypes", "my-bundle")
+
+	c.Assert(afero.WriteFile(mm, filepath.Join(archetypeDir, "index.md"), []byte(`title: {{ replace .Name "-" " " | title }}`), 0o755), qt.IsNil)
+	c.Assert(afero.WriteFile(mm, filepath.Join(archetypeDir, "images", "{{ .Name }}-cover.txt"), []byte(`{{ .Name }}`), 0o755), qt.IsNil)
+	c.Assert(afero.WriteFile(mm, filepath.Join(archetypeDir, "data.json.gotmpl"), []byte(`{{ dict "name" .Name "type" .Type | jsonify }}`), 0o755), qt.IsNil)
+	c.Assert(afero.WriteFile(mm, filepath.Join(archetypeDir, "greeting.txt.gotmpl"), []byte(`{{ $r := resources.FromString "greeting.txt" "Hello from a resource" }}{{ $r.Content }}`), 0o755), qt.IsNil)
+
+	// The project's archetype files take precedence over the theme's.
+	c.Assert(afero.WriteFile(mm, filepath.Join(archetypeThemeDir, "index.md"), []byte(`theme index`), 0o755), qt.IsNil)
+	c.Assert(afero.WriteFile(mm, filepath.Join(archetypeThemeDir, "theme.txt"), []byte(`theme file`), 0o755), qt.IsNil)
+
+	c.Assert(initFs(mm), qt.IsNil)
+	cfg, fs := newTestCfg(c, mm)
+
+	h, err := hugolib.NewHugoSites(deps.DepsCfg{Cfg: cfg, Fs: fs})
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(create.NewContent(h, "my-bundle", "post/my-post"), qt.IsNil)
+
+	cContains(c, readFileFromFs(t, fs.Source, filepath.Join("content", "post/my-post/index.md")), `title: My Post`)
+	cContains(c, readFileFromFs(t, fs.Source, filepath.Join("content", "post/my-post/images/my-post-cover.txt")), `{{ .Name }}`)
+	cContains(c, readFileFromFs(t, fs.Source, filepath.Join("content", "post/my-post/data.json")), `{"name":"my-post","type":"my-bundle"}`)
+	cContains(c, readFileFromFs(t, fs.Source, filepath.Join("content", "post/my-post/greeting.txt")), `Hello from a resource`)
+	cContains(c, readFileFromFs(t, fs.Source, filepath.Join("content", "post/my-post/theme.txt")), `theme file`)
+
+	exists, _ := afero.Exists(fs.Source, filepath.Join("content", "post/my-post/data.json.gotmpl"))
+	c.Assert(exists, qt.IsFalse)
+}
+
+func TestNewContentFromURL(t *testing.T) {
+	c := qt.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/posts/clipped/":
+			fmt.Fprint(w, `<html><head><title>Clipped Post</title><meta property="article:published_time" content="2021-02-03T10:00:00Z"></head>
+<body><nav><a href="/">Home</a></nav><article><h2>Intro</h2><p>Hello <img src="/img/sunset.png" alt="Sunset"> <img src="missing.jpg" alt="Missing"></p></article></body></html>`)
+		case "/img/sunset.png":
+			w.Header().Set("Content-Type", "image/png")
+			fmt.Fprint(w, "png")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	mm := afero.NewMemMapFs()
+	c.Assert(initFs(mm), qt.IsNil)
+	cfg, fs := newTestCfg(c, mm)
+	h, err := hugolib.NewHugoSites(deps.DepsCfg{Cfg: cfg, Fs: fs})
+	c.Assert(err, qt.IsNil)
+
+	c.Assert(create.NewContentFromURL(h, srv.URL+"/posts/clipped/", "post/clipped.md"), qt.IsNil)
+
+	content := readFileFromFs(t, fs.Source, filepath.Join("content", "post", "clipped", "index.md"))
+	cContains(c, content,
+		`title: Clipped Post`,
+		`date: "2021-02-03T10:00:00Z"`,
+		`canonical: `+srv.URL+`/posts/clipped/`,
+		`draft: true`,
+		"## Intro\n\nHello ![Sunset](sunset.png) ![Missing]("+srv.URL+"/posts/clipped/missing.jpg)",
+	)
+	c.Assert(strings.Contains(content, "Home"), qt.IsFalse)
+	c.Assert(readFileFromFs(t, fs.Source, filepath.Join("content", "post", "clipped", "sunset.png")), qt.Equals, "png")
+
+	// The bundle already exists.
+	c.Assert(create.NewContentFromURL(h, srv.URL+"/posts/clipped/", "post/clipped"), qt.ErrorMatches, `.*already exists`)
+}
+
 func initFs(fs afero.Fs) error {
 	perm := os.FileMode(0o755)
 	var err error
@@ -0,0 +1,230 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/common/htime"
+	"github.com/gohugoio/hugo/common/paths"
+	"github.com/gohugoio/hugo/hugofs/files"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/parser"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/spf13/afero"
+)
+
+// NewContentFromURL fetches the web page at pageURL, converts its main content
+// to Markdown and stores it, with any images it references, in a new page
+// bundle in targetPath.
+func NewContentFromURL(h *hugolib.HugoSites, pageURL, targetPath string) error {
+	if h.BaseFs.Content.Dirs == nil {
+		return errors.New("no existing content directory configured for this project")
+	}
+
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL %q: %w", pageURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("URL %q must be absolute with scheme http or https", pageURL)
+	}
+
+	// Create the page bundle, e.g. posts/my-post/index.md for posts/my-post.md.
+	if files.IsContentFile(targetPath) {
+		targetPath = paths.PathNoExt(targetPath)
+	}
+	_, filename, err := h.AbsProjectContentDir(filepath.Join(filepath.FromSlash(targetPath), "index.md"))
+	if err != nil {
+		return err
+	}
+	if exists, _ := afero.Exists(h.Fs.Source, filename); exists {
+		return fmt.Errorf("%q already exists", filename)
+	}
+
+	c := &webClipper{
+		h:      h,
+		client: &http.Client{Timeout: time.Minute},
+		dir:    filepath.Dir(filename),
+		images: make(map[string]string),
+		names:  make(map[string]bool),
+	}
+
+	res, err := c.get(u.String())
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	wp, err := parseWebPage(res.Body, res.Request.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse %q: %w", pageURL, err)
+	}
+
+	if err := h.Fs.Source.MkdirAll(c.dir, 0o777); err != nil {
+		return err
+	}
+
+	conv := &markdownConverter{base: res.Request.URL, image: c.downloadImage}
+	content := conv.Convert(wp.Content)
+
+	date := wp.Date
+	if date.IsZero() {
+		date = htime.Now()
+	}
+
+	frontMatter := map[string]any{
+		"title":     wp.Title,
+		"date":      date.Format(time.RFC3339),
+		"draft":     true,
+		"canonical": wp.Canonical,
+	}
+	if wp.Description != "" {
+		frontMatter["description"] = wp.Description
+	}
+
+	var buf bytes.Buffer
+	if err := parser.InterfaceToFrontMatter(frontMatter, metadecoders.YAML, &buf); err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+	buf.WriteString(content)
+
+	if err := afero.WriteReader(h.Fs.Source, filename, &buf); err != nil {
+		return fmt.Errorf("failed to write %q: %w", filename, err)
+	}
+
+	h.Log.Printf("Content %q created from %s", filename, pageURL)
+
+	b := &contentBuilder{h: h}
+	return b.openInEditorIfConfigured(filename)
+}
+
+// webClipper fetches a web page and the images it references.
+type webClipper struct {
+	h      *hugolib.HugoSites
+	client *http.Client
+
+	// The page bundle directory.
+	dir string
+
+	// Maps the absolute image URL to its bundle file name.
+	images map[string]string
+	names  map[string]bool
+}
+
+func (c *webClipper) get(uri string) (*http.Response, error) {
+	if err := c.h.ExecHelper.Sec().CheckAllowedHTTPURL(uri); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "Hugo Static Site Generator")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %q: %w", uri, err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		res.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %q: %s", uri, res.Status)
+	}
+
+	return res, nil
+}
+
+// downloadImage downloads the image at src into the page bundle and returns
+// its file name. The original URL is returned if the download fails.
+func (c *webClipper) downloadImage(src string) string {
+	if name, found := c.images[src]; found {
+		return name
+	}
+
+	name, err := c.saveImage(src)
+	if err != nil {
+		c.h.Log.Warnf("Failed to download image %q, keeping the remote URL: %s", src, err)
+		name = src
+	}
+	c.images[src] = name
+
+	return name
+}
+
+func (c *webClipper) saveImage(src string) (string, error) {
+	u, err := url.Parse(src)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := c.get(src)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	name := c.imageName(u, res.Header.Get("Content-Type"))
+
+	f, err := c.h.Fs.Source.Create(filepath.Join(c.dir, name))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, res.Body); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// imageName creates a unique file name in the bundle for the image at u.
+func (c *webClipper) imageName(u *url.URL, contentType string) string {
+	base := path.Base(u.Path)
+	ext := path.Ext(base)
+	base = c.h.PathSpec.MakePathSanitized(strings.TrimSuffix(base, ext))
+	if base == "" || base == "." || base == "/" {
+		base = "image"
+	}
+
+	if ext == "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+			if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+				ext = exts[0]
+			}
+		}
+	}
+	ext = strings.ToLower(ext)
+
+	name := base + ext
+	for i := 1; c.names[name]; i++ {
+		name = fmt.Sprintf("%s-%d%s", base, i, ext)
+	}
+	c.names[name] = true
+
+	return name
+}
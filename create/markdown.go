@@ -0,0 +1,398 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Elements not considered part of the content.
+var skipElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Iframe:   true,
+	atom.Svg:      true,
+	atom.Nav:      true,
+	atom.Aside:    true,
+	atom.Footer:   true,
+	atom.Form:     true,
+	atom.Button:   true,
+}
+
+var blockElements = map[atom.Atom]bool{
+	atom.Address:    true,
+	atom.Article:    true,
+	atom.Blockquote: true,
+	atom.Dd:         true,
+	atom.Div:        true,
+	atom.Dl:         true,
+	atom.Dt:         true,
+	atom.Figcaption: true,
+	atom.Figure:     true,
+	atom.H1:         true,
+	atom.H2:         true,
+	atom.H3:         true,
+	atom.H4:         true,
+	atom.H5:         true,
+	atom.H6:         true,
+	atom.Header:     true,
+	atom.Hr:         true,
+	atom.Li:         true,
+	atom.Main:       true,
+	atom.Ol:         true,
+	atom.P:          true,
+	atom.Pre:        true,
+	atom.Section:    true,
+	atom.Table:      true,
+	atom.Ul:         true,
+}
+
+var (
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`,
+		"*", `\*`,
+		"_", `\_`,
+		"`", "\\`",
+		"[", `\[`,
+		"]", `\]`,
+	)
+	multipleSpacesRe = regexp.MustCompile(` {2,}`)
+)
+
+// markdownConverter converts HTML to Markdown.
+type markdownConverter struct {
+	// Used to resolve relative link and image URLs.
+	base *url.URL
+
+	// If set, image returns the destination to use for the image with
+	// the given absolute URL, e.g. a local copy of it.
+	image func(src string) string
+}
+
+// Convert converts the content of n to Markdown.
+func (c *markdownConverter) Convert(n *html.Node) string {
+	return c.blocks(n) + "\n"
+}
+
+// blocks converts the children of n to Markdown blocks separated by blank lines.
+func (c *markdownConverter) blocks(n *html.Node) string {
+	var (
+		b      strings.Builder
+		inline strings.Builder
+	)
+
+	addBlock := func(s string) {
+		if s == "" {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(s)
+	}
+
+	flushInline := func() {
+		addBlock(cleanInline(inline.String()))
+		inline.Reset()
+	}
+
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		if ch.Type == html.ElementNode && blockElements[ch.DataAtom] {
+			flushInline()
+			addBlock(c.block(ch))
+			continue
+		}
+		c.inline(&inline, ch)
+	}
+	flushInline()
+
+	return b.String()
+}
+
+func (c *markdownConverter) block(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		text := c.inlineText(n)
+		if text == "" {
+			return ""
+		}
+		level := int(n.Data[1] - '0')
+		return strings.Repeat("#", level) + " " + text
+	case atom.P, atom.Dt:
+		return c.inlineText(n)
+	case atom.Hr:
+		return "---"
+	case atom.Pre:
+		return c.codeBlock(n)
+	case atom.Blockquote:
+		return prefixLines(c.blocks(n), "> ")
+	case atom.Ul, atom.Ol:
+		return c.list(n)
+	case atom.Table:
+		return c.table(n)
+	default:
+		return c.blocks(n)
+	}
+}
+
+func (c *markdownConverter) codeBlock(n *html.Node) string {
+	var lang string
+	if code := findNode(n, func(n *html.Node) bool { return n.DataAtom == atom.Code }); code != nil {
+		for _, class := range strings.Fields(attr(code, "class")) {
+			if strings.HasPrefix(class, "language-") {
+				lang = strings.TrimPrefix(class, "language-")
+				break
+			}
+		}
+	}
+
+	code := strings.Trim(rawText(n), "\n")
+	fence := "```"
+	if strings.Contains(code, fence) {
+		fence = "~~~"
+	}
+
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+func (c *markdownConverter) list(n *html.Node) string {
+	start := 1
+	if s := attr(n, "start"); s != "" {
+		if i, err := strconv.Atoi(s); err == nil {
+			start = i
+		}
+	}
+
+	var items []string
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", start+len(items))
+		}
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, marker+strings.TrimPrefix(prefixLines(c.blocks(li), indent), indent))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+func (c *markdownConverter) table(n *html.Node) string {
+	var rows [][]string
+	walkNodes(n, func(nn *html.Node) bool {
+		if nn.DataAtom != atom.Tr {
+			return true
+		}
+		var row []string
+		for cell := nn.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+				row = append(row, strings.ReplaceAll(c.inlineText(cell), "|", `\|`))
+			}
+		}
+		if len(row) > 0 {
+			rows = append(rows, row)
+		}
+		return false
+	})
+
+	if len(rows) == 0 {
+		return ""
+	}
+
+	var cols int
+	for _, row := range rows {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(row []string) {
+		b.WriteString("|")
+		for i := 0; i < cols; i++ {
+			var cell string
+			if i < len(row) {
+				cell = row[i]
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	b.WriteString("|" + strings.Repeat(" --- |", cols) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// inlineText converts the children of n to inline Markdown.
+func (c *markdownConverter) inlineText(n *html.Node) string {
+	var b strings.Builder
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		c.inline(&b, ch)
+	}
+	return cleanInline(b.String())
+}
+
+func (c *markdownConverter) inline(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(markdownEscaper.Replace(collapseWhitespace(n.Data)))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if skipElements[n.DataAtom] {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		b.WriteString("\\\n")
+	case atom.Img:
+		src := attr(n, "src")
+		if src == "" || strings.HasPrefix(src, "data:") {
+			// Lazy loaded images.
+			src = attr(n, "data-src")
+		}
+		if src == "" || strings.HasPrefix(src, "data:") {
+			return
+		}
+		src = resolveURL(c.base, src)
+		if c.image != nil {
+			src = c.image(src)
+		}
+		alt := markdownEscaper.Replace(collapseWhitespace(attr(n, "alt")))
+		fmt.Fprintf(b, "![%s](%s)", strings.TrimSpace(alt), src)
+	case atom.A:
+		text := c.inlineText(n)
+		href := attr(n, "href")
+		if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:") || text == "" {
+			b.WriteString(text)
+			return
+		}
+		fmt.Fprintf(b, "[%s](%s)", text, resolveURL(c.base, href))
+	case atom.Strong, atom.B:
+		c.wrapInline(b, n, "**")
+	case atom.Em, atom.I:
+		c.wrapInline(b, n, "_")
+	case atom.Del, atom.S:
+		c.wrapInline(b, n, "~~")
+	case atom.Code, atom.Kbd, atom.Samp:
+		code := collapseWhitespace(rawText(n))
+		delim := "`"
+		if strings.Contains(code, "`") {
+			delim = "``"
+			code = " " + code + " "
+		}
+		b.WriteString(delim + code + delim)
+	default:
+		for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+			c.inline(b, ch)
+		}
+	}
+}
+
+// wrapInline writes the inline content of n surrounded by delim,
+// keeping any surrounding whitespace outside of the delimiters.
+func (c *markdownConverter) wrapInline(b *strings.Builder, n *html.Node, delim string) {
+	var inner strings.Builder
+	for ch := n.FirstChild; ch != nil; ch = ch.NextSibling {
+		c.inline(&inner, ch)
+	}
+	s := inner.String()
+	text := strings.TrimSpace(s)
+	if text == "" {
+		b.WriteString(s)
+		return
+	}
+	if strings.HasPrefix(s, " ") {
+		b.WriteString(" ")
+	}
+	b.WriteString(delim + text + delim)
+	if strings.HasSuffix(s, " ") {
+		b.WriteString(" ")
+	}
+}
+
+func cleanInline(s string) string {
+	s = multipleSpacesRe.ReplaceAllString(s, " ")
+	s = strings.ReplaceAll(s, "\n ", "\n")
+	s = strings.ReplaceAll(s, " \\\n", "\\\n")
+	s = strings.TrimSpace(s)
+	// Drop any trailing hard line breaks.
+	for strings.HasSuffix(s, `\`) && !strings.HasSuffix(s, `\\`) {
+		s = strings.TrimSpace(strings.TrimSuffix(s, `\`))
+	}
+	return s
+}
+
+func collapseWhitespace(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			if !space {
+				b.WriteRune(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func rawText(n *html.Node) string {
+	var b strings.Builder
+	walkNodes(n, func(n *html.Node) bool {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		if n.DataAtom == atom.Br {
+			b.WriteString("\n")
+		}
+		return true
+	})
+	return b.String()
+}
+
+func prefixLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimSpace(prefix)
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
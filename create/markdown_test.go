@@ -0,0 +1,109 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestParseWebPage(t *testing.T) {
+	c := qt.New(t)
+
+	pageURL, _ := url.Parse("https://example.org/blog/post/")
+
+	wp, err := parseWebPage(strings.NewReader(`<!DOCTYPE html>
+<html>
+<head>
+<title>My Post | My Blog</title>
+<meta property="og:title" content="My Post">
+<meta name="description" content="About my post.">
+<meta property="article:published_time" content="2022-05-03T10:00:00Z">
+<link rel="canonical" href="/blog/my-post/">
+</head>
+<body>
+<nav><a href="/">Home</a></nav>
+<div class="sidebar"><p>Subscribe.</p></div>
+<div class="content">
+<p>First paragraph with some text.</p>
+<p>Second paragraph with some more text.</p>
+</div>
+<footer><p>Copyright</p></footer>
+</body>
+</html>`), pageURL)
+
+	c.Assert(err, qt.IsNil)
+	c.Assert(wp.Title, qt.Equals, "My Post")
+	c.Assert(wp.Description, qt.Equals, "About my post.")
+	c.Assert(wp.Date.Format("2006-01-02"), qt.Equals, "2022-05-03")
+	c.Assert(wp.Canonical, qt.Equals, "https://example.org/blog/my-post/")
+	c.Assert(attr(wp.Content, "class"), qt.Equals, "content")
+}
+
+func TestMarkdownConverter(t *testing.T) {
+	c := qt.New(t)
+
+	base, _ := url.Parse("https://example.org/blog/post/")
+
+	wp, err := parseWebPage(strings.NewReader(`<html><body>
+<article>
+<h1>The  Title</h1>
+<p>Some <strong>bold</strong> and <em>emphasized</em> text with a <a href="../other/">link</a> and <code>code</code>.<br>
+A new line with a literal *.</p>
+<script>alert("no");</script>
+<figure><img src="images/cat.jpg" alt="A cat"><figcaption>The cat.</figcaption></figure>
+<ul>
+<li>One</li>
+<li>Two
+<ol start="3"><li>Three</li><li>Four</li></ol>
+</li>
+</ul>
+<blockquote><p>Quoted.</p><p>Twice.</p></blockquote>
+<pre><code class="language-go">func main() {
+	fmt.Println("Hello")
+}
+</code></pre>
+<table><tr><th>A</th><th>B</th></tr><tr><td>1</td><td>2</td></tr></table>
+</article>
+</body></html>`), base)
+	c.Assert(err, qt.IsNil)
+
+	var images []string
+	conv := &markdownConverter{base: base, image: func(src string) string {
+		images = append(images, src)
+		return "cat.jpg"
+	}}
+
+	c.Assert(conv.Convert(wp.Content), qt.Equals, "# The Title\n\n"+
+		"Some **bold** and _emphasized_ text with a [link](https://example.org/blog/other/) and `code`.\\\n"+
+		"A new line with a literal \\*.\n\n"+
+		"![A cat](cat.jpg)\n\n"+
+		"The cat.\n\n"+
+		"- One\n"+
+		"- Two\n\n"+
+		"  3. Three\n"+
+		"  4. Four\n\n"+
+		"> Quoted.\n"+
+		">\n"+
+		"> Twice.\n\n"+
+		"```go\nfunc main() {\n\tfmt.Println(\"Hello\")\n}\n```\n\n"+
+		"| A | B |\n"+
+		"| --- | --- |\n"+
+		"| 1 | 2 |\n")
+
+	c.Assert(images, qt.DeepEquals, []string{"https://example.org/blog/post/images/cat.jpg"})
+}
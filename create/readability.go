@@ -0,0 +1,233 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package create
+
+import (
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/common/htime"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// webPage holds the metadata and the main content extracted from a web page.
+type webPage struct {
+	Title       string
+	Description string
+
+	// The publish date, zero if not found.
+	Date time.Time
+
+	// The canonical URL of the page, which defaults to the URL it was fetched from.
+	Canonical string
+
+	// The node holding the main content of the page.
+	Content *html.Node
+}
+
+// parseWebPage parses the HTML in r, fetched from pageURL, and extracts
+// its metadata and main content.
+func parseWebPage(r io.Reader, pageURL *url.URL) (webPage, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return webPage{}, err
+	}
+
+	var (
+		wp                 webPage
+		title, ogTitle, h1 string
+		dateStr, timeStr   string
+		body               *html.Node
+	)
+
+	walkNodes(doc, func(n *html.Node) bool {
+		if n.Type != html.ElementNode {
+			return true
+		}
+		switch n.DataAtom {
+		case atom.Title:
+			if title == "" {
+				title = textContent(n)
+			}
+		case atom.H1:
+			if h1 == "" {
+				h1 = textContent(n)
+			}
+		case atom.Body:
+			body = n
+		case atom.Time:
+			if timeStr == "" {
+				timeStr = attr(n, "datetime")
+			}
+		case atom.Link:
+			if wp.Canonical == "" && strings.EqualFold(attr(n, "rel"), "canonical") {
+				wp.Canonical = resolveURL(pageURL, attr(n, "href"))
+			}
+		case atom.Meta:
+			name := strings.ToLower(attr(n, "property"))
+			if name == "" {
+				name = strings.ToLower(attr(n, "name"))
+			}
+			content := strings.TrimSpace(attr(n, "content"))
+			switch name {
+			case "og:title":
+				ogTitle = content
+			case "description", "og:description":
+				if wp.Description == "" {
+					wp.Description = content
+				}
+			case "article:published_time", "date", "dc.date", "dcterms.created":
+				if dateStr == "" {
+					dateStr = content
+				}
+			case "og:url":
+				if wp.Canonical == "" {
+					wp.Canonical = resolveURL(pageURL, content)
+				}
+			}
+		}
+		return true
+	})
+
+	wp.Title = firstNonEmpty(ogTitle, h1, strings.TrimSpace(title))
+
+	for _, s := range []string{dateStr, timeStr} {
+		if s == "" {
+			continue
+		}
+		if d, err := htime.ToTimeInDefaultLocationE(s, time.UTC); err == nil {
+			wp.Date = d
+			break
+		}
+	}
+
+	if wp.Canonical == "" {
+		wp.Canonical = pageURL.String()
+	}
+
+	wp.Content = findMainContent(doc, body)
+
+	return wp, nil
+}
+
+// findMainContent finds the element most likely to hold the main content,
+// in order: the first article, main or role=main element, the element with
+// the most paragraph text, and the body.
+func findMainContent(doc, body *html.Node) *html.Node {
+	for _, match := range []func(n *html.Node) bool{
+		func(n *html.Node) bool { return n.DataAtom == atom.Article },
+		func(n *html.Node) bool { return n.DataAtom == atom.Main },
+		func(n *html.Node) bool { return attr(n, "role") == "main" },
+	} {
+		if n := findNode(doc, match); n != nil {
+			return n
+		}
+	}
+
+	// Score every element by the length of the text in its paragraphs.
+	scores := make(map[*html.Node]int)
+	var best *html.Node
+	walkNodes(doc, func(n *html.Node) bool {
+		if skipElements[n.DataAtom] {
+			return false
+		}
+		if n.DataAtom == atom.P && n.Parent != nil {
+			scores[n.Parent] += len(textContent(n))
+			if best == nil || scores[n.Parent] > scores[best] {
+				best = n.Parent
+			}
+		}
+		return true
+	})
+
+	if best != nil {
+		return best
+	}
+
+	if body != nil {
+		return body
+	}
+
+	return doc
+}
+
+// walkNodes walks the tree rooted in n depth-first. The children of a node
+// are skipped if fn returns false.
+func walkNodes(n *html.Node, fn func(n *html.Node) bool) {
+	if !fn(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walkNodes(c, fn)
+	}
+}
+
+func findNode(n *html.Node, match func(n *html.Node) bool) *html.Node {
+	var found *html.Node
+	walkNodes(n, func(n *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if n.Type == html.ElementNode && match(n) {
+			found = n
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	walkNodes(n, func(n *html.Node) bool {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		return true
+	})
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func resolveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+func firstNonEmpty(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
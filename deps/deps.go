@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/gohugoio/hugo/cache/filecache"
+	"github.com/gohugoio/hugo/common/constants"
 	"github.com/gohugoio/hugo/common/hexec"
 	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/config/security"
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugolib/buildhooks"
 	"github.com/gohugoio/hugo/langs"
 	"github.com/gohugoio/hugo/media"
 	"github.com/gohugoio/hugo/resources/page"
@@ -91,6 +93,9 @@ type Deps struct {
 	// Timeout is configurable in site config.
 	Timeout time.Duration
 
+	// The categories of problems to report as errors.
+	Strict config.Strict
+
 	// BuildStartListeners will be notified before a build starts.
 	BuildStartListeners *Listeners
 
@@ -269,8 +274,24 @@ func New(cfg DepsCfg) (*Deps, error) {
 		timeoutms = 3000
 	}
 
+	strict, err := config.DecodeStrict(cfg.Cfg.Get("strict"))
+	if err != nil {
+		return nil, err
+	}
+
 	ignoreErrors := cast.ToStringSlice(cfg.Cfg.Get("ignoreErrors"))
-	ignorableLogger := loggers.NewIgnorableLogger(logger, ignoreErrors...)
+	if strict.RemoteFetch {
+		// The failed remote fetches can not be ignored in strict mode.
+		var keep []string
+		for _, id := range ignoreErrors {
+			if id != constants.ErrRemoteGetJSON && id != constants.ErrRemoteGetCSV {
+				keep = append(keep, id)
+			}
+		}
+		ignoreErrors = keep
+	}
+	ignoreWarnings := cast.ToStringSlice(cfg.Cfg.Get("ignoreWarnings"))
+	ignorableLogger := loggers.NewIgnorableLogger(logger, ignoreErrors, ignoreWarnings)
 
 	logDistinct := helpers.NewDistinctLogger(logger)
 
@@ -296,6 +317,7 @@ func New(cfg DepsCfg) (*Deps, error) {
 		BuildState:              buildState,
 		Running:                 cfg.Running,
 		Timeout:                 time.Duration(timeoutms) * time.Millisecond,
+		Strict:                  strict,
 		globalErrHandler:        errorHandler,
 	}
 
@@ -398,17 +420,38 @@ type DepsCfg struct {
 
 	// Whether we are in running (server) mode
 	Running bool
+
+	// The Go callbacks to run at the stages of a build.
+	BuildHooks *buildhooks.Hooks
 }
 
 // BuildState are flags that may be turned on during a build.
 type BuildState struct {
 	counter uint64
+
+	// The number of partialCached cache hits and misses.
+	partialCacheHits   uint64
+	partialCacheMisses uint64
 }
 
 func (b *BuildState) Incr() int {
 	return int(atomic.AddUint64(&b.counter, uint64(1)))
 }
 
+// IncrPartialCache counts a partialCached cache hit or miss.
+func (b *BuildState) IncrPartialCache(hit bool) {
+	if hit {
+		atomic.AddUint64(&b.partialCacheHits, 1)
+	} else {
+		atomic.AddUint64(&b.partialCacheMisses, 1)
+	}
+}
+
+// PartialCacheStats returns the number of partialCached cache hits and misses.
+func (b *BuildState) PartialCacheStats() (hits, misses uint64) {
+	return atomic.LoadUint64(&b.partialCacheHits), atomic.LoadUint64(&b.partialCacheMisses)
+}
+
 func NewBuildState() BuildState {
 	return BuildState{}
 }
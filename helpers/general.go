@@ -400,6 +400,10 @@ var (
 
 	// DistinctWarnLog can be used to avoid spamming the logs with warnings.
 	DistinctWarnLog = NewDistinctWarnLogger()
+
+	// StrictDeprecations is set when building in strict mode to log all
+	// deprecations as ERRORs, see the strict config.
+	StrictDeprecations bool
 )
 
 // InitLoggers resets the global distinct loggers.
@@ -410,12 +414,14 @@ func InitLoggers() {
 
 // Deprecated informs about a deprecation, but only once for a given set of arguments' values.
 // If the err flag is enabled, it logs as an ERROR (will exit with -1) and the text will
-// point at the next Hugo release.
+// point at the next Hugo release. In strict mode, all deprecations are logged as ERRORs.
 // The idea is two remove an item in two Hugo releases to give users and theme authors
 // plenty of time to fix their templates.
 func Deprecated(item, alternative string, err bool) {
 	if err {
 		DistinctErrorLog.Errorf("%s is deprecated and will be removed in Hugo %s. %s", item, hugo.CurrentVersion.Next().ReleaseVersion(), alternative)
+	} else if StrictDeprecations {
+		DistinctErrorLog.Errorf("%s is deprecated and will be removed in a future release. %s", item, alternative)
 	} else {
 		var warnPanicMessage string
 		if !loggers.PanicOnWarning {
@@ -507,6 +513,8 @@ func NormalizeHugoFlags(f *pflag.FlagSet, name string) pflag.NormalizedName {
 		name = "baseURL"
 	case "uglyUrls":
 		name = "uglyURLs"
+	case "from-url":
+		name = "fromURL"
 	}
 	return pflag.NormalizedName(name)
 }
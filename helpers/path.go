@@ -39,8 +39,12 @@ import (
 // It does so by creating a Unicode-sanitized string, with the spaces replaced,
 // whilst preserving the original casing of the string.
 // E.g. Social Media -> Social-Media
+// Any slugs configuration for the language is applied, see package slug.
 func (p *PathSpec) MakePath(s string) string {
-	return p.UnicodeSanitize(s)
+	if p.Slugs == nil {
+		return p.UnicodeSanitize(s)
+	}
+	return p.Slugs.Shorten(p.UnicodeSanitize(p.Slugs.Transliterate(s)))
 }
 
 // MakePathsSanitized applies MakePathSanitized on every item in the slice
@@ -182,6 +182,7 @@ const (
 
 	FolderResources = "resources"
 	FolderJSConfig  = "_jsconfig" // Mounted below /assets with postcss.config.js etc.
+	FolderPartials  = "partials"  // Mounted below /assets with colocated assets if enabled.
 )
 
 var (
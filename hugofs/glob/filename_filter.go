@@ -38,6 +38,40 @@ func normalizeFilenameGlobPattern(s string) string {
 	return s
 }
 
+// expandFilenameGlobPatterns normalizes the patterns and adds the variants
+// needed to match them as in e.g. .gitignore files:
+//
+//   - A "/**/" also matches zero directories, so "/**/*.md" matches "/a.md".
+//   - With dirs set, a pattern ending with "/**" also matches the directory
+//     itself, so an excluded directory is skipped without reading it.
+func expandFilenameGlobPatterns(patterns []string, dirs bool) []string {
+	var expanded []string
+	seen := make(map[string]bool)
+
+	var add func(pattern string)
+	add = func(pattern string) {
+		if seen[pattern] {
+			return
+		}
+		seen[pattern] = true
+		expanded = append(expanded, pattern)
+		if dirs && strings.HasSuffix(pattern, "/**") && len(pattern) > len("/**") {
+			add(strings.TrimSuffix(pattern, "/**"))
+		}
+		for i := 0; i < len(pattern); i++ {
+			if strings.HasPrefix(pattern[i:], "/**/") {
+				add(pattern[:i] + pattern[i+len("/**"):])
+			}
+		}
+	}
+
+	for _, pattern := range patterns {
+		add(normalizeFilenameGlobPattern(pattern))
+	}
+
+	return expanded
+}
+
 // NewFilenameFilter creates a new Glob where the Match method will
 // return true if the file should be included.
 // Note that the inclusions will be checked first.
@@ -47,8 +81,7 @@ func NewFilenameFilter(inclusions, exclusions []string) (*FilenameFilter, error)
 	}
 	filter := &FilenameFilter{isWindows: isWindows}
 
-	for _, include := range inclusions {
-		include = normalizeFilenameGlobPattern(include)
+	for _, include := range expandFilenameGlobPatterns(inclusions, false) {
 		g, err := filenamesGlobCache.GetGlob(include)
 		if err != nil {
 			return nil, err
@@ -70,8 +103,7 @@ func NewFilenameFilter(inclusions, exclusions []string) (*FilenameFilter, error)
 		}
 	}
 
-	for _, exclude := range exclusions {
-		exclude = normalizeFilenameGlobPattern(exclude)
+	for _, exclude := range expandFilenameGlobPatterns(exclusions, true) {
 		g, err := filenamesGlobCache.GetGlob(exclude)
 		if err != nil {
 			return nil, err
@@ -68,3 +68,26 @@ func TestFilenameFilter(t *testing.T) {
 	c.Assert(funcFilter.Match("ab.bson", false), qt.Equals, false)
 
 }
+
+func TestFilenameFilterGitignoreStyle(t *testing.T) {
+	c := qt.New(t)
+
+	filter, err := NewFilenameFilter(nil, []string{"drafts/**", "**/*.test.md", "/a/**/b/**/c.md"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(filter.Match(filepath.FromSlash("/drafts"), true), qt.Equals, false)
+	c.Assert(filter.Match(filepath.FromSlash("/drafts/p1.md"), false), qt.Equals, false)
+	c.Assert(filter.Match(filepath.FromSlash("/posts/drafts"), true), qt.Equals, true)
+	c.Assert(filter.Match(filepath.FromSlash("/p1.test.md"), false), qt.Equals, false)
+	c.Assert(filter.Match(filepath.FromSlash("/posts/2022/p1.test.md"), false), qt.Equals, false)
+	c.Assert(filter.Match(filepath.FromSlash("/posts/p1.md"), false), qt.Equals, true)
+	c.Assert(filter.Match(filepath.FromSlash("/a/b/c.md"), false), qt.Equals, false)
+	c.Assert(filter.Match(filepath.FromSlash("/a/x/b/c.md"), false), qt.Equals, false)
+	c.Assert(filter.Match(filepath.FromSlash("/a/b/y/c.md"), false), qt.Equals, false)
+
+	includeFilter, err := NewFilenameFilter([]string{"/docs/**/*.md"}, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(includeFilter.Match(filepath.FromSlash("/docs/index.md"), false), qt.Equals, true)
+	c.Assert(includeFilter.Match(filepath.FromSlash("/docs/a/index.md"), false), qt.Equals, true)
+	c.Assert(includeFilter.Match(filepath.FromSlash("/docs/a"), true), qt.Equals, true)
+	c.Assert(includeFilter.Match(filepath.FromSlash("/docs/a.txt"), false), qt.Equals, false)
+}
@@ -26,8 +26,9 @@ import (
 var ErrPermissionSymlink = errors.New("symlinks not allowed in this filesystem")
 
 // NewNoSymlinkFs creates a new filesystem that prevents symlinks.
-func NewNoSymlinkFs(fs afero.Fs, logger loggers.Logger, allowFiles bool) afero.Fs {
-	return &noSymlinkFs{Fs: fs, logger: logger, allowFiles: allowFiles}
+// The symlinks for which allow, if set, returns true are let through.
+func NewNoSymlinkFs(fs afero.Fs, logger loggers.Logger, allowFiles bool, allow func(filename string) bool) afero.Fs {
+	return &noSymlinkFs{Fs: fs, logger: logger, allowFiles: allowFiles, allow: allow}
 }
 
 var (
@@ -37,6 +38,7 @@ var (
 // noSymlinkFs is a filesystem that prevents symlinking.
 type noSymlinkFs struct {
 	allowFiles bool // block dirs only
+	allow      func(filename string) bool
 	logger     loggers.Logger
 	afero.Fs
 }
@@ -114,6 +116,10 @@ func (fs *noSymlinkFs) checkSymlinkStatus(name string, fi os.FileInfo) (os.FileI
 		metaIsSymlink = meta.IsSymlink
 	}
 
+	if (metaIsSymlink || isSymlink(fi)) && fs.allow != nil && fs.allow(name) {
+		return fi, nil
+	}
+
 	if metaIsSymlink {
 		if fs.allowFiles && !fi.IsDir() {
 			return fi, nil
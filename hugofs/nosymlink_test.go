@@ -69,7 +69,7 @@ func TestNoSymlinkFs(t *testing.T) {
 	for _, bfs := range []afero.Fs{NewBaseFileDecorator(Os), Os} {
 		for _, allowFiles := range []bool{false, true} {
 			logger.LogCounters().WarnCounter.Reset()
-			fs := NewNoSymlinkFs(bfs, logger, allowFiles)
+			fs := NewNoSymlinkFs(bfs, logger, allowFiles, nil)
 			ls := fs.(afero.Lstater)
 			symlinkedDir := filepath.Join(workDir, "symlinkdedir")
 			symlinkedFilename := "symlinkdedfile.txt"
@@ -144,3 +144,35 @@ func TestNoSymlinkFs(t *testing.T) {
 		}
 	}
 }
+
+func TestNoSymlinkFsAllow(t *testing.T) {
+	if skipSymlink() {
+		t.Skip("Skip; os.Symlink needs administrator rights on Windows")
+	}
+	c := qt.New(t)
+	workDir, clean := prepareSymlinks(t)
+	defer clean()
+
+	logger := loggers.NewWarningLogger()
+	symlinkedDir := filepath.Join(workDir, "symlinkdedir")
+	allow := func(filename string) bool {
+		return filename == symlinkedDir
+	}
+
+	fs := NewNoSymlinkFs(NewBaseFileDecorator(Os), logger, false, allow)
+
+	fi, err := fs.Stat(symlinkedDir)
+	c.Assert(err, qt.IsNil)
+	c.Assert(fi.IsDir(), qt.IsTrue)
+
+	_, err = fs.Stat(filepath.Join(workDir, "blog", "symsub"))
+	c.Assert(err, qt.Equals, ErrPermissionSymlink)
+
+	f, err := fs.Open(workDir)
+	c.Assert(err, qt.IsNil)
+	names, err := f.Readdirnames(-1)
+	f.Close()
+	c.Assert(err, qt.IsNil)
+	c.Assert(names, qt.Contains, "symlinkdedir")
+	c.Assert(logger.LogCounters().WarnCounter.Count(), qt.Equals, uint64(0))
+}
@@ -166,7 +166,7 @@ func (w *Walkway) checkErr(filename string, err error) bool {
 }
 
 func logUnsupportedSymlink(filename string, logger loggers.Logger) {
-	logger.Warnf("Unsupported symlink found in %q, skipping.", filename)
+	logger.Warnf("Unsupported symlink found in %q, skipping. Add it to security.symlinks in site config to allow it.", filename)
 }
 
 // walk recursively descends path, calling walkFn.
@@ -240,6 +240,18 @@ func (w *Walkway) walk(path string, info FileMetaInfo, dirEntries []FileMetaInfo
 		}
 	}
 
+	// The real path of this directory, to detect symlink cycles.
+	var realFilename string
+	resolveRealFilename := func() string {
+		if realFilename == "" {
+			realFilename = filename
+			if s, err := filepath.EvalSymlinks(filename); err == nil {
+				realFilename = s
+			}
+		}
+		return realFilename
+	}
+
 	// First add some metadata to the dir entries
 	for _, fi := range dirEntries {
 		fim := fi.(FileMetaInfo)
@@ -265,10 +277,16 @@ func (w *Walkway) walk(path string, info FileMetaInfo, dirEntries []FileMetaInfo
 		meta.Path = normalizeFilename(pathMeta)
 		meta.PathWalk = pathn
 
-		if fim.IsDir() && meta.IsSymlink && w.isSeen(meta.Filename) {
-			// Prevent infinite recursion
-			// Possible cyclic reference
-			meta.SkipDir = true
+		if fim.IsDir() && meta.IsSymlink {
+			if isSameOrParentDir(meta.Filename, resolveRealFilename()) {
+				// Following it would loop forever.
+				w.logger.Warnf("Symlink cycle found in %q: it points to %q, which contains it, skipping.", pathn, meta.Filename)
+				meta.SkipDir = true
+			} else if w.isSeen(meta.Filename) {
+				// Prevent infinite recursion
+				// Possible cyclic reference
+				meta.SkipDir = true
+			}
 		}
 	}
 
@@ -310,6 +328,14 @@ func (w *Walkway) walk(path string, info FileMetaInfo, dirEntries []FileMetaInfo
 	return nil
 }
 
+// isSameOrParentDir reports whether dir is the same as or a parent of filename.
+func isSameOrParentDir(dir, filename string) bool {
+	if dir == "" || filename == "" {
+		return false
+	}
+	return dir == filename || strings.HasPrefix(filename, strings.TrimSuffix(dir, filepathSeparator)+filepathSeparator)
+}
+
 func (w *Walkway) isSeen(filename string) bool {
 	if filename == "" {
 		return false
@@ -77,3 +77,72 @@ Page not found
 Base:
 Page not found`)
 }
+
+func TestErrorPages(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+defaultContentLanguage = "en"
+[errorPages]
+codes = [404, 410, 500]
+sections = true
+formats = ["netlify", "cloudfront"]
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+-- content/docs/_index.md --
+---
+title: "Docs"
+---
+-- content/blog/_index.md --
+---
+title: "Blog"
+---
+-- content/docs/_index.nn.md --
+---
+title: "Dokumentasjon"
+---
+-- layouts/404.html --
+{{ .Params.statusCode }}|{{ .Params.section }}|{{ .Language.Lang }}|404.html
+-- layouts/docs/404.html --
+{{ .Params.statusCode }}|{{ .Language.Lang }}|docs/404.html
+-- layouts/500.html --
+{{ .Params.statusCode }}|{{ .Language.Lang }}|500.html
+-- layouts/index.html --
+Home.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/404.html", "404||en|404.html")
+	b.AssertFileContent("public/410.html", "410||en|404.html")
+	b.AssertFileContent("public/500.html", "500|en|500.html")
+	b.AssertFileContent("public/docs/404.html", "404|en|docs/404.html")
+	b.AssertFileContent("public/docs/410.html", "410|en|docs/404.html")
+	b.AssertFileContent("public/docs/500.html", "500|en|500.html")
+	b.AssertFileContent("public/blog/404.html", "404|blog|en|404.html")
+	b.AssertFileContent("public/nn/404.html", "404||nn|404.html")
+	b.AssertFileContent("public/nn/docs/404.html", "404|nn|docs/404.html")
+	b.AssertDestinationExists("public/nn/blog/404.html", false)
+
+	b.AssertFileContentExact("public/_redirects", `/nn/docs/* /nn/docs/404.html 404
+/blog/* /blog/404.html 404
+/docs/* /docs/404.html 404
+/nn/* /nn/404.html 404
+`)
+
+	b.AssertFileContent("public/cloudfront-error-responses.json", `"ErrorCode": 404,
+      "ResponsePagePath": "/404.html",
+      "ResponseCode": "404",`, `"ErrorCode": 500,
+      "ResponsePagePath": "/500.html",`, `"Quantity": 3`)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/gohugoio/hugo/output"
 	"github.com/gohugoio/hugo/publisher"
 	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/resources/resource"
 	"github.com/gohugoio/hugo/tpl"
 )
 
@@ -102,7 +103,59 @@ func (s *Site) publishDestAlias(allowRoot bool, path, permalink string, outputFo
 		pd.AbsURLPath = s.absURLPath(targetPath)
 	}
 
-	return s.publisher.Publish(pd)
+	if err := s.publisher.Publish(pd); err != nil {
+		return err
+	}
+
+	e := buildManifestEntry{Path: targetPath, Kind: manifestKindAlias, OutputFormat: outputFormat.Name}
+	if p != nil && !p.File().IsZero() {
+		e.Source = s.h.manifestSource(p.File().Filename())
+	}
+	s.h.manifest.add(s.BaseFs.PublishFs, e)
+
+	return nil
+}
+
+// resourceAliases holds the aliases of a page resource.
+type resourceAliases struct {
+	r       resource.Resource
+	aliases []string
+}
+
+// publishResourceAlias publishes a copy of r at the alias path.
+func (s *Site) publishResourceAlias(alias string, r resource.Resource) error {
+	rsc, ok := r.(resource.ReadSeekCloserResource)
+	if !ok {
+		return fmt.Errorf("resource %q cannot be copied to alias %q", r.Name(), alias)
+	}
+
+	targetPath, err := cleanResourceAlias(alias)
+	if err != nil {
+		return err
+	}
+
+	f, err := rsc.ReadSeekCloser()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s.Log.Debugln("creating resource alias:", alias, "copy of", r.RelPermalink())
+
+	return s.publish(&s.PathSpec.ProcessingStats.Aliases, targetPath, f, s.BaseFs.PublishFs)
+}
+
+// cleanResourceAlias validates alias, a resource path relative to the
+// publish root, and returns it as a file path.
+func cleanResourceAlias(alias string) (string, error) {
+	a := strings.TrimPrefix(path.Clean(alias), "/")
+	if a == "" || a == "." || strings.HasSuffix(alias, "/") {
+		return "", fmt.Errorf("resource alias %q must be a file path", alias)
+	}
+	if a == ".." || strings.HasPrefix(a, "../") {
+		return "", fmt.Errorf("resource alias %q traverses outside the website root directory", alias)
+	}
+	return filepath.FromSlash(a), nil
 }
 
 func (a aliasHandler) targetPathAlias(src string) (string, error) {
@@ -16,6 +16,7 @@ package hugolib
 import (
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/gohugoio/hugo/common/loggers"
@@ -157,3 +158,67 @@ func TestTargetPathHTMLRedirectAlias(t *testing.T) {
 		}
 	}
 }
+
+func TestResourceAliases(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "rss", "sitemap", "robotsTXT", "404"]
+-- content/post/index.md --
+---
+title: "Post"
+resources:
+- src: "images/logo.svg"
+  name: "logo"
+  aliases: ["logo-old.svg", "/img/logo.svg"]
+- src: "*.txt"
+  aliases: ["../download.txt"]
+---
+-- content/post/images/logo.svg --
+<svg></svg>
+-- content/post/data.txt --
+Data.
+-- layouts/_default/single.html --
+{{ (.Resources.Get "logo").RelPermalink }}
+-- layouts/index.html --
+Home.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/post/index.html", "/post/images/logo.svg")
+	b.AssertFileContent("public/post/logo-old.svg", "<svg></svg>")
+	b.AssertFileContent("public/img/logo.svg", "<svg></svg>")
+	b.AssertFileContent("public/download.txt", "Data.")
+	b.AssertFileContent("public/post/data.txt", "Data.")
+
+	files = strings.Replace(files, "-- content/", `[redirects]
+formats = ["netlify"]
+-- content/`, 1)
+
+	b = NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	)
+	b.Build()
+
+	b.AssertFileContent("public/_redirects", `
+/post/logo-old.svg /post/images/logo.svg 301
+/img/logo.svg /post/images/logo.svg 301
+/download.txt /post/data.txt 301
+`)
+	b.AssertDestinationExists("public/img/logo.svg", false)
+
+	_, to, found := b.H.MatchRedirect("", "/img/logo.svg", "")
+	b.Assert(found, qt.IsTrue)
+	b.Assert(to, qt.Equals, "/post/images/logo.svg")
+}
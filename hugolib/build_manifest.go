@@ -0,0 +1,291 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/spf13/afero"
+)
+
+const buildManifestFilename = "build-manifest.json"
+
+// The kinds of published files in the build manifest.
+const (
+	manifestKindPage     = "page"
+	manifestKindAlias    = "alias"
+	manifestKindResource = "resource"
+	manifestKindFile     = "file"
+	manifestKindStatic   = "static"
+)
+
+// buildManifest collects the files published in the build with their
+// sources, written to build-manifest.json when build.writeManifest is enabled.
+// The entries are kept between rebuilds in server mode, as not all files are
+// published again.
+type buildManifest struct {
+	mu      sync.Mutex
+	entries map[string]buildManifestEntry
+}
+
+type buildManifestEntry struct {
+	// The slash separated path relative to the publish dir.
+	Path string `json:"path"`
+
+	// One of page, alias, resource, file or static.
+	Kind string `json:"kind"`
+
+	// The slash separated source filename, relative to the working dir if
+	// possible. Empty if not backed by a file, e.g. a taxonomy list.
+	Source string `json:"source,omitempty"`
+
+	OutputFormat string `json:"outputFormat,omitempty"`
+
+	// The hex encoded SHA-256 of the published content.
+	Hash string `json:"hash"`
+	Size int64  `json:"size"`
+
+	// The templates used to render the file.
+	Dependencies []string `json:"dependencies,omitempty"`
+
+	// The HTTP headers of a resource or static file, see cacheControl.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// The filesystem and filename to read the published content from.
+	fs       afero.Fs
+	filename string
+}
+
+func newBuildManifestIfEnabled(cfg config.Provider) *buildManifest {
+	if !config.DecodeBuild(cfg).WriteManifest {
+		return nil
+	}
+	return &buildManifest{entries: make(map[string]buildManifestEntry)}
+}
+
+// add adds e published to fs. This is a no-op if m is nil, i.e. the manifest
+// is not enabled.
+func (m *buildManifest) add(fs afero.Fs, e buildManifestEntry) {
+	if m == nil {
+		return
+	}
+	e.fs, e.filename = fs, e.Path
+	e.Path = manifestPath(e.Path)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[e.Path] = e
+}
+
+func (m *buildManifest) remove(path string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, path)
+}
+
+func (m *buildManifest) snapshot() []buildManifestEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries := make([]buildManifestEntry, 0, len(m.entries))
+	for _, e := range m.entries {
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func (s *Site) addPageToManifest(kind, targetPath string, p *pageState, templ tpl.Template) {
+	if s.h.manifest == nil {
+		return
+	}
+
+	e := buildManifestEntry{
+		Path:         targetPath,
+		Kind:         kind,
+		OutputFormat: p.outputFormat().Name,
+	}
+	if !p.File().IsZero() {
+		e.Source = s.h.manifestSource(p.File().Filename())
+	}
+	if templ != nil {
+		e.Dependencies = []string{templ.Name()}
+	}
+
+	s.h.manifest.add(s.BaseFs.PublishFs, e)
+}
+
+// manifestSource returns filename relative to the working dir if possible.
+func (h *HugoSites) manifestSource(filename string) string {
+	if filename == "" {
+		return ""
+	}
+	if rel, err := filepath.Rel(h.WorkingDir, filename); err == nil && !strings.HasPrefix(rel, "..") {
+		filename = rel
+	}
+	return filepath.ToSlash(filename)
+}
+
+func manifestPath(filename string) string {
+	return strings.TrimPrefix(filepath.ToSlash(filename), "/")
+}
+
+// writeBuildManifest writes build-manifest.json to the working dir with all
+// the files published by the build, the resources and the static files.
+func (h *HugoSites) writeBuildManifest() error {
+	if h.manifest == nil {
+		return nil
+	}
+
+	entries := make(map[string]buildManifestEntry)
+	for _, e := range h.manifest.snapshot() {
+		entries[e.Path] = e
+	}
+
+	h.ResourceSpec.WalkPublishedResources(func(target, source string) {
+		path := manifestPath(target)
+		if _, found := entries[path]; found {
+			return
+		}
+		entries[path] = buildManifestEntry{
+			Path:     path,
+			Kind:     manifestKindResource,
+			Source:   h.manifestSource(source),
+			fs:       h.BaseFs.PublishFs,
+			filename: target,
+		}
+	})
+
+	// The static files are synced outside of the build, so we read them
+	// from the source.
+	err := h.walkStaticFiles(func(target, source string, fs afero.Fs, filename string) {
+		target = manifestPath(target)
+		if _, found := entries[target]; found {
+			return
+		}
+		entries[target] = buildManifestEntry{
+			Path:     target,
+			Kind:     manifestKindStatic,
+			Source:   h.manifestSource(source),
+			fs:       fs,
+			filename: filename,
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	fingerprinted := make(map[string]bool)
+	for _, target := range h.publishedFingerprinted() {
+		fingerprinted[manifestPath(target)] = true
+	}
+
+	var files []buildManifestEntry
+	for _, e := range entries {
+		if err := e.hashFile(); err != nil {
+			if os.IsNotExist(err) {
+				// Removed since it was published.
+				h.manifest.remove(e.Path)
+				continue
+			}
+			return err
+		}
+		if e.Kind == manifestKindResource || e.Kind == manifestKindStatic {
+			for _, kv := range h.ResourceSpec.CacheControl.MatchHeaders("/"+e.Path, fingerprinted[e.Path]) {
+				if e.Headers == nil {
+					e.Headers = make(map[string]string)
+				}
+				e.Headers[kv.Key] = kv.Value
+			}
+		}
+		files = append(files, e)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	b, err := json.MarshalIndent(struct {
+		Files []buildManifestEntry `json:"files"`
+	}{Files: files}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	filename := filepath.Join(h.WorkingDir, buildManifestFilename)
+
+	// Make sure it's always written to the OS fs.
+	if err := afero.WriteFile(hugofs.Os, filename, b, 0666); err != nil {
+		return err
+	}
+
+	// Write to the destination as well if it's a in-memory fs.
+	if !hugofs.IsOsFs(h.Fs.Source) {
+		if err := afero.WriteFile(h.Fs.WorkingDirWritable, filename, b, 0666); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// walkStaticFiles calls fn with the target and source filenames of all the
+// static files and the filesystem and filename to read them from.
+func (h *HugoSites) walkStaticFiles(fn func(target, source string, fs afero.Fs, filename string)) error {
+	for _, sfs := range h.BaseFs.SourceFilesystems.Static {
+		sfs := sfs
+		err := afero.Walk(sfs.Fs, "", func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+			var source string
+			if fim, ok := info.(hugofs.FileMetaInfo); ok {
+				source = fim.Meta().Filename
+			}
+			fn(filepath.Join(sfs.PublishFolder, path), source, sfs.Fs, path)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *buildManifestEntry) hashFile() error {
+	f, err := e.fs.Open(e.filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	n, err := io.Copy(hash, f)
+	if err != nil {
+		return err
+	}
+
+	e.Hash = hex.EncodeToString(hash.Sum(nil))
+	e.Size = n
+
+	return nil
+}
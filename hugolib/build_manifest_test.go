@@ -0,0 +1,96 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"encoding/json"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestBuildManifest(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+[build]
+writeManifest = true
+-- content/posts/p1/index.md --
+---
+title: "P1"
+aliases: ["/old/p1/"]
+---
+-- content/posts/p1/data.txt --
+Data.
+-- content/files/doc.txt --
+Doc.
+-- assets/css/main.css --
+body { color: red; }
+-- static/favicon.ico --
+Icon.
+-- layouts/index.html --
+{{ $css := resources.Get "css/main.css" | minify }}
+Home: {{ $css.RelPermalink }}
+-- layouts/_default/single.html --
+Single: {{ .Title }}
+-- layouts/_default/list.html --
+List: {{ .Title }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			NeedsOsFS:   true,
+		},
+	).Build()
+
+	var manifest struct {
+		Files []buildManifestEntry `json:"files"`
+	}
+	b.Assert(json.Unmarshal([]byte(b.FileContent("build-manifest.json")), &manifest), qt.IsNil)
+
+	byPath := make(map[string]buildManifestEntry)
+	for _, e := range manifest.Files {
+		b.Assert(e.Hash, qt.HasLen, 64)
+		e.Hash = ""
+		byPath[e.Path] = e
+	}
+
+	b.Assert(byPath["posts/p1/index.html"], qt.CmpEquals(cmpopts.IgnoreUnexported(buildManifestEntry{})), buildManifestEntry{
+		Path:         "posts/p1/index.html",
+		Kind:         "page",
+		Source:       "content/posts/p1/index.md",
+		OutputFormat: "HTML",
+		Size:         10,
+		Dependencies: []string{"_default/single.html"},
+	})
+	b.Assert(byPath["index.html"].Source, qt.Equals, "")
+	b.Assert(byPath["index.html"].Dependencies, qt.DeepEquals, []string{"index.html"})
+	b.Assert(byPath["old/p1/index.html"].Kind, qt.Equals, "alias")
+	b.Assert(byPath["old/p1/index.html"].Source, qt.Equals, "content/posts/p1/index.md")
+	b.Assert(byPath["posts/p1/data.txt"].Kind, qt.Equals, "resource")
+	b.Assert(byPath["posts/p1/data.txt"].Source, qt.Equals, "content/posts/p1/data.txt")
+	b.Assert(byPath["files/doc.txt"].Kind, qt.Equals, "file")
+	b.Assert(byPath["files/doc.txt"].Source, qt.Equals, "content/files/doc.txt")
+	b.Assert(byPath["css/main.min.css"].Kind, qt.Equals, "resource")
+	b.Assert(byPath["css/main.min.css"].Source, qt.Equals, "assets/css/main.css")
+	b.Assert(byPath["favicon.ico"].Kind, qt.Equals, "static")
+	b.Assert(byPath["favicon.ico"].Source, qt.Equals, "static/favicon.ico")
+	b.Assert(byPath["favicon.ico"].Size, qt.Equals, int64(5))
+}
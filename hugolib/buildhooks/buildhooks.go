@@ -0,0 +1,161 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package buildhooks provides the hooks run at the stages of a build,
+// Go callbacks registered with Hooks and external commands set in config.
+package buildhooks
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/mitchellh/mapstructure"
+)
+
+// The build events, in the order they happen in a build.
+const (
+	// ConfigLoaded happens at the start of a full build, after the
+	// configuration is loaded.
+	ConfigLoaded = "configLoaded"
+
+	// ContentRead happens when the content, data and templates are read.
+	ContentRead = "contentRead"
+
+	// BeforeRender happens before the pages are rendered.
+	BeforeRender = "beforeRender"
+
+	// AfterPublish happens when all files are published without errors.
+	AfterPublish = "afterPublish"
+)
+
+// Events are the build events in the order they happen in a build.
+var Events = []string{ConfigLoaded, ContentRead, BeforeRender, AfterPublish}
+
+func isEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Context describes the build for a hook. It is passed as JSON on stdin
+// to external commands.
+type Context struct {
+	// The build event, e.g. afterPublish.
+	Event string `json:"event"`
+
+	// The build environment, e.g. production.
+	Environment string `json:"environment"`
+
+	// The absolute paths of the project and the publish dir.
+	WorkingDir string `json:"workingDir"`
+	PublishDir string `json:"publishDir"`
+
+	// The language codes of the sites built.
+	Languages []string `json:"languages"`
+
+	// The number of pages in all sites, 0 before contentRead.
+	Pages int `json:"pages"`
+
+	// Whether this is a partial rebuild in server mode.
+	Rebuild bool `json:"rebuild"`
+}
+
+// Func is a Go callback run for a build event. An error fails the build.
+type Func func(ctx Context) error
+
+// Hooks holds the Go callbacks to run for the build events.
+type Hooks struct {
+	mu    sync.RWMutex
+	funcs map[string][]Func
+}
+
+// New creates a new Hooks.
+func New() *Hooks {
+	return &Hooks{funcs: make(map[string][]Func)}
+}
+
+// Add registers f to run on event, one of Events.
+func (h *Hooks) Add(event string, f Func) error {
+	if !isEvent(event) {
+		return fmt.Errorf("unknown build event %q, must be one of %s", event, strings.Join(Events, ", "))
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.funcs[event] = append(h.funcs[event], f)
+	return nil
+}
+
+// Funcs returns the callbacks registered for event in the order added.
+func (h *Hooks) Funcs(event string) []Func {
+	if h == nil {
+		return nil
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.funcs[event]
+}
+
+// Command is an external command to run for a build event. The command
+// must be allowed in security.exec.allow.
+type Command struct {
+	// The name of the command, e.g. pagefind.
+	Command string
+
+	Args []string
+}
+
+// Config holds the commands to run by build event, configured in
+// buildHooks in site config.
+type Config map[string][]Command
+
+// DecodeConfig decodes the buildHooks section in site config.
+func DecodeConfig(in any) (Config, error) {
+	c := make(Config)
+	if in == nil {
+		return c, nil
+	}
+
+	m, err := maps.ToStringMapE(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode buildHooks: %w", err)
+	}
+
+	for k, v := range m {
+		event := ""
+		for _, e := range Events {
+			if strings.EqualFold(e, k) {
+				event = e
+			}
+		}
+		if event == "" {
+			return nil, fmt.Errorf("buildHooks: unknown build event %q, must be one of %s", k, strings.Join(Events, ", "))
+		}
+		var commands []Command
+		if err := mapstructure.WeakDecode(v, &commands); err != nil {
+			return nil, fmt.Errorf("failed to decode buildHooks.%s: %w", event, err)
+		}
+		for _, cmd := range commands {
+			if cmd.Command == "" || strings.ContainsAny(cmd.Command, `/\`) {
+				return nil, fmt.Errorf("buildHooks.%s: command must be the name of a command in PATH, got %q", event, cmd.Command)
+			}
+		}
+		c[event] = commands
+	}
+
+	return c, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildhooks
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := DecodeConfig(map[string]any{
+		"afterpublish": []any{
+			map[string]any{"command": "pagefind", "args": []any{"--site", "public"}},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(cfg, qt.DeepEquals, Config{
+		AfterPublish: {{Command: "pagefind", Args: []string{"--site", "public"}}},
+	})
+
+	_, err = DecodeConfig(map[string]any{"afterDeploy": []any{}})
+	c.Assert(err, qt.ErrorMatches, `buildHooks: unknown build event "afterDeploy".*`)
+
+	_, err = DecodeConfig(map[string]any{"beforeRender": []any{map[string]any{"command": "/bin/sh"}}})
+	c.Assert(err, qt.ErrorMatches, `buildHooks.beforeRender: command must be .*`)
+}
+
+func TestHooks(t *testing.T) {
+	c := qt.New(t)
+
+	h := New()
+	var events []string
+	f := func(ctx Context) error {
+		events = append(events, ctx.Event)
+		return nil
+	}
+	c.Assert(h.Add(ContentRead, f), qt.IsNil)
+	c.Assert(h.Add("afterDeploy", f), qt.Not(qt.IsNil))
+	c.Assert(h.Funcs(ContentRead), qt.HasLen, 1)
+	c.Assert(h.Funcs(AfterPublish), qt.HasLen, 0)
+
+	var nilHooks *Hooks
+	c.Assert(nilHooks.Funcs(ContentRead), qt.IsNil)
+}
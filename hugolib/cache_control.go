@@ -0,0 +1,149 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+
+	"github.com/gohugoio/hugo/common/types"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/spf13/afero"
+)
+
+// The file names used for the cache control formats.
+var cacheControlFilenames = map[string]string{
+	config.CacheControlFormatNetlify: "_headers",
+	config.CacheControlFormatVercel:  "vercel.json",
+}
+
+type cacheControlHeaders struct {
+	path    string
+	headers []types.KeyValueStr
+}
+
+// writeCacheControlHeaders publishes the headers of the published resources
+// and static files in the formats set in cacheControl.formats.
+func (h *HugoSites) writeCacheControlHeaders() error {
+	cfg := h.ResourceSpec.CacheControl
+	if !cfg.IsEnabled() {
+		return nil
+	}
+
+	fingerprinted := make(map[string]bool)
+	for _, target := range h.publishedFingerprinted() {
+		fingerprinted[target] = true
+	}
+
+	targets := make(map[string]bool)
+	h.ResourceSpec.WalkPublishedResources(func(target, source string) {
+		targets[path.Clean("/"+filepath.ToSlash(target))] = true
+	})
+	err := h.walkStaticFiles(func(target, source string, fs afero.Fs, filename string) {
+		targets[path.Clean("/"+filepath.ToSlash(target))] = true
+	})
+	if err != nil {
+		return err
+	}
+
+	var all []cacheControlHeaders
+	for target := range targets {
+		if headers := cfg.MatchHeaders(target, fingerprinted[target]); headers != nil {
+			all = append(all, cacheControlHeaders{path: target, headers: headers})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].path < all[j].path
+	})
+
+	for _, f := range cfg.Formats {
+		var b []byte
+		switch f {
+		case config.CacheControlFormatNetlify:
+			b = cacheControlNetlify(all)
+		case config.CacheControlFormatVercel:
+			b, err = h.cacheControlVercel(all)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create %s headers: %w", f, err)
+		}
+		if len(b) == 0 {
+			continue
+		}
+		if err := helpers.WriteToDisk(cacheControlFilenames[f], bytes.NewReader(b), h.BaseFs.PublishFs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cacheControlNetlify creates a Netlify _headers file, also used by
+// Cloudflare Pages.
+func cacheControlNetlify(all []cacheControlHeaders) []byte {
+	var b bytes.Buffer
+	for _, h := range all {
+		fmt.Fprintln(&b, h.path)
+		for _, kv := range h.headers {
+			fmt.Fprintf(&b, "  %s: %s\n", kv.Key, kv.Value)
+		}
+	}
+	return b.Bytes()
+}
+
+// cacheControlVercel adds the headers to vercel.json, keeping the redirects
+// and rewrites published before.
+func (h *HugoSites) cacheControlVercel(all []cacheControlHeaders) ([]byte, error) {
+	type header struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	type route struct {
+		Source  string   `json:"source"`
+		Headers []header `json:"headers"`
+	}
+
+	cfg := make(map[string]any)
+	filename := cacheControlFilenames[config.CacheControlFormatVercel]
+	if b, err := afero.ReadFile(h.BaseFs.PublishFs, filename); err == nil {
+		if err := json.Unmarshal(b, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+		}
+	}
+
+	routes := make([]route, 0, len(all))
+	for _, h := range all {
+		r := route{Source: h.path}
+		for _, kv := range h.headers {
+			r.Headers = append(r.Headers, header{Key: kv.Key, Value: kv.Value})
+		}
+		routes = append(routes, r)
+	}
+
+	if len(routes) == 0 {
+		delete(cfg, "headers")
+	} else {
+		cfg["headers"] = routes
+	}
+	if len(cfg) == 0 {
+		return nil, nil
+	}
+
+	return json.MarshalIndent(cfg, "", "  ")
+}
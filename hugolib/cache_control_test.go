@@ -0,0 +1,95 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestCacheControl(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404", "section", "page"]
+[build]
+writeManifest = true
+[cacheControl]
+formats = ["netlify", "vercel"]
+[[cacheControl.rules]]
+for = "/css/**"
+cacheControl = "public, max-age=3600"
+[[cacheControl.rules]]
+for = "/**.woff2"
+cacheControl = "public, max-age=604800"
+[cacheControl.rules.headers]
+Access-Control-Allow-Origin = "*"
+[redirects]
+formats = ["vercel"]
+[[redirects.rules]]
+from = "/old/"
+to = "/new/"
+-- assets/css/main.css --
+body { color: red; }
+-- assets/css/print.css --
+body { color: black; }
+-- static/fonts/font.woff2 --
+Font.
+-- static/favicon.ico --
+Icon.
+-- layouts/index.html --
+{{ $main := resources.Get "css/main.css" | fingerprint "md5" }}
+{{ $print := resources.Get "css/print.css" | minify }}
+Home: {{ $main.RelPermalink }}|{{ $print.RelPermalink }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html", "Home: /css/main.f2b804d3e3bd61d76922a667f90e66d8.css|/css/print.min.css")
+
+	b.AssertFileContent("public/_headers", `/css/main.f2b804d3e3bd61d76922a667f90e66d8.css
+  Cache-Control: public, max-age=31536000, immutable
+/css/print.min.css
+  Cache-Control: public, max-age=3600
+/fonts/font.woff2
+  Access-Control-Allow-Origin: *
+  Cache-Control: public, max-age=604800
+`)
+
+	b.Assert(b.FileContent("public/_headers"), qt.Not(qt.Contains), "favicon")
+
+	b.AssertFileContent("public/vercel.json",
+		`"source": "/old/"`,
+		`"source": "/css/print.min.css",
+      "headers": [
+        {
+          "key": "Cache-Control",
+          "value": "public, max-age=3600"
+        }
+      ]`,
+	)
+
+	b.AssertFileContent("build-manifest.json", `"path": "css/main.f2b804d3e3bd61d76922a667f90e66d8.css",
+      "kind": "resource",`, `"headers": {
+        "Cache-Control": "public, max-age=31536000, immutable"
+      }`)
+}
@@ -628,3 +628,79 @@ S1|p1:|p2:p2|
 		`)
 	})
 }
+
+func TestCascadeComputedParams(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+[[cascade]]
+year = { expr = ".Date.Year", type = "int" }
+titleLen = { expr = "len .Title" }
+[cascade._target]
+kind = "page"
+-- content/posts/p1.md --
+---
+title: "Post 1"
+date: 2019-03-01
+---
+-- content/posts/p2.md --
+---
+title: "Post Two"
+date: 2022-01-15
+---
+-- content/posts/p3.md --
+---
+title: "Post 3"
+date: 2022-05-15
+year: 1999
+---
+-- content/posts/p4.md --
+---
+title: "Post 4"
+date: 2022-06-15
+secret: { expr: 'readFile "config.toml"' }
+---
+-- content/posts/_index.md --
+---
+title: "Posts"
+cascade:
+  other: { expr: ".Title" }
+---
+-- layouts/index.html --
+{{ range where site.RegularPages "Params.year" 2022 }}{{ .Title }}|{{ .Params.year }}|{{ printf "%T" .Params.year }}|{{ .Params.titlelen }}|{{ end }}
+{{ range where site.RegularPages "Params.year" "<" 2020 }}{{ .Title }}|{{ end }}
+{{ with site.GetPage "posts/p4" }}Secret: {{ .Params.secret.expr }}|Other: {{ .Params.other.expr }}|{{ end }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	// An expr map in content front matter, including its cascade, is a regular param.
+	b.AssertFileContent("public/index.html", `
+Post 4|2022|int|6|Post Two|2022|int|8|
+Post 3|Post 1|
+Secret: readFile &#34;config.toml&#34;|Other: .Title|
+`)
+}
+
+func TestCascadeComputedParamsContentDependent(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+[[cascade]]
+reading_level = { expr = "cond (gt .WordCount 500) \"advanced\" \"basic\"" }
+`)
+	b.WithContent("p1.md", "---\ntitle: P1\n---\n")
+
+	err := b.CreateSitesE()
+	b.Assert(err, qt.Not(qt.IsNil))
+	b.Assert(err.Error(), qt.Contains, `computed param "reading_level": .WordCount depends on the rendered content`)
+}
@@ -14,6 +14,7 @@
 package hugolib
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -114,6 +115,10 @@ func LoadConfig(d ConfigSourceDescriptor, doWithConfig ...func(cfg config.Provid
 		return l.cfg, configFiles, err
 	}
 
+	if err := l.applyProfile(); err != nil {
+		return l.cfg, configFiles, err
+	}
+
 	modulesConfig, err := l.loadModulesConfig()
 	if err != nil {
 		return l.cfg, configFiles, err
@@ -191,6 +196,10 @@ type ConfigSourceDescriptor struct {
 
 	// Defaults to os.Environ if not set.
 	Environ []string
+
+	// The name of the profile in the profiles section of the config to
+	// merge on top of the config, if set.
+	Profile string
 }
 
 func (d ConfigSourceDescriptor) configFileDir() string {
@@ -288,6 +297,35 @@ func (l configLoader) applyConfigDefaults() error {
 	return nil
 }
 
+// applyProfile merges the settings of the selected profile on top of the
+// config. Unless set in the profile, the profile is published to a sub
+// directory of publishDir with its name.
+func (l configLoader) applyProfile() error {
+	if l.Profile == "" {
+		return nil
+	}
+
+	name := strings.ToLower(l.Profile)
+	profiles := l.cfg.GetStringMap("profiles")
+	v, found := profiles[name]
+	if !found {
+		return fmt.Errorf("profile %q not found in the profiles config", l.Profile)
+	}
+	profile, err := maps.ToStringMapE(v)
+	if err != nil {
+		return fmt.Errorf("failed to decode profile %q: %w", l.Profile, err)
+	}
+
+	publishDir := l.cfg.GetString("publishDir")
+	l.cfg.Set("", profile)
+	if _, found := maps.LookupEqualFold(profile, "publishDir"); !found {
+		l.cfg.Set("publishDir", filepath.Join(publishDir, name))
+	}
+	l.cfg.Set("profile", name)
+
+	return nil
+}
+
 func (l configLoader) applyOsEnvOverrides(environ []string) error {
 	if len(environ) == 0 {
 		return nil
@@ -64,6 +64,23 @@ func (f ContentFactory) ApplyArchetypeFilename(w io.Writer, p page.Page, archety
 
 // ApplyArchetypeTemplate templateSource to w as a template using the given Page p as the foundation for the data context.
 func (f ContentFactory) ApplyArchetypeTemplate(w io.Writer, p page.Page, archetypeKind, templateSource string) error {
+	templateSource = f.shortcodeReplacerPre.Replace(templateSource)
+
+	var b strings.Builder
+	if err := f.ExecuteArchetypeTemplate(&b, p, archetypeKind, "archetype.md", templateSource); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, f.shortcodeReplacerPost.Replace(b.String()))
+
+	return err
+
+}
+
+// ExecuteArchetypeTemplate executes templateSource as is, e.g. a templated file name or
+// a resource template in a directory archetype, and writes the result to w.
+// The given Page p is used as the foundation for the data context.
+func (f ContentFactory) ExecuteArchetypeTemplate(w io.Writer, p page.Page, archetypeKind, name, templateSource string) error {
 	ps := p.(*pageState)
 	if archetypeKind == "" {
 		archetypeKind = p.Type()
@@ -76,22 +93,16 @@ func (f ContentFactory) ApplyArchetypeTemplate(w io.Writer, p page.Page, archety
 		File: p.File(),
 	}
 
-	templateSource = f.shortcodeReplacerPre.Replace(templateSource)
-
-	templ, err := ps.s.TextTmpl().Parse("archetype.md", string(templateSource))
+	templ, err := ps.s.TextTmpl().Parse(name, templateSource)
 	if err != nil {
 		return fmt.Errorf("failed to parse archetype template: %s: %w", err, err)
 	}
 
-	result, err := executeToString(ps.s.Tmpl(), templ, d)
-	if err != nil {
+	if err := ps.s.Tmpl().Execute(templ, w, d); err != nil {
 		return fmt.Errorf("failed to execute archetype template: %s: %w", err, err)
 	}
 
-	_, err = io.WriteString(w, f.shortcodeReplacerPost.Replace(result))
-
-	return err
-
+	return nil
 }
 
 func (f ContentFactory) SectionFromFilename(filename string) (string, error) {
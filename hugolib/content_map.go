@@ -735,6 +735,7 @@ func (m *contentMap) testDump() string {
 type contentMapConfig struct {
 	lang                 string
 	taxonomyConfig       []viewName
+	taxonomyOptions      map[string]taxonomyOptions
 	taxonomyDisabled     bool
 	taxonomyTermDisabled bool
 	pageDisabled         bool
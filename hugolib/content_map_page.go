@@ -84,6 +84,54 @@ func (m *pageMap) createMissingTaxonomyNodes() error {
 	return nil
 }
 
+// mergeLowCountTerms moves the pages of the terms with fewer pages than the
+// minTermCount of their taxonomy to the otherTerm of that taxonomy.
+func (m *pageMap) mergeLowCountTerms() error {
+	if m.cfg.taxonomyDisabled {
+		return nil
+	}
+	for plural, opts := range m.cfg.taxonomyOptions {
+		if opts.MinTermCount <= 1 {
+			continue
+		}
+		prefix := cleanSectionTreeKey(plural)
+		otherKey := m.s.getTaxonomyKey(opts.OtherTerm)
+
+		counts := make(map[string]int)
+		m.taxonomyEntries.WalkPrefix(prefix, func(s string, v any) bool {
+			counts[v.(*contentNode).viewInfo.termKey]++
+			return false
+		})
+
+		type entry struct {
+			key string
+			n   *contentNode
+		}
+		var merged []entry
+		m.taxonomyEntries.WalkPrefix(prefix, func(s string, v any) bool {
+			n := v.(*contentNode)
+			termKey := n.viewInfo.termKey
+			if termKey != otherKey && counts[termKey] < opts.MinTermCount {
+				merged = append(merged, entry{key: s, n: n})
+			}
+			return false
+		})
+
+		for _, e := range merged {
+			vi := *e.n.viewInfo
+			m.taxonomyEntries.Delete(e.key)
+			m.taxonomies.Delete(cleanSectionTreeKey(path.Join(plural, vi.termKey)))
+
+			key := prefix + otherKey + strings.TrimPrefix(e.key, prefix+vi.termKey)
+			vi.termKey = otherKey
+			vi.termOrigin = opts.OtherTerm
+			m.taxonomyEntries.Insert(key, &contentNode{viewInfo: &vi})
+		}
+	}
+
+	return nil
+}
+
 func (m *pageMap) newPageFromContentNode(n *contentNode, parentBucket *pagesMapBucket, owner *pageState) (*pageState, error) {
 	if n.fi == nil {
 		panic("FileInfo must (currently) be set")
@@ -173,6 +221,8 @@ func (m *pageMap) newPageFromContentNode(n *contentNode, parentBucket *pagesMapB
 		return nil, err
 	}
 
+	metaProvider.llmsExcluded = !metaProvider.s.siteCfg.llms.Includes(ps)
+
 	ps.init.Add(func() (any, error) {
 		pp, err := newPagePaths(s, ps, metaProvider)
 		if err != nil {
@@ -256,7 +306,7 @@ func (m *pageMap) newResource(fim hugofs.FileMetaInfo, owner *pageState) (resour
 
 	target := strings.TrimPrefix(meta.Path, owner.File().Dir())
 
-	return owner.s.ResourceSpec.New(
+	rs, err := owner.s.ResourceSpec.New(
 		resources.ResourceSourceDescriptor{
 			TargetPaths:        owner.getTargetPaths,
 			OpenReadSeekCloser: r,
@@ -265,6 +315,11 @@ func (m *pageMap) newResource(fim hugofs.FileMetaInfo, owner *pageState) (resour
 			TargetBasePaths:    targetBasePaths,
 			LazyPublish:        !owner.m.buildConfig.PublishResources,
 		})
+	if err != nil {
+		return nil, err
+	}
+
+	return owner.s.ResourceSpec.ProcessSectionImage(rs, owner.Section())
 }
 
 func (m *pageMap) createSiteTaxonomies() error {
@@ -368,7 +423,7 @@ func (m *pageMap) assemblePages() error {
 			return true
 		}
 
-		shouldBuild = !(n.p.Kind() == page.KindPage && m.cfg.pageDisabled) && m.s.shouldBuild(n.p)
+		shouldBuild = !(n.p.m.kind == page.KindPage && m.cfg.pageDisabled) && m.s.shouldBuild(n.p)
 		if !shouldBuild {
 			m.deletePage(s)
 			return false
@@ -719,6 +774,10 @@ func (m *pageMaps) AssemblePages() error {
 			return err
 		}
 
+		if err := pm.mergeLowCountTerms(); err != nil {
+			return err
+		}
+
 		if err := pm.createMissingTaxonomyNodes(); err != nil {
 			return err
 		}
@@ -15,8 +15,11 @@ package hugolib
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/bep/clock"
 	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/htime"
 
 	"strings"
 	"testing"
@@ -204,6 +207,65 @@ timeZone = "America/LosAngeles"   # Should be America/Los_Angeles
 	b.Assert(err.Error(), qt.Contains, `failed to load config: invalid timeZone for language "en": unknown time zone America/LosAngeles`)
 }
 
+func TestTimeZonePage(t *testing.T) {
+	htime.Clock = clock.Start(time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC))
+	t.Cleanup(func() { htime.Clock = clock.System() })
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+timeZone = "UTC"
+-- content/utc.md --
+---
+title: UTC
+publishDate: 2030-01-01T10:00:00
+---
+-- content/newyork.md --
+---
+title: New York
+publishDate: 2030-01-01T10:00:00
+timeZone: America/New_York
+---
+-- content/tokyo/_index.md --
+---
+title: Tokyo
+cascade:
+  timeZone: Asia/Tokyo
+---
+-- content/tokyo/p1.md --
+---
+title: Tokyo P1
+date: 2029-07-10T15:28:01
+lastmod: 2029-07-10T06:28:01Z
+---
+-- layouts/_default/single.html --
+{{ .Title }}|Date: {{ .Date | safeHTML }}|Lastmod: {{ .Lastmod | safeHTML }}|
+-- layouts/_default/list.html --
+{{ range site.RegularPages }}{{ .Title }}|{{ end }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html", "UTC|")
+	b.Assert(b.FileContent("public/index.html"), qt.Not(qt.Contains), "New York")
+	b.AssertFileContent("public/tokyo/p1/index.html", "Tokyo P1|Date: 2029-07-10 15:28:01 +0900 JST|Lastmod: 2029-07-10 15:28:01 +0900 JST|")
+
+	b = NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: strings.ReplaceAll(files, "America/New_York", "America/NewYork"),
+		},
+	)
+	_, err := b.BuildE()
+	b.Assert(err, qt.Not(qt.IsNil))
+	b.Assert(err.Error(), qt.Contains, `invalid timeZone "America/NewYork" in front matter`)
+}
+
 // Issue 8835
 func TestTimeOnError(t *testing.T) {
 	b := newTestSitesBuilder(t)
@@ -214,3 +276,50 @@ func TestTimeOnError(t *testing.T) {
 	b.Assert(b.BuildE(BuildCfg{}), qt.Not(qt.IsNil))
 
 }
+
+func TestDatePrecision(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+[frontmatter]
+dateFormats = ["02.01.2006"]
+-- content/p1.md --
+---
+title: P1
+date: 2024
+---
+-- content/p2.md --
+---
+title: P2
+date: 2024-05
+---
+-- content/p3.md --
++++
+title = "P3"
+date = "17.05.2024"
++++
+-- content/p4.md --
+---
+title: P4
+date: 2024-05-17T10:00:00Z
+---
+-- layouts/_default/single.html --
+{{ $layouts := dict "year" "2006" "month" "January 2006" "day" "2 January 2006" "time" "2 January 2006 15:04" }}
+{{ .Title }}|{{ .DatePrecision }}|{{ .Date.Format (index $layouts .DatePrecision) }}|
+-- layouts/_default/list.html --
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html", "P1|year|2024|")
+	b.AssertFileContent("public/p2/index.html", "P2|month|May 2024|")
+	b.AssertFileContent("public/p3/index.html", "P3|day|17 May 2024|")
+	b.AssertFileContent("public/p4/index.html", "P4|time|17 May 2024 10:00|")
+}
@@ -416,3 +416,168 @@ title: Shorty
 
 	}
 }
+
+func TestShortcodeInclude(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "section", "RSS", "sitemap", "robotsTXT", "home"]
+-- content/docs/install.md --
+---
+title: "Install"
+---
+
+## Requirements
+
+You need Go[^go] and [Git](git/).
+See [usage](#usage) and [download](https://example.com/dl).
+
+![Logo](logo.png)
+
+### Versions
+
+Go 1.18.
+
+## Usage
+
+Run hugo.
+
+[^go]: See [go.dev](https://go.dev).
+-- content/guide.md --
+---
+title: "Guide"
+---
+
+Intro[^1].
+
+{{< include "/docs/install.md#requirements" >}}
+
+{{< include "/docs/install" >}}
+
+[^1]: Guide note.
+-- layouts/_default/single.html --
+{{ .Content }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	content := b.FileContent("public/guide/index.html")
+
+	b.AssertFileContent("public/guide/index.html",
+		`<h2 id="requirements">Requirements</h2>`,
+		`<a href="/docs/install/git/">Git</a>`,
+		`<a href="/docs/install/#usage">usage</a>`,
+		`<a href="https://example.com/dl">download</a>`,
+		`<img src="/docs/install/logo.png" alt="Logo">`,
+		`<h3 id="versions">Versions</h3>`,
+		`<a href="#fn:1" class="footnote-ref" role="doc-noteref">1</a>`,
+		`<a href="#usage">usage</a>`,
+		`<p>Run hugo.</p>`,
+	)
+
+	b.Assert(strings.Count(content, `<h2 id="requirements">`), qt.Equals, 2)
+	b.Assert(strings.Count(content, `<li id="fn:`), qt.Equals, 3)
+	b.Assert(content, qt.Contains, `See <a href="https://go.dev">go.dev</a>`)
+	b.Assert(content, qt.Not(qt.Contains), `Run hugo.</p>
+<div class="footnotes" role="doc-endnotes">
+<hr>
+<ol>
+<li id="fn:1"`)
+}
+
+func TestShortcodeIncludeCircular(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "section", "RSS", "sitemap", "robotsTXT", "home"]
+-- content/a.md --
+---
+title: "A"
+---
+## A
+
+{{< include "b.md" >}}
+-- content/b.md --
+---
+title: "B"
+---
+## B
+
+{{< include "a.md#a" >}}
+-- layouts/_default/single.html --
+{{ .Content }}
+`
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, "circular include")
+}
+
+func TestShortcodeGallery(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t)
+	b.WithConfigFile("toml", `
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "section", "RSS", "sitemap", "robotsTXT", "home"]
+[gallery]
+thumbnail = "fill 60x40"
+large = "fit 200x200"
+`)
+	b.WithContent("trip/index.md", `---
+title: "Trip"
+gallery:
+  sort: name
+  groupBy: dir
+resources:
+- src: "b/*"
+  title: "The B sunset"
+---
+
+{{< gallery >}}
+
+{{< gallery match="b/*" reverse="true" >}}
+`)
+	b.WithTemplates("_default/single.html", `{{ .Content }}
+Images: {{ with .Gallery }}{{ range .Images }}{{ .Image.Name }}|{{ .Date.Year }}|{{ .Thumbnail.Width }}x{{ .Thumbnail.Height }}|{{ end }}{{ end }}
+`)
+	b.WithSunset("content/trip/a/sunset.jpg")
+	b.WithSunset("content/trip/b/sunset1.jpg")
+	b.WithSunset("content/trip/b/sunset2.jpg")
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/trip/index.html",
+		"Images: a/sunset.jpg|2017|60x40|b/sunset1.jpg|2017|60x40|b/sunset2.jpg|2017|60x40|",
+		`<h3 class="gallery-group">a</h3>`,
+		`<h3 class="gallery-group">b</h3>`,
+		`<figure class="gallery-image">
+      <a href="/trip/a/sunset_hu`,
+		`<img src="/trip/a/sunset_hu`,
+		`width="60" height="40" loading="lazy"></a>
+    </figure>`,
+		`alt="The B sunset" loading="lazy"></a>
+      <figcaption>The B sunset</figcaption>`,
+	)
+
+	content := b.FileContent("public/trip/index.html")
+	b.Assert(strings.Count(content, `<figure class="gallery-image">`), qt.Equals, 5)
+	second := content[strings.LastIndex(content, `<div class="gallery">`):]
+	b.Assert(strings.Index(second, "/trip/b/sunset2_hu") < strings.Index(second, "/trip/b/sunset1_hu"), qt.IsTrue)
+	b.Assert(second, qt.Not(qt.Contains), "/trip/a/")
+}
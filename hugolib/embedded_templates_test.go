@@ -14,6 +14,7 @@
 package hugolib
 
 import (
+	"strings"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -167,3 +168,143 @@ func TestEmbeddedPaginationTemplate(t *testing.T) {
 	variant = `{{ template "_internal/pagination.html" (dict "page" . "format" "terse") }}`
 	test(variant, expectedOutputTerseFormat)
 }
+
+func TestInternalTemplatesCanonical(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+defaultContentLanguage = "en"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT"]
+[canonical]
+host = "https://www.example.org"
+[languages]
+[languages.en]
+weight = 1
+languageCode = "en-US"
+[languages.de]
+weight = 2
+[languages.de.canonical]
+host = "https://www.example.de"
+xDefault = "none"
+[languages.fr]
+weight = 3
+-- content/about.en.md --
+---
+title: About
+---
+-- content/about.de.md --
+---
+title: Über
+url: /ueber-uns/
+---
+-- content/alone.fr.md --
+---
+title: Seul
+canonical: /about/
+---
+-- layouts/_default/single.html --
+{{ template "_internal/canonical.html" . }}
+-- layouts/index.html --
+Home.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/about/index.html", `
+<link rel="canonical" href="https://www.example.org/about/" />
+<link rel="alternate" hreflang="en-US" href="https://www.example.org/about/" />
+<link rel="alternate" hreflang="de" href="https://www.example.de/ueber-uns/" />
+<link rel="alternate" hreflang="x-default" href="https://www.example.org/about/" />
+`)
+	b.AssertFileContent("public/ueber-uns/index.html", `
+<link rel="canonical" href="https://www.example.de/ueber-uns/" />
+<link rel="alternate" hreflang="de" href="https://www.example.de/ueber-uns/" />
+`)
+	b.AssertFileContent("public/fr/alone/index.html", `<link rel="canonical" href="https://www.example.org/about/" />`)
+
+	content := b.FileContent("public/ueber-uns/index.html")
+	b.Assert(content, qt.Not(qt.Contains), "x-default")
+	content = b.FileContent("public/fr/alone/index.html")
+	b.Assert(content, qt.Not(qt.Contains), "hreflang")
+}
+
+func TestInternalTemplatesAlternates(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+defaultContentLanguage = "en"
+disableKinds = ["taxonomy", "term", "sitemap", "robotsTXT"]
+[outputs]
+home = ["HTML", "RSS", "JSON"]
+page = ["HTML", "AMP"]
+[languages]
+[languages.en]
+weight = 1
+[languages.de]
+weight = 2
+languageCode = "de-DE"
+-- content/p1.en.md --
+---
+title: P1
+---
+-- content/p1.de.md --
+---
+title: P1 DE
+---
+-- content/p2.md --
+---
+title: P2
+---
+-- layouts/_default/single.html --
+{{ template "_internal/alternates.html" . }}
+-- layouts/_default/single.amp.html --
+{{ template "_internal/alternates.html" . }}
+-- layouts/index.html --
+<link rel="canonical" href="{{ .Canonical }}" />
+{{ template "_internal/alternates.html" . }}
+-- layouts/index.json --
+{{ range .Alternates }}{{ .Name }}|{{ .Rel }}|{{ .Lang }}|{{ .RelPermalink }};{{ end }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html", `
+<link rel="alternate" type="application/rss+xml" href="https://example.org/index.xml" />
+<link rel="alternate" type="application/json" href="https://example.org/index.json" />
+<link rel="alternate" type="text/html" hreflang="de-DE" href="https://example.org/de/" />
+<link rel="alternate" type="application/rss+xml" hreflang="de-DE" href="https://example.org/de/index.xml" />
+<link rel="alternate" type="application/json" hreflang="de-DE" href="https://example.org/de/index.json" />
+`)
+	content := b.FileContent("public/index.html")
+	b.Assert(strings.Count(content, `hreflang="de-DE"`), qt.Equals, 3)
+
+	b.AssertFileContent("public/index.json", "HTML|alternate|de-DE|/de/;RSS|alternate|de-DE|/de/index.xml;JSON|alternate|de-DE|/de/index.json;")
+
+	b.AssertFileContent("public/p1/index.html", `
+<link rel="amphtml" type="text/html" href="https://example.org/amp/p1/" />
+<link rel="alternate" type="text/html" hreflang="de-DE" href="https://example.org/de/p1/" />
+<link rel="alternate" type="text/html" hreflang="de-DE" href="https://example.org/de/amp/p1/" />
+`)
+	b.AssertFileContent("public/amp/p1/index.html", `
+<link rel="canonical" type="text/html" href="https://example.org/p1/" />
+<link rel="alternate" type="text/html" hreflang="de-DE" href="https://example.org/de/p1/" />
+<link rel="alternate" type="text/html" hreflang="de-DE" href="https://example.org/de/amp/p1/" />
+`)
+	b.AssertFileContent("public/p2/index.html", `<link rel="amphtml" type="text/html" href="https://example.org/amp/p2/" />`)
+	content = b.FileContent("public/p2/index.html")
+	b.Assert(content, qt.Not(qt.Contains), "hreflang")
+}
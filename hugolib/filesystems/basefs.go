@@ -29,6 +29,7 @@ import (
 	"github.com/gohugoio/hugo/hugofs/glob"
 
 	"github.com/gohugoio/hugo/common/types"
+	"github.com/gohugoio/hugo/config/security"
 
 	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/rogpeppe/go-internal/lockedfile"
@@ -591,9 +592,14 @@ func (b *sourceFilesystemsBuilder) createMainOverlayFs(p *paths.Paths) (*filesys
 		}
 	}
 
+	securityConfig, err := security.DecodeConfig(b.p.Cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	collector := &filesystemsCollector{
 		sourceProject:     b.sourceFs,
-		sourceModules:     hugofs.NewNoSymlinkFs(b.sourceFs, b.logger, false),
+		symlinks:          securityConfig.Symlinks,
 		overlayDirs:       make(map[string][]hugofs.FileMetaInfo),
 		staticPerLanguage: staticFsMap,
 
@@ -622,7 +628,7 @@ func (b *sourceFilesystemsBuilder) createMainOverlayFs(p *paths.Paths) (*filesys
 
 	}
 
-	err := b.createOverlayFs(collector, mounts)
+	err = b.createOverlayFs(collector, mounts)
 
 	return collector, err
 }
@@ -681,7 +687,7 @@ func (b *sourceFilesystemsBuilder) createOverlayFs(
 				types.ToStringSlicePreserveString(mount.ExcludeFiles),
 			)
 			if err != nil {
-				return err
+				return fmt.Errorf("invalid includeFiles or excludeFiles in mount %q: %w", mount.Source, err)
 			}
 
 			base, filename := absPathify(mount.Source)
@@ -718,11 +724,12 @@ func (b *sourceFilesystemsBuilder) createOverlayFs(
 			}
 		}
 
+		allowSymlink := collector.symlinkAllower(md.dir)
 		modBase := collector.sourceProject
 		if !md.isMainProject {
-			modBase = collector.sourceModules
+			modBase = hugofs.NewNoSymlinkFs(collector.sourceProject, b.logger, false, allowSymlink)
 		}
-		sourceStatic := hugofs.NewNoSymlinkFs(modBase, b.logger, true)
+		sourceStatic := hugofs.NewNoSymlinkFs(modBase, b.logger, true, allowSymlink)
 
 		rmfs, err := hugofs.NewRootMappingFs(modBase, fromTo...)
 		if err != nil {
@@ -797,9 +804,24 @@ func printFs(fs afero.Fs, path string, w io.Writer) {
 	})
 }
 
+// symlinkAllower returns a func that reports whether the symlink filename in
+// the module in dir is allowed by security.symlinks, matched against its
+// slash path relative to dir, e.g. content/shared.
+func (c *filesystemsCollector) symlinkAllower(dir string) func(filename string) bool {
+	return func(filename string) bool {
+		rel, err := filepath.Rel(dir, filename)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return false
+		}
+		return c.symlinks.Accept(filepath.ToSlash(rel))
+	}
+}
+
 type filesystemsCollector struct {
 	sourceProject afero.Fs // Source for project folders
-	sourceModules afero.Fs // Source for modules/themes
+
+	// The symlinks allowed in the modules/themes and static folders.
+	symlinks security.Whitelist
 
 	overlayMounts        *overlayfs.OverlayFs
 	overlayMountsContent *overlayfs.OverlayFs
@@ -0,0 +1,117 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+)
+
+const (
+	// The id in the assets file cache of the fingerprinted filenames of the
+	// previous builds, keyed by the filenames before fingerprinting and the
+	// hash algorithm.
+	fingerprintHistoryID = "fingerprint-history.json"
+
+	// The number of filenames kept per resource in the history.
+	fingerprintHistoryLimit = 10
+)
+
+// publishedFingerprinted returns the published fingerprinted filenames keyed
+// by the filenames before fingerprinting and the hash algorithm, with the
+// same base path, e.g. a language prefix.
+func (h *HugoSites) publishedFingerprinted() map[string]string {
+	published := make(map[string]bool)
+	h.ResourceSpec.WalkPublishedResources(func(target, source string) {
+		published[path.Clean("/"+filepath.ToSlash(target))] = true
+	})
+
+	fingerprinted := make(map[string]string)
+	h.ResourceSpec.WalkFingerprinted(func(name, outPath string) {
+		outPath = path.Clean("/" + outPath)
+		for target := range published {
+			if strings.HasSuffix(target, outPath) {
+				fingerprinted[strings.TrimSuffix(target, outPath)+path.Clean("/"+name)] = target
+			}
+		}
+	})
+
+	return fingerprinted
+}
+
+// writeFingerprintRenameMap writes the map of the fingerprinted filenames of
+// the previous builds to the current ones to fingerprint.renameMap.
+func (h *HugoSites) writeFingerprintRenameMap() error {
+	filename := h.ResourceSpec.Fingerprint.RenameMap
+	if filename == "" {
+		return nil
+	}
+
+	current := h.publishedFingerprinted()
+
+	cache := h.FileCaches.AssetsCache()
+
+	history := make(map[string][]string)
+	if _, b, err := cache.GetBytes(fingerprintHistoryID); err != nil {
+		return err
+	} else if b != nil {
+		if err := json.Unmarshal(b, &history); err != nil {
+			h.Log.Warnf("Failed to read the fingerprint history, starting over: %s", err)
+			history = make(map[string][]string)
+		}
+	}
+
+	renames := make(map[string]string)
+	for name, target := range current {
+		var names []string
+		for _, previous := range history[name] {
+			if previous != target {
+				renames[previous] = target
+				names = append(names, previous)
+			}
+		}
+		names = append(names, target)
+		if len(names) > fingerprintHistoryLimit {
+			names = names[len(names)-fingerprintHistoryLimit:]
+		}
+		history[name] = names
+	}
+
+	b, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, w, err := cache.WriteCloser(fingerprintHistoryID)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	w.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write the fingerprint history: %w", err)
+	}
+
+	b, err = json.MarshalIndent(renames, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return helpers.WriteToDisk(filepath.FromSlash(filename), bytes.NewReader(b), h.BaseFs.PublishFs)
+}
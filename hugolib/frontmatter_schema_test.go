@@ -0,0 +1,113 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestFrontMatterSchema(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+[frontmatter.schemas.posts]
+title = { type = "string", required = true }
+category = { values = ["news", "releases"] }
+rating = { type = "int" }
+-- content/posts/valid.md --
+---
+title: "Valid"
+category: news
+rating: 3
+---
+-- content/posts/invalid.md --
+---
+draft: false
+category: gossip
+rating: "high"
+---
+-- content/docs/other.md --
+---
+category: gossip
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+`
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(b.H.NumLogErrors(), qt.Equals, 3)
+	b.AssertLogContains(`/content/posts/invalid.md:2:1": front matter field "category": value "gossip" is not one of ["news" "releases"]`)
+	b.AssertLogContains(`/content/posts/invalid.md:2:1": front matter field "rating": expected a value of type int, got string`)
+	b.AssertLogContains(`/content/posts/invalid.md:2:1": front matter field "title" is required`)
+}
+
+func TestFrontMatterSchemaArchetype(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+[frontmatter.schemas.posts]
+rating = { type = "float" }
+-- archetypes/posts.md --
+---
+title: "{{ replace .Name "-" " " | title }}"
+date: {{ .Date }}
+---
+-- archetypes/posts.schema.yaml --
+title: { type: string, required: true }
+rating: { type: int }
+author: { type: map }
+-- content/posts/valid.md --
+---
+title: "Valid"
+rating: 3.5
+author:
+  name: Jo
+---
+-- content/posts/invalid.md --
+---
+author:
+  rating: 5
+  title: "Nested"
+rating: "high"
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+`
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(b.H.NumLogErrors(), qt.Equals, 2)
+	// The site config wins, and the nested rating key is not reported.
+	b.AssertLogContains(`/content/posts/invalid.md:2:1": front matter field "rating": expected a value of type float, got string`)
+	b.AssertLogContains(`/content/posts/invalid.md:2:1": front matter field "title" is required`)
+}
@@ -786,6 +786,58 @@ weight = 2
 	}
 }
 
+func TestModulesSymlinksAllowed(t *testing.T) {
+	skipSymlink(t)
+
+	c := qt.New(t)
+	workingDir, clean, err := htesting.CreateTempDir(hugofs.Os, "hugo-mod-sym-allowed")
+	c.Assert(err, qt.IsNil)
+	defer clean()
+
+	cfg := config.NewWithTestDefaults()
+	cfg.Set("workingDir", workingDir)
+	fs := hugofs.NewFrom(hugofs.Os, cfg)
+
+	// A monorepo with content and static files shared by the theme.
+	sharedDir := filepath.Join(workingDir, "shared")
+	themeDir := filepath.Join(workingDir, "themes", "mymod")
+	c.Assert(os.MkdirAll(filepath.Join(sharedDir, "content", "loop"), 0777), qt.IsNil)
+	c.Assert(os.MkdirAll(filepath.Join(sharedDir, "static"), 0777), qt.IsNil)
+	for _, dir := range []string{"content", "static", "layouts/_default"} {
+		c.Assert(os.MkdirAll(filepath.Join(themeDir, filepath.FromSlash(dir)), 0777), qt.IsNil)
+	}
+	c.Assert(afero.WriteFile(fs.Source, filepath.Join(sharedDir, "content", "p1.md"), []byte("---\ntitle: P1\n---"), 0777), qt.IsNil)
+	c.Assert(afero.WriteFile(fs.Source, filepath.Join(sharedDir, "static", "s1.txt"), []byte("S1"), 0777), qt.IsNil)
+	c.Assert(afero.WriteFile(fs.Source, filepath.Join(themeDir, "layouts", "_default", "single.html"), []byte("Single: {{ .Title }}"), 0777), qt.IsNil)
+
+	c.Assert(os.Symlink(filepath.Join(sharedDir, "content"), filepath.Join(themeDir, "content", "shared")), qt.IsNil)
+	c.Assert(os.Symlink(filepath.Join(sharedDir, "static"), filepath.Join(themeDir, "static", "shared")), qt.IsNil)
+	c.Assert(os.Symlink(filepath.Join(sharedDir, "content"), filepath.Join(sharedDir, "content", "loop", "back")), qt.IsNil)
+
+	config := `
+baseURL = "https://example.com"
+theme = "mymod"
+disableKinds = ["home", "section", "taxonomy", "term", "RSS", "sitemap"]
+[security]
+symlinks = ['^content/shared', '^static/shared$']
+`
+
+	logger := loggers.NewWarningLogger()
+	b := newTestSitesBuilder(t).WithNothingAdded().WithWorkingDir(workingDir)
+	b.WithLogger(logger)
+	b.Fs = fs
+	b.WithConfigFile("toml", config)
+
+	b.Build(BuildCfg{})
+
+	b.AssertFileContent("public/shared/p1/index.html", "Single: P1")
+	_, err = b.H.BaseFs.Static[""].Fs.Stat(filepath.FromSlash("shared/s1.txt"))
+	b.Assert(err, qt.IsNil)
+	b.Assert(b.CheckExists("public/shared/loop/back/p1/index.html"), qt.IsFalse)
+	// The symlink cycle.
+	b.Assert(logger.LogCounters().WarnCounter.Count(), qt.Equals, uint64(1))
+}
+
 func TestMountsProject(t *testing.T) {
 	t.Parallel()
 
@@ -38,6 +38,7 @@ import (
 
 	"github.com/gohugoio/hugo/common/para"
 	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugolib/buildhooks"
 
 	"github.com/gohugoio/hugo/source"
 
@@ -96,6 +97,25 @@ type HugoSites struct {
 	skipRebuildForFilenamesMu sync.Mutex
 	skipRebuildForFilenames   map[string]bool
 
+	// Collects the published files when build.writeManifest is enabled.
+	manifest *buildManifest
+
+	// The Go callbacks and the commands to run at the stages of a build.
+	buildHooks        *buildhooks.Hooks
+	buildHookCommands buildhooks.Config
+
+	// The pages to create from data files.
+	pagesFromData []page.PagesFromData
+
+	// The content includes in the current build.
+	includes includeGraph
+
+	// The pages rendered in the last build when running the server.
+	rendered renderedPages
+
+	// The pages to render on their next visit in fast render mode.
+	stale stalePages
+
 	init *hugoSitesInit
 
 	workers    *para.Workers
@@ -332,6 +352,16 @@ func newHugoSites(cfg deps.DepsCfg, sites ...*Site) (*HugoSites, error) {
 		return nil, fmt.Errorf("failed to create language config: %w", err)
 	}
 
+	buildHookCommands, err := buildhooks.DecodeConfig(cfg.Cfg.Get("buildHooks"))
+	if err != nil {
+		return nil, err
+	}
+
+	pagesFromData, err := page.DecodePagesFromData(cfg.Cfg.Get("pagesFromData"))
+	if err != nil {
+		return nil, err
+	}
+
 	var contentChangeTracker *contentChangeMap
 
 	numWorkers := config.GetNumWorkerMultiplier()
@@ -351,6 +381,10 @@ func newHugoSites(cfg deps.DepsCfg, sites ...*Site) (*HugoSites, error) {
 		workers:                 workers,
 		numWorkers:              numWorkers,
 		skipRebuildForFilenames: make(map[string]bool),
+		manifest:                newBuildManifestIfEnabled(cfg.Cfg),
+		buildHooks:              cfg.BuildHooks,
+		buildHookCommands:       buildHookCommands,
+		pagesFromData:           pagesFromData,
 		init: &hugoSitesInit{
 			data:         lazy.New(),
 			layouts:      lazy.New(),
@@ -383,8 +417,11 @@ func newHugoSites(cfg deps.DepsCfg, sites ...*Site) (*HugoSites, error) {
 
 	h.init.translations.Add(func() (any, error) {
 		if len(h.Sites) > 1 {
-			allTranslations := pagesToTranslationsMap(h.Sites)
-			assignTranslationsToPages(allTranslations, h.Sites)
+			allTranslations, errs := linkTranslations(pagesToTranslationsMap(h.Sites), h.Sites)
+			for _, err := range errs {
+				h.Log.Errorln(err)
+			}
+			assignTranslationsToPages(allTranslations)
 		}
 
 		return nil, nil
@@ -412,6 +449,8 @@ func newHugoSites(cfg deps.DepsCfg, sites ...*Site) (*HugoSites, error) {
 		return nil, initErr
 	}
 
+	helpers.StrictDeprecations = h.Deps.Strict.Deprecated
+
 	// Only needed in server mode.
 	// TODO(bep) clean up the running vs watching terms
 	if cfg.Running {
@@ -495,6 +534,7 @@ func (l configLoader) applyDeps(cfg deps.DepsCfg, sites ...*Site) error {
 				contentMap: newContentMap(contentMapConfig{
 					lang:                 s.Lang(),
 					taxonomyConfig:       s.siteCfg.taxonomiesConfig.Values(),
+					taxonomyOptions:      s.siteCfg.taxonomyOptions,
 					taxonomyDisabled:     !s.isEnabled(page.KindTerm),
 					taxonomyTermDisabled: !s.isEnabled(page.KindTaxonomy),
 					pageDisabled:         !s.isEnabled(page.KindPage),
@@ -611,6 +651,7 @@ func (h *HugoSites) reset(config *BuildCfg) {
 	}
 
 	h.init.Reset()
+	h.includes.reset()
 }
 
 // resetLogs resets the log counters etc. Used to do a new build on the same sites.
@@ -727,24 +768,56 @@ type BuildCfg struct {
 // For regular builds, this will allways return true.
 // TODO(bep) rename/work this.
 func (cfg *BuildCfg) shouldRender(p *pageState) bool {
+	return cfg.renderReason(p) != ""
+}
+
+// renderReason returns why p needs to be rendered, empty if it does not.
+func (cfg *BuildCfg) renderReason(p *pageState) string {
 	if p == nil {
-		return false
+		return ""
+	}
+
+	if len(cfg.RecentlyVisited) == 0 {
+		return RenderReasonAll
+	}
+
+	if cfg.RecentlyVisited[p.RelPermalink()] {
+		return RenderReasonVisited
+	}
+
+	if cfg.whatChanged != nil && !p.File().IsZero() && cfg.whatChanged.files[p.File().Filename()] {
+		return RenderReasonChanged
+	}
+
+	if cfg.whatChanged != nil && cfg.whatChanged.affected[p] {
+		return RenderReasonDependency
 	}
 
 	if p.forceRender {
-		return true
+		return RenderReasonRootFile
 	}
 
-	if len(cfg.RecentlyVisited) == 0 {
-		return true
+	return ""
+}
+
+// mayBeStale returns whether p, when not rendered in this build, may be out
+// of date. These pages are rendered on their next visit in fast render mode.
+func (cfg *BuildCfg) mayBeStale(p *pageState) bool {
+	w := cfg.whatChanged
+	if w == nil || w.identities == nil {
+		// Not a rebuild for file changes.
+		return false
 	}
 
-	if cfg.RecentlyVisited[p.RelPermalink()] {
+	if !w.dependenciesComplete {
 		return true
 	}
 
-	if cfg.whatChanged != nil && !p.File().IsZero() {
-		return cfg.whatChanged.files[p.File().Filename()]
+	for _, po := range p.pageOutputs {
+		if po.render && po.renderDeps == nil {
+			// Not rendered yet, so its dependencies are unknown.
+			return true
+		}
 	}
 
 	return false
@@ -836,7 +909,7 @@ func (h *HugoSites) createPageCollections() error {
 	})
 
 	allRegularPages := newLazyPagesFactory(func() page.Pages {
-		return h.findPagesByKindIn(page.KindPage, allPages.get())
+		return h.findRegularPagesIn(allPages.get())
 	})
 
 	for _, s := range h.Sites {
@@ -984,8 +1057,8 @@ func (h *HugoSites) readData(f source.File) (any, error) {
 	return metadecoders.Default.Unmarshal(content, format)
 }
 
-func (h *HugoSites) findPagesByKindIn(kind string, inPages page.Pages) page.Pages {
-	return h.Sites[0].findPagesByKindIn(kind, inPages)
+func (h *HugoSites) findRegularPagesIn(inPages page.Pages) page.Pages {
+	return h.Sites[0].findRegularPagesIn(inPages)
 }
 
 func (h *HugoSites) resetPageState() {
@@ -1005,6 +1078,73 @@ func (h *HugoSites) resetPageState() {
 	})
 }
 
+// pagesDependingOn returns the pages depending on any of ids through the
+// templates and partials they were rendered with, the pages they reference,
+// their content or their shortcodes. The bool is set if every one of ids was
+// found in these dependencies.
+// This must be called before the page state is reset.
+func (h *HugoSites) pagesDependingOn(ids identity.Identities) (map[*pageState]bool, bool) {
+	var mu sync.Mutex
+	affected := make(map[*pageState]bool)
+	found := make(identity.Identities)
+
+	h.getContentMaps().walkBundles(func(n *contentNode) bool {
+		if n.p == nil {
+			return false
+		}
+		for id := range ids {
+			if n.p.dependsOn(id) {
+				mu.Lock()
+				affected[n.p] = true
+				found[id] = ids[id]
+				mu.Unlock()
+			}
+		}
+		return false
+	})
+
+	return affected, len(found) == len(ids)
+}
+
+// addPagesRelatedToChanges adds the ancestors and the translations of the
+// changed content pages to the affected pages, as these usually list or
+// link to them.
+func (h *HugoSites) addPagesRelatedToChanges(w *whatChanged) {
+	if len(w.files) == 0 {
+		return
+	}
+
+	var mu sync.Mutex
+	if w.affected == nil {
+		w.affected = make(map[*pageState]bool)
+	}
+
+	add := func(p page.Page) *pageState {
+		ps, ok := p.(*pageState)
+		if !ok || ps == nil {
+			return nil
+		}
+		mu.Lock()
+		w.affected[ps] = true
+		mu.Unlock()
+		return ps
+	}
+
+	h.getContentMaps().walkBundles(func(n *contentNode) bool {
+		p := n.p
+		if p == nil || p.File().IsZero() || !w.files[p.File().Filename()] {
+			return false
+		}
+		for parent := add(p.Parent()); parent != nil; {
+			parent = add(parent.Parent())
+		}
+		for _, t := range p.Translations() {
+			add(t)
+		}
+		return false
+	})
+}
+
 func (h *HugoSites) resetPageStateFromEvents(idset identity.Identities) {
 	h.getContentMaps().walkBundles(func(n *contentNode) bool {
 		if n.p == nil {
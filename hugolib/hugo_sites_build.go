@@ -38,14 +38,19 @@ import (
 	"errors"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/gohugoio/hugo/common/htrace"
 	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/hugolib/buildhooks"
 )
 
 // Build builds all sites. If filesystem events are provided,
 // this is considered to be a potential partial rebuild.
 func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
-	ctx, task := trace.NewTask(context.Background(), "Build")
+	ctx, task := trace.NewTask(htrace.BuildContext(), "Build")
 	defer task.End()
+	ctx, span := htrace.Start(ctx, "build")
+	defer span.End()
+	defer htrace.SetBuildContext(ctx)()
 
 	if !config.NoBuildLock {
 		unlock, err := h.BaseFs.LockBuild()
@@ -78,6 +83,7 @@ func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
 	}
 
 	h.testCounters = config.testCounters
+	h.rendered.reset()
 
 	// Need a pointer as this may be modified.
 	conf := &config
@@ -90,7 +96,7 @@ func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
 	var prepareErr error
 
 	if !config.PartialReRender {
-		prepare := func() error {
+		prepare := func(ctx context.Context) error {
 			init := func(conf *BuildCfg) error {
 				for _, s := range h.Sites {
 					s.Deps.BuildStartListeners.Notify()
@@ -112,18 +118,26 @@ func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
 
 			var err error
 
-			f := func() {
-				err = h.process(conf, init, events...)
+			if len(events) == 0 {
+				if err := h.runBuildHooks(buildhooks.ConfigLoaded, false); err != nil {
+					return err
+				}
 			}
-			trace.WithRegion(ctx, "process", f)
+
+			htrace.WithRegion(ctx, "process", func(ctx context.Context) {
+				err = h.process(conf, init, events...)
+			})
 			if err != nil {
 				return fmt.Errorf("process: %w", err)
 			}
 
-			f = func() {
-				err = h.assemble(conf)
+			if err := h.runBuildHooks(buildhooks.ContentRead, len(events) > 0); err != nil {
+				return err
 			}
-			trace.WithRegion(ctx, "assemble", f)
+
+			htrace.WithRegion(ctx, "assemble", func(ctx context.Context) {
+				err = h.assemble(conf)
+			})
 			if err != nil {
 				return err
 			}
@@ -131,10 +145,9 @@ func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
 			return nil
 		}
 
-		f := func() {
-			prepareErr = prepare()
-		}
-		trace.WithRegion(ctx, "prepare", f)
+		htrace.WithRegion(ctx, "prepare", func(ctx context.Context) {
+			prepareErr = prepare(ctx)
+		})
 		if prepareErr != nil {
 			h.SendError(prepareErr)
 		}
@@ -143,15 +156,33 @@ func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
 
 	if prepareErr == nil {
 		var err error
-		f := func() {
+		if err = h.runBuildHooks(buildhooks.BeforeRender, len(events) > 0); err != nil {
+			h.SendError(err)
+		}
+
+		htrace.WithRegion(ctx, "render", func(ctx context.Context) {
 			err = h.render(conf)
+		})
+		if err != nil {
+			h.SendError(err)
 		}
-		trace.WithRegion(ctx, "render", f)
+
+		htrace.WithRegion(ctx, "postProcess", func(ctx context.Context) {
+			err = h.postProcess()
+		})
 		if err != nil {
 			h.SendError(err)
 		}
 
-		if err = h.postProcess(); err != nil {
+		if err = h.writeCacheControlHeaders(); err != nil {
+			h.SendError(err)
+		}
+
+		if err = h.writeBuildManifest(); err != nil {
+			h.SendError(err)
+		}
+
+		if err = h.writeFingerprintRenameMap(); err != nil {
 			h.SendError(err)
 		}
 	}
@@ -185,6 +216,12 @@ func (h *HugoSites) Build(config BuildCfg, events ...fsnotify.Event) error {
 		return fmt.Errorf("logged %d error(s)", errorCount)
 	}
 
+	if !config.SkipRender {
+		if err := h.runBuildHooks(buildhooks.AfterPublish, len(events) > 0); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -275,6 +312,14 @@ func (h *HugoSites) render(config *BuildCfg) error {
 
 	siteRenderContext := &siteRenderContext{cfg: config, multihost: h.multihost}
 
+	if len(config.RecentlyVisited) > 0 && config.whatChanged.identities != nil {
+		h.addPagesRelatedToChanges(config.whatChanged)
+	}
+
+	for _, s := range h.Sites {
+		s.siteRefLinker.fragments.reset()
+	}
+
 	if !config.PartialReRender {
 		h.renderFormats = output.Formats{}
 		h.withSite(func(s *Site) error {
@@ -327,12 +372,18 @@ func (h *HugoSites) render(config *BuildCfg) error {
 	}
 
 	if !config.SkipRender {
+		for _, s := range h.Sites {
+			s.checkRefFragments()
+		}
 		if err := h.renderCrossSitesSitemap(); err != nil {
 			return err
 		}
 		if err := h.renderCrossSitesRobotsTXT(); err != nil {
 			return err
 		}
+		if err := h.renderRedirects(); err != nil {
+			return err
+		}
 	}
 
 	return nil
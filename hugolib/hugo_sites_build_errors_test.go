@@ -645,3 +645,51 @@ title: "A page"
 
 	b.CreateSites().BuildFail(BuildCfg{})
 }
+
+func TestStrictMode(t *testing.T) {
+	t.Parallel()
+
+	filesTemplate := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+STRICT
+-- i18n/en.toml --
+hello = "Hello"
+-- content/p1.md --
+---
+title: P1
+---
+Some <span>raw HTML</span>.
+
+![](/images/a.png)
+
+![A](/images/b.png)
+-- layouts/index.html --
+Home.
+-- layouts/_default/single.html --
+{{ i18n "hello" }}|{{ i18n "missing" }}|{{ .Content }}
+`
+
+	build := func(strict string) error {
+		files := strings.Replace(filesTemplate, "STRICT", strict, 1)
+		_, err := NewIntegrationTestBuilder(
+			IntegrationTestConfig{
+				T:           t,
+				TxtarString: files,
+			},
+		).BuildE()
+		return err
+	}
+
+	b := qt.New(t)
+
+	b.Assert(build(""), qt.IsNil)
+	b.Assert(build("[strict]\ndeprecated = true"), qt.IsNil)
+
+	for _, category := range []string{"missingTranslations", "rawHTMLOmitted", "missingAltText"} {
+		err := build(fmt.Sprintf("[strict]\n%s = true", category))
+		b.Assert(err, qt.IsNotNil, qt.Commentf(category))
+		b.Assert(err.Error(), qt.Contains, "logged 1 error(s)", qt.Commentf(category))
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/collections"
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/hugolib/buildhooks"
+)
+
+// runBuildHooks runs the Go callbacks and then the commands configured for
+// event, stopping at the first error.
+func (h *HugoSites) runBuildHooks(event string, rebuild bool) error {
+	funcs := h.buildHooks.Funcs(event)
+	commands := h.buildHookCommands[event]
+	if len(funcs) == 0 && len(commands) == 0 {
+		return nil
+	}
+
+	ctx := buildhooks.Context{
+		Event:       event,
+		Environment: h.Cfg.GetString("environment"),
+		WorkingDir:  h.Cfg.GetString("workingDir"),
+		PublishDir:  h.PathSpec.AbsPublishDir,
+		Rebuild:     rebuild,
+	}
+	for _, s := range h.Sites {
+		ctx.Languages = append(ctx.Languages, s.Language().Lang)
+	}
+	if event == buildhooks.BeforeRender || event == buildhooks.AfterPublish {
+		for _, s := range h.Sites {
+			ctx.Pages += len(s.RegularPages())
+		}
+	}
+
+	for _, f := range funcs {
+		if err := f(ctx); err != nil {
+			return fmt.Errorf("%s build hook: %w", event, err)
+		}
+	}
+
+	if len(commands) == 0 {
+		return nil
+	}
+
+	b, err := json.Marshal(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range commands {
+		var out bytes.Buffer
+		args := collections.StringSliceToInterfaceSlice(c.Args)
+		args = append(args,
+			hexec.WithDir(ctx.WorkingDir),
+			hexec.WithEnviron([]string{"HUGO_BUILD_EVENT=" + event}),
+			hexec.WithStdin(bytes.NewReader(b)),
+			hexec.WithStdout(&out),
+			hexec.WithStderr(&out),
+		)
+		cmd, err := h.ExecHelper.New(c.Command, args...)
+		if err != nil {
+			return fmt.Errorf("%s build hook: %w", event, err)
+		}
+		err = cmd.Run()
+		if s := strings.TrimSpace(out.String()); s != "" {
+			h.Log.Infof("%s build hook %s: %s", event, c.Command, s)
+		}
+		if err != nil {
+			return fmt.Errorf("%s build hook: %s failed: %w", event, c.Command, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"errors"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib/buildhooks"
+)
+
+func TestBuildHooks(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "sitemap", "robotsTXT", "RSS"]
+defaultContentLanguage = "en"
+[languages.en]
+[languages.nn]
+[[buildHooks.afterPublish]]
+command = "go"
+args = ["env", "GOOS"]
+-- content/p1.md --
+---
+title: "p1"
+---
+-- content/p2.md --
+---
+title: "p2"
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+-- layouts/index.html --
+home
+`
+
+	var contexts []buildhooks.Context
+	hooks := buildhooks.New()
+	for _, event := range buildhooks.Events {
+		c.Assert(hooks.Add(event, func(ctx buildhooks.Context) error {
+			contexts = append(contexts, ctx)
+			return nil
+		}), qt.IsNil)
+	}
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			BuildHooks:  hooks,
+		},
+	).Build()
+
+	b.Assert(contexts, qt.HasLen, 4)
+	for i, event := range buildhooks.Events {
+		b.Assert(contexts[i].Event, qt.Equals, event)
+		b.Assert(contexts[i].Languages, qt.DeepEquals, []string{"en", "nn"})
+		b.Assert(contexts[i].Rebuild, qt.IsFalse)
+	}
+	b.Assert(contexts[0].Pages, qt.Equals, 0)
+	b.Assert(contexts[3].Pages, qt.Equals, 2)
+	b.Assert(contexts[3].Environment, qt.Equals, "production")
+
+	b.AssertFileContent("public/p1/index.html", "p1")
+}
+
+func TestBuildHooksErrors(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "sitemap", "robotsTXT", "RSS"]
+-- layouts/index.html --
+home
+`
+
+	hooks := buildhooks.New()
+	c.Assert(hooks.Add(buildhooks.BeforeRender, func(ctx buildhooks.Context) error {
+		return errors.New("index not ready")
+	}), qt.IsNil)
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			BuildHooks:  hooks,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.ErrorMatches, ".*beforeRender build hook: index not ready.*")
+
+	b, err = NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: `
+-- config.toml --
+[[buildHooks.contentRead]]
+command = "sh"
+-- layouts/index.html --
+home
+`,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.ErrorMatches, `(?s).*contentRead build hook: access denied.*"sh".*`)
+}
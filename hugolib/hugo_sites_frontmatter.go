@@ -0,0 +1,59 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/parser/pageparser"
+	"github.com/gohugoio/hugo/resources/page/pagemeta"
+	"github.com/spf13/cast"
+)
+
+// ValidateFrontMatter validates the front matter in content, the source of
+// the content file filename, against the schema configured for its content
+// type: the type set in front matter, else its section.
+// The file does not need to exist, e.g. to validate unsaved changes.
+func (h *HugoSites) ValidateFrontMatter(filename string, content []byte) ([]pagemeta.FrontMatterViolation, error) {
+	pf, err := pageparser.ParseFrontMatterAndContent(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	frontmatter := pf.FrontMatter
+	if frontmatter == nil {
+		frontmatter = make(map[string]any)
+	}
+	maps.PrepareParams(frontmatter)
+
+	contentType := cast.ToString(frontmatter["type"])
+	if contentType == "" {
+		contentType = "page"
+		if rel, found := h.BaseFs.Content.MakePathRelative(filename); found {
+			if section, _, found := strings.Cut(filepath.ToSlash(rel), "/"); found {
+				contentType = section
+			}
+		}
+	}
+
+	schema, found, err := h.Sites[0].frontMatterSchema(contentType)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return schema.Validate(frontmatter), nil
+}
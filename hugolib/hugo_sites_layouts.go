@@ -0,0 +1,74 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+
+	"github.com/gohugoio/hugo/output"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/tpl"
+)
+
+// LayoutLookup is the template lookup of a page in one of its output formats.
+type LayoutLookup struct {
+	OutputFormat output.Format
+
+	// The templates evaluated, in lookup order.
+	Candidates []tpl.LayoutCandidate
+}
+
+// Winner returns the template used, nil if none was found.
+func (l LayoutLookup) Winner() *tpl.LayoutCandidate {
+	for i, c := range l.Candidates {
+		if c.Found {
+			return &l.Candidates[i]
+		}
+	}
+	return nil
+}
+
+// ExplainLayouts returns the template lookup of p for each of its output
+// formats, in the same way as when rendering the page.
+// The sites must have been built first, typically with SkipRender set.
+func (h *HugoSites) ExplainLayouts(p page.Page) ([]LayoutLookup, error) {
+	pp, err := unwrapPage(p)
+	if err != nil {
+		return nil, err
+	}
+	ps, ok := pp.(*pageState)
+	if !ok {
+		return nil, fmt.Errorf("%T has no layouts", p)
+	}
+
+	if _, err := h.init.layouts.Do(); err != nil {
+		return nil, err
+	}
+
+	explainer, ok := ps.s.Tmpl().(tpl.LayoutExplainer)
+	if !ok {
+		return nil, fmt.Errorf("%T does not explain layouts", ps.s.Tmpl())
+	}
+
+	d := ps.getLayoutDescriptor()
+	var lookups []LayoutLookup
+	for _, f := range ps.m.outputFormats() {
+		lookups = append(lookups, LayoutLookup{
+			OutputFormat: f,
+			Candidates:   explainer.ExplainLayout(d, f),
+		})
+	}
+
+	return lookups, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gohugoio/hugo/resources/page"
+)
+
+// RenderPage renders p in the output format with the given name, e.g. html,
+// to the publish file system, together with its resources and the resources
+// published by its templates.
+// The sites must have been built first, typically with SkipRender set.
+// It returns the target filename of the rendered page.
+func (h *HugoSites) RenderPage(p page.Page, format string) (string, error) {
+	pp, err := unwrapPage(p)
+	if err != nil {
+		return "", err
+	}
+	ps, ok := pp.(*pageState)
+	if !ok {
+		return "", fmt.Errorf("%T can not be rendered", p)
+	}
+
+	if _, err := h.init.layouts.Do(); err != nil {
+		return "", err
+	}
+
+	// The page outputs are indexed by the render formats of all sites.
+	s := ps.s
+	idx := -1
+	offset := 0
+	for _, s2 := range h.Sites {
+		if s2 == s {
+			for i, f := range s.renderFormats {
+				if strings.EqualFold(f.Name, format) {
+					idx = offset + i
+				}
+			}
+			break
+		}
+		offset += len(s2.renderFormats)
+	}
+	if idx == -1 {
+		return "", fmt.Errorf("output format %q not found", format)
+	}
+	f := h.renderFormats[idx]
+
+	h.currentSite = s
+	for _, s2 := range h.Sites {
+		s2.rc = &siteRenderingContext{Format: f}
+		if err := s2.preparePagesForRender(s == s2, idx); err != nil {
+			return "", err
+		}
+	}
+
+	if !ps.render {
+		return "", fmt.Errorf("page %q is not rendered in output format %q", ps.pathOrTitle(), f.Name)
+	}
+
+	if err := ps.renderResources(); err != nil {
+		return "", ps.errorf(err, "failed to render page resources")
+	}
+
+	templ, found, err := ps.resolveTemplate()
+	if err != nil {
+		return "", ps.errorf(err, "failed to resolve template")
+	}
+	if !found {
+		return "", fmt.Errorf("found no layout file for %q for kind %q", f.Name, ps.Kind())
+	}
+
+	targetPath := ps.targetPaths().TargetFilename
+	if err := s.renderAndWritePage(&s.PathSpec.ProcessingStats.Pages, "page "+ps.Title(), targetPath, ps, templ); err != nil {
+		return "", err
+	}
+
+	return targetPath, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRenderPage(t *testing.T) {
+	t.Parallel()
+
+	b := newTestSitesBuilder(t).WithConfigFile("toml", `
+baseURL = "https://example.org/"
+[outputs]
+page = ["html", "json"]
+`)
+	b.WithContent(
+		"blog/p1/index.md", `---
+title: "P1"
+---
+Content.
+`,
+		"blog/p1/data.txt", "Data.",
+		"blog/p2.md", `---
+title: "P2"
+---
+`,
+	)
+	b.WithTemplatesAdded(
+		"_default/single.html", `Single: {{ .Title }}|{{ .Content }}|{{ range .Resources }}{{ .RelPermalink }}{{ end }}|{{ $css := "body{}" | resources.FromString "css/main.css" }}{{ $css.RelPermalink }}`,
+		"_default/single.json", `{"title": {{ .Title | jsonify }}}`,
+	)
+	b.Build(BuildCfg{SkipRender: true})
+
+	c := qt.New(t)
+
+	p, err := b.H.Sites[0].Info.GetPage("/blog/p1")
+	c.Assert(err, qt.IsNil)
+
+	filename, err := b.H.RenderPage(p, "html")
+	c.Assert(err, qt.IsNil)
+	c.Assert(filename, qt.Equals, "/blog/p1/index.html")
+	b.AssertFileContent("public/blog/p1/index.html", "Single: P1|<p>Content.</p>\n|/blog/p1/data.txt|/css/main.css")
+	b.AssertFileContent("public/blog/p1/data.txt", "Data.")
+	b.AssertFileContent("public/css/main.css", "body{}")
+	c.Assert(b.CheckExists("public/blog/p1/index.json"), qt.IsFalse)
+	c.Assert(b.CheckExists("public/blog/p2/index.html"), qt.IsFalse)
+	c.Assert(b.CheckExists("public/index.html"), qt.IsFalse)
+
+	filename, err = b.H.RenderPage(p, "JSON")
+	c.Assert(err, qt.IsNil)
+	c.Assert(filename, qt.Equals, "/blog/p1/index.json")
+	b.AssertFileContent("public/blog/p1/index.json", `{"title": "P1"}`)
+
+	_, err = b.H.RenderPage(p, "rss")
+	c.Assert(err, qt.ErrorMatches, `.*not rendered in output format "RSS"`)
+
+	_, err = b.H.RenderPage(p, "foo")
+	c.Assert(err, qt.ErrorMatches, `output format "foo" not found`)
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/bep/gitmap"
+)
+
+// The reasons a page is rendered, see RenderedPage.
+const (
+	// All pages are rendered, e.g. in the first build or when
+	// fast render mode is disabled.
+	RenderReasonAll = "all pages"
+
+	// The page's content file is at the root of a content dir, these are
+	// always rendered.
+	RenderReasonRootFile = "content root file"
+
+	// The page was recently visited in the browser (fast render mode).
+	RenderReasonVisited = "recently visited"
+
+	// The page's content file changed.
+	RenderReasonChanged = "content file changed"
+
+	// The page depends on a changed template, partial, shortcode or page,
+	// or is a section or translation of a changed page (fast render mode).
+	RenderReasonDependency = "depends on a change"
+)
+
+// RenderedPage is a page rendered in a build.
+type RenderedPage struct {
+	// The relative permalink.
+	Path string `json:"path"`
+
+	// The content file relative to its content dir, if any.
+	File string `json:"file,omitempty"`
+
+	Lang string `json:"lang"`
+
+	// Why the page was rendered, one of the RenderReason constants.
+	Reason string `json:"reason"`
+
+	// The last commit of the content file if it changed and GitInfo is
+	// enabled, i.e. who last changed the page and when.
+	GitInfo *gitmap.GitInfo `json:"gitInfo,omitempty"`
+}
+
+// renderedPages collects the pages rendered in the current build.
+// This is only done when running the server.
+type renderedPages struct {
+	mu    sync.Mutex
+	seen  map[*pageState]bool
+	pages []RenderedPage
+}
+
+func (r *renderedPages) reset() {
+	r.mu.Lock()
+	r.seen = nil
+	r.pages = nil
+	r.mu.Unlock()
+}
+
+// add adds p, once for all its output formats.
+func (r *renderedPages) add(p *pageState, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seen[p] {
+		return
+	}
+	if r.seen == nil {
+		r.seen = make(map[*pageState]bool)
+	}
+	r.seen[p] = true
+
+	rp := RenderedPage{
+		Path:   p.RelPermalink(),
+		Lang:   p.Lang(),
+		Reason: reason,
+	}
+	if !p.File().IsZero() {
+		rp.File = strings.TrimPrefix(filepath.ToSlash(p.File().Path()), "/")
+	}
+	if reason == RenderReasonChanged {
+		rp.GitInfo = p.GitInfo()
+	}
+	r.pages = append(r.pages, rp)
+}
+
+// RenderedPages returns the pages rendered in the last build, ordered by
+// path, when running the server.
+func (h *HugoSites) RenderedPages() []RenderedPage {
+	h.rendered.mu.Lock()
+	defer h.rendered.mu.Unlock()
+	pages := make([]RenderedPage, len(h.rendered.pages))
+	copy(pages, h.rendered.pages)
+	sort.SliceStable(pages, func(i, j int) bool {
+		if pages[i].Path != pages[j].Path {
+			return pages[i].Path < pages[j].Path
+		}
+		return pages[i].Lang < pages[j].Lang
+	})
+	return pages
+}
+
+// stalePages holds the pages left out of fast render mode rebuilds that may
+// depend on the changes, to render them on their next visit.
+// This is only done when running the server.
+type stalePages struct {
+	mu sync.Mutex
+
+	// Maps the URLs of every output format of the stale pages to the
+	// relative permalink of the page.
+	urls map[string]string
+
+	// Maps the relative permalink of the stale pages to their URLs.
+	pages map[string][]string
+}
+
+// update marks p as current if rendered, else as stale if stale is set.
+func (s *stalePages) update(p *pageState, rendered, stale bool) {
+	if !rendered && !stale {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rendered {
+		if len(s.pages) == 0 {
+			return
+		}
+		key := p.RelPermalink()
+		for _, u := range s.pages[key] {
+			delete(s.urls, u)
+		}
+		delete(s.pages, key)
+		return
+	}
+
+	key := p.RelPermalink()
+	if _, found := s.pages[key]; found {
+		return
+	}
+	if s.pages == nil {
+		s.pages = make(map[string][]string)
+		s.urls = make(map[string]string)
+	}
+	urls := []string{key}
+	for _, of := range p.OutputFormats() {
+		if u := of.RelPermalink(); u != key {
+			urls = append(urls, u)
+		}
+	}
+	for _, u := range urls {
+		s.urls[u] = key
+	}
+	s.pages[key] = urls
+}
+
+func (s *stalePages) get(u string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, found := s.urls[u]
+	return key, found
+}
+
+// StaleURL returns the relative permalink of the page to render to bring
+// the URL u up to date, if that page was left out of a fast render mode
+// rebuild and may depend on the changes. The URL may point to any of the
+// page's output formats or paginator pages.
+func (h *HugoSites) StaleURL(u string) (string, bool) {
+	if key, found := h.stale.get(u); found {
+		return key, true
+	}
+
+	if strings.HasSuffix(u, "/index.html") {
+		u = strings.TrimSuffix(u, "index.html")
+		if key, found := h.stale.get(u); found {
+			return key, true
+		}
+	}
+
+	for _, s := range h.Sites {
+		paginatePath := "/" + s.Cfg.GetString("paginatePath") + "/"
+		if i := strings.LastIndex(u, paginatePath); i != -1 {
+			if key, found := h.stale.get(u[:i+1]); found {
+				return key, true
+			}
+		}
+	}
+
+	return "", false
+}
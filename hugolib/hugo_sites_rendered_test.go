@@ -0,0 +1,159 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRenderedPages(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "sitemap", "robotsTXT", "404"]
+-- content/p1.md --
+---
+title: "P1"
+---
+-- content/p2.md --
+---
+title: "P2"
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+-- layouts/_default/list.html --
+{{ .Title }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			Running:     true,
+		},
+	).Build()
+
+	b.Assert(b.H.RenderedPages(), qt.DeepEquals, []RenderedPage{
+		{Path: "/", Lang: "en", Reason: RenderReasonAll},
+		{Path: "/p1/", File: "p1.md", Lang: "en", Reason: RenderReasonAll},
+		{Path: "/p2/", File: "p2.md", Lang: "en", Reason: RenderReasonAll},
+	})
+
+	b.EditFiles("content/p1.md", "---\ntitle: \"P1 edited\"\n---\n")
+	b.Assert(b.H.Build(BuildCfg{RecentlyVisited: map[string]bool{"/": true}}, b.changeEvents()...), qt.IsNil)
+
+	b.Assert(b.H.RenderedPages(), qt.DeepEquals, []RenderedPage{
+		{Path: "/", Lang: "en", Reason: RenderReasonVisited},
+		{Path: "/p1/", File: "p1.md", Lang: "en", Reason: RenderReasonChanged},
+		{Path: "/p2/", File: "p2.md", Lang: "en", Reason: RenderReasonRootFile},
+	})
+}
+
+func TestRenderedPagesDependencies(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "sitemap", "robotsTXT", "404"]
+-- content/_index.md --
+---
+title: "Home"
+---
+-- content/blog/_index.md --
+---
+title: "Blog"
+---
+-- content/blog/p1.md --
+---
+title: "P1"
+---
+-- content/blog/p2.md --
+---
+title: "P2"
+---
+-- content/docs/d1.md --
+---
+title: "D1"
+---
+See [P2]({{< relref "/blog/p2.md" >}}).
+-- content/notes/n1.md --
+---
+title: "N1"
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+-- layouts/_default/list.html --
+{{ .Title }}
+-- layouts/docs/single.html --
+{{ .Title }}|{{ partial "byline.html" . }}|{{ .Content }}
+-- layouts/partials/byline.html --
+By me.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			Running:     true,
+		},
+	).Build()
+
+	rebuild := func() {
+		b.Helper()
+		b.Assert(b.H.Build(BuildCfg{RecentlyVisited: map[string]bool{"/": true}}, b.changeEvents()...), qt.IsNil)
+		b.changedFiles = nil
+	}
+
+	// Only the pages rendered with the partial depend on it.
+	b.EditFiles("layouts/partials/byline.html", "By you.")
+	rebuild()
+	b.Assert(b.H.RenderedPages(), qt.DeepEquals, []RenderedPage{
+		{Path: "/", File: "_index.md", Lang: "en", Reason: RenderReasonVisited},
+		{Path: "/docs/d1/", File: "docs/d1.md", Lang: "en", Reason: RenderReasonDependency},
+	})
+	b.AssertFileContent("public/docs/d1/index.html", "D1|By you.|")
+	_, stale := b.H.StaleURL("/notes/n1/")
+	b.Assert(stale, qt.IsFalse)
+
+	// The section listing the changed page and the page linking to it
+	// are rendered, the others may list it, so are stale.
+	// The other pages in the same directory are always rendered.
+	b.EditFiles("content/blog/p2.md", "---\ntitle: \"P2 edited\"\n---\n")
+	rebuild()
+	b.Assert(b.H.RenderedPages(), qt.DeepEquals, []RenderedPage{
+		{Path: "/", File: "_index.md", Lang: "en", Reason: RenderReasonVisited},
+		{Path: "/blog/", File: "blog/_index.md", Lang: "en", Reason: RenderReasonDependency},
+		{Path: "/blog/p1/", File: "blog/p1.md", Lang: "en", Reason: RenderReasonRootFile},
+		{Path: "/blog/p2/", File: "blog/p2.md", Lang: "en", Reason: RenderReasonChanged},
+		{Path: "/docs/d1/", File: "docs/d1.md", Lang: "en", Reason: RenderReasonDependency},
+	})
+
+	for _, u := range []string{"/notes/n1/", "/notes/n1/index.html", "/notes/n1/page/2/"} {
+		pageURL, stale := b.H.StaleURL(u)
+		b.Assert(stale, qt.IsTrue, qt.Commentf(u))
+		b.Assert(pageURL, qt.Equals, "/notes/n1/")
+	}
+	_, stale = b.H.StaleURL("/blog/p2/")
+	b.Assert(stale, qt.IsFalse)
+
+	// Visiting a stale page renders it.
+	b.Assert(b.H.Build(BuildCfg{RecentlyVisited: map[string]bool{"/notes/n1/": true}, PartialReRender: true}), qt.IsNil)
+	_, stale = b.H.StaleURL("/notes/n1/")
+	b.Assert(stale, qt.IsFalse)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/htesting"
 	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugolib/buildhooks"
 	"github.com/spf13/afero"
 	"golang.org/x/tools/txtar"
 )
@@ -105,6 +106,11 @@ func (s *IntegrationTestBuilder) AssertLogContains(text string) {
 	s.Assert(s.logBuff.String(), qt.Contains, text)
 }
 
+// LogString returns the log output of the last build.
+func (s *IntegrationTestBuilder) LogString() string {
+	return s.logBuff.String()
+}
+
 func (s *IntegrationTestBuilder) AssertLogMatches(expression string) {
 	s.Helper()
 	re := regexp.MustCompile(expression)
@@ -197,7 +203,9 @@ func (s *IntegrationTestBuilder) Build() *IntegrationTestBuilder {
 
 func (s *IntegrationTestBuilder) BuildE() (*IntegrationTestBuilder, error) {
 	s.Helper()
-	s.initBuilder()
+	if err := s.initBuilder(); err != nil {
+		return s, err
+	}
 	err := s.build(BuildCfg{})
 	return s, err
 }
@@ -267,7 +275,9 @@ func (s *IntegrationTestBuilder) FileContent(filename string) string {
 	return s.readWorkingDir(s, s.fs, filepath.FromSlash(filename))
 }
 
-func (s *IntegrationTestBuilder) initBuilder() {
+// initBuilder creates the sites, returning any error from NewHugoSites.
+func (s *IntegrationTestBuilder) initBuilder() error {
+	var initErr error
 	s.builderInit.Do(func() {
 		var afs afero.Fs
 		if s.Cfg.NeedsOsFS {
@@ -318,9 +328,12 @@ func (s *IntegrationTestBuilder) initBuilder() {
 
 		s.Assert(err, qt.IsNil)
 
-		depsCfg := deps.DepsCfg{Cfg: cfg, Fs: fs, Running: s.Cfg.Running, Logger: logger}
+		depsCfg := deps.DepsCfg{Cfg: cfg, Fs: fs, Running: s.Cfg.Running, Logger: logger, BuildHooks: s.Cfg.BuildHooks}
 		sites, err := NewHugoSites(depsCfg)
-		s.Assert(err, qt.IsNil)
+		if err != nil {
+			initErr = err
+			return
+		}
 
 		s.H = sites
 		s.fs = fs
@@ -338,6 +351,7 @@ func (s *IntegrationTestBuilder) initBuilder() {
 
 		}
 	})
+	return initErr
 }
 
 func (s *IntegrationTestBuilder) absFilename(filename string) string {
@@ -479,4 +493,7 @@ type IntegrationTestConfig struct {
 	NeedsNpmInstall bool
 
 	WorkingDir string
+
+	// The Go callbacks to run at the stages of the build.
+	BuildHooks *buildhooks.Hooks
 }
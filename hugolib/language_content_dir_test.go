@@ -524,3 +524,65 @@ title: "p4 theme (nl)"
 	b.AssertFileContent("public/en/index.html", `home (en): en: p1 (en)|p2 (en)|p3 (en)|:END`)
 
 }
+
+func TestLanguageContentDirs(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "rss", "sitemap", "robotsTXT", "404"]
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+contentDirs = ["overrides/en", "upstream/en"]
+[languages.de]
+weight = 2
+contentDir = "upstream/de"
+-- upstream/en/docs/_index.md --
+---
+title: "Docs"
+---
+-- upstream/en/docs/install.md --
+---
+title: "Install"
+---
+Upstream install.
+-- upstream/en/docs/usage.md --
+---
+title: "Usage"
+---
+Upstream usage.
+-- upstream/de/docs/install.md --
+---
+title: "Installation"
+---
+-- overrides/en/docs/install.md --
+---
+title: "Install (Enterprise)"
+---
+Enterprise install.
+-- overrides/en/docs/support.md --
+---
+title: "Support"
+---
+-- layouts/_default/single.html --
+{{ .Title }}|{{ .Content }}|{{ range .Translations }}{{ .Lang }}:{{ .Title }}|{{ end }}
+-- layouts/_default/list.html --
+{{ .Title }}|{{ range .RegularPages }}{{ .Title }}|{{ end }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/docs/install/index.html", "Install (Enterprise)|<p>Enterprise install.</p>\n|de:Installation|")
+	b.AssertFileContent("public/docs/usage/index.html", "Usage|<p>Upstream usage.</p>\n|")
+	b.AssertFileContent("public/docs/support/index.html", "Support||")
+	b.AssertFileContent("public/docs/index.html", "Docs|Install (Enterprise)|Support|Usage|")
+	b.AssertFileContent("public/de/docs/install/index.html", "Installation||en:Install (Enterprise)|")
+}
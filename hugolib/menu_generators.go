@@ -0,0 +1,269 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/htime"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/common/types"
+	"github.com/gohugoio/hugo/navigation"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/spf13/cast"
+)
+
+// getMenusFromGenerators creates the menu entries configured in menuGenerators.
+func (s *Site) getMenusFromGenerators() navigation.Menus {
+	ret := navigation.Menus{}
+
+	cfg := s.language.Get("menuGenerators")
+	if cfg == nil {
+		return ret
+	}
+
+	generators, err := navigation.DecodeMenuGenerators(cfg)
+	if err != nil {
+		s.Log.Errorln(err)
+		return ret
+	}
+
+	// Sort for a stable result when the same entry is generated twice.
+	var names []string
+	for name := range generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, g := range generators[name] {
+			mg := &menuGenerator{s: s, menu: name, g: g}
+			var entries navigation.Menu
+			switch g.Source {
+			case navigation.MenuSourceSection:
+				entries, err = mg.fromSection()
+			case navigation.MenuSourceTaxonomy:
+				entries, err = mg.fromTaxonomy()
+			case navigation.MenuSourceData:
+				entries, err = mg.fromData()
+			}
+			if err != nil {
+				s.Log.Errorf("menu generator for menu %q: %s", name, err)
+				continue
+			}
+			for _, e := range entries {
+				ret[name] = ret[name].Add(e)
+			}
+		}
+	}
+
+	return ret
+}
+
+type menuGenerator struct {
+	s    *Site
+	menu string
+	g    navigation.MenuGenerator
+}
+
+func (m *menuGenerator) fromSection() (navigation.Menu, error) {
+	root, err := m.s.getPageNew(nil, "/"+m.g.Section)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		// The section may not exist in all languages.
+		return nil, nil
+	}
+	if !root.IsNode() {
+		return nil, fmt.Errorf("%q is not a section", m.g.Section)
+	}
+
+	var entries navigation.Menu
+	m.addSectionEntries(&entries, root, m.g.Parent, 1)
+
+	return entries, nil
+}
+
+func (m *menuGenerator) addSectionEntries(entries *navigation.Menu, section page.Page, parent string, level int) {
+	for _, p := range section.Sections() {
+		if !m.acceptDates(p) {
+			continue
+		}
+		e := m.newEntry(p, p.SectionsPath(), parent)
+		*entries = append(*entries, e)
+		if level < m.g.Depth {
+			m.addSectionEntries(entries, p, e.Identifier, level+1)
+		}
+	}
+
+	if m.g.Pages && level <= m.g.Depth && !section.IsHome() {
+		m.addPageEntries(entries, section.RegularPages(), parent)
+	}
+}
+
+func (m *menuGenerator) fromTaxonomy() (navigation.Menu, error) {
+	p, err := m.s.getPageNew(nil, "/"+m.g.Taxonomy)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil || p.Kind() != page.KindTaxonomy {
+		return nil, fmt.Errorf("taxonomy %q not found", m.g.Taxonomy)
+	}
+
+	var entries navigation.Menu
+	for _, term := range p.Pages() {
+		e := m.newEntry(term, term.SectionsPath(), m.g.Parent)
+		entries = append(entries, e)
+		if m.g.Depth > 1 {
+			m.addPageEntries(&entries, term.Pages(), e.Identifier)
+		}
+	}
+
+	return entries, nil
+}
+
+func (m *menuGenerator) addPageEntries(entries *navigation.Menu, pages page.Pages, parent string) {
+	for _, p := range pages {
+		if !p.IsPage() || !m.accept(p) {
+			continue
+		}
+		*entries = append(*entries, m.newEntry(p, filepath.ToSlash(p.Pathc()), parent))
+	}
+}
+
+func (m *menuGenerator) fromData() (navigation.Menu, error) {
+	base := lookupDataPath(m.s.h.Data(), m.g.Data)
+	if base == nil {
+		return nil, fmt.Errorf("no data found in %q", m.g.Data)
+	}
+
+	entries, err := m.dataEntries(base)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge in any language overlay, e.g. menus/footer.nn.
+	overlay, err := m.dataEntries(lookupDataPath(m.s.h.Data(), m.g.Data+"."+m.s.language.Lang))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, oe := range overlay {
+		replaced := false
+		for i, e := range entries {
+			if e.KeyName() == oe.KeyName() {
+				entries[i] = oe
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			entries = append(entries, oe)
+		}
+	}
+
+	return entries, nil
+}
+
+func (m *menuGenerator) dataEntries(v any) (navigation.Menu, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	// A TOML file can not have a top level array, so allow the
+	// entries to be wrapped in a map with an entries key.
+	if mm, err := maps.ToStringMapE(v); err == nil {
+		v = mm["entries"]
+	}
+
+	items, err := cast.ToSliceE(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode menu entries in %q: %w", m.g.Data, err)
+	}
+
+	var entries navigation.Menu
+	for _, item := range items {
+		ime, err := maps.ToStringMapE(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode menu entry in %q: %w", m.g.Data, err)
+		}
+		me := &navigation.MenuEntry{Menu: m.menu}
+		if err := me.MarshallMap(ime); err != nil {
+			return nil, err
+		}
+		me.ConfiguredURL = m.s.Info.createNodeMenuEntryURL(me.ConfiguredURL)
+		if types.IsNil(me.Page) && me.PageRef != "" {
+			me.Page, _ = m.s.getPageNew(nil, me.PageRef)
+		}
+		if !types.IsNil(me.Page) {
+			if p, ok := me.Page.(page.Page); ok && !m.accept(p) {
+				continue
+			}
+		}
+		if me.Parent == "" {
+			me.Parent = m.g.Parent
+		}
+		entries = append(entries, me)
+	}
+
+	return entries, nil
+}
+
+func (m *menuGenerator) newEntry(p page.Page, identifier, parent string) *navigation.MenuEntry {
+	return &navigation.MenuEntry{
+		Menu:       m.menu,
+		Identifier: identifier,
+		Name:       p.LinkTitle(),
+		Weight:     p.Weight(),
+		Parent:     parent,
+		Page:       p,
+	}
+}
+
+// accept returns whether to add the regular page p to the menu.
+func (m *menuGenerator) accept(p page.Page) bool {
+	return m.acceptDates(p) && m.g.MatchParams(p.Param)
+}
+
+// acceptDates returns whether p passes the draft, future and expired filters.
+func (m *menuGenerator) acceptDates(p page.Page) bool {
+	if m.g.ExcludeDrafts() && p.Draft() {
+		return false
+	}
+	now := htime.Now()
+	if m.g.ExcludeFuture() && p.PublishDate().After(now) {
+		return false
+	}
+	if m.g.ExcludeExpired() && !p.ExpiryDate().IsZero() && p.ExpiryDate().Before(now) {
+		return false
+	}
+	return true
+}
+
+// lookupDataPath looks up the slash separated path in the site data.
+func lookupDataPath(data map[string]any, path string) any {
+	var v any = data
+	for _, key := range strings.Split(path, "/") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
@@ -588,3 +588,116 @@ Page IsAncestor Self: false
 Page IsDescendant Self: false
 `)
 }
+
+func TestMenuGenerators(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ['RSS','sitemap']
+buildDrafts = true
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+[taxonomies]
+category = "categories"
+[[menuGenerators.main]]
+source = "section"
+depth = 2
+pages = true
+exclude = ["drafts"]
+[[menuGenerators.tags]]
+source = "taxonomy"
+taxonomy = "categories"
+depth = 2
+[[menuGenerators.footer]]
+source = "data"
+data = "menus/footer"
+[[menuGenerators.featured]]
+source = "section"
+section = "docs"
+depth = 2
+pages = true
+[menuGenerators.featured.params]
+featured = true
+-- data/menus/footer.toml --
+[[entries]]
+identifier = "about"
+name = "About"
+url = "/about/"
+weight = 1
+[[entries]]
+identifier = "docs"
+name = "Docs"
+pageRef = "/docs"
+weight = 2
+-- data/menus/footer.nn.toml --
+[[entries]]
+identifier = "about"
+name = "Om"
+url = "/om/"
+weight = 1
+-- content/docs/_index.md --
+---
+title: "Docs"
+weight: 1
+---
+-- content/docs/install/_index.md --
+---
+title: "Install"
+---
+-- content/docs/install/linux.md --
+---
+title: "Linux"
+categories: ["os"]
+featured: true
+---
+-- content/docs/intro.md --
+---
+title: "Intro"
+weight: 1
+categories: ["basics"]
+---
+-- content/docs/draft.md --
+---
+title: "Draft"
+draft: true
+---
+-- content/blog/_index.md --
+---
+title: "Blog"
+weight: 2
+---
+-- layouts/index.html --
+{{ define "entries" }}{{ range . }}{{ .Name }}|{{ .URL }}|{{ with .Children }}[{{ template "entries" . }}]{{ end }};{{ end }}{{ end }}
+Main: {{ template "entries" site.Menus.main }}
+Tags: {{ template "entries" site.Menus.tags }}
+Footer: {{ template "entries" site.Menus.footer }}
+Featured: {{ template "entries" site.Menus.featured }}
+-- layouts/_default/single.html --
+Trail: {{ range site.Menus.main.ActiveTrail . }}{{ .Name }}/{{ end }}
+-- layouts/_default/list.html --
+List.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html",
+		"Main: Docs|/docs/|[Intro|/docs/intro/|;Install|/docs/install/|;];Blog|/blog/|;",
+		"Tags: basics|/categories/basics/|[Intro|/docs/intro/|;];os|/categories/os/|[Linux|/docs/install/linux/|;];",
+		"Footer: About|/about/|;Docs|/docs/|;",
+		"Featured: Install|/docs/install/|[Linux|/docs/install/linux/|;];",
+	)
+	b.AssertFileContent("public/nn/index.html", "Footer: Om|/om/|;Docs||;")
+	b.AssertFileContent("public/docs/install/linux/index.html", "Trail: Docs/Install/")
+	b.AssertFileContent("public/docs/intro/index.html", "Trail: Docs/Intro/")
+}
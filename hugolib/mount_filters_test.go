@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gohugoio/hugo/common/loggers"
@@ -117,3 +118,48 @@ Resources: [js/include.js]
 `)
 
 }
+
+func TestMountFiltersGitignoreStyle(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+[module]
+[[module.mounts]]
+source = "docs"
+target = "content"
+excludeFiles = ["drafts/**", "**/*.test.md"]
+-- docs/p1.md --
+---
+title: "p1"
+---
+-- docs/p1.test.md --
+---
+title: "p1 test"
+---
+-- docs/guide/p2.md --
+---
+title: "p2"
+---
+-- docs/guide/p2.test.md --
+---
+title: "p2 test"
+---
+-- docs/drafts/p3.md --
+---
+title: "p3"
+---
+-- layouts/index.html --
+{{ range site.RegularPages }}{{ .Title }}|{{ end }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.Assert(strings.TrimSpace(b.FileContent("public/index.html")), qt.Equals, "p1|p2|")
+}
@@ -16,6 +16,7 @@ package hugolib
 import (
 	"bytes"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -24,6 +25,7 @@ import (
 
 	"go.uber.org/atomic"
 
+	"github.com/gohugoio/hugo/common/maps"
 	"github.com/gohugoio/hugo/identity"
 
 	"github.com/gohugoio/hugo/markup/converter"
@@ -45,6 +47,7 @@ import (
 
 	"github.com/gohugoio/hugo/output"
 
+	"github.com/gohugoio/hugo/langs"
 	"github.com/gohugoio/hugo/media"
 	"github.com/gohugoio/hugo/source"
 
@@ -52,7 +55,9 @@ import (
 	"github.com/gohugoio/hugo/common/text"
 	"github.com/gohugoio/hugo/resources"
 	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/resources/page/pagemeta"
 	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/spf13/cast"
 )
 
 var (
@@ -221,7 +226,7 @@ func (p *pageState) getPagesAndSections() page.Pages {
 func (p *pageState) RegularPagesRecursive() page.Pages {
 	p.regularPagesRecursiveInit.Do(func() {
 		var pages page.Pages
-		switch p.Kind() {
+		switch p.m.kind {
 		case page.KindSection:
 			pages = p.getPagesRecursive()
 		default:
@@ -240,7 +245,7 @@ func (p *pageState) RegularPages() page.Pages {
 	p.regularPagesInit.Do(func() {
 		var pages page.Pages
 
-		switch p.Kind() {
+		switch p.m.kind {
 		case page.KindPage:
 		case page.KindSection, page.KindHome, page.KindTaxonomy:
 			pages = p.getPages()
@@ -265,7 +270,7 @@ func (p *pageState) Pages() page.Pages {
 	p.pagesInit.Do(func() {
 		var pages page.Pages
 
-		switch p.Kind() {
+		switch p.m.kind {
 		case page.KindPage:
 		case page.KindSection, page.KindHome:
 			pages = p.getPagesAndSections()
@@ -296,6 +301,32 @@ func (p *pageState) RawContent() string {
 	return string(p.source.parsed.Input()[start:])
 }
 
+// RawContentSource returns the source of the page as read from file,
+// including any front matter.
+func (p *pageState) RawContentSource() string {
+	if p.source.parsed == nil {
+		return ""
+	}
+	return string(p.source.parsed.Input())
+}
+
+// RawFrontMatter returns the front matter as written in the source,
+// decoded again on every call so the map can be modified freely.
+func (p *pageState) RawFrontMatter() page.RawFrontMatter {
+	it := p.source.frontMatter
+	if !it.IsFrontMatter() {
+		return page.RawFrontMatter{}
+	}
+	f := pageparser.FormatFromFrontMatterType(it.Type)
+	// This has already been decoded successfully in mapContent.
+	m, _ := metadecoders.Default.UnmarshalToMap(it.Val, f)
+	return page.RawFrontMatter{
+		Format: string(f),
+		Map:    m,
+		Source: string(it.Val),
+	}
+}
+
 func (p *pageState) sortResources() {
 	sort.SliceStable(p.resources, func(i, j int) bool {
 		ri, rj := p.resources[i], p.resources[j]
@@ -324,6 +355,12 @@ func (p *pageState) Resources() resource.Resources {
 	p.resourcesInit.Do(func() {
 		p.sortResources()
 		if len(p.m.resourcesMetadata) > 0 {
+			// Match the aliases before the resources get renamed.
+			for _, r := range p.resources {
+				if aliases, _ := resources.ResourceAliases(p.m.resourcesMetadata, r); len(aliases) > 0 {
+					p.resourceAliases = append(p.resourceAliases, resourceAliases{r: r, aliases: aliases})
+				}
+			}
 			resources.AssignMetadata(p.m.resourcesMetadata, p.resources...)
 			p.sortResources()
 		}
@@ -331,6 +368,19 @@ func (p *pageState) Resources() resource.Resources {
 	return p.resources
 }
 
+func (p *pageState) Gallery(options ...any) (*page.Gallery, error) {
+	cfg, err := page.DecodeGalleryConfig(p.s.Info.gallery, p.m.params["gallery"])
+	if err != nil {
+		return nil, p.wrapError(err)
+	}
+	if len(options) > 0 {
+		if cfg, err = page.DecodeGalleryConfig(cfg, options[0]); err != nil {
+			return nil, err
+		}
+	}
+	return page.NewGallery(cfg, p.Resources())
+}
+
 func (p *pageState) HasShortcode(name string) bool {
 	if p.shortcodeState == nil {
 		return false
@@ -350,6 +400,15 @@ func (p *pageState) String() string {
 	return fmt.Sprintf("Page(%q)", p.Title())
 }
 
+// RenderScratch returns a Scratch that gets reset every time the page
+// is rendered in the current output format.
+func (p *pageState) RenderScratch() *maps.Scratch {
+	if p.pageOutput == nil {
+		return maps.NewScratch()
+	}
+	return p.pageOutput.renderScratch
+}
+
 // IsTranslated returns whether this content file is translated to
 // other language(s).
 func (p *pageState) IsTranslated() bool {
@@ -361,7 +420,20 @@ func (p *pageState) IsTranslated() bool {
 // It will use the translationKey set in front matter if set, or the content path and
 // filename (excluding any language code and extension), e.g. "about/index".
 // The Page Kind is always prepended.
+// Pages linked by the translations in front matter share the smallest of their keys.
 func (p *pageState) TranslationKey() string {
+	if len(p.s.h.Sites) > 1 {
+		p.s.h.init.translations.Do()
+		if p.translationGraphKey != "" {
+			return p.translationGraphKey
+		}
+	}
+	return p.baseTranslationKey()
+}
+
+// baseTranslationKey returns the translation key of p before any
+// translations in front matter are applied.
+func (p *pageState) baseTranslationKey() string {
 	p.translationKeyInit.Do(func() {
 		if p.m.translationKey != "" {
 			p.translationKey = p.Kind() + "/" + p.m.translationKey
@@ -387,6 +459,53 @@ func (p *pageState) Translations() page.Pages {
 	return p.translations
 }
 
+// Canonical returns the canonical URL of the page.
+func (p *pageState) Canonical() string {
+	host := p.s.Info.canonical.Host
+	if v, found := p.Params()["canonical"]; found {
+		if s := cast.ToString(v); s != "" {
+			if u, err := url.Parse(s); err == nil && u.IsAbs() {
+				return s
+			}
+			return page.CanonicalURL(p.s.PathSpec.AbsURL(s, false), host)
+		}
+	}
+	return page.CanonicalURL(p.Permalink(), host)
+}
+
+// Hreflangs returns the hreflang alternates of the page.
+func (p *pageState) Hreflangs() []page.Hreflang {
+	if !p.IsTranslated() {
+		return nil
+	}
+
+	var hreflangs []page.Hreflang
+	var xDefault string
+	xDefaultLang := p.s.Info.canonical.XDefault
+	for _, t := range p.AllTranslations() {
+		lang := t.Language()
+		u := t.Canonical()
+		hreflangs = append(hreflangs, page.Hreflang{Lang: hreflang(lang), URL: u})
+		if strings.EqualFold(lang.Lang, xDefaultLang) {
+			xDefault = u
+		}
+	}
+
+	if xDefault != "" {
+		hreflangs = append(hreflangs, page.Hreflang{Lang: "x-default", URL: xDefault})
+	}
+
+	return hreflangs
+}
+
+// hreflang returns the hreflang value of lang.
+func hreflang(lang *langs.Language) string {
+	if code := lang.GetString("languageCode"); code != "" {
+		return code
+	}
+	return lang.Lang
+}
+
 func (ps *pageState) initCommonProviders(pp pagePaths) error {
 	if ps.IsPage() {
 		ps.posNextPrev = &nextPrev{init: ps.s.init.prevNext}
@@ -408,7 +527,7 @@ func (p *pageState) getLayoutDescriptor() output.LayoutDescriptor {
 		var section string
 		sections := p.SectionsEntries()
 
-		switch p.Kind() {
+		switch p.m.kind {
 		case page.KindSection:
 			if len(sections) > 0 {
 				section = sections[0]
@@ -426,6 +545,21 @@ func (p *pageState) getLayoutDescriptor() output.LayoutDescriptor {
 			Layout:  p.Layout(),
 			Section: section,
 		}
+
+		if p.m.customKind != "" {
+			kc, _ := p.s.siteCfg.kinds.Get(p.m.customKind)
+			p.layoutDescriptor.BaseKind = p.m.kind
+			p.layoutDescriptor.KindLayouts = strings.Join(kc.Layouts, ",")
+		}
+
+		if dims := p.s.siteCfg.layoutRules.Dimensions; len(dims) > 0 {
+			values := make([]string, len(dims))
+			for i, dim := range dims {
+				v, _ := p.Param(dim)
+				values[i] = cast.ToString(v)
+			}
+			p.layoutDescriptor.Dimensions = strings.Join(values, ",")
+		}
 	})
 
 	return p.layoutDescriptor
@@ -542,6 +676,28 @@ func (p *pageState) AlternativeOutputFormats() page.OutputFormats {
 	return o
 }
 
+// Alternates returns the alternate output formats and translations of the
+// page, see page.AlternativeOutputFormatsProvider.
+func (p *pageState) Alternates() page.Alternates {
+	var alternates page.Alternates
+	for _, of := range p.AlternativeOutputFormats() {
+		alternates = append(alternates, page.Alternate{OutputFormat: of})
+	}
+
+	for _, t := range p.Translations() {
+		code := hreflang(t.Language())
+		for _, of := range t.OutputFormats() {
+			if of.Format.NotAlternative {
+				continue
+			}
+			of.Rel = "alternate"
+			alternates = append(alternates, page.Alternate{OutputFormat: of, Lang: code})
+		}
+	}
+
+	return alternates
+}
+
 type renderStringOpts struct {
 	Display string
 	Markup  string
@@ -559,6 +715,33 @@ func (p *pageState) addDependency(dep identity.Provider) {
 	p.pageOutput.cp.dependencyTracker.Add(dep)
 }
 
+// dependsOn returns whether any output of p depends on id, see
+// HugoSites.pagesDependingOn.
+func (p *pageState) dependsOn(id identity.Identity) bool {
+	for _, po := range p.pageOutputs {
+		if po.renderDeps != nil && po.renderDeps.Search(id) != nil {
+			return true
+		}
+		if po.cp != nil && po.cp.dependencyTracker != nil && po.cp.dependencyTracker.Search(id) != nil {
+			return true
+		}
+	}
+
+	if p.shortcodeState == nil {
+		return false
+	}
+
+	for _, s := range p.shortcodeState.shortcodes {
+		for _, templ := range s.templs {
+			if sid, ok := templ.(identity.Manager); ok && sid.Search(id) != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 // wrapError adds some more context to the given error if possible/needed
 func (p *pageState) wrapError(err error) error {
 	if err == nil {
@@ -630,6 +813,7 @@ func (p *pageState) mapContent(bucket *pagesMapBucket, meta *pageMeta) error {
 	var currShortcode shortcode
 	var ordinal int
 	var frontMatterSet bool
+	var frontMatter map[string]any
 
 Loop:
 	for {
@@ -638,6 +822,7 @@ Loop:
 		switch {
 		case it.Type == pageparser.TypeIgnore:
 		case it.IsFrontMatter():
+			p.source.frontMatter = it
 			f := pageparser.FormatFromFrontMatterType(it.Type)
 			m, err := metadecoders.Default.UnmarshalToMap(it.Val, f)
 			if err != nil {
@@ -660,22 +845,27 @@ Loop:
 				}
 			}
 
+			next := iter.Peek()
+			if !next.IsDone() {
+				p.source.posMainContent = next.Pos
+			}
+
 			if err := meta.setMetadata(bucket, p, m); err != nil {
 				return err
 			}
 
 			frontMatterSet = true
-
-			next := iter.Peek()
-			if !next.IsDone() {
-				p.source.posMainContent = next.Pos
-			}
+			frontMatter = m
 
 			if !p.s.shouldBuild(p) {
 				// Nothing more to do.
 				return nil
 			}
 
+			if err := p.validateFrontMatter(m, it.Pos); err != nil {
+				return err
+			}
+
 		case it.Type == pageparser.TypeLeadSummaryDivider:
 			posBody := -1
 			f := func(item pageparser.Item) bool {
@@ -758,6 +948,17 @@ Loop:
 		if err := meta.setMetadata(bucket, p, nil); err != nil {
 			return err
 		}
+		if err := p.validateFrontMatter(meta.params, 0); err != nil {
+			return err
+		}
+	}
+
+	// Enrich only the pages that get built, and after the front matter is
+	// processed, so the enriched params are just params.
+	if p.s.shouldBuild(p) {
+		if err := p.s.enrichParams(p, frontMatter); err != nil {
+			return err
+		}
 	}
 
 	p.cmap = rn
@@ -765,6 +966,34 @@ Loop:
 	return nil
 }
 
+// validateFrontMatter logs an error for every front matter value not matching
+// the schema for the page's content type.
+// The errors point to the front matter, at pos in the page.
+func (p *pageState) validateFrontMatter(frontmatter map[string]any, pos int) error {
+	schema, found, err := p.s.frontMatterSchema(p.Type())
+	if err != nil || !found {
+		return err
+	}
+
+	// Validate the computed params by their result.
+	for k, v := range frontmatter {
+		if _, ok := v.(pagemeta.ComputedParam); ok {
+			frontmatter[k] = p.m.params[k]
+		}
+	}
+
+	violations := schema.Validate(frontmatter)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	for _, v := range violations {
+		p.s.Log.Errorln(herrors.NewFileErrorFromPos(v, p.posFromPage(pos)))
+	}
+
+	return nil
+}
+
 func (p *pageState) errorf(err error, format string, a ...any) error {
 	if herrors.UnwrapFileError(err) != nil {
 		// More isn't always better.
@@ -853,6 +1082,8 @@ func (p *pageState) shiftToOutputFormat(isRenderingSite bool, idx int) error {
 	}
 
 	if isRenderingSite {
+		p.pageOutput.renderScratch = maps.NewScratch()
+
 		cp := p.pageOutput.cp
 		if cp == nil && p.reusePageOutputContent() {
 			// Look for content to reuse.
@@ -122,6 +122,7 @@ type pageCommon struct {
 	// Any bundled resources
 	resources            resource.Resources
 	resourcesInit        sync.Once
+	resourceAliases      []resourceAliases
 	resourcesPublishInit sync.Once
 
 	translations    page.Pages
@@ -131,6 +132,10 @@ type pageCommon struct {
 	translationKey     string
 	translationKeyInit sync.Once
 
+	// The key of the translation set this page is linked into, see
+	// linkTranslations.
+	translationGraphKey string
+
 	// Will only be set for bundled pages.
 	parent *pageState
 
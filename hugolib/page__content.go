@@ -88,6 +88,9 @@ type rawPageContent struct {
 	// shortcodes, front matter, summary indicators.
 	parsed pageparser.Result
 
+	// The front matter item, the zero value if none.
+	frontMatter pageparser.Item
+
 	// Returns the position in bytes after any front matter.
 	posMainContent int
 
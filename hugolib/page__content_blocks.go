@@ -0,0 +1,77 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strconv"
+)
+
+var (
+	// A content block marker on a line of its own, e.g. <!--block: sidebar-->.
+	contentBlockMarkerRe = regexp.MustCompile(`(?m)^[ \t]*<!--\s*block:\s*([^\s>]+?)\s*-->[ \t]*\r?$`)
+
+	// The block markers are replaced with these before the content is rendered,
+	// the number is the index of the block name.
+	internalContentBlockBase  = "HUGOBLOCK42x"
+	contentBlockPlaceholderRe = regexp.MustCompile(`(?:<p>)?` + internalContentBlockBase + `(\d+)x(?:</p>)?\n?`)
+)
+
+// insertContentBlockPlaceholders replaces the content block markers in c
+// with placeholders surviving the rendering of the content, returning the
+// names of the blocks.
+func insertContentBlockPlaceholders(c []byte) ([]byte, []string) {
+	if !bytes.Contains(c, []byte("block:")) {
+		return c, nil
+	}
+	var names []string
+	c = contentBlockMarkerRe.ReplaceAllFunc(c, func(b []byte) []byte {
+		name := string(contentBlockMarkerRe.FindSubmatch(b)[1])
+		names = append(names, name)
+		return []byte(fmt.Sprintf("\n\n%s%dx\n\n", internalContentBlockBase, len(names)-1))
+	})
+	return c, names
+}
+
+// splitContentBlocks splits the rendered content c into the main content,
+// before the first block, and the blocks, each running to the next block.
+// Blocks with the same name are joined.
+func splitContentBlocks(c []byte, names []string) ([]byte, map[string]template.HTML) {
+	locs := contentBlockPlaceholderRe.FindAllSubmatchIndex(c, -1)
+	if len(locs) == 0 {
+		return c, nil
+	}
+
+	blocks := make(map[string]template.HTML)
+	for i, loc := range locs {
+		idx, _ := strconv.Atoi(string(c[loc[2]:loc[3]]))
+		if idx >= len(names) {
+			continue
+		}
+		end := len(c)
+		if i < len(locs)-1 {
+			end = locs[i+1][0]
+		}
+		name := names[idx]
+		if blocks[name] != "" {
+			blocks[name] += "\n"
+		}
+		blocks[name] += template.HTML(bytes.TrimSpace(c[loc[1]:end]))
+	}
+
+	return bytes.TrimRight(c[:locs[0][0]], " \n"), blocks
+}
@@ -30,11 +30,11 @@ func (p *pageData) Data() any {
 	p.dataInit.Do(func() {
 		p.data = make(page.Data)
 
-		if p.Kind() == page.KindPage {
+		if p.m.kind == page.KindPage {
 			return
 		}
 
-		switch p.Kind() {
+		switch p.m.kind {
 		case page.KindTerm:
 			b := p.treeRef.n
 			name := b.viewInfo.name
@@ -0,0 +1,196 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/spf13/cast"
+)
+
+// includeGraph tracks which pages include which in the current build to
+// detect circular includes, which would otherwise wait for each other's
+// content until the timeout.
+type includeGraph struct {
+	mu    sync.Mutex
+	edges map[*pageState]map[*pageState]bool
+}
+
+func (g *includeGraph) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.edges = nil
+}
+
+// add adds the include of to in from, failing if to already (transitively)
+// includes from.
+func (g *includeGraph) add(from, to *pageState) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.edges == nil {
+		g.edges = make(map[*pageState]map[*pageState]bool)
+	}
+
+	if from == to || g.reaches(to, from, make(map[*pageState]bool)) {
+		return fmt.Errorf("circular include of %q in %q", to.pathOrTitle(), from.pathOrTitle())
+	}
+
+	if g.edges[from] == nil {
+		g.edges[from] = make(map[*pageState]bool)
+	}
+	g.edges[from][to] = true
+
+	return nil
+}
+
+func (g *includeGraph) reaches(from, to *pageState, seen map[*pageState]bool) bool {
+	if seen[from] {
+		return false
+	}
+	seen[from] = true
+	for p := range g.edges[from] {
+		if p == to || g.reaches(p, to, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// Include renders the content of the page ref, or the part of it below the
+// heading with the id given after a #, e.g. "/docs/install.md#requirements".
+// The ref is resolved relative to this page.
+// Relative links are rewritten to point to the included page, and the
+// footnotes referenced are appended with new ids.
+func (p *pageContentOutput) Include(ref string) (template.HTML, error) {
+	pageRef, id, _ := strings.Cut(ref, "#")
+	if pageRef == "" {
+		return "", fmt.Errorf("include: missing page in %q", ref)
+	}
+
+	tp, err := p.p.s.getPageNew(p.p, pageRef)
+	if err != nil {
+		return "", fmt.Errorf("include: %w", err)
+	}
+	target, ok := tp.(*pageState)
+	if !ok || target == nil {
+		return "", fmt.Errorf("include: page %q not found", pageRef)
+	}
+
+	if err := p.p.s.h.includes.add(p.p, target); err != nil {
+		return "", fmt.Errorf("include: %w", err)
+	}
+
+	c, err := target.Content()
+	if err != nil {
+		return "", err
+	}
+	content := cast.ToString(c)
+
+	fragment := content
+	if id != "" {
+		f, err := target.RenderFragment(id)
+		if err != nil {
+			return "", err
+		}
+		if f == "" {
+			return "", fmt.Errorf("include: no heading with id %q found in %q", id, target.pathOrTitle())
+		}
+		fragment = string(f)
+	} else {
+		// The footnotes are appended below with new ids.
+		if loc := footnotesStartRe.FindStringIndex(fragment); loc != nil {
+			fragment = strings.TrimSpace(fragment[:loc[0]])
+		}
+	}
+
+	prefix := helpers.MD5String(target.pathOrTitle() + "#" + id)[:8]
+
+	return template.HTML(rewriteIncluded(fragment, content, target.RelPermalink(), prefix)), nil
+}
+
+var (
+	footnotesStartRe = regexp.MustCompile(`<(div|section) class="footnotes"`)
+	footnoteRefRe    = regexp.MustCompile(`<a href="#fn:([^"]+)"([^>]*)>[^<]*</a>`)
+	footnoteIDRe     = regexp.MustCompile(`(id|href)="(#?)(fn|fnref\d*):([^"]+)"`)
+	idAttrRe         = regexp.MustCompile(`\sid="([^"]+)"`)
+	linkAttrRe       = regexp.MustCompile(`\s(href|src)="([^"]*)"`)
+)
+
+// rewriteIncluded rewrites fragment, a part of content published at
+// relPermalink, to be included in another page.
+func rewriteIncluded(fragment, content, relPermalink, prefix string) string {
+	// Collect and renumber the footnotes referenced in the fragment.
+	var (
+		footnotes []string
+		numbers   = make(map[string]int)
+	)
+	fragment = footnoteRefRe.ReplaceAllStringFunc(fragment, func(s string) string {
+		m := footnoteRefRe.FindStringSubmatch(s)
+		name := m[1]
+		n, found := numbers[name]
+		if !found {
+			liRe := regexp.MustCompile(`(?s)<li id="fn:` + regexp.QuoteMeta(name) + `"[^>]*>.*?</li>`)
+			if li := liRe.FindString(content); li != "" {
+				footnotes = append(footnotes, li)
+				n = len(footnotes)
+				numbers[name] = n
+			}
+		}
+		if n == 0 {
+			return s
+		}
+		return fmt.Sprintf(`<a href="#fn:%s"%s>%d</a>`, name, m[2], n)
+	})
+
+	if len(footnotes) > 0 {
+		fragment += "\n<div class=\"footnotes\" role=\"doc-endnotes\">\n<hr>\n<ol>\n" + strings.Join(footnotes, "\n") + "\n</ol>\n</div>"
+	}
+
+	fragment = footnoteIDRe.ReplaceAllString(fragment, `$1="$2$3:`+prefix+`-$4"`)
+
+	// Links to the ids in the fragment stay local, all other relative links
+	// are resolved relative to the included page.
+	ids := make(map[string]bool)
+	for _, m := range idAttrRe.FindAllStringSubmatch(fragment, -1) {
+		ids[m[1]] = true
+	}
+
+	base, err := url.Parse(relPermalink)
+	if err != nil {
+		return fragment
+	}
+
+	return linkAttrRe.ReplaceAllStringFunc(fragment, func(s string) string {
+		m := linkAttrRe.FindStringSubmatch(s)
+		attr, v := m[1], m[2]
+		if v == "" || strings.HasPrefix(v, "/") {
+			return s
+		}
+		if strings.HasPrefix(v, "#") && ids[v[1:]] {
+			return s
+		}
+		u, err := url.Parse(v)
+		if err != nil || u.IsAbs() {
+			return s
+		}
+		return fmt.Sprintf(` %s="%s"`, attr, base.ResolveReference(u))
+	})
+}
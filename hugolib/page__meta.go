@@ -18,6 +18,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -36,6 +37,7 @@ import (
 	"github.com/gohugoio/hugo/source"
 
 	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/common/types"
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/helpers"
 
@@ -58,6 +60,11 @@ type pageMeta struct {
 	// the templates.
 	kind string
 
+	// A custom kind set in front matter, e.g. author, and configured in
+	// the kinds section of the site config. If set, this is the Kind
+	// available to the templates.
+	customKind string
+
 	// This is a standalone page not part of any page collection. These
 	// include sitemap, robotsTXT and similar. It will have no pageOutputs, but
 	// a fixed pageOutput.
@@ -104,9 +111,15 @@ type pageMeta struct {
 	// from the page front matter.
 	translationKey string
 
+	// References to the translations of this page, from front matter.
+	translationRefs []string
+
 	// From front matter.
 	configuredOutputFormats output.Formats
 
+	// Whether this page is excluded from llms.txt and the Markdown export.
+	llmsExcluded bool
+
 	// This is the raw front matter metadata that is going to be assigned to
 	// the Resources above.
 	resourcesMetadata []map[string]any
@@ -180,7 +193,7 @@ func (p *pageMeta) File() source.File {
 }
 
 func (p *pageMeta) IsHome() bool {
-	return p.Kind() == page.KindHome
+	return p.kind == page.KindHome
 }
 
 func (p *pageMeta) Keywords() []string {
@@ -188,6 +201,9 @@ func (p *pageMeta) Keywords() []string {
 }
 
 func (p *pageMeta) Kind() string {
+	if p.customKind != "" {
+		return p.customKind
+	}
 	return p.kind
 }
 
@@ -215,7 +231,7 @@ func (p *pageMeta) IsNode() bool {
 }
 
 func (p *pageMeta) IsPage() bool {
-	return p.Kind() == page.KindPage
+	return p.kind == page.KindPage
 }
 
 // Param is a convenience method to do lookups in Page's and Site's Params map,
@@ -267,7 +283,7 @@ func (p *pageMeta) RelatedKeywords(cfg related.IndexConfig) ([]related.Keyword,
 }
 
 func (p *pageMeta) IsSection() bool {
-	return p.Kind() == page.KindSection
+	return p.kind == page.KindSection
 }
 
 func (p *pageMeta) Section() string {
@@ -408,6 +424,21 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 		gitAuthorDate = p.gitInfo.AuthorDate
 	}
 
+	// The time zone of the page, used for dates without time zone info,
+	// defaults to the time zone of the language.
+	location := langs.GetLocation(pm.s.Language())
+	var pageLocation *time.Location
+	for k, v := range frontmatter {
+		if strings.EqualFold(k, "timezone") {
+			var err error
+			if pageLocation, err = time.LoadLocation(cast.ToString(v)); err != nil {
+				return fmt.Errorf("invalid timeZone %q in front matter: %w", v, err)
+			}
+			location = pageLocation
+			break
+		}
+	}
+
 	descriptor := &pagemeta.FrontMatterDescriptor{
 		Frontmatter:   frontmatter,
 		Params:        pm.params,
@@ -416,7 +447,7 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 		BaseFilename:  contentBaseName,
 		ModTime:       mtime,
 		GitAuthorDate: gitAuthorDate,
-		Location:      langs.GetLocation(pm.s.Language()),
+		Location:      location,
 	}
 
 	// Handle the date separately
@@ -427,6 +458,11 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 		p.s.Log.Errorf("Failed to handle dates for page %q: %s", p.pathOrTitle(), err)
 	}
 
+	if pageLocation != nil {
+		// Show all dates in the time zone of the page, e.g. the Git author date.
+		pm.Dates.InLocation(pageLocation)
+	}
+
 	pm.buildConfig, err = pagemeta.DecodeBuildConfig(frontmatter["_build"])
 	if err != nil {
 		return err
@@ -434,6 +470,9 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 
 	var sitemapSet bool
 
+	var computed map[string]pagemeta.ComputedParam
+	var sitemapComputed map[string]pagemeta.ComputedParam
+
 	var draft, published, isCJKLanguage *bool
 	for k, v := range frontmatter {
 		loki := strings.ToLower(k)
@@ -487,6 +526,16 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 		case "type":
 			pm.contentType = cast.ToString(v)
 			pm.params[loki] = pm.contentType
+		case "kind":
+			kind := strings.ToLower(cast.ToString(v))
+			if kc, found := p.s.siteCfg.kinds.Get(kind); found {
+				pm.customKind = kind
+				if !hasBuildListConfig(frontmatter["_build"]) {
+					pm.buildConfig.List = kc.List
+				}
+			}
+			// Kept as a param for backwards compatibility.
+			pm.params[loki] = cast.ToString(v)
 		case "keywords":
 			pm.keywords = cast.ToStringSlice(v)
 			pm.params[loki] = pm.keywords
@@ -536,7 +585,20 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 			}
 			pm.params[loki] = pm.aliases
 		case "sitemap":
-			p.m.sitemap = config.DecodeSitemap(p.s.siteCfg.sitemap, maps.ToStringMap(v))
+			// The map may be shared with other pages via cascade, so don't modify it.
+			sm := make(map[string]any)
+			for k, vv := range maps.ToStringMap(v) {
+				if cp, ok := vv.(pagemeta.ComputedParam); ok {
+					// Evaluated with the computed params below.
+					if sitemapComputed == nil {
+						sitemapComputed = make(map[string]pagemeta.ComputedParam)
+					}
+					sitemapComputed[k] = cp
+					continue
+				}
+				sm[k] = vv
+			}
+			p.m.sitemap = config.DecodeSitemap(p.s.siteCfg.sitemap, sm)
 			pm.params[loki] = p.m.sitemap
 			sitemapSet = true
 		case "iscjklanguage":
@@ -545,6 +607,9 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 		case "translationkey":
 			pm.translationKey = cast.ToString(v)
 			pm.params[loki] = pm.translationKey
+		case "translations":
+			pm.translationRefs = types.ToStringSlicePreserveString(v)
+			pm.params[loki] = pm.translationRefs
 		case "resources":
 			var resources []map[string]any
 			handled := true
@@ -577,6 +642,13 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 			fallthrough
 
 		default:
+			if cp, ok := v.(pagemeta.ComputedParam); ok {
+				if computed == nil {
+					computed = make(map[string]pagemeta.ComputedParam)
+				}
+				computed[loki] = cp
+				continue
+			}
 			// If not one of the explicit values, store in Params
 			switch vv := v.(type) {
 			case bool:
@@ -646,9 +718,77 @@ func (pm *pageMeta) setMetadata(parentBucket *pagesMapBucket, p *pageState, fron
 
 	pm.params["iscjklanguage"] = p.m.isCJKLanguage
 
+	if err := pm.setComputedParams(p, computed); err != nil {
+		return err
+	}
+
+	return pm.setComputedSitemap(p, sitemapComputed)
+}
+
+// setComputedParams evaluates the computed params with the page as context
+// and stores the results in params. They are evaluated in key order
+// after all other front matter has been handled.
+func (pm *pageMeta) setComputedParams(p *pageState, computed map[string]pagemeta.ComputedParam) error {
+	keys := make([]string, 0, len(computed))
+	for k := range computed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, err := pm.evalComputedParam(p, k, computed[k])
+		if err != nil {
+			return err
+		}
+		pm.params[k] = v
+	}
+
 	return nil
 }
 
+// setComputedSitemap evaluates the computed sitemap settings, e.g. priority
+// or disable, with the page as context and applies them to the page's sitemap.
+func (pm *pageMeta) setComputedSitemap(p *pageState, computed map[string]pagemeta.ComputedParam) error {
+	if len(computed) == 0 {
+		return nil
+	}
+
+	m := make(map[string]any)
+	for k, cp := range computed {
+		v, err := pm.evalComputedParam(p, "sitemap."+k, cp)
+		if err != nil {
+			return err
+		}
+		m[k] = v
+	}
+
+	pm.sitemap = config.DecodeSitemap(pm.sitemap, m)
+	pm.params["sitemap"] = pm.sitemap
+
+	return nil
+}
+
+func (pm *pageMeta) evalComputedParam(p *pageState, key string, cp pagemeta.ComputedParam) (any, error) {
+	templName := "_computed/" + cp.Expr
+	templ, found := pm.s.TextTmpl().Lookup(templName)
+	if !found {
+		var err error
+		templ, err = pm.s.TextTmpl().Parse(templName, cp.Template())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse computed param %q: %w", key, err)
+		}
+	}
+	result, err := executeToString(pm.s.Tmpl(), templ, p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute param %q for page %q: %w", key, p.pathOrTitle(), err)
+	}
+	v, err := cp.Convert(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert computed param %q for page %q: %w", key, p.pathOrTitle(), err)
+	}
+	return v, nil
+}
+
 func (p *pageMeta) noListAlways() bool {
 	return p.buildConfig.List != pagemeta.Always
 }
@@ -686,7 +826,7 @@ func (p *pageMeta) applyDefaultValues(n *contentNode) error {
 		p.buildConfig, _ = pagemeta.DecodeBuildConfig(nil)
 	}
 
-	if !p.s.isEnabled(p.Kind()) {
+	if !p.s.isEnabled(p.kind) || !p.s.isEnabled(p.Kind()) {
 		(&p.buildConfig).Disable()
 	}
 
@@ -701,7 +841,7 @@ func (p *pageMeta) applyDefaultValues(n *contentNode) error {
 	}
 
 	if p.title == "" && p.f.IsZero() {
-		switch p.Kind() {
+		switch p.kind {
 		case page.KindHome:
 			p.title = p.s.Info.title
 		case page.KindSection:
@@ -783,11 +923,46 @@ func (p *pageMeta) newContentConverter(ps *pageState, markup string) (converter.
 
 // The output formats this page will be rendered to.
 func (m *pageMeta) outputFormats() output.Formats {
+	formats := m.configuredOrDefaultOutputFormats()
+
+	if m.llmsExcluded && len(formats) > 1 {
+		if _, found := formats.GetByName(output.MarkdownFormat.Name); found {
+			// Excluded from the Markdown export by the llms config.
+			filtered := make(output.Formats, 0, len(formats)-1)
+			for _, f := range formats {
+				if f.Name != output.MarkdownFormat.Name {
+					filtered = append(filtered, f)
+				}
+			}
+			return filtered
+		}
+	}
+
+	return formats
+}
+
+func (m *pageMeta) configuredOrDefaultOutputFormats() output.Formats {
 	if len(m.configuredOutputFormats) > 0 {
 		return m.configuredOutputFormats
 	}
 
-	return m.s.outputFormats[m.Kind()]
+	if m.customKind != "" {
+		if formats, found := m.s.outputFormats[m.customKind]; found {
+			return formats
+		}
+	}
+
+	return m.s.outputFormats[m.kind]
+}
+
+// hasBuildListConfig reports whether list is set in the _build front matter.
+func hasBuildListConfig(v any) bool {
+	m, err := maps.ToStringMapE(v)
+	if err != nil {
+		return false
+	}
+	_, found := maps.LookupEqualFold(m, "list")
+	return found
 }
 
 func (p *pageMeta) Slug() string {
@@ -119,6 +119,8 @@ func newPageFromMeta(
 		return nil, err
 	}
 
+	metaProvider.llmsExcluded = !metaProvider.s.siteCfg.llms.Includes(ps)
+
 	ps.init.Add(func() (any, error) {
 		pp, err := newPagePaths(metaProvider.s, ps, metaProvider)
 		if err != nil {
@@ -0,0 +1,155 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/text"
+	"github.com/gohugoio/hugo/resources/page"
+)
+
+// References to numbered elements are rendered as placeholders holding the
+// language, the target page and the ID, replaced with links once the
+// target's content is rendered.
+const numberRefPlaceholderPrefix = "HAHAHUGONUMREF"
+
+var numberRefPlaceholderRe = regexp.MustCompile(numberRefPlaceholderPrefix + `-([0-9a-f]*)-([0-9a-f]*)-([0-9a-f]*)-HBHB`)
+
+// emptyNumbering is used for pages without content.
+var emptyNumbering = page.NewNumbering(page.DefaultNumberingConfig, "")
+
+func (p *pageState) Numbering() *page.Numbering {
+	if p.pageOutput == nil || p.pageOutput.cp == nil || p.pageOutput.cp.numbering == nil {
+		return emptyNumbering
+	}
+	return p.pageOutput.cp.numbering
+}
+
+func (p *pageState) NumberRef(ref string) (template.HTML, error) {
+	pth, id := "", ref
+	if i := strings.LastIndex(ref, "#"); i != -1 {
+		pth, id = ref[:i], ref[i+1:]
+	}
+	if id == "" {
+		return "", fmt.Errorf("NumberRef: no ID in %q", ref)
+	}
+
+	target := p
+	if pth != "" {
+		tp, err := p.s.getPageRef(p, pth)
+		if err != nil {
+			return "", err
+		}
+		if tp == nil {
+			p.s.siteRefLinker.logNotFound(ref, "page not found", p, text.Position{})
+			return "??", nil
+		}
+		target = tp.(*pageState)
+	}
+
+	enc := hex.EncodeToString
+	return template.HTML(fmt.Sprintf("%s-%s-%s-%s-HBHB", numberRefPlaceholderPrefix, enc([]byte(target.s.Lang())), enc([]byte(target.Pathc())), enc([]byte(id)))), nil
+}
+
+// numberingPrefix returns the prefix of the numbers in p, e.g. "2." for the
+// second page in its section when numbering.scope is section.
+func (p *pageState) numberingPrefix() string {
+	if p.s.Info.numbering.Scope != "section" || !p.IsPage() {
+		return ""
+	}
+	parent := p.Parent()
+	if parent == nil {
+		return ""
+	}
+	for i, pp := range parent.RegularPages() {
+		if pp == p {
+			return strconv.Itoa(i+1) + "."
+		}
+	}
+	return ""
+}
+
+// resolveNumberRefs replaces the references to numbered elements in b with
+// links. If self is set, only references to self are replaced.
+func (s *Site) resolveNumberRefs(b []byte, self *pageState) []byte {
+	if !bytes.Contains(b, []byte(numberRefPlaceholderPrefix)) {
+		return b
+	}
+
+	return numberRefPlaceholderRe.ReplaceAllFunc(b, func(placeholder []byte) []byte {
+		m := numberRefPlaceholderRe.FindSubmatch(placeholder)
+		var parts [3]string
+		for i := range parts {
+			v, err := hex.DecodeString(string(m[i+1]))
+			if err != nil {
+				return placeholder
+			}
+			parts[i] = string(v)
+		}
+		lang, pth, id := parts[0], parts[1], parts[2]
+
+		if self != nil && (self.s.Lang() != lang || self.Pathc() != pth) {
+			return placeholder
+		}
+
+		target := self
+		if target == nil {
+			for _, ss := range s.h.Sites {
+				if ss.Lang() != lang {
+					continue
+				}
+				if tp, _ := ss.getPageNew(nil, "/"+strings.TrimPrefix(pth, "/")); tp != nil {
+					target, _ = tp.(*pageState)
+				}
+			}
+			if target == nil {
+				s.siteRefLinker.logNotFound(pth+"#"+id, "page not found", nil, text.Position{})
+				return []byte("??")
+			}
+			// Make sure the numbers are assigned.
+			if _, err := target.Content(); err != nil {
+				return []byte("??")
+			}
+		}
+
+		num, found := target.Numbering().Get(id)
+		if !found {
+			s.siteRefLinker.logNotFound(pth+"#"+id, fmt.Sprintf("no numbered element with ID %q", id), target, text.Position{})
+			return []byte("??")
+		}
+
+		href := "#" + id
+		if self == nil {
+			href = target.RelPermalink() + href
+		}
+
+		return []byte(fmt.Sprintf(`<a href="%s" class="numref">%s</a>`, template.HTMLEscapeString(href), template.HTMLEscapeString(num.Label)))
+	})
+}
+
+func (s *Site) resolveNumberRefsInBuffer(buf *bytes.Buffer) {
+	if !bytes.Contains(buf.Bytes(), []byte(numberRefPlaceholderPrefix)) {
+		return
+	}
+	b := s.resolveNumberRefs(buf.Bytes(), nil)
+	buf.Reset()
+	buf.Write(b)
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPageNumbering(t *testing.T) {
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+[numbering.labels]
+table = "Tab."
+-- content/docs/_index.md --
+---
+title: "Docs"
+---
+-- content/docs/a.md --
+---
+title: "A"
+weight: 1
+---
+
+As shown in {{< numref "fig-arch" >}} and {{< numref "tab-1" >}}, see also {{< numref "b.md#fig-flow" >}}.
+
+{{< figure src="/arch.png" id="fig-arch" caption="The *architecture*" >}}
+
+{{< figure src="/other.png" id="fig-other" >}}
+
+{{< table id="tab-1" >}}
+-- content/docs/b.md --
+---
+title: "B"
+weight: 2
+---
+
+{{< figure src="/flow.png" id="fig-flow" caption="Flow" >}}
+
+Back to {{< numref "a.md#fig-other" >}}.
+-- layouts/shortcodes/table.html --
+{{ $n := .Page.Numbering.Next "table" (.Get "id") }}<table id="{{ $n.ID }}"><caption>{{ $n.Label }}</caption></table>
+-- layouts/_default/single.html --
+{{ .Content }}
+Figures: {{ range .Numbering.Of "figure" }}{{ .Label }}={{ .ID }}|{{ end }}
+Summary: {{ .Summary }}
+-- layouts/_default/list.html --
+{{ .Title }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/docs/a/index.html",
+		`As shown in <a href="#fig-arch" class="numref">Figure 1</a> and <a href="#tab-1" class="numref">Tab. 1</a>, see also <a href="/docs/b/#fig-flow" class="numref">Figure 1</a>.`,
+		`<figure id="fig-arch"><img src="/arch.png"`,
+		`<p><span class="figure-number">Figure 1:</span> The <em>architecture</em></p>`,
+		`<p><span class="figure-number">Figure 2:</span> </p>`,
+		`<table id="tab-1"><caption>Tab. 1</caption></table>`,
+		"Figures: Figure 1=fig-arch|Figure 2=fig-other|",
+	)
+
+	b.AssertFileContent("public/docs/b/index.html",
+		`Back to <a href="/docs/a/#fig-other" class="numref">Figure 2</a>.`,
+	)
+}
+
+func TestPageNumberingSectionScope(t *testing.T) {
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+[numbering]
+scope = "section"
+-- content/docs/a.md --
+---
+title: "A"
+weight: 1
+---
+-- content/docs/b.md --
+---
+title: "B"
+weight: 2
+---
+
+{{< figure src="/flow.png" id="fig-flow" >}} {{< numref "fig-flow" >}} {{< numref "fig-missing" >}}
+-- layouts/_default/single.html --
+{{ .Content }}
+`
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.AssertFileContent("public/docs/b/index.html", `<span class="figure-number">Figure 2.1:</span>`, `<a href="#fig-flow" class="numref">Figure 2.1</a> ??`)
+	b.AssertLogContains(`REF_NOT_FOUND: Ref "docs/b.md#fig-missing" from page "docs/b.md": no numbered element with ID "fig-missing"`)
+}
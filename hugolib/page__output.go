@@ -14,6 +14,8 @@
 package hugolib
 
 import (
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/identity"
 	"github.com/gohugoio/hugo/output"
 	"github.com/gohugoio/hugo/resources/page"
 	"github.com/gohugoio/hugo/resources/resource"
@@ -61,6 +63,7 @@ func newPageOutput(
 		PageRenderProvider:      page.NopPage,
 		render:                  render,
 		paginator:               pag,
+		renderScratch:           maps.NewScratch(),
 	}
 
 	return po
@@ -88,6 +91,26 @@ type pageOutput struct {
 
 	// May be nil.
 	cp *pageContentOutput
+
+	// Reset every time the page is rendered to this format.
+	renderScratch *maps.Scratch
+
+	// The template used in the last render of this output format,
+	// set in server mode.
+	renderDeps identity.Manager
+}
+
+var pageOutputRenderDependenciesID = identity.KeyValueIdentity{Key: "pageOutput", Value: "renderDependencies"}
+
+// trackRenderDependency records templ as the template this output format
+// was rendered with.
+func (p *pageOutput) trackRenderDependency(templ identity.Provider) {
+	if p.renderDeps == nil {
+		p.renderDeps = identity.NewManager(pageOutputRenderDependenciesID)
+	} else {
+		p.renderDeps.Reset()
+	}
+	p.renderDeps.Add(templ)
 }
 
 func (p *pageOutput) initContentProvider(cp *pageContentOutput) {
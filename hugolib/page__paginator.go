@@ -14,9 +14,13 @@
 package hugolib
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/gohugoio/hugo/resources/page"
+	"github.com/spf13/cast"
 )
 
 func newPagePaginator(source *pageState) *pagePaginator {
@@ -44,7 +48,7 @@ func (p *pagePaginator) reset() {
 func (p *pagePaginator) Paginate(seq any, options ...any) (*page.Pager, error) {
 	var initErr error
 	p.init.Do(func() {
-		pagerSize, err := page.ResolvePagerSize(p.source.s.Cfg, options...)
+		pagerSize, err := p.resolvePagerSize(options...)
 		if err != nil {
 			initErr = err
 			return
@@ -71,7 +75,7 @@ func (p *pagePaginator) Paginate(seq any, options ...any) (*page.Pager, error) {
 func (p *pagePaginator) Paginator(options ...any) (*page.Pager, error) {
 	var initErr error
 	p.init.Do(func() {
-		pagerSize, err := page.ResolvePagerSize(p.source.s.Cfg, options...)
+		pagerSize, err := p.resolvePagerSize(options...)
 		if err != nil {
 			initErr = err
 			return
@@ -82,7 +86,7 @@ func (p *pagePaginator) Paginator(options ...any) (*page.Pager, error) {
 
 		var pages page.Pages
 
-		switch p.source.Kind() {
+		switch p.source.m.kind {
 		case page.KindHome:
 			// From Hugo 0.57 we made home.Pages() work like any other
 			// section. To avoid the default paginators for the home page
@@ -94,6 +98,13 @@ func (p *pagePaginator) Paginator(options ...any) (*page.Pager, error) {
 			pages = p.source.RegularPages()
 		}
 
+		by, order := p.paginateSort()
+		pages, err = sortPagesBy(pages, by, order)
+		if err != nil {
+			initErr = fmt.Errorf("%q: %w", p.source.Pathc(), err)
+			return
+		}
+
 		paginator, err := page.Paginate(pd, pages, pagerSize)
 		if err != nil {
 			initErr = err
@@ -109,3 +120,94 @@ func (p *pagePaginator) Paginator(options ...any) (*page.Pager, error) {
 
 	return p.current, nil
 }
+
+// paginationOption returns the page's front matter param (or cascade) with
+// the given name, falling back to the taxonomyOptions of its taxonomy.
+func (p *pagePaginator) paginationOption(name string, fromTaxonomy func(o taxonomyOptions) any) any {
+	if v, found := p.source.Params()[strings.ToLower(name)]; found {
+		return v
+	}
+	switch p.source.Kind() {
+	case page.KindTaxonomy, page.KindTerm:
+		if o, found := p.source.s.siteCfg.taxonomyOptions[p.source.Section()]; found {
+			return fromTaxonomy(o)
+		}
+	}
+	return nil
+}
+
+func (p *pagePaginator) resolvePagerSize(options ...any) (int, error) {
+	if len(options) == 0 {
+		v := p.paginationOption("paginate", func(o taxonomyOptions) any {
+			if o.Paginate == 0 {
+				return nil
+			}
+			return o.Paginate
+		})
+		if v != nil {
+			size, err := cast.ToIntE(v)
+			if err != nil || size <= 0 {
+				return -1, fmt.Errorf("%q: 'paginate' must be a positive integer", p.source.Pathc())
+			}
+			return size, nil
+		}
+	}
+	return page.ResolvePagerSize(p.source.s.Cfg, options...)
+}
+
+func (p *pagePaginator) paginateSort() (by, order string) {
+	by = cast.ToString(p.paginationOption("paginateBy", func(o taxonomyOptions) any { return o.PaginateBy }))
+	order = cast.ToString(p.paginationOption("paginateOrder", func(o taxonomyOptions) any { return o.PaginateOrder }))
+	return
+}
+
+func validatePaginateSort(by, order string) error {
+	if _, found := paginateSorts[strings.ToLower(by)]; !found && by != "" {
+		return fmt.Errorf("invalid paginateBy %q", by)
+	}
+	switch strings.ToLower(order) {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("invalid paginateOrder %q, must be asc or desc", order)
+	}
+	return nil
+}
+
+// The sort orders of paginateBy, "count" sorts by the number of pages, e.g.
+// the terms on a taxonomy page.
+var paginateSorts = map[string]func(page.Pages) page.Pages{
+	"weight":      page.Pages.ByWeight,
+	"title":       page.Pages.ByTitle,
+	"linktitle":   page.Pages.ByLinkTitle,
+	"date":        page.Pages.ByDate,
+	"publishdate": page.Pages.ByPublishDate,
+	"expirydate":  page.Pages.ByExpiryDate,
+	"lastmod":     page.Pages.ByLastmod,
+	"length":      page.Pages.ByLength,
+	"count": func(pages page.Pages) page.Pages {
+		sorted := make(page.Pages, len(pages))
+		copy(sorted, pages)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return len(sorted[i].Pages()) < len(sorted[j].Pages())
+		})
+		return sorted
+	},
+}
+
+// sortPagesBy sorts pages as configured in paginateBy and paginateOrder, the
+// pages are returned as is if both are empty.
+func sortPagesBy(pages page.Pages, by, order string) (page.Pages, error) {
+	if by == "" && order == "" {
+		return pages, nil
+	}
+	if err := validatePaginateSort(by, order); err != nil {
+		return nil, err
+	}
+	if by != "" {
+		pages = paginateSorts[strings.ToLower(by)](pages)
+	}
+	if strings.ToLower(order) == "desc" {
+		pages = pages.Reverse()
+	}
+	return pages, nil
+}
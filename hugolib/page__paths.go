@@ -18,6 +18,7 @@ import (
 	"strings"
 
 	"github.com/gohugoio/hugo/helpers"
+	"github.com/spf13/cast"
 
 	"github.com/gohugoio/hugo/resources/page"
 )
@@ -124,14 +125,25 @@ func createTargetPathDescriptor(s *Site, p page.Page, pm *pageMeta) (page.Target
 
 	alwaysInSubDir := p.Kind() == kindSitemap
 
+	// The URL style can be set in front matter, e.g. for a section with cascade.
+	uglyURLs := s.Info.uglyURLs(p)
+	if v, found := pm.params["uglyurls"]; found {
+		uglyURLs = cast.ToBool(v)
+	}
+	var noTrailingSlash bool
+	if v, found := pm.params["trailingslash"]; found {
+		noTrailingSlash = !cast.ToBool(v)
+	}
+
 	desc := page.TargetPathDescriptor{
-		PathSpec:    d.PathSpec,
-		Kind:        p.Kind(),
-		Sections:    p.SectionsEntries(),
-		UglyURLs:    s.Info.uglyURLs(p),
-		ForcePrefix: s.h.IsMultihost() || alwaysInSubDir,
-		Dir:         dir,
-		URL:         pm.urlPaths.URL,
+		PathSpec:        d.PathSpec,
+		Kind:            pm.kind,
+		Sections:        p.SectionsEntries(),
+		UglyURLs:        uglyURLs,
+		NoTrailingSlash: noTrailingSlash,
+		ForcePrefix:     s.h.IsMultihost() || alwaysInSubDir,
+		Dir:             dir,
+		URL:             pm.urlPaths.URL,
 	}
 
 	if pm.Slug() != "" {
@@ -148,12 +160,19 @@ func createTargetPathDescriptor(s *Site, p page.Page, pm *pageMeta) (page.Target
 	// the permalink configuration values are likely to be redundant, e.g.
 	// naively expanding /category/:slug/ would give /category/categories/ for
 	// the "categories" page.KindTaxonomyTerm.
-	if p.Kind() == page.KindPage || p.Kind() == page.KindTerm {
-		opath, err := d.ResourceSpec.Permalinks.Expand(p.Section(), p)
+	if pm.kind == page.KindPage || pm.kind == page.KindTerm || pm.customKind != "" {
+		opath, err := d.ResourceSpec.Permalinks.ExpandKind(pm.customKind, p)
 		if err != nil {
 			return desc, err
 		}
 
+		if opath == "" && pm.kind != page.KindSection && pm.kind != page.KindHome {
+			opath, err = d.ResourceSpec.Permalinks.Expand(p.Section(), p)
+			if err != nil {
+				return desc, err
+			}
+		}
+
 		if opath != "" {
 			opath, _ = url.QueryUnescape(opath)
 			desc.ExpandedPermalink = opath
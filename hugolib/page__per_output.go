@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"html/template"
+	"regexp"
 	"runtime/debug"
 	"strings"
 	"sync"
@@ -30,10 +31,12 @@ import (
 	"github.com/gohugoio/hugo/identity"
 	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cast"
+	"golang.org/x/net/html"
 
 	"github.com/gohugoio/hugo/markup/converter/hooks"
 
 	"github.com/gohugoio/hugo/markup/converter"
+	"github.com/gohugoio/hugo/markup/rawhtml"
 
 	"github.com/alecthomas/chroma/lexers"
 	"github.com/gohugoio/hugo/lazy"
@@ -105,6 +108,8 @@ func newPageContentOutput(p *pageState, po *pageOutput) (*pageContentOutput, err
 			return err
 		}
 
+		cp.numbering = page.NewNumbering(p.s.Info.numbering, p.numberingPrefix())
+
 		var hasShortcodeVariants bool
 
 		f := po.f
@@ -119,6 +124,9 @@ func newPageContentOutput(p *pageState, po *pageOutput) (*pageContentOutput, err
 
 		cp.workContent = p.contentToRender(cp.contentPlaceholders)
 
+		var contentBlockNames []string
+		cp.workContent, contentBlockNames = insertContentBlockPlaceholders(cp.workContent)
+
 		isHTML := cp.p.m.markup == "html"
 
 		if !isHTML {
@@ -128,6 +136,7 @@ func newPageContentOutput(p *pageState, po *pageOutput) (*pageContentOutput, err
 			}
 
 			cp.workContent = r.Bytes()
+			cp.reportContentProblems(cp.workContent)
 
 			if tocProvider, ok := r.(converter.TableOfContentsProvider); ok {
 				cfg := p.s.ContentSpec.Converters.GetMarkupConfig()
@@ -159,6 +168,13 @@ func newPageContentOutput(p *pageState, po *pageOutput) (*pageContentOutput, err
 			}
 		}
 
+		if len(contentBlockNames) > 0 {
+			cp.workContent, cp.contentBlocks = splitContentBlocks(cp.workContent, contentBlockNames)
+			for name, block := range cp.contentBlocks {
+				cp.contentBlocks[name] = template.HTML(p.s.resolveNumberRefs([]byte(block), p))
+			}
+		}
+
 		if cp.p.source.hasSummaryDivider {
 			if isHTML {
 				src := p.source.parsed.Input()
@@ -190,6 +206,11 @@ func newPageContentOutput(p *pageState, po *pageOutput) (*pageContentOutput, err
 			cp.summary = helpers.BytesToHTML(html)
 		}
 
+		// Resolve the references to numbered elements in this page,
+		// the others are resolved when published.
+		cp.workContent = p.s.resolveNumberRefs(cp.workContent, p)
+		cp.summary = template.HTML(p.s.resolveNumberRefs([]byte(cp.summary), p))
+
 		cp.content = helpers.BytesToHTML(cp.workContent)
 
 		return nil
@@ -244,11 +265,17 @@ type pageContentOutput struct {
 	// after any markup is rendered, so they share a common prefix.
 	contentPlaceholders map[string]string
 
+	// The numbered elements in the content.
+	numbering *page.Numbering
+
 	// Content sections
 	content         template.HTML
 	summary         template.HTML
 	tableOfContents template.HTML
 
+	// The named content blocks, after the main content.
+	contentBlocks map[string]template.HTML
+
 	truncated bool
 
 	plainWords     []string
@@ -280,6 +307,11 @@ func (p *pageContentOutput) Content() (any, error) {
 	return nil, nil
 }
 
+func (p *pageContentOutput) ContentBlocks() map[string]template.HTML {
+	p.p.s.initInit(p.initMain, p.p)
+	return p.contentBlocks
+}
+
 func (p *pageContentOutput) FuzzyWordCount() int {
 	p.p.s.initInit(p.initPlain, p.p)
 	return p.fuzzyWordCount
@@ -397,6 +429,126 @@ func (p *pageContentOutput) RenderString(args ...any) (template.HTML, error) {
 	return template.HTML(string(b)), nil
 }
 
+// RenderShortcode renders the top level shortcode with the given name in the
+// content of this page for the current output format, the first if index is
+// not set. Shortcodes using the {{% %}} notation are rendered with the
+// content renderer and any render hooks of this page.
+// It returns an empty string if no such shortcode is found.
+func (p *pageContentOutput) RenderShortcode(name string, index ...int) (template.HTML, error) {
+	if len(index) > 1 {
+		return "", errors.New("want 1 or 2 arguments")
+	}
+
+	idx := 0
+	if len(index) == 1 {
+		idx = index[0]
+	}
+
+	if p.p.shortcodeState == nil {
+		return "", nil
+	}
+
+	var sc *shortcode
+	for _, v := range p.p.shortcodeState.shortcodes {
+		if v.name != name {
+			continue
+		}
+		if idx == 0 {
+			sc = v
+			break
+		}
+		idx--
+	}
+
+	if sc == nil {
+		return "", nil
+	}
+
+	if !p.p.s.initInit(p.initMain, p.p) {
+		return "", nil
+	}
+
+	rendered := p.contentPlaceholders[sc.placeholder]
+
+	if sc.insertPlaceholder() || p.p.m.markup == "html" {
+		return template.HTML(rendered), nil
+	}
+
+	c, err := p.renderContent([]byte(rendered), false)
+	if err != nil {
+		return "", p.p.wrapError(err)
+	}
+
+	return template.HTML(string(bytes.TrimSpace(c.Bytes()))), nil
+}
+
+// RenderFragment renders the fragment of the content of this page for the
+// current output format starting with the heading with the given id, up to
+// the next heading of the same or a higher level.
+// It returns an empty string if no such heading is found.
+func (p *pageContentOutput) RenderFragment(id string) (template.HTML, error) {
+	if id == "" {
+		return "", errors.New("missing fragment id")
+	}
+
+	if !p.p.s.initInit(p.initMain, p.p) {
+		return "", nil
+	}
+
+	return template.HTML(strings.TrimSpace(headingFragment(string(p.content), id))), nil
+}
+
+// headingFragment returns the part of content starting with the heading
+// with the given id, up to the next heading of the same or a higher level.
+func headingFragment(content, id string) string {
+	var (
+		offset int
+		start  = -1
+		level  byte
+	)
+
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF, or invalid HTML, which we treat the same.
+			break
+		}
+		tokenStart := offset
+		offset += len(z.Raw())
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+		name, hasAttr := z.TagName()
+		if len(name) != 2 || name[0] != 'h' || name[1] < '1' || name[1] > '6' {
+			continue
+		}
+
+		if start != -1 {
+			if name[1] <= level {
+				return content[start:tokenStart]
+			}
+			continue
+		}
+
+		for hasAttr {
+			var key, val []byte
+			key, val, hasAttr = z.TagAttr()
+			if string(key) == "id" && string(val) == id {
+				start, level = tokenStart, name[1]
+				break
+			}
+		}
+	}
+
+	if start == -1 {
+		return ""
+	}
+
+	return content[start:]
+}
+
 func (p *pageContentOutput) RenderWithTemplateInfo(info tpl.Info, layout ...string) (template.HTML, error) {
 	p.p.addDependency(info)
 	return p.Render(layout...)
@@ -481,6 +633,10 @@ func (p *pageContentOutput) initRenderHooks() error {
 				layoutDescriptor.Kind = "render-image"
 			case hooks.HeadingRendererType:
 				layoutDescriptor.Kind = "render-heading"
+			case hooks.CitationRendererType:
+				layoutDescriptor.Kind = "render-citation"
+			case hooks.BibliographyRendererType:
+				layoutDescriptor.Kind = "render-bibliography"
 			case hooks.CodeBlockRendererType:
 				layoutDescriptor.Kind = "render-codeblock"
 				if id != nil {
@@ -578,14 +734,36 @@ func (cp *pageContentOutput) renderContent(content []byte, renderTOC bool) (conv
 }
 
 func (cp *pageContentOutput) renderContentWithConverter(c converter.Converter, content []byte, renderTOC bool) (converter.Result, error) {
-	r, err := c.Convert(
-		converter.RenderContext{
-			Src:         content,
-			RenderTOC:   renderTOC,
-			GetRenderer: cp.renderHooks.getRenderer,
-		})
+	rctx := converter.RenderContext{
+		Src:         content,
+		RenderTOC:   renderTOC,
+		GetRenderer: cp.renderHooks.getRenderer,
+	}
+	if renderTOC {
+		// Only the main content, the one with a ToC, gets the glossary terms
+		// marked up and the citations resolved.
+		rctx.Glossary = cp.p.glossary()
+		rctx.Bibliography = cp.p.bibliography()
+	}
+
+	if cp.p.m.markup == "pandoc" {
+		rctx.LuaFilters = cp.p.pandocLuaFilters()
+		rctx.CSL = cp.p.pandocCSL()
+		rctx.CitationLocale = cp.p.pandocCitationLocale()
+	}
+
+	var rawHTMLRemoved []rawhtml.Removal
+	if rctx.RawHTML = cp.p.rawHTMLPolicy(); rctx.RawHTML != nil {
+		rctx.RawHTMLRemoved = func(r rawhtml.Removal) {
+			rawHTMLRemoved = append(rawHTMLRemoved, r)
+		}
+	}
+
+	r, err := c.Convert(rctx)
 
 	if err == nil {
+		cp.p.reportRawHTMLRemoved(rawHTMLRemoved)
+
 		if ids, ok := r.(identity.IdentitiesProvider); ok {
 			for _, v := range ids.GetIdentities() {
 				cp.trackDependency(v)
@@ -596,6 +774,37 @@ func (cp *pageContentOutput) renderContentWithConverter(c converter.Converter, c
 	return r, err
 }
 
+var (
+	rawHTMLOmittedMarker = []byte("<!-- raw HTML omitted -->")
+	imgRe                = regexp.MustCompile(`(?i)<img\b[^>]*>`)
+	imgAltRe             = regexp.MustCompile(`(?i)\salt\s*=\s*("[^"]*[^"\s][^"]*"|'[^']*[^'\s][^']*'|[^\s"'>]+)`)
+)
+
+// reportContentProblems reports the raw HTML omitted and the images without
+// alt text in the rendered content, as errors in strict mode.
+func (cp *pageContentOutput) reportContentProblems(content []byte) {
+	s := cp.p.s
+	strict := s.Strict
+
+	logf := func(isErr bool, format string, args ...any) {
+		if isErr {
+			s.LogDistinct.Errorf(format, args...)
+		} else {
+			s.LogDistinct.Infof(format, args...)
+		}
+	}
+
+	if bytes.Contains(content, rawHTMLOmittedMarker) {
+		logf(strict.RawHTMLOmitted, "Raw HTML omitted in %q, set markup.goldmark.renderer.unsafe to render it.", cp.p.pathOrTitle())
+	}
+
+	for _, img := range imgRe.FindAll(content, -1) {
+		if !imgAltRe.Match(img) {
+			logf(strict.MissingAltText, "Image without alt text in %q: %s", cp.p.pathOrTitle(), img)
+		}
+	}
+}
+
 func (p *pageContentOutput) setWordCounts(isCJKLanguage bool) {
 	if isCJKLanguage {
 		p.wordCount = 0
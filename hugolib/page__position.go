@@ -73,3 +73,14 @@ func (p pagePositionInSection) NextInSection() page.Page {
 func (p pagePositionInSection) PrevInSection() page.Page {
 	return p.prev()
 }
+
+// NextInCollection returns the next page relative to this in pages.
+// This is defined on pageState, as sections can also be part of a collection.
+func (p *pageState) NextInCollection(pages page.Pages) page.Page {
+	return pages.Next(p)
+}
+
+// PrevInCollection returns the previous page relative to this in pages.
+func (p *pageState) PrevInCollection(pages page.Pages) page.Page {
+	return pages.Prev(p)
+}
@@ -60,6 +60,18 @@ func (pt pageTree) IsAncestor(other any) (bool, error) {
 	return strings.HasPrefix(ref2.key, ref1.key+cmBranchSeparator), nil
 }
 
+func (pt pageTree) Breadcrumbs() page.Breadcrumbs {
+	var ancestors page.Pages
+	for p := pt.Parent(); !types.IsNil(p); p = p.Parent() {
+		ancestors = append(page.Pages{p}, ancestors...)
+		if p.IsHome() {
+			break
+		}
+	}
+
+	return page.NewBreadcrumbs(pt.p.s.Info.breadcrumbs, ancestors, pt.p)
+}
+
 func (pt pageTree) CurrentSection() page.Page {
 	p := pt.p
 
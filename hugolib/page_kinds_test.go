@@ -0,0 +1,94 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+)
+
+func TestCustomKinds(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+[kinds.author]
+layouts = ["person"]
+permalink = "/people/:slug/"
+[kinds.changelog]
+list = "never"
+outputs = ["HTML", "JSON"]
+-- content/authors/_index.md --
+---
+title: "Authors"
+---
+-- content/authors/jane.md --
+---
+title: "Jane"
+kind: author
+slug: jane-doe
+---
+-- content/authors/john.md --
+---
+title: "John"
+kind: author
+---
+-- content/posts/p1.md --
+---
+title: "P1"
+kind: notconfigured
+---
+-- content/changelog/_index.md --
+---
+title: "Changelog"
+kind: changelog
+---
+-- content/changelog/v1.md --
+---
+title: "v1"
+---
+-- layouts/index.html --
+RegularPages: {{ range .Site.RegularPages }}{{ .Title }}|{{ end }}
+Authors: {{ range where .Site.Pages "Kind" "author" }}{{ .Title }}:{{ .RelPermalink }}|{{ end }}
+Changelog: {{ with .Site.GetPage "/changelog" }}{{ .Kind }}:{{ .IsSection }}:{{ .RelPermalink }}{{ end }}
+-- layouts/_default/single.html --
+Single: {{ .Title }}|{{ .Kind }}|{{ .Params.kind }}
+-- layouts/_default/list.html --
+List: {{ .Title }}|{{ .Kind }}|Pages: {{ range .Pages }}{{ .Title }}:{{ .Kind }}|{{ end }}RegularPages: {{ range .RegularPages }}{{ .Title }}|{{ end }}
+-- layouts/_default/person.html --
+Person: {{ .Title }}|{{ .Kind }}|IsPage: {{ .IsPage }}|Section: {{ .Section }}|Prev: {{ with .Prev }}{{ .Title }}{{ end }}|Next: {{ with .Next }}{{ .Title }}{{ end }}
+-- layouts/_default/changelog.json --
+{"title": {{ .Title | jsonify }}, "kind": {{ .Kind | jsonify }}, "pages": {{ len .Pages }}}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html",
+		"RegularPages: Jane|John|P1|v1|",
+		"Authors: Jane:/people/jane-doe/|John:/people/john/|",
+		"Changelog: changelog:true:/changelog/",
+	)
+	b.AssertFileContent("public/people/jane-doe/index.html", "Person: Jane|author|IsPage: true|Section: authors|Prev: John|")
+	b.AssertFileContent("public/people/john/index.html", "Person: John|author|IsPage: true|Section: authors|Prev: P1|Next: Jane")
+	b.AssertFileContent("public/authors/index.html", "List: Authors|section|Pages: Jane:author|John:author|RegularPages: Jane|John|")
+	b.AssertFileContent("public/posts/p1/index.html", "Single: P1|page|notconfigured")
+	b.AssertFileContent("public/changelog/index.html", "List: Changelog|changelog|Pages: v1:page|RegularPages: v1|")
+	b.AssertFileContent("public/changelog/index.json", `{"title": "Changelog", "kind": "changelog", "pages": 1}`)
+}
@@ -1857,6 +1857,50 @@ Store: bar|
 	`)
 }
 
+func TestRenderScratch(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+[outputs]
+page = ["HTML", "JSON"]
+-- content/p1.md --
+---
+title: "p1"
+---
+-- layouts/_default/single.html --
+{{ partial "counts.html" . }}
+-- layouts/_default/single.json --
+{{ partial "counts.html" . }}
+-- layouts/_default/list.html --
+List.
+-- layouts/partials/counts.html --
+{{ $render := .RenderScratch.Incr "n" }}{{ $render = .RenderScratch.Incr "n" }}
+{{ $page := .Scratch.Incr "n" }}
+{{ $store := .Store.Incr "n" }}
+Render: {{ $render }}|Page: {{ $page }}|Store: {{ $store }}|
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			Running:     true,
+		},
+	).Build()
+
+	// The Page's Scratch is shared by the output formats, the RenderScratch is not.
+	b.AssertFileContent("public/p1/index.html", "Render: 2|Page: 1|Store: 1|")
+	b.AssertFileContent("public/p1/index.json", "Render: 2|Page: 2|Store: 2|")
+
+	b.EditFiles("layouts/partials/counts.html", strings.Replace(b.FileContent("layouts/partials/counts.html"), "Render:", "Render again:", 1))
+	b.Build()
+
+	b.AssertFileContent("public/p1/index.html", "Render again: 2|Page: 1|Store: 3|")
+	b.AssertFileContent("public/p1/index.json", "Render again: 2|Page: 2|Store: 4|")
+}
+
 func TestPageParam(t *testing.T) {
 	t.Parallel()
 
@@ -2001,3 +2045,229 @@ Page1: {{ $p1.Path }}
 
 	b.AssertFileContent("public/index.html", "Lang: no", filepath.FromSlash("Page1: a/B/C/Page1.md"))
 }
+
+func TestRenderShortcodeAndFragment(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "section", "RSS", "sitemap", "robotsTXT"]
+[outputs]
+home = ["HTML", "JSON"]
+-- content/p1.md --
+---
+title: "P1"
+---
+
+Intro.
+
+{{< card "First" >}}
+
+## Install
+
+Install with [Hugo](https://gohugo.io).
+
+### Requirements
+
+{{% note %}}**Go** is needed.{{% /note %}}
+
+## Usage
+
+{{< card "Second" >}}
+-- layouts/shortcodes/card.html --
+<div class="card">{{ .Get 0 }}</div>
+-- layouts/shortcodes/card.json --
+{"card": {{ .Get 0 | jsonify }}}
+-- layouts/shortcodes/note.html --
+Note: {{ .Inner }}
+-- layouts/_default/_markup/render-link.html --
+<a href="{{ .Destination }}" class="hooked">{{ .Text }}</a>
+-- layouts/_default/single.html --
+{{ .Content }}
+-- layouts/index.html --
+{{ $p := site.GetPage "p1" }}
+Card0: {{ $p.RenderShortcode "card" }}|
+Card1: {{ $p.RenderShortcode "card" 1 }}|
+Card2: {{ $p.RenderShortcode "card" 2 }}|
+Note: {{ $p.RenderShortcode "note" }}|
+Install: {{ $p.RenderFragment "install" }}|
+Usage: {{ $p.RenderFragment "usage" }}|
+None: {{ $p.RenderFragment "none" }}|
+-- layouts/index.json --
+{{ $p := site.GetPage "p1" }}
+Card0: {{ $p.RenderShortcode "card" }}|
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html",
+		`Card0: <div class="card">First</div>|`,
+		`Card1: <div class="card">Second</div>|`,
+		`Card2: |`,
+		`Note: <p>Note: <strong>Go</strong> is needed.</p>|`,
+		`Install: <h2 id="install">Install</h2>
+<p>Install with <a href="https://gohugo.io" class="hooked">Hugo</a>.</p>
+<h3 id="requirements">Requirements</h3>
+<p>Note: <strong>Go</strong> is needed.</p>|`,
+		`Usage: <h2 id="usage">Usage</h2>
+<div class="card">Second</div>|`,
+		`None: |`,
+	)
+
+	b.AssertFileContent("public/index.json", `Card0: {"card": "First"}|`)
+}
+
+func TestHeadingFragment(t *testing.T) {
+	c := qt.New(t)
+
+	content := `<!-- <h2 id="usage">In a comment</h2> -->
+<h2 class="a" id='install'>Install</h2>
+<p>Run <code>&lt;h2 id="x"&gt;</code>.</p>
+<header><h3 id="requirements">Requirements</h3></header>
+<script>document.write("<h1>In a script</h1>")</script>
+<h2 id="usage">Usage</h2>
+<p>Use it.</p>
+<h4 id=config>Config</h4>
+<h1 id="more">More</h1>`
+
+	c.Assert(headingFragment(content, "install"), qt.Equals, `<h2 class="a" id='install'>Install</h2>
+<p>Run <code>&lt;h2 id="x"&gt;</code>.</p>
+<header><h3 id="requirements">Requirements</h3></header>
+<script>document.write("<h1>In a script</h1>")</script>
+`)
+	c.Assert(headingFragment(content, "requirements"), qt.Equals, `<h3 id="requirements">Requirements</h3></header>
+<script>document.write("<h1>In a script</h1>")</script>
+`)
+	c.Assert(headingFragment(content, "usage"), qt.Equals, `<h2 id="usage">Usage</h2>
+<p>Use it.</p>
+<h4 id=config>Config</h4>
+`)
+	c.Assert(headingFragment(content, "config"), qt.Equals, "<h4 id=config>Config</h4>\n")
+	c.Assert(headingFragment(content, "more"), qt.Equals, `<h1 id="more">More</h1>`)
+	c.Assert(headingFragment(content, "x"), qt.Equals, "")
+}
+
+func TestPageContentBlocks(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+-- content/p1.md --
+---
+title: P1
+---
+Main **content**.
+
+<!--more-->
+
+More main {{< sc >}}.
+
+<!--block: sidebar-->
+Sidebar {{< sc >}}.
+
+<!-- block: appendix -->
+## Appendix
+
+Appendix text.
+
+<!--block: sidebar-->
+More sidebar.
+-- content/p2.html --
+---
+title: P2
+---
+<p>Main HTML.</p>
+<!--block: sidebar-->
+<p>Sidebar HTML.</p>
+-- layouts/shortcodes/sc.html --
+<span>SC</span>
+-- layouts/_default/single.html --
+Summary: {{ .Summary }}|
+Content: {{ .Content }}|
+Sidebar: {{ .ContentBlocks.sidebar }}|
+Appendix: {{ .ContentBlocks.appendix }}|
+None: {{ .ContentBlocks.none }}|
+Blocks: {{ len .ContentBlocks }}|
+-- layouts/_default/list.html --
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html",
+		"Summary: <p>Main <strong>content</strong>.</p>|",
+		"Content: <p>Main <strong>content</strong>.</p>\n<p>More main <span>SC</span>.</p>|",
+		"Sidebar: <p>Sidebar <span>SC</span>.</p>\n<p>More sidebar.</p>|",
+		"Appendix: <h2 id=\"appendix\">Appendix</h2>\n<p>Appendix text.</p>|",
+		"None: |",
+		"Blocks: 2|",
+	)
+
+	b.AssertFileContent("public/p2/index.html",
+		"Content: <p>Main HTML.</p>|",
+		"Sidebar: <p>Sidebar HTML.</p>|",
+	)
+}
+
+func TestPageRawFrontMatterAndSource(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404", "section", "home"]
+[cascade]
+color = "blue"
+-- content/p1.md --
++++
+title = "P1"
+myParam = 42
+date = 2022-03-01
++++
+Content **P1**.
+-- content/p2.md --
+No front matter.
+-- layouts/_default/single.html --
+Format: {{ .RawFrontMatter.Format }}|
+Map: {{ .RawFrontMatter.Map }}|
+MyParam: {{ .RawFrontMatter.Map.myParam }}|{{ printf "%T" .RawFrontMatter.Map.myParam }}|
+Date: {{ printf "%T" .RawFrontMatter.Map.date }}|
+Source: {{ .RawFrontMatter.Source }}|
+RawContentSource: {{ .RawContentSource }}|
+RawContent: {{ .RawContent }}|
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html",
+		"Format: toml|",
+		"Map: map[date:2022-03-01 myParam:42 title:P1]|",
+		"MyParam: 42|int64|",
+		"Date: toml.LocalDate|",
+		"Source: title = &#34;P1&#34;\nmyParam = 42\ndate = 2022-03-01\n|",
+		"RawContentSource: &#43;&#43;&#43;\ntitle = &#34;P1&#34;\nmyParam = 42\ndate = 2022-03-01\n&#43;&#43;&#43;\nContent **P1**.|",
+		"RawContent: Content **P1**.|",
+	)
+
+	b.AssertFileContent("public/p2/index.html",
+		"Format: |",
+		"Map: map[]|",
+		"RawContentSource: No front matter.|",
+	)
+}
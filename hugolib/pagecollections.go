@@ -92,7 +92,7 @@ func newPageCollections(m *pageMap) *PageCollections {
 	})
 
 	c.regularPages = newLazyPagesFactory(func() page.Pages {
-		return c.findPagesByKindIn(page.KindPage, c.pages.get())
+		return c.findRegularPagesIn(c.pages.get())
 	})
 
 	return c
@@ -329,10 +329,12 @@ func (c *PageCollections) getContentNode(context page.Page, isReflink bool, ref
 	return getByName(path.Base(name))
 }
 
-func (*PageCollections) findPagesByKindIn(kind string, inPages page.Pages) page.Pages {
+// findRegularPagesIn filters on IsPage and not on Kind, which
+// returns the custom kind for pages with one.
+func (*PageCollections) findRegularPagesIn(inPages page.Pages) page.Pages {
 	var pages page.Pages
 	for _, p := range inPages {
-		if p.Kind() == kind {
+		if p.IsPage() {
 			pages = append(pages, p)
 		}
 	}
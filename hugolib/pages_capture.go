@@ -25,7 +25,9 @@ import (
 
 	"github.com/gohugoio/hugo/parser/pageparser"
 
+	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/hugofs/files"
+	"github.com/gohugoio/hugo/hugofs/glob"
 
 	"github.com/gohugoio/hugo/source"
 
@@ -43,18 +45,62 @@ func newPagesCollector(
 	contentMap *pageMaps,
 	logger loggers.Logger,
 	contentTracker *contentChangeMap,
+	contentFilter *contentFilter,
 	proc pagesCollectorProcessorProvider, filenames ...string) *pagesCollector {
 	return &pagesCollector{
-		fs:         sp.SourceFs,
-		contentMap: contentMap,
-		proc:       proc,
-		sp:         sp,
-		logger:     logger,
-		filenames:  filenames,
-		tracker:    contentTracker,
+		fs:            sp.SourceFs,
+		contentMap:    contentMap,
+		proc:          proc,
+		sp:            sp,
+		logger:        logger,
+		filenames:     filenames,
+		tracker:       contentTracker,
+		contentFilter: contentFilter,
 	}
 }
 
+// contentFilter restricts the content files read to those matching
+// the include globs and none of the exclude globs.
+type contentFilter struct {
+	include *glob.FilenameFilter
+	exclude *glob.FilenameFilter
+}
+
+// newContentFilter creates a filter from the contentInclude and
+// contentExclude globs in cfg, nil if none is set.
+func newContentFilter(cfg config.Provider) (*contentFilter, error) {
+	include := cfg.GetStringSlice("contentInclude")
+	exclude := cfg.GetStringSlice("contentExclude")
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	var (
+		f   contentFilter
+		err error
+	)
+	if len(include) > 0 {
+		if f.include, err = glob.NewFilenameFilter(include, nil); err != nil {
+			return nil, fmt.Errorf("failed to decode contentInclude: %w", err)
+		}
+	}
+	if len(exclude) > 0 {
+		if f.exclude, err = glob.NewFilenameFilter(nil, exclude); err != nil {
+			return nil, fmt.Errorf("failed to decode contentExclude: %w", err)
+		}
+	}
+
+	return &f, nil
+}
+
+// Match returns whether filename, relative to the content dir, should be read.
+func (f *contentFilter) Match(filename string, isDir bool) bool {
+	if f == nil {
+		return true
+	}
+	return f.include.Match(filename, isDir) && f.exclude.Match(filename, isDir)
+}
+
 type contentDirKey struct {
 	dirname  string
 	filename string
@@ -91,6 +137,14 @@ type pagesCollector struct {
 	// Content files tracker used in partial builds.
 	tracker *contentChangeMap
 
+	// Restricts the content files read.
+	// Branch bundle headers, e.g. _index.md, are always read so the
+	// sections of the included content can be rendered.
+	contentFilter *contentFilter
+
+	// The content files created from data, see pagesFromData.
+	generated []hugofs.FileMetaInfo
+
 	proc pagesCollectorProcessorProvider
 }
 
@@ -169,6 +223,13 @@ func (c *pagesCollector) Collect() (collectErr error) {
 
 	if len(c.filenames) == 0 {
 		// Collect everything.
+		// The generated pages go first so the content files with the
+		// same path replace them.
+		for _, fi := range c.generated {
+			if err := c.proc.Process(fi); err != nil {
+				return err
+			}
+		}
 		collectErr = c.collectDir("", false, nil)
 	} else {
 		for _, pm := range c.contentMap.pmaps {
@@ -315,6 +376,24 @@ func (c *pagesCollector) cloneFileInfo(fi hugofs.FileMetaInfo) hugofs.FileMetaIn
 	return hugofs.NewFileMetaInfo(fi, hugofs.NewFileMeta())
 }
 
+// includeContent returns whether fim passes the content filter.
+// Files that are not content, e.g. page resources, follow their directory.
+func (c *pagesCollector) includeContent(fim hugofs.FileMetaInfo) bool {
+	if c.contentFilter == nil {
+		return true
+	}
+
+	meta := fim.Meta()
+	if fim.IsDir() {
+		return c.contentFilter.Match(meta.Path, true)
+	}
+	switch meta.Classifier {
+	case files.ContentClassFile, files.ContentClassBranch:
+		return true
+	}
+	return c.contentFilter.Match(meta.Path, false)
+}
+
 func (c *pagesCollector) collectDir(dirname string, partial bool, inFilter func(fim hugofs.FileMetaInfo) bool) error {
 	fi, err := c.fs.Stat(dirname)
 	if err != nil {
@@ -364,6 +443,10 @@ func (c *pagesCollector) collectDir(dirname string, partial bool, inFilter func(
 			return false
 		}
 
+		if !c.includeContent(fim) {
+			return false
+		}
+
 		if inFilter != nil {
 			return inFilter(fim)
 		}
@@ -56,7 +56,7 @@ func TestPagesCapture(t *testing.T) {
 	t.Run("Collect", func(t *testing.T) {
 		c := qt.New(t)
 		proc := &testPagesCollectorProcessor{}
-		coll := newPagesCollector(sourceSpec, nil, loggers.NewErrorLogger(), nil, proc)
+		coll := newPagesCollector(sourceSpec, nil, loggers.NewErrorLogger(), nil, nil, proc)
 		c.Assert(coll.Collect(), qt.IsNil)
 		c.Assert(len(proc.items), qt.Equals, 4)
 	})
@@ -77,3 +77,76 @@ func (proc *testPagesCollectorProcessor) Start(ctx context.Context) context.Cont
 }
 
 func (proc *testPagesCollectorProcessor) Wait() error { return proc.waitErr }
+
+func TestContentIncludeExclude(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["sitemap", "robotsTXT", "404", "rss"]
+contentInclude = ["blog/**"]
+contentExclude = ["**/draft-*.md"]
+-- content/_index.md --
+---
+title: "Home"
+---
+-- content/about.md --
+---
+title: "About"
+---
+-- content/blog/_index.md --
+---
+title: "Blog"
+menu: main
+---
+-- content/blog/p1.md --
+---
+title: "P1"
+tags: ["a"]
+---
+-- content/blog/draft-p2.md --
+---
+title: "Draft P2"
+tags: ["a"]
+---
+-- content/blog/p3/index.md --
+---
+title: "P3"
+tags: ["b"]
+---
+-- content/blog/p3/sunset.txt --
+Sunset.
+-- content/docs/_index.md --
+---
+title: "Docs"
+---
+-- content/docs/d1.md --
+---
+title: "D1"
+tags: ["a"]
+---
+-- layouts/_default/single.html --
+Single: {{ .Title }}|{{ range .Resources }}{{ .RelPermalink }}|{{ end }}
+-- layouts/_default/list.html --
+List: {{ .Title }}|{{ range .Pages }}{{ .Title }}|{{ end }}
+-- layouts/index.html --
+Home: {{ .Title }}|{{ range site.RegularPages }}{{ .Title }}|{{ end }}|Menu: {{ range site.Menus.main }}{{ .Name }}|{{ end }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html", "Home: Home|P1|P3||Menu: Blog|")
+	b.AssertFileContent("public/blog/index.html", "List: Blog|P1|P3|")
+	b.AssertFileContent("public/blog/p3/index.html", "Single: P3|/blog/p3/sunset.txt|")
+	b.AssertFileContent("public/tags/a/index.html", "List: a|P1|")
+	b.AssertDestinationExists("about/index.html", false)
+	b.AssertDestinationExists("docs/index.html", false)
+	b.AssertDestinationExists("docs/d1/index.html", false)
+	b.AssertDestinationExists("blog/draft-p2/index.html", false)
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugofs/files"
+	"github.com/gohugoio/hugo/parser"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/spf13/afero"
+)
+
+// pagesFromDataFiles creates the content files, kept in memory, of the pages
+// configured in pagesFromData, one per data row.
+func (h *HugoSites) pagesFromDataFiles() ([]hugofs.FileMetaInfo, error) {
+	if len(h.pagesFromData) == 0 {
+		return nil, nil
+	}
+
+	var (
+		data        = h.Data()
+		fs          = afero.NewMemMapFs()
+		contentDir  = h.PathSpec.AbsPathify(h.Cfg.GetString("contentDir"))
+		defaultLang = h.Cfg.GetString("defaultContentLanguage")
+		fis         []hugofs.FileMetaInfo
+	)
+
+	for _, c := range h.pagesFromData {
+		pages, err := c.Pages(c.Lookup(data), h.PathSpec.URLize)
+		if err != nil {
+			return nil, err
+		}
+
+		lang := c.Lang
+		if lang == "" {
+			lang = defaultLang
+		}
+
+		for _, p := range pages {
+			var b bytes.Buffer
+			if err := parser.InterfaceToFrontMatter(p.FrontMatter, metadecoders.JSON, &b); err != nil {
+				return nil, fmt.Errorf("pagesFromData: failed to create %q: %w", p.Path, err)
+			}
+			b.WriteString("\n")
+			b.WriteString(p.Content)
+
+			// The same path may be used in more than one language.
+			memFilename := path.Join(lang, p.Path)
+			if err := helpers.WriteToDisk(memFilename, &b, fs); err != nil {
+				return nil, err
+			}
+			fi, err := fs.Stat(memFilename)
+			if err != nil {
+				return nil, err
+			}
+
+			name := path.Base(p.Path)
+			meta := &hugofs.FileMeta{
+				Name:                       name,
+				Filename:                   filepath.Join(contentDir, filepath.FromSlash(p.Path)),
+				Path:                       filepath.FromSlash(p.Path),
+				Lang:                       lang,
+				TranslationBaseName:        strings.TrimSuffix(name, path.Ext(name)),
+				TranslationBaseNameWithExt: name,
+				Classifier:                 files.ContentClassContent,
+				Fs:                         fs,
+				OpenFunc: func() (afero.File, error) {
+					return fs.Open(memFilename)
+				},
+			}
+
+			fis = append(fis, hugofs.NewFileMetaInfo(fi, meta))
+		}
+	}
+
+	return fis, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+)
+
+func TestPagesFromData(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["RSS", "sitemap", "robotsTXT", "404"]
+[[pagesFromData]]
+data = "catalog.books"
+name = "isbn"
+permalink = "/books/:year/:slug/"
+content = "description"
+[pagesFromData.params]
+type = "book"
+rating = 3
+[[pagesFromData]]
+data = "authors"
+section = "people/authors"
+-- data/catalog/books.yaml --
+- title: The Hobbit
+  isbn: "978-0547928227"
+  year: 1937
+  rating: 5
+  tags: [fantasy]
+  description: "A **hobbit** goes on an adventure."
+- title: Dune
+  isbn: "978-0441172719"
+  year: 1965
+  tags: [scifi]
+-- data/authors.toml --
+[tolkien]
+title = "J. R. R. Tolkien"
+[herbert]
+title = "Frank Herbert"
+slug = "frank"
+-- content/books/_index.md --
+---
+title: Books
+---
+-- content/books/978-0441172719.md --
+---
+title: Dune from content
+---
+-- layouts/index.html --
+Home.
+-- layouts/_default/list.html --
+{{ .Title }}|{{ range .Pages }}{{ .Title }}:{{ .RelPermalink }}|{{ end }}
+-- layouts/_default/single.html --
+{{ .Title }}|{{ .Type }}|{{ .Params.rating }}|{{ .Params.isbn }}|{{ .Content }}
+-- layouts/book/single.html --
+Book: {{ .Title }}|{{ .Params.year }}|{{ .Params.rating }}|{{ .Params.tags }}|{{ .Content }}
+-- layouts/_default/term.html --
+{{ .Title }}|{{ range .Pages }}{{ .Title }}|{{ end }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/books/1937/978-0547928227/index.html", "Book: The Hobbit|1937|5|[fantasy]|<p>A <strong>hobbit</strong> goes on an adventure.</p>")
+	b.AssertFileContent("public/books/978-0441172719/index.html", "Dune from content|books|||")
+	b.AssertFileContent("public/books/index.html", "Books|")
+	b.AssertFileContent("public/tags/fantasy/index.html", "fantasy|The Hobbit|")
+	b.AssertFileContent("public/people/authors/j.-r.-r.-tolkien/index.html", "J. R. R. Tolkien|people|")
+	b.AssertFileContent("public/people/authors/frank/index.html", "Frank Herbert|people|")
+}
@@ -169,7 +169,13 @@ func (p *sitePagesProcessor) copyFile(fim hugofs.FileMetaInfo) error {
 		fs = s.PublishFsStatic
 	}
 
-	return s.publish(&s.PathSpec.ProcessingStats.Files, target, f, fs)
+	if err := s.publish(&s.PathSpec.ProcessingStats.Files, target, f, fs); err != nil {
+		return err
+	}
+
+	s.h.manifest.add(fs, buildManifestEntry{Path: target, Kind: manifestKindFile, Source: s.h.manifestSource(meta.Filename)})
+
+	return nil
 }
 
 func (p *sitePagesProcessor) doProcess(item any) error {
@@ -22,6 +22,7 @@ import (
 
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/langs"
+	"github.com/gohugoio/hugo/langs/slug"
 	"github.com/gohugoio/hugo/modules"
 
 	"github.com/gohugoio/hugo/hugofs"
@@ -61,6 +62,9 @@ type Paths struct {
 	UglyURLs           bool
 	CanonifyURLs       bool
 
+	// The language specific slug pipeline, nil if not configured.
+	Slugs *slug.Slugger
+
 	Language              *langs.Language
 	Languages             langs.Languages
 	LanguagesDefaultFirst langs.Languages
@@ -138,6 +142,15 @@ func New(fs *hugofs.Fs, cfg config.Provider) (*Paths, error) {
 		absResourcesDir = FilePathSeparator
 	}
 
+	slugsConfig, err := slug.DecodeConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	slugs, err := slug.New(slugsConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	var multihostTargetBasePaths []string
 	if languages.IsMultihost() {
 		for _, l := range languages {
@@ -154,6 +167,7 @@ func New(fs *hugofs.Fs, cfg config.Provider) (*Paths, error) {
 		RemovePathAccents:  cfg.GetBool("removePathAccents"),
 		UglyURLs:           cfg.GetBool("uglyURLs"),
 		CanonifyURLs:       cfg.GetBool("canonifyURLs"),
+		Slugs:              slugs,
 
 		ThemesDir:  cfg.GetString("themesDir"),
 		WorkingDir: workingDir,
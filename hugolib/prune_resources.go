@@ -13,7 +13,19 @@
 
 package hugolib
 
+import "github.com/gohugoio/hugo/cache/filecache"
+
 // GC requires a build first and must run on it's own. It is not thread safe.
 func (h *HugoSites) GC() (int, error) {
 	return h.Deps.FileCaches.Prune()
 }
+
+// WriteFileCacheStats writes the file cache hits and misses of the last build
+// to the project's cache dir, see hugo cache stats.
+func (h *HugoSites) WriteFileCacheStats() error {
+	filename, err := filecache.BuildStatsFilename(h.Fs.Source, h.Cfg)
+	if err != nil {
+		return err
+	}
+	return filecache.WriteBuildStats(h.Fs.Source, filename, h.Deps.FileCaches.BuildStats())
+}
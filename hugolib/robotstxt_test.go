@@ -14,8 +14,11 @@
 package hugolib
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	qt "github.com/frankban/quicktest"
 	"github.com/gohugoio/hugo/config"
 )
 
@@ -39,3 +42,85 @@ func TestRobotsTXTOutput(t *testing.T) {
 
 	b.AssertFileContent("public/robots.txt", "User-agent: Googlebot")
 }
+
+func TestRobotsTXTDefault(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+enableRobotsTXT = true
+-- layouts/index.html --
+Home.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.Assert(b.FileContent("public/robots.txt"), qt.Equals, "User-agent: *")
+}
+
+func TestRobotsTXTFromConfig(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+enableRobotsTXT = true
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+[robots]
+sitemap = true
+disallowEnvironments = ["staging"]
+disallowBaseURLs = ["*.netlify.app"]
+[[robots.rules]]
+userAgent = "*"
+disallow = ["/private/"]
+crawlDelay = 10
+[[robots.rules]]
+userAgent = ["BadBot", "WorseBot"]
+disallow = "/"
+environments = ["production"]
+-- layouts/index.html --
+Home.
+`
+
+	build := func(env, baseURL string) *IntegrationTestBuilder {
+		return NewIntegrationTestBuilder(
+			IntegrationTestConfig{
+				T:           t,
+				TxtarString: strings.Replace(files, `baseURL = "https://example.org/"`, fmt.Sprintf("baseURL = %q\nenvironment = %q", baseURL, env), 1),
+			},
+		).Build()
+	}
+
+	b := build("production", "https://example.org/")
+	b.AssertFileContentExact("public/robots.txt", `User-agent: *
+Crawl-delay: 10
+Disallow: /private/
+
+User-agent: BadBot
+User-agent: WorseBot
+Disallow: /
+
+Sitemap: https://example.org/en/sitemap.xml
+Sitemap: https://example.org/nn/sitemap.xml
+`)
+
+	b = build("development", "https://example.org/")
+	b.Assert(b.FileContent("public/robots.txt"), qt.Not(qt.Contains), "BadBot")
+
+	b = build("staging", "https://example.org/")
+	b.AssertFileContentExact("public/robots.txt", "User-agent: *\nDisallow: /\n")
+
+	b = build("production", "https://deploy-preview-42--mysite.netlify.app/")
+	b.AssertFileContentExact("public/robots.txt", "User-agent: *\nDisallow: /\n")
+}
@@ -80,6 +80,7 @@ import (
 	"github.com/gohugoio/hugo/resources/page/pagemeta"
 	"github.com/gohugoio/hugo/source"
 	"github.com/gohugoio/hugo/tpl"
+	"github.com/gohugoio/hugo/transform/outputprofile"
 
 	"github.com/spf13/afero"
 	"github.com/spf13/cast"
@@ -122,6 +123,12 @@ type Site struct {
 
 	disabledKinds map[string]bool
 
+	// The error pages rendered in the last build.
+	errorPages []errorPage
+
+	// The redirects from the aliases of the page resources.
+	resourceRedirects []config.RedirectRule
+
 	// Output formats defined in site config per Page Kind, or some defaults
 	// if not set.
 	// Output formats defined in Page front matter will override these.
@@ -133,6 +140,9 @@ type Site struct {
 	outputFormatsConfig output.Formats
 	mediaTypesConfig    media.Types
 
+	// The output profiles by lower case name, see output.Format.Profile.
+	outputProfiles map[string]outputprofile.Config
+
 	siteConfigConfig SiteConfig
 
 	// How to handle page front matter.
@@ -168,6 +178,12 @@ type Site struct {
 	// The last modification date of this site.
 	lastmod time.Time
 
+	// Reset on rebuilds.
+	scratch *maps.Scratch
+
+	// Survives rebuilds.
+	store *maps.Scratch
+
 	// Lazily loaded site dependencies
 	init *siteInit
 }
@@ -193,7 +209,15 @@ func (t taxonomiesConfig) Values() []viewName {
 
 type siteConfigHolder struct {
 	sitemap          config.Sitemap
+	robots           config.Robots
+	redirects        config.Redirects
+	errorPages       config.ErrorPages
+	enrichment       config.Enrichment
+	llms             page.LLMs
+	kinds            page.KindsConfig
 	taxonomiesConfig taxonomiesConfig
+	taxonomyOptions  map[string]taxonomyOptions
+	layoutRules      output.LayoutRulesConfig
 	timeout          time.Duration
 	hasCJKLanguage   bool
 	enableEmoji      bool
@@ -205,6 +229,13 @@ type siteInit struct {
 	prevNextInSection *lazy.Init
 	menus             *lazy.Init
 	taxonomies        *lazy.Init
+	glossary          *lazy.Init
+	bibliography      *lazy.Init
+	rawHTML           *lazy.Init
+	navTrees          *lazy.Init
+
+	// The front matter schemas from config and archetypes.
+	frontMatterSchemas *lazy.Init
 }
 
 func (init *siteInit) Reset() {
@@ -212,6 +243,11 @@ func (init *siteInit) Reset() {
 	init.prevNextInSection.Reset()
 	init.menus.Reset()
 	init.taxonomies.Reset()
+	init.glossary.Reset()
+	init.bibliography.Reset()
+	init.rawHTML.Reset()
+	init.navTrees.Reset()
+	init.frontMatterSchemas.Reset()
 }
 
 func (s *Site) initInit(init *lazy.Init, pctx pageContext) bool {
@@ -292,9 +328,8 @@ func (s *Site) prepareInits() {
 			treeRef.m.collectPages(pageMapQuery{Prefix: treeRef.key + cmBranchSeparator}, func(c *contentNode) {
 				pas = append(pas, c.p)
 			})
-			page.SortByDefault(pas)
 
-			setNextPrev(pas)
+			setNextPrev(s.inSectionOrder(sect).Sort(pas))
 		}
 
 		// The root section only goes one level down.
@@ -304,9 +339,8 @@ func (s *Site) prepareInits() {
 		treeRef.m.collectPages(pageMapQuery{Prefix: treeRef.key + cmBranchSeparator}, func(c *contentNode) {
 			pas = append(pas, c.p)
 		})
-		page.SortByDefault(pas)
 
-		setNextPrev(pas)
+		setNextPrev(s.inSectionOrder(s.home).Sort(pas))
 
 		return nil, nil
 	})
@@ -320,6 +354,37 @@ func (s *Site) prepareInits() {
 		err := s.pageMap.assembleTaxonomies()
 		return nil, err
 	})
+
+	s.init.glossary = init.Branch(func() (any, error) {
+		return s.newGlossary()
+	})
+
+	s.init.bibliography = init.Branch(func() (any, error) {
+		return s.newBibliographyEntries()
+	})
+
+	s.init.rawHTML = init.Branch(func() (any, error) {
+		return s.newRawHTMLRules()
+	})
+
+	s.init.navTrees = init.Branch(func() (any, error) {
+		return &navTrees{trees: make(map[string]*page.NavTree)}, nil
+	})
+
+	s.init.frontMatterSchemas = init.Branch(func() (any, error) {
+		return s.newFrontMatterSchemas()
+	})
+}
+
+// inSectionOrder returns the order used for NextInSection and PrevInSection
+// in section p, as set in its inSectionOrder front matter, possibly cascaded.
+func (s *Site) inSectionOrder(p page.Page) page.PagesOrder {
+	o, err := page.DecodePagesOrder(p.Params()["insectionorder"])
+	if err != nil {
+		s.Log.Errorf("%q: %s", p.SectionsPath(), err)
+		return page.PagesOrder{}
+	}
+	return o
 }
 
 type siteRenderingContext struct {
@@ -344,8 +409,12 @@ func (s *Site) initRenderFormats() {
 		return false
 	})
 
-	// Add the per kind configured output formats
-	for _, kind := range allKindsInPages {
+	// Add the per kind configured output formats, including the custom kinds.
+	kinds := append([]string(nil), allKindsInPages...)
+	for kind := range s.siteCfg.kinds {
+		kinds = append(kinds, kind)
+	}
+	for _, kind := range kinds {
 		if siteFormats, found := s.outputFormats[kind]; found {
 			for _, f := range siteFormats {
 				if !formatSet[f.Name] {
@@ -386,6 +455,7 @@ func (s *Site) reset() *Site {
 		outputFormats:       s.outputFormats,
 		rc:                  s.rc,
 		outputFormatsConfig: s.outputFormatsConfig,
+		outputProfiles:      s.outputProfiles,
 		frontmatterHandler:  s.frontmatterHandler,
 		mediaTypesConfig:    s.mediaTypesConfig,
 		language:            s.language,
@@ -396,6 +466,8 @@ func (s *Site) reset() *Site {
 		init:                s.init,
 		PageCollections:     s.PageCollections,
 		siteCfg:             s.siteCfg,
+		scratch:             maps.NewScratch(),
+		store:               s.store,
 	}
 }
 
@@ -409,7 +481,8 @@ func newSite(cfg deps.DepsCfg) (*Site, error) {
 	}
 
 	ignoreErrors := cast.ToStringSlice(cfg.Language.Get("ignoreErrors"))
-	ignorableLogger := loggers.NewIgnorableLogger(cfg.Logger, ignoreErrors...)
+	ignoreWarnings := cast.ToStringSlice(cfg.Language.Get("ignoreWarnings"))
+	ignorableLogger := loggers.NewIgnorableLogger(cfg.Logger, ignoreErrors, ignoreWarnings)
 
 	disabledKinds := make(map[string]bool)
 	for _, disabled := range cast.ToStringSlice(cfg.Language.Get("disableKinds")) {
@@ -460,6 +533,11 @@ But this also means that your site configuration may not do what you expect. If
 		return nil, err
 	}
 
+	outputProfiles, err := outputprofile.DecodeConfig(cfg.Language.Get("outputProfiles"))
+	if err != nil {
+		return nil, err
+	}
+
 	rssDisabled := disabledKinds[kindRSS]
 	if rssDisabled {
 		// Legacy
@@ -502,6 +580,11 @@ But this also means that your site configuration may not do what you expect. If
 
 	taxonomies := cfg.Language.GetStringMapString("taxonomies")
 
+	taxonomyOpts, err := decodeTaxonomyOptions(cfg.Language.GetStringMap("taxonomyOptions"), taxonomies)
+	if err != nil {
+		return nil, err
+	}
+
 	var relatedContentConfig related.Config
 
 	if cfg.Language.IsSet("related") {
@@ -532,9 +615,63 @@ But this also means that your site configuration may not do what you expect. If
 		}
 	}
 
+	robots, err := config.DecodeRobots(cfg.Language.GetStringMap("robots"))
+	if err != nil {
+		return nil, err
+	}
+
+	redirects, err := config.DecodeRedirects(cfg.Language.GetStringMap("redirects"))
+	if err != nil {
+		return nil, err
+	}
+
+	errorPages, err := config.DecodeErrorPages(cfg.Language.GetStringMap("errorPages"))
+	if err != nil {
+		return nil, err
+	}
+
+	enrichment, err := config.DecodeEnrichment(cfg.Language.GetStringMap("enrichment"))
+	if err != nil {
+		return nil, err
+	}
+
+	llms, err := page.DecodeLLMs(cfg.Language.GetStringMap("llms"))
+	if err != nil {
+		return nil, err
+	}
+
+	kinds, err := page.DecodeKindsConfig(cfg.Language.Get("kinds"))
+	if err != nil {
+		return nil, err
+	}
+
+	layoutRules, err := output.DecodeLayoutRulesConfig(cfg.Cfg.Get("layoutLookup"))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, kc := range kinds {
+		if len(kc.Outputs) == 0 {
+			continue
+		}
+		formats, err := siteOutputFormatsConfig.GetByNames(kc.Outputs...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve output formats for kind %q: %w", name, err)
+		}
+		outputFormats[name] = formats
+	}
+
 	siteConfig := siteConfigHolder{
 		sitemap:          config.DecodeSitemap(config.Sitemap{Priority: -1, Filename: "sitemap.xml"}, cfg.Language.GetStringMap("sitemap")),
+		robots:           robots,
+		redirects:        redirects,
+		errorPages:       errorPages,
+		enrichment:       enrichment,
+		llms:             llms,
+		kinds:            kinds,
 		taxonomiesConfig: taxonomies,
+		taxonomyOptions:  taxonomyOpts,
+		layoutRules:      layoutRules,
 		timeout:          timeout,
 		hasCJKLanguage:   cfg.Language.GetBool("hasCJKLanguage"),
 		enableEmoji:      cfg.Language.Cfg.GetBool("enableEmoji"),
@@ -548,6 +685,18 @@ But this also means that your site configuration may not do what you expect. If
 			return nil, fmt.Errorf("failed to decode cascade config: %s", err)
 		}
 
+		// Computed params are only allowed in the cascade in site config.
+		for m, v := range cascade {
+			vv := make(maps.Params, len(v))
+			for k, val := range v {
+				vv[k] = val
+			}
+			if err := pagemeta.ResolveComputedParams(vv); err != nil {
+				return nil, fmt.Errorf("failed to decode cascade config: %w", err)
+			}
+			cascade[m] = vv
+		}
+
 		siteBucket = &pagesMapBucket{
 			cascade: cascade,
 		}
@@ -561,6 +710,7 @@ But this also means that your site configuration may not do what you expect. If
 
 		outputFormats:       outputFormats,
 		outputFormatsConfig: siteOutputFormatsConfig,
+		outputProfiles:      outputProfiles,
 		mediaTypesConfig:    siteMediaTypesConfig,
 
 		siteCfg: siteConfig,
@@ -571,6 +721,9 @@ But this also means that your site configuration may not do what you expect. If
 
 		frontmatterHandler: frontMatterHandler,
 		relatedDocsHandler: page.NewRelatedDocsHandler(relatedContentConfig),
+
+		scratch: maps.NewScratch(),
+		store:   maps.NewScratch(),
 	}
 
 	s.prepareInits()
@@ -673,6 +826,10 @@ type SiteInfo struct {
 	language                       *langs.Language
 	defaultContentLanguageInSubdir bool
 	sectionPagesMenu               string
+	breadcrumbs                    page.BreadcrumbsConfig
+	gallery                        page.GalleryConfig
+	numbering                      page.NumberingConfig
+	canonical                      page.CanonicalConfig
 }
 
 func (s *SiteInfo) Pages() page.Pages {
@@ -683,6 +840,18 @@ func (s *SiteInfo) RegularPages() page.Pages {
 	return s.s.RegularPages()
 }
 
+// LLMsPages returns the regular pages to list in llms.txt, see the llms
+// section in site config.
+func (s *SiteInfo) LLMsPages() page.Pages {
+	var pages page.Pages
+	for _, p := range s.s.RegularPages() {
+		if !p.(*pageState).m.llmsExcluded {
+			pages = append(pages, p)
+		}
+	}
+	return pages
+}
+
 func (s *SiteInfo) AllPages() page.Pages {
 	return s.s.AllPages()
 }
@@ -732,6 +901,18 @@ func (s *SiteInfo) Hugo() hugo.Info {
 	return s.hugoInfo
 }
 
+// Scratch returns a Scratch shared by all pages in this site.
+// It gets reset on server rebuilds.
+func (s *SiteInfo) Scratch() *maps.Scratch {
+	return s.s.scratch
+}
+
+// Store returns a Scratch shared by all pages in this site.
+// Unlike Scratch, it survives server rebuilds.
+func (s *SiteInfo) Store() *maps.Scratch {
+	return s.s.store
+}
+
 // Sites is a convenience method to get all the Hugo sites/languages configured.
 func (s *SiteInfo) Sites() page.Sites {
 	return s.s.h.siteInfos()
@@ -812,6 +993,11 @@ type siteRefLinker struct {
 
 	errorLogger *log.Logger
 	notFoundURL string
+
+	// Whether to check that the fragments in refs match an anchor in the
+	// target page once rendered.
+	checkFragments bool
+	fragments      *refFragments
 }
 
 func newSiteRefLinker(cfg config.Provider, s *Site) (siteRefLinker, error) {
@@ -822,7 +1008,13 @@ func newSiteRefLinker(cfg config.Provider, s *Site) (siteRefLinker, error) {
 	if strings.EqualFold(errLevel, "warning") {
 		logger = s.Log.Warn()
 	}
-	return siteRefLinker{s: s, errorLogger: logger, notFoundURL: notFoundURL}, nil
+	return siteRefLinker{
+		s:              s,
+		errorLogger:    logger,
+		notFoundURL:    notFoundURL,
+		checkFragments: cfg.GetBool("refLinksCheckFragments"),
+		fragments:      newRefFragments(),
+	}, nil
 }
 
 func (s siteRefLinker) logNotFound(ref, what string, p page.Page, position text.Position) {
@@ -874,6 +1066,15 @@ func (s *siteRefLinker) refLink(ref string, source any, relative bool, outputFor
 			return s.notFoundURL, nil
 		}
 
+		// Track the pages linked to when in server mode.
+		var from any = p
+		if pw, ok := from.(pageWrapper); ok {
+			from = pw.page()
+		}
+		if pc, ok := from.(pageContext); ok {
+			pc.addDependency(target)
+		}
+
 		var permalinker Permalinker = target
 
 		if outputFormat != "" {
@@ -894,8 +1095,32 @@ func (s *siteRefLinker) refLink(ref string, source any, relative bool, outputFor
 	}
 
 	if refURL.Fragment != "" {
-		_ = target
-		link = link + "#" + refURL.Fragment
+		fragment := refURL.Fragment
+
+		targetState, _ := target.(*pageState)
+		if targetState == nil && refURL.Path == "" {
+			targetState, _ = p.(*pageState)
+		}
+		if targetState != nil {
+			// The fragment may be the text of a heading.
+			fragment = s.fragments.resolve(targetState, fragment)
+			if s.checkFragments {
+				var pos text.Position
+				if pp, ok := source.(text.Positioner); ok {
+					pos = pp.Position()
+				}
+				s.fragments.add(refFragment{
+					ref:          ref,
+					fragment:     fragment,
+					source:       p,
+					target:       targetState,
+					outputFormat: outputFormat,
+					pos:          pos,
+				})
+			}
+		}
+
+		link = link + "#" + fragment
 
 		if pctx, ok := target.(pageContext); ok {
 			if refURL.Path != "" {
@@ -925,6 +1150,16 @@ func (s *Site) multilingual() *Multilingual {
 type whatChanged struct {
 	source bool
 	files  map[string]bool
+
+	// The identities of the changed files, set when rebuilding for file events.
+	identities identity.Identities
+
+	// Whether the affected pages are all the pages depending on the changes,
+	// e.g. when only templates changed. If not, the other pages may be stale.
+	dependenciesComplete bool
+
+	// The pages depending on the changes, see HugoSites.pagesDependingOn.
+	affected map[*pageState]bool
 }
 
 // RegisterMediaTypes will register the Site's media types in the mime
@@ -1103,8 +1338,9 @@ func (s *Site) processPartial(config *BuildCfg, init func(config *BuildCfg) erro
 	}
 
 	changed := &whatChanged{
-		source: len(sourceChanged) > 0,
-		files:  sourceFilesChanged,
+		source:     len(sourceChanged) > 0,
+		files:      sourceFilesChanged,
+		identities: changeIdentities,
 	}
 
 	config.whatChanged = changed
@@ -1182,6 +1418,17 @@ func (s *Site) processPartial(config *BuildCfg, init func(config *BuildCfg) erro
 		sourceFilesChanged[ev.Name] = true
 	}
 
+	if len(config.RecentlyVisited) > 0 {
+		// Fast render mode. Find the pages depending on the changes
+		// before their state is reset.
+		changed.affected, changed.dependenciesComplete = h.pagesDependingOn(changeIdentities)
+		if config.ErrRecovery || tmplAdded || !changeIdentitiesAreLayouts(changeIdentities) {
+			// Changes in content, data, i18n and assets may affect any page,
+			// and a new template may change the template lookups.
+			changed.dependenciesComplete = false
+		}
+	}
+
 	if config.ErrRecovery || tmplAdded || dataChanged {
 		h.resetPageState()
 	} else {
@@ -1237,6 +1484,9 @@ func (s *Site) render(ctx *siteRenderContext) (err error) {
 			if err = s.renderAliases(); err != nil {
 				return
 			}
+			if err = s.renderResourceAliases(); err != nil {
+				return
+			}
 		}
 	}
 
@@ -1255,7 +1505,7 @@ func (s *Site) render(ctx *siteRenderContext) (err error) {
 			}
 		}
 
-		if err = s.render404(); err != nil {
+		if err = s.renderErrorPages(); err != nil {
 			return
 		}
 	}
@@ -1298,6 +1548,18 @@ func (s *SiteInfo) SitemapAbsURL() string {
 	return p
 }
 
+// RobotsTXT creates the robots.txt content from the robots section in
+// site config, for the current environment.
+func (s *SiteInfo) RobotsTXT() string {
+	var sitemaps []string
+	for _, ss := range s.s.h.Sites {
+		if ss.isEnabled(kindSitemap) {
+			sitemaps = append(sitemaps, ss.Info.SitemapAbsURL())
+		}
+	}
+	return s.s.siteCfg.robots.Text(s.s.Cfg.GetString("environment"), string(s.BaseURL()), sitemaps)
+}
+
 func (s *Site) initializeSiteInfo() error {
 	var (
 		lang      = s.language
@@ -1369,6 +1631,29 @@ func (s *Site) initializeSiteInfo() error {
 		deps = append(deps, depFromMod(m))
 	}
 
+	breadcrumbs, err := page.DecodeBreadcrumbsConfig(lang.Get("breadcrumbs"))
+	if err != nil {
+		return err
+	}
+
+	gallery, err := page.DecodeGalleryConfig(page.DefaultGalleryConfig, lang.Get("gallery"))
+	if err != nil {
+		return err
+	}
+
+	numbering, err := page.DecodeNumberingConfig(lang.Get("numbering"))
+	if err != nil {
+		return err
+	}
+
+	canonical, err := page.DecodeCanonicalConfig(lang.Get("canonical"))
+	if err != nil {
+		return err
+	}
+	if canonical.XDefault == "" {
+		canonical.XDefault = defaultContentLanguage
+	}
+
 	s.Info = &SiteInfo{
 		title:                          lang.GetString("title"),
 		Author:                         lang.GetStringMap("author"),
@@ -1380,6 +1665,10 @@ func (s *Site) initializeSiteInfo() error {
 		Languages:                      languages,
 		defaultContentLanguageInSubdir: defaultContentInSubDir,
 		sectionPagesMenu:               lang.GetString("sectionPagesMenu"),
+		breadcrumbs:                    breadcrumbs,
+		gallery:                        gallery,
+		numbering:                      numbering,
+		canonical:                      canonical,
 		BuildDrafts:                    s.Cfg.GetBool("buildDrafts"),
 		canonifyURLs:                   s.Cfg.GetBool("canonifyURLs"),
 		relativeURLs:                   s.Cfg.GetBool("relativeURLs"),
@@ -1399,6 +1688,15 @@ func (s *Site) initializeSiteInfo() error {
 	return nil
 }
 
+func changeIdentitiesAreLayouts(ids identity.Identities) bool {
+	for id := range ids {
+		if pid, ok := id.(identity.PathIdentity); !ok || pid.Type != files.ComponentFolderLayouts {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *Site) eventToIdentity(e fsnotify.Event) (identity.PathIdentity, bool) {
 	for _, fs := range s.BaseFs.SourceFilesystems.FileSystems() {
 		if p := fs.Path(e.Name); p != "" {
@@ -1411,9 +1709,20 @@ func (s *Site) eventToIdentity(e fsnotify.Event) (identity.PathIdentity, bool) {
 func (s *Site) readAndProcessContent(buildConfig BuildCfg, filenames ...string) error {
 	sourceSpec := source.NewSourceSpec(s.PathSpec, buildConfig.ContentInclusionFilter, s.BaseFs.Content.Fs)
 
+	contentFilter, err := newContentFilter(s.Cfg)
+	if err != nil {
+		return err
+	}
+
 	proc := newPagesProcessor(s.h, sourceSpec)
 
-	c := newPagesCollector(sourceSpec, s.h.getContentMaps(), s.Log, s.h.ContentChanges, proc, filenames...)
+	c := newPagesCollector(sourceSpec, s.h.getContentMaps(), s.Log, s.h.ContentChanges, contentFilter, proc, filenames...)
+
+	if len(filenames) == 0 {
+		if c.generated, err = s.h.pagesFromDataFiles(); err != nil {
+			return err
+		}
+	}
 
 	if err := c.Collect(); err != nil {
 		return err
@@ -1499,6 +1808,16 @@ func (s *Site) assembleMenus() {
 		}
 	}
 
+	// Add menu entries generated from pages and data.
+	for name, menu := range s.getMenusFromGenerators() {
+		for _, me := range menu {
+			if _, ok := flat[twoD{name, me.KeyName()}]; ok {
+				continue
+			}
+			flat[twoD{name, me.KeyName()}] = me
+		}
+	}
+
 	sectionPagesMenu := s.Info.sectionPagesMenu
 
 	if sectionPagesMenu != "" {
@@ -1611,6 +1930,7 @@ func (s *Site) getTaxonomyKey(key string) string {
 func (s *Site) resetBuildState(sourceChanged bool) {
 	s.relatedDocsHandler = s.relatedDocsHandler.Clone()
 	s.init.Reset()
+	s.scratch = maps.NewScratch()
 
 	if sourceChanged {
 		s.pageMap.contentMap.pageReverseIndex.Reset()
@@ -1723,7 +2043,15 @@ func (s *Site) renderAndWriteXML(statCounter *uint64, name string, targetPath st
 		AbsURLPath:   s.absURLPath(targetPath),
 	}
 
-	return s.publisher.Publish(pd)
+	if err := s.publisher.Publish(pd); err != nil {
+		return err
+	}
+
+	if p, ok := d.(*pageState); ok {
+		s.addPageToManifest(manifestKindPage, targetPath, p, templ)
+	}
+
+	return nil
 }
 
 func (s *Site) renderAndWritePage(statCounter *uint64, name string, targetPath string, p *pageState, templ tpl.Template) error {
@@ -1742,14 +2070,31 @@ func (s *Site) renderAndWritePage(statCounter *uint64, name string, targetPath s
 		return nil
 	}
 
+	s.resolveNumberRefsInBuffer(renderBuffer)
+
+	if of.Name == output.PDFFormat.Name {
+		pdf, err := s.renderPDF(p, renderBuffer.Bytes())
+		if err != nil {
+			return fmt.Errorf("failed to render PDF for %q: %w", p.pathOrTitle(), err)
+		}
+		renderBuffer.Reset()
+		renderBuffer.Write(pdf)
+	}
+
 	isHTML := of.IsHTML
 	isRSS := of.Name == "RSS"
 
+	profile, err := s.newOutputProfileTransformer(p, targetPath)
+	if err != nil {
+		return err
+	}
+
 	pd := publisher.Descriptor{
 		Src:          renderBuffer,
 		TargetPath:   targetPath,
 		StatCounter:  statCounter,
 		OutputFormat: p.outputFormat(),
+		Profile:      profile,
 	}
 
 	if isRSS {
@@ -1774,7 +2119,13 @@ func (s *Site) renderAndWritePage(statCounter *uint64, name string, targetPath s
 
 	}
 
-	return s.publisher.Publish(pd)
+	if err := s.publisher.Publish(pd); err != nil {
+		return err
+	}
+
+	s.addPageToManifest(manifestKindPage, targetPath, p, templ)
+
+	return nil
 }
 
 var infoOnMissingLayout = map[string]bool{
@@ -1803,6 +2154,14 @@ func (hr hookRendererTemplate) RenderCodeblock(w hugio.FlexiWriter, ctx hooks.Co
 	return hr.templateHandler.Execute(hr.templ, w, ctx)
 }
 
+func (hr hookRendererTemplate) RenderCitation(w io.Writer, ctx hooks.CitationContext) error {
+	return hr.templateHandler.Execute(hr.templ, w, ctx)
+}
+
+func (hr hookRendererTemplate) RenderBibliography(w io.Writer, ctx hooks.BibliographyContext) error {
+	return hr.templateHandler.Execute(hr.templ, w, ctx)
+}
+
 func (hr hookRendererTemplate) ResolvePosition(ctx any) text.Position {
 	return hr.resolvePosition(ctx)
 }
@@ -1836,7 +2195,13 @@ func (s *Site) lookupTemplate(layouts ...string) (tpl.Template, bool) {
 func (s *Site) publish(statCounter *uint64, path string, r io.Reader, fs afero.Fs) (err error) {
 	s.PathSpec.ProcessingStats.Incr(statCounter)
 
-	return helpers.WriteToDisk(filepath.Clean(path), r, fs)
+	if err := helpers.WriteToDisk(filepath.Clean(path), r, fs); err != nil {
+		return err
+	}
+
+	s.h.manifest.add(fs, buildManifestEntry{Path: filepath.Clean(path), Kind: manifestKindFile})
+
+	return nil
 }
 
 func (s *Site) kindFromFileInfoOrSections(fi *fileInfo, sections []string) string {
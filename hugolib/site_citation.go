@@ -0,0 +1,118 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/gohugoio/hugo/markup/citation"
+	"github.com/gohugoio/hugo/resources/resource"
+)
+
+// newBibliographyEntries decodes the entries in the configured data file,
+// nil if citations are not enabled.
+func (s *Site) newBibliographyEntries() ([]citation.Entry, error) {
+	cfg := s.ContentSpec.Converters.GetMarkupConfig().Goldmark.Extensions.Citation
+	if !cfg.Enable || cfg.Data == "" {
+		return nil, nil
+	}
+
+	var v any = s.h.Data()
+	for _, key := range strings.FieldsFunc(cfg.Data, func(r rune) bool { return r == '.' || r == '/' }) {
+		m, ok := v.(map[string]any)
+		if !ok {
+			v = nil
+			break
+		}
+		v = m[key]
+	}
+
+	entries, err := citation.DecodeCSLJSON(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode bibliography data %q: %w", cfg.Data, err)
+	}
+
+	return entries, nil
+}
+
+// bibliography returns the entries the citations in the content of p are
+// resolved against, the site's and those in the page resources, nil if
+// citations are not enabled.
+func (p *pageState) bibliography() *citation.Bibliography {
+	cfg := p.s.ContentSpec.Converters.GetMarkupConfig().Goldmark.Extensions.Citation
+	if !cfg.Enable {
+		return nil
+	}
+
+	v, err := p.s.init.bibliography.Do()
+	if err != nil {
+		p.s.h.FatalError(p.wrapError(err))
+		return nil
+	}
+	entries := [][]citation.Entry{v.([]citation.Entry)}
+
+	if cfg.Resources != "" {
+		for _, r := range p.Resources().Match(cfg.Resources) {
+			e, err := readBibliographyResource(r)
+			if err != nil {
+				p.s.h.FatalError(p.wrapError(err))
+				return nil
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	b, err := citation.New(cfg.Style, entries...)
+	if err != nil {
+		p.s.h.FatalError(p.wrapError(err))
+		return nil
+	}
+
+	return b
+}
+
+func readBibliographyResource(r resource.Resource) ([]citation.Entry, error) {
+	rr, ok := r.(resource.ReadSeekCloserResource)
+	if !ok {
+		return nil, fmt.Errorf("bibliography %q: not readable", r.Name())
+	}
+	rc, err := rr.ReadSeekCloser()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []citation.Entry
+	if strings.EqualFold(path.Ext(r.Name()), ".bib") {
+		entries, err = citation.ParseBibTeX(string(b))
+	} else {
+		var v any
+		if err = json.Unmarshal(b, &v); err == nil {
+			entries, err = citation.DecodeCSLJSON(v)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bibliography %q: %w", r.Name(), err)
+	}
+
+	return entries, nil
+}
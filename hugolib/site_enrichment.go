@@ -0,0 +1,155 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/collections"
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/helpers"
+)
+
+// enrichmentRequest is the page sent to the enrichment source.
+type enrichmentRequest struct {
+	// The path of the content file relative to the content dir.
+	Path        string         `json:"path"`
+	Lang        string         `json:"lang"`
+	FrontMatter map[string]any `json:"frontMatter"`
+	Content     string         `json:"content"`
+}
+
+// enrichParams adds the params computed by the configured enrichment
+// source for p to its params, keeping the values already set. It runs after
+// the front matter is processed, so the enrichment can not change e.g. the
+// draft state, dates or URLs of the page.
+// The results are cached by the source and the content file.
+func (s *Site) enrichParams(p *pageState, frontmatter map[string]any) error {
+	cfg := s.siteCfg.enrichment
+	if cfg.IsZero() || p.File().IsZero() || p.source.parsed == nil {
+		return nil
+	}
+	if frontmatter == nil {
+		frontmatter = make(map[string]any)
+	}
+
+	src := p.source.parsed.Input()
+	content := src
+	if p.source.posMainContent > 0 {
+		content = src[p.source.posMainContent:]
+	}
+	id := helpers.MD5String(strings.Join([]string{cfg.Key(), s.Lang(), p.File().Path(), string(src)}, "|"))
+
+	var params map[string]any
+
+	_, b, err := s.FileCaches.EnrichmentCache().GetOrCreateBytes(id, func() ([]byte, error) {
+		req, err := json.Marshal(enrichmentRequest{
+			Path:        p.File().Path(),
+			Lang:        s.Lang(),
+			FrontMatter: frontmatter,
+			Content:     string(content),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var b []byte
+		if cfg.URL != "" {
+			b, err = s.fetchEnrichment(cfg, req)
+		} else {
+			b, err = s.runEnrichment(cfg, req)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Validate before it gets cached.
+		if err := json.Unmarshal(b, &params); err != nil {
+			return nil, fmt.Errorf("the result is not a JSON object: %w", err)
+		}
+
+		return b, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enrich %q: %w", p.File().Filename(), err)
+	}
+
+	if params == nil {
+		// From the cache.
+		if err := json.Unmarshal(b, &params); err != nil {
+			return fmt.Errorf("failed to enrich %q: %w", p.File().Filename(), err)
+		}
+	}
+
+	maps.PrepareParams(params)
+	for k, v := range params {
+		if _, found := p.m.params[k]; !found {
+			p.m.params[k] = v
+		}
+	}
+
+	return nil
+}
+
+func (s *Site) runEnrichment(cfg config.Enrichment, req []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	args := collections.StringSliceToInterfaceSlice(cfg.Args)
+	args = append(args,
+		hexec.WithDir(s.WorkingDir),
+		hexec.WithStdin(bytes.NewReader(req)),
+		hexec.WithStdout(&stdout),
+		hexec.WithStderr(&stderr),
+	)
+	cmd, err := s.ExecHelper.New(cfg.Command, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", cfg.Command, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func (s *Site) fetchEnrichment(cfg config.Enrichment, req []byte) ([]byte, error) {
+	if err := s.ExecHelper.Sec().CheckAllowedHTTPURL(cfg.URL); err != nil {
+		return nil, err
+	}
+	if err := s.ExecHelper.Sec().CheckAllowedHTTPMethod("POST"); err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: s.siteCfg.timeout}
+	res, err := client.Post(cfg.URL, "application/json", bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("%s: %s", cfg.URL, res.Status)
+	}
+
+	return b, nil
+}
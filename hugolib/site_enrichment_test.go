@@ -0,0 +1,115 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestEnrichment(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var req enrichmentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		words := len(strings.Fields(req.Content))
+		fmt.Fprintf(w, `{"color": "#%s", "tags": ["t-%s"], "words": %d, "title": "Enriched", "draft": true, "url": "/enriched/"}`, req.Lang, req.FrontMatter["title"], words)
+	}))
+	t.Cleanup(ts.Close)
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["RSS", "sitemap", "robotsTXT", "404", "taxonomy", "term"]
+[enrichment]
+url = "ENRICH_URL"
+-- content/p1.md --
+---
+title: P1
+---
+One two three.
+-- content/p2.md --
+---
+title: P2
+tags: ["mine"]
+---
+One.
+-- content/p3.md --
+---
+title: P3
+draft: true
+---
+-- content/p4.md --
+---
+title: P4
+date: 2099-01-01
+---
+-- layouts/index.html --
+Home.
+-- layouts/_default/single.html --
+{{ .Title }}|{{ .Params.color }}|{{ .Params.words }}|{{ .Params.tags }}|
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: strings.Replace(files, "ENRICH_URL", ts.URL, 1),
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html", "P1|#en|3|[t-P1]|")
+	b.AssertFileContent("public/p2/index.html", "P2|#en|1|[mine]|")
+	b.AssertDestinationExists("public/enriched/index.html", false)
+	b.Assert(atomic.LoadInt32(&requests), qt.Equals, int32(2))
+}
+
+func TestEnrichmentNotAllowed(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+[enrichment]
+command = "enrich"
+-- content/p1.md --
+---
+title: P1
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+`
+
+	_, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	qt.Assert(t, err, qt.IsNotNil)
+	qt.Assert(t, err.Error(), qt.Contains, `access denied: "enrich" is not whitelisted in policy "security.exec.allow"`)
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/paths"
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/gohugoio/hugo/resources/page/pagemeta"
+	"github.com/spf13/afero"
+)
+
+// archetypeSchemaSuffix is the suffix of the files in /archetypes declaring
+// the front matter schema for the content type in their base name,
+// e.g. archetypes/posts.schema.toml.
+const archetypeSchemaSuffix = ".schema"
+
+// newFrontMatterSchemas returns the front matter schemas declared next to
+// the archetypes with those in frontmatter.schemas merged in. For the
+// fields defined in both, the site config wins.
+func (s *Site) newFrontMatterSchemas() (pagemeta.FrontMatterSchemas, error) {
+	schemas := make(pagemeta.FrontMatterSchemas)
+
+	fis, err := afero.ReadDir(s.BaseFs.Archetypes.Fs, "")
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read archetypes: %w", err)
+	}
+
+	for _, fi := range fis {
+		if fi.IsDir() {
+			continue
+		}
+		name := fi.Name()
+		ext := paths.Ext(name)
+		format := metadecoders.FormatFromString(ext)
+		if format == "" {
+			continue
+		}
+		typ := strings.TrimSuffix(name, ext)
+		if !strings.HasSuffix(typ, archetypeSchemaSuffix) {
+			continue
+		}
+		typ = strings.ToLower(strings.TrimSuffix(typ, archetypeSchemaSuffix))
+
+		b, err := afero.ReadFile(s.BaseFs.Archetypes.Fs, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read front matter schema %q: %w", name, err)
+		}
+		m, err := metadecoders.Default.UnmarshalToMap(b, format)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode front matter schema %q: %w", name, err)
+		}
+		schema, err := pagemeta.DecodeFrontMatterSchema(typ, m)
+		if err != nil {
+			return nil, err
+		}
+		schemas[typ] = schema
+	}
+
+	return s.frontmatterHandler.Schemas().Merge(schemas), nil
+}
+
+// frontMatterSchema returns the front matter schema for the given content type, if any.
+func (s *Site) frontMatterSchema(contentType string) (pagemeta.FrontMatterSchema, bool, error) {
+	v, err := s.init.frontMatterSchemas.Do()
+	if err != nil {
+		return nil, false, err
+	}
+	schema, found := v.(pagemeta.FrontMatterSchemas)[strings.ToLower(contentType)]
+	return schema, found, nil
+}
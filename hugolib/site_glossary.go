@@ -0,0 +1,94 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gohugoio/hugo/markup/glossary"
+	"github.com/spf13/cast"
+)
+
+// newGlossary creates the glossary from the configured data file and
+// content section, nil if the glossary is not enabled.
+func (s *Site) newGlossary() (*glossary.Glossary, error) {
+	cfg := s.ContentSpec.Converters.GetMarkupConfig().Goldmark.Extensions.Glossary
+	if !cfg.Enable {
+		return nil, nil
+	}
+
+	var terms []glossary.Term
+
+	if cfg.Data != "" {
+		var v any = s.h.Data()
+		for _, key := range strings.FieldsFunc(cfg.Data, func(r rune) bool { return r == '.' || r == '/' }) {
+			m, ok := v.(map[string]any)
+			if !ok {
+				v = nil
+				break
+			}
+			v = m[key]
+		}
+		dataTerms, err := glossary.DecodeTerms(v, cfg.CaseSensitive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode glossary data %q: %w", cfg.Data, err)
+		}
+		terms = append(terms, dataTerms...)
+	}
+
+	if cfg.Section != "" {
+		for _, p := range s.RegularPages() {
+			if p.Section() != cfg.Section {
+				continue
+			}
+			t := glossary.Term{
+				Term:          p.Title(),
+				Title:         p.Description(),
+				URL:           p.RelPermalink(),
+				CaseSensitive: cfg.CaseSensitive,
+			}
+			params := p.Params()
+			if v, found := params["term"]; found {
+				t.Term = cast.ToString(v)
+			}
+			if v, found := params["casesensitive"]; found {
+				t.CaseSensitive = cast.ToBool(v)
+			}
+			terms = append(terms, t)
+		}
+	}
+
+	return glossary.New(terms)
+}
+
+// glossary returns the glossary terms to mark up in the content of p, nil if
+// none or if disabled for p with glossary = false in front matter.
+func (p *pageState) glossary() *glossary.Glossary {
+	if !p.s.ContentSpec.Converters.GetMarkupConfig().Goldmark.Extensions.Glossary.Enable {
+		return nil
+	}
+	if v, found := p.m.params["glossary"]; found && !cast.ToBool(v) {
+		return nil
+	}
+
+	v, err := p.s.init.glossary.Do()
+	if err != nil {
+		p.s.h.FatalError(p.wrapError(err))
+		return nil
+	}
+
+	// Don't link the terms defined on this page to itself.
+	return v.(*glossary.Glossary).Exclude(p.RelPermalink())
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+)
+
+func TestLLMsOutputFormats(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+title = "My Site"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+[params]
+description = "A site about things."
+[outputs]
+home = ["HTML", "llms"]
+page = ["HTML", "markdown"]
+[[llms.include]]
+path = "/docs/**"
+[[llms.include]]
+path = "/about.md"
+[[llms.exclude]]
+path = "/docs/internal/**"
+-- content/about.md --
+---
+title: "About"
+---
+About us.
+-- content/docs/_index.md --
+---
+title: "Documentation"
+---
+-- content/docs/intro.md --
+---
+title: "Introduction"
+description: "Start here."
+---
+Read **this** {{< sc >}}.
+-- content/docs/internal/secret.md --
+---
+title: "Secret"
+---
+Secret.
+-- content/docs/hidden.md --
+---
+title: "Hidden"
+llms: false
+---
+Hidden.
+-- content/blog/post.md --
+---
+title: "Post"
+llms: true
+---
+Post.
+-- content/news/item.md --
+---
+title: "Item"
+---
+Item.
+-- layouts/shortcodes/sc.html --
+SC
+-- layouts/index.html --
+Home.
+-- layouts/_default/single.html --
+Single: {{ .Title }}|{{ with .OutputFormats.Get "markdown" }}{{ .RelPermalink }}{{ end }}|
+-- layouts/_default/list.html --
+List.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/llms.txt", `# My Site
+
+> A site about things.
+
+## Pages
+
+- [About](https://example.org/about/index.md)
+
+## Blog
+
+- [Post](https://example.org/blog/post/index.md)
+
+## Documentation
+
+- [Introduction](https://example.org/docs/intro/index.md): Start here.
+`)
+
+	b.AssertFileContent("public/docs/intro/index.md", `# Introduction
+
+> Start here.
+
+Read **this** {{< sc >}}.`)
+
+	b.AssertFileContent("public/docs/intro/index.html", "Single: Introduction|/docs/intro/index.md|")
+	b.AssertFileContent("public/docs/hidden/index.html", "Single: Hidden||")
+	b.AssertFileContent("public/news/item/index.html", "Single: Item||")
+	b.AssertDestinationExists("public/docs/hidden/index.md", false)
+	b.AssertDestinationExists("public/docs/internal/secret/index.md", false)
+	b.AssertDestinationExists("public/news/item/index.md", false)
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gohugoio/hugo/resources/page"
+)
+
+// navTrees caches the navigation trees of a site by section.
+type navTrees struct {
+	mu    sync.Mutex
+	trees map[string]*page.NavTree
+}
+
+// NavTree returns the navigation tree of section, e.g. "docs", or the whole
+// site for "/". The tree is created once per build.
+func (s *SiteInfo) NavTree(section string) (*page.NavTree, error) {
+	v, err := s.s.init.navTrees.Do()
+	if err != nil {
+		return nil, err
+	}
+	cache := v.(*navTrees)
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if t, found := cache.trees[section]; found {
+		return t, nil
+	}
+
+	p, err := s.s.getPageNew(nil, section)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("NavTree: section %q not found", section)
+	}
+	if !p.IsNode() {
+		return nil, fmt.Errorf("NavTree: %q is not a section", section)
+	}
+
+	t := page.NewNavTree(p, func(sect page.Page) page.Pages {
+		return sect.Pages()
+	})
+	cache.trees[section] = t
+
+	return t, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+)
+
+func TestSiteNavTree(t *testing.T) {
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+-- content/docs/_index.md --
+---
+title: "Docs"
+---
+-- content/docs/intro.md --
+---
+title: "Intro"
+weight: 1
+---
+-- content/docs/guide/_index.md --
+---
+title: "Guide"
+weight: 2
+---
+-- content/docs/guide/install.md --
+---
+title: "Install"
+weight: 2
+---
+-- content/docs/guide/config.md --
+---
+title: "Config"
+weight: 1
+---
+-- content/docs/reference/_index.md --
+---
+title: "Reference"
+weight: 3
+---
+-- content/docs/reference/cli.md --
+---
+title: "CLI"
+---
+-- layouts/_default/single.html --
+{{ $tree := .Site.NavTree "docs" }}
+Root: {{ $tree.Root.Page.Title }}|{{ len $tree.Nodes }}
+All: {{ range $tree.Nodes }}{{ .Level }}:{{ .Page.Title }}|{{ end }}
+Visible: {{ range $tree.Visible . }}{{ .Level }}:{{ .Page.Title }}{{ if .IsActive $ }}*{{ end }}{{ if .IsCollapsed $ }}+{{ end }}|{{ end }}
+Trail: {{ range $tree.ActiveTrail . }}{{ .Page.Title }}|{{ end }}
+{{ with $tree.Find . }}Parent: {{ .Parent.Page.Title }}|{{ .Parent.InActiveTrail $ }}{{ end }}
+-- layouts/_default/list.html --
+{{ .Title }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/docs/guide/install/index.html",
+		"Root: Docs|6",
+		"All: 1:Intro|1:Guide|2:Config|2:Install|1:Reference|2:CLI|",
+		"Visible: 1:Intro|1:Guide|2:Config|2:Install*|1:Reference+|",
+		"Trail: Docs|Guide|Install|",
+		"Parent: Guide|true",
+	)
+
+	b.AssertFileContent("public/docs/intro/index.html",
+		"Visible: 1:Intro*|1:Guide+|1:Reference+|",
+		"Parent: Docs|true",
+	)
+}
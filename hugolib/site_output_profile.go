@@ -0,0 +1,101 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/transform"
+	"github.com/gohugoio/hugo/transform/outputprofile"
+	"github.com/spf13/afero"
+)
+
+// newOutputProfileTransformer creates the transformer for the profile of the
+// output format p is rendered in to targetPath, nil if it has none.
+func (s *Site) newOutputProfileTransformer(p *pageState, targetPath string) (transform.Transformer, error) {
+	name := p.outputFormat().Profile
+	if name == "" {
+		return nil, nil
+	}
+
+	cfg, found := s.outputProfiles[strings.ToLower(name)]
+	if !found {
+		return nil, fmt.Errorf("output profile %q not found", name)
+	}
+
+	source := p.pathOrTitle()
+
+	return outputprofile.New(cfg, outputprofile.Options{
+		ReadFile: func(ref string) ([]byte, bool) {
+			return s.readSiteFile(p, ref)
+		},
+		Report: func(v outputprofile.Violation) {
+			location := strings.TrimPrefix(filepath.ToSlash(targetPath), "/")
+			if v.Line > 0 {
+				location += fmt.Sprintf(":%d", v.Line)
+			}
+			msg := fmt.Sprintf("%s: output profile %q: %s: %s", source, name, location, v)
+			if cfg.Strict {
+				s.Log.Errorln(msg)
+			} else {
+				s.Log.Warnln(msg)
+			}
+		},
+	}), nil
+}
+
+// readSiteFile reads the file the site-local URL ref in p points to from the
+// published files, then from the static files.
+func (s *Site) readSiteFile(p *pageState, ref string) ([]byte, bool) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, false
+	}
+
+	baseURL := s.PathSpec.BaseURL.URL()
+	if u.Scheme != "" || u.Host != "" {
+		if u.Host != baseURL.Host {
+			return nil, false
+		}
+	}
+
+	filename := u.Path
+	if !strings.HasPrefix(filename, "/") {
+		filename = path.Join(path.Dir(p.RelPermalink()), filename)
+	}
+	if baseURL.Path != "" && baseURL.Path != "/" {
+		filename = strings.TrimPrefix(filename, strings.TrimSuffix(baseURL.Path, "/"))
+	}
+	filename = filepath.FromSlash(strings.TrimPrefix(path.Clean(filename), "/"))
+
+	var candidates []string
+	if s.h.IsMultihost() {
+		candidates = append(candidates, filepath.Join(s.Lang(), filename))
+	}
+	candidates = append(candidates, filename)
+
+	for _, fs := range []afero.Fs{s.BaseFs.PublishFs, s.BaseFs.StaticFs(s.Lang())} {
+		for _, filename := range candidates {
+			if b, err := afero.ReadFile(fs, filename); err == nil {
+				return b, true
+			}
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,127 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestOutputProfile(t *testing.T) {
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+[outputs]
+page = ["html", "amp"]
+[outputFormats.AMP]
+profile = "amp"
+-- static/css/main.css --
+body { color: red; }
+-- content/p1/index.md --
+---
+title: "P1"
+---
+-- content/p1/dot.png --
+iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==
+-- layouts/_default/single.html --
+<html><head><link rel="stylesheet" href="/css/main.css"><script src="/js/app.js"></script></head><body>{{ .Title }}</body></html>
+-- layouts/_default/single.amp.html --
+<html><head>
+<link rel="stylesheet" href="/css/main.css">
+<script src="/js/app.js"></script>
+</head><body>{{ .Title }}
+{{ with .Resources.GetMatch "dot.png" }}<img src="{{ .RelPermalink }}" alt="Dot">{{ end }}
+</body></html>
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html", `<link rel="stylesheet" href="/css/main.css"><script src="/js/app.js"></script>`)
+	b.AssertFileContent("public/amp/p1/index.html",
+		"<style amp-custom>body { color: red; }</style></head>",
+		`<amp-img src="/amp/p1/dot.png" alt="Dot" width="1" height="1"></amp-img>`,
+	)
+	b.Assert(b.FileContent("public/amp/p1/index.html"), qt.Not(qt.Contains), "app.js")
+	b.AssertLogContains(`p1/index.md: output profile "amp": amp/p1/index.html:3: custom JavaScript removed: <script src="/js/app.js"></script> (script)`)
+}
+
+func TestOutputProfileStrict(t *testing.T) {
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+[outputs]
+page = ["html"]
+[outputFormats.HTML]
+profile = "email"
+[outputProfiles.email]
+strict = true
+-- content/p1.md --
+---
+title: "P1"
+---
+-- layouts/_default/single.html --
+<html><body><img src="/missing.png"></body></html>
+`
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.AssertLogContains(`image without width and height: <img src="/missing.png"> (image-dimensions)`)
+}
+
+func TestOutputProfileValidateHTML(t *testing.T) {
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+[outputs]
+page = ["html"]
+[outputFormats.HTML]
+profile = "validate"
+[markup.goldmark.renderer]
+unsafe = true
+-- content/p1.md --
+---
+title: "P1"
+---
+## Heading
+
+<div class="note">
+Not closed.
+-- layouts/_default/single.html --
+<html><body><h2 id="heading">Heading</h2>{{ .Content }}</body></html>
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html", `<div class="note">`)
+	b.AssertLogContains(`p1.md: output profile "validate": p1/index.html:1: duplicate id "heading", first used on line 1: <h2 id="heading"> (duplicate-id)`)
+	b.AssertLogContains(`p1.md: output profile "validate": p1/index.html:2: element <div> is not closed: <div class="note"> (unclosed-element)`)
+}
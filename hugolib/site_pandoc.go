@@ -0,0 +1,161 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/spf13/afero"
+	"github.com/spf13/cast"
+	"golang.org/x/text/language"
+)
+
+// pandocLuaFilters returns the filenames of the pandoc Lua filters to run on
+// the content of p: the ones in markup.pandoc.luaFilters followed by the ones
+// in the pandoc.luaFilters front matter.
+func (p *pageState) pandocLuaFilters() []string {
+	names := p.s.ContentSpec.Converters.GetMarkupConfig().Pandoc.LuaFilters
+
+	if v := p.Params().Get("pandoc", "luaFilters"); v != nil {
+		pageNames, err := cast.ToStringSliceE(v)
+		if err != nil {
+			p.s.h.FatalError(p.wrapError(fmt.Errorf("failed to decode pandoc.luaFilters in front matter: %w", err)))
+			return nil
+		}
+		names = append(names[:len(names):len(names)], pageNames...)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	filenames := make([]string, len(names))
+	for i, name := range names {
+		filename, err := p.s.resolvePandocLuaFilter(name)
+		if err != nil {
+			p.s.h.FatalError(p.wrapError(err))
+			return nil
+		}
+		filenames[i] = filename
+	}
+
+	return filenames
+}
+
+// resolvePandocLuaFilter returns the absolute filename of the Lua filter
+// name, relative to the project dir or to the assets of the project and its
+// modules.
+func (s *Site) resolvePandocLuaFilter(name string) (string, error) {
+	filename := filepath.FromSlash(name)
+	if filepath.IsAbs(filename) {
+		return filename, nil
+	}
+
+	if fi, err := s.Fs.WorkingDirReadOnly.Stat(filename); err == nil && !fi.IsDir() {
+		return filepath.Join(s.Cfg.GetString("workingDir"), filename), nil
+	}
+
+	if filename, found := statFilename(s.BaseFs.Assets.Fs, filename); found {
+		return filename, nil
+	}
+
+	return "", fmt.Errorf("pandoc Lua filter %q not found in the project or in the assets", name)
+}
+
+// pandocCSL returns the filename of the CSL style for the citations in p, set
+// in the csl front matter or in markup.pandoc.csl.
+func (p *pageState) pandocCSL() string {
+	name := p.s.ContentSpec.Converters.GetMarkupConfig().Pandoc.CSL
+	if v, found := p.Params()["csl"]; found {
+		var err error
+		if name, err = cast.ToStringE(v); err != nil {
+			p.s.h.FatalError(p.wrapError(fmt.Errorf("failed to decode csl in front matter: %w", err)))
+			return ""
+		}
+	}
+
+	if name == "" {
+		return ""
+	}
+
+	filename, err := p.resolvePandocCSL(name)
+	if err != nil {
+		p.s.h.FatalError(p.wrapError(err))
+		return ""
+	}
+
+	return filename
+}
+
+// resolvePandocCSL returns the absolute filename of the CSL style name,
+// relative to the page bundle of p or to the assets of the project and its
+// modules.
+func (p *pageState) resolvePandocCSL(name string) (string, error) {
+	filename := filepath.FromSlash(name)
+	if filepath.IsAbs(filename) {
+		return filename, nil
+	}
+
+	if p.File() != nil && p.BundleType() != "" {
+		if filename, found := statFilename(p.s.BaseFs.Content.Fs, filepath.Join(p.File().Dir(), filename)); found {
+			return filename, nil
+		}
+	}
+
+	if filename, found := statFilename(p.s.BaseFs.Assets.Fs, filename); found {
+		return filename, nil
+	}
+
+	return "", fmt.Errorf("pandoc CSL style %q not found in the page bundle or in the assets", name)
+}
+
+// pandocCitationLocale returns the locale of the citations in p, set in the
+// citation-locale front matter or in markup.pandoc.citationLocale.
+func (p *pageState) pandocCitationLocale() string {
+	locale := p.s.ContentSpec.Converters.GetMarkupConfig().Pandoc.CitationLocale
+	if v, found := p.Params()["citation-locale"]; found {
+		var err error
+		if locale, err = cast.ToStringE(v); err != nil {
+			p.s.h.FatalError(p.wrapError(fmt.Errorf("failed to decode citation-locale in front matter: %w", err)))
+			return ""
+		}
+	}
+
+	if locale == "" {
+		return ""
+	}
+
+	if _, err := language.Parse(locale); err != nil {
+		p.s.h.FatalError(p.wrapError(fmt.Errorf("invalid citation locale %q: %w", locale, err)))
+		return ""
+	}
+
+	return locale
+}
+
+// statFilename returns the real filename of filename in the composite
+// filesystem fs, and whether it was found.
+func statFilename(fs afero.Fs, filename string) (string, bool) {
+	fi, err := fs.Stat(filename)
+	if err != nil || fi.IsDir() {
+		return "", false
+	}
+	fim, ok := fi.(hugofs.FileMetaInfo)
+	if !ok {
+		return "", false
+	}
+	return fim.Meta().Filename, true
+}
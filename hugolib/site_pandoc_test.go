@@ -0,0 +1,217 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPandocLuaFilters(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+theme = "mytheme"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+[markup.pandoc]
+luaFilters = ["filters/site.lua", "lua/theme.lua"]
+-- filters/site.lua --
+-- filters/page.lua --
+-- themes/mytheme/assets/lua/theme.lua --
+-- content/p1.pdc --
+---
+title: P1
+pandoc:
+  luaFilters: ["filters/page.lua", "/abs/filter.lua"]
+---
+-- content/p2.pdc --
+---
+title: P2
+---
+-- content/p3.md --
+---
+title: P3
+pandoc:
+  luaFilters: ["filters/missing.lua"]
+---
+-- layouts/_default/single.html --
+{{ .Content }}
+-- layouts/index.html --
+Home.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	abs := func(names ...string) []string {
+		for i, name := range names {
+			names[i] = filepath.FromSlash(name)
+		}
+		return names
+	}
+
+	s := b.H.Sites[0]
+	b.Assert(s.getPage("p1").(*pageState).pandocLuaFilters(), qt.DeepEquals, abs("/filters/site.lua", "/themes/mytheme/assets/lua/theme.lua", "/filters/page.lua", "/abs/filter.lua"))
+	b.Assert(s.getPage("p2").(*pageState).pandocLuaFilters(), qt.DeepEquals, abs("/filters/site.lua", "/themes/mytheme/assets/lua/theme.lua"))
+
+	_, err := s.resolvePandocLuaFilter("filters/missing.lua")
+	b.Assert(err, qt.ErrorMatches, `pandoc Lua filter "filters/missing.lua" not found in the project or in the assets`)
+}
+
+func TestPandocLuaFiltersNotFound(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+-- content/p1.pdc --
+---
+title: P1
+pandoc:
+  luaFilters: ["filters/missing.lua"]
+---
+-- layouts/_default/single.html --
+{{ .Content }}
+-- layouts/index.html --
+Home.
+`
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.ErrorMatches, `(?s).*p1.pdc.*pandoc Lua filter "filters/missing.lua" not found in the project or in the assets.*`)
+}
+
+func TestPandocCSL(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+theme = "mytheme"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+[markup.pandoc]
+csl = "csl/site.csl"
+citationLocale = "en-GB"
+-- themes/mytheme/assets/csl/site.csl --
+-- assets/csl/apa.csl --
+-- content/p1/index.pdc --
+---
+title: P1
+csl: "style.csl"
+citation-locale: "de-DE"
+---
+-- content/p1/style.csl --
+-- content/p2.pdc --
+---
+title: P2
+csl: "csl/apa.csl"
+---
+-- content/p3.pdc --
+---
+title: P3
+---
+-- content/p4.pdc --
+---
+title: P4
+csl: "/abs/style.csl"
+citation-locale: ""
+---
+-- layouts/_default/single.html --
+{{ .Content }}
+-- layouts/index.html --
+Home.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	s := b.H.Sites[0]
+	for _, test := range []struct {
+		path   string
+		csl    string
+		locale string
+	}{
+		{"p1", "/content/p1/style.csl", "de-DE"},
+		{"p2", "/assets/csl/apa.csl", "en-GB"},
+		{"p3", "/themes/mytheme/assets/csl/site.csl", "en-GB"},
+		{"p4", "/abs/style.csl", ""},
+	} {
+		p := s.getPage(test.path).(*pageState)
+		b.Assert(p.pandocCSL(), qt.Equals, filepath.FromSlash(test.csl), qt.Commentf(test.path))
+		b.Assert(p.pandocCitationLocale(), qt.Equals, test.locale, qt.Commentf(test.path))
+	}
+
+	_, err := s.getPage("p2").(*pageState).resolvePandocCSL("style.csl")
+	b.Assert(err, qt.ErrorMatches, `pandoc CSL style "style.csl" not found in the page bundle or in the assets`)
+}
+
+func TestPandocCSLErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name        string
+		frontMatter string
+		expect      string
+	}{
+		{"Not found", `csl: "missing.csl"`, `(?s).*p1.pdc.*pandoc CSL style "missing.csl" not found in the page bundle or in the assets.*`},
+		{"Invalid locale", `citation-locale: "not a locale"`, `(?s).*p1.pdc.*invalid citation locale "not a locale".*`},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+-- content/p1.pdc --
+---
+title: P1
+` + test.frontMatter + `
+---
+-- layouts/_default/single.html --
+{{ .Content }}
+-- layouts/index.html --
+Home.
+`
+
+			b, err := NewIntegrationTestBuilder(
+				IntegrationTestConfig{
+					T:           t,
+					TxtarString: files,
+				},
+			).BuildE()
+
+			b.Assert(err, qt.ErrorMatches, test.expect)
+		})
+	}
+}
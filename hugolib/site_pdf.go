@@ -0,0 +1,189 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/collections"
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/mitchellh/mapstructure"
+)
+
+// defaultPDFConfig holds the default PDF configuration.
+var defaultPDFConfig = pdfConfig{
+	Renderer:     "wkhtmltopdf",
+	InlineAssets: true,
+}
+
+// pdfConfig configures how pages in the PDF output format are converted
+// from HTML.
+type pdfConfig struct {
+	// The command that converts the HTML to PDF. It must be allowed in
+	// security.exec.allow. Chromium and Chrome are run headless, any other
+	// command must, as wkhtmltopdf, read HTML on stdin and write PDF on stdout.
+	Renderer string
+
+	// Additional arguments to the renderer, e.g. ["--page-size", "A4"].
+	Args []string
+
+	// Whether to inline the site's stylesheets, scripts and images in the
+	// HTML, so the renderer does not need to fetch them.
+	InlineAssets bool
+}
+
+func decodePDFConfig(in any) (pdfConfig, error) {
+	c := defaultPDFConfig
+	if in == nil {
+		return c, nil
+	}
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode pdf config: %w", err)
+	}
+	if c.Renderer == "" || strings.ContainsAny(c.Renderer, `/\`) {
+		return c, fmt.Errorf("pdf.renderer must be the name of a command in PATH, got %q", c.Renderer)
+	}
+	return c, nil
+}
+
+func isChromiumRenderer(name string) bool {
+	name = strings.TrimSuffix(strings.ToLower(name), ".exe")
+	return strings.Contains(name, "chromium") || strings.Contains(name, "chrome")
+}
+
+// renderPDF converts the HTML rendered for p in the PDF output format to PDF.
+func (s *Site) renderPDF(p *pageState, html []byte) ([]byte, error) {
+	cfg, err := decodePDFConfig(s.Cfg.Get("pdf"))
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.InlineAssets {
+		html = s.inlinePDFAssets(p, html)
+	}
+
+	var out, stderr bytes.Buffer
+	args := collections.StringSliceToInterfaceSlice(cfg.Args)
+
+	if isChromiumRenderer(cfg.Renderer) {
+		dir, err := os.MkdirTemp("", "hugo-pdf")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		in, pdf := filepath.Join(dir, "index.html"), filepath.Join(dir, "index.pdf")
+		if err := os.WriteFile(in, html, 0644); err != nil {
+			return nil, err
+		}
+
+		args = append([]any{"--headless", "--disable-gpu", "--print-to-pdf=" + pdf}, args...)
+		args = append(args, (&url.URL{Scheme: "file", Path: filepath.ToSlash(in)}).String(), hexec.WithStderr(&stderr))
+		if err := s.runPDFRenderer(cfg.Renderer, args, &stderr); err != nil {
+			return nil, err
+		}
+
+		return os.ReadFile(pdf)
+	}
+
+	args = append([]any{"--quiet"}, args...)
+	args = append(args, "-", "-", hexec.WithStdin(bytes.NewReader(html)), hexec.WithStdout(&out), hexec.WithStderr(&stderr))
+	if err := s.runPDFRenderer(cfg.Renderer, args, &stderr); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func (s *Site) runPDFRenderer(name string, args []any, stderr *bytes.Buffer) error {
+	cmd, err := s.ExecHelper.New(name, args...)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+var (
+	pdfStylesheetRe = regexp.MustCompile(`(?is)<link\s[^>]*\brel=["']?stylesheet\b[^>]*>`)
+	pdfScriptRe     = regexp.MustCompile(`(?is)<script\s([^>]*)\bsrc=["']([^"']+)["']([^>]*)>\s*</script>`)
+	pdfImgSrcRe     = regexp.MustCompile(`(?is)(<img\s[^>]*\bsrc=)["']([^"']+)["']`)
+	pdfHrefRe       = regexp.MustCompile(`(?is)\bhref=["']([^"']+)["']`)
+)
+
+// inlinePDFAssets replaces the references to the site's stylesheets,
+// scripts and images in html with their content.
+// References that cannot be resolved are left as is.
+func (s *Site) inlinePDFAssets(p *pageState, html []byte) []byte {
+	html = pdfStylesheetRe.ReplaceAllFunc(html, func(m []byte) []byte {
+		href := pdfHrefRe.FindSubmatch(m)
+		if href == nil {
+			return m
+		}
+		b, ok := s.readSiteFile(p, string(href[1]))
+		if !ok {
+			return m
+		}
+		return append(append([]byte("<style>"), b...), "</style>"...)
+	})
+
+	html = pdfScriptRe.ReplaceAllFunc(html, func(m []byte) []byte {
+		sm := pdfScriptRe.FindSubmatch(m)
+		b, ok := s.readSiteFile(p, string(sm[2]))
+		if !ok {
+			return m
+		}
+		attrs := strings.TrimSpace(string(sm[1]) + string(sm[3]))
+		var buf bytes.Buffer
+		buf.WriteString("<script")
+		if attrs != "" {
+			buf.WriteString(" " + attrs)
+		}
+		buf.WriteString(">")
+		buf.Write(b)
+		buf.WriteString("</script>")
+		return buf.Bytes()
+	})
+
+	html = pdfImgSrcRe.ReplaceAllFunc(html, func(m []byte) []byte {
+		sm := pdfImgSrcRe.FindSubmatch(m)
+		src := string(sm[2])
+		if strings.HasPrefix(src, "data:") {
+			return m
+		}
+		b, ok := s.readSiteFile(p, src)
+		if !ok {
+			return m
+		}
+		u, _ := url.Parse(src)
+		mt := mime.TypeByExtension(path.Ext(u.Path))
+		if mt == "" {
+			mt = "application/octet-stream"
+		}
+		return []byte(fmt.Sprintf(`%s"data:%s;base64,%s"`, sm[1], mt, base64.StdEncoding.EncodeToString(b)))
+	})
+
+	return html
+}
@@ -0,0 +1,110 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPDFOutputFormat(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as renderer")
+	}
+
+	// A fake renderer that writes a PDF header followed by the HTML it got.
+	dir := t.TempDir()
+	script := "#!/bin/sh\nprintf '%%PDF-fake %s\\n' \"$*\"\ncat\n"
+	if err := os.WriteFile(filepath.Join(dir, "fakepdf"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/docs/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+[outputs]
+page = ["html", "pdf"]
+[pdf]
+renderer = "fakepdf"
+args = ["--page-size", "A4"]
+[security.exec]
+allow = ["^fakepdf$"]
+-- static/css/style.css --
+body { color: red; }
+-- static/js/main.js --
+console.log("pdf");
+-- static/images/dot.png --
+UE5H
+-- content/book.md --
+---
+title: "The Book"
+---
+Read me.
+-- layouts/_default/single.html --
+HTML: {{ .Title }}|{{ with .OutputFormats.Get "pdf" }}{{ .RelPermalink }}{{ end }}
+-- layouts/_default/single.pdf.html --
+<html><head><link rel="stylesheet" href="{{ "css/style.css" | absURL }}"><script src="/docs/js/main.js"></script><link rel="stylesheet" href="https://other.org/remote.css"></head>
+<body><img src="../images/dot.png" alt="Dot"> {{ .Title }}: {{ .Content }}</body></html>
+-- layouts/index.html --
+Home.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/book/index.html", "HTML: The Book|/docs/book/index.pdf")
+	b.AssertFileContent("public/book/index.pdf",
+		"%PDF-fake --quiet --page-size A4 - -",
+		"<style>body { color: red; }</style>",
+		"<script>console.log(\"pdf\");</script>",
+		`<link rel="stylesheet" href="https://other.org/remote.css">`,
+		`<img src="data:image/png;base64,UE5H" alt="Dot">`,
+		"The Book: <p>Read me.</p>",
+	)
+}
+
+func TestPDFOutputFormatNotAllowed(t *testing.T) {
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+[outputs]
+page = ["pdf"]
+-- content/book.md --
+---
+title: "The Book"
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+`
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, `access denied: "wkhtmltopdf" is not whitelisted in policy "security.exec.allow"`)
+}
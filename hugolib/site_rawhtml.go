@@ -0,0 +1,119 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gohugoio/hugo/markup/rawhtml"
+	"github.com/gohugoio/hugo/resources/page"
+)
+
+// rawHTMLRule is a compiled markup.goldmark.renderer.rawHTML rule.
+type rawHTMLRule struct {
+	matcher page.PageMatcher
+	policy  *rawhtml.Policy
+}
+
+// newRawHTMLRules compiles the raw HTML policies configured for the site's
+// language.
+func (s *Site) newRawHTMLRules() ([]rawHTMLRule, error) {
+	cfg := s.ContentSpec.Converters.GetMarkupConfig().Goldmark.Renderer.RawHTML
+
+	rules := make([]rawHTMLRule, len(cfg))
+	for i, c := range cfg {
+		policy, err := rawhtml.New(c.Action, c.Elements, c.Attributes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode markup.goldmark.renderer.rawHTML config: %w", err)
+		}
+		rules[i] = rawHTMLRule{matcher: page.PageMatcher{Path: c.Path}, policy: policy}
+	}
+
+	return rules, nil
+}
+
+// rawHTMLPolicy returns the policy of the first raw HTML rule matching p,
+// nil if none.
+func (p *pageState) rawHTMLPolicy() *rawhtml.Policy {
+	if len(p.s.ContentSpec.Converters.GetMarkupConfig().Goldmark.Renderer.RawHTML) == 0 {
+		return nil
+	}
+
+	v, err := p.s.init.rawHTML.Do()
+	if err != nil {
+		p.s.h.FatalError(p.wrapError(err))
+		return nil
+	}
+
+	for _, r := range v.([]rawHTMLRule) {
+		if r.matcher.Matches(p) {
+			return r.policy
+		}
+	}
+
+	return nil
+}
+
+// reportRawHTMLRemoved logs the raw HTML removed from the content of p with
+// its position in the source file, as an error in strict mode.
+func (p *pageState) reportRawHTMLRemoved(removed []rawhtml.Removal) {
+	if len(removed) == 0 {
+		return
+	}
+
+	var (
+		sb     strings.Builder
+		source string
+		offset = p.source.posMainContent
+	)
+	if p.source.parsed != nil {
+		source = string(p.source.parsed.Input())
+	}
+	if offset < 0 || offset > len(source) {
+		offset = 0
+	}
+
+	fmt.Fprintf(&sb, "Raw HTML removed in %q:", p.pathOrTitle())
+	for _, r := range removed {
+		// Search for the first line only, as the lines of HTML in e.g.
+		// lists are indented in the source.
+		markup := r.Markup
+		if i := strings.IndexByte(markup, '\n'); i != -1 {
+			markup = markup[:i]
+		}
+
+		sb.WriteString("\n  ")
+		if i := strings.Index(source[offset:], markup); i != -1 {
+			pos := p.posFromPage(offset + i)
+			fmt.Fprintf(&sb, "%d:%d: ", pos.LineNumber, pos.ColumnNumber)
+			offset += i + len(markup)
+		}
+		fmt.Fprintf(&sb, "%s: %s", r.Reason, truncateMarkup(markup))
+	}
+
+	if p.s.Strict.RawHTMLOmitted {
+		p.s.LogDistinct.Errorln(sb.String())
+	} else {
+		p.s.LogDistinct.Warnln(sb.String())
+	}
+}
+
+func truncateMarkup(s string) string {
+	const max = 80
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "…"
+}
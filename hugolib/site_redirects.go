@@ -0,0 +1,289 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/config"
+)
+
+// The file names used for the redirects formats.
+var redirectsFilenames = map[string]string{
+	config.RedirectsFormatNetlify: "_redirects",
+	config.RedirectsFormatVercel:  "vercel.json",
+	config.RedirectsFormatNginx:   "nginx-redirects.conf",
+
+	config.ErrorPagesFormatCloudFront: "cloudfront-error-responses.json",
+}
+
+// MatchRedirect returns the first redirect rule configured in any of the
+// sites that matches path, and its target. With multiple hosts, only the
+// rules of the site with the given language are matched, as each language
+// is served and published on its own.
+func (h *HugoSites) MatchRedirect(lang, path, rawQuery string) (config.RedirectRule, string, bool) {
+	for _, s := range h.Sites {
+		if h.multihost && lang != "" && s.Lang() != lang {
+			continue
+		}
+		if rule, to, found := s.siteCfg.redirects.Match(path, rawQuery); found {
+			return rule, to, true
+		}
+		resourceRedirects := config.Redirects{Rules: s.resourceRedirects}
+		if rule, to, found := resourceRedirects.Match(path, rawQuery); found {
+			return rule, to, true
+		}
+	}
+	return config.RedirectRule{}, "", false
+}
+
+// renderRedirects publishes the redirects config and the error page wiring
+// in the configured formats. With multiple hosts, each language gets its own
+// files, else the rules of all languages are merged into one set of files in
+// the publish root.
+func (h *HugoSites) renderRedirects() error {
+	if h.multihost {
+		for _, s := range h.Sites {
+			if err := s.publishRedirects([]*Site{s}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return h.Sites[0].publishRedirects(h.Sites)
+}
+
+// langRedirectRule is a redirect rule and the language it was configured in.
+type langRedirectRule struct {
+	lang string
+	rule config.RedirectRule
+}
+
+// publishRedirects publishes the redirects and error pages of sites into the publish root of s.
+func (s *Site) publishRedirects(sites []*Site) error {
+	var (
+		formats    []string
+		rules      []config.RedirectRule
+		errorPages []errorPage
+		seen       = make(map[string]bool)
+		seenRules  = make(map[string]langRedirectRule)
+	)
+
+	addFormat := func(f string) {
+		if !seen["format:"+f] {
+			seen["format:"+f] = true
+			formats = append(formats, f)
+		}
+	}
+
+	for _, ss := range sites {
+		for _, f := range ss.siteCfg.redirects.Formats {
+			addFormat(f)
+		}
+		for _, rules2 := range [][]config.RedirectRule{ss.siteCfg.redirects.Rules, ss.resourceRedirects} {
+			for _, rule := range rules2 {
+				if first, found := seenRules[rule.From]; found {
+					// The rules configured for all languages are repeated in every
+					// site, but with one host, all the rules end up in one file.
+					if first.rule.To != rule.To || first.rule.Status != rule.Status {
+						return fmt.Errorf("redirect from %q: %q in language %q conflicts with %q in language %q", rule.From, rule.To, ss.Lang(), first.rule.To, first.lang)
+					}
+					continue
+				}
+				seenRules[rule.From] = langRedirectRule{lang: ss.Lang(), rule: rule}
+				rules = append(rules, rule)
+			}
+		}
+		for _, f := range ss.siteCfg.errorPages.Formats {
+			addFormat(f)
+		}
+		if len(ss.siteCfg.errorPages.Formats) > 0 {
+			errorPages = append(errorPages, ss.errorPages...)
+		}
+	}
+
+	for _, f := range formats {
+		var (
+			b   []byte
+			err error
+		)
+		switch f {
+		case config.RedirectsFormatNetlify:
+			b = redirectsNetlify(append(rules, errorPagesNetlify(errorPages)...))
+		case config.RedirectsFormatVercel:
+			if len(rules) > 0 {
+				b, err = redirectsVercel(rules)
+			}
+		case config.RedirectsFormatNginx:
+			b = redirectsNginx(rules)
+		case config.ErrorPagesFormatCloudFront:
+			b, err = errorPagesCloudFront(errorPages)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create %s redirects: %w", f, err)
+		}
+		if len(b) == 0 {
+			continue
+		}
+
+		targetPath := filepath.Join(s.PathSpec.GetTargetLanguageBasePath(), redirectsFilenames[f])
+		if err := s.publish(&s.PathSpec.ProcessingStats.Files, targetPath, bytes.NewReader(b), s.BaseFs.PublishFs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// errorPagesNetlify creates the rules to serve the 404 pages in the sections
+// and languages, most specific first. Netlify serves /404.html by default.
+func errorPagesNetlify(pages []errorPage) []config.RedirectRule {
+	var rules []config.RedirectRule
+	for _, p := range pages {
+		if p.code != http.StatusNotFound {
+			continue
+		}
+		dir := path.Dir(p.relPermalink)
+		if dir == "/" {
+			continue
+		}
+		rules = append(rules, config.RedirectRule{From: dir + "/*", To: p.relPermalink, Status: p.code})
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].From) > len(rules[j].From)
+	})
+
+	return rules
+}
+
+// errorPagesCloudFront creates the CustomErrorResponses of a CloudFront
+// distribution for the error pages in the root, as only one page per
+// status code is supported.
+func errorPagesCloudFront(pages []errorPage) ([]byte, error) {
+	type item struct {
+		ErrorCode          int
+		ResponsePagePath   string
+		ResponseCode       string
+		ErrorCachingMinTTL int
+	}
+
+	var items []item
+	seen := make(map[int]bool)
+	for _, p := range pages {
+		if p.section != "" || seen[p.code] {
+			continue
+		}
+		seen[p.code] = true
+		items = append(items, item{
+			ErrorCode:          p.code,
+			ResponsePagePath:   p.relPermalink,
+			ResponseCode:       strconv.Itoa(p.code),
+			ErrorCachingMinTTL: 10,
+		})
+	}
+
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	return json.MarshalIndent(map[string]any{
+		"Quantity": len(items),
+		"Items":    items,
+	}, "", "  ")
+}
+
+// redirectsNetlify creates a Netlify _redirects file, which uses the same
+// pattern syntax as the config.
+func redirectsNetlify(rules []config.RedirectRule) []byte {
+	var b bytes.Buffer
+	for _, r := range rules {
+		force := ""
+		if r.Force {
+			force = "!"
+		}
+		fmt.Fprintf(&b, "%s %s %d%s\n", r.From, r.To, r.Status, force)
+	}
+	return b.Bytes()
+}
+
+type vercelRoute struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+}
+
+// redirectsVercel creates a vercel.json file with redirects and rewrites.
+// Vercel names the trailing wildcard like any other param, e.g. ":splat*".
+func redirectsVercel(rules []config.RedirectRule) ([]byte, error) {
+	var cfg struct {
+		Redirects []vercelRoute `json:"redirects,omitempty"`
+		Rewrites  []vercelRoute `json:"rewrites,omitempty"`
+	}
+
+	for _, r := range rules {
+		route := vercelRoute{
+			Source:      r.From,
+			Destination: r.To,
+		}
+		if strings.HasSuffix(route.Source, "*") {
+			route.Source = strings.TrimSuffix(route.Source, "*") + ":splat*"
+		}
+		if r.IsRewrite() {
+			cfg.Rewrites = append(cfg.Rewrites, route)
+		} else {
+			route.StatusCode = r.Status
+			cfg.Redirects = append(cfg.Redirects, route)
+		}
+	}
+
+	return json.MarshalIndent(cfg, "", "  ")
+}
+
+var redirectParamRe = regexp.MustCompile(`:[a-zA-Z_][a-zA-Z0-9_]*`)
+
+// redirectsNginx creates an Nginx config snippet to include in a server block.
+func redirectsNginx(rules []config.RedirectRule) []byte {
+	var b bytes.Buffer
+	for _, r := range rules {
+		params := r.Params()
+		to := redirectParamRe.ReplaceAllStringFunc(r.To, func(s string) string {
+			for i, p := range params {
+				if s[1:] == p {
+					return fmt.Sprintf("$%d", i+1)
+				}
+			}
+			return s
+		})
+		if r.IsRewrite() {
+			fmt.Fprintf(&b, "rewrite %s %s last;\n", r.RegexpSource(), to)
+			continue
+		}
+		if r.PreserveQuery {
+			to += "$is_args$args"
+		}
+		fmt.Fprintf(&b, "location ~ %s {\n  return %d %s;\n}\n", r.RegexpSource(), r.Status, to)
+	}
+	return b.Bytes()
+}
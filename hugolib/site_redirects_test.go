@@ -0,0 +1,174 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRedirects(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+defaultContentLanguage = "en"
+[redirects]
+formats = ["netlify", "vercel", "nginx"]
+[[redirects.rules]]
+from = "/blog/:year/*"
+to = "/posts/:year/:splat"
+[[redirects.rules]]
+from = "/search"
+to = "/find/"
+status = 302
+preserveQuery = true
+force = true
+[[redirects.rules]]
+from = "/app/*"
+to = "/app/"
+status = 200
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+[[languages.nn.redirects.rules]]
+from = "/nn/gamal/*"
+to = "/nn/ny/:splat"
+-- layouts/index.html --
+Home.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContentExact("public/_redirects", `/blog/:year/* /posts/:year/:splat 301
+/search /find/ 302!
+/app/* /app/ 200
+/nn/gamal/* /nn/ny/:splat 301
+`)
+
+	b.AssertFileContent("public/vercel.json", `"source": "/blog/:year/:splat*",
+      "destination": "/posts/:year/:splat",
+      "statusCode": 301`, `"rewrites": [
+    {
+      "source": "/app/:splat*",
+      "destination": "/app/"
+    }`)
+
+	b.AssertFileContentExact("public/nginx-redirects.conf", `location ~ ^/blog/([^/]+)/(.*)$ {
+  return 301 /posts/$1/$2;
+}
+location ~ ^/search$ {
+  return 302 /find/$is_args$args;
+}
+rewrite ^/app/(.*)$ /app/ last;
+`)
+
+	rule, to, found := b.H.MatchRedirect("", "/nn/gamal/a/", "")
+	b.Assert(found, qt.IsTrue)
+	b.Assert(rule.Status, qt.Equals, 301)
+	b.Assert(to, qt.Equals, "/nn/ny/a/")
+}
+
+func TestRedirectsLanguageConflict(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+[languages.en.redirects]
+formats = ["netlify"]
+[[languages.en.redirects.rules]]
+from = "/old"
+to = "/new/"
+[languages.nn]
+weight = 2
+[languages.nn.redirects]
+formats = ["netlify"]
+[[languages.nn.redirects.rules]]
+from = "/old"
+to = "/nn/ny/"
+-- layouts/index.html --
+Home.
+`
+
+	_, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	qt.Assert(t, err, qt.IsNotNil)
+	qt.Assert(t, err.Error(), qt.Contains, `redirect from "/old": "/nn/ny/" in language "nn" conflicts with "/new/" in language "en"`)
+}
+
+func TestRedirectsMultihost(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+baseURL = "https://example.org/"
+weight = 1
+[languages.en.redirects]
+formats = ["netlify"]
+[[languages.en.redirects.rules]]
+from = "/old"
+to = "/new/"
+[languages.fr]
+baseURL = "https://example.fr/"
+weight = 2
+[languages.fr.redirects]
+formats = ["netlify"]
+[[languages.fr.redirects.rules]]
+from = "/old"
+to = "/nouveau/"
+-- layouts/index.html --
+Home.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContentExact("public/en/_redirects", "/old /new/ 301\n")
+	b.AssertFileContentExact("public/fr/_redirects", "/old /nouveau/ 301\n")
+
+	_, to, found := b.H.MatchRedirect("fr", "/old", "")
+	b.Assert(found, qt.IsTrue)
+	b.Assert(to, qt.Equals, "/nouveau/")
+	_, to, _ = b.H.MatchRedirect("en", "/old", "")
+	b.Assert(to, qt.Equals, "/new/")
+}
@@ -0,0 +1,332 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gohugoio/hugo/common/text"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/spf13/afero"
+)
+
+// refFragment is a #fragment in a ref or relref, checked against the
+// anchors of the target page once the site is rendered.
+type refFragment struct {
+	ref          string
+	fragment     string
+	source       page.Page
+	target       *pageState
+	outputFormat string
+	pos          text.Position
+}
+
+// refFragments collects the fragments in a build and caches the anchors of
+// the pages.
+type refFragments struct {
+	mu   sync.Mutex
+	refs map[string]refFragment
+
+	// The headings in the source of a page.
+	headings map[*pageState][]refHeading
+
+	// The id attributes in the rendered output of a page, by output format.
+	anchors map[string]map[string]bool
+}
+
+type refHeading struct {
+	ID   string
+	Text string
+}
+
+func newRefFragments() *refFragments {
+	f := &refFragments{}
+	f.reset()
+	return f
+}
+
+func (f *refFragments) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.refs = make(map[string]refFragment)
+	f.headings = make(map[*pageState][]refHeading)
+	f.anchors = make(map[string]map[string]bool)
+}
+
+func (f *refFragments) add(r refFragment) {
+	key := fmt.Sprintf("%p|%p|%s|%s|%s", r.source, r.target, r.ref, r.outputFormat, r.pos)
+	f.mu.Lock()
+	f.refs[key] = r
+	f.mu.Unlock()
+}
+
+var (
+	refATXHeadingRe    = regexp.MustCompile(`^ {0,3}#{1,6}(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	refSetextUnderline = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+	refFenceRe         = regexp.MustCompile("^ {0,3}(```|~~~)")
+	refHeadingIDRe     = regexp.MustCompile(`[ \t]*\{[^}]*?(?:#([^\s}]+)|\bid=["']?([^"'\s}]+)["']?)[^}]*\}[ \t]*$`)
+	refInlineMarkupRe  = regexp.MustCompile("!?\\[([^\\]]*)\\]\\([^)]*\\)|[*_`~]")
+	refIDAttrRe        = regexp.MustCompile(`(?i)\s(?:id|name)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>"']+))`)
+)
+
+// headings returns the headings in the Markdown source of p with the IDs
+// they will get when rendered, so a ref can point to a heading by its text.
+func (f *refFragments) headingsOf(p *pageState) []refHeading {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if h, found := f.headings[p]; found {
+		return h
+	}
+
+	var headings []refHeading
+	if p.m.markup == "markdown" || p.m.markup == "goldmark" {
+		seen := make(map[string]bool)
+		add := func(s string) {
+			h := refHeading{Text: strings.TrimSpace(s)}
+			if m := refHeadingIDRe.FindStringSubmatch(h.Text); m != nil {
+				h.ID = m[1] + m[2]
+				h.Text = strings.TrimSuffix(h.Text, m[0])
+			}
+			h.Text = strings.TrimSpace(refInlineMarkupRe.ReplaceAllString(h.Text, "$1"))
+			if h.ID == "" {
+				base := p.s.ContentSpec.SanitizeAnchorName(h.Text)
+				h.ID = base
+				for i := 1; seen[h.ID]; i++ {
+					h.ID = base + "-" + strconv.Itoa(i)
+				}
+			}
+			seen[h.ID] = true
+			headings = append(headings, h)
+		}
+
+		var inFence bool
+		var prev string
+		for _, line := range strings.Split(p.RawContent(), "\n") {
+			line = strings.TrimRight(line, "\r")
+			if refFenceRe.MatchString(line) {
+				inFence = !inFence
+				prev = ""
+				continue
+			}
+			if inFence {
+				continue
+			}
+			if m := refATXHeadingRe.FindStringSubmatch(line); m != nil {
+				add(m[1])
+				prev = ""
+				continue
+			}
+			if strings.TrimSpace(prev) != "" && refSetextUnderline.MatchString(line) {
+				add(prev)
+				prev = ""
+				continue
+			}
+			prev = line
+		}
+	}
+
+	f.headings[p] = headings
+
+	return headings
+}
+
+// resolve returns the anchor for fragment in p: fragment itself if it is
+// the ID of a heading, else the ID of the heading with fragment as its
+// text, if any.
+func (f *refFragments) resolve(p *pageState, fragment string) string {
+	headings := f.headingsOf(p)
+	for _, h := range headings {
+		if h.ID == fragment {
+			return fragment
+		}
+	}
+	for _, h := range headings {
+		if strings.EqualFold(h.Text, fragment) {
+			return h.ID
+		}
+	}
+	return fragment
+}
+
+// anchorsOf returns the IDs in the rendered output of p in output format f,
+// nil if not rendered.
+func (f *refFragments) anchorsOf(p *pageState, outputFormat string) map[string]bool {
+	var po *pageOutput
+	for _, o := range p.pageOutputs {
+		if !o.render {
+			continue
+		}
+		if outputFormat == "" && o.f.IsHTML || strings.EqualFold(o.f.Name, outputFormat) {
+			po = o
+			break
+		}
+	}
+	if po == nil {
+		return nil
+	}
+
+	filename := filepath.FromSlash(strings.TrimPrefix(po.targetPaths().TargetFilename, "/"))
+	key := p.s.Lang() + ":" + filename
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if anchors, found := f.anchors[key]; found {
+		return anchors
+	}
+
+	var anchors map[string]bool
+	candidates := []string{filename}
+	if p.s.h.IsMultihost() {
+		candidates = append([]string{filepath.Join(p.s.Lang(), filename)}, candidates...)
+	}
+	for _, filename := range candidates {
+		b, err := afero.ReadFile(p.s.BaseFs.PublishFs, filename)
+		if err != nil {
+			continue
+		}
+		anchors = make(map[string]bool)
+		for _, m := range refIDAttrRe.FindAllStringSubmatch(string(b), -1) {
+			anchors[m[1]+m[2]+m[3]] = true
+		}
+		break
+	}
+	f.anchors[key] = anchors
+
+	return anchors
+}
+
+// checkRefFragments logs the fragments in ref and relref that do not match
+// an anchor in the target page, if refLinksCheckFragments is enabled.
+func (s *Site) checkRefFragments() {
+	if !s.siteRefLinker.checkFragments {
+		return
+	}
+
+	f := s.siteRefLinker.fragments
+	f.mu.Lock()
+	refs := make([]refFragment, 0, len(f.refs))
+	for _, r := range f.refs {
+		refs = append(refs, r)
+	}
+	f.mu.Unlock()
+
+	sourcePath := func(r refFragment) string {
+		if r.source == nil {
+			return ""
+		}
+		return r.source.Pathc()
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if pi, pj := sourcePath(refs[i]), sourcePath(refs[j]); pi != pj {
+			return pi < pj
+		}
+		return refs[i].ref < refs[j].ref
+	})
+
+	for _, r := range refs {
+		anchors := f.anchorsOf(r.target, r.outputFormat)
+		if anchors == nil || anchors[r.fragment] {
+			continue
+		}
+		s.siteRefLinker.logNotFound(r.ref, fmt.Sprintf("fragment %q not found in %q%s", r.fragment, r.target.Pathc(), refCandidates(r.fragment, f.headingsOf(r.target), anchors)), r.source, r.pos)
+	}
+}
+
+// refCandidates lists the anchors closest to fragment.
+func refCandidates(fragment string, headings []refHeading, anchors map[string]bool) string {
+	if len(anchors) == 0 {
+		return ""
+	}
+
+	texts := make(map[string]string)
+	for _, h := range headings {
+		texts[h.ID] = h.Text
+	}
+
+	type candidate struct {
+		id       string
+		distance int
+	}
+	var candidates []candidate
+	for id := range anchors {
+		d := editDistance(strings.ToLower(fragment), strings.ToLower(id))
+		if t, found := texts[id]; found {
+			if td := editDistance(strings.ToLower(fragment), strings.ToLower(t)); td < d {
+				d = td
+			}
+		}
+		candidates = append(candidates, candidate{id: id, distance: d})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].id < candidates[j].id
+	})
+
+	const max = 5
+	var names []string
+	for i, c := range candidates {
+		if i == max {
+			break
+		}
+		name := "#" + c.id
+		if t, found := texts[c.id]; found {
+			name += fmt.Sprintf(" (%q)", t)
+		}
+		names = append(names, name)
+	}
+
+	return "; candidates: " + strings.Join(names, ", ")
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+const refFragmentsTestFiles = `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+refLinksCheckFragments = true
+-- content/guide.md --
+---
+title: "Guide"
+---
+
+## Getting Started
+
+## Install **Hugo** {#setup}
+
+Configuration
+-------------
+
+### Deploy {id="deploying"}
+
+` + "```" + `
+## Not a heading
+` + "```" + `
+-- content/ref.md --
+---
+title: "Ref"
+---
+
+[A]({{< relref "guide.md#getting-started" >}})
+[B]({{< relref "guide.md#Install Hugo" >}})
+[C]({{< relref "guide.md#configuration" >}})
+[D]({{< relref "guide.md#comments" >}})
+[E]({{< relref "#local" >}})
+[F]({{< relref "guide.md#deploy" >}})
+-- layouts/_default/single.html --
+{{ .Content }}
+<div id="comments"></div>
+<div id="local"></div>
+`
+
+func TestRefFragments(t *testing.T) {
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: refFragmentsTestFiles,
+		},
+	).Build()
+
+	b.AssertFileContent("public/ref/index.html",
+		`<a href="/guide/#getting-started">A</a>`,
+		`<a href="/guide/#setup">B</a>`,
+		`<a href="/guide/#configuration">C</a>`,
+		`<a href="/guide/#comments">D</a>`,
+		`<a href="#local">E</a>`,
+		`<a href="/guide/#deploying">F</a>`,
+	)
+}
+
+func TestRefFragmentsNotFound(t *testing.T) {
+	files := refFragmentsTestFiles + `
+-- content/broken.md --
+---
+title: "Broken"
+---
+
+[A]({{< relref "guide.md#getting-startd" >}})
+[B]({{< relref "guide.md#Not a heading" >}})
+`
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.AssertLogContains(`REF_NOT_FOUND: Ref "guide.md#getting-startd": "/content/broken.md:5:5": fragment "getting-startd" not found in "guide.md"; candidates: #getting-started ("Getting Started"), #comments`)
+	b.AssertLogContains(`REF_NOT_FOUND: Ref "guide.md#Not a heading": "/content/broken.md:6:5": fragment "Not a heading" not found in "guide.md"; candidates: #configuration ("Configuration")`)
+}
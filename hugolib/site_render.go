@@ -15,10 +15,13 @@ package hugolib
 
 import (
 	"fmt"
+	"net/http"
 	"path"
 	"strings"
 	"sync"
 
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/identity"
 	"github.com/gohugoio/hugo/tpl"
 
 	"github.com/gohugoio/hugo/config"
@@ -77,7 +80,14 @@ func (s *Site) renderPages(ctx *siteRenderContext) error {
 	cfg := ctx.cfg
 
 	s.pageMap.pageTrees.Walk(func(ss string, n *contentNode) bool {
-		if cfg.shouldRender(n.p) {
+		reason := cfg.renderReason(n.p)
+		if s.h.running && n.p != nil && n.p.render {
+			s.h.stale.update(n.p, reason != "", cfg.mayBeStale(n.p))
+		}
+		if reason != "" {
+			if s.h.running && n.p.render {
+				s.h.rendered.add(n.p, reason)
+			}
 			select {
 			case <-s.h.Done():
 				return true
@@ -133,6 +143,12 @@ func pageRenderer(
 			continue
 		}
 
+		if s.running() {
+			if id, ok := templ.(identity.Provider); ok {
+				p.pageOutput.trackRenderDependency(id)
+			}
+		}
+
 		targetPath := p.targetPaths().TargetFilename
 
 		if err := s.renderAndWritePage(&s.PathSpec.ProcessingStats.Pages, "page "+p.Title(), targetPath, p, templ); err != nil {
@@ -222,12 +238,51 @@ func (s *Site) renderPaginator(p *pageState, templ tpl.Template) error {
 	return nil
 }
 
-func (s *Site) render404() error {
+// errorPage is a rendered error page.
+type errorPage struct {
+	code         int
+	section      string
+	relPermalink string
+}
+
+// renderErrorPages renders the configured error pages, e.g. 404.html,
+// and, if enabled, the same for each top level section.
+func (s *Site) renderErrorPages() error {
+	s.errorPages = nil
+
+	var sections []string
+	if s.siteCfg.errorPages.Sections && s.home != nil {
+		for _, sect := range s.home.Sections() {
+			sections = append(sections, sect.Section())
+		}
+	}
+
+	for _, code := range s.siteCfg.errorPages.Codes {
+		if err := s.renderErrorPage(code, ""); err != nil {
+			return err
+		}
+		for _, section := range sections {
+			if err := s.renderErrorPage(code, section); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *Site) renderErrorPage(code int, section string) error {
+	name := fmt.Sprintf("%d.html", code)
+
 	p, err := newPageStandalone(&pageMeta{
 		s:    s,
 		kind: kind404,
+		params: maps.Params{
+			"statuscode": code,
+			"section":    section,
+		},
 		urlPaths: pagemeta.URLPath{
-			URL: "404.html",
+			URL: path.Join(section, name),
 		},
 	},
 		output.HTMLFormat,
@@ -240,24 +295,51 @@ func (s *Site) render404() error {
 		return nil
 	}
 
-	var d output.LayoutDescriptor
-	d.Kind = kind404
-
-	templ, found, err := s.Tmpl().LookupLayout(d, output.HTMLFormat)
-	if err != nil {
-		return err
+	var layouts []string
+	if section != "" {
+		layouts = append(layouts, path.Join(section, name))
 	}
-	if !found {
-		return nil
+
+	var templ tpl.Template
+	if code == 404 {
+		if section != "" {
+			templ = s.lookupLayouts(layouts...)
+		}
+		if templ == nil {
+			var d output.LayoutDescriptor
+			d.Kind = kind404
+
+			var found bool
+			templ, found, err = s.Tmpl().LookupLayout(d, output.HTMLFormat)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return nil
+			}
+		}
+	} else {
+		layouts = append(layouts, name, "_default/"+name)
+		// Fall back to the section's and the site's 404 template.
+		if section != "" {
+			layouts = append(layouts, path.Join(section, "404.html"))
+		}
+		layouts = append(layouts, "404.html", "_default/404.html")
+		templ = s.lookupLayouts(layouts...)
+		if templ == nil {
+			return nil
+		}
 	}
 
 	targetPath := p.targetPaths().TargetFilename
 
 	if targetPath == "" {
-		return errors.New("failed to create targetPath for 404 page")
+		return fmt.Errorf("failed to create targetPath for %d page", code)
 	}
 
-	return s.renderAndWritePage(&s.PathSpec.ProcessingStats.Pages, "404 page", targetPath, p, templ)
+	s.errorPages = append(s.errorPages, errorPage{code: code, section: section, relPermalink: p.RelPermalink()})
+
+	return s.renderAndWritePage(&s.PathSpec.ProcessingStats.Pages, fmt.Sprintf("%d page", code), targetPath, p, templ)
 }
 
 func (s *Site) renderSitemap() error {
@@ -315,6 +397,49 @@ func (s *Site) renderRobotsTXT() error {
 	return s.renderAndWritePage(&s.PathSpec.ProcessingStats.Pages, "Robots Txt", p.targetPaths().TargetFilename, p, templ)
 }
 
+// renderResourceAliases publishes the aliases of the page resources set in
+// the resources front matter: redirects if a redirects format is configured,
+// else copies of the resources.
+func (s *Site) renderResourceAliases() error {
+	s.resourceRedirects = nil
+
+	var err error
+	s.pageMap.pageTrees.WalkLinkable(func(ss string, n *contentNode) bool {
+		p := n.p
+		if len(p.m.resourcesMetadata) == 0 {
+			return false
+		}
+
+		p.Resources()
+		for _, ra := range p.resourceAliases {
+			target := ra.r.RelPermalink()
+			for _, a := range ra.aliases {
+				if !strings.HasPrefix(a, "/") {
+					a = path.Join(p.targetPaths().SubResourceBaseLink, a)
+				}
+
+				lang := p.Language().Lang
+				if s.h.multihost && !strings.HasPrefix(a, "/"+lang+"/") {
+					a = path.Join("/", lang, a)
+				}
+
+				if len(s.siteCfg.redirects.Formats) > 0 {
+					s.resourceRedirects = append(s.resourceRedirects, config.NewRedirectRule(a, target, http.StatusMovedPermanently))
+					continue
+				}
+
+				if err = s.publishResourceAlias(a, ra.r); err != nil {
+					err = fmt.Errorf("%s: %w", p.pathOrTitle(), err)
+					return true
+				}
+			}
+		}
+		return false
+	})
+
+	return err
+}
+
 // renderAliases renders shell pages that simply have a redirect in the header.
 func (s *Site) renderAliases() error {
 	var err error
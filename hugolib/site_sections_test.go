@@ -373,3 +373,152 @@ Next: {{ with .NextInSection }}{{ .RelPermalink }}{{ end }}|
 	b.AssertFileContent("public/blog/cool/cool2/index.html",
 		"Prev: |", "Next: /blog/cool/cool1/|")
 }
+
+func TestPageBreadcrumbs(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+title = "My Site"
+disableKinds = ['RSS','sitemap','taxonomy','term']
+-- content/docs/_index.md --
+---
+title: "Documentation"
+linkTitle: "Docs"
+---
+-- content/docs/a/_index.md --
+---
+title: "A"
+---
+-- content/docs/a/b/_index.md --
+---
+title: "B"
+---
+-- content/docs/a/b/p1.md --
+---
+title: "P1"
+---
+-- layouts/_default/single.html --
+{{ define "crumbs" }}{{ range . }}{{ if .IsEllipsis }}…{{ else }}{{ .Position }}:{{ .Name }}:{{ .RelPermalink }}{{ if .IsCurrent }}:current{{ end }}{{ end }}|{{ end }}{{ end }}
+Crumbs: {{ template "crumbs" .Breadcrumbs }}
+-- layouts/_default/list.html --
+{{ range .Breadcrumbs }}{{ .Position }}:{{ .Name }}|{{ end }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/docs/a/b/p1/index.html", "Crumbs: 1:My Site:/|2:Docs:/docs/|3:A:/docs/a/|4:B:/docs/a/b/|5:P1:/docs/a/b/p1/:current|")
+	b.AssertFileContent("public/docs/index.html", "1:My Site|2:Docs|")
+	b.AssertFileContent("public/index.html", "1:My Site|")
+
+	b = NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: strings.Replace(files, "title = \"My Site\"", "title = \"My Site\"\n[breadcrumbs]\nmaxEntries = 4", 1),
+		},
+	).Build()
+
+	b.AssertFileContent("public/docs/a/b/p1/index.html", "Crumbs: 1:My Site:/|…|4:B:/docs/a/b/|5:P1:/docs/a/b/p1/:current|")
+
+	b = NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: strings.Replace(files, "title = \"My Site\"", "title = \"My Site\"\n[breadcrumbs]\nhome = false", 1),
+		},
+	).Build()
+
+	b.AssertFileContent("public/docs/a/b/p1/index.html", "Crumbs: 1:Docs:/docs/|2:A:/docs/a/|3:B:/docs/a/b/|4:P1:/docs/a/b/p1/:current|")
+}
+
+func TestNextInSectionOrder(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ['RSS','sitemap','taxonomy','term']
+-- content/_index.md --
+---
+title: "Home"
+cascade:
+  _target:
+    path: "/series/**"
+  inSectionOrder: "param:part"
+---
+-- content/blog/_index.md --
+---
+title: "Blog"
+inSectionOrder:
+  by: date
+  reverse: true
+---
+-- content/blog/b1.md --
+---
+title: "B1"
+weight: 1
+date: 2022-01-03
+---
+-- content/blog/b2.md --
+---
+title: "B2"
+weight: 2
+date: 2022-01-01
+---
+-- content/blog/b3.md --
+---
+title: "B3"
+weight: 3
+date: 2022-01-02
+---
+-- content/series/_index.md --
+---
+title: "Series"
+---
+-- content/series/s1/_index.md --
+---
+title: "S1"
+---
+-- content/series/s1/p1.md --
+---
+title: "P1"
+part: 3
+---
+-- content/series/s1/p2.md --
+---
+title: "P2"
+part: 1
+---
+-- content/series/s1/p3.md --
+---
+title: "P3"
+part: 2
+---
+-- layouts/_default/single.html --
+Prev: {{ with .PrevInSection }}{{ .Title }}{{ end }}|Next: {{ with .NextInSection }}{{ .Title }}{{ end }}|
+{{ $pages := site.RegularPages.ByTitle }}
+PrevInCollection: {{ with .PrevInCollection $pages }}{{ .Title }}{{ end }}|NextInCollection: {{ with .NextInCollection $pages }}{{ .Title }}{{ end }}|
+-- layouts/_default/list.html --
+List.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	// Reversed by date: B1, B3, B2.
+	b.AssertFileContent("public/blog/b3/index.html", "Prev: B2|Next: B1|", "PrevInCollection: P1|NextInCollection: B2|")
+	b.AssertFileContent("public/blog/b1/index.html", "Prev: B3|Next: |")
+
+	// By the part param: P2, P3, P1.
+	b.AssertFileContent("public/series/s1/p3/index.html", "Prev: P1|Next: P2|", "PrevInCollection: |NextInCollection: P2|")
+	b.AssertFileContent("public/series/s1/p2/index.html", "Prev: P3|Next: |")
+}
@@ -1109,3 +1109,51 @@ ABC.
 		b.Assert(els.IDs, qt.HasLen, 1)
 	}
 }
+
+func TestSiteScratchAndStore(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+-- content/p1.md --
+---
+title: "p1"
+---
+{{< count >}}{{< count >}}
+-- content/p2.md --
+---
+title: "p2"
+---
+{{< count >}}
+-- layouts/shortcodes/count.html --
+{{ $.Page.Site.Scratch.Incr "shortcodes" }}
+-- layouts/shortcodes/foo.html --
+notused
+-- layouts/index.html --
+Home.
+-- layouts/_default/single.html --
+{{ .Content }}
+Scratch: {{ .Site.Scratch.Incr .Title }}|
+Store: {{ .Site.Store.Incr .Title }}|
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			Running:     true,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html", "Scratch: 1|", "Store: 1|")
+	b.AssertFileContent("public/p2/index.html", "Scratch: 1|", "Store: 1|")
+	b.Assert(b.H.Sites[0].Info.Scratch().Get("shortcodes"), qt.Equals, int64(3))
+
+	b.EditFiles("layouts/shortcodes/foo.html", "edit")
+
+	b.Build()
+
+	b.AssertFileContent("public/p1/index.html", "Scratch: 1|", "Store: 2|")
+	b.AssertFileContent("public/p2/index.html", "Scratch: 1|", "Store: 2|")
+}
@@ -185,3 +185,120 @@ Do not go gentle into that good night.
 	th.assertFileContent(filepath.Join("public", "ss1", "index.html"), "P1|URL: /ss1/|Next: /ss1/page/2/")
 	th.assertFileContent(filepath.Join("public", "ss1", "page", "2", "index.html"), "P2|URL: /ss1/page/2/|Next: /ss1/page/3/")
 }
+
+func TestURLStylePerOutputFormatAndSection(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "rss", "sitemap", "robotsTXT", "404"]
+[outputs]
+page = ["html", "json"]
+[outputFormats.json]
+ugly = true
+-- content/docs/_index.md --
+---
+title: "Docs"
+cascade:
+  trailingSlash: false
+---
+-- content/docs/d1.md --
+---
+title: "D1"
+---
+-- content/blog/_index.md --
+---
+title: "Blog"
+cascade:
+  uglyURLs: true
+---
+-- content/blog/b1.md --
+---
+title: "B1"
+---
+-- content/p1.md --
+---
+title: "P1"
+uglyURLs: true
+---
+-- layouts/_default/single.html --
+{{ .Title }}|{{ .RelPermalink }}|{{ with .OutputFormats.Get "json" }}{{ .RelPermalink }}{{ end }}|
+-- layouts/_default/single.json --
+{}
+-- layouts/_default/list.html --
+{{ .Title }}|{{ .RelPermalink }}|
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/docs/index.html", "Docs|/docs|")
+	b.AssertFileContent("public/docs/d1/index.html", "D1|/docs/d1|/docs/d1.json|")
+	b.AssertFileContent("public/blog.html", "Blog|/blog.html|")
+	b.AssertFileContent("public/blog/b1.html", "B1|/blog/b1.html|/blog/b1.json|")
+	b.AssertFileContent("public/p1.html", "P1|/p1.html|/p1.json|")
+}
+
+func TestSlugsPerLanguage(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["rss", "sitemap", "robotsTXT", "404"]
+defaultContentLanguage = "en"
+[slugs]
+stopWords = ["a", "the"]
+[languages]
+[languages.en]
+weight = 1
+[languages.ru]
+weight = 2
+[languages.ru.slugs]
+transliterate = ["cyrillic"]
+stopWords = ["i", "v"]
+maxLength = 20
+[languages.ru.permalinks]
+posts = "/:section/:title/"
+[languages.de]
+weight = 3
+[languages.de.slugs]
+transliterate = ["german"]
+-- content/posts/the-first-post.md --
+---
+title: "The First Post"
+tags: ["A Tag"]
+---
+-- content/posts/first.ru.md --
+---
+title: "Война и мир в двух томах и четырёх частях"
+tags: ["Ёлки"]
+---
+-- content/posts/grüße.de.md --
+---
+title: "Grüße"
+tags: ["Größe"]
+---
+-- layouts/_default/single.html --
+{{ .Title }}|{{ .RelPermalink }}|{{ .Title | urlize }}|{{ range .GetTerms "tags" }}{{ .RelPermalink }}|{{ end }}
+-- layouts/_default/list.html --
+{{ .Title }}|{{ .RelPermalink }}|
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/posts/first-post/index.html", "The First Post|/posts/first-post/|first-post|/tags/tag/|")
+	b.AssertFileContent("public/ru/posts/voyna-mir-dvukh/index.html", "|/ru/posts/voyna-mir-dvukh/|voyna-mir-dvukh|/ru/tags/yolki/|")
+	b.AssertFileContent("public/ru/tags/yolki/index.html", "Ёлки|/ru/tags/yolki/|")
+	b.AssertFileContent("public/de/posts/gruesse/index.html", "Grüße|/de/posts/gruesse/|gruesse|/de/tags/groesse/|")
+}
@@ -87,11 +87,12 @@ func doTestSitemapOutput(t *testing.T, internal bool) {
 
 func TestParseSitemap(t *testing.T) {
 	t.Parallel()
-	expected := config.Sitemap{Priority: 3.0, Filename: "doo.xml", ChangeFreq: "3"}
+	expected := config.Sitemap{Priority: 3.0, Filename: "doo.xml", ChangeFreq: "3", Disable: true}
 	input := map[string]any{
 		"changefreq": "3",
 		"priority":   3.0,
 		"filename":   "doo.xml",
+		"disable":    true,
 		"unknown":    "ignore",
 	}
 	result := config.DecodeSitemap(config.Sitemap{}, input)
@@ -118,3 +119,63 @@ outputs: [ "html", "amp" ]
 	// Should link to the HTML version.
 	b.AssertFileContent("public/sitemap.xml", " <loc>http://example.com/blog/html-amp/</loc>")
 }
+
+func TestSitemapComputed(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS"]
+[[cascade]]
+[cascade.sitemap]
+priority = { expr = 'cond (eq .Section "docs") 0.8 0.3', type = "float" }
+changefreq = { expr = 'cond (eq .Section "docs") "weekly" "yearly"' }
+disable = { expr = "lt .Lastmod.Year 2020", type = "bool" }
+[cascade._target]
+kind = "page"
+-- content/docs/d1.md --
+---
+title: "D1"
+date: 2022-01-01
+---
+-- content/blog/b1.md --
+---
+title: "B1"
+date: 2021-01-01
+---
+-- content/blog/b2.md --
+---
+title: "B2"
+date: 2015-01-01
+---
+-- content/blog/b3.md --
+---
+title: "B3"
+date: 2015-01-01
+sitemap:
+  priority: 0.1
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+-- layouts/_default/list.html --
+List.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/sitemap.xml",
+		"<loc>https://example.org/docs/d1/</loc>\n    <lastmod>2022-01-01T00:00:00+00:00</lastmod>\n    <changefreq>weekly</changefreq>\n    <priority>0.8</priority>",
+		"<loc>https://example.org/blog/b1/</loc>\n    <lastmod>2021-01-01T00:00:00+00:00</lastmod>\n    <changefreq>yearly</changefreq>\n    <priority>0.3</priority>",
+		// Front matter overrides the cascade.
+		"<loc>https://example.org/blog/b3/</loc>\n    <lastmod>2015-01-01T00:00:00+00:00</lastmod>\n    <priority>0.1</priority>",
+	)
+
+	// Disabled, as it is older than 2020.
+	b.Assert(b.FileContent("public/sitemap.xml"), qt.Not(qt.Contains), "https://example.org/blog/b2/")
+}
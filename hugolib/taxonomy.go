@@ -16,11 +16,13 @@ package hugolib
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/gohugoio/hugo/compare"
 	"github.com/gohugoio/hugo/langs"
 
 	"github.com/gohugoio/hugo/resources/page"
+	"github.com/mitchellh/mapstructure"
 )
 
 // The TaxonomyList is a list of all taxonomies and their values
@@ -171,3 +173,60 @@ func (s *orderedTaxonomySorter) Swap(i, j int) {
 func (s *orderedTaxonomySorter) Less(i, j int) bool {
 	return s.by(&s.taxonomy[i], &s.taxonomy[j])
 }
+
+// taxonomyOptions holds the options for a taxonomy, configured in
+// taxonomyOptions.<plural> in site config, e.g.:
+//
+//	[taxonomyOptions.tags]
+//	paginate = 20
+//	paginateBy = "title"
+//	minTermCount = 3
+type taxonomyOptions struct {
+	// The pager size and sort order of the taxonomy and term pages,
+	// overridden by the paginate, paginateBy and paginateOrder front matter
+	// params of the page (or cascade).
+	Paginate      int
+	PaginateBy    string
+	PaginateOrder string
+
+	// Terms with fewer pages than this get no term page, their pages are
+	// listed in the OtherTerm term instead.
+	MinTermCount int
+
+	// The term collecting the pages of the low-count terms, default "other".
+	OtherTerm string
+}
+
+func decodeTaxonomyOptions(in map[string]any, taxonomies taxonomiesConfig) (map[string]taxonomyOptions, error) {
+	opts := make(map[string]taxonomyOptions)
+	for k, v := range in {
+		plural := strings.ToLower(k)
+		var found bool
+		for _, p := range taxonomies {
+			if p == plural {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("taxonomyOptions: %q is not a configured taxonomy", k)
+		}
+
+		var o taxonomyOptions
+		if err := mapstructure.WeakDecode(v, &o); err != nil {
+			return nil, fmt.Errorf("failed to decode taxonomyOptions for %q: %w", k, err)
+		}
+		if o.Paginate < 0 {
+			return nil, fmt.Errorf("taxonomyOptions: paginate for %q must be a positive integer", k)
+		}
+		if err := validatePaginateSort(o.PaginateBy, o.PaginateOrder); err != nil {
+			return nil, fmt.Errorf("taxonomyOptions: %s", err)
+		}
+		if o.OtherTerm == "" {
+			o.OtherTerm = "other"
+		}
+		opts[plural] = o
+	}
+
+	return opts, nil
+}
@@ -694,3 +694,86 @@ abcdefgs: {{ template "print-page" $abcdefgs }}|IsAncestor: {{ $abcdefgs.IsAnces
     abcdefgs: /abcdefgs/|Abcdefgs|taxonomy|Parent: /|CurrentSection: /|FirstSection: /|IsAncestor: true|IsDescendant: false
 `)
 }
+
+func TestTaxonomyOptions(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["rss", "sitemap", "robotsTXT", "404"]
+paginate = 10
+[taxonomyOptions.tags]
+paginate = 2
+paginateBy = "title"
+minTermCount = 2
+otherTerm = "Misc"
+[taxonomyOptions.categories]
+paginateBy = "count"
+paginateOrder = "desc"
+-- content/p1.md --
+---
+title: "P1"
+tags: [a, rare1]
+categories: [cx]
+---
+-- content/p2.md --
+---
+title: "P2"
+tags: [a, rare2]
+categories: [cy]
+---
+-- content/p3.md --
+---
+title: "P3"
+tags: [a]
+categories: [cy]
+---
+-- content/tags/a/_index.md --
+---
+title: "A"
+paginateBy: "title"
+paginateOrder: "desc"
+---
+-- content/tags/rare1/_index.md --
+---
+title: "Rare 1"
+---
+-- layouts/_default/list.html --
+{{ $p := .Paginator }}Pager {{ $p.PageNumber }} of {{ $p.TotalPages }}: {{ range $p.Pages }}{{ .Title }}|{{ end }}
+-- layouts/_default/single.html --
+{{ .Title }}
+-- layouts/index.html --
+Tags: {{ range $k, $v := site.Taxonomies.tags }}{{ $k }}:{{ $v.Count }}|{{ end }}
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html", "Tags: a:3|misc:2|")
+	b.AssertFileContent("public/tags/index.html", "Pager 1 of 1: A|Misc|")
+	b.AssertFileContent("public/tags/a/index.html", "Pager 1 of 2: P3|P2|")
+	b.AssertFileContent("public/tags/a/page/2/index.html", "Pager 2 of 2: P1|")
+	b.AssertFileContent("public/tags/misc/index.html", "Pager 1 of 1: P1|P2|")
+	b.AssertFileContent("public/categories/index.html", "Pager 1 of 1: cy|cx|")
+	b.AssertDestinationExists("public/tags/rare1/index.html", false)
+	b.AssertDestinationExists("public/tags/rare2/index.html", false)
+}
+
+func TestDecodeTaxonomyOptions(t *testing.T) {
+	c := qt.New(t)
+
+	opts, err := decodeTaxonomyOptions(map[string]any{"Tags": map[string]any{"minTermCount": "3"}}, taxonomiesConfig{"tag": "tags"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(opts["tags"], qt.Equals, taxonomyOptions{MinTermCount: 3, OtherTerm: "other"})
+
+	_, err = decodeTaxonomyOptions(map[string]any{"foos": map[string]any{}}, taxonomiesConfig{"tag": "tags"})
+	c.Assert(err, qt.ErrorMatches, `taxonomyOptions: "foos" is not a configured taxonomy`)
+
+	_, err = decodeTaxonomyOptions(map[string]any{"tags": map[string]any{"paginateOrder": "up"}}, taxonomiesConfig{"tag": "tags"})
+	c.Assert(err, qt.ErrorMatches, `taxonomyOptions: invalid paginateOrder "up", must be asc or desc`)
+}
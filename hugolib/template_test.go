@@ -772,3 +772,55 @@ a: {{ $a }}
 
 	b.AssertFileContent("public/index.html", `a: [a b c]`)
 }
+
+func TestLayoutLookupRules(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT"]
+[params]
+brand = "acme"
+[layoutLookup]
+dimensions = ["brand"]
+[[layoutLookup.rules]]
+paths = ["brands/{brand}/{type}/{layout}", "brands/{brand}/{layout}"]
+-- content/posts/p1.md --
+---
+title: P1
+---
+-- content/posts/p2.md --
+---
+title: P2
+brand: globex
+---
+-- content/about.md --
+---
+title: About
+---
+-- layouts/_default/single.html --
+Default: {{ .Title }}
+-- layouts/_default/list.html --
+List: {{ .Title }}
+-- layouts/brands/acme/posts/single.html --
+Acme post: {{ .Title }}
+-- layouts/brands/globex/single.html --
+Globex: {{ .Title }}
+-- layouts/brands/acme/home.html --
+Acme home
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/posts/p1/index.html", "Acme post: P1")
+	b.AssertFileContent("public/posts/p2/index.html", "Globex: P2")
+	b.AssertFileContent("public/about/index.html", "Default: About")
+	b.AssertFileContent("public/index.html", "Acme home")
+	b.AssertFileContent("public/posts/index.html", "List: Posts")
+}
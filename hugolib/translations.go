@@ -14,6 +14,10 @@
 package hugolib
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/gohugoio/hugo/resources/page"
 )
 
@@ -24,7 +28,7 @@ func pagesToTranslationsMap(sites []*Site) map[string]page.Pages {
 		s.pageMap.pageTrees.Walk(func(ss string, n *contentNode) bool {
 			p := n.p
 			// TranslationKey is implemented for all page types.
-			base := p.TranslationKey()
+			base := p.baseTranslationKey()
 
 			pageTranslations, found := out[base]
 			if !found {
@@ -41,17 +45,137 @@ func pagesToTranslationsMap(sites []*Site) map[string]page.Pages {
 	return out
 }
 
-func assignTranslationsToPages(allTranslations map[string]page.Pages, sites []*Site) {
+// linkTranslations merges the translation sets in allTranslations linked by
+// the translations references in front matter, keyed by the smallest of
+// their keys, and returns the errors found in the resulting graph.
+func linkTranslations(allTranslations map[string]page.Pages, sites []*Site) (map[string]page.Pages, []error) {
+	var errs []error
+
+	// The translation sets as a disjoint-set forest of their keys.
+	parents := make(map[string]string)
+	var find func(key string) string
+	find = func(key string) string {
+		parent, found := parents[key]
+		if !found || parent == key {
+			return key
+		}
+		root := find(parent)
+		parents[key] = root
+		return root
+	}
+	union := func(a, b string) {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return
+		}
+		if rb < ra {
+			ra, rb = rb, ra
+		}
+		parents[rb] = ra
+	}
+
 	for _, s := range sites {
 		s.pageMap.pageTrees.Walk(func(ss string, n *contentNode) bool {
 			p := n.p
-			base := p.TranslationKey()
-			translations, found := allTranslations[base]
-			if !found {
-				return false
+			for _, ref := range p.m.translationRefs {
+				targets, err := p.resolveTranslationRef(ref, sites)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				for _, t := range targets {
+					union(p.baseTranslationKey(), t.(*pageState).baseTranslationKey())
+				}
 			}
-			p.setTranslations(translations)
 			return false
 		})
 	}
+
+	if len(parents) == 0 {
+		return allTranslations, errs
+	}
+
+	linked := make(map[string]page.Pages)
+	for key, pages := range allTranslations {
+		root := find(key)
+		linked[root] = append(linked[root], pages...)
+	}
+
+	// Validate that no language has more than one page in a set.
+	keys := make([]string, 0, len(linked))
+	for key := range linked {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		byLang := make(map[string][]string)
+		var langs []string
+		for _, p := range linked[key] {
+			lang := p.Lang()
+			if _, found := byLang[lang]; !found {
+				langs = append(langs, lang)
+			}
+			byLang[lang] = append(byLang[lang], p.(*pageState).pathOrTitle())
+		}
+		for _, lang := range langs {
+			if paths := byLang[lang]; len(paths) > 1 {
+				sort.Strings(paths)
+				errs = append(errs, fmt.Errorf("translations: %q are all linked as the %q translation of the same page, check the translations and translationKey in their front matter", paths, lang))
+			}
+		}
+	}
+
+	return linked, errs
+}
+
+// resolveTranslationRef resolves ref, a translations reference in the front
+// matter of p, to the page(s) it points to. The ref is a page path as in
+// GetPage, e.g. "/blog/hello", optionally prefixed with a language code,
+// e.g. "de:/blog/hallo"; without one it is looked up in all other languages.
+func (p *pageState) resolveTranslationRef(ref string, sites []*Site) (page.Pages, error) {
+	lang, path := "", ref
+	if i := strings.Index(ref, ":"); i != -1 {
+		for _, s := range sites {
+			if s.Lang() == ref[:i] {
+				lang, path = ref[:i], ref[i+1:]
+				break
+			}
+		}
+	}
+
+	if lang == p.Lang() {
+		return nil, fmt.Errorf("%q: translation %q is in the same language as the page", p.pathOrTitle(), ref)
+	}
+
+	var targets page.Pages
+	for _, s := range sites {
+		if s.Lang() == p.Lang() || (lang != "" && s.Lang() != lang) {
+			continue
+		}
+		t, err := s.getPageNew(p, path)
+		if err != nil {
+			return nil, fmt.Errorf("%q: failed to resolve translation %q: %w", p.pathOrTitle(), ref, err)
+		}
+		if t != nil {
+			targets = append(targets, t)
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%q: translation %q not found", p.pathOrTitle(), ref)
+	}
+
+	return targets, nil
+}
+
+func assignTranslationsToPages(allTranslations map[string]page.Pages) {
+	for key, translations := range allTranslations {
+		// Sort before the loop, setTranslations sorts the slice in place.
+		page.SortByLanguage(translations)
+		for _, p := range translations {
+			ps := p.(*pageState)
+			ps.translationGraphKey = key
+			ps.setTranslations(translations)
+		}
+	}
 }
@@ -0,0 +1,120 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugolib
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestTranslationsInFrontMatter(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+contentDir = "content/en"
+[languages.de]
+weight = 2
+contentDir = "content/de"
+[languages.fr]
+weight = 3
+contentDir = "content/fr"
+-- content/en/blog/hello.md --
+---
+title: "Hello"
+translations: ["de:/blog/hallo", "/articles/bonjour"]
+---
+-- content/de/blog/hallo.md --
+---
+title: "Hallo"
+---
+-- content/fr/articles/bonjour.md --
+---
+title: "Bonjour"
+---
+-- content/en/blog/other.md --
+---
+title: "Other"
+---
+-- layouts/_default/single.html --
+{{ .Title }}|{{ .TranslationKey }}|{{ range .Translations }}{{ .Lang }}:{{ .Title }}|{{ end }}
+-- layouts/_default/list.html --
+List.
+`
+
+	b := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/blog/hello/index.html", "Hello|page/articles/bonjour|de:Hallo|fr:Bonjour|")
+	b.AssertFileContent("public/de/blog/hallo/index.html", "Hallo|page/articles/bonjour|en:Hello|fr:Bonjour|")
+	b.AssertFileContent("public/fr/articles/bonjour/index.html", "Bonjour|page/articles/bonjour|en:Hello|de:Hallo|")
+	b.AssertFileContent("public/blog/other/index.html", "Other|page/blog/other|\n")
+}
+
+func TestTranslationsInFrontMatterErrors(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "RSS", "sitemap"]
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+contentDir = "content/en"
+[languages.de]
+weight = 2
+contentDir = "content/de"
+-- content/en/p1.md --
+---
+title: "P1"
+translations: ["de:/p1", "/missing", "en:/p2"]
+---
+-- content/en/p2.md --
+---
+title: "P2"
+translations: "de:/p1"
+---
+-- content/de/p1.md --
+---
+title: "P1 de"
+---
+-- layouts/_default/single.html --
+{{ .Title }}|{{ range .Translations }}{{ .Lang }}:{{ .Title }}|{{ end }}
+-- layouts/_default/list.html --
+List.
+`
+
+	b, err := NewIntegrationTestBuilder(
+		IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.Not(qt.IsNil))
+	b.AssertLogContains(`"/content/en/p1.md": translation "/missing" not found`)
+	b.AssertLogContains(`"/content/en/p1.md": translation "en:/p2" is in the same language as the page`)
+	b.AssertLogContains(`translations: ["/content/en/p1.md" "/content/en/p2.md"] are all linked as the "en" translation of the same page`)
+}
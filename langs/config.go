@@ -20,6 +20,7 @@ import (
 	"strings"
 
 	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/common/types"
 
 	"github.com/spf13/cast"
 
@@ -194,6 +195,8 @@ func toSortedLanguages(cfg config.Provider, l map[string]any) (Languages, error)
 				language.Weight = cast.ToInt(v)
 			case "contentdir":
 				language.ContentDir = filepath.Clean(cast.ToString(v))
+			case "contentdirs":
+				language.ContentDirs = cleanDirs(types.ToStringSlicePreserveString(v))
 			case "disabled":
 				language.Disabled = cast.ToBool(v)
 			case "params":
@@ -224,3 +227,14 @@ func toSortedLanguages(cfg config.Provider, l map[string]any) (Languages, error)
 
 	return languages, nil
 }
+
+func cleanDirs(dirs []string) []string {
+	if len(dirs) == 0 {
+		return nil
+	}
+	cleaned := make([]string, len(dirs))
+	for i, dir := range dirs {
+		cleaned[i] = filepath.Clean(dir)
+	}
+	return cleaned
+}
@@ -37,11 +37,17 @@ type Translator struct {
 	translateFuncs map[string]translateFunc
 	cfg            config.Provider
 	logger         loggers.Logger
+
+	// Set in strict mode to log the missing translations as errors.
+	missingLogger loggers.Logger
 }
 
 // NewTranslator creates a new Translator for the given language bundle and configuration.
 func NewTranslator(b *i18n.Bundle, cfg config.Provider, logger loggers.Logger) Translator {
 	t := Translator{cfg: cfg, logger: logger, translateFuncs: make(map[string]translateFunc)}
+	if strict, _ := config.DecodeStrict(cfg.Get("strict")); strict.MissingTranslations {
+		t.missingLogger = helpers.NewDistinctLogger(logger)
+	}
 	t.initFuncs(b)
 	return t
 }
@@ -110,6 +116,10 @@ func (t Translator) initFuncs(bndl *i18n.Bundle) {
 				t.logger.Warnf("Failed to get translated string for language %q and ID %q: %s", currentLangStr, translationID, err)
 			}
 
+			if t.missingLogger != nil {
+				t.missingLogger.Errorf("Missing translation for language %q and ID %q", currentLangStr, translationID)
+			}
+
 			if t.cfg.GetBool("logI18nWarnings") {
 				i18nWarningLogger.Printf("i18n|MISSING_TRANSLATION|%s|%s", currentLangStr, translationID)
 			}
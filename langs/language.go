@@ -64,6 +64,12 @@ type Language struct {
 	// For internal use.
 	ContentDir string
 
+	// If set, the content directories of this language, the most important
+	// first. The content in them is merged by path, so a file in one replaces
+	// the file with the same name in the directories after it.
+	// For internal use.
+	ContentDirs []string
+
 	// Global config.
 	// For internal use.
 	Cfg config.Provider
@@ -133,9 +139,10 @@ func NewLanguage(lang string, cfg config.Provider) *Language {
 	}
 
 	l := &Language{
-		Lang:       lang,
-		ContentDir: cfg.GetString("contentDir"),
-		Cfg:        cfg, LocalCfg: localCfg,
+		Lang:        lang,
+		ContentDir:  cfg.GetString("contentDir"),
+		ContentDirs: cleanDirs(cfg.GetStringSlice("contentDirs")),
+		Cfg:         cfg, LocalCfg: localCfg,
 		Provider:      compositeConfig,
 		params:        params,
 		translator:    translator,
@@ -0,0 +1,253 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slug implements the language specific steps used when creating
+// the slugs in URLs: transliteration, stop word removal and length limits.
+package slug
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Config configures the slugs for a language.
+type Config struct {
+	// The names of the built-in transliteration tables to apply, in order,
+	// e.g. ["cyrillic"]. See Tables.
+	Transliterate []string
+
+	// Strings to replace before transliterating, e.g. "C++" = "cpp".
+	// These take precedence over the transliteration tables.
+	Replacements map[string]string
+
+	// Words to remove from every path element, matched case-insensitively.
+	StopWords []string
+
+	// The max length, in characters, of every path element.
+	// Longer elements are cut at the last word boundary.
+	// Zero means no limit.
+	MaxLength int
+}
+
+// DecodeConfig decodes the slugs configuration in cfg.
+func DecodeConfig(cfg config.Provider) (Config, error) {
+	var c Config
+	if !cfg.IsSet("slugs") {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(cfg.GetStringMap("slugs"), &c); err != nil {
+		return c, fmt.Errorf("failed to decode slugs config: %w", err)
+	}
+
+	if c.MaxLength < 0 {
+		return c, fmt.Errorf("failed to decode slugs config: maxLength must be positive, got %d", c.MaxLength)
+	}
+
+	return c, nil
+}
+
+// Slugger applies a slug Config.
+type Slugger struct {
+	replacer  *strings.Replacer
+	tables    []map[rune]string
+	stopWords map[string]bool
+	maxLength int
+}
+
+// New creates a new Slugger for cfg, nil if cfg is empty.
+func New(cfg Config) (*Slugger, error) {
+	if len(cfg.Transliterate) == 0 && len(cfg.Replacements) == 0 && len(cfg.StopWords) == 0 && cfg.MaxLength == 0 {
+		return nil, nil
+	}
+
+	s := &Slugger{maxLength: cfg.MaxLength}
+
+	for _, name := range cfg.Transliterate {
+		table, found := Tables[strings.ToLower(name)]
+		if !found {
+			names := make([]string, 0, len(Tables))
+			for name := range Tables {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("slugs: transliteration table %q not found, must be one of %s", name, strings.Join(names, ", "))
+		}
+		s.tables = append(s.tables, table)
+	}
+
+	if len(cfg.Replacements) > 0 {
+		// The longest match wins, the replacer tries the pairs in order.
+		olds := make([]string, 0, len(cfg.Replacements))
+		for old := range cfg.Replacements {
+			if old != "" {
+				olds = append(olds, old)
+			}
+		}
+		sort.Slice(olds, func(i, j int) bool {
+			if len(olds[i]) != len(olds[j]) {
+				return len(olds[i]) > len(olds[j])
+			}
+			return olds[i] < olds[j]
+		})
+		pairs := make([]string, 0, len(olds)*2)
+		for _, old := range olds {
+			pairs = append(pairs, old, cfg.Replacements[old])
+		}
+		s.replacer = strings.NewReplacer(pairs...)
+	}
+
+	if len(cfg.StopWords) > 0 {
+		s.stopWords = make(map[string]bool)
+		for _, w := range cfg.StopWords {
+			s.stopWords[strings.ToLower(w)] = true
+		}
+	}
+
+	return s, nil
+}
+
+// Transliterate applies the replacements and the transliteration tables
+// to str. Uppercase letters are transliterated to a capitalized string.
+func (s *Slugger) Transliterate(str string) string {
+	if s == nil {
+		return str
+	}
+
+	if s.replacer != nil {
+		str = s.replacer.Replace(str)
+	}
+
+	if len(s.tables) == 0 {
+		return str
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(str))
+
+	for _, r := range str {
+		if r < utf8.RuneSelf {
+			sb.WriteRune(r)
+			continue
+		}
+		if v, found := s.lookup(r); found {
+			sb.WriteString(v)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
+
+func (s *Slugger) lookup(r rune) (string, bool) {
+	lower := unicode.ToLower(r)
+	v, found := s.lookupLower(lower)
+	if !found {
+		// Try the letter without any accents, e.g. ά => α.
+		decomposed := norm.NFD.String(string(lower))
+		base, size := utf8.DecodeRuneInString(decomposed)
+		if size == len(decomposed) || base == lower {
+			return "", false
+		}
+		if v, found = s.lookupLower(base); !found {
+			return "", false
+		}
+	}
+	if r != lower && v != "" {
+		first, size := utf8.DecodeRuneInString(v)
+		v = string(unicode.ToUpper(first)) + v[size:]
+	}
+	return v, true
+}
+
+func (s *Slugger) lookupLower(r rune) (string, bool) {
+	for _, table := range s.tables {
+		if v, found := table[r]; found {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Shorten removes the stop words from every path element in the sanitized
+// path p, then cuts the elements longer than the max length. A file
+// extension is kept as is. Elements with only stop words are left alone.
+func (s *Slugger) Shorten(p string) string {
+	if s == nil || (len(s.stopWords) == 0 && s.maxLength == 0) {
+		return p
+	}
+
+	elements := strings.Split(p, "/")
+	for i, el := range elements {
+		elements[i] = s.shortenElement(el)
+	}
+
+	return strings.Join(elements, "/")
+}
+
+func (s *Slugger) shortenElement(el string) string {
+	if el == "" {
+		return el
+	}
+
+	stem, ext := splitExt(el)
+
+	if len(s.stopWords) > 0 {
+		words := strings.Split(stem, "-")
+		kept := words[:0:0]
+		for _, w := range words {
+			if !s.stopWords[strings.ToLower(w)] {
+				kept = append(kept, w)
+			}
+		}
+		if len(kept) > 0 && len(kept) < len(words) {
+			stem = strings.Join(kept, "-")
+		}
+	}
+
+	if s.maxLength > 0 && utf8.RuneCountInString(stem) > s.maxLength {
+		runes := []rune(stem)[:s.maxLength+1]
+		cut := s.maxLength
+		for i := s.maxLength; i > 0; i-- {
+			if runes[i] == '-' {
+				cut = i
+				break
+			}
+		}
+		stem = strings.TrimRight(string(runes[:cut]), "-")
+	}
+
+	return stem + ext
+}
+
+// splitExt splits el into its stem and a file extension, e.g. ".html".
+func splitExt(el string) (string, string) {
+	i := strings.LastIndexByte(el, '.')
+	if i <= 0 || len(el)-i-1 > 6 || len(el)-i-1 == 0 {
+		return el, ""
+	}
+	for _, r := range el[i+1:] {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return el, ""
+		}
+	}
+	return el[:i], el[i:]
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slug
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg := config.New()
+	conf, err := DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	s, err := New(conf)
+	c.Assert(err, qt.IsNil)
+	c.Assert(s, qt.IsNil)
+
+	cfg.Set("slugs", map[string]any{
+		"transliterate": []any{"cyrillic"},
+		"stopWords":     []any{"a", "the"},
+		"maxLength":     "20",
+	})
+	conf, err = DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf, qt.DeepEquals, Config{Transliterate: []string{"cyrillic"}, StopWords: []string{"a", "the"}, MaxLength: 20})
+
+	_, err = New(Config{Transliterate: []string{"klingon"}})
+	c.Assert(err, qt.ErrorMatches, `slugs: transliteration table "klingon" not found, must be one of arabic, cyrillic, german, greek, latin`)
+
+	cfg.Set("slugs", map[string]any{"maxLength": -1})
+	_, err = DecodeConfig(cfg)
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestTransliterate(t *testing.T) {
+	c := qt.New(t)
+
+	for _, test := range []struct {
+		tables []string
+		in     string
+		expect string
+	}{
+		{[]string{"cyrillic"}, "Привет, мир", "Privet, mir"},
+		{[]string{"cyrillic"}, "Щука и Ёж", "Shchuka i Yozh"},
+		{[]string{"cyrillic"}, "Їжак і ґанок", "Yizhak i ganok"},
+		{[]string{"greek"}, "Καλημέρα κόσμε", "Kalimera kosme"},
+		{[]string{"arabic"}, "مَرْحَبًا", "mrhba"},
+		{[]string{"arabic"}, "٢٠٢٢", "2022"},
+		{[]string{"german"}, "Grüße aus Köln", "Gruesse aus Koeln"},
+		{[]string{"german", "latin"}, "Über Smørrebrød", "Ueber Smorrebrod"},
+		{[]string{"latin"}, "Über Smørrebrød", "Uber Smorrebrod"},
+		{[]string{"latin"}, "Ærø Łódź", "Aero Lodz"},
+		// Scripts without a table are kept as is.
+		{[]string{"cyrillic"}, "你好 Привет", "你好 Privet"},
+	} {
+		s, err := New(Config{Transliterate: test.tables})
+		c.Assert(err, qt.IsNil)
+		c.Assert(s.Transliterate(test.in), qt.Equals, test.expect, qt.Commentf(test.in))
+	}
+
+	s, err := New(Config{
+		Transliterate: []string{"latin"},
+		Replacements:  map[string]string{"C++": "cpp", "C": "c-lang", "ø": "oe"},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(s.Transliterate("C++ and C in Ø"), qt.Equals, "cpp and c-lang in O")
+	c.Assert(s.Transliterate("smørrebrød"), qt.Equals, "smoerrebroed")
+
+	var nilSlugger *Slugger
+	c.Assert(nilSlugger.Transliterate("Привет"), qt.Equals, "Привет")
+}
+
+func TestShorten(t *testing.T) {
+	c := qt.New(t)
+
+	s, err := New(Config{StopWords: []string{"a", "The", "of"}, MaxLength: 12})
+	c.Assert(err, qt.IsNil)
+
+	for _, test := range []struct {
+		in     string
+		expect string
+	}{
+		{"the-lord-of-the-rings", "lord-rings"},
+		{"The-End", "End"},
+		{"/blog/a-tale-of-two-cities/", "/blog/tale-two/"},
+		{"/blog/a-tale-of-two-cities/index.html", "/blog/tale-two/index.html"},
+		{"a-tale-of-two-cities.html", "tale-two.html"},
+		{"supercalifragilistic", "supercalifra"},
+		{"the-of", "the-of"},
+		{"", ""},
+	} {
+		c.Assert(s.Shorten(test.in), qt.Equals, test.expect, qt.Commentf(test.in))
+	}
+}
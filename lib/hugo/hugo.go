@@ -0,0 +1,319 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hugo provides a stable API to build Hugo sites from Go programs,
+// e.g. to render previews or in serverless functions, without the hugo
+// command. By default the site is published to memory:
+//
+//	site := hugo.NewSite(hugo.Config{WorkingDir: "/path/to/project"})
+//	result, err := site.Build(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	for _, p := range result.Pages {
+//		b, err := result.ReadFile(p.Files[0])
+//		...
+//	}
+package hugo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/hugofs"
+	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/hugolib/buildhooks"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/spf13/afero"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+// Config configures a Site.
+type Config struct {
+	// The absolute path to the project directory with the site config,
+	// content, layouts etc. Defaults to the current working directory.
+	WorkingDir string
+
+	// The file system to read the project from. Defaults to the OS file
+	// system.
+	Source afero.Fs
+
+	// The file system to publish to. Defaults to a new in-memory file
+	// system per build; use afero.NewOsFs() to write to disk.
+	Destination afero.Fs
+
+	// The config file relative to WorkingDir. Defaults to config.toml,
+	// hugo.toml etc. and the config directory, as with the hugo command.
+	ConfigFilename string
+
+	// The build environment. Defaults to production.
+	Environment string
+
+	// Settings that override the config files, e.g. baseURL or buildDrafts.
+	Settings map[string]any
+
+	// Where to write the log. Discarded if nil.
+	LogOutput io.Writer
+
+	// Whether to log INFO messages.
+	Verbose bool
+
+	// The Go callbacks to run at the stages of a build.
+	BuildHooks *buildhooks.Hooks
+}
+
+// Site is a Hugo project to build. It is safe for concurrent use; the builds
+// run one at a time.
+type Site struct {
+	cfg Config
+	mu  sync.Mutex
+}
+
+// NewSite creates a new Site from cfg.
+func NewSite(cfg Config) *Site {
+	return &Site{cfg: cfg}
+}
+
+// Result is the result of a build.
+type Result struct {
+	// The pages in all languages, with the files published for each.
+	Pages []Page
+
+	// The number of WARNING messages logged.
+	Warnings int
+
+	// How long the build took.
+	Duration time.Duration
+
+	// The published files, rooted at the publish directory.
+	fs afero.Fs
+}
+
+// Page describes a page in the built site.
+type Page struct {
+	// The page kind, e.g. page, section or home.
+	Kind string
+
+	// The language code, e.g. en.
+	Lang string
+
+	Title        string
+	Section      string
+	Date         time.Time
+	Draft        bool
+	Permalink    string
+	RelPermalink string
+
+	// The source filename relative to the content dir, empty if not backed
+	// by a file.
+	Filename string
+
+	// The front matter params.
+	Params map[string]any
+
+	// The slash separated paths, relative to the publish directory, of the
+	// files published for the page in its output formats, e.g.
+	// posts/a/index.html.
+	Files []string
+}
+
+// Build builds the site from scratch. ctx is checked before the build
+// starts and when it is done; an ongoing build is not interrupted.
+func (s *Site) Build(ctx context.Context) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	h, dest, err := s.newHugoSites()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.Build(hugolib.BuildCfg{}); err != nil {
+		return nil, err
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	publishFs := afero.NewBasePathFs(dest, h.PathSpec.AbsPublishDir)
+
+	r := &Result{
+		Warnings: int(h.Log.LogCounters().WarnCounter.Count()),
+		Duration: time.Since(start),
+		fs:       publishFs,
+	}
+
+	basePath := h.PathSpec.BaseURL.Path()
+	for _, p := range h.Pages() {
+		r.Pages = append(r.Pages, newPage(p, basePath, publishFs))
+	}
+
+	return r, nil
+}
+
+func (s *Site) newHugoSites() (*hugolib.HugoSites, afero.Fs, error) {
+	cfg := s.cfg
+
+	if cfg.WorkingDir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, nil, err
+		}
+		cfg.WorkingDir = wd
+	}
+	if !filepath.IsAbs(cfg.WorkingDir) {
+		return nil, nil, fmt.Errorf("working dir must be absolute, got %q", cfg.WorkingDir)
+	}
+	if cfg.Source == nil {
+		cfg.Source = hugofs.Os
+	}
+	dest := cfg.Destination
+	if dest == nil {
+		dest = afero.NewMemMapFs()
+	}
+
+	logOutput := cfg.LogOutput
+	if logOutput == nil {
+		logOutput = ioutil.Discard
+	}
+	threshold := jww.LevelWarn
+	if cfg.Verbose {
+		threshold = jww.LevelInfo
+	}
+	logger := loggers.NewBasicLoggerForWriter(threshold, logOutput)
+
+	conf, _, err := hugolib.LoadConfig(
+		hugolib.ConfigSourceDescriptor{
+			Fs:           cfg.Source,
+			Logger:       logger,
+			Filename:     cfg.ConfigFilename,
+			Path:         cfg.WorkingDir,
+			WorkingDir:   cfg.WorkingDir,
+			AbsConfigDir: filepath.Join(cfg.WorkingDir, "config"),
+			Environment:  cfg.Environment,
+		},
+		func(c config.Provider) error {
+			for k, v := range cfg.Settings {
+				c.Set(k, v)
+			}
+			c.Set("workingDir", cfg.WorkingDir)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fs := hugofs.NewFromSourceAndDestination(cfg.Source, dest, conf)
+
+	h, err := hugolib.NewHugoSites(deps.DepsCfg{
+		Cfg:        conf,
+		Fs:         fs,
+		Logger:     logger,
+		BuildHooks: cfg.BuildHooks,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return h, dest, nil
+}
+
+func newPage(p page.Page, basePath string, publishFs afero.Fs) Page {
+	pp := Page{
+		Kind:         p.Kind(),
+		Lang:         p.Lang(),
+		Title:        p.Title(),
+		Section:      p.Section(),
+		Date:         p.Date(),
+		Draft:        p.Draft(),
+		Permalink:    p.Permalink(),
+		RelPermalink: p.RelPermalink(),
+		Params:       p.Params(),
+	}
+	if f := p.File(); f != nil && !f.IsZero() {
+		pp.Filename = filepath.ToSlash(f.Path())
+	}
+
+	for _, of := range p.OutputFormats() {
+		rel, err := url.PathUnescape(of.RelPermalink())
+		if err != nil {
+			continue
+		}
+		filename := strings.TrimPrefix(strings.TrimPrefix(rel, basePath), "/")
+		if filename == "" || strings.HasSuffix(filename, "/") {
+			filename += "index." + of.Format.MediaType.FirstSuffix.Suffix
+		}
+		// With multihost, the languages are published to their own directory.
+		for _, candidate := range []string{filename, pp.Lang + "/" + filename} {
+			if _, err := publishFs.Stat(filepath.FromSlash(candidate)); err == nil {
+				pp.Files = append(pp.Files, candidate)
+				break
+			}
+		}
+	}
+
+	return pp
+}
+
+// Fs returns the published files, rooted at the publish directory.
+func (r *Result) Fs() afero.Fs {
+	return r.fs
+}
+
+// ReadFile reads the published file with the slash separated name relative
+// to the publish directory, e.g. posts/a/index.html.
+func (r *Result) ReadFile(name string) ([]byte, error) {
+	return afero.ReadFile(r.fs, filepath.FromSlash(path.Clean("/"+name)))
+}
+
+// Files returns the slash separated paths of all published files relative to
+// the publish directory, sorted.
+func (r *Result) Files() ([]string, error) {
+	var files []string
+	err := afero.Walk(r.fs, "", func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			files = append(files, strings.TrimPrefix(filepath.ToSlash(filename), "/"))
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
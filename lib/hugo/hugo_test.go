@@ -0,0 +1,112 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hugo
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/spf13/afero"
+)
+
+func newSourceFs(c *qt.C, files map[string]string) afero.Fs {
+	fs := afero.NewMemMapFs()
+	for name, content := range files {
+		c.Assert(afero.WriteFile(fs, filepath.Join("/site", filepath.FromSlash(name)), []byte(content), 0666), qt.IsNil)
+	}
+	return fs
+}
+
+func TestBuild(t *testing.T) {
+	c := qt.New(t)
+
+	source := newSourceFs(c, map[string]string{
+		"config.toml": `
+baseURL = "https://example.org/docs/"
+disableKinds = ["taxonomy", "term", "sitemap", "robotsTXT"]
+[params]
+greeting = "Hello"
+`,
+		"content/posts/a.md":           "---\ntitle: A\ndate: 2022-06-01\n---\nContent A",
+		"content/posts/b.md":           "---\ntitle: B\ndraft: true\n---\nContent B",
+		"layouts/_default/single.html": "{{ site.Params.greeting }} {{ .Title }}: {{ .Content }}",
+		"layouts/_default/list.html":   "List {{ .Title }}",
+	})
+
+	var log bytes.Buffer
+	site := NewSite(Config{
+		WorkingDir: "/site",
+		Source:     source,
+		Settings:   map[string]any{"buildDrafts": true},
+		LogOutput:  &log,
+	})
+
+	r, err := site.Build(context.Background())
+	c.Assert(err, qt.IsNil)
+
+	pages := make(map[string]Page)
+	for _, p := range r.Pages {
+		pages[p.Kind+":"+p.Title] = p
+	}
+	c.Assert(pages, qt.HasLen, 4)
+
+	a := pages["page:A"]
+	c.Assert(a.Lang, qt.Equals, "en")
+	c.Assert(a.Section, qt.Equals, "posts")
+	c.Assert(a.Filename, qt.Equals, "posts/a.md")
+	c.Assert(a.RelPermalink, qt.Equals, "/docs/posts/a/")
+	c.Assert(a.Date.Year(), qt.Equals, 2022)
+	c.Assert(a.Files, qt.DeepEquals, []string{"posts/a/index.html"})
+	c.Assert(pages["page:B"].Draft, qt.IsTrue)
+	c.Assert(pages["section:Posts"].Files, qt.DeepEquals, []string{"posts/index.html", "posts/index.xml"})
+
+	b, err := r.ReadFile(a.Files[0])
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "Hello A: <p>Content A</p>\n")
+
+	files, err := r.Files()
+	c.Assert(err, qt.IsNil)
+	c.Assert(files, qt.Contains, "posts/b/index.html")
+	c.Assert(files, qt.Contains, "index.xml")
+
+	// Nothing is written to the source.
+	_, err = source.Stat("/site/public/posts/a/index.html")
+	c.Assert(err, qt.Not(qt.IsNil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = site.Build(ctx)
+	c.Assert(err, qt.Equals, context.Canceled)
+}
+
+func TestBuildError(t *testing.T) {
+	c := qt.New(t)
+
+	site := NewSite(Config{
+		WorkingDir: "/site",
+		Source: newSourceFs(c, map[string]string{
+			"config.toml":        `baseURL = "https://example.org/"`,
+			"layouts/index.html": "{{ .Foo }}",
+		}),
+	})
+
+	_, err := site.Build(context.Background())
+	c.Assert(err, qt.ErrorMatches, `(?s).*can't evaluate field Foo.*`)
+
+	_, err = NewSite(Config{WorkingDir: "site"}).Build(context.Background())
+	c.Assert(err, qt.ErrorMatches, `working dir must be absolute.*`)
+}
@@ -0,0 +1,348 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package citation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ParseBibTeX parses the entries in a BibTeX file.
+// @string macros are expanded, @comment and @preamble are skipped.
+func ParseBibTeX(src string) ([]Entry, error) {
+	p := &bibParser{src: src, macros: make(map[string]string)}
+	return p.parse()
+}
+
+type bibParser struct {
+	src    string
+	pos    int
+	macros map[string]string
+}
+
+func (p *bibParser) errorf(format string, args ...any) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	return fmt.Errorf("bibtex: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *bibParser) parse() ([]Entry, error) {
+	var entries []Entry
+
+	for {
+		i := strings.IndexByte(p.src[p.pos:], '@')
+		if i == -1 {
+			return entries, nil
+		}
+		p.pos += i + 1
+
+		typ := strings.ToLower(p.ident())
+		p.skipSpace()
+		if p.pos >= len(p.src) || (p.src[p.pos] != '{' && p.src[p.pos] != '(') {
+			return nil, p.errorf("expected { after @%s", typ)
+		}
+		closing := byte('}')
+		if p.src[p.pos] == '(' {
+			closing = ')'
+		}
+		p.pos++
+
+		switch typ {
+		case "comment", "preamble":
+			if err := p.skipBalanced(closing); err != nil {
+				return nil, err
+			}
+			continue
+		case "string":
+			fields, err := p.fields(closing)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range fields {
+				p.macros[k] = v
+			}
+			continue
+		}
+
+		p.skipSpace()
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != ',' && p.src[p.pos] != closing && !unicode.IsSpace(rune(p.src[p.pos])) {
+			p.pos++
+		}
+		key := p.src[start:p.pos]
+		if key == "" {
+			return nil, p.errorf("@%s without key", typ)
+		}
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			p.pos++
+		}
+
+		fields, err := p.fields(closing)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, newBibEntry(typ, key, fields))
+	}
+}
+
+// fields parses name = value pairs until closing.
+func (p *bibParser) fields(closing byte) (map[string]string, error) {
+	fields := make(map[string]string)
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unexpected end of file")
+		}
+		if p.src[p.pos] == closing {
+			p.pos++
+			return fields, nil
+		}
+		if p.src[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+
+		name := strings.ToLower(p.ident())
+		if name == "" {
+			return nil, p.errorf("expected a field name, got %q", p.src[p.pos])
+		}
+		p.skipSpace()
+		if p.pos >= len(p.src) || p.src[p.pos] != '=' {
+			return nil, p.errorf("expected = after %s", name)
+		}
+		p.pos++
+
+		var value strings.Builder
+		for {
+			p.skipSpace()
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			value.WriteString(v)
+			p.skipSpace()
+			if p.pos < len(p.src) && p.src[p.pos] == '#' {
+				p.pos++
+				continue
+			}
+			break
+		}
+		fields[name] = value.String()
+	}
+}
+
+func (p *bibParser) value() (string, error) {
+	if p.pos >= len(p.src) {
+		return "", p.errorf("unexpected end of file")
+	}
+	switch p.src[p.pos] {
+	case '{':
+		p.pos++
+		start := p.pos
+		if err := p.skipBalanced('}'); err != nil {
+			return "", err
+		}
+		return p.src[start : p.pos-1], nil
+	case '"':
+		p.pos++
+		start := p.pos
+		depth := 0
+		for ; p.pos < len(p.src); p.pos++ {
+			switch p.src[p.pos] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			case '"':
+				if depth == 0 {
+					p.pos++
+					return p.src[start : p.pos-1], nil
+				}
+			}
+		}
+		return "", p.errorf("unterminated string")
+	default:
+		word := p.ident()
+		if word == "" {
+			return "", p.errorf("expected a value, got %q", p.src[p.pos])
+		}
+		if v, found := p.macros[strings.ToLower(word)]; found {
+			return v, nil
+		}
+		return word, nil
+	}
+}
+
+// skipBalanced moves past closing, skipping nested braces.
+func (p *bibParser) skipBalanced(closing byte) error {
+	depth := 0
+	for ; p.pos < len(p.src); p.pos++ {
+		switch c := p.src[p.pos]; {
+		case c == '{' && closing != '{':
+			depth++
+		case c == closing && depth == 0:
+			p.pos++
+			return nil
+		case c == '}':
+			depth--
+		}
+	}
+	return p.errorf("unbalanced braces")
+}
+
+func (p *bibParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := rune(p.src[p.pos])
+		if !(unicode.IsLetter(c) || unicode.IsDigit(c) || strings.ContainsRune("_-:.+/", c)) {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *bibParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func newBibEntry(typ, key string, fields map[string]string) Entry {
+	e := Entry{
+		Key:       key,
+		Type:      typ,
+		Title:     cleanTeX(fields["title"]),
+		Authors:   parseBibNames(fields["author"]),
+		Editors:   parseBibNames(fields["editor"]),
+		Year:      cleanTeX(fields["year"]),
+		Publisher: cleanTeX(fields["publisher"]),
+		Volume:    cleanTeX(fields["volume"]),
+		Issue:     cleanTeX(fields["number"]),
+		Pages:     strings.ReplaceAll(cleanTeX(fields["pages"]), "–", "-"),
+		DOI:       strings.TrimSpace(fields["doi"]),
+		URL:       strings.TrimSpace(fields["url"]),
+	}
+	if e.Year == "" {
+		if d := cleanTeX(fields["date"]); len(d) >= 4 {
+			e.Year = d[:4]
+		}
+	}
+	for _, k := range []string{"journal", "journaltitle", "booktitle"} {
+		if v := cleanTeX(fields[k]); v != "" {
+			e.Container = v
+			break
+		}
+	}
+	if e.Publisher == "" {
+		e.Publisher = cleanTeX(fields["institution"])
+	}
+	return e
+}
+
+var bibAndRe = regexp.MustCompile(`\s+and\s+`)
+
+// parseBibNames parses names separated by "and", written as "Given Family"
+// or "Family, Given". Names in braces are kept as is.
+func parseBibNames(s string) []Name {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+
+	var names []Name
+	for _, part := range splitTopLevel(s, bibAndRe) {
+		part = strings.TrimSpace(part)
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") && strings.Count(part, "{") == 1 {
+			names = append(names, Name{Literal: cleanTeX(part)})
+			continue
+		}
+		if i := strings.Index(part, ","); i != -1 {
+			names = append(names, Name{Family: cleanTeX(part[:i]), Given: cleanTeX(part[i+1:])})
+			continue
+		}
+		words := strings.Fields(part)
+		if len(words) == 1 {
+			names = append(names, Name{Family: cleanTeX(words[0])})
+			continue
+		}
+		// Lower case particles, e.g. van, belong to the family name.
+		i := len(words) - 1
+		for i > 1 && isLowerWord(words[i-1]) {
+			i--
+		}
+		names = append(names, Name{Family: cleanTeX(strings.Join(words[i:], " ")), Given: cleanTeX(strings.Join(words[:i], " "))})
+	}
+	return names
+}
+
+func isLowerWord(s string) bool {
+	for _, r := range s {
+		return unicode.IsLower(r)
+	}
+	return false
+}
+
+// splitTopLevel splits s by re outside of braces.
+func splitTopLevel(s string, re *regexp.Regexp) []string {
+	var parts []string
+	last := 0
+	for _, loc := range re.FindAllStringIndex(s, -1) {
+		if strings.Count(s[:loc[0]], "{") != strings.Count(s[:loc[0]], "}") {
+			continue
+		}
+		parts = append(parts, s[last:loc[0]])
+		last = loc[1]
+	}
+	return append(parts, s[last:])
+}
+
+var (
+	texAccentRe  = regexp.MustCompile(`\\([\x60'^"~=.cuvH])\s*\{?\\?([a-zA-Z])\}?`)
+	texCommandRe = regexp.MustCompile(`\\[a-zA-Z]+\s*`)
+
+	texAccents = map[string]string{
+		"`": "̀", "'": "́", "^": "̂", "~": "̃", "=": "̄",
+		"u": "̆", ".": "̇", "\"": "̈", "H": "̋", "v": "̌", "c": "̧",
+	}
+
+	texReplacer = strings.NewReplacer(
+		`\&`, "&", `\%`, "%", `\$`, "$", `\_`, "_", `\#`, "#",
+		`\LaTeX`, "LaTeX", `\TeX`, "TeX",
+		"---", "—", "--", "–", "``", "“", "''", "”", "~", " ",
+	)
+)
+
+// cleanTeX converts the common TeX markup in s to plain text.
+func cleanTeX(s string) string {
+	s = texAccentRe.ReplaceAllStringFunc(s, func(m string) string {
+		sm := texAccentRe.FindStringSubmatch(m)
+		return composeAccent(sm[2], texAccents[sm[1]])
+	})
+	s = texReplacer.Replace(s)
+	s = texCommandRe.ReplaceAllString(s, "")
+	s = strings.NewReplacer("{", "", "}", "").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// composeAccent returns the letter with the combining accent, composed if
+// possible, e.g. ä for a and U+0308.
+func composeAccent(letter, accent string) string {
+	return norm.NFC.String(letter + accent)
+}
@@ -0,0 +1,353 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package citation holds the bibliography entries cited in the content and
+// their default formatting.
+package citation
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// The citation styles.
+const (
+	// StyleAuthorDate cites as (Doe 2020) and sorts the bibliography by author.
+	StyleAuthorDate = "author-date"
+
+	// StyleNumeric cites as [1] and numbers the bibliography in citation order.
+	StyleNumeric = "numeric"
+)
+
+// Name is the name of an author or editor.
+type Name struct {
+	Family string
+	Given  string
+
+	// A name that is not split, e.g. of an organization.
+	Literal string
+}
+
+func (n Name) String() string {
+	if n.Literal != "" {
+		return n.Literal
+	}
+	return strings.TrimSpace(n.Given + " " + n.Family)
+}
+
+// family returns the family name, or the literal name.
+func (n Name) family() string {
+	if n.Literal != "" {
+		return n.Literal
+	}
+	return n.Family
+}
+
+// Entry is a bibliography entry.
+type Entry struct {
+	// The citation key, e.g. doe2020.
+	Key string
+
+	// The type of work, e.g. article or book.
+	Type string
+
+	Title   string
+	Authors []Name
+	Editors []Name
+	Year    string
+
+	// The journal, book or proceedings the work is part of.
+	Container string
+
+	Publisher string
+	Volume    string
+	Issue     string
+	Pages     string
+	DOI       string
+	URL       string
+}
+
+// Link returns the URL of the entry, the DOI resolver URL if it has a DOI.
+func (e *Entry) Link() string {
+	if e.DOI != "" {
+		return "https://doi.org/" + e.DOI
+	}
+	return e.URL
+}
+
+// Bibliography holds the entries that can be cited.
+type Bibliography struct {
+	// The citation style, author-date or numeric.
+	Style string
+
+	entries map[string]*Entry
+}
+
+// New creates a new Bibliography in the given style with entries, where
+// later entries replace earlier ones with the same key.
+func New(style string, entries ...[]Entry) (*Bibliography, error) {
+	switch style {
+	case "":
+		style = StyleAuthorDate
+	case StyleAuthorDate, StyleNumeric:
+	default:
+		return nil, fmt.Errorf("citation style must be one of %s or %s, got %q", StyleAuthorDate, StyleNumeric, style)
+	}
+
+	b := &Bibliography{Style: style, entries: make(map[string]*Entry)}
+	for _, ee := range entries {
+		for i := range ee {
+			e := ee[i]
+			if e.Key == "" {
+				return nil, fmt.Errorf("bibliography entry %q has no key", e.Title)
+			}
+			b.entries[e.Key] = &e
+		}
+	}
+
+	return b, nil
+}
+
+// Get returns the entry with key, nil if not found.
+func (b *Bibliography) Get(key string) *Entry {
+	if b == nil {
+		return nil
+	}
+	return b.entries[key]
+}
+
+// Len returns the number of entries.
+func (b *Bibliography) Len() int {
+	if b == nil {
+		return 0
+	}
+	return len(b.entries)
+}
+
+// Item is a cited entry in a citation, e.g. "see @doe2020, p. 33".
+type Item struct {
+	Key string
+
+	// The cited entry, nil if not in the bibliography.
+	Entry *Entry
+
+	// The position of the entry in the bibliography of the document when
+	// numbered, starting at 1.
+	Number int
+
+	// The text before the key, e.g. "see".
+	Prefix string
+
+	// The text after the key, e.g. "p. 33".
+	Locator string
+
+	// Whether to omit the author, written as -@key.
+	SuppressAuthor bool
+}
+
+// Reference is an entry in the bibliography of a document.
+type Reference struct {
+	*Entry
+
+	// The position in the bibliography of the document, starting at 1.
+	Number int
+
+	// The ID to link to, e.g. ref-doe2020.
+	ID string
+}
+
+// ID returns the ID of the bibliography entry of key in a document.
+func ID(key string) string {
+	return "ref-" + key
+}
+
+// InText formats items as an in-text citation in HTML, linking to the
+// bibliography entries.
+func (b *Bibliography) InText(items []Item) string {
+	var sb strings.Builder
+
+	numeric := b.Style == StyleNumeric
+	if numeric {
+		sb.WriteString("[")
+	} else {
+		sb.WriteString("(")
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		if item.Prefix != "" {
+			sb.WriteString(html.EscapeString(item.Prefix))
+			sb.WriteString(" ")
+		}
+		if item.Entry == nil {
+			sb.WriteString("<strong>")
+			sb.WriteString(html.EscapeString(item.Key))
+			sb.WriteString("?</strong>")
+		} else {
+			var label string
+			switch {
+			case numeric:
+				label = fmt.Sprint(item.Number)
+			case item.SuppressAuthor:
+				label = item.Entry.Year
+			default:
+				label = strings.TrimSpace(shortAuthors(item.Entry) + " " + item.Entry.Year)
+			}
+			fmt.Fprintf(&sb, `<a href="#%s">%s</a>`, html.EscapeString(ID(item.Key)), html.EscapeString(label))
+		}
+		if item.Locator != "" {
+			sb.WriteString(", ")
+			sb.WriteString(html.EscapeString(item.Locator))
+		}
+	}
+
+	if numeric {
+		sb.WriteString("]")
+	} else {
+		sb.WriteString(")")
+	}
+
+	return sb.String()
+}
+
+// References returns the entries cited in items as they appear in the
+// bibliography: in order of first citation if numeric, else by author
+// and year.
+func (b *Bibliography) References(items []Item) []Reference {
+	var refs []Reference
+	seen := make(map[string]bool)
+	for _, item := range items {
+		if item.Entry == nil || seen[item.Key] {
+			continue
+		}
+		seen[item.Key] = true
+		refs = append(refs, Reference{Entry: item.Entry, Number: item.Number, ID: ID(item.Key)})
+	}
+
+	if b.Style != StyleNumeric {
+		sort.SliceStable(refs, func(i, j int) bool {
+			ai, aj := strings.ToLower(sortAuthors(refs[i].Entry)), strings.ToLower(sortAuthors(refs[j].Entry))
+			if ai != aj {
+				return ai < aj
+			}
+			return refs[i].Year < refs[j].Year
+		})
+	}
+
+	return refs
+}
+
+// Format formats the reference in HTML, e.g.
+// Doe, J., & Roe, R. (2020). Title. <em>Journal</em>, 3(2), 1–10.
+func (r Reference) Format() string {
+	e := r.Entry
+	var sb strings.Builder
+
+	if len(e.Authors) > 0 {
+		sb.WriteString(html.EscapeString(longAuthors(e.Authors)))
+		sb.WriteString(" ")
+	}
+	if e.Year != "" {
+		sb.WriteString("(" + html.EscapeString(e.Year) + "). ")
+	}
+
+	if e.Title != "" {
+		title := html.EscapeString(strings.TrimSuffix(e.Title, "."))
+		if e.Container == "" {
+			title = "<em>" + title + "</em>"
+		}
+		sb.WriteString(title + ". ")
+	}
+
+	if e.Container != "" {
+		sb.WriteString("<em>" + html.EscapeString(e.Container) + "</em>")
+		if e.Volume != "" {
+			sb.WriteString(", " + html.EscapeString(e.Volume))
+			if e.Issue != "" {
+				sb.WriteString("(" + html.EscapeString(e.Issue) + ")")
+			}
+		}
+		if e.Pages != "" {
+			sb.WriteString(", " + html.EscapeString(strings.ReplaceAll(e.Pages, "-", "–")))
+		}
+		sb.WriteString(". ")
+	}
+
+	if e.Publisher != "" {
+		sb.WriteString(html.EscapeString(e.Publisher) + ". ")
+	}
+
+	if link := e.Link(); link != "" {
+		fmt.Fprintf(&sb, `<a href="%s">%s</a>`, html.EscapeString(link), html.EscapeString(link))
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// shortAuthors returns e.g. Doe, Doe and Roe or Doe et al.
+func shortAuthors(e *Entry) string {
+	names := e.Authors
+	if len(names) == 0 {
+		names = e.Editors
+	}
+	switch len(names) {
+	case 0:
+		return e.Title
+	case 1:
+		return names[0].family()
+	case 2:
+		return names[0].family() + " and " + names[1].family()
+	default:
+		return names[0].family() + " et al."
+	}
+}
+
+func sortAuthors(e *Entry) string {
+	var s []string
+	for _, n := range e.Authors {
+		s = append(s, n.family()+" "+n.Given)
+	}
+	if len(s) == 0 {
+		return e.Title
+	}
+	return strings.Join(s, ", ")
+}
+
+// longAuthors returns e.g. Doe, J., & Roe, R.
+func longAuthors(names []Name) string {
+	formatted := make([]string, len(names))
+	for i, n := range names {
+		if n.Literal != "" || n.Given == "" {
+			formatted[i] = n.family()
+			continue
+		}
+		var initials []string
+		for _, g := range strings.FieldsFunc(n.Given, func(r rune) bool { return r == ' ' || r == '-' }) {
+			initials = append(initials, string([]rune(g)[0])+".")
+		}
+		formatted[i] = n.Family + ", " + strings.Join(initials, " ")
+	}
+
+	switch len(formatted) {
+	case 1:
+		return formatted[0]
+	case 2:
+		return formatted[0] + ", & " + formatted[1]
+	default:
+		return strings.Join(formatted[:len(formatted)-1], ", ") + ", & " + formatted[len(formatted)-1]
+	}
+}
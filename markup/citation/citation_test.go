@@ -0,0 +1,145 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package citation
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestParseBibTeX(t *testing.T) {
+	c := qt.New(t)
+
+	entries, err := ParseBibTeX(`
+@comment{ignored {nested} }
+@string{ jgo = "Journal of {Go}" }
+
+@article{doe2020,
+  author  = {Doe, Jane and John van der Roe and {ACME Inc.}},
+  title   = {On {G}ophers \& M{\"u}nchen},
+  journal = jgo # " Studies",
+  year    = 2020,
+  volume  = "3", number = {2},
+  pages   = {1--10},
+  doi     = {10.1000/xyz},
+}
+
+@Book(knuth1984,
+  author = "Donald E. Knuth",
+  title = "The {\TeX}book",
+  publisher = {Addison-Wesley},
+  date = {1984-01-01}
+)
+`)
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.DeepEquals, []Entry{
+		{
+			Key:       "doe2020",
+			Type:      "article",
+			Title:     "On Gophers & München",
+			Authors:   []Name{{Family: "Doe", Given: "Jane"}, {Family: "van der Roe", Given: "John"}, {Literal: "ACME Inc."}},
+			Year:      "2020",
+			Container: "Journal of Go Studies",
+			Volume:    "3",
+			Issue:     "2",
+			Pages:     "1-10",
+			DOI:       "10.1000/xyz",
+		},
+		{
+			Key:       "knuth1984",
+			Type:      "book",
+			Title:     "The TeXbook",
+			Authors:   []Name{{Family: "Knuth", Given: "Donald E."}},
+			Year:      "1984",
+			Publisher: "Addison-Wesley",
+		},
+	})
+
+	_, err = ParseBibTeX("@article{a, title = {unclosed}")
+	c.Assert(err, qt.ErrorMatches, "bibtex: line 1: unexpected end of file")
+}
+
+func TestDecodeCSLJSON(t *testing.T) {
+	c := qt.New(t)
+
+	entries, err := DecodeCSLJSON([]any{
+		map[string]any{
+			"id":              "doe2020",
+			"type":            "article-journal",
+			"title":           "On Gophers",
+			"author":          []any{map[string]any{"family": "Doe", "given": "Jane"}},
+			"issued":          map[string]any{"date-parts": []any{[]any{2020, 3}}},
+			"container-title": "Journal of Go",
+			"URL":             "https://example.org/doe",
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries, qt.DeepEquals, []Entry{
+		{
+			Key:       "doe2020",
+			Type:      "article-journal",
+			Title:     "On Gophers",
+			Authors:   []Name{{Family: "Doe", Given: "Jane"}},
+			Year:      "2020",
+			Container: "Journal of Go",
+			URL:       "https://example.org/doe",
+		},
+	})
+
+	entries, err = DecodeCSLJSON(map[string]any{
+		"roe": map[string]any{"title": "Roe", "issued": map[string]any{"raw": "2019-05"}},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(entries[0].Key, qt.Equals, "roe")
+	c.Assert(entries[0].Year, qt.Equals, "2019")
+
+	_, err = DecodeCSLJSON([]any{map[string]any{"title": "No ID"}})
+	c.Assert(err, qt.ErrorMatches, ".*missing id")
+}
+
+func TestBibliography(t *testing.T) {
+	c := qt.New(t)
+
+	entries := []Entry{
+		{Key: "roe", Title: "Second", Authors: []Name{{Family: "Roe", Given: "Richard"}}, Year: "2019"},
+		{Key: "doe", Title: "First", Authors: []Name{{Family: "Doe", Given: "Jane Ann"}, {Family: "Moe", Given: "M."}}, Year: "2020", Container: "Journal", Volume: "3", Issue: "2", Pages: "1-10", DOI: "10/x"},
+	}
+
+	b, err := New("", entries)
+	c.Assert(err, qt.IsNil)
+	c.Assert(b.Style, qt.Equals, StyleAuthorDate)
+	c.Assert(b.Len(), qt.Equals, 2)
+
+	items := []Item{
+		{Key: "roe", Entry: b.Get("roe"), Number: 1, Prefix: "see", Locator: "p. 3"},
+		{Key: "doe", Entry: b.Get("doe"), Number: 2, SuppressAuthor: true},
+		{Key: "nope"},
+	}
+	c.Assert(b.InText(items), qt.Equals, `(see <a href="#ref-roe">Roe 2019</a>, p. 3; <a href="#ref-doe">2020</a>; <strong>nope?</strong>)`)
+
+	refs := b.References(append(items, items[0]))
+	c.Assert(refs, qt.HasLen, 2)
+	c.Assert(refs[0].ID, qt.Equals, "ref-doe")
+	c.Assert(refs[0].Format(), qt.Equals, `Doe, J. A., &amp; Moe, M. (2020). First. <em>Journal</em>, 3(2), 1–10. <a href="https://doi.org/10/x">https://doi.org/10/x</a>`)
+	c.Assert(refs[1].Format(), qt.Equals, `Roe, R. (2019). <em>Second</em>.`)
+
+	b, err = New(StyleNumeric, entries)
+	c.Assert(err, qt.IsNil)
+	c.Assert(b.InText(items[:2]), qt.Equals, `[see <a href="#ref-roe">1</a>, p. 3; <a href="#ref-doe">2</a>]`)
+	c.Assert(b.References(items)[0].Number, qt.Equals, 1)
+
+	_, err = New("harvard")
+	c.Assert(err, qt.Not(qt.IsNil))
+}
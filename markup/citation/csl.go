@@ -0,0 +1,114 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package citation
+
+import (
+	"fmt"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/spf13/cast"
+)
+
+// DecodeCSLJSON decodes the entries in v, a list of CSL-JSON items as
+// unmarshaled from JSON, YAML or TOML, or a map with such items by key.
+func DecodeCSLJSON(v any) ([]Entry, error) {
+	var items []any
+	switch vv := v.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		items = vv
+	case []map[string]any:
+		for _, m := range vv {
+			items = append(items, m)
+		}
+	default:
+		m, err := maps.ToStringMapE(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode CSL-JSON: expected a list of items, got %T", v)
+		}
+		for k, vv := range m {
+			mm, err := maps.ToStringMapE(vv)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode CSL-JSON item %q: %w", k, err)
+			}
+			if _, found := mm["id"]; !found {
+				mm["id"] = k
+			}
+			items = append(items, mm)
+		}
+	}
+
+	entries := make([]Entry, 0, len(items))
+	for i, item := range items {
+		m, err := maps.ToStringMapE(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode CSL-JSON item %d: %w", i, err)
+		}
+		e := Entry{
+			Key:       cast.ToString(m["id"]),
+			Type:      cast.ToString(m["type"]),
+			Title:     cast.ToString(m["title"]),
+			Authors:   cslNames(m["author"]),
+			Editors:   cslNames(m["editor"]),
+			Year:      cslYear(m["issued"]),
+			Container: cast.ToString(m["container-title"]),
+			Publisher: cast.ToString(m["publisher"]),
+			Volume:    cast.ToString(m["volume"]),
+			Issue:     cast.ToString(m["issue"]),
+			Pages:     cast.ToString(m["page"]),
+			DOI:       cast.ToString(m["DOI"]),
+			URL:       cast.ToString(m["URL"]),
+		}
+		if e.Key == "" {
+			return nil, fmt.Errorf("failed to decode CSL-JSON item %d: missing id", i)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+func cslNames(v any) []Name {
+	var names []Name
+	for _, n := range cast.ToSlice(v) {
+		m, err := maps.ToStringMapE(n)
+		if err != nil {
+			continue
+		}
+		names = append(names, Name{
+			Family:  cast.ToString(m["family"]),
+			Given:   cast.ToString(m["given"]),
+			Literal: cast.ToString(m["literal"]),
+		})
+	}
+	return names
+}
+
+// cslYear returns the year of a CSL date, e.g. {"date-parts": [[2020, 3]]}.
+func cslYear(v any) string {
+	m, err := maps.ToStringMapE(v)
+	if err != nil {
+		return ""
+	}
+	if parts := cast.ToSlice(m["date-parts"]); len(parts) > 0 {
+		if first := cast.ToSlice(parts[0]); len(first) > 0 {
+			return cast.ToString(first[0])
+		}
+	}
+	if raw := cast.ToString(m["raw"]); len(raw) >= 4 {
+		return raw[:4]
+	}
+	return cast.ToString(m["literal"])
+}
@@ -20,9 +20,12 @@ import (
 	"github.com/gohugoio/hugo/common/loggers"
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/identity"
+	"github.com/gohugoio/hugo/markup/citation"
 	"github.com/gohugoio/hugo/markup/converter/hooks"
+	"github.com/gohugoio/hugo/markup/glossary"
 	"github.com/gohugoio/hugo/markup/highlight"
 	"github.com/gohugoio/hugo/markup/markup_config"
+	"github.com/gohugoio/hugo/markup/rawhtml"
 	"github.com/gohugoio/hugo/markup/tableofcontents"
 	"github.com/spf13/afero"
 )
@@ -135,6 +138,27 @@ type RenderContext struct {
 
 	// GerRenderer provides hook renderers on demand.
 	GetRenderer hooks.GetRendererFunc
+
+	// The glossary terms to mark up, if enabled.
+	Glossary *glossary.Glossary
+
+	// The entries the citations are resolved against, if enabled.
+	Bibliography *citation.Bibliography
+
+	// The policy for the raw HTML, if configured for the document.
+	RawHTML *rawhtml.Policy
+
+	// Called for the raw HTML removed by the RawHTML policy.
+	RawHTMLRemoved func(rawhtml.Removal)
+
+	// The absolute filenames of the pandoc Lua filters to run, if any.
+	LuaFilters []string
+
+	// The absolute filename of the CSL style for pandoc's citeproc, if any.
+	CSL string
+
+	// The locale of the citations for pandoc's citeproc, if any.
+	CitationLocale string
 }
 
 var FeatureRenderHooks = identity.NewPathIdentity("markup", "renderingHooks")
@@ -20,6 +20,7 @@ import (
 	"github.com/gohugoio/hugo/common/text"
 	"github.com/gohugoio/hugo/common/types/hstring"
 	"github.com/gohugoio/hugo/identity"
+	"github.com/gohugoio/hugo/markup/citation"
 	"github.com/gohugoio/hugo/markup/internal/attributes"
 )
 
@@ -91,6 +92,44 @@ type HeadingRenderer interface {
 	identity.Provider
 }
 
+// CitationContext contains accessors to all attributes that a
+// CitationRenderer can use to render a citation, e.g. [see @doe2020, p. 3].
+type CitationContext interface {
+	// Page is the page containing the citation.
+	Page() any
+	// Items are the cited entries.
+	Items() []citation.Item
+	// Style is the configured citation style, author-date or numeric.
+	Style() string
+	// Text is the citation rendered in the configured style.
+	Text() hstring.RenderedString
+}
+
+// CitationRenderer describes a uniquely identifiable rendering hook.
+type CitationRenderer interface {
+	RenderCitation(w io.Writer, ctx CitationContext) error
+	identity.Provider
+}
+
+// BibliographyContext contains accessors to all attributes that a
+// BibliographyRenderer can use to render the bibliography of a page.
+type BibliographyContext interface {
+	// Page is the page containing the citations.
+	Page() any
+	// References are the cited entries in bibliography order.
+	References() []citation.Reference
+	// Style is the configured citation style, author-date or numeric.
+	Style() string
+	// Text is the bibliography rendered in the configured style.
+	Text() hstring.RenderedString
+}
+
+// BibliographyRenderer describes a uniquely identifiable rendering hook.
+type BibliographyRenderer interface {
+	RenderBibliography(w io.Writer, ctx BibliographyContext) error
+	identity.Provider
+}
+
 // ElementPositionResolver provides a way to resolve the start Position
 // of a markdown element in the original source document.
 // This may be both slow and approximate, so should only be
@@ -106,6 +145,8 @@ const (
 	ImageRendererType
 	HeadingRendererType
 	CodeBlockRendererType
+	CitationRendererType
+	BibliographyRendererType
 )
 
 type GetRendererFunc func(t RendererType, id any) any
@@ -0,0 +1,266 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package glossary holds the glossary terms to mark up in the content.
+package glossary
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Term is a glossary term.
+type Term struct {
+	// The term as written in the content, e.g. HTML.
+	Term string
+
+	// The expanded form or a short definition of the term, e.g.
+	// "HyperText Markup Language", rendered as the title of an abbr element.
+	Title string
+
+	// The URL to the definition of the term.
+	URL string
+
+	// Whether the term is only matched with the same case.
+	CaseSensitive bool
+}
+
+// Match is a term found in a text.
+type Match struct {
+	Term *Term
+
+	// The byte offsets of the term in the text.
+	Start, End int
+}
+
+// Glossary finds the glossary terms in text.
+type Glossary struct {
+	// Keyed by the term, lower case if not case sensitive.
+	sensitive   map[string]*Term
+	insensitive map[string]*Term
+
+	sensitiveRe   *regexp.Regexp
+	insensitiveRe *regexp.Regexp
+
+	// Terms with this URL are not matched.
+	excludeURL string
+}
+
+// New creates a new Glossary with terms, nil if there are no terms.
+func New(terms []Term) (*Glossary, error) {
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	g := &Glossary{
+		sensitive:   make(map[string]*Term),
+		insensitive: make(map[string]*Term),
+	}
+
+	for i := range terms {
+		t := &terms[i]
+		t.Term = strings.TrimSpace(t.Term)
+		if t.Term == "" {
+			return nil, fmt.Errorf("glossary: term %d is empty", i+1)
+		}
+		if t.CaseSensitive {
+			g.sensitive[t.Term] = t
+		} else {
+			g.insensitive[strings.ToLower(t.Term)] = t
+		}
+	}
+
+	g.sensitiveRe = compile(g.sensitive, "")
+	g.insensitiveRe = compile(g.insensitive, "(?i)")
+
+	return g, nil
+}
+
+// compile creates a regexp matching any of the terms, the longest first.
+func compile(terms map[string]*Term, flags string) *regexp.Regexp {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(terms))
+	for _, t := range terms {
+		keys = append(keys, regexp.QuoteMeta(t.Term))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+
+	return regexp.MustCompile(flags + "(?:" + strings.Join(keys, "|") + ")")
+}
+
+// Exclude returns a copy of g which does not match the terms defined at url,
+// e.g. to not link a term to the page it is rendered on.
+func (g *Glossary) Exclude(url string) *Glossary {
+	if g == nil {
+		return nil
+	}
+	gc := *g
+	gc.excludeURL = url
+	return &gc
+}
+
+// FindAll returns the terms in s, as whole words, ordered by position.
+func (g *Glossary) FindAll(s []byte) []Match {
+	if g == nil {
+		return nil
+	}
+
+	var matches []Match
+	matches = g.findAll(matches, s, g.sensitiveRe, func(m []byte) *Term {
+		return g.sensitive[string(m)]
+	})
+	matches = g.findAll(matches, s, g.insensitiveRe, func(m []byte) *Term {
+		return g.insensitive[strings.ToLower(string(m))]
+	})
+
+	if len(matches) < 2 {
+		return matches
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+
+	// Remove overlapping matches, keeping the first and longest.
+	result := matches[:1]
+	for _, m := range matches[1:] {
+		if m.Start >= result[len(result)-1].End {
+			result = append(result, m)
+		}
+	}
+
+	return result
+}
+
+func (g *Glossary) findAll(matches []Match, s []byte, re *regexp.Regexp, lookup func(m []byte) *Term) []Match {
+	if re == nil {
+		return matches
+	}
+
+	for offset := 0; offset < len(s); {
+		loc := re.FindIndex(s[offset:])
+		if loc == nil {
+			break
+		}
+		start, end := offset+loc[0], offset+loc[1]
+		if isWordBoundary(s, start, end) {
+			if t := lookup(s[start:end]); t != nil && (g.excludeURL == "" || t.URL != g.excludeURL) {
+				matches = append(matches, Match{Term: t, Start: start, End: end})
+			}
+			offset = end
+			continue
+		}
+		_, size := utf8.DecodeRune(s[start:])
+		offset = start + size
+	}
+
+	return matches
+}
+
+func isWordBoundary(s []byte, start, end int) bool {
+	if start > 0 {
+		r, _ := utf8.DecodeLastRune(s[:start])
+		if isWordRune(r) {
+			return false
+		}
+	}
+	if end < len(s) {
+		r, _ := utf8.DecodeRune(s[end:])
+		if isWordRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// DecodeTerms decodes the terms in a data file, either a list of terms or
+// a map of terms to their titles or to terms without the term set.
+// Terms not setting caseSensitive default to caseSensitive.
+func DecodeTerms(in any, caseSensitive bool) ([]Term, error) {
+	type term struct {
+		Term          string
+		Title         string
+		URL           string
+		CaseSensitive *bool
+	}
+
+	var decoded []term
+
+	decode := func(v any, name string) error {
+		var t term
+		if s, ok := v.(string); ok {
+			t.Title = s
+		} else if err := mapstructure.WeakDecode(v, &t); err != nil {
+			return fmt.Errorf("failed to decode glossary term: %w", err)
+		}
+		if t.Term == "" {
+			t.Term = name
+		}
+		decoded = append(decoded, t)
+		return nil
+	}
+
+	switch v := in.(type) {
+	case nil:
+	case []any:
+		for _, vv := range v {
+			if err := decode(vv, ""); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := decode(v[k], k); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("glossary: unsupported data type %T", in)
+	}
+
+	terms := make([]Term, len(decoded))
+	for i, t := range decoded {
+		terms[i] = Term{Term: t.Term, Title: t.Title, URL: t.URL, CaseSensitive: caseSensitive}
+		if t.CaseSensitive != nil {
+			terms[i].CaseSensitive = *t.CaseSensitive
+		}
+	}
+
+	return terms, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package glossary
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestFindAll(t *testing.T) {
+	c := qt.New(t)
+
+	g, err := New([]Term{
+		{Term: "Go"},
+		{Term: "Go Modules"},
+		{Term: "CSS", CaseSensitive: true},
+		{Term: "C++", URL: "/cpp/"},
+	})
+	c.Assert(err, qt.IsNil)
+
+	find := func(g *Glossary, s string) []string {
+		var terms []string
+		for _, m := range g.FindAll([]byte(s)) {
+			terms = append(terms, s[m.Start:m.End])
+		}
+		return terms
+	}
+
+	c.Assert(find(g, "Use go modules, not Gopher Go_ or css, but CSS and C++."), qt.DeepEquals, []string{"go modules", "CSS", "C++"})
+	c.Assert(find(g, "Go, Go! Ågo Go"), qt.DeepEquals, []string{"Go", "Go", "Go"})
+	c.Assert(find(g.Exclude("/cpp/"), "C++ and Go"), qt.DeepEquals, []string{"Go"})
+	c.Assert(find(nil, "Go"), qt.IsNil)
+
+	_, err = New([]Term{{Term: " "}})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestDecodeTerms(t *testing.T) {
+	c := qt.New(t)
+
+	terms, err := DecodeTerms(map[string]any{
+		"HTML": "HyperText Markup Language",
+		"Go": map[string]any{
+			"title":         "The Go programming language",
+			"url":           "https://go.dev/",
+			"caseSensitive": false,
+		},
+	}, true)
+	c.Assert(err, qt.IsNil)
+	c.Assert(terms, qt.DeepEquals, []Term{
+		{Term: "Go", Title: "The Go programming language", URL: "https://go.dev/"},
+		{Term: "HTML", Title: "HyperText Markup Language", CaseSensitive: true},
+	})
+
+	terms, err = DecodeTerms([]any{map[string]any{"term": "CSS", "title": "Cascading Style Sheets"}}, false)
+	c.Assert(err, qt.IsNil)
+	c.Assert(terms, qt.DeepEquals, []Term{{Term: "CSS", Title: "Cascading Style Sheets"}})
+
+	_, err = DecodeTerms("HTML", false)
+	c.Assert(err, qt.Not(qt.IsNil))
+}
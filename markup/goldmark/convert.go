@@ -19,6 +19,9 @@ import (
 
 	"github.com/gohugoio/hugo/markup/goldmark/codeblocks"
 	"github.com/gohugoio/hugo/markup/goldmark/internal/extensions/attributes"
+	"github.com/gohugoio/hugo/markup/goldmark/internal/extensions/citation"
+	"github.com/gohugoio/hugo/markup/goldmark/internal/extensions/glossary"
+	"github.com/gohugoio/hugo/markup/goldmark/internal/extensions/rawhtml"
 	"github.com/gohugoio/hugo/markup/goldmark/internal/render"
 
 	"github.com/gohugoio/hugo/identity"
@@ -124,6 +127,18 @@ func newMarkdown(pcfg converter.ProviderConfig) goldmark.Markdown {
 		extensions = append(extensions, extension.Footnote)
 	}
 
+	if cfg.Extensions.Glossary.Enable {
+		extensions = append(extensions, glossary.New())
+	}
+
+	if cfg.Extensions.Citation.Enable {
+		extensions = append(extensions, citation.New())
+	}
+
+	if len(cfg.Renderer.RawHTML) > 0 {
+		extensions = append(extensions, rawhtml.New())
+	}
+
 	if cfg.Parser.AutoHeadingID {
 		parserOptions = append(parserOptions, parser.WithAutoHeadingID())
 	}
@@ -214,6 +229,15 @@ func (c *goldmarkConverter) Supports(feature identity.Identity) bool {
 func (c *goldmarkConverter) newParserContext(rctx converter.RenderContext) *parserContext {
 	ctx := parser.NewContext(parser.WithIDs(newIDFactory(c.cfg.MarkupConfig.Goldmark.Parser.AutoHeadingIDType)))
 	ctx.Set(tocEnableKey, rctx.RenderTOC)
+	if rctx.Glossary != nil {
+		glossary.SetGlossary(ctx, rctx.Glossary)
+	}
+	if rctx.Bibliography != nil {
+		citation.SetBibliography(ctx, rctx.Bibliography)
+	}
+	if rctx.RawHTML != nil {
+		rawhtml.SetPolicy(ctx, rctx.RawHTML, rctx.RawHTMLRemoved)
+	}
 	return &parserContext{
 		Context: ctx,
 	}
@@ -31,6 +31,14 @@ var Default = Config{
 		Linkify:         true,
 		LinkifyProtocol: "https",
 		TaskList:        true,
+		Glossary: Glossary{
+			Data: "glossary",
+		},
+		Citation: Citation{
+			Data:      "bibliography",
+			Resources: "**.{bib,csl.json}",
+			Style:     "author-date",
+		},
 	},
 	Renderer: Renderer{
 		Unsafe: false,
@@ -63,6 +71,46 @@ type Extensions struct {
 	Linkify         bool
 	LinkifyProtocol string
 	TaskList        bool
+
+	// Marks up the glossary terms in the content.
+	Glossary Glossary
+
+	// Renders [@key] citations and a bibliography.
+	Citation Citation
+}
+
+// Glossary configures the glossary terms to mark up, on their first occurrence
+// on a page, with an abbr element and a link to their definition.
+type Glossary struct {
+	Enable bool
+
+	// The data file with the terms, e.g. "glossary" for data/glossary.toml.
+	Data string
+
+	// The content section with a page per term. The title of the page is the
+	// term and the description the title of the abbr element.
+	Section string
+
+	// Whether to match the terms with the same case only.
+	// Can be overridden per term.
+	CaseSensitive bool
+}
+
+// Citation configures the citations, e.g. [see @doe2020, p. 3], and the
+// bibliography appended to the content of the pages that cite.
+type Citation struct {
+	Enable bool
+
+	// The data file with the CSL-JSON entries, e.g. "bibliography" for
+	// data/bibliography.json.
+	Data string
+
+	// A glob matching the BibTeX (.bib) and CSL-JSON (.json) page resources
+	// with entries, which override those in Data.
+	Resources string
+
+	// The citation style, author-date or numeric.
+	Style string
 }
 
 type Renderer struct {
@@ -74,6 +122,33 @@ type Renderer struct {
 
 	// Allow raw HTML etc.
 	Unsafe bool
+
+	// The policies for the raw HTML, overriding Unsafe for the pages they
+	// match. The first matching rule is used.
+	RawHTML []RawHTMLRule
+}
+
+// RawHTMLRule configures what to do with the raw HTML in the pages matching
+// Path, e.g.:
+//
+//	[[markup.goldmark.renderer.rawHTML]]
+//	path = "/blog/**"
+//	action = "sanitize"
+//	elements = ["p", "a", "span"]
+//	attributes = ["href", "class", "data-*"]
+type RawHTMLRule struct {
+	// A Glob pattern matching the content path of the pages, e.g. "/blog/**".
+	// All pages if empty.
+	Path string
+
+	// One of allow, sanitize or strip.
+	Action string
+
+	// The elements and attributes kept when sanitizing, a default allow-list
+	// of elements and attributes that can not run scripts if not set.
+	// The attributes may be Glob patterns, e.g. "data-*".
+	Elements   []string
+	Attributes []string
 }
 
 type Parser struct {
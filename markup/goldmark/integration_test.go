@@ -575,3 +575,204 @@ a <!-- b --> c
 		"<li>This is a list item <!-- Comment: an innocent-looking comment --></li>",
 	)
 }
+
+func TestGlossary(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+[markup.goldmark.extensions.glossary]
+enable = true
+section = "glossary"
+-- data/glossary.toml --
+HTML = "HyperText Markup Language"
+[Go]
+title = "The Go programming language"
+url = "https://go.dev/"
+caseSensitive = true
+-- content/glossary/ssg.md --
+---
+title: "SSG"
+description: "Static Site Generator"
+---
+An SSG builds HTML.
+-- content/p1.md --
+---
+title: "p1"
+---
+## HTML in a heading
+
+Write html with an SSG, then more HTML and go to [HTML](/html/) in Go.
+Go go HTMLX ` + "`HTML`" + `.
+-- content/p2.md --
+---
+title: "p2"
+glossary: false
+---
+HTML and Go.
+-- layouts/_default/single.html --
+{{ .Content }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html", `
+<h2 id="html-in-a-heading">HTML in a heading</h2>
+<p>Write <abbr title="HyperText Markup Language">html</abbr> with an <a href="/glossary/ssg/" class="glossary-term"><abbr title="Static Site Generator">SSG</abbr></a>, then more HTML and go to <a href="/html/">HTML</a> in <a href="https://go.dev/" class="glossary-term"><abbr title="The Go programming language">Go</abbr></a>.
+Go go HTMLX <code>HTML</code>.</p>
+`)
+
+	b.AssertFileContent("public/p2/index.html", "<p>HTML and Go.</p>")
+
+	// The term is not linked on its own page.
+	b.AssertFileContent("public/glossary/ssg/index.html", `<p>An SSG builds <abbr title="HyperText Markup Language">HTML</abbr>.</p>`)
+}
+
+func TestCitations(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+[markup.goldmark.extensions.citation]
+enable = true
+-- data/bibliography.json --
+[
+  {"id": "roe2019", "type": "book", "title": "Gophers", "author": [{"family": "Roe", "given": "Richard"}], "issued": {"date-parts": [[2019]]}, "publisher": "Go Press"},
+  {"id": "doe2020", "title": "Old title"}
+]
+-- content/p1/index.md --
+---
+title: "p1"
+---
+As shown [see @doe2020, p. 3; -@roe2019], and [@roe2019].
+
+Not cited: [@nope], [a link](/a/), [me @ home] and [@roe2019](/roe/).
+-- content/p1/refs.bib --
+@article{doe2020,
+  author = {Doe, Jane},
+  title = {On {Go}},
+  journal = {Journal of Go},
+  year = {2020},
+}
+-- content/p2.md --
+---
+title: "p2"
+---
+Numbered [@roe2019].
+-- layouts/_default/single.html --
+{{ .Content }}
+-- layouts/_default/_markup/render-citation.html --
+{{- if eq .Page.Title "p2" }}<cite>{{ range .Items }}{{ .Entry.Title }}{{ end }}</cite>{{ else }}{{ .Text | safeHTML }}{{ end -}}
+-- layouts/_default/_markup/render-bibliography.html --
+{{- if eq .Page.Title "p2" }}<div class="refs">{{ range .References }}{{ .Number }}: {{ .Format | safeHTML }}{{ end }}</div>{{ else }}{{ .Text | safeHTML }}{{ end -}}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html",
+		`<p>As shown (see <a href="#ref-doe2020">Doe 2020</a>, p. 3; <a href="#ref-roe2019">2019</a>), and (<a href="#ref-roe2019">Roe 2019</a>).</p>`,
+		`<p>Not cited: [@nope], <a href="/a/">a link</a>, [me @ home] and <a href="/roe/">@roe2019</a>.</p>`,
+		`<section class="bibliography" role="doc-bibliography">
+<ul>
+<li id="ref-doe2020">Doe, J. (2020). On Go. <em>Journal of Go</em>.</li>
+<li id="ref-roe2019">Roe, R. (2019). <em>Gophers</em>. Go Press.</li>
+</ul>
+</section>`,
+	)
+
+	b.AssertFileContent("public/p2/index.html",
+		`<p>Numbered <cite>Gophers</cite>.</p>`,
+		`<div class="refs">1: Roe, R. (2019). <em>Gophers</em>. Go Press.</div>`,
+	)
+}
+
+func TestRawHTMLPolicy(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+defaultContentLanguage = "en"
+[languages]
+[languages.en]
+weight = 1
+[languages.nn]
+weight = 2
+[[languages.nn.markup.goldmark.renderer.rawHTML]]
+action = "allow"
+[[markup.goldmark.renderer.rawHTML]]
+path = "/trusted/**"
+action = "allow"
+[[markup.goldmark.renderer.rawHTML]]
+path = "/comments/**"
+action = "strip"
+[[markup.goldmark.renderer.rawHTML]]
+action = "sanitize"
+attributes = ["class", "href", "data-*"]
+-- content/p1.md --
+---
+title: "p1"
+---
+Some <span class="x" style="color: red">inline</span> HTML.
+
+<div class="note" data-id="1" onclick="alert(1)">
+<script>alert("x")</script>
+<a href="javascript:alert(1)">Link</a>
+</div>
+-- content/trusted/p2.md --
+---
+title: "p2"
+---
+<script>console.log("trusted")</script>
+-- content/comments/p3.md --
+---
+title: "p3"
+---
+Text.
+
+<div>A comment</div>
+-- content/p4.nn.md --
+---
+title: "p4"
+---
+<script>console.log("nn")</script>
+-- layouts/_default/single.html --
+{{ .Content }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html", `
+<p>Some <span class="x">inline</span> HTML.</p>
+<div class="note" data-id="1">
+
+<a>Link</a>
+</div>
+`)
+	b.AssertFileContent("public/trusted/p2/index.html", `<script>console.log("trusted")</script>`)
+	b.AssertFileContent("public/comments/p3/index.html", "<p>Text.</p>")
+	b.Assert(b.FileContent("public/comments/p3/index.html"), qt.Not(qt.Contains), "A comment")
+	b.AssertFileContent("public/nn/p4/index.html", `<script>console.log("nn")</script>`)
+
+	b.AssertLogContains(`Raw HTML removed in "/content/p1.md":
+  4:6: attribute "style" not allowed: <span class="x" style="color: red">
+  6:1: attribute "onclick" not allowed: <div class="note" data-id="1" onclick="alert(1)">
+  7:1: element "script" not allowed: <script>
+  8:1: attribute "href" not allowed: <a href="javascript:alert(1)">`)
+	b.AssertLogContains(`Raw HTML removed in "/content/comments/p3.md":
+  6:1: raw HTML not allowed: <div>A comment</div>`)
+}
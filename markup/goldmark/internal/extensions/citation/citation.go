@@ -0,0 +1,335 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package citation parses citations, e.g. [see @doe2020, p. 3], in the
+// Markdown text and appends the bibliography to the document.
+package citation
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/types/hstring"
+	"github.com/gohugoio/hugo/markup/citation"
+	"github.com/gohugoio/hugo/markup/converter/hooks"
+	"github.com/gohugoio/hugo/markup/goldmark/internal/render"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	kindCitation     = ast.NewNodeKind("Citation")
+	kindBibliography = ast.NewNodeKind("Bibliography")
+
+	bibliographyKey = parser.NewContextKey()
+
+	defaultParser                        = new(citationParser)
+	defaultTransformer                   = new(transformer)
+	defaultRenderer                      = new(citationRenderer)
+	extension          goldmark.Extender = new(citationExtension)
+)
+
+// New returns the citation extension. The entries to cite are set per
+// document with SetBibliography.
+func New() goldmark.Extender {
+	return extension
+}
+
+// SetBibliography sets the bibliography to use when parsing with pc.
+func SetBibliography(pc parser.Context, b *citation.Bibliography) {
+	pc.Set(bibliographyKey, b)
+}
+
+type citationExtension struct{}
+
+func (e *citationExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithInlineParsers(
+			// Before links.
+			util.Prioritized(defaultParser, 150),
+		),
+		parser.WithASTTransformers(
+			util.Prioritized(defaultTransformer, 60),
+		),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(defaultRenderer, 100),
+		),
+	)
+}
+
+type citationNode struct {
+	ast.BaseInline
+	bib   *citation.Bibliography
+	items []citation.Item
+}
+
+func (n *citationNode) Kind() ast.NodeKind {
+	return kindCitation
+}
+
+func (n *citationNode) Dump(source []byte, level int) {
+	keys := make([]string, len(n.items))
+	for i, item := range n.items {
+		keys[i] = item.Key
+	}
+	ast.DumpHelper(n, source, level, map[string]string{"Keys": strings.Join(keys, ",")}, nil)
+}
+
+type bibliographyNode struct {
+	ast.BaseBlock
+	bib  *citation.Bibliography
+	refs []citation.Reference
+}
+
+func (n *bibliographyNode) Kind() ast.NodeKind {
+	return kindBibliography
+}
+
+func (n *bibliographyNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+type citationParser struct{}
+
+func (p *citationParser) Trigger() []byte {
+	return []byte{'['}
+}
+
+// Parse parses a citation with one or more items separated by semicolons,
+// e.g. [see @doe2020, p. 3; -@roe2019]. Brackets with no known key are left
+// to the other parsers.
+func (p *citationParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	b, ok := pc.Get(bibliographyKey).(*citation.Bibliography)
+	if !ok || b == nil {
+		return nil
+	}
+
+	line, _ := block.PeekLine()
+	end := bytes.IndexByte(line, ']')
+	if end == -1 || bytes.IndexByte(line[1:end], '[') != -1 {
+		return nil
+	}
+	if end+1 < len(line) && (line[end+1] == '(' || line[end+1] == '[' || line[end+1] == ':') {
+		// A link.
+		return nil
+	}
+
+	items, ok := parseItems(string(line[1:end]))
+	if !ok {
+		return nil
+	}
+
+	var known bool
+	for i := range items {
+		items[i].Entry = b.Get(items[i].Key)
+		known = known || items[i].Entry != nil
+	}
+	if !known {
+		return nil
+	}
+
+	block.Advance(end + 1)
+
+	return &citationNode{bib: b, items: items}
+}
+
+var itemKeyRe = regexp.MustCompile(`(?:^|\s)(-?)@([\p{L}\p{N}_](?:[\p{L}\p{N}_:.#$%&+?<>~/-]*[\p{L}\p{N}_])?)`)
+
+func parseItems(s string) ([]citation.Item, bool) {
+	var items []citation.Item
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		m := itemKeyRe.FindStringSubmatchIndex(part)
+		if m == nil {
+			return nil, false
+		}
+		items = append(items, citation.Item{
+			Key:            part[m[4]:m[5]],
+			Prefix:         strings.TrimSpace(part[:m[0]]),
+			Locator:        strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part[m[1]:]), ",")),
+			SuppressAuthor: m[3] > m[2],
+		})
+	}
+	return items, len(items) > 0
+}
+
+type transformer struct{}
+
+// Transform numbers the cited entries in order of first citation and
+// appends the bibliography to the document.
+func (t *transformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	b, ok := pc.Get(bibliographyKey).(*citation.Bibliography)
+	if !ok || b == nil {
+		return
+	}
+
+	var (
+		items   []citation.Item
+		numbers = make(map[string]int)
+	)
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering || n.Kind() != kindCitation {
+			return ast.WalkContinue, nil
+		}
+		cn := n.(*citationNode)
+		for i, item := range cn.items {
+			if item.Entry == nil {
+				continue
+			}
+			num, found := numbers[item.Key]
+			if !found {
+				num = len(numbers) + 1
+				numbers[item.Key] = num
+			}
+			cn.items[i].Number = num
+			items = append(items, cn.items[i])
+		}
+		return ast.WalkContinue, nil
+	})
+
+	if len(items) == 0 {
+		return
+	}
+
+	doc.AppendChild(doc, &bibliographyNode{bib: b, refs: b.References(items)})
+}
+
+type citationRenderer struct{}
+
+func (r *citationRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindCitation, r.renderCitation)
+	reg.Register(kindBibliography, r.renderBibliography)
+}
+
+func (r *citationRenderer) renderCitation(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*citationNode)
+	text := n.bib.InText(n.items)
+
+	if ctx, ok := w.(*render.Context); ok {
+		if h := ctx.RenderContext().GetRenderer(hooks.CitationRendererType, nil); h != nil {
+			hr := h.(hooks.CitationRenderer)
+			err := hr.RenderCitation(w, citationContext{
+				page:  ctx.DocumentContext().Document,
+				items: n.items,
+				style: n.bib.Style,
+				text:  hstring.RenderedString(text),
+			})
+			ctx.AddIdentity(hr)
+			return ast.WalkSkipChildren, err
+		}
+	}
+
+	_, _ = w.WriteString(text)
+
+	return ast.WalkSkipChildren, nil
+}
+
+func (r *citationRenderer) renderBibliography(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*bibliographyNode)
+
+	list := "ul"
+	if n.bib.Style == citation.StyleNumeric {
+		list = "ol"
+	}
+	var sb strings.Builder
+	sb.WriteString(`<section class="bibliography" role="doc-bibliography">` + "\n<" + list + ">\n")
+	for _, ref := range n.refs {
+		sb.WriteString(`<li id="`)
+		sb.Write(util.EscapeHTML([]byte(ref.ID)))
+		sb.WriteString(`">`)
+		sb.WriteString(ref.Format())
+		sb.WriteString("</li>\n")
+	}
+	sb.WriteString("</" + list + ">\n</section>\n")
+	text := sb.String()
+
+	if ctx, ok := w.(*render.Context); ok {
+		if h := ctx.RenderContext().GetRenderer(hooks.BibliographyRendererType, nil); h != nil {
+			hr := h.(hooks.BibliographyRenderer)
+			err := hr.RenderBibliography(w, bibliographyContext{
+				page:  ctx.DocumentContext().Document,
+				refs:  n.refs,
+				style: n.bib.Style,
+				text:  hstring.RenderedString(text),
+			})
+			ctx.AddIdentity(hr)
+			return ast.WalkSkipChildren, err
+		}
+	}
+
+	_, _ = w.WriteString(text)
+
+	return ast.WalkSkipChildren, nil
+}
+
+type citationContext struct {
+	page  any
+	items []citation.Item
+	style string
+	text  hstring.RenderedString
+}
+
+func (c citationContext) Page() any {
+	return c.page
+}
+
+func (c citationContext) Items() []citation.Item {
+	return c.items
+}
+
+func (c citationContext) Style() string {
+	return c.style
+}
+
+func (c citationContext) Text() hstring.RenderedString {
+	return c.text
+}
+
+type bibliographyContext struct {
+	page  any
+	refs  []citation.Reference
+	style string
+	text  hstring.RenderedString
+}
+
+func (c bibliographyContext) Page() any {
+	return c.page
+}
+
+func (c bibliographyContext) References() []citation.Reference {
+	return c.refs
+}
+
+func (c bibliographyContext) Style() string {
+	return c.style
+}
+
+func (c bibliographyContext) Text() hstring.RenderedString {
+	return c.text
+}
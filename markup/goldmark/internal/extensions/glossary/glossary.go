@@ -0,0 +1,168 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package glossary marks up the glossary terms in the Markdown text.
+package glossary
+
+import (
+	"github.com/gohugoio/hugo/markup/glossary"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	kindTerm = ast.NewNodeKind("GlossaryTerm")
+
+	glossaryKey = parser.NewContextKey()
+
+	defaultTransformer                   = new(transformer)
+	defaultRenderer                      = new(termRenderer)
+	extension          goldmark.Extender = new(glossaryExtension)
+)
+
+// New returns the glossary extension. The terms are set per document with
+// SetGlossary.
+func New() goldmark.Extender {
+	return extension
+}
+
+// SetGlossary sets the glossary to use when parsing with pc.
+func SetGlossary(pc parser.Context, g *glossary.Glossary) {
+	pc.Set(glossaryKey, g)
+}
+
+type glossaryExtension struct{}
+
+func (e *glossaryExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(
+			util.Prioritized(defaultTransformer, 50),
+		),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(defaultRenderer, 100),
+		),
+	)
+}
+
+type termNode struct {
+	ast.BaseInline
+	term *glossary.Term
+}
+
+func (n *termNode) Kind() ast.NodeKind {
+	return kindTerm
+}
+
+func (n *termNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Term": n.term.Term}, nil)
+}
+
+type transformer struct{}
+
+// Transform wraps the first occurrence of every term in the document.
+func (t *transformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	g, ok := pc.Get(glossaryKey).(*glossary.Glossary)
+	if !ok || g == nil {
+		return
+	}
+
+	var texts []*ast.Text
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindHeading, ast.KindLink, ast.KindAutoLink, ast.KindImage,
+			ast.KindCodeSpan, ast.KindCodeBlock, ast.KindFencedCodeBlock,
+			ast.KindRawHTML, ast.KindHTMLBlock:
+			return ast.WalkSkipChildren, nil
+		case ast.KindText:
+			texts = append(texts, n.(*ast.Text))
+		}
+		return ast.WalkContinue, nil
+	})
+
+	var (
+		source = reader.Source()
+		seen   = make(map[*glossary.Term]bool)
+	)
+
+	for _, n := range texts {
+		if n.IsRaw() {
+			continue
+		}
+		seg := n.Segment
+		parent := n.Parent()
+		pos := 0
+
+		for _, m := range g.FindAll(seg.Value(source)) {
+			if seen[m.Term] {
+				continue
+			}
+			seen[m.Term] = true
+
+			if m.Start > pos {
+				parent.InsertBefore(parent, n, ast.NewTextSegment(text.NewSegment(seg.Start+pos, seg.Start+m.Start)))
+			}
+			tn := &termNode{term: m.Term}
+			tn.AppendChild(tn, ast.NewTextSegment(text.NewSegment(seg.Start+m.Start, seg.Start+m.End)))
+			parent.InsertBefore(parent, n, tn)
+			pos = m.End
+		}
+
+		if pos > 0 {
+			// Keep the node, possibly empty, for its line breaks.
+			n.Segment = text.NewSegment(seg.Start+pos, seg.Stop)
+		}
+	}
+}
+
+type termRenderer struct{}
+
+func (r *termRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindTerm, r.renderTerm)
+}
+
+func (r *termRenderer) renderTerm(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	t := node.(*termNode).term
+
+	if entering {
+		if t.URL != "" {
+			_, _ = w.WriteString(`<a href="`)
+			_, _ = w.Write(util.EscapeHTML(util.URLEscape([]byte(t.URL), false)))
+			_, _ = w.WriteString(`" class="glossary-term">`)
+		}
+		if t.Title != "" {
+			_, _ = w.WriteString(`<abbr title="`)
+			_, _ = w.Write(util.EscapeHTML([]byte(t.Title)))
+			_, _ = w.WriteString(`">`)
+		}
+		return ast.WalkContinue, nil
+	}
+
+	if t.Title != "" {
+		_, _ = w.WriteString("</abbr>")
+	}
+	if t.URL != "" {
+		_, _ = w.WriteString("</a>")
+	}
+
+	return ast.WalkContinue, nil
+}
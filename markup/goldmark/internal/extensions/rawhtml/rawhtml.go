@@ -0,0 +1,176 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rawhtml applies the raw HTML policy to the HTML blocks and the
+// inline HTML in the Markdown text.
+package rawhtml
+
+import (
+	"bytes"
+
+	"github.com/gohugoio/hugo/markup/rawhtml"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var (
+	kindHTML = ast.NewNodeKind("PolicyHTML")
+
+	policyKey  = parser.NewContextKey()
+	removedKey = parser.NewContextKey()
+
+	defaultTransformer                   = new(transformer)
+	defaultRenderer                      = new(htmlRenderer)
+	extension          goldmark.Extender = new(rawHTMLExtension)
+)
+
+// New returns the raw HTML extension. The policy is set per document with
+// SetPolicy, documents without one are rendered as without the extension.
+func New() goldmark.Extender {
+	return extension
+}
+
+// SetPolicy sets the policy to apply when parsing with pc, and the function
+// to call for the markup removed, which may be nil.
+func SetPolicy(pc parser.Context, p *rawhtml.Policy, removed func(rawhtml.Removal)) {
+	pc.Set(policyKey, p)
+	pc.Set(removedKey, removed)
+}
+
+type rawHTMLExtension struct{}
+
+func (e *rawHTMLExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithASTTransformers(
+			util.Prioritized(defaultTransformer, 70),
+		),
+	)
+	m.Renderer().AddOptions(
+		renderer.WithNodeRenderers(
+			util.Prioritized(defaultRenderer, 100),
+		),
+	)
+}
+
+// htmlNode is the raw HTML with the policy applied.
+type htmlNode interface {
+	ast.Node
+	HTML() []byte
+}
+
+type htmlBlock struct {
+	ast.BaseBlock
+	html []byte
+}
+
+func (n *htmlBlock) Kind() ast.NodeKind {
+	return kindHTML
+}
+
+func (n *htmlBlock) HTML() []byte {
+	return n.html
+}
+
+func (n *htmlBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"HTML": string(n.html)}, nil)
+}
+
+type htmlInline struct {
+	ast.BaseInline
+	html []byte
+}
+
+func (n *htmlInline) Kind() ast.NodeKind {
+	return kindHTML
+}
+
+func (n *htmlInline) HTML() []byte {
+	return n.html
+}
+
+func (n *htmlInline) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"HTML": string(n.html)}, nil)
+}
+
+type transformer struct{}
+
+// Transform replaces the raw HTML nodes with the policy applied to them.
+func (t *transformer) Transform(doc *ast.Document, reader text.Reader, pc parser.Context) {
+	p, ok := pc.Get(policyKey).(*rawhtml.Policy)
+	if !ok || p == nil {
+		return
+	}
+	removed, _ := pc.Get(removedKey).(func(rawhtml.Removal))
+
+	var nodes []ast.Node
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindHTMLBlock, ast.KindRawHTML:
+			nodes = append(nodes, n)
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	source := reader.Source()
+
+	for _, n := range nodes {
+		var (
+			buf         bytes.Buffer
+			replacement htmlNode
+		)
+
+		switch n := n.(type) {
+		case *ast.HTMLBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				line := lines.At(i)
+				buf.Write(line.Value(source))
+			}
+			if n.HasClosure() {
+				buf.Write(n.ClosureLine.Value(source))
+			}
+			replacement = &htmlBlock{html: p.Sanitize(buf.Bytes(), removed)}
+		case *ast.RawHTML:
+			for i := 0; i < n.Segments.Len(); i++ {
+				segment := n.Segments.At(i)
+				buf.Write(segment.Value(source))
+			}
+			replacement = &htmlInline{html: p.Sanitize(buf.Bytes(), removed)}
+		}
+
+		parent := n.Parent()
+		parent.ReplaceChild(parent, n, replacement)
+	}
+}
+
+type htmlRenderer struct{}
+
+func (r *htmlRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindHTML, r.renderHTML)
+}
+
+func (r *htmlRenderer) renderHTML(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if entering {
+		_, _ = w.Write(node.(htmlNode).HTML())
+	}
+	return ast.WalkSkipChildren, nil
+}
@@ -15,10 +15,14 @@
 package pandoc
 
 import (
+	"regexp"
+	"strings"
+
 	"github.com/gohugoio/hugo/common/hexec"
 	"github.com/gohugoio/hugo/htesting"
 	"github.com/gohugoio/hugo/identity"
 	"github.com/gohugoio/hugo/markup/internal"
+	"github.com/gohugoio/hugo/markup/pandoc/pandoc_config"
 
 	"github.com/gohugoio/hugo/markup/converter"
 )
@@ -44,7 +48,7 @@ type pandocConverter struct {
 }
 
 func (c *pandocConverter) Convert(ctx converter.RenderContext) (converter.Result, error) {
-	b, err := c.getPandocContent(ctx.Src, c.ctx)
+	b, err := c.getPandocContent(ctx, c.ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -56,16 +60,92 @@ func (c *pandocConverter) Supports(feature identity.Identity) bool {
 }
 
 // getPandocContent calls pandoc as an external helper to convert pandoc markdown to HTML.
-func (c *pandocConverter) getPandocContent(src []byte, ctx converter.DocumentContext) ([]byte, error) {
+func (c *pandocConverter) getPandocContent(rctx converter.RenderContext, ctx converter.DocumentContext) ([]byte, error) {
 	logger := c.cfg.Logger
 	binaryName := getPandocBinaryName()
 	if binaryName == "" {
 		logger.Println("pandoc not found in $PATH: Please install.\n",
 			"                 Leaving pandoc content unrendered.")
-		return src, nil
+		return rctx.Src, nil
 	}
+	args := c.parseArgs(rctx)
+	return internal.ExternallyRenderContent(c.cfg, ctx, rctx.Src, binaryName, args)
+}
+
+var extensionRe = regexp.MustCompile(`^[+-][a-z0-9_]+$`)
+
+func (c *pandocConverter) parseArgs(ctx converter.RenderContext) []string {
+	cfg := c.cfg.MarkupConfig.Pandoc
 	args := []string{"--mathjax"}
-	return internal.ExternallyRenderContent(c.cfg, ctx, src, binaryName, args)
+
+	var extensions string
+	for _, extension := range cfg.Extensions {
+		if !extensionRe.MatchString(extension) {
+			c.cfg.Logger.Errorln("Unsupported pandoc extension `" + extension + "` was passed in and will be ignored. Extensions must start with + or -, e.g. +smart.")
+			continue
+		}
+		extensions += extension
+	}
+	if extensions != "" {
+		args = append(args, "--from=markdown"+extensions)
+	}
+
+	var citeproc bool
+	for _, arg := range cfg.ExtraArgs {
+		if !strings.HasPrefix(arg, "-") {
+			// This would be read as an input file.
+			c.cfg.Logger.Errorln("Unsupported pandoc argument `" + arg + "` was passed in and will be ignored. Use the --option=value form for options with a value.")
+			continue
+		}
+		if pandoc_config.DisallowedArgs[argName(arg)] {
+			switch argName(arg) {
+			case "-L", "--lua-filter":
+				c.cfg.Logger.Errorln("Unsupported pandoc argument `" + arg + "` was passed in and will be ignored. Use luaFilters to run Lua filters.")
+				continue
+			case "--csl":
+				c.cfg.Logger.Errorln("Unsupported pandoc argument `" + arg + "` was passed in and will be ignored. Use csl to set the citation style.")
+				continue
+			}
+			c.cfg.Logger.Errorln("Unsupported pandoc argument `" + arg + "` was passed in and will be ignored.")
+			continue
+		}
+		if arg == "-C" || arg == "--citeproc" {
+			citeproc = true
+		}
+		args = append(args, arg)
+	}
+
+	if ctx.CSL != "" || ctx.CitationLocale != "" {
+		if ctx.CSL != "" {
+			args = append(args, "--csl="+ctx.CSL)
+		}
+		if ctx.CitationLocale != "" {
+			// citeproc takes the locale from the lang metadata.
+			args = append(args, "--metadata=lang:"+ctx.CitationLocale)
+		}
+		if !citeproc {
+			args = append(args, "--citeproc")
+		}
+	}
+
+	for _, filename := range ctx.LuaFilters {
+		args = append(args, "--lua-filter="+filename)
+	}
+
+	return args
+}
+
+// argName returns the option name of the command line argument arg,
+// e.g. "--output" for "--output=foo.html" and "-o" for "-ofoo.html".
+func argName(arg string) string {
+	if strings.HasPrefix(arg, "--") {
+		name, _, _ := strings.Cut(arg, "=")
+		return name
+	}
+	if strings.HasPrefix(arg, "-") && len(arg) > 2 {
+		return arg[:2]
+	}
+	return arg
 }
 
 const pandocBinary = "pandoc"
@@ -21,6 +21,8 @@ import (
 	"github.com/gohugoio/hugo/config/security"
 
 	"github.com/gohugoio/hugo/markup/converter"
+	"github.com/gohugoio/hugo/markup/markup_config"
+	"github.com/gohugoio/hugo/markup/pandoc/pandoc_config"
 
 	qt "github.com/frankban/quicktest"
 )
@@ -40,3 +42,36 @@ func TestConvert(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(string(b.Bytes()), qt.Equals, "<p>testContent</p>\n")
 }
+
+func TestParseArgs(t *testing.T) {
+	c := qt.New(t)
+
+	newConverter := func(conf pandoc_config.Config) *pandocConverter {
+		mconf := markup_config.Default
+		mconf.Pandoc = conf
+		p, err := Provider.New(converter.ProviderConfig{MarkupConfig: mconf, Logger: loggers.NewErrorLogger()})
+		c.Assert(err, qt.IsNil)
+		conv, err := p.New(converter.DocumentContext{})
+		c.Assert(err, qt.IsNil)
+		return conv.(*pandocConverter)
+	}
+
+	c.Assert(newConverter(pandoc_config.Default).parseArgs(converter.RenderContext{}), qt.DeepEquals, []string{"--mathjax"})
+
+	c.Assert(newConverter(pandoc_config.Config{
+		Extensions: []string{"+smart", "-raw_html", "emoji", "+smart;rm"},
+		ExtraArgs:  []string{"--shift-heading-level-by=1", "--wrap=none", "--output=foo.html", "-ofoo.html", "-o", "foo.html", "--lua-filter=x.lua", "--csl=x.csl"},
+	}).parseArgs(converter.RenderContext{
+		LuaFilters: []string{"/project/filters/a.lua", "/project/themes/t/assets/b.lua"},
+	}), qt.DeepEquals, []string{"--mathjax", "--from=markdown+smart-raw_html", "--shift-heading-level-by=1", "--wrap=none", "--lua-filter=/project/filters/a.lua", "--lua-filter=/project/themes/t/assets/b.lua"})
+
+	c.Assert(newConverter(pandoc_config.Default).parseArgs(converter.RenderContext{
+		CSL:            "/project/content/p1/apa.csl",
+		CitationLocale: "de-DE",
+		LuaFilters:     []string{"/project/filters/a.lua"},
+	}), qt.DeepEquals, []string{"--mathjax", "--csl=/project/content/p1/apa.csl", "--metadata=lang:de-DE", "--citeproc", "--lua-filter=/project/filters/a.lua"})
+
+	c.Assert(newConverter(pandoc_config.Config{
+		ExtraArgs: []string{"--citeproc", "--bibliography=refs.bib"},
+	}).parseArgs(converter.RenderContext{CitationLocale: "de-DE"}), qt.DeepEquals, []string{"--mathjax", "--citeproc", "--bibliography=refs.bib", "--metadata=lang:de-DE"})
+}
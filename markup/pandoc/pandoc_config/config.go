@@ -0,0 +1,77 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pandoc_config holds pandoc related configuration.
+package pandoc_config
+
+var (
+	// Default holds Hugo's default pandoc configuration.
+	Default = Config{
+		Extensions: []string{},
+		ExtraArgs:  []string{},
+		LuaFilters: []string{},
+	}
+
+	// DisallowedArgs are the pandoc options Hugo controls, or that would
+	// make pandoc write files or run other programs.
+	DisallowedArgs = map[string]bool{
+		"-f":              true,
+		"--from":          true,
+		"-r":              true,
+		"--read":          true,
+		"-t":              true,
+		"--to":            true,
+		"-w":              true,
+		"--write":         true,
+		"-o":              true,
+		"--output":        true,
+		"-s":              true,
+		"--standalone":    true,
+		"-F":              true,
+		"--filter":        true,
+		"-L":              true,
+		"--lua-filter":    true,
+		"--csl":           true,
+		"--data-dir":      true,
+		"--extract-media": true,
+		"--log":           true,
+		"--defaults":      true,
+		"-d":              true,
+	}
+)
+
+// Config configures pandoc.
+type Config struct {
+	// The pandoc Markdown extensions to enable or disable, e.g. "+smart"
+	// or "-raw_html".
+	Extensions []string
+
+	// Extra command line arguments passed to pandoc, e.g.
+	// "--shift-heading-level-by=1". Options with a value must use the
+	// --option=value form.
+	ExtraArgs []string
+
+	// The Lua filters to run, in order, e.g. "filters/wordcount.lua".
+	// Relative paths are looked up in the project dir, then in the assets
+	// of the project and its modules. Pages can add filters in front matter.
+	LuaFilters []string
+
+	// The CSL style used to format the citations, e.g. "csl/apa.csl".
+	// Relative paths are looked up in the page bundle, then in the assets
+	// of the project and its modules. Pages can set csl in front matter.
+	CSL string
+
+	// The locale used to format the citations, e.g. "de-DE". Pages can set
+	// citation-locale in front matter.
+	CitationLocale string
+}
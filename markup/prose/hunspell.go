@@ -0,0 +1,356 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prose
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Dictionary is a Hunspell dictionary with the word forms expanded.
+//
+// Only the prefix and suffix rules of the affix file are supported, which is
+// sufficient for most dictionaries for spell checking, but not for
+// compounding languages.
+type Dictionary struct {
+	words map[string]bool
+}
+
+// NewDictionary creates an empty dictionary, e.g. to add words to.
+func NewDictionary() *Dictionary {
+	return &Dictionary{words: make(map[string]bool)}
+}
+
+// ParseDictionary reads a dictionary from its .dic and .aff files, both
+// expected to be UTF-8 encoded.
+func ParseDictionary(dic, aff io.Reader) (*Dictionary, error) {
+	a, err := parseAffixes(aff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse affix file: %w", err)
+	}
+
+	d := NewDictionary()
+
+	scanner := bufio.NewScanner(dic)
+	for first := true; scanner.Scan(); first = false {
+		line := strings.TrimSpace(scanner.Text())
+		if first {
+			// The approximate number of words.
+			if _, err := strconv.Atoi(line); err == nil {
+				continue
+			}
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Skip any morphological fields.
+		if i := strings.IndexAny(line, " \t"); i != -1 {
+			line = line[:i]
+		}
+
+		word, flags := splitWordFlags(line)
+		if word == "" {
+			continue
+		}
+		wordFlags := a.parseFlags(flags)
+		if a.has(wordFlags, a.forbidden) {
+			continue
+		}
+		a.expand(word, wordFlags, d.Add)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary: %w", err)
+	}
+
+	return d, nil
+}
+
+// Add adds word to the dictionary.
+func (d *Dictionary) Add(word string) {
+	d.words[word] = true
+}
+
+// Check reports whether word is correctly spelled. A capitalized or all upper
+// case word is also accepted if its lower case form is in the dictionary.
+func (d *Dictionary) Check(word string) bool {
+	if d.words[word] {
+		return true
+	}
+
+	lower := strings.ToLower(word)
+	if lower == word {
+		return false
+	}
+
+	r, size := utf8.DecodeRuneInString(word)
+	rest := word[size:]
+	switch {
+	case strings.ToUpper(word) == word:
+		// HELLO and PARIS.
+		lr, lsize := utf8.DecodeRuneInString(lower)
+		return d.words[lower] || d.words[string(unicode.ToUpper(lr))+lower[lsize:]]
+	case unicode.IsUpper(r) && strings.ToLower(rest) == rest:
+		// Hello.
+		return d.words[lower]
+	}
+
+	return false
+}
+
+// splitWordFlags splits a dictionary entry, e.g. "work/DGS", into its word
+// and flags, handling any escaped slash in the word.
+func splitWordFlags(s string) (string, string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '/':
+			return strings.ReplaceAll(s[:i], `\/`, "/"), s[i+1:]
+		}
+	}
+	return strings.ReplaceAll(s, `\/`, "/"), ""
+}
+
+type flagType int
+
+const (
+	flagChar flagType = iota
+	flagLong
+	flagNum
+)
+
+type affixRule struct {
+	strip string
+	add   string
+	cond  *regexp.Regexp
+}
+
+type affix struct {
+	prefix       bool
+	crossProduct bool
+	rules        []affixRule
+}
+
+type affixes struct {
+	flagType  flagType
+	forbidden string
+	affixes   map[string]*affix
+}
+
+func parseAffixes(r io.Reader) (*affixes, error) {
+	a := &affixes{affixes: make(map[string]*affix)}
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i == 0 {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "FLAG":
+			switch fields[1] {
+			case "long":
+				a.flagType = flagLong
+			case "num":
+				a.flagType = flagNum
+			}
+		case "FORBIDDENWORD":
+			a.forbidden = fields[1]
+		case "PFX", "SFX":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("line %d: invalid affix %q", lineNumber, line)
+			}
+			flag := fields[1]
+			af, found := a.affixes[flag]
+			if !found {
+				// The header, e.g. SFX D Y 4.
+				a.affixes[flag] = &affix{
+					prefix:       fields[0] == "PFX",
+					crossProduct: fields[2] == "Y",
+				}
+				continue
+			}
+
+			// A rule, e.g. SFX D y ied [^aeiou]y.
+			rule := affixRule{strip: fields[2], add: fields[3]}
+			if rule.strip == "0" {
+				rule.strip = ""
+			}
+			if i := strings.Index(rule.add, "/"); i != -1 {
+				// Continuation flags are not supported.
+				rule.add = rule.add[:i]
+			}
+			if rule.add == "0" {
+				rule.add = ""
+			}
+			cond := "."
+			if len(fields) > 4 {
+				cond = fields[4]
+			}
+			if cond != "." {
+				re, err := compileCondition(cond, af.prefix)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid condition %q: %w", lineNumber, cond, err)
+				}
+				rule.cond = re
+			}
+			af.rules = append(af.rules, rule)
+		}
+	}
+
+	return a, scanner.Err()
+}
+
+// compileCondition compiles an affix condition, e.g. [^aeiou]y, to a regexp
+// matching the start or end of a word.
+func compileCondition(cond string, prefix bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	if prefix {
+		b.WriteString("^")
+	}
+	b.WriteString("(?:")
+	inClass := false
+	for _, r := range cond {
+		switch {
+		case r == '[':
+			inClass = true
+			b.WriteRune(r)
+		case r == ']':
+			inClass = false
+			b.WriteRune(r)
+		case inClass || r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(")")
+	if !prefix {
+		b.WriteString("$")
+	}
+	return regexp.Compile(b.String())
+}
+
+func (a *affixes) parseFlags(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	var flags []string
+	switch a.flagType {
+	case flagLong:
+		runes := []rune(s)
+		for i := 0; i+1 < len(runes); i += 2 {
+			flags = append(flags, string(runes[i:i+2]))
+		}
+	case flagNum:
+		flags = strings.Split(s, ",")
+	default:
+		for _, r := range s {
+			flags = append(flags, string(r))
+		}
+	}
+
+	return flags
+}
+
+func (a *affixes) has(flags []string, flag string) bool {
+	if flag == "" {
+		return false
+	}
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// expand calls add with word and all its forms given its flags.
+func (a *affixes) expand(word string, flags []string, add func(string)) {
+	add(word)
+
+	var prefixes, suffixes []*affix
+	for _, flag := range flags {
+		af, found := a.affixes[flag]
+		if !found {
+			continue
+		}
+		if af.prefix {
+			prefixes = append(prefixes, af)
+		} else {
+			suffixes = append(suffixes, af)
+		}
+	}
+
+	for _, sfx := range suffixes {
+		for _, rule := range sfx.rules {
+			form, ok := rule.apply(word, false)
+			if !ok {
+				continue
+			}
+			add(form)
+			if !sfx.crossProduct {
+				continue
+			}
+			for _, pfx := range prefixes {
+				if !pfx.crossProduct {
+					continue
+				}
+				for _, prule := range pfx.rules {
+					if pform, ok := prule.apply(form, true); ok {
+						add(pform)
+					}
+				}
+			}
+		}
+	}
+
+	for _, pfx := range prefixes {
+		for _, rule := range pfx.rules {
+			if form, ok := rule.apply(word, true); ok {
+				add(form)
+			}
+		}
+	}
+}
+
+func (r affixRule) apply(word string, prefix bool) (string, bool) {
+	if r.cond != nil && !r.cond.MatchString(word) {
+		return "", false
+	}
+	if prefix {
+		if !strings.HasPrefix(word, r.strip) {
+			return "", false
+		}
+		return r.add + word[len(r.strip):], true
+	}
+	if !strings.HasSuffix(word, r.strip) {
+		return "", false
+	}
+	return word[:len(word)-len(r.strip)] + r.add, true
+}
@@ -0,0 +1,343 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prose checks the spelling and style of plain text.
+package prose
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// The rules reported in Diagnostic.
+const (
+	RuleSpelling       = "spelling"
+	RuleSentenceLength = "sentence-length"
+	RuleBannedWord     = "banned-word"
+)
+
+// Config configures the prose checks. It can be set per language.
+type Config struct {
+	// The max number of words in a sentence. 0 disables the check.
+	MaxSentenceLength int
+
+	// The key of the data file with the banned words, e.g. "prose.banned"
+	// for data/prose/banned.toml. It holds either a list of words or a map
+	// of words to the word to use instead.
+	BannedWords string
+
+	Spelling Spelling
+}
+
+// Spelling configures the spell checking.
+type Spelling struct {
+	// The Hunspell dictionaries, relative to the project dir and without the
+	// extension, e.g. "dictionaries/en_US" for dictionaries/en_US.dic and
+	// dictionaries/en_US.aff. No dictionary disables the check.
+	Dictionaries []string
+
+	// Words to accept in addition to those in the dictionaries.
+	Ignore []string
+}
+
+// DecodeConfig decodes the prose configuration in in.
+func DecodeConfig(in any) (Config, error) {
+	var c Config
+	if in == nil {
+		return c, nil
+	}
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode prose config: %w", err)
+	}
+	return c, nil
+}
+
+// DecodeBannedWords decodes the banned words in a data file, either a list of
+// words or a map of words to their replacement, to a map keyed by the lower
+// case word.
+func DecodeBannedWords(in any) (map[string]string, error) {
+	m := make(map[string]string)
+	switch v := in.(type) {
+	case nil:
+	case []any:
+		for _, vv := range v {
+			s, ok := vv.(string)
+			if !ok {
+				return nil, fmt.Errorf("banned words: expected a string, got %T", vv)
+			}
+			m[strings.ToLower(s)] = ""
+		}
+	case map[string]any:
+		for k, vv := range v {
+			s, ok := vv.(string)
+			if !ok {
+				return nil, fmt.Errorf("banned words: expected a string for %q, got %T", k, vv)
+			}
+			m[strings.ToLower(k)] = s
+		}
+	default:
+		return nil, fmt.Errorf("banned words: unsupported data type %T", in)
+	}
+	return m, nil
+}
+
+// Diagnostic is a problem found in a text.
+type Diagnostic struct {
+	Rule    string
+	Message string
+
+	// The text the problem was found in, e.g. the misspelled word or the
+	// first word of a long sentence, and its byte offset.
+	Text   string
+	Offset int
+}
+
+// Checker checks texts with the configured rules.
+type Checker struct {
+	maxSentenceLength int
+	banned            []bannedWord
+	dictionaries      []*Dictionary
+}
+
+type bannedWord struct {
+	re      *regexp.Regexp
+	replace string
+}
+
+// NewChecker creates a new Checker. Spelling is only checked if there are
+// dictionaries.
+func NewChecker(cfg Config, banned map[string]string, dictionaries ...*Dictionary) *Checker {
+	c := &Checker{
+		maxSentenceLength: cfg.MaxSentenceLength,
+	}
+
+	if len(dictionaries) > 0 {
+		ignore := NewDictionary()
+		for _, w := range cfg.Spelling.Ignore {
+			ignore.Add(w)
+		}
+		c.dictionaries = append(dictionaries, ignore)
+	}
+
+	words := make([]string, 0, len(banned))
+	for w := range banned {
+		words = append(words, w)
+	}
+	sort.Strings(words)
+	for _, w := range words {
+		c.banned = append(c.banned, bannedWord{
+			re:      regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(w) + `\b`),
+			replace: banned[w],
+		})
+	}
+
+	return c
+}
+
+// Check checks s and returns the problems found ordered by position.
+func (c *Checker) Check(s string) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	if len(c.dictionaries) > 0 {
+		for _, w := range words(s) {
+			if !c.checkSpelling(w.text) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Rule:    RuleSpelling,
+					Message: fmt.Sprintf("unknown word %q", w.text),
+					Text:    w.text,
+					Offset:  w.offset,
+				})
+			}
+		}
+	}
+
+	if c.maxSentenceLength > 0 {
+		for _, sentence := range sentences(s) {
+			if len(sentence) > c.maxSentenceLength {
+				diagnostics = append(diagnostics, Diagnostic{
+					Rule:    RuleSentenceLength,
+					Message: fmt.Sprintf("sentence has %d words, more than %d", len(sentence), c.maxSentenceLength),
+					Text:    sentence[0].text,
+					Offset:  sentence[0].offset,
+				})
+			}
+		}
+	}
+
+	for _, b := range c.banned {
+		for _, loc := range b.re.FindAllStringIndex(s, -1) {
+			w := s[loc[0]:loc[1]]
+			msg := fmt.Sprintf("avoid %q", w)
+			if b.replace != "" {
+				msg += fmt.Sprintf(", use %q instead", b.replace)
+			}
+			diagnostics = append(diagnostics, Diagnostic{
+				Rule:    RuleBannedWord,
+				Message: msg,
+				Text:    w,
+				Offset:  loc[0],
+			})
+		}
+	}
+
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Offset < diagnostics[j].Offset
+	})
+
+	return diagnostics
+}
+
+func (c *Checker) checkSpelling(w string) bool {
+	for _, d := range c.dictionaries {
+		if d.Check(w) {
+			return true
+		}
+	}
+	// Try without any possessive 's.
+	for _, suffix := range []string{"'s", "’s"} {
+		if strings.HasSuffix(w, suffix) {
+			return c.checkSpelling(strings.TrimSuffix(w, suffix))
+		}
+	}
+	return false
+}
+
+type word struct {
+	text   string
+	offset int
+}
+
+// words splits s into words, skipping those with digits, e.g. 2022 and mp3.
+func words(s string) []word {
+	var (
+		result []word
+		start  = -1
+		digit  bool
+	)
+
+	flush := func(end int) {
+		if start == -1 {
+			return
+		}
+		w := strings.TrimRight(s[start:end], "'’")
+		if !digit && w != "" {
+			result = append(result, word{text: w, offset: start})
+		}
+		start, digit = -1, false
+	}
+
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			if start == -1 {
+				start = i
+			}
+		case unicode.IsDigit(r):
+			if start == -1 {
+				start = i
+			}
+			digit = true
+		case (r == '\'' || r == '’') && start != -1:
+			// An apostrophe within a word, e.g. don't.
+		default:
+			flush(i)
+		}
+	}
+	flush(len(s))
+
+	return result
+}
+
+// sentences splits s into sentences at ., ! and ? followed by a space and at
+// line breaks, and returns the words of each.
+func sentences(s string) [][]word {
+	var (
+		result [][]word
+		start  int
+	)
+
+	for i, r := range s {
+		end := -1
+		switch r {
+		case '\n':
+			end = i
+		case '.', '!', '?':
+			next, _ := utf8.DecodeRuneInString(s[i+1:])
+			if i+1 == len(s) || unicode.IsSpace(next) {
+				end = i + 1
+			}
+		}
+		if end == -1 {
+			continue
+		}
+		if ws := words(s[start:end]); len(ws) > 0 {
+			for j := range ws {
+				ws[j].offset += start
+			}
+			result = append(result, ws)
+		}
+		start = end
+	}
+
+	if ws := words(s[start:]); len(ws) > 0 {
+		for j := range ws {
+			ws[j].offset += start
+		}
+		result = append(result, ws)
+	}
+
+	return result
+}
+
+// Locate finds the position in source, e.g. the Markdown, of the problem d
+// found in text, e.g. the plain text rendered from source. It returns the
+// offset of the same occurrence of d.Text, else the first, else -1.
+func Locate(text, source string, d Diagnostic) int {
+	n := len(findWord(text[:d.Offset], d.Text))
+	occurrences := findWord(source, d.Text)
+	if len(occurrences) == 0 {
+		return -1
+	}
+	if n < len(occurrences) {
+		return occurrences[n]
+	}
+	return occurrences[0]
+}
+
+// findWord returns the offsets of w in s as a whole word.
+func findWord(s, w string) []int {
+	var offsets []int
+	for offset := 0; ; {
+		i := strings.Index(s[offset:], w)
+		if i == -1 {
+			return offsets
+		}
+		start, end := offset+i, offset+i+len(w)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			offsets = append(offsets, start)
+		}
+		offset = end
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
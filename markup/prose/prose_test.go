@@ -0,0 +1,107 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prose
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+const (
+	testAff = `SET UTF-8
+TRY esianrtolcdugmphbyfvkwz
+
+PFX A Y 1
+PFX A 0 re .
+
+SFX D Y 4
+SFX D 0 d e
+SFX D y ied [^aeiou]y
+SFX D 0 ed [^ey]
+SFX D 0 ed [aeiou]y
+
+SFX S Y 2
+SFX S y ies [^aeiou]y
+SFX S 0 s [^y]
+`
+	testDic = `5
+work/ADS
+try/DS
+Paris
+hugo
+don't
+`
+)
+
+func TestDictionary(t *testing.T) {
+	c := qt.New(t)
+
+	d, err := ParseDictionary(strings.NewReader(testDic), strings.NewReader(testAff))
+	c.Assert(err, qt.IsNil)
+
+	for _, w := range []string{"work", "worked", "works", "rework", "reworked", "tried", "tries", "Paris", "PARIS", "hugo", "Hugo", "HUGO", "don't"} {
+		c.Assert(d.Check(w), qt.IsTrue, qt.Commentf(w))
+	}
+	for _, w := range []string{"wrok", "tryed", "paris", "retry", "hUGO"} {
+		c.Assert(d.Check(w), qt.IsFalse, qt.Commentf(w))
+	}
+}
+
+func TestChecker(t *testing.T) {
+	c := qt.New(t)
+
+	d, err := ParseDictionary(strings.NewReader(testDic), strings.NewReader(testAff))
+	c.Assert(err, qt.IsNil)
+
+	cfg, err := DecodeConfig(map[string]any{
+		"maxSentenceLength": 4,
+		"spelling": map[string]any{
+			"ignore": []any{"utilize", "we", "it", "in", "a"},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+
+	banned, err := DecodeBannedWords(map[string]any{"utilize": "use"})
+	c.Assert(err, qt.IsNil)
+
+	checker := NewChecker(cfg, banned, d)
+
+	text := "We utilize Hugo. It works in Paris in 2022 and reworks wrok!\nWe don't try."
+	diagnostics := checker.Check(text)
+
+	var got []string
+	for _, d := range diagnostics {
+		got = append(got, d.Rule+": "+d.Message+": "+text[d.Offset:d.Offset+len(d.Text)])
+	}
+
+	c.Assert(got, qt.DeepEquals, []string{
+		`banned-word: avoid "utilize", use "use" instead: utilize`,
+		`sentence-length: sentence has 8 words, more than 4: It`,
+		`spelling: unknown word "and": and`,
+		`spelling: unknown word "wrok": wrok`,
+	})
+}
+
+func TestLocate(t *testing.T) {
+	c := qt.New(t)
+
+	source := "Go **go** to [go](/go/), gopher go."
+	text := "Go go to go, gopher go."
+
+	c.Assert(Locate(text, source, Diagnostic{Text: "go", Offset: 3}), qt.Equals, 5)
+	c.Assert(Locate(text, source, Diagnostic{Text: "go", Offset: 9}), qt.Equals, 14)
+	c.Assert(Locate(text, source, Diagnostic{Text: "stop", Offset: 0}), qt.Equals, -1)
+}
@@ -0,0 +1,284 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rawhtml sanitizes the raw HTML in the Markdown content.
+package rawhtml
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	xhtml "golang.org/x/net/html"
+)
+
+// The actions of a policy.
+const (
+	// Render the raw HTML as is.
+	ActionAllow = "allow"
+
+	// Remove the elements and attributes not in the allow-list.
+	ActionSanitize = "sanitize"
+
+	// Remove all raw HTML.
+	ActionStrip = "strip"
+)
+
+// DefaultElements are the elements allowed by the sanitize action if none
+// are configured.
+var DefaultElements = []string{
+	"a", "abbr", "b", "bdi", "bdo", "blockquote", "br", "caption", "cite",
+	"code", "col", "colgroup", "dd", "del", "details", "dfn", "div", "dl",
+	"dt", "em", "figcaption", "figure", "h1", "h2", "h3", "h4", "h5", "h6",
+	"hr", "i", "img", "ins", "kbd", "li", "mark", "ol", "p", "picture",
+	"pre", "q", "rp", "rt", "ruby", "s", "samp", "small", "source", "span",
+	"strong", "sub", "summary", "sup", "table", "tbody", "td", "tfoot", "th",
+	"thead", "time", "tr", "u", "ul", "var", "wbr",
+}
+
+// DefaultAttributes are the attributes allowed by the sanitize action if
+// none are configured.
+var DefaultAttributes = []string{
+	"abbr", "alt", "aria-*", "cite", "class", "colspan", "datetime", "dir",
+	"headers", "height", "href", "id", "lang", "loading", "media", "open",
+	"rel", "rowspan", "scope", "sizes", "src", "srcset", "start", "title",
+	"type", "width",
+}
+
+// The attributes holding URLs, checked for unsafe schemes.
+var urlAttributes = map[string]bool{
+	"action":     true,
+	"background": true,
+	"cite":       true,
+	"formaction": true,
+	"href":       true,
+	"poster":     true,
+	"src":        true,
+	"srcset":     true,
+	"xlink:href": true,
+}
+
+// The elements whose content is removed with them.
+var rawTextElements = map[string]bool{
+	"iframe":   true,
+	"noembed":  true,
+	"noframes": true,
+	"noscript": true,
+	"script":   true,
+	"style":    true,
+	"template": true,
+	"textarea": true,
+	"title":    true,
+	"xmp":      true,
+}
+
+var unsafeSchemes = []string{"javascript:", "vbscript:", "data:"}
+
+// Removal is markup removed by a policy.
+type Removal struct {
+	// The removed markup as written in the source, e.g. a start tag.
+	Markup string
+
+	// Why it was removed, e.g. element "script" not allowed.
+	Reason string
+}
+
+// Policy decides which of the raw HTML to keep.
+type Policy struct {
+	action     string
+	elements   map[string]bool
+	attributes []glob.Glob
+}
+
+// New creates a new Policy. The elements and attributes are only used by
+// the sanitize action, which falls back to DefaultElements and
+// DefaultAttributes if they are empty. The attributes may be Glob patterns,
+// e.g. data-*.
+func New(action string, elements, attributes []string) (*Policy, error) {
+	p := &Policy{action: strings.ToLower(action)}
+
+	switch p.action {
+	case ActionAllow, ActionStrip:
+		return p, nil
+	case "", ActionSanitize:
+		p.action = ActionSanitize
+	default:
+		return nil, fmt.Errorf("invalid raw HTML action %q, must be one of %s, %s or %s", action, ActionAllow, ActionSanitize, ActionStrip)
+	}
+
+	if len(elements) == 0 {
+		elements = DefaultElements
+	}
+	if len(attributes) == 0 {
+		attributes = DefaultAttributes
+	}
+
+	p.elements = make(map[string]bool)
+	for _, e := range elements {
+		p.elements[strings.ToLower(e)] = true
+	}
+	for _, a := range attributes {
+		g, err := glob.Compile(strings.ToLower(a))
+		if err != nil {
+			return nil, fmt.Errorf("invalid raw HTML attribute pattern %q: %w", a, err)
+		}
+		p.attributes = append(p.attributes, g)
+	}
+
+	return p, nil
+}
+
+// Action returns the action of p, one of ActionAllow, ActionSanitize or
+// ActionStrip.
+func (p *Policy) Action() string {
+	return p.action
+}
+
+// Sanitize returns src, a fragment of raw HTML, with the markup not allowed
+// by p removed. The removed function, if not nil, is called for every
+// element, or the whole fragment if stripped, removed.
+//
+// Disallowed elements are removed with their content if it is raw text,
+// e.g. a script, else the content is kept. Event handler attributes, e.g.
+// onclick, and javascript:, vbscript: and data: URLs are always removed.
+// Comments are removed without notice.
+func (p *Policy) Sanitize(src []byte, removed func(Removal)) []byte {
+	if removed == nil {
+		removed = func(Removal) {}
+	}
+
+	switch p.action {
+	case ActionAllow:
+		return src
+	case ActionStrip:
+		if len(bytes.TrimSpace(src)) > 0 {
+			removed(Removal{Markup: string(bytes.TrimSpace(src)), Reason: "raw HTML not allowed"})
+		}
+		return nil
+	}
+
+	var (
+		buf     bytes.Buffer
+		z       = xhtml.NewTokenizer(bytes.NewReader(src))
+		skipRaw bool
+	)
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case xhtml.ErrorToken:
+			// io.EOF, the only error reading from memory.
+			return buf.Bytes()
+		case xhtml.TextToken:
+			if skipRaw {
+				skipRaw = false
+				continue
+			}
+			buf.Write(z.Raw())
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			raw := string(z.Raw())
+			name, hasAttr := z.TagName()
+			tag := strings.ToLower(string(name))
+			skipRaw = false
+
+			if !p.elements[tag] {
+				removed(Removal{Markup: raw, Reason: fmt.Sprintf("element %q not allowed", tag)})
+				skipRaw = tt == xhtml.StartTagToken && rawTextElements[tag]
+				continue
+			}
+
+			var dropped []string
+			buf.WriteByte('<')
+			buf.WriteString(tag)
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				attr := strings.ToLower(string(key))
+				if !p.allowsAttribute(attr, string(val)) {
+					dropped = append(dropped, fmt.Sprintf("%q", attr))
+					continue
+				}
+				buf.WriteByte(' ')
+				buf.WriteString(attr)
+				buf.WriteString(`="`)
+				buf.WriteString(html.EscapeString(string(val)))
+				buf.WriteByte('"')
+			}
+			if tt == xhtml.SelfClosingTagToken {
+				buf.WriteString(" /")
+			}
+			buf.WriteByte('>')
+
+			if len(dropped) > 0 {
+				sort.Strings(dropped)
+				what := "attribute"
+				if len(dropped) > 1 {
+					what += "s"
+				}
+				removed(Removal{Markup: raw, Reason: fmt.Sprintf("%s %s not allowed", what, strings.Join(dropped, ", "))})
+			}
+		case xhtml.EndTagToken:
+			name, _ := z.TagName()
+			tag := strings.ToLower(string(name))
+			if p.elements[tag] {
+				buf.WriteString("</")
+				buf.WriteString(tag)
+				buf.WriteByte('>')
+			}
+		case xhtml.CommentToken, xhtml.DoctypeToken:
+			// Dropped.
+		}
+	}
+}
+
+func (p *Policy) allowsAttribute(name, val string) bool {
+	if strings.HasPrefix(name, "on") {
+		return false
+	}
+	if urlAttributes[name] {
+		urls := []string{val}
+		if name == "srcset" {
+			urls = strings.Split(val, ",")
+		}
+		for _, u := range urls {
+			if hasUnsafeScheme(u) {
+				return false
+			}
+		}
+	}
+	for _, g := range p.attributes {
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUnsafeScheme(url string) bool {
+	// Browsers ignore whitespace and control characters in the scheme.
+	url = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(url))
+	for _, s := range unsafeSchemes {
+		if strings.HasPrefix(url, s) {
+			return true
+		}
+	}
+	return false
+}
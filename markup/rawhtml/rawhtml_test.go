@@ -0,0 +1,73 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rawhtml
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestSanitize(t *testing.T) {
+	c := qt.New(t)
+
+	sanitize := func(p *Policy, s string) (string, []Removal) {
+		var removed []Removal
+		b := p.Sanitize([]byte(s), func(r Removal) {
+			removed = append(removed, r)
+		})
+		return string(b), removed
+	}
+
+	p, err := New("", nil, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(p.Action(), qt.Equals, ActionSanitize)
+
+	got, removed := sanitize(p, `<div class="note" onclick="alert(1)"><p>Hello <font>world</font> &amp; all</p><script>alert("x")</script></div>`)
+	c.Assert(got, qt.Equals, `<div class="note"><p>Hello world &amp; all</p></div>`)
+	c.Assert(removed, qt.DeepEquals, []Removal{
+		{Markup: `<div class="note" onclick="alert(1)">`, Reason: `attribute "onclick" not allowed`},
+		{Markup: `<font>`, Reason: `element "font" not allowed`},
+		{Markup: `<script>`, Reason: `element "script" not allowed`},
+	})
+
+	got, removed = sanitize(p, `<a href=" javascript:alert(1)" title='a "b"' style="x">x</a><img src="a.png" alt="A" /><!-- comment -->`)
+	c.Assert(got, qt.Equals, `<a title="a &#34;b&#34;">x</a><img src="a.png" alt="A" />`)
+	c.Assert(removed, qt.DeepEquals, []Removal{
+		{Markup: `<a href=" javascript:alert(1)" title='a "b"' style="x">`, Reason: `attributes "href", "style" not allowed`},
+	})
+
+	p, err = New("sanitize", []string{"Span"}, []string{"data-*"})
+	c.Assert(err, qt.IsNil)
+	got, removed = sanitize(p, `<span data-id="1" class="x">a</span><b>b</b>`)
+	c.Assert(got, qt.Equals, `<span data-id="1">a</span>b`)
+	c.Assert(removed, qt.HasLen, 2)
+
+	p, err = New("strip", nil, nil)
+	c.Assert(err, qt.IsNil)
+	got, removed = sanitize(p, "<div>\n  text\n</div>\n")
+	c.Assert(got, qt.Equals, "")
+	c.Assert(removed, qt.DeepEquals, []Removal{{Markup: "<div>\n  text\n</div>", Reason: "raw HTML not allowed"}})
+
+	p, err = New("allow", nil, nil)
+	c.Assert(err, qt.IsNil)
+	got, removed = sanitize(p, `<script>alert(1)</script>`)
+	c.Assert(got, qt.Equals, `<script>alert(1)</script>`)
+	c.Assert(removed, qt.HasLen, 0)
+
+	_, err = New("escape", nil, nil)
+	c.Assert(err, qt.Not(qt.IsNil))
+	_, err = New("sanitize", nil, []string{"data-[*"})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
@@ -85,6 +85,10 @@ func FromContent(types Types, extensionHints []string, content []byte) Type {
 		return zero
 	}
 
+	if len(extensionHints) == 0 {
+		return m
+	}
+
 	var mm Type
 
 	for _, extension := range extensionHints {
@@ -237,10 +241,12 @@ var (
 
 	JSONType           = newMediaType("application", "json", []string{"json"})
 	WebAppManifestType = newMediaTypeWithMimeSuffix("application", "manifest", "json", []string{"webmanifest"})
+	WebFingerType      = newMediaTypeWithMimeSuffix("application", "jrd", "json", nil)
 	RSSType            = newMediaTypeWithMimeSuffix("application", "rss", "xml", []string{"xml", "rss"})
 	XMLType            = newMediaType("application", "xml", []string{"xml"})
 	SVGType            = newMediaTypeWithMimeSuffix("image", "svg", "xml", []string{"svg"})
 	TextType           = newMediaType("text", "plain", []string{"txt"})
+	MarkdownType       = newMediaType("text", "markdown", []string{"md"})
 	TOMLType           = newMediaType("application", "toml", []string{"toml"})
 	YAMLType           = newMediaType("application", "yaml", []string{"yaml", "yml"})
 
@@ -259,6 +265,10 @@ var (
 	// Common document types
 	PDFType = newMediaType("application", "pdf", []string{"pdf"})
 
+	// Common audio types
+	MP3Type = newMediaType("audio", "mpeg", []string{"mp3"})
+	M4AType = newMediaType("audio", "mp4", []string{"m4a"})
+
 	// Common video types
 	AVIType  = newMediaType("video", "x-msvideo", []string{"avi"})
 	MPEGType = newMediaType("video", "mpeg", []string{"mpg", "mpeg"})
@@ -284,10 +294,12 @@ var DefaultTypes = Types{
 	JSXType,
 	JSONType,
 	WebAppManifestType,
+	WebFingerType,
 	RSSType,
 	XMLType,
 	SVGType,
 	TextType,
+	MarkdownType,
 	OctetType,
 	YAMLType,
 	TOMLType,
@@ -296,6 +308,8 @@ var DefaultTypes = Types{
 	BMPType,
 	JPEGType,
 	WEBPType,
+	MP3Type,
+	M4AType,
 	AVIType,
 	MPEGType,
 	MP4Type,
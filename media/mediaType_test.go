@@ -48,6 +48,7 @@ func TestDefaultTypes(t *testing.T) {
 		{RSSType, "application", "rss", "xml", "application/rss+xml", "application/rss+xml"},
 		{SVGType, "image", "svg", "svg", "image/svg+xml", "image/svg+xml"},
 		{TextType, "text", "plain", "txt", "text/plain", "text/plain"},
+		{MarkdownType, "text", "markdown", "md", "text/markdown", "text/markdown"},
 		{XMLType, "application", "xml", "xml", "application/xml", "application/xml"},
 		{TOMLType, "application", "toml", "toml", "application/toml", "application/toml"},
 		{YAMLType, "application", "yaml", "yaml", "application/yaml", "application/yaml"},
@@ -63,7 +64,7 @@ func TestDefaultTypes(t *testing.T) {
 
 	}
 
-	c.Assert(len(DefaultTypes), qt.Equals, 33)
+	c.Assert(len(DefaultTypes), qt.Equals, 37)
 }
 
 func TestGetByType(t *testing.T) {
@@ -203,6 +204,10 @@ func TestFromContent(t *testing.T) {
 			c.Assert(found, qt.IsTrue)
 			got := FromContent(mtypes, exts, content)
 			c.Assert(got, qt.Equals, expected)
+			if expected.MainType == "image" && expected.SubType != "svg" {
+				// Binary formats are detected without hints.
+				c.Assert(FromContent(mtypes, nil, content), qt.Equals, expected)
+			}
 		})
 	}
 }
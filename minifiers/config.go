@@ -14,6 +14,8 @@
 package minifiers
 
 import (
+	"strings"
+
 	"github.com/gohugoio/hugo/common/maps"
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/docshelper"
@@ -72,7 +74,14 @@ type minifyConfig struct {
 	DisableSVG  bool
 	DisableXML  bool
 
+	// Media types not to minify, e.g. application/manifest+json.
+	DisableMediaTypes []string
+
 	Tdewolff tdewolffConfig
+
+	// Overrides of the above by output format name, e.g. minifyOutput = false
+	// for email or other JS minifier options for AMP.
+	OutputFormats map[string]minifyConfig
 }
 
 var defaultConfig = minifyConfig{
@@ -97,8 +106,35 @@ func decodeConfig(cfg config.Provider) (conf minifyConfig, err error) {
 	}
 
 	m := maps.ToStringMap(v)
+	handleRenames(m)
+
+	outputFormats := maps.ToStringMap(m["outputformats"])
+	delete(m, "outputformats")
+
+	if err = mapstructure.WeakDecode(m, &conf); err != nil {
+		return
+	}
+
+	for k, v := range outputFormats {
+		if conf.OutputFormats == nil {
+			conf.OutputFormats = make(map[string]minifyConfig)
+		}
+		// Start out with the site wide config.
+		ofConf := conf
+		ofConf.OutputFormats = nil
+		ofm := maps.ToStringMap(v)
+		handleRenames(ofm)
+		if err = mapstructure.WeakDecode(ofm, &ofConf); err != nil {
+			return
+		}
+		conf.OutputFormats[strings.ToLower(k)] = ofConf
+	}
 
-	// Handle upstream renames.
+	return
+}
+
+// handleRenames handles upstream renames in the minify config m.
+func handleRenames(m map[string]any) {
 	if td, found := m["tdewolff"]; found {
 		tdm := maps.ToStringMap(td)
 		for _, key := range []string{"css", "svg"} {
@@ -113,14 +149,6 @@ func decodeConfig(cfg config.Provider) (conf minifyConfig, err error) {
 			}
 		}
 	}
-
-	err = mapstructure.WeakDecode(m, &conf)
-
-	if err != nil {
-		return
-	}
-
-	return
 }
 
 func init() {
@@ -61,3 +61,39 @@ func TestConfigLegacy(t *testing.T) {
 	c.Assert(err, qt.IsNil)
 	c.Assert(conf.MinifyOutput, qt.Equals, true)
 }
+
+func TestConfigOutputFormats(t *testing.T) {
+	c := qt.New(t)
+	v := config.NewWithTestDefaults()
+
+	v.Set("minify", map[string]any{
+		"minifyOutput": true,
+		"disablexml":   true,
+		"outputformats": map[string]any{
+			"Email": map[string]any{
+				"minifyoutput": false,
+			},
+			"amp": map[string]any{
+				"tdewolff": map[string]any{
+					"css": map[string]any{
+						"decimal": 3,
+					},
+				},
+			},
+		},
+	})
+
+	conf, err := decodeConfig(v)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.MinifyOutput, qt.IsTrue)
+	c.Assert(conf.OutputFormats, qt.HasLen, 2)
+
+	email := conf.OutputFormats["email"]
+	c.Assert(email.MinifyOutput, qt.IsFalse)
+	c.Assert(email.DisableXML, qt.IsTrue)
+
+	amp := conf.OutputFormats["amp"]
+	c.Assert(amp.MinifyOutput, qt.IsTrue)
+	c.Assert(amp.Tdewolff.CSS.Precision, qt.Equals, 3)
+	c.Assert(conf.Tdewolff.CSS.Precision, qt.Equals, 0)
+}
@@ -19,6 +19,7 @@ package minifiers
 import (
 	"io"
 	"regexp"
+	"strings"
 
 	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/output"
@@ -34,6 +35,18 @@ type Client struct {
 	MinifyOutput bool
 
 	m *minify.M
+
+	// The clients configured for specific output formats, keyed by the
+	// lower case output format name.
+	outputFormats map[string]Client
+}
+
+// ForOutputFormat returns the client to use to minify f in the publishing chain.
+func (m Client) ForOutputFormat(f output.Format) Client {
+	if c, found := m.outputFormats[strings.ToLower(f.Name)]; found {
+		return c
+	}
+	return m
 }
 
 // Transformer returns a func that can be used in the transformer publishing chain.
@@ -76,12 +89,25 @@ func (m noopMinifier) Minify(_ *minify.M, w io.Writer, r io.Reader, _ map[string
 // provided list of output formats.
 func New(mediaTypes media.Types, outputFormats output.Formats, cfg config.Provider) (Client, error) {
 	conf, err := decodeConfig(cfg)
-
-	m := minify.New()
 	if err != nil {
 		return Client{}, err
 	}
 
+	client := newClient(conf, mediaTypes, outputFormats)
+
+	for name, ofConf := range conf.OutputFormats {
+		if client.outputFormats == nil {
+			client.outputFormats = make(map[string]Client)
+		}
+		client.outputFormats[name] = newClient(ofConf, mediaTypes, outputFormats)
+	}
+
+	return client, nil
+}
+
+func newClient(conf minifyConfig, mediaTypes media.Types, outputFormats output.Formats) Client {
+	m := minify.New()
+
 	// We use the Type definition of the media types defined in the site if found.
 	addMinifier(m, mediaTypes, "css", getMinifier(conf, "css"))
 
@@ -103,7 +129,11 @@ func New(mediaTypes media.Types, outputFormats output.Formats, cfg config.Provid
 		}
 	}
 
-	return Client{m: m, MinifyOutput: conf.MinifyOutput}, nil
+	for _, t := range conf.DisableMediaTypes {
+		m.Add(t, noopMinifier{})
+	}
+
+	return Client{m: m, MinifyOutput: conf.MinifyOutput}
 }
 
 // getMinifier returns the appropriate minify.MinifierFunc for the MIME
@@ -218,3 +218,45 @@ func TestDecodeConfigKeepWhitespace(t *testing.T) {
 	)
 
 }
+
+func TestMinifyByOutputFormat(t *testing.T) {
+	c := qt.New(t)
+	v := config.NewWithTestDefaults()
+	v.Set("minifyOutput", true)
+	v.Set("minify", map[string]any{
+		"disableMediaTypes": []string{"application/manifest+json"},
+		"outputFormats": map[string]any{
+			"AMP": map[string]any{
+				"tdewolff": map[string]any{
+					"html": map[string]any{
+						"keepComments": true,
+					},
+				},
+			},
+			"calendar": map[string]any{
+				"minifyOutput": false,
+			},
+		},
+	})
+
+	m, err := New(media.DefaultTypes, output.DefaultFormats, v)
+	c.Assert(err, qt.IsNil)
+
+	minify := func(client Client, tp media.Type, s string) string {
+		var b bytes.Buffer
+		c.Assert(client.Minify(tp, &b, strings.NewReader(s)), qt.IsNil)
+		return b.String()
+	}
+
+	html := "<!-- Comment --><p>Hello   <b>World</b></p>\n\n"
+
+	c.Assert(m.ForOutputFormat(output.HTMLFormat).MinifyOutput, qt.IsTrue)
+	c.Assert(minify(m.ForOutputFormat(output.HTMLFormat), media.HTMLType, html), qt.Equals, "<p>Hello <b>World</b></p>")
+	c.Assert(minify(m.ForOutputFormat(output.AMPFormat), media.HTMLType, html), qt.Equals, "<!-- Comment --><p>Hello <b>World</b></p>")
+	c.Assert(m.ForOutputFormat(output.AMPFormat).MinifyOutput, qt.IsTrue)
+	c.Assert(m.ForOutputFormat(output.CalendarFormat).MinifyOutput, qt.IsFalse)
+
+	manifest := media.Type{MainType: "application", SubType: "manifest+json"}
+	c.Assert(minify(m, manifest, `{ "a": 1 }`), qt.Equals, `{ "a": 1 }`)
+	c.Assert(minify(m, media.JSONType, `{ "a": 1 }`), qt.Equals, `{"a":1}`)
+}
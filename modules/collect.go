@@ -85,6 +85,10 @@ func (h *Client) Collect() (ModulesConfig, error) {
 		return mc, err
 	}
 
+	if err := (&mc).checkManifests(h.fs, h.logger); err != nil {
+		return mc, err
+	}
+
 	return mc, nil
 }
 
@@ -390,6 +394,12 @@ func (c *collector) applyMounts(moduleImport Import, mod *moduleAdapter) error {
 		}
 	}
 
+	if !mod.projectMod && modConfig.ColocateAssets {
+		// The project's colocated assets are mounted together with
+		// its default mounts.
+		mounts = append(mounts, colocatedAssetsMounts(mounts)...)
+	}
+
 	var err error
 	mounts, err = c.normalizeMounts(mod, mounts)
 	if err != nil {
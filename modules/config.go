@@ -128,7 +128,7 @@ func ApplyProjectConfigDefaults(cfg config.Provider, mod Module) error {
 				seen := make(map[string]bool)
 				hasContentDir := false
 				for _, language := range languages {
-					if language.ContentDir != "" {
+					if language.ContentDir != "" || len(language.ContentDirs) > 0 {
 						hasContentDir = true
 						break
 					}
@@ -136,15 +136,22 @@ func ApplyProjectConfigDefaults(cfg config.Provider, mod Module) error {
 
 				if hasContentDir {
 					for _, language := range languages {
-						contentDir := language.ContentDir
-						if contentDir == "" {
-							contentDir = files.ComponentFolderContent
+						// The content dirs are mounted in order, and
+						// the first mount wins for files with the same path.
+						contentDirs := language.ContentDirs
+						if len(contentDirs) == 0 {
+							contentDirs = []string{language.ContentDir}
 						}
-						if contentDir == "" || seen[contentDir] {
-							continue
+						for _, contentDir := range contentDirs {
+							if contentDir == "" {
+								contentDir = files.ComponentFolderContent
+							}
+							if seen[contentDir] {
+								continue
+							}
+							seen[contentDir] = true
+							mounts = append(mounts, Mount{Lang: language.Lang, Source: contentDir, Target: d.component})
 						}
-						seen[contentDir] = true
-						mounts = append(mounts, Mount{Lang: language.Lang, Source: contentDir, Target: d.component})
 					}
 				}
 
@@ -183,6 +190,10 @@ func ApplyProjectConfigDefaults(cfg config.Provider, mod Module) error {
 	// Prepend the mounts from configuration.
 	mounts = append(moda.mounts, mounts...)
 
+	if moda.config.ColocateAssets {
+		mounts = append(mounts, colocatedAssetsMounts(mounts)...)
+	}
+
 	moda.mounts = mounts
 
 	return nil
@@ -273,6 +284,21 @@ type Config struct {
 	// Will be validated against the running Hugo version.
 	HugoVersion HugoVersion
 
+	// The layouts and params this module provides, used to detect
+	// conflicts with other modules.
+	Provides Provides
+
+	// Will be validated against the running Hugo binary.
+	Requires Requires
+
+	// The template func namespaces this module provides with plugins.
+	Funcs []Funcs
+
+	// When enabled, the assets (SCSS, JS etc.) below layouts/partials are
+	// also mounted in assets/partials, so they can live next to the partial
+	// templates using them.
+	ColocateAssets bool
+
 	// A optional Glob pattern matching module paths to skip when vendoring, e.g.
 	// "github.com/**".
 	NoVendor string
@@ -404,6 +430,39 @@ func (m Mount) ComponentAndName() (string, string) {
 	return c, n
 }
 
+// The partial templates excluded from the colocated assets mounts.
+var colocatedAssetsExcludeFiles = []string{"**.html", "**.htm", "**.xml", "**.txt"}
+
+// colocatedAssetsMounts creates the assets/partials mounts for the
+// layouts/partials folders in mounts.
+func colocatedAssetsMounts(mounts []Mount) []Mount {
+	partialsTarget := filepath.Join(files.ComponentFolderLayouts, files.FolderPartials)
+
+	var out []Mount
+	for _, m := range mounts {
+		var source, target string
+		switch {
+		case m.Target == files.ComponentFolderLayouts:
+			source = filepath.Join(m.Source, files.FolderPartials)
+			target = filepath.Join(files.ComponentFolderAssets, files.FolderPartials)
+		case m.Target == partialsTarget || strings.HasPrefix(m.Target, partialsTarget+fileSeparator):
+			source = m.Source
+			target = filepath.Join(files.ComponentFolderAssets, strings.TrimPrefix(m.Target, files.ComponentFolderLayouts+fileSeparator))
+		default:
+			continue
+		}
+		out = append(out, Mount{
+			Source:       source,
+			Target:       target,
+			Lang:         m.Lang,
+			IncludeFiles: m.IncludeFiles,
+			ExcludeFiles: colocatedAssetsExcludeFiles,
+		})
+	}
+
+	return out
+}
+
 func getStaticDirs(cfg config.Provider) []string {
 	var staticDirs []string
 	for i := -1; i <= 10; i++ {
@@ -0,0 +1,77 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+)
+
+// Funcs declares a template func namespace provided by a plugin in a module.
+type Funcs struct {
+	// The namespace, e.g. "sci" for sci.Gamma.
+	Namespace string
+
+	// The plugin file, relative to the module dir, e.g. "plugins/sci.so".
+	// Only Go plugins (.so) built with go build -buildmode=plugin are
+	// currently supported.
+	Plugin string
+
+	// Set to provide funcs in a built-in namespace, e.g. "math". The plugin's
+	// funcs are used instead of the built-in funcs with the same name.
+	Override bool
+}
+
+// FuncsPlugin is a template func namespace declared by a module.
+type FuncsPlugin struct {
+	Funcs
+
+	// The module declaring it.
+	Module Module
+
+	// The absolute filename of the plugin.
+	Filename string
+}
+
+// FuncsPlugins returns the template func namespaces declared by mods, in
+// module order. A namespace can only be declared by one module.
+func FuncsPlugins(mods Modules) ([]FuncsPlugin, error) {
+	var plugins []FuncsPlugin
+	declared := make(map[string]Module)
+
+	for _, mod := range mods {
+		for _, funcs := range mod.Config().Funcs {
+			if !token.IsIdentifier(funcs.Namespace) {
+				return nil, fmt.Errorf("module %q: invalid template func namespace %q", pathDisplay(mod), funcs.Namespace)
+			}
+			if funcs.Plugin == "" {
+				return nil, fmt.Errorf("module %q: no plugin set for template func namespace %q", pathDisplay(mod), funcs.Namespace)
+			}
+			if first, found := declared[funcs.Namespace]; found {
+				return nil, fmt.Errorf("template func namespace %q is declared by both %q and %q", funcs.Namespace, pathDisplay(first), pathDisplay(mod))
+			}
+			declared[funcs.Namespace] = mod
+
+			filename := filepath.FromSlash(funcs.Plugin)
+			if !filepath.IsAbs(filename) {
+				filename = filepath.Join(mod.Dir(), filename)
+			}
+
+			plugins = append(plugins, FuncsPlugin{Funcs: funcs, Module: mod, Filename: filename})
+		}
+	}
+
+	return plugins, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/hugo"
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/gohugoio/hugo/hugofs/files"
+	"github.com/spf13/afero"
+)
+
+// Provides declares what a module, typically a theme component, provides.
+type Provides struct {
+	// The templates, relative to the layouts folder, e.g. partials/head.html.
+	Layouts []string
+
+	// The site params the module reads, e.g. colors.primary.
+	Params []string
+}
+
+// Requires declares the optional Hugo features a module needs.
+type Requires struct {
+	// See Features.
+	Features []string
+}
+
+// Features maps the optional Hugo features a module can require to whether
+// they are available in the running Hugo binary.
+var Features = map[string]func() bool{
+	"extended": func() bool { return hugo.IsExtended },
+}
+
+// LayoutOverride describes a template path provided by more than one module.
+type LayoutOverride struct {
+	// The path relative to the layouts folder, e.g. partials/head.html.
+	Path string
+
+	// The module whose template is used.
+	Winner Module
+
+	// The modules whose template is not used, in module order.
+	Overridden []Module
+}
+
+// LayoutOverrides returns the template paths provided by more than one of
+// the active modules, sorted by path.
+// A module provides the templates found in its layouts mounts and those
+// declared in its manifest; the first module in the module order wins.
+func (m *ModulesConfig) LayoutOverrides(fs afero.Fs) ([]LayoutOverride, error) {
+	providers := make(map[string][]Module)
+	for _, mod := range m.ActiveModules {
+		layouts, err := moduleLayouts(fs, mod)
+		if err != nil {
+			return nil, err
+		}
+		for _, layout := range layouts {
+			providers[layout] = append(providers[layout], mod)
+		}
+	}
+
+	var overrides []LayoutOverride
+	for p, mods := range providers {
+		if len(mods) < 2 {
+			continue
+		}
+		overrides = append(overrides, LayoutOverride{Path: p, Winner: mods[0], Overridden: mods[1:]})
+	}
+
+	sort.Slice(overrides, func(i, j int) bool {
+		return overrides[i].Path < overrides[j].Path
+	})
+
+	return overrides, nil
+}
+
+// moduleLayouts returns the templates mod provides, sorted and without duplicates.
+func moduleLayouts(fs afero.Fs, mod Module) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, layout := range mod.Config().Provides.Layouts {
+		seen[path.Clean(filepath.ToSlash(layout))] = true
+	}
+
+	for _, mount := range mod.Mounts() {
+		target := filepath.ToSlash(mount.Target)
+		if mount.Component() != files.ComponentFolderLayouts || mount.Lang != "" {
+			continue
+		}
+		prefix := strings.TrimPrefix(strings.TrimPrefix(target, files.ComponentFolderLayouts), "/")
+
+		source := mount.Source
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(mod.Dir(), source)
+		}
+
+		err := afero.Walk(fs, source, func(filename string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(source, filename)
+			if err != nil {
+				return err
+			}
+			seen[path.Join(prefix, filepath.ToSlash(rel))] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	layouts := make([]string, 0, len(seen))
+	for layout := range seen {
+		layouts = append(layouts, layout)
+	}
+	sort.Strings(layouts)
+
+	return layouts, nil
+}
+
+// checkManifests warns about the features required by the active modules that
+// are not available, and about the layouts and params declared by more than one
+// module. The other layout overrides are logged on the info level.
+func (m *ModulesConfig) checkManifests(fs afero.Fs, logger loggers.Logger) error {
+	for _, mod := range m.ActiveModules {
+		for _, feature := range mod.Config().Requires.Features {
+			available, found := Features[strings.ToLower(feature)]
+			if !found {
+				logger.Warnf("Module %q requires unknown Hugo feature %q.", mod.Path(), feature)
+			} else if !available() {
+				logger.Warnf("Module %q requires Hugo feature %q, which is not available in this Hugo binary.", mod.Path(), feature)
+			}
+		}
+	}
+
+	params := make(map[string]Module)
+	for _, mod := range m.ActiveModules {
+		for _, param := range mod.Config().Provides.Params {
+			key := strings.ToLower(param)
+			if first, found := params[key]; found {
+				logger.Warnf("Param %q is declared by %q and %q.", param, pathDisplay(first), pathDisplay(mod))
+				continue
+			}
+			params[key] = mod
+		}
+	}
+
+	overrides, err := m.LayoutOverrides(fs)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range overrides {
+		var declared []string
+		for _, mod := range append([]Module{o.Winner}, o.Overridden...) {
+			if mod.Config().providesLayout(o.Path) {
+				declared = append(declared, pathDisplay(mod))
+			}
+		}
+		msg := fmt.Sprintf("Layout %q from %q overrides %s.", o.Path, pathDisplay(o.Winner), strings.Join(quoteAll(modulePaths(o.Overridden)), ", "))
+		if len(declared) > 1 {
+			logger.Warnf("%s It is declared by %s.", msg, strings.Join(quoteAll(declared), ", "))
+		} else {
+			logger.Infoln(msg)
+		}
+	}
+
+	return nil
+}
+
+func (c Config) providesLayout(p string) bool {
+	for _, layout := range c.Provides.Layouts {
+		if path.Clean(filepath.ToSlash(layout)) == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Layouts writes the template paths provided by more than one module, the
+// module that wins and the modules it overrides to w.
+func (c *Client) Layouts(w io.Writer) error {
+	mc, err := c.Collect()
+	if err != nil {
+		return err
+	}
+
+	overrides, err := mc.LayoutOverrides(c.fs)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range overrides {
+		fmt.Fprintf(w, "%s: %s overrides %s\n", o.Path, pathDisplay(o.Winner), strings.Join(modulePaths(o.Overridden), ", "))
+	}
+
+	return nil
+}
+
+func pathDisplay(mod Module) string {
+	if mod.Owner() == nil {
+		return "project"
+	}
+	return mod.Path()
+}
+
+func modulePaths(mods []Module) []string {
+	var paths []string
+	for _, mod := range mods {
+		paths = append(paths, pathDisplay(mod))
+	}
+	return paths
+}
+
+func quoteAll(s []string) []string {
+	quoted := make([]string, len(s))
+	for i, v := range s {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return quoted
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package modules
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/spf13/afero"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+func TestCheckManifests(t *testing.T) {
+	c := qt.New(t)
+
+	fs := afero.NewMemMapFs()
+	for _, filename := range []string{
+		"/p/layouts/index.html",
+		"/p/layouts/_default/single.html",
+		"/p/themes/a/layouts/index.html",
+		"/p/themes/a/layouts/partials/head.html",
+		"/p/themes/b/layouts/partials/head.html",
+		"/p/themes/b/layouts/partials/foot.html",
+		"/p/themes/b/mylayouts/single.html",
+	} {
+		c.Assert(afero.WriteFile(fs, filepath.FromSlash(filename), []byte("x"), 0666), qt.IsNil)
+	}
+
+	project := &moduleAdapter{
+		path:   "project",
+		dir:    filepath.FromSlash("/p"),
+		mounts: []Mount{{Source: "layouts", Target: "layouts"}},
+	}
+	a := &moduleAdapter{
+		path:  "a",
+		dir:   filepath.FromSlash("/p/themes/a"),
+		owner: project,
+		config: Config{
+			Provides: Provides{Layouts: []string{"partials/head.html"}, Params: []string{"colors.primary"}},
+			Requires: Requires{Features: []string{"teleport"}},
+		},
+		mounts: []Mount{{Source: "layouts", Target: "layouts"}},
+	}
+	b := &moduleAdapter{
+		path:  "b",
+		dir:   filepath.FromSlash("/p/themes/b"),
+		owner: project,
+		config: Config{
+			Provides: Provides{Layouts: []string{"partials/head.html"}, Params: []string{"Colors.Primary"}},
+		},
+		mounts: []Mount{
+			{Source: "layouts", Target: "layouts"},
+			{Source: "mylayouts", Target: filepath.FromSlash("layouts/_default")},
+		},
+	}
+
+	mc := &ModulesConfig{ActiveModules: Modules{project, a, b}}
+
+	overrides, err := mc.LayoutOverrides(fs)
+	c.Assert(err, qt.IsNil)
+	c.Assert(overrides, qt.HasLen, 3)
+	c.Assert(overrides[0].Path, qt.Equals, "_default/single.html")
+	c.Assert(overrides[0].Winner, qt.Equals, Module(project))
+	c.Assert(modulePaths(overrides[0].Overridden), qt.DeepEquals, []string{"b"})
+	c.Assert(overrides[1].Path, qt.Equals, "index.html")
+	c.Assert(modulePaths(overrides[1].Overridden), qt.DeepEquals, []string{"a"})
+	c.Assert(overrides[2].Path, qt.Equals, "partials/head.html")
+	c.Assert(overrides[2].Winner, qt.Equals, Module(a))
+
+	var buf bytes.Buffer
+	c.Assert(mc.checkManifests(fs, loggers.NewBasicLoggerForWriter(jww.LevelInfo, &buf)), qt.IsNil)
+
+	log := buf.String()
+	c.Assert(log, qt.Contains, `Module "a" requires unknown Hugo feature "teleport".`)
+	c.Assert(log, qt.Contains, `Param "Colors.Primary" is declared by "a" and "b".`)
+	c.Assert(log, qt.Contains, `Layout "index.html" from "project" overrides "a".`)
+	c.Assert(log, qt.Contains, `Layout "partials/head.html" from "a" overrides "b". It is declared by "a", "b".`)
+}
@@ -296,6 +296,49 @@ func (m Menu) Reverse() Menu {
 	return menus
 }
 
+// ActiveTrail returns the menu entries from the top level of m down to the
+// entry for p. If p is not in the menu, the trail ends with the deepest
+// entry for one of p's ancestors, the home page excluded.
+func (m Menu) ActiveTrail(p Page) Menu {
+	if types.IsNil(p) {
+		return nil
+	}
+
+	var exact, ancestor Menu
+
+	var walk func(menu Menu, trail Menu)
+	walk = func(menu Menu, trail Menu) {
+		for _, e := range menu {
+			if exact != nil {
+				return
+			}
+			t := append(trail[:len(trail):len(trail)], e)
+			if e.isSamePage(p) || (types.IsNil(e.Page) && e.ConfiguredURL != "" && e.ConfiguredURL == p.RelPermalink()) {
+				exact = t
+				return
+			}
+			if !types.IsNil(e.Page) && !isHome(e.Page) && len(t) > len(ancestor) {
+				if ok, _ := e.Page.IsAncestor(p); ok {
+					ancestor = t
+				}
+			}
+			walk(e.Children, t)
+		}
+	}
+
+	walk(m, nil)
+
+	if exact != nil {
+		return exact
+	}
+
+	return ancestor
+}
+
+func isHome(p Page) bool {
+	return p.Section() == "" && !p.IsPage() && !p.IsSection()
+}
+
 // Clone clones the menu entries.
 // This is for internal use only.
 func (m Menu) Clone() Menu {
@@ -0,0 +1,168 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package navigation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+// The sources a menu can be generated from.
+const (
+	MenuSourceSection  = "section"
+	MenuSourceTaxonomy = "taxonomy"
+	MenuSourceData     = "data"
+)
+
+// The page filters available in MenuGenerator.Exclude.
+const (
+	MenuExcludeDrafts  = "drafts"
+	MenuExcludeFuture  = "future"
+	MenuExcludeExpired = "expired"
+)
+
+// MenuGenerator configures how to generate menu entries from the site's
+// pages or data, e.g.:
+//
+//	[[menuGenerators.main]]
+//	source = "section"
+//	section = "docs"
+//	depth = 2
+//	exclude = ["drafts", "future"]
+type MenuGenerator struct {
+	// One of section, taxonomy or data.
+	Source string
+
+	// For source section, the path to the root section, e.g. "docs".
+	// Leave empty to start from the top level sections.
+	Section string
+
+	// For source taxonomy, the plural name of the taxonomy, e.g. "categories".
+	Taxonomy string
+
+	// For source data, the slash separated path to the menu entries in the
+	// site data, e.g. "menus/footer". Entries in a file with the language
+	// code added, e.g. data/menus/footer.nn.toml, are merged on top of these
+	// for that language.
+	Data string
+
+	// The number of levels of nested sections (or terms and their pages)
+	// to include. Defaults to 1.
+	Depth int
+
+	// Whether to include the regular pages in the sections.
+	Pages bool
+
+	// The identifier of the menu entry to add the generated top level entries to.
+	Parent string
+
+	// Pages to exclude, any of drafts, future and expired.
+	Exclude []string
+
+	// If set, only include regular pages with these param values.
+	Params map[string]any
+}
+
+// ExcludeDrafts returns whether draft pages should be excluded.
+func (g MenuGenerator) ExcludeDrafts() bool {
+	return g.excludes(MenuExcludeDrafts)
+}
+
+// ExcludeFuture returns whether pages with a publish date in the future should be excluded.
+func (g MenuGenerator) ExcludeFuture() bool {
+	return g.excludes(MenuExcludeFuture)
+}
+
+// ExcludeExpired returns whether expired pages should be excluded.
+func (g MenuGenerator) ExcludeExpired() bool {
+	return g.excludes(MenuExcludeExpired)
+}
+
+func (g MenuGenerator) excludes(s string) bool {
+	for _, v := range g.Exclude {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchParams returns whether the values returned by param match the configured params.
+func (g MenuGenerator) MatchParams(param func(key any) (any, error)) bool {
+	for k, want := range g.Params {
+		v, err := param(k)
+		if err != nil || v == nil {
+			return false
+		}
+		if cast.ToString(v) != cast.ToString(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// DecodeMenuGenerators decodes the menu generators, keyed by menu name.
+func DecodeMenuGenerators(in any) (map[string][]MenuGenerator, error) {
+	m, err := maps.ToStringMapE(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode menuGenerators: %w", err)
+	}
+
+	generators := make(map[string][]MenuGenerator)
+
+	for menu, v := range m {
+		var gens []MenuGenerator
+		if err := mapstructure.WeakDecode(v, &gens); err != nil {
+			return nil, fmt.Errorf("failed to decode menuGenerators for menu %q: %w", menu, err)
+		}
+		for i, g := range gens {
+			g.Source = strings.ToLower(g.Source)
+			switch g.Source {
+			case MenuSourceSection:
+			case MenuSourceTaxonomy:
+				if g.Taxonomy == "" {
+					return nil, fmt.Errorf("menu generator for menu %q: taxonomy must be set", menu)
+				}
+			case MenuSourceData:
+				if g.Data == "" {
+					return nil, fmt.Errorf("menu generator for menu %q: data must be set", menu)
+				}
+			default:
+				return nil, fmt.Errorf("menu generator for menu %q: invalid source %q, must be one of section, taxonomy or data", menu, g.Source)
+			}
+			for j, ex := range g.Exclude {
+				ex = strings.ToLower(ex)
+				switch ex {
+				case MenuExcludeDrafts, MenuExcludeFuture, MenuExcludeExpired:
+				default:
+					return nil, fmt.Errorf("menu generator for menu %q: invalid exclude %q, must be one of drafts, future or expired", menu, ex)
+				}
+				g.Exclude[j] = ex
+			}
+			if g.Depth <= 0 {
+				g.Depth = 1
+			}
+			g.Section = strings.Trim(g.Section, "/")
+			g.Data = strings.Trim(g.Data, "/")
+			gens[i] = g
+		}
+		generators[menu] = gens
+	}
+
+	return generators, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package navigation
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeMenuGenerators(t *testing.T) {
+	c := qt.New(t)
+
+	gens, err := DecodeMenuGenerators(map[string]any{
+		"main": []any{
+			map[string]any{"source": "Section", "section": "/docs/", "exclude": []any{"Drafts"}},
+			map[string]any{"source": "data", "data": "menus/main", "depth": "2"},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(gens["main"], qt.HasLen, 2)
+	c.Assert(gens["main"][0].Source, qt.Equals, MenuSourceSection)
+	c.Assert(gens["main"][0].Section, qt.Equals, "docs")
+	c.Assert(gens["main"][0].Depth, qt.Equals, 1)
+	c.Assert(gens["main"][0].ExcludeDrafts(), qt.IsTrue)
+	c.Assert(gens["main"][0].ExcludeFuture(), qt.IsFalse)
+	c.Assert(gens["main"][1].Depth, qt.Equals, 2)
+
+	_, err = DecodeMenuGenerators(map[string]any{"main": []any{map[string]any{"source": "foo"}}})
+	c.Assert(err, qt.ErrorMatches, `.*invalid source "foo".*`)
+	_, err = DecodeMenuGenerators(map[string]any{"main": []any{map[string]any{"source": "taxonomy"}}})
+	c.Assert(err, qt.ErrorMatches, `.*taxonomy must be set`)
+	_, err = DecodeMenuGenerators(map[string]any{"main": []any{map[string]any{"source": "section", "exclude": []any{"old"}}}})
+	c.Assert(err, qt.ErrorMatches, `.*invalid exclude "old".*`)
+}
+
+func TestMenuGeneratorMatchParams(t *testing.T) {
+	c := qt.New(t)
+
+	g := MenuGenerator{Params: map[string]any{"featured": true}}
+	param := func(v any) func(key any) (any, error) {
+		return func(key any) (any, error) { return v, nil }
+	}
+
+	c.Assert(g.MatchParams(param(true)), qt.IsTrue)
+	c.Assert(g.MatchParams(param("true")), qt.IsTrue)
+	c.Assert(g.MatchParams(param(false)), qt.IsFalse)
+	c.Assert(g.MatchParams(param(nil)), qt.IsFalse)
+	c.Assert(MenuGenerator{}.MatchParams(param(nil)), qt.IsTrue)
+}
@@ -18,6 +18,7 @@ import (
 	"sync"
 
 	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/media"
 )
 
 // These may be used as content sections with potential conflicts. Avoid that.
@@ -38,6 +39,14 @@ type LayoutDescriptor struct {
 	// Comma-separated list of kind variants, e.g. "go,json" as variants which would find "render-codeblock-go.html"
 	KindVariants string
 
+	// For custom page kinds, e.g. "author", the kind of page it is built
+	// from, e.g. "page" or "section", which decides the fallback layouts.
+	BaseKind string
+
+	// For custom page kinds, a comma-separated list of layouts to try after
+	// the kind itself, e.g. "person".
+	KindLayouts string
+
 	Lang   string
 	Layout string
 	// LayoutOverride indicates what we should only look for the above layout.
@@ -45,14 +54,24 @@ type LayoutDescriptor struct {
 
 	RenderingHook bool
 	Baseof        bool
+
+	// Comma-separated list of the values of the dimensions configured in
+	// layoutLookup, in the same order.
+	Dimensions string
 }
 
 func (d LayoutDescriptor) isList() bool {
-	return !d.RenderingHook && d.Kind != "page" && d.Kind != "404"
+	kind := d.Kind
+	if d.BaseKind != "" {
+		kind = d.BaseKind
+	}
+	return !d.RenderingHook && kind != "page" && kind != "404"
 }
 
 // LayoutHandler calculates the layout template to use to render a given output type.
 type LayoutHandler struct {
+	rules LayoutRulesConfig
+
 	mu    sync.RWMutex
 	cache map[layoutCacheKey][]string
 }
@@ -64,7 +83,13 @@ type layoutCacheKey struct {
 
 // NewLayoutHandler creates a new LayoutHandler.
 func NewLayoutHandler() *LayoutHandler {
-	return &LayoutHandler{cache: make(map[layoutCacheKey][]string)}
+	return NewLayoutHandlerWithRules(LayoutRulesConfig{})
+}
+
+// NewLayoutHandlerWithRules creates a new LayoutHandler with the given
+// project-defined lookup rules.
+func NewLayoutHandlerWithRules(rules LayoutRulesConfig) *LayoutHandler {
+	return &LayoutHandler{rules: rules, cache: make(map[layoutCacheKey][]string)}
 }
 
 // For returns a layout for the given LayoutDescriptor and options.
@@ -79,7 +104,7 @@ func (l *LayoutHandler) For(d LayoutDescriptor, f Format) ([]string, error) {
 	}
 	l.mu.RUnlock()
 
-	layouts := resolvePageTemplate(d, f)
+	layouts := resolvePageTemplate(d, f, l.rules)
 
 	layouts = helpers.UniqueStringsReuse(layouts)
 
@@ -134,8 +159,9 @@ func (l *layoutBuilder) addKind() {
 
 const renderingHookRoot = "/_markup"
 
-func resolvePageTemplate(d LayoutDescriptor, f Format) []string {
+func resolvePageTemplate(d LayoutDescriptor, f Format, rules LayoutRulesConfig) []string {
 	b := &layoutBuilder{d: d, f: f}
+	kind := d.Kind
 
 	if !d.RenderingHook && d.Layout != "" {
 		b.addLayoutVariations(d.Layout)
@@ -155,6 +181,17 @@ func resolvePageTemplate(d LayoutDescriptor, f Format) []string {
 		b.addSectionType()
 	}
 
+	if d.BaseKind != "" {
+		// A custom kind, e.g. author.html, before the layouts of the
+		// kind it's built from.
+		b.addKind()
+		if d.KindLayouts != "" {
+			b.addLayoutVariations(strings.Split(d.KindLayouts, ",")...)
+		}
+		d.Kind = d.BaseKind
+		b.d.Kind = d.BaseKind
+	}
+
 	switch d.Kind {
 	case "page":
 		b.addLayoutVariations("single")
@@ -212,16 +249,50 @@ func resolvePageTemplate(d LayoutDescriptor, f Format) []string {
 
 	layouts := b.resolveVariations()
 
+	rd := d
+	rd.Kind = kind
+	if first, last := rules.resolveRules(b, rd); first != nil || last != nil {
+		layouts = append(append(first, layouts...), last...)
+	}
+
 	if !d.RenderingHook && !d.Baseof && isRSS {
 		layouts = append(layouts, "_internal/_default/rss.xml")
 	}
 
+	if !d.RenderingHook && !d.Baseof && f.Name == CalendarFormat.Name {
+		layouts = append(layouts, "_internal/_default/calendar.ics")
+	}
+
+	if !d.RenderingHook && !d.Baseof && f.Name == ActivityPubFormat.Name {
+		layouts = append(layouts, "_internal/_default/activitypub.json")
+	}
+
+	if !d.RenderingHook && !d.Baseof && f.Name == ActivityPubOutboxFormat.Name {
+		layouts = append(layouts, "_internal/_default/activitypub_outbox.json")
+	}
+
+	if !d.RenderingHook && !d.Baseof && f.Name == WebFingerFormat.Name {
+		layouts = append(layouts, "_internal/_default/index.webfinger")
+	}
+
+	if !d.RenderingHook && !d.Baseof && f.Name == PodcastFormat.Name {
+		layouts = append(layouts, "_internal/_default/podcast.xml")
+	}
+
+	if !d.RenderingHook && !d.Baseof && f.Name == LLMsFormat.Name {
+		layouts = append(layouts, "_internal/_default/llms.txt")
+	}
+
+	if !d.RenderingHook && !d.Baseof && f.Name == MarkdownFormat.Name {
+		layouts = append(layouts, "_internal/_default/markdown.md")
+	}
+
 	return layouts
 }
 
-func (l *layoutBuilder) resolveVariations() []string {
-	var layouts []string
-
+// formatVariations returns the language and output format variations of
+// the layout names, most specific first.
+func (l *layoutBuilder) formatVariations() []string {
 	var variations []string
 	name := strings.ToLower(l.f.Name)
 
@@ -232,7 +303,22 @@ func (l *layoutBuilder) resolveVariations() []string {
 		variations = append(variations, name)
 	}
 
-	variations = append(variations, "")
+	return append(variations, "")
+}
+
+func (l *layoutBuilder) suffix() string {
+	if l.f.Name == PDFFormat.Name {
+		// PDF is rendered from HTML templates.
+		return media.HTMLType.FirstSuffix.Suffix
+	}
+	return l.f.MediaType.FirstSuffix.Suffix
+}
+
+func (l *layoutBuilder) resolveVariations() []string {
+	var layouts []string
+
+	variations := l.formatVariations()
+	suffix := l.suffix()
 
 	for _, typeVar := range l.typeVariations {
 		for _, variation := range variations {
@@ -241,7 +327,7 @@ func (l *layoutBuilder) resolveVariations() []string {
 					continue
 				}
 
-				s := constructLayoutPath(typeVar, layoutVar, variation, l.f.MediaType.FirstSuffix.Suffix)
+				s := constructLayoutPath(typeVar, layoutVar, variation, suffix)
 				if s != "" {
 					layouts = append(layouts, s)
 				}
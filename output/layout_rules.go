@@ -0,0 +1,247 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// The positions of a LayoutRule in the lookup order.
+const (
+	LayoutRuleFirst = "first"
+	LayoutRuleLast  = "last"
+)
+
+// The placeholders available in the paths of all rules, dimensions come in addition.
+var layoutRulePlaceholders = map[string]bool{
+	"kind":    true,
+	"type":    true,
+	"section": true,
+	"lang":    true,
+	"layout":  true,
+}
+
+var (
+	layoutRulePlaceholderRe = regexp.MustCompile(`\{([^{}]*)\}`)
+	layoutDimensionRe       = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// LayoutRulesConfig configures project-defined template lookup rules, e.g.:
+//
+//	[layoutLookup]
+//	dimensions = ["brand"]
+//	[[layoutLookup.rules]]
+//	kinds = ["page"]
+//	paths = ["brands/{brand}/{type}/{layout}", "brands/{brand}/{layout}"]
+type LayoutRulesConfig struct {
+	// Additional placeholders, resolved from the page params, falling back to
+	// the site params, e.g. brand.
+	Dimensions []string
+
+	Rules []LayoutRule
+}
+
+// LayoutRule adds paths to the template lookup order.
+type LayoutRule struct {
+	// The page kinds the rule applies to, all if empty.
+	Kinds []string
+
+	// The names of the output formats the rule applies to, all if empty.
+	OutputFormats []string
+
+	// Whether the paths are tried before the built-in lookup order, "first",
+	// the default, or after it, "last".
+	Position string
+
+	// The paths relative to the layouts folder, ending with {layout}, which
+	// is replaced with each of the layout names of the built-in lookup order,
+	// e.g. single. The other placeholders are {kind}, {type}, {section},
+	// {lang} and the dimensions; paths with an empty value are skipped.
+	// As in the built-in lookup order, {section} is only set for section,
+	// taxonomy and term pages, {type} defaults to the section.
+	// The output format and language variants and the suffix are added as in
+	// the built-in lookup order.
+	Paths []string
+}
+
+// DecodeLayoutRulesConfig decodes and validates the layoutLookup section in
+// site config.
+func DecodeLayoutRulesConfig(in any) (LayoutRulesConfig, error) {
+	var c LayoutRulesConfig
+	if in == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode layoutLookup config: %w", err)
+	}
+
+	placeholders := make(map[string]bool)
+	for k := range layoutRulePlaceholders {
+		placeholders[k] = true
+	}
+	for i, dim := range c.Dimensions {
+		dim = strings.ToLower(dim)
+		if !layoutDimensionRe.MatchString(dim) {
+			return c, fmt.Errorf("layoutLookup: invalid dimension name %q", dim)
+		}
+		if placeholders[dim] {
+			return c, fmt.Errorf("layoutLookup: dimension %q is already defined", dim)
+		}
+		placeholders[dim] = true
+		c.Dimensions[i] = dim
+	}
+
+	for i, r := range c.Rules {
+		switch strings.ToLower(r.Position) {
+		case "", LayoutRuleFirst:
+			r.Position = LayoutRuleFirst
+		case LayoutRuleLast:
+			r.Position = LayoutRuleLast
+		default:
+			return c, fmt.Errorf("layoutLookup: invalid rule position %q, must be first or last", r.Position)
+		}
+
+		if len(r.Paths) == 0 {
+			return c, fmt.Errorf("layoutLookup: rule %d has no paths", i+1)
+		}
+
+		for _, p := range r.Paths {
+			if path.Base(p) != "{layout}" || strings.Count(p, "{layout}") != 1 || strings.HasPrefix(p, "/") {
+				return c, fmt.Errorf("layoutLookup: path %q must be relative and end with {layout}", p)
+			}
+			for _, m := range layoutRulePlaceholderRe.FindAllStringSubmatch(p, -1) {
+				if !placeholders[strings.ToLower(m[1])] {
+					return c, fmt.Errorf("layoutLookup: unknown placeholder %q in path %q", m[0], p)
+				}
+			}
+		}
+
+		for j, k := range r.Kinds {
+			r.Kinds[j] = strings.ToLower(k)
+		}
+
+		c.Rules[i] = r
+	}
+
+	return c, nil
+}
+
+func (r LayoutRule) appliesTo(d LayoutDescriptor, f Format) bool {
+	if len(r.Kinds) > 0 {
+		var found bool
+		for _, k := range r.Kinds {
+			if k == d.Kind || (d.BaseKind != "" && k == d.BaseKind) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.OutputFormats) > 0 {
+		var found bool
+		for _, name := range r.OutputFormats {
+			if strings.EqualFold(name, f.Name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolveRules returns the paths added by the rules before and after the
+// built-in lookup order.
+func (c LayoutRulesConfig) resolveRules(b *layoutBuilder, d LayoutDescriptor) (first, last []string) {
+	if len(c.Rules) == 0 || d.RenderingHook {
+		return
+	}
+
+	values := map[string]string{
+		"kind":    d.Kind,
+		"type":    d.Type,
+		"section": d.Section,
+		"lang":    d.Lang,
+	}
+	dims := strings.Split(d.Dimensions, ",")
+	for i, dim := range c.Dimensions {
+		if i < len(dims) {
+			values[dim] = dims[i]
+		}
+	}
+
+	variations := b.formatVariations()
+	suffix := b.suffix()
+
+	for _, r := range c.Rules {
+		if !r.appliesTo(d, b.f) {
+			continue
+		}
+
+		var paths []string
+		for _, p := range r.Paths {
+			dir, ok := expandLayoutRulePath(path.Dir(p), values)
+			if !ok {
+				continue
+			}
+			for _, variation := range variations {
+				for _, layoutVar := range b.layoutVariations {
+					if layoutVar == "" {
+						continue
+					}
+					if s := constructLayoutPath(dir, layoutVar, variation, suffix); s != "" {
+						paths = append(paths, s)
+					}
+				}
+			}
+		}
+
+		if r.Position == LayoutRuleLast {
+			last = append(last, paths...)
+		} else {
+			first = append(first, paths...)
+		}
+	}
+
+	return
+}
+
+// expandLayoutRulePath replaces the placeholders in dir, returning false if
+// any of them has no value.
+func expandLayoutRulePath(dir string, values map[string]string) (string, bool) {
+	if dir == "." {
+		return "", true
+	}
+	ok := true
+	dir = layoutRulePlaceholderRe.ReplaceAllStringFunc(dir, func(s string) string {
+		v := values[strings.ToLower(s[1:len(s)-1])]
+		if v == "" {
+			ok = false
+		}
+		return v
+	})
+	return dir, ok
+}
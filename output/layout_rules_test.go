@@ -0,0 +1,99 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeLayoutRulesConfig(t *testing.T) {
+	c := qt.New(t)
+
+	conf, err := DecodeLayoutRulesConfig(map[string]any{
+		"dimensions": []any{"Brand"},
+		"rules": []any{
+			map[string]any{"kinds": []any{"Page"}, "paths": []any{"brands/{brand}/{layout}"}},
+			map[string]any{"position": "LAST", "paths": []any{"{section}/{type}/{layout}"}},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Dimensions, qt.DeepEquals, []string{"brand"})
+	c.Assert(conf.Rules, qt.HasLen, 2)
+	c.Assert(conf.Rules[0].Position, qt.Equals, LayoutRuleFirst)
+	c.Assert(conf.Rules[0].Kinds, qt.DeepEquals, []string{"page"})
+	c.Assert(conf.Rules[1].Position, qt.Equals, LayoutRuleLast)
+
+	for _, test := range []struct {
+		name string
+		in   map[string]any
+		err  string
+	}{
+		{"unknown placeholder", map[string]any{"rules": []any{map[string]any{"paths": []any{"{brand}/{layout}"}}}}, `.*unknown placeholder "{brand}".*`},
+		{"no layout", map[string]any{"rules": []any{map[string]any{"paths": []any{"{section}"}}}}, `.*must be relative and end with {layout}.*`},
+		{"layout in dir", map[string]any{"rules": []any{map[string]any{"paths": []any{"{layout}/{layout}"}}}}, `.*must be relative and end with {layout}.*`},
+		{"absolute", map[string]any{"rules": []any{map[string]any{"paths": []any{"/{section}/{layout}"}}}}, `.*must be relative.*`},
+		{"no paths", map[string]any{"rules": []any{map[string]any{"kinds": []any{"page"}}}}, `.*rule 1 has no paths.*`},
+		{"position", map[string]any{"rules": []any{map[string]any{"position": "middle", "paths": []any{"{layout}"}}}}, `.*invalid rule position "middle".*`},
+		{"builtin dimension", map[string]any{"dimensions": []any{"section"}}, `.*dimension "section" is already defined.*`},
+		{"invalid dimension", map[string]any{"dimensions": []any{"my-brand"}}, `.*invalid dimension name "my-brand".*`},
+	} {
+		c.Run(test.name, func(c *qt.C) {
+			_, err := DecodeLayoutRulesConfig(test.in)
+			c.Assert(err, qt.ErrorMatches, test.err)
+		})
+	}
+}
+
+func TestLayoutRules(t *testing.T) {
+	c := qt.New(t)
+
+	rules, err := DecodeLayoutRulesConfig(map[string]any{
+		"dimensions": []any{"brand"},
+		"rules": []any{
+			map[string]any{"kinds": []any{"page"}, "paths": []any{"brands/{brand}/{type}/{layout}"}},
+			map[string]any{"outputFormats": []any{"html"}, "position": "last", "paths": []any{"shared/{layout}"}},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+
+	h := NewLayoutHandlerWithRules(rules)
+
+	layouts, err := h.For(LayoutDescriptor{Kind: "page", Type: "posts", Dimensions: "acme"}, HTMLFormat)
+	c.Assert(err, qt.IsNil)
+	c.Assert(layouts[:3], qt.DeepEquals, []string{
+		"brands/acme/posts/single.html.html",
+		"brands/acme/posts/single.html",
+		"posts/single.html.html",
+	})
+	c.Assert(layouts[len(layouts)-2:], qt.DeepEquals, []string{
+		"shared/single.html.html",
+		"shared/single.html",
+	})
+
+	// No brand, the first rule is skipped.
+	layouts, err = h.For(LayoutDescriptor{Kind: "page", Type: "posts"}, HTMLFormat)
+	c.Assert(err, qt.IsNil)
+	c.Assert(layouts[0], qt.Equals, "posts/single.html.html")
+
+	// Not a page, not HTML.
+	layouts, err = h.For(LayoutDescriptor{Kind: "section", Section: "posts", Dimensions: "acme"}, JSONFormat)
+	c.Assert(err, qt.IsNil)
+	c.Assert(layouts[0], qt.Equals, "posts/posts.json.json")
+	for _, l := range layouts {
+		c.Assert(l, qt.Not(qt.Contains), "brands")
+		c.Assert(l, qt.Not(qt.Contains), "shared")
+	}
+}
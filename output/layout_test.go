@@ -587,6 +587,79 @@ func TestLayout(t *testing.T) {
 				"_default/single.html",
 			},
 		},
+		{
+			"Page PDF",
+			LayoutDescriptor{Kind: "page"},
+			"", PDFFormat,
+			[]string{
+				"_default/single.pdf.html",
+				"_default/single.html",
+			},
+		},
+		{
+			"Custom kind",
+			LayoutDescriptor{Kind: "author", BaseKind: "page", KindLayouts: "person", Type: "authors", Section: "authors"},
+			"", htmlFormat,
+			[]string{
+				"authors/author.html.html",
+				"authors/person.html.html",
+				"authors/single.html.html",
+				"authors/author.html",
+				"authors/person.html",
+				"authors/single.html",
+				"author/author.html.html",
+				"author/person.html.html",
+				"author/single.html.html",
+				"author/author.html",
+				"author/person.html",
+				"author/single.html",
+				"_default/author.html.html",
+				"_default/person.html.html",
+				"_default/single.html.html",
+				"_default/author.html",
+				"_default/person.html",
+				"_default/single.html",
+			},
+		},
+		{
+			"Custom kind, section",
+			LayoutDescriptor{Kind: "changelog", BaseKind: "section", Type: "changes", Section: "changes"},
+			"", ampType,
+			[]string{
+				"changes/changelog.amp.html",
+				"changes/changes.amp.html",
+				"changes/section.amp.html",
+				"changes/list.amp.html",
+				"changes/changelog.html",
+				"changes/changes.html",
+				"changes/section.html",
+				"changes/list.html",
+				"changelog/changelog.amp.html",
+				"changelog/changes.amp.html",
+				"changelog/section.amp.html",
+				"changelog/list.amp.html",
+				"changelog/changelog.html",
+				"changelog/changes.html",
+				"changelog/section.html",
+				"changelog/list.html",
+				"section/changelog.amp.html",
+				"section/changes.amp.html",
+				"section/section.amp.html",
+				"section/list.amp.html",
+				"section/changelog.html",
+				"section/changes.html",
+				"section/section.html",
+				"section/list.html",
+				"_default/changelog.amp.html",
+				"_default/changes.amp.html",
+				"_default/section.amp.html",
+				"_default/list.amp.html",
+				"_default/changelog.html",
+				"_default/changes.html",
+				"_default/section.html",
+				"_default/list.html",
+			},
+		},
 		{
 			"Page, baseof",
 			LayoutDescriptor{Kind: "page", Baseof: true},
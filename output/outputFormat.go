@@ -63,6 +63,14 @@ type Format struct {
 	// Enable to ignore the global uglyURLs setting.
 	NoUgly bool `json:"noUgly"`
 
+	// Enable to always use ugly URLs for this format, e.g. /posts/p1.json,
+	// regardless of the uglyURLs setting. NoUgly takes precedence.
+	Ugly bool `json:"ugly"`
+
+	// Enable to link to the index files of this format without a trailing
+	// slash, e.g. /posts/p1 instead of /posts/p1/.
+	NoTrailingSlash bool `json:"noTrailingSlash"`
+
 	// Enable if it doesn't make sense to include this format in an alternative
 	// format listing, CSS being one good example.
 	// Note that we use the term "alternative" and not "alternate" here, as it
@@ -80,10 +88,34 @@ type Format struct {
 
 	// Setting this to a non-zero value will be used as the first sort criteria.
 	Weight int `json:"weight"`
+
+	// The name of the output profile, e.g. "amp", the rendered HTML is
+	// post-processed with to satisfy its constraints.
+	Profile string `json:"profile"`
 }
 
 // An ordered list of built-in output formats.
 var (
+	// ActivityPubFormat is the ActivityStreams actor of the site on the home
+	// page and an object, e.g. an Article, on the other pages.
+	ActivityPubFormat = Format{
+		Name:        "ActivityPub",
+		MediaType:   media.JSONType,
+		BaseName:    "activity",
+		IsPlainText: true,
+		Rel:         "alternate",
+	}
+
+	// ActivityPubOutboxFormat is the outbox collection of the site actor.
+	ActivityPubOutboxFormat = Format{
+		Name:           "ActivityPubOutbox",
+		MediaType:      media.JSONType,
+		BaseName:       "outbox",
+		IsPlainText:    true,
+		NotAlternative: true,
+		Rel:            "alternate",
+	}
+
 	AMPFormat = Format{
 		Name:          "AMP",
 		MediaType:     media.HTMLType,
@@ -150,6 +182,57 @@ var (
 		Rel:            "manifest",
 	}
 
+	// LLMsFormat is a llms.txt file, see https://llmstxt.org, listing the
+	// pages included by the llms section in site config.
+	LLMsFormat = Format{
+		Name:           "llms",
+		MediaType:      media.TextType,
+		BaseName:       "llms",
+		IsPlainText:    true,
+		NotAlternative: true,
+		Rel:            "alternate",
+	}
+
+	// MarkdownFormat is a plain Markdown export of a page.
+	MarkdownFormat = Format{
+		Name:        "Markdown",
+		MediaType:   media.MarkdownType,
+		BaseName:    "index",
+		IsPlainText: true,
+		Rel:         "alternate",
+	}
+
+	// WebFingerFormat is the WebFinger document of the site actor,
+	// published to /.well-known/webfinger.
+	WebFingerFormat = Format{
+		Name:           "WebFinger",
+		MediaType:      media.WebFingerType,
+		Path:           ".well-known",
+		BaseName:       "webfinger",
+		IsPlainText:    true,
+		NotAlternative: true,
+		Rel:            "lrdd",
+	}
+
+	// PDFFormat is rendered from HTML templates, e.g. single.pdf.html, and
+	// converted to PDF by the renderer configured in the pdf section in site config.
+	PDFFormat = Format{
+		Name:      "PDF",
+		MediaType: media.PDFType,
+		BaseName:  "index",
+		Rel:       "alternate",
+	}
+
+	// PodcastFormat is an RSS feed with the iTunes, Spotify and Podcasting 2.0
+	// tags of the pages with audio.
+	PodcastFormat = Format{
+		Name:      "Podcast",
+		MediaType: media.RSSType,
+		BaseName:  "podcast",
+		NoUgly:    true,
+		Rel:       "alternate",
+	}
+
 	RobotsTxtFormat = Format{
 		Name:        "ROBOTS",
 		MediaType:   media.TextType,
@@ -177,13 +260,20 @@ var (
 
 // DefaultFormats contains the default output formats supported by Hugo.
 var DefaultFormats = Formats{
+	ActivityPubFormat,
+	ActivityPubOutboxFormat,
 	AMPFormat,
 	CalendarFormat,
 	CSSFormat,
 	CSVFormat,
 	HTMLFormat,
 	JSONFormat,
+	LLMsFormat,
+	MarkdownFormat,
 	WebAppManifestFormat,
+	WebFingerFormat,
+	PDFFormat,
+	PodcastFormat,
 	RobotsTxtFormat,
 	RSSFormat,
 	SitemapFormat,
@@ -280,10 +370,37 @@ func (formats Formats) FromFilename(filename string) (f Format, found bool) {
 	if ext != "" {
 		f, found = formats.GetBySuffix(ext)
 		if !found && len(parts) == 2 {
+			// The suffix may be shared, e.g. robots.txt and llms.txt.
+			if f, found = formats.GetByName(parts[0]); found {
+				return
+			}
 			// For extensionless output formats (e.g. Netlify's _redirects)
 			// we must fall back to using the extension as format lookup.
 			f, found = formats.GetByName(ext)
 		}
+		if !found {
+			f, found = formats.getPlainTextBySuffix(ext)
+		}
+	}
+	return
+}
+
+// getPlainTextBySuffix returns the first format with the given suffix if all
+// the formats with that suffix are plain text, so it does not matter which
+// one is used to parse a template.
+func (formats Formats) getPlainTextBySuffix(suffix string) (f Format, found bool) {
+	for _, ff := range formats {
+		for _, suffix2 := range ff.MediaType.Suffixes() {
+			if !strings.EqualFold(suffix, suffix2) {
+				continue
+			}
+			if !ff.IsPlainText {
+				return Format{}, false
+			}
+			if !found {
+				f, found = ff, true
+			}
+		}
 	}
 	return
 }
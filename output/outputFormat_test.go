@@ -68,7 +68,7 @@ func TestDefaultTypes(t *testing.T) {
 	c.Assert(RSSFormat.NoUgly, qt.Equals, true)
 	c.Assert(CalendarFormat.IsHTML, qt.Equals, false)
 
-	c.Assert(len(DefaultFormats), qt.Equals, 10)
+	c.Assert(len(DefaultFormats), qt.Equals, 17)
 
 }
 
@@ -136,6 +136,18 @@ func TestGetFormatByFilename(t *testing.T) {
 	c.Assert(f, qt.Equals, noExt)
 	_, found = formats.FromFilename("my.css")
 	c.Assert(found, qt.Equals, false)
+
+	// Shared suffix.
+	formats = Formats{RobotsTxtFormat, LLMsFormat, HTMLFormat}
+	f, found = formats.FromFilename("llms.txt")
+	c.Assert(found, qt.Equals, true)
+	c.Assert(f, qt.Equals, LLMsFormat)
+	f, found = formats.FromFilename("robots.txt")
+	c.Assert(found, qt.Equals, true)
+	c.Assert(f, qt.Equals, RobotsTxtFormat)
+	f, found = formats.FromFilename("list.txt")
+	c.Assert(found, qt.Equals, true)
+	c.Assert(f.IsPlainText, qt.Equals, true)
 }
 
 func TestDecodeFormats(t *testing.T) {
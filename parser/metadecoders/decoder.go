@@ -40,6 +40,14 @@ type Decoder struct {
 	// Comment, if not 0, is the comment character ued in the CSV decoder. Lines beginning with the
 	// Comment character without preceding whitespace are ignored.
 	Comment rune
+
+	// XMLArrays lists the XML elements to always decode to slices, even if
+	// they occur once, e.g. item and entry in RSS and Atom feeds.
+	XMLArrays []string
+
+	// XMLNamespaces, if set, keeps the namespace prefixes in the XML
+	// element and attribute names, e.g. atom:link and media:content.
+	XMLNamespaces bool
 }
 
 // OptionsKey is used in cache keys.
@@ -47,6 +55,12 @@ func (d Decoder) OptionsKey() string {
 	var sb strings.Builder
 	sb.WriteRune(d.Delimiter)
 	sb.WriteRune(d.Comment)
+	if d.useXMLDecoder() {
+		sb.WriteString(strings.Join(d.XMLArrays, ","))
+		if d.XMLNamespaces {
+			sb.WriteString("ns")
+		}
+	}
 	return sb.String()
 }
 
@@ -136,6 +150,21 @@ func (d Decoder) UnmarshalTo(data []byte, f Format, v any) error {
 	case JSON:
 		err = json.Unmarshal(data, v)
 	case XML:
+		if d.useXMLDecoder() {
+			var xmlValue map[string]any
+			xmlValue, err = d.unmarshalXML(data)
+			if err != nil {
+				return toFileError(f, data, fmt.Errorf("failed to unmarshal XML: %w", err))
+			}
+			switch v := v.(type) {
+			case *map[string]any:
+				*v = xmlValue
+			case *any:
+				*v = xmlValue
+			}
+			break
+		}
+
 		var xmlRoot xml.Map
 		xmlRoot, err = xml.NewMapXml(data)
 
@@ -73,6 +73,70 @@ func TestUnmarshalXML(t *testing.T) {
 	c.Assert(m, qt.DeepEquals, expect)
 
 }
+
+func TestUnmarshalXMLOptions(t *testing.T) {
+	c := qt.New(t)
+
+	xmlDoc := `<?xml version="1.0" encoding="utf-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+	<channel>
+		<title>Example feed</title>
+		<link>https://example.com/</link>
+		<atom:link href="https://example.com/feed.xml" rel="self"/>
+		<item>
+			<title>First &amp; only</title>
+			<category domain="tags">a</category>
+			<category domain="tags">b</category>
+			<media:content url="https://example.com/a.jpg" medium="image"/>
+			<description><![CDATA[<p>Hello</p>]]></description>
+		</item>
+	</channel>
+</rss>`
+
+	d := Default
+	d.XMLArrays = []string{"item"}
+	d.XMLNamespaces = true
+
+	m, err := d.Unmarshal([]byte(xmlDoc), XML)
+	c.Assert(err, qt.IsNil)
+	c.Assert(m, qt.DeepEquals, map[string]any{
+		"-version":     "2.0",
+		"-xmlns:atom":  "http://www.w3.org/2005/Atom",
+		"-xmlns:media": "http://search.yahoo.com/mrss/",
+		"channel": map[string]any{
+			"title": "Example feed",
+			"link":  "https://example.com/",
+			"atom:link": map[string]any{
+				"-href": "https://example.com/feed.xml",
+				"-rel":  "self",
+			},
+			"item": []any{
+				map[string]any{
+					"title": "First & only",
+					"category": []any{
+						map[string]any{"-domain": "tags", "#text": "a"},
+						map[string]any{"-domain": "tags", "#text": "b"},
+					},
+					"media:content": map[string]any{
+						"-url":    "https://example.com/a.jpg",
+						"-medium": "image",
+					},
+					"description": "<p>Hello</p>",
+				},
+			},
+		},
+	})
+
+	d.XMLNamespaces = false
+	m, err = d.Unmarshal([]byte(xmlDoc), XML)
+	c.Assert(err, qt.IsNil)
+	channel := m.(map[string]any)["channel"].(map[string]any)
+	c.Assert(channel["link"], qt.HasLen, 2)
+	c.Assert(m.(map[string]any)["-atom"], qt.Equals, "http://www.w3.org/2005/Atom")
+
+	_, err = d.Unmarshal([]byte(`<a><b></a>`), XML)
+	c.Assert(err, qt.Not(qt.IsNil))
+}
 func TestUnmarshalToMap(t *testing.T) {
 	c := qt.New(t)
 
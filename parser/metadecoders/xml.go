@@ -0,0 +1,158 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadecoders
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+)
+
+// The keys used for XML attributes and text in the decoded maps.
+const (
+	XMLAttrPrefix = "-"
+	XMLTextKey    = "#text"
+)
+
+// useXMLDecoder reports whether to use the XML decoder below instead of
+// the default.
+func (d Decoder) useXMLDecoder() bool {
+	return d.XMLNamespaces || len(d.XMLArrays) > 0
+}
+
+type xmlElement struct {
+	name     string
+	attrs    []xml.Attr
+	children map[string]any
+	text     strings.Builder
+}
+
+func (e *xmlElement) value() any {
+	text := strings.TrimSpace(e.text.String())
+	if len(e.attrs) == 0 && len(e.children) == 0 {
+		return text
+	}
+
+	m := e.children
+	if m == nil {
+		m = make(map[string]any)
+	}
+	for _, attr := range e.attrs {
+		m[XMLAttrPrefix+attr.Name.Local] = attr.Value
+	}
+	if text != "" {
+		m[XMLTextKey] = text
+	}
+	return m
+}
+
+// unmarshalXML decodes data into a map in the same structure as the
+// default XML decoder, but keeps the namespace prefixes in the names if
+// XMLNamespaces is set, e.g. atom:link, and always decodes the elements
+// in XMLArrays to slices, e.g. the items in a RSS feed, even if there is
+// only one.
+func (d Decoder) unmarshalXML(data []byte) (map[string]any, error) {
+	arrays := make(map[string]bool)
+	for _, name := range d.XMLArrays {
+		arrays[name] = true
+	}
+
+	qname := func(name xml.Name) string {
+		if d.XMLNamespaces && name.Space != "" {
+			return name.Space + ":" + name.Local
+		}
+		return name.Local
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Entity = xml.HTMLEntity
+
+	var (
+		stack []*xmlElement
+		root  any
+	)
+
+	for {
+		// RawToken does not resolve the namespace prefixes to URLs.
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			e := &xmlElement{name: qname(t.Name)}
+			for _, attr := range t.Attr {
+				if !d.XMLNamespaces && attr.Name.Space == "xmlns" {
+					// Keep the old behaviour of e.g. -atom for xmlns:atom.
+					e.attrs = append(e.attrs, xml.Attr{Name: xml.Name{Local: attr.Name.Local}, Value: attr.Value})
+					continue
+				}
+				e.attrs = append(e.attrs, xml.Attr{Name: xml.Name{Local: qname(attr.Name)}, Value: attr.Value})
+			}
+			stack = append(stack, e)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, errors.New("unexpected end element " + qname(t.Name))
+			}
+			e := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			v := e.value()
+
+			if len(stack) == 0 {
+				root = v
+				continue
+			}
+
+			parent := stack[len(stack)-1]
+			if parent.children == nil {
+				parent.children = make(map[string]any)
+			}
+			local := e.name[strings.LastIndex(e.name, ":")+1:]
+			switch existing := parent.children[e.name].(type) {
+			case nil:
+				if arrays[e.name] || arrays[local] {
+					v = []any{v}
+				}
+				parent.children[e.name] = v
+			case []any:
+				parent.children[e.name] = append(existing, v)
+			default:
+				parent.children[e.name] = []any{existing, v}
+			}
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, errors.New("unexpected EOF, unclosed element " + stack[len(stack)-1].name)
+	}
+
+	switch v := root.(type) {
+	case map[string]any:
+		return v, nil
+	case string:
+		return map[string]any{XMLTextKey: v}, nil
+	default:
+		return nil, errors.New("no root element")
+	}
+}
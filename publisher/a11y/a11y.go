@@ -0,0 +1,310 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package a11y performs static accessibility checks on HTML.
+package a11y
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/net/html"
+)
+
+// The rules checked.
+const (
+	RuleImageAlt     = "image-alt"
+	RuleHeadingOrder = "heading-order"
+	RuleFormLabel    = "form-label"
+	RuleContrast     = "contrast"
+)
+
+// The severities of a rule.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityIgnore  = "ignore"
+)
+
+// DefaultConfig holds the default configuration.
+var DefaultConfig = Config{
+	Rules: map[string]string{
+		RuleImageAlt:     SeverityError,
+		RuleHeadingOrder: SeverityWarning,
+		RuleFormLabel:    SeverityError,
+		RuleContrast:     SeverityWarning,
+	},
+	MinContrast: 4.5,
+}
+
+// Config configures the accessibility checks.
+type Config struct {
+	// The severity of each rule, one of error, warning or ignore.
+	Rules map[string]string
+
+	// The min contrast ratio between the text and background color.
+	MinContrast float64
+}
+
+// DecodeConfig decodes the a11y section in site config and merges it with
+// the defaults.
+func DecodeConfig(in any) (Config, error) {
+	c := Config{Rules: make(map[string]string), MinContrast: DefaultConfig.MinContrast}
+	for k, v := range DefaultConfig.Rules {
+		c.Rules[k] = v
+	}
+
+	if in == nil {
+		return c, nil
+	}
+
+	var cfg Config
+	if err := mapstructure.WeakDecode(in, &cfg); err != nil {
+		return c, fmt.Errorf("failed to decode a11y config: %w", err)
+	}
+
+	for k, v := range cfg.Rules {
+		rule, severity := strings.ToLower(k), strings.ToLower(v)
+		if _, found := DefaultConfig.Rules[rule]; !found {
+			return c, fmt.Errorf("unknown a11y rule %q", k)
+		}
+		switch severity {
+		case SeverityError, SeverityWarning, SeverityIgnore:
+		default:
+			return c, fmt.Errorf("invalid severity %q for a11y rule %q, must be one of error, warning or ignore", v, k)
+		}
+		c.Rules[rule] = severity
+	}
+	if cfg.MinContrast > 0 {
+		c.MinContrast = cfg.MinContrast
+	}
+
+	return c, nil
+}
+
+// Issue is an accessibility problem found in a HTML document.
+type Issue struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+
+	// The offending element, shortened.
+	Source string `json:"source"`
+
+	// The line in the document.
+	Line int `json:"line"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%d: %s: %s: %s (%s)", i.Line, i.Severity, i.Message, i.Source, i.Rule)
+}
+
+// Checker checks HTML documents with the configured rules.
+type Checker struct {
+	cfg Config
+}
+
+// NewChecker creates a new Checker.
+func NewChecker(cfg Config) *Checker {
+	return &Checker{cfg: cfg}
+}
+
+type element struct {
+	name string
+
+	// The text and background colors from the inline styles of the element
+	// and its ancestors, nil if not set.
+	color      *rgb
+	background *rgb
+}
+
+type formControl struct {
+	id     string
+	offset int
+	tag    string
+}
+
+// Check checks the HTML in r and returns the issues found, ordered by line.
+func (c *Checker) Check(r io.Reader) ([]Issue, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		issues      []Issue
+		stack       []element
+		offset      int
+		lastHeading int
+		labelFor    = make(map[string]bool)
+		unlabeled   []formControl
+	)
+
+	report := func(rule string, offset int, tag, format string, args ...any) {
+		severity := c.cfg.Rules[rule]
+		if severity == "" || severity == SeverityIgnore {
+			return
+		}
+		issues = append(issues, Issue{
+			Rule:     rule,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+			Source:   shorten(tag),
+			Line:     bytes.Count(src[:offset], []byte("\n")) + 1,
+		})
+	}
+
+	inLabel := func() bool {
+		for _, el := range stack {
+			if el.name == "label" {
+				return true
+			}
+		}
+		return false
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(src))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+
+		raw := string(z.Raw())
+		start := offset
+		offset += len(raw)
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			attrs := make(map[string]string)
+			for _, a := range tok.Attr {
+				if _, found := attrs[a.Key]; !found {
+					attrs[a.Key] = a.Val
+				}
+			}
+			_, hasAlt := attrs["alt"]
+			isLabeled := attrs["aria-label"] != "" || attrs["aria-labelledby"] != "" || attrs["title"] != "" || inLabel()
+
+			switch tok.Data {
+			case "img", "area":
+				if !hasAlt && attrs["role"] != "presentation" && attrs["role"] != "none" {
+					report(RuleImageAlt, start, raw, "<%s> without alt text", tok.Data)
+				}
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				level := int(tok.Data[1] - '0')
+				if lastHeading > 0 && level > lastHeading+1 {
+					report(RuleHeadingOrder, start, raw, "heading level jumps from h%d to h%d", lastHeading, level)
+				}
+				lastHeading = level
+			case "label":
+				if id := attrs["for"]; id != "" {
+					labelFor[id] = true
+				}
+			case "input":
+				typ := strings.ToLower(attrs["type"])
+				switch typ {
+				case "hidden", "submit", "reset", "button":
+				case "image":
+					if !hasAlt {
+						report(RuleImageAlt, start, raw, "<input type=\"image\"> without alt text")
+					}
+				default:
+					if !isLabeled {
+						unlabeled = append(unlabeled, formControl{id: attrs["id"], offset: start, tag: raw})
+					}
+				}
+			case "select", "textarea":
+				if !isLabeled {
+					unlabeled = append(unlabeled, formControl{id: attrs["id"], offset: start, tag: raw})
+				}
+			}
+
+			if tt == html.SelfClosingTagToken || voidElements[tok.Data] {
+				continue
+			}
+
+			el := element{name: tok.Data}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				el.color, el.background = parent.color, parent.background
+			}
+			var setsColor bool
+			if style, found := attrs["style"]; found {
+				fg, bg := parseStyleColors(style)
+				if fg != nil {
+					el.color = fg
+					setsColor = true
+				}
+				if bg != nil {
+					el.background = bg
+					setsColor = true
+				}
+			}
+			if setsColor && el.color != nil && el.background != nil {
+				ratio := contrastRatio(*el.color, *el.background)
+				if ratio < c.cfg.MinContrast {
+					report(RuleContrast, start, raw, "contrast ratio %.2f between text and background is less than %.1f", ratio, c.cfg.MinContrast)
+				}
+			}
+			stack = append(stack, el)
+		case html.EndTagToken:
+			tok := z.Token()
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].name == tok.Data {
+					stack = stack[:i]
+					break
+				}
+			}
+		}
+	}
+
+	for _, fc := range unlabeled {
+		if fc.id != "" && labelFor[fc.id] {
+			continue
+		}
+		report(RuleFormLabel, fc.offset, fc.tag, "form control without a label")
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+
+	return issues, nil
+}
+
+// Elements without an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "keygen": true, "link": true,
+	"meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+func shorten(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 80 {
+		i := 77
+		for i > 0 && s[i]&0xC0 == 0x80 {
+			i--
+		}
+		s = s[:i] + "..."
+	}
+	return s
+}
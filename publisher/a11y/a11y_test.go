@@ -0,0 +1,94 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package a11y
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := DecodeConfig(map[string]any{
+		"rules":       map[string]any{"Contrast": "error", "heading-order": "ignore"},
+		"minContrast": 7,
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(cfg.Rules[RuleContrast], qt.Equals, SeverityError)
+	c.Assert(cfg.Rules[RuleHeadingOrder], qt.Equals, SeverityIgnore)
+	c.Assert(cfg.Rules[RuleImageAlt], qt.Equals, SeverityError)
+	c.Assert(cfg.MinContrast, qt.Equals, 7.0)
+	c.Assert(DefaultConfig.Rules[RuleContrast], qt.Equals, SeverityWarning)
+
+	_, err = DecodeConfig(map[string]any{"rules": map[string]any{"foo": "error"}})
+	c.Assert(err, qt.ErrorMatches, `unknown a11y rule "foo"`)
+	_, err = DecodeConfig(map[string]any{"rules": map[string]any{"contrast": "fatal"}})
+	c.Assert(err, qt.ErrorMatches, `invalid severity "fatal".*`)
+}
+
+func TestCheck(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := DecodeConfig(nil)
+	c.Assert(err, qt.IsNil)
+
+	input := `<html><body>
+<h1>Title</h1>
+<img src="a.png">
+<img src="b.png" alt="">
+<h3>Skipped</h3>
+<form>
+<label for="name">Name</label><input id="name">
+<label>Email <input type="email"></label>
+<input type="text" name="q">
+<input type="submit">
+<textarea aria-label="Message"></textarea>
+<select name="s"></select>
+</form>
+<div style="background-color: #fff">
+<p style="color: #ccc">Light</p>
+<p style="color: black">Dark</p>
+</div>
+<span style="color: rgb(0, 0, 255); background: #00f">Blue</span>
+</body></html>`
+
+	issues, err := NewChecker(cfg).Check(strings.NewReader(input))
+	c.Assert(err, qt.IsNil)
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+
+	c.Assert(got, qt.DeepEquals, []string{
+		`3: error: <img> without alt text: <img src="a.png"> (image-alt)`,
+		`5: warning: heading level jumps from h1 to h3: <h3> (heading-order)`,
+		`9: error: form control without a label: <input type="text" name="q"> (form-label)`,
+		`12: error: form control without a label: <select name="s"> (form-label)`,
+		`15: warning: contrast ratio 1.61 between text and background is less than 4.5: <p style="color: #ccc"> (contrast)`,
+		`18: warning: contrast ratio 1.00 between text and background is less than 4.5: <span style="color: rgb(0, 0, 255); background: #00f"> (contrast)`,
+	})
+}
+
+func TestContrastRatio(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(contrastRatio(rgb{0, 0, 0}, rgb{255, 255, 255}), qt.Equals, 21.0)
+	c.Assert(*parseColor("#abc"), qt.Equals, rgb{0xaa, 0xbb, 0xcc})
+	c.Assert(parseColor("rgba(0, 0, 0, 0.5)"), qt.IsNil)
+	c.Assert(parseColor("var(--fg)"), qt.IsNil)
+}
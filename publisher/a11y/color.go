@@ -0,0 +1,130 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package a11y
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+type rgb struct {
+	r, g, b uint8
+}
+
+// The named colors, limited to the CSS basic colors.
+var namedColors = map[string]rgb{
+	"black":   {0, 0, 0},
+	"silver":  {192, 192, 192},
+	"gray":    {128, 128, 128},
+	"grey":    {128, 128, 128},
+	"white":   {255, 255, 255},
+	"maroon":  {128, 0, 0},
+	"red":     {255, 0, 0},
+	"purple":  {128, 0, 128},
+	"fuchsia": {255, 0, 255},
+	"green":   {0, 128, 0},
+	"lime":    {0, 255, 0},
+	"olive":   {128, 128, 0},
+	"yellow":  {255, 255, 0},
+	"navy":    {0, 0, 128},
+	"blue":    {0, 0, 255},
+	"teal":    {0, 128, 128},
+	"aqua":    {0, 255, 255},
+	"orange":  {255, 165, 0},
+}
+
+// parseStyleColors returns the text and background colors set in the style
+// attribute value style, nil if not set or not computable.
+func parseStyleColors(style string) (color, background *rgb) {
+	for _, decl := range strings.Split(style, ";") {
+		i := strings.Index(decl, ":")
+		if i == -1 {
+			continue
+		}
+		prop := strings.ToLower(strings.TrimSpace(decl[:i]))
+		val := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(decl[i+1:]), "!important"))
+		switch prop {
+		case "color":
+			color = parseColor(val)
+		case "background-color", "background":
+			background = parseColor(val)
+		}
+	}
+	return
+}
+
+// parseColor parses a hex, rgb() or named color, nil if not supported or
+// not opaque.
+func parseColor(s string) *rgb {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if c, found := namedColors[s]; found {
+		return &c
+	}
+
+	if strings.HasPrefix(s, "#") {
+		s = s[1:]
+		if len(s) == 3 {
+			s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+		}
+		if len(s) != 6 {
+			return nil
+		}
+		v, err := strconv.ParseUint(s, 16, 32)
+		if err != nil {
+			return nil
+		}
+		return &rgb{uint8(v >> 16), uint8(v >> 8), uint8(v)}
+	}
+
+	if strings.HasPrefix(s, "rgb(") && strings.HasSuffix(s, ")") {
+		parts := strings.FieldsFunc(s[4:len(s)-1], func(r rune) bool { return r == ',' || r == ' ' })
+		if len(parts) != 3 {
+			return nil
+		}
+		var c [3]uint8
+		for i, p := range parts {
+			v, err := strconv.Atoi(p)
+			if err != nil || v < 0 || v > 255 {
+				return nil
+			}
+			c[i] = uint8(v)
+		}
+		return &rgb{c[0], c[1], c[2]}
+	}
+
+	return nil
+}
+
+// luminance returns the relative luminance of c as defined in WCAG 2.
+func luminance(c rgb) float64 {
+	channel := func(v uint8) float64 {
+		f := float64(v) / 255
+		if f <= 0.03928 {
+			return f / 12.92
+		}
+		return math.Pow((f+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(c.r) + 0.7152*channel(c.g) + 0.0722*channel(c.b)
+}
+
+// contrastRatio returns the WCAG 2 contrast ratio between a and b, from 1 to 21.
+func contrastRatio(a, b rgb) float64 {
+	la, lb := luminance(a), luminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
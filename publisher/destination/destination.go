@@ -0,0 +1,165 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package destination provides the targets Hugo can publish to instead of
+// the publishDir on disk, e.g. memory, a tarball or a bucket.
+package destination
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/paths"
+	"github.com/spf13/afero"
+)
+
+// Destination is where Hugo publishes a site when not to the publishDir
+// on disk.
+type Destination interface {
+	// Fs returns the file system Hugo publishes to.
+	Fs() afero.Fs
+
+	// Flush writes the files published to Fs to the target.
+	// It is a no-op for in-memory destinations.
+	Flush(ctx context.Context) error
+
+	// String returns the destination as configured.
+	String() string
+}
+
+// New creates the destination for uri, one of:
+//
+//	memory
+//	tar:path/to/site.tar, tar:path/to/site.tar.gz (or .tgz)
+//	a bucket URL, e.g. s3://my-bucket?region=us-west-1&prefix=site/
+//
+// Relative tarball paths are resolved against workingDir.
+func New(uri, workingDir string) (Destination, error) {
+	switch {
+	case uri == "":
+		return nil, fmt.Errorf("no destination")
+	case strings.EqualFold(uri, "memory"):
+		return &memDestination{fs: afero.NewMemMapFs(), uri: uri}, nil
+	case strings.HasPrefix(uri, "tar:"):
+		filename := strings.TrimPrefix(strings.TrimPrefix(uri, "tar:"), "//")
+		if filename == "" {
+			return nil, fmt.Errorf("destination %q: missing filename", uri)
+		}
+		return &tarDestination{
+			memDestination: memDestination{fs: afero.NewMemMapFs(), uri: uri},
+			filename:       paths.AbsPathify(workingDir, filename),
+		}, nil
+	case strings.Contains(uri, "://"):
+		return newBucketDestination(uri)
+	default:
+		return nil, fmt.Errorf("unsupported destination %q, must be memory, tar:filename or a bucket URL", uri)
+	}
+}
+
+type memDestination struct {
+	fs  afero.Fs
+	uri string
+}
+
+func (d *memDestination) Fs() afero.Fs {
+	return d.fs
+}
+
+func (d *memDestination) Flush(ctx context.Context) error {
+	return nil
+}
+
+func (d *memDestination) String() string {
+	return d.uri
+}
+
+// tarDestination publishes to memory and writes the files to a tarball
+// on Flush.
+type tarDestination struct {
+	memDestination
+	filename string
+}
+
+func (d *tarDestination) Flush(ctx context.Context) (err error) {
+	if err := os.MkdirAll(filepath.Dir(d.filename), 0777); err != nil {
+		return err
+	}
+	f, err := os.Create(d.filename)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	var w io.Writer = f
+	if strings.HasSuffix(d.filename, ".gz") || strings.HasSuffix(d.filename, ".tgz") {
+		gw := gzip.NewWriter(f)
+		defer func() {
+			if cerr := gw.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = gw
+	}
+
+	tw := tar.NewWriter(w)
+	defer func() {
+		if cerr := tw.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return walkFiles(d.fs, func(path, name string, fi os.FileInfo) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		src, err := d.fs.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+}
+
+// walkFiles calls fn for the regular files in fs in lexical order with
+// their path in fs and their slash separated name relative to the root.
+func walkFiles(fs afero.Fs, fn func(path, name string, fi os.FileInfo) error) error {
+	return afero.Walk(fs, "/", func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+		return fn(path, strings.TrimPrefix(filepath.ToSlash(path), "/"), fi)
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeploy
+// +build !nodeploy
+
+package destination
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+
+	"github.com/spf13/afero"
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/fileblob" // import
+	_ "gocloud.dev/blob/gcsblob"  // import
+	_ "gocloud.dev/blob/memblob"  // import
+	_ "gocloud.dev/blob/s3blob"   // import
+)
+
+// bucketDestination publishes to memory and uploads the files to a bucket
+// on Flush.
+type bucketDestination struct {
+	memDestination
+}
+
+func newBucketDestination(uri string) (Destination, error) {
+	return &bucketDestination{memDestination{fs: afero.NewMemMapFs(), uri: uri}}, nil
+}
+
+func (d *bucketDestination) Flush(ctx context.Context) error {
+	bucket, err := blob.OpenBucket(ctx, d.uri)
+	if err != nil {
+		return fmt.Errorf("failed to open bucket %q: %w", d.uri, err)
+	}
+	defer bucket.Close()
+
+	return walkFiles(d.fs, func(filename, name string, fi os.FileInfo) error {
+		if err := d.upload(ctx, bucket, filename, name); err != nil {
+			return fmt.Errorf("failed to upload %q: %w", name, err)
+		}
+		return nil
+	})
+}
+
+func (d *bucketDestination) upload(ctx context.Context, bucket *blob.Bucket, filename, name string) error {
+	src, err := d.fs.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w, err := bucket.NewWriter(ctx, name, &blob.WriterOptions{
+		ContentType: mime.TypeByExtension(path.Ext(name)),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
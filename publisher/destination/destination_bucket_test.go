@@ -0,0 +1,40 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !nodeploy
+// +build !nodeploy
+
+package destination
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestBucket(t *testing.T) {
+	c := qt.New(t)
+	dir := filepath.ToSlash(t.TempDir())
+
+	d, err := New("file://"+dir+"?prefix=site/", "/work")
+	c.Assert(err, qt.IsNil)
+	writeSite(c, d.Fs())
+	c.Assert(d.Flush(context.Background()), qt.IsNil)
+
+	b, err := os.ReadFile(filepath.Join(dir, "site", "posts", "p1", "index.html"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "p1")
+}
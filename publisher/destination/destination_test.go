@@ -0,0 +1,85 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/spf13/afero"
+)
+
+func writeSite(c *qt.C, fs afero.Fs) {
+	c.Assert(afero.WriteFile(fs, filepath.FromSlash("/index.html"), []byte("home"), 0666), qt.IsNil)
+	c.Assert(afero.WriteFile(fs, filepath.FromSlash("/posts/p1/index.html"), []byte("p1"), 0666), qt.IsNil)
+}
+
+func TestNew(t *testing.T) {
+	c := qt.New(t)
+
+	d, err := New("memory", "/work")
+	c.Assert(err, qt.IsNil)
+	c.Assert(d.String(), qt.Equals, "memory")
+	c.Assert(d.Flush(context.Background()), qt.IsNil)
+
+	d, err = New("tar:site.tar.gz", "/work")
+	c.Assert(err, qt.IsNil)
+	c.Assert(d.(*tarDestination).filename, qt.Equals, filepath.FromSlash("/work/site.tar.gz"))
+
+	_, err = New("tar:", "/work")
+	c.Assert(err, qt.ErrorMatches, ".*missing filename")
+
+	_, err = New("public", "/work")
+	c.Assert(err, qt.ErrorMatches, "unsupported destination.*")
+}
+
+func TestTar(t *testing.T) {
+	c := qt.New(t)
+	dir := t.TempDir()
+
+	d, err := New("tar:out/site.tgz", dir)
+	c.Assert(err, qt.IsNil)
+	writeSite(c, d.Fs())
+	c.Assert(d.Flush(context.Background()), qt.IsNil)
+
+	f, err := os.Open(filepath.Join(dir, "out", "site.tgz"))
+	c.Assert(err, qt.IsNil)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	c.Assert(err, qt.IsNil)
+	tr := tar.NewReader(gr)
+
+	files := make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, qt.IsNil)
+		b, err := io.ReadAll(tr)
+		c.Assert(err, qt.IsNil)
+		files[hdr.Name] = string(b)
+	}
+
+	c.Assert(files, qt.DeepEquals, map[string]string{
+		"index.html":          "home",
+		"posts/p1/index.html": "p1",
+	})
+}
@@ -66,6 +66,10 @@ type Descriptor struct {
 	// Enable to minify the output using the OutputFormat defined above to
 	// pick the correct minifier configuration.
 	Minify bool
+
+	// If set, applied first to make the content satisfy the constraints of
+	// the output format's profile.
+	Profile transform.Transformer
 }
 
 // DestinationPublisher is the default and currently only publisher in Hugo. This
@@ -156,6 +160,10 @@ type Publisher interface {
 func (p DestinationPublisher) createTransformerChain(f Descriptor) transform.Chain {
 	transformers := transform.NewEmpty()
 
+	if f.Profile != nil {
+		transformers = append(transformers, f.Profile)
+	}
+
 	isHTML := f.OutputFormat.IsHTML
 
 	if f.AbsURLPath != "" {
@@ -179,8 +187,8 @@ func (p DestinationPublisher) createTransformerChain(f Descriptor) transform.Cha
 
 	}
 
-	if p.min.MinifyOutput {
-		minifyTransformer := p.min.Transformer(f.OutputFormat.MediaType)
+	if min := p.min.ForOutputFormat(f.OutputFormat); min.MinifyOutput {
+		minifyTransformer := min.Transformer(f.OutputFormat.MediaType)
 		if minifyTransformer != nil {
 			transformers = append(transformers, minifyTransformer)
 		}
@@ -72,9 +72,32 @@ type Config struct {
 	// May get better results, but at a slight performance cost.
 	ToLower bool
 
+	// Decays the score of the matches by their distance in time to the
+	// searched document.
+	Recency RecencyConfig
+
+	// The name of a page param holding a number the score of the page is
+	// multiplied with when it matches, e.g. 2 to favor it and 0.5 to disfavor
+	// it.
+	BoostParam string
+
 	Indices IndexConfigs
 }
 
+// scored reports whether the matches are ordered by their score instead of
+// their weight.
+func (c Config) scored() bool {
+	if c.Recency.Decay != "" || c.BoostParam != "" {
+		return true
+	}
+	for _, conf := range c.Indices {
+		if conf.OverlapWeight != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Add adds a given index.
 func (c *Config) Add(index IndexConfig) {
 	if c.ToLower {
@@ -104,6 +127,10 @@ type IndexConfig struct {
 	// Will lower case all string values in and queries tothis index.
 	// May get better accurate results, but at a slight performance cost.
 	ToLower bool
+
+	// Added to the score for every keyword matched in this index beyond the
+	// first, e.g. to favor pages sharing many tags.
+	OverlapWeight int
 }
 
 // Document is the interface an indexable document in Hugo must fulfill.
@@ -201,20 +228,27 @@ type rank struct {
 	Doc     Document
 	Weight  int
 	Matches int
+
+	keywords []MatchedKeyword
+	match    *Match
 }
 
-func (r *rank) addWeight(w int) {
+func (r *rank) addWeight(index string, kw Keyword, w int) {
 	r.Weight += w
 	r.Matches++
+	r.keywords = append(r.keywords, MatchedKeyword{Index: index, Keyword: kw.String(), Weight: w})
 }
 
-func newRank(doc Document, weight int) *rank {
-	return &rank{Doc: doc, Weight: weight, Matches: 1}
+func newRank(doc Document, index string, kw Keyword, weight int) *rank {
+	return &rank{Doc: doc, Weight: weight, Matches: 1, keywords: []MatchedKeyword{{Index: index, Keyword: kw.String(), Weight: weight}}}
 }
 
 func (r ranks) Len() int      { return len(r) }
 func (r ranks) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
 func (r ranks) Less(i, j int) bool {
+	if r[i].match.scored && r[i].match.Score != r[j].match.Score {
+		return r[i].match.Score > r[j].match.Score
+	}
 	if r[i].Weight == r[j].Weight {
 		if r[i].Doc.PublishDate() == r[j].Doc.PublishDate() {
 			return r[i].Doc.Name() < r[j].Doc.Name()
@@ -231,6 +265,16 @@ func (r ranks) Less(i, j int) bool {
 // threshold (normalize to 0..100) will be removed.
 // If an index name is provided, only that index will be queried.
 func (idx *InvertedIndex) SearchDoc(doc Document, indices ...string) ([]Document, error) {
+	matches, err := idx.ExplainDoc(doc, indices...)
+	if err != nil {
+		return nil, err
+	}
+	return matchDocs(matches), nil
+}
+
+// ExplainDoc is SearchDoc returning the matches with the keywords and
+// factors that made them match.
+func (idx *InvertedIndex) ExplainDoc(doc Document, indices ...string) ([]*Match, error) {
 	var q []queryElement
 
 	var configs IndexConfigs
@@ -258,7 +302,7 @@ func (idx *InvertedIndex) SearchDoc(doc Document, indices ...string) ([]Document
 
 	}
 
-	return idx.searchDate(doc.PublishDate(), q...)
+	return idx.searchMatches(doc.PublishDate(), q...)
 }
 
 // ToKeywords returns a Keyword slice of the given input.
@@ -336,18 +380,26 @@ func (idx *InvertedIndex) search(query ...queryElement) ([]Document, error) {
 }
 
 func (idx *InvertedIndex) searchDate(upperDate time.Time, query ...queryElement) ([]Document, error) {
+	matches, err := idx.searchMatches(upperDate, query...)
+	if err != nil {
+		return []Document{}, err
+	}
+	return matchDocs(matches), nil
+}
+
+func (idx *InvertedIndex) searchMatches(upperDate time.Time, query ...queryElement) ([]*Match, error) {
 	matchm := make(map[Document]*rank, 200)
 	applyDateFilter := !idx.cfg.IncludeNewer && !upperDate.IsZero()
 
 	for _, el := range query {
 		setm, found := idx.index[el.Index]
 		if !found {
-			return []Document{}, fmt.Errorf("index for %q not found", el.Index)
+			return nil, fmt.Errorf("index for %q not found", el.Index)
 		}
 
 		config, found := idx.getIndexCfg(el.Index)
 		if !found {
-			return []Document{}, fmt.Errorf("index config for %q not found", el.Index)
+			return nil, fmt.Errorf("index config for %q not found", el.Index)
 		}
 
 		for _, kw := range el.Keywords {
@@ -361,9 +413,9 @@ func (idx *InvertedIndex) searchDate(upperDate time.Time, query ...queryElement)
 					}
 					r, found := matchm[doc]
 					if !found {
-						matchm[doc] = newRank(doc, config.Weight)
+						matchm[doc] = newRank(doc, el.Index, kw, config.Weight)
 					} else {
-						r.addWeight(config.Weight)
+						r.addWeight(el.Index, kw, config.Weight)
 					}
 				}
 			}
@@ -371,7 +423,7 @@ func (idx *InvertedIndex) searchDate(upperDate time.Time, query ...queryElement)
 	}
 
 	if len(matchm) == 0 {
-		return []Document{}, nil
+		return nil, nil
 	}
 
 	matches := make(ranks, 0, 100)
@@ -382,21 +434,30 @@ func (idx *InvertedIndex) searchDate(upperDate time.Time, query ...queryElement)
 		threshold := idx.cfg.Threshold / v.Matches
 
 		if weight >= threshold {
+			v.match = idx.newMatch(v, weight, upperDate)
 			matches = append(matches, v)
 		}
 	}
 
 	sort.Stable(matches)
 
-	result := make([]Document, len(matches))
+	result := make([]*Match, len(matches))
 
 	for i, m := range matches {
-		result[i] = m.Doc
+		result[i] = m.match
 	}
 
 	return result, nil
 }
 
+func matchDocs(matches []*Match) []Document {
+	docs := make([]Document, len(matches))
+	for i, m := range matches {
+		docs[i] = m.Doc
+	}
+	return docs
+}
+
 // normalizes num to a number between 0 and 100.
 func norm(num, min, max int) int {
 	if min > max {
@@ -417,7 +478,15 @@ func DecodeConfig(m maps.Params) (Config, error) {
 
 	var c Config
 
-	if err := mapstructure.WeakDecode(m, &c); err != nil {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           &c,
+	})
+	if err != nil {
+		return c, err
+	}
+	if err := decoder.Decode(m); err != nil {
 		return c, err
 	}
 
@@ -425,6 +494,10 @@ func DecodeConfig(m maps.Params) (Config, error) {
 		return Config{}, errors.New("related threshold must be between 0 and 100")
 	}
 
+	if err := c.Recency.validate(); err != nil {
+		return Config{}, err
+	}
+
 	if c.ToLower {
 		for i := range c.Indices {
 			c.Indices[i].ToLower = true
@@ -198,6 +198,116 @@ func TestSearch(t *testing.T) {
 	})
 }
 
+type testDocWithParams struct {
+	*testDoc
+	params map[string]any
+}
+
+func (d *testDocWithParams) Param(key any) (any, error) {
+	return d.params[key.(string)], nil
+}
+
+func TestSearchScored(t *testing.T) {
+	c := qt.New(t)
+
+	date := time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+	docs := []Document{
+		newTestDocWithDate("tags", date.AddDate(0, 0, -1), "a"),
+		newTestDocWithDate("tags", date.AddDate(0, 0, -2), "a", "b", "c"),
+		&testDocWithParams{newTestDocWithDate("tags", date.AddDate(-1, 0, 0), "a"), map[string]any{"boost": 3}},
+	}
+	for i, doc := range docs {
+		switch v := doc.(type) {
+		case *testDoc:
+			v.name = fmt.Sprintf("doc%d", i)
+		case *testDocWithParams:
+			v.name = fmt.Sprintf("doc%d", i)
+		}
+	}
+	search := newTestDocWithDate("tags", date, "a", "b", "c")
+
+	newIndex := func(cfg Config) *InvertedIndex {
+		cfg.Threshold = 0
+		idx := NewInvertedIndex(cfg)
+		c.Assert(idx.Add(docs...), qt.IsNil)
+		return idx
+	}
+
+	names := func(matches []*Match) []string {
+		var s []string
+		for _, m := range matches {
+			s = append(s, m.Doc.Name())
+		}
+		return s
+	}
+
+	idx := newIndex(Config{Indices: IndexConfigs{{Name: "tags", Weight: 100, OverlapWeight: 10}}})
+	matches, err := idx.ExplainDoc(search)
+	c.Assert(err, qt.IsNil)
+	c.Assert(names(matches), qt.DeepEquals, []string{"doc1", "doc0", "doc2"})
+	c.Assert(matches[0].Overlap, qt.Equals, 20)
+	c.Assert(matches[0].Score, qt.Equals, 120.0)
+	c.Assert(matches[0].Explain(), qt.Equals, "tags: a, b, c; weight 100, overlap 20, score 120.00")
+
+	idx = newIndex(Config{BoostParam: "boost", Indices: IndexConfigs{{Name: "tags", Weight: 100}}})
+	matches, err = idx.ExplainDoc(search)
+	c.Assert(err, qt.IsNil)
+	c.Assert(names(matches), qt.DeepEquals, []string{"doc2", "doc1", "doc0"})
+	c.Assert(matches[0].Boost, qt.Equals, 3.0)
+
+	idx = newIndex(Config{BoostParam: "boost", Recency: RecencyConfig{Decay: DecayLinear, Scale: 365 * 24 * time.Hour}, Indices: IndexConfigs{{Name: "tags", Weight: 100}}})
+	matches, err = idx.ExplainDoc(search)
+	c.Assert(err, qt.IsNil)
+	c.Assert(names(matches), qt.DeepEquals, []string{"doc0", "doc1", "doc2"})
+	c.Assert(matches[2].Decay, qt.Equals, 0.0)
+}
+
+func TestRecencyFactor(t *testing.T) {
+	c := qt.New(t)
+
+	day := 24 * time.Hour
+	for _, test := range []struct {
+		decay  string
+		d      time.Duration
+		expect float64
+	}{
+		{DecayExponential, 0, 1},
+		{DecayExponential, 10 * day, 0.5},
+		{DecayExponential, -20 * day, 0.25},
+		{DecayGauss, 10 * day, 0.5},
+		{DecayGauss, 20 * day, 0.0625},
+		{DecayLinear, 5 * day, 0.5},
+		{DecayLinear, 30 * day, 0},
+		{"", 30 * day, 1},
+	} {
+		cfg := RecencyConfig{Decay: test.decay, Scale: 10 * day}
+		c.Assert(cfg.factor(test.d), qt.Equals, test.expect, qt.Commentf("%s %s", test.decay, test.d))
+	}
+}
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg, err := DecodeConfig(map[string]any{
+		"threshold":  20,
+		"boostParam": "relatedBoost",
+		"recency":    map[string]any{"decay": "Gauss", "scale": "720h"},
+		"indices": []map[string]any{
+			{"name": "tags", "weight": 100, "overlapWeight": 5},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(cfg.Recency, qt.Equals, RecencyConfig{Decay: DecayGauss, Scale: 720 * time.Hour})
+	c.Assert(cfg.BoostParam, qt.Equals, "relatedBoost")
+	c.Assert(cfg.Indices[0].OverlapWeight, qt.Equals, 5)
+
+	_, err = DecodeConfig(map[string]any{"recency": map[string]any{"decay": "foo", "scale": "720h"}})
+	c.Assert(err, qt.ErrorMatches, `invalid related recency decay "foo".*`)
+
+	_, err = DecodeConfig(map[string]any{"recency": map[string]any{"decay": "linear"}})
+	c.Assert(err, qt.ErrorMatches, `related recency scale must be a positive duration`)
+}
+
 func TestToKeywordsToLower(t *testing.T) {
 	c := qt.New(t)
 	slice := []string{"A", "B", "C"}
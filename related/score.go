@@ -0,0 +1,193 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package related
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// The decay functions of RecencyConfig.
+const (
+	DecayExponential = "exponential"
+	DecayLinear      = "linear"
+	DecayGauss       = "gauss"
+)
+
+// RecencyConfig configures how the score of a match decays with its
+// distance in time to the searched document, or to now when searching by
+// keywords, e.g.:
+//
+//	[related.recency]
+//	decay = "exponential"
+//	scale = "8760h"
+type RecencyConfig struct {
+	// One of exponential, gauss or linear. Empty disables the decay.
+	Decay string
+
+	// For exponential and gauss, the distance at which the score is halved.
+	// For linear, the distance at which the score reaches 0.
+	Scale time.Duration
+}
+
+func (c *RecencyConfig) validate() error {
+	c.Decay = strings.ToLower(c.Decay)
+	switch c.Decay {
+	case "":
+		return nil
+	case DecayExponential, DecayLinear, DecayGauss:
+	default:
+		return fmt.Errorf("invalid related recency decay %q, must be one of exponential, gauss or linear", c.Decay)
+	}
+	if c.Scale <= 0 {
+		return fmt.Errorf("related recency scale must be a positive duration")
+	}
+	return nil
+}
+
+// factor returns the decay factor, between 0 and 1, for the distance d.
+func (c RecencyConfig) factor(d time.Duration) float64 {
+	if d < 0 {
+		d = -d
+	}
+	x := float64(d) / float64(c.Scale)
+	switch c.Decay {
+	case DecayExponential:
+		return math.Pow(0.5, x)
+	case DecayGauss:
+		return math.Pow(0.5, x*x)
+	case DecayLinear:
+		return math.Max(0, 1-x)
+	default:
+		return 1
+	}
+}
+
+// MatchedKeyword is a keyword a match shares with the search.
+type MatchedKeyword struct {
+	Index   string
+	Keyword string
+	Weight  int
+}
+
+// Match is a document found in a search, with the reasons it was selected.
+type Match struct {
+	Doc Document
+
+	// The keywords shared with the search.
+	Keywords []MatchedKeyword
+
+	// The normalized average weight of the keywords, between 0 and 100,
+	// compared with the threshold.
+	Weight int
+
+	// The sum of the OverlapWeight of the indices with more than one
+	// matched keyword.
+	Overlap int
+
+	// The recency decay factor, 1 if not configured.
+	Decay float64
+
+	// The value of the boost param, 1 if not set.
+	Boost float64
+
+	// (Weight + Overlap) * Decay * Boost, used for the order of the
+	// matches when any of the scoring options is configured.
+	Score float64
+
+	scored bool
+}
+
+// Explain returns a human readable explanation of why the document matched.
+func (m *Match) Explain() string {
+	var sb strings.Builder
+	var prev string
+	for _, kw := range m.Keywords {
+		if kw.Index != prev {
+			if prev != "" {
+				sb.WriteString("; ")
+			}
+			sb.WriteString(kw.Index)
+			sb.WriteString(": ")
+			prev = kw.Index
+		} else {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(kw.Keyword)
+	}
+	fmt.Fprintf(&sb, "; weight %d", m.Weight)
+	if m.Overlap != 0 {
+		fmt.Fprintf(&sb, ", overlap %d", m.Overlap)
+	}
+	if m.Decay != 1 {
+		fmt.Fprintf(&sb, ", decay %.2f", m.Decay)
+	}
+	if m.Boost != 1 {
+		fmt.Fprintf(&sb, ", boost %g", m.Boost)
+	}
+	fmt.Fprintf(&sb, ", score %.2f", m.Score)
+	return sb.String()
+}
+
+// paramProvider is implemented by documents with params, e.g. pages.
+type paramProvider interface {
+	Param(key any) (any, error)
+}
+
+func (idx *InvertedIndex) newMatch(r *rank, weight int, upperDate time.Time) *Match {
+	m := &Match{
+		Doc:      r.Doc,
+		Keywords: r.keywords,
+		Weight:   weight,
+		Decay:    1,
+		Boost:    1,
+		scored:   idx.cfg.scored(),
+	}
+
+	perIndex := make(map[string]int)
+	for _, kw := range r.keywords {
+		perIndex[kw.Index]++
+	}
+	for name, n := range perIndex {
+		if conf, found := idx.getIndexCfg(name); found && n > 1 {
+			m.Overlap += conf.OverlapWeight * (n - 1)
+		}
+	}
+
+	if idx.cfg.Recency.Decay != "" {
+		to := upperDate
+		if to.IsZero() {
+			to = time.Now()
+		}
+		m.Decay = idx.cfg.Recency.factor(to.Sub(r.Doc.PublishDate()))
+	}
+
+	if idx.cfg.BoostParam != "" {
+		if pp, ok := r.Doc.(paramProvider); ok {
+			if v, err := pp.Param(idx.cfg.BoostParam); err == nil && v != nil {
+				if boost, err := cast.ToFloat64E(v); err == nil {
+					m.Boost = boost
+				}
+			}
+		}
+	}
+
+	m.Score = float64(m.Weight+m.Overlap) * m.Decay * m.Boost
+
+	return m
+}
@@ -0,0 +1,319 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audio reads the duration and bitrate of MP3 and MP4 audio files.
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Info holds the decoded audio metadata.
+type Info struct {
+	// The playing time.
+	Duration time.Duration
+
+	// The (average) bitrate in bits per second.
+	Bitrate int
+
+	// The sample rate in Hz, 0 if unknown.
+	SampleRate int
+
+	// The size of the file in bytes.
+	Size int64
+}
+
+// ErrUnsupported is returned for audio formats this package can not decode.
+var ErrUnsupported = errors.New("unsupported audio format")
+
+// Decode decodes the audio metadata in r, where suffix is the file suffix
+// without the dot, e.g. mp3 or m4a.
+func Decode(r io.ReadSeeker, suffix string) (Info, error) {
+	switch strings.ToLower(suffix) {
+	case "mp3":
+		return DecodeMP3(r)
+	case "m4a", "mp4", "m4b", "aac":
+		return DecodeMP4(r)
+	default:
+		return Info{}, ErrUnsupported
+	}
+}
+
+func size(r io.ReadSeeker) (int64, error) {
+	n, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	_, err = r.Seek(0, io.SeekStart)
+	return n, err
+}
+
+// The MPEG audio versions in the frame header.
+const (
+	mpeg25 = 0
+	mpeg2  = 2
+	mpeg1  = 3
+)
+
+var (
+	mp3Bitrates = map[[2]int][]int{
+		// {MPEG-1, layer}: kbit/s by index.
+		{1, 1}: {0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		{1, 2}: {0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		{1, 3}: {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+		// {MPEG-2 and 2.5, layer}
+		{2, 1}: {0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{2, 2}: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{2, 3}: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	}
+
+	mp3SampleRates = map[int][]int{
+		mpeg1:  {44100, 48000, 32000},
+		mpeg2:  {22050, 24000, 16000},
+		mpeg25: {11025, 12000, 8000},
+	}
+)
+
+type mp3Frame struct {
+	version    int
+	layer      int
+	bitrate    int // kbit/s
+	sampleRate int
+	mono       bool
+}
+
+func parseMP3Frame(b []byte) (mp3Frame, bool) {
+	var f mp3Frame
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return f, false
+	}
+	f.version = int(b[1]>>3) & 3
+	f.layer = 4 - int(b[1]>>1)&3
+	if f.version == 1 || f.layer == 4 {
+		return f, false
+	}
+
+	table := 1
+	if f.version != mpeg1 {
+		table = 2
+	}
+	bi, si := int(b[2]>>4), int(b[2]>>2)&3
+	if bi == 0 || bi == 15 || si == 3 {
+		// Free format bitrates are not supported.
+		return f, false
+	}
+	f.bitrate = mp3Bitrates[[2]int{table, f.layer}][bi]
+	f.sampleRate = mp3SampleRates[f.version][si]
+	f.mono = b[3]>>6 == 3
+
+	return f, true
+}
+
+func (f mp3Frame) samples() int {
+	switch {
+	case f.layer == 1:
+		return 384
+	case f.layer == 3 && f.version != mpeg1:
+		return 576
+	default:
+		return 1152
+	}
+}
+
+// sideInfoSize is the offset of the Xing header after the frame header.
+func (f mp3Frame) sideInfoSize() int {
+	switch {
+	case f.version == mpeg1 && f.mono:
+		return 17
+	case f.version == mpeg1:
+		return 32
+	case f.mono:
+		return 9
+	default:
+		return 17
+	}
+}
+
+// DecodeMP3 decodes the metadata of the MP3 file in r. The duration is read
+// from the Xing or VBRI header of VBR files and computed from the bitrate of
+// the first frame of CBR files.
+func DecodeMP3(r io.ReadSeeker) (Info, error) {
+	var info Info
+	n, err := size(r)
+	if err != nil {
+		return info, err
+	}
+	info.Size = n
+
+	var start int64
+	head := make([]byte, 10)
+	if _, err := io.ReadFull(r, head); err != nil {
+		return info, fmt.Errorf("mp3: %w", err)
+	}
+	if bytes.HasPrefix(head, []byte("ID3")) {
+		// The ID3v2 tag size is a 28 bit syncsafe integer.
+		start = 10 + (int64(head[6])<<21 | int64(head[7])<<14 | int64(head[8])<<7 | int64(head[9]))
+		if head[5]&0x10 != 0 {
+			start += 10
+		}
+	}
+
+	// Look for the first frame in the next 64 KB.
+	buf := make([]byte, 64*1024)
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return info, err
+	}
+	m, err := io.ReadFull(r, buf)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return info, fmt.Errorf("mp3: %w", err)
+	}
+	buf = buf[:m]
+
+	var (
+		frame mp3Frame
+		found bool
+		i     int
+	)
+	for ; i < len(buf)-4; i++ {
+		if frame, found = parseMP3Frame(buf[i:]); found {
+			break
+		}
+	}
+	if !found {
+		return info, errors.New("mp3: no audio frame found")
+	}
+	start += int64(i)
+	buf = buf[i:]
+	info.SampleRate = frame.sampleRate
+
+	end := n
+	if _, err := r.Seek(-128, io.SeekEnd); err == nil {
+		tag := make([]byte, 3)
+		if _, err := io.ReadFull(r, tag); err == nil && string(tag) == "TAG" {
+			end -= 128
+		}
+	}
+	audioSize := end - start
+	if audioSize <= 0 {
+		return info, errors.New("mp3: no audio data")
+	}
+
+	var frames, vbrBytes int64
+	if off := 4 + frame.sideInfoSize(); len(buf) >= off+16 && (string(buf[off:off+4]) == "Xing" || string(buf[off:off+4]) == "Info") {
+		flags := binary.BigEndian.Uint32(buf[off+4:])
+		p := off + 8
+		if flags&1 != 0 {
+			frames = int64(binary.BigEndian.Uint32(buf[p:]))
+			p += 4
+		}
+		if flags&2 != 0 {
+			vbrBytes = int64(binary.BigEndian.Uint32(buf[p:]))
+		}
+	} else if len(buf) >= 36+18 && string(buf[36:40]) == "VBRI" {
+		vbrBytes = int64(binary.BigEndian.Uint32(buf[46:]))
+		frames = int64(binary.BigEndian.Uint32(buf[50:]))
+	}
+
+	if frames > 0 {
+		seconds := float64(frames) * float64(frame.samples()) / float64(frame.sampleRate)
+		info.Duration = time.Duration(seconds * float64(time.Second))
+		if vbrBytes == 0 {
+			vbrBytes = audioSize
+		}
+		info.Bitrate = int(float64(vbrBytes*8) / seconds)
+		return info, nil
+	}
+
+	info.Bitrate = frame.bitrate * 1000
+	info.Duration = time.Duration(float64(audioSize*8) / float64(info.Bitrate) * float64(time.Second))
+
+	return info, nil
+}
+
+// DecodeMP4 decodes the metadata of the MP4 (e.g. M4A) file in r, read from
+// the movie header.
+func DecodeMP4(r io.ReadSeeker) (Info, error) {
+	var info Info
+	n, err := size(r)
+	if err != nil {
+		return info, err
+	}
+	info.Size = n
+
+	// The boxes to descend into to find the movie header.
+	path := []string{"moov", "mvhd"}
+	pos, end := int64(0), n
+	for len(path) > 0 {
+		if pos+8 > end {
+			return info, errors.New("mp4: no movie header found")
+		}
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return info, err
+		}
+		head := make([]byte, 16)
+		if _, err := io.ReadFull(r, head[:8]); err != nil {
+			return info, fmt.Errorf("mp4: %w", err)
+		}
+		boxSize, headSize := int64(binary.BigEndian.Uint32(head)), int64(8)
+		switch boxSize {
+		case 0:
+			boxSize = end - pos
+		case 1:
+			if _, err := io.ReadFull(r, head[8:]); err != nil {
+				return info, fmt.Errorf("mp4: %w", err)
+			}
+			boxSize, headSize = int64(binary.BigEndian.Uint64(head[8:])), 16
+		}
+		if boxSize < headSize {
+			return info, errors.New("mp4: invalid box size")
+		}
+
+		if string(head[4:8]) == path[0] {
+			path = path[1:]
+			end = pos + boxSize
+			pos += headSize
+			continue
+		}
+		pos += boxSize
+	}
+
+	mvhd := make([]byte, 32)
+	if _, err := io.ReadFull(r, mvhd); err != nil {
+		return info, fmt.Errorf("mp4: %w", err)
+	}
+	var timescale, duration uint64
+	if mvhd[0] == 1 {
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[20:]))
+		duration = binary.BigEndian.Uint64(mvhd[24:])
+	} else {
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[12:]))
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:]))
+	}
+	if timescale == 0 {
+		return info, errors.New("mp4: invalid time scale")
+	}
+
+	seconds := float64(duration) / float64(timescale)
+	info.Duration = time.Duration(seconds * float64(time.Second))
+	if seconds > 0 {
+		info.Bitrate = int(float64(n*8) / seconds)
+	}
+
+	return info, nil
+}
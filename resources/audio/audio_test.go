@@ -0,0 +1,101 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// An MPEG-1 layer III frame header, 128 kbit/s, 44.1 kHz, stereo.
+var mp3FrameHeader = []byte{0xFF, 0xFB, 0x90, 0x00}
+
+func TestDecodeMP3CBR(t *testing.T) {
+	c := qt.New(t)
+
+	var b bytes.Buffer
+	// An empty ID3v2 tag with 6 bytes of padding.
+	b.Write([]byte{'I', 'D', '3', 4, 0, 0, 0, 0, 0, 6})
+	b.Write(make([]byte, 6))
+	b.Write(mp3FrameHeader)
+	b.Write(make([]byte, 16000-len(mp3FrameHeader)))
+
+	info, err := Decode(bytes.NewReader(b.Bytes()), "mp3")
+	c.Assert(err, qt.IsNil)
+	c.Assert(info.Bitrate, qt.Equals, 128000)
+	c.Assert(info.SampleRate, qt.Equals, 44100)
+	c.Assert(info.Duration, qt.Equals, time.Second)
+	c.Assert(info.Size, qt.Equals, int64(16016))
+}
+
+func TestDecodeMP3VBR(t *testing.T) {
+	c := qt.New(t)
+
+	var b bytes.Buffer
+	b.Write(mp3FrameHeader)
+	b.Write(make([]byte, 32))
+	b.WriteString("Xing")
+	binary.Write(&b, binary.BigEndian, uint32(3))
+	binary.Write(&b, binary.BigEndian, uint32(441))
+	binary.Write(&b, binary.BigEndian, uint32(100000))
+	b.Write(make([]byte, 1000))
+
+	info, err := DecodeMP3(bytes.NewReader(b.Bytes()))
+	c.Assert(err, qt.IsNil)
+	// 441 frames of 1152 samples at 44.1 kHz.
+	c.Assert(info.Duration, qt.Equals, 11520*time.Millisecond)
+	c.Assert(info.Bitrate, qt.Equals, 69444)
+}
+
+func TestDecodeMP3Invalid(t *testing.T) {
+	c := qt.New(t)
+
+	_, err := DecodeMP3(bytes.NewReader(make([]byte, 1000)))
+	c.Assert(err, qt.ErrorMatches, "mp3: no audio frame found")
+
+	_, err = Decode(bytes.NewReader(nil), "wav")
+	c.Assert(err, qt.Equals, ErrUnsupported)
+}
+
+func TestDecodeMP4(t *testing.T) {
+	c := qt.New(t)
+
+	box := func(typ string, content []byte) []byte {
+		b := make([]byte, 8, 8+len(content))
+		binary.BigEndian.PutUint32(b, uint32(8+len(content)))
+		copy(b[4:], typ)
+		return append(b, content...)
+	}
+
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:], 90500)
+
+	var b bytes.Buffer
+	b.Write(box("ftyp", []byte("M4A \x00\x00\x00\x00")))
+	b.Write(box("mdat", make([]byte, 1000)))
+	b.Write(box("moov", append(box("udta", make([]byte, 10)), box("mvhd", mvhd)...)))
+
+	info, err := Decode(bytes.NewReader(b.Bytes()), "m4a")
+	c.Assert(err, qt.IsNil)
+	c.Assert(info.Duration, qt.Equals, 90500*time.Millisecond)
+	c.Assert(info.Size, qt.Equals, int64(b.Len()))
+
+	_, err = DecodeMP4(bytes.NewReader(box("ftyp", nil)))
+	c.Assert(err, qt.ErrorMatches, "mp4: no movie header found")
+}
@@ -119,6 +119,10 @@ func (e *errorResource) Filter(filters ...any) (images.ImageResource, error) {
 	panic(e.ResourceError)
 }
 
+func (e *errorResource) Process(spec string) (images.ImageResource, error) {
+	panic(e.ResourceError)
+}
+
 func (e *errorResource) Exif() *exif.ExifInfo {
 	panic(e.ResourceError)
 }
@@ -29,7 +29,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/gohugoio/hugo/common/htrace"
 	"github.com/gohugoio/hugo/common/paths"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/disintegration/gift"
 
@@ -262,6 +264,71 @@ func (i *imageResource) Filter(filters ...any) (images.ImageResource, error) {
 	})
 }
 
+// Process processes the image with the given spec, an action (crop, fill,
+// fit, resize, filter or format) followed by its options, e.g.
+// "fill 300x200 webp", or "preset:" followed by the name of an image preset.
+func (i *imageResource) Process(spec string) (images.ImageResource, error) {
+	if strings.HasPrefix(spec, images.PresetPrefix) {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(spec, images.PresetPrefix)))
+		steps, found := i.Proc.Cfg.Presets[name]
+		if !found {
+			return nil, fmt.Errorf("image preset %q not found", name)
+		}
+		var (
+			img images.ImageResource = i
+			err error
+		)
+		for _, step := range steps {
+			if img, err = processImageStep(img, step); err != nil {
+				return nil, err
+			}
+		}
+		return img, nil
+	}
+
+	step, err := images.ParseImageStep(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return processImageStep(i, step)
+}
+
+// ProcessSectionImage processes r with the image preset configured for
+// section, if any. Other resources than raster images are returned as is.
+func (spec *Spec) ProcessSectionImage(r resource.Resource, section string) (resource.Resource, error) {
+	name, found := spec.imaging.Cfg.SectionPresets[strings.ToLower(section)]
+	if !found {
+		return r, nil
+	}
+	ra, ok := r.(*resourceAdapter)
+	if !ok {
+		return r, nil
+	}
+	if _, ok := ra.target.(images.ImageResourceOps); !ok {
+		return r, nil
+	}
+	return ra.Process(images.PresetPrefix + name)
+}
+
+func processImageStep(img images.ImageResource, step images.ImageStep) (images.ImageResource, error) {
+	switch step.Action {
+	case "crop":
+		return img.Crop(step.Options)
+	case "fill":
+		return img.Fill(step.Options)
+	case "fit":
+		return img.Fit(step.Options)
+	case "resize":
+		return img.Resize(step.Options)
+	case "filter":
+		return img.Filter(step.Filter)
+	default:
+		// Format, e.g. "webp q75". Resize to the same size to convert.
+		return img.Resize(fmt.Sprintf("%dx%d %s", img.Width(), img.Height(), step.Options))
+	}
+}
+
 // Serialize image processing. The imaging library spins up its own set of Go routines,
 // so there is not much to gain from adding more load to the mix. That
 // can even have negative effect in low resource scenarios.
@@ -272,7 +339,7 @@ const imageProcWorkers = 1
 var imageProcSem = make(chan bool, imageProcWorkers)
 
 func (i *imageResource) doWithImageConfig(conf images.ImageConfig, f func(src image.Image) (image.Image, error)) (images.ImageResource, error) {
-	img, err := i.getSpec().imageCache.getOrCreate(i, conf, func() (*imageResource, image.Image, error) {
+	img, err := i.getSpec().imageCache.getOrCreate(i, conf, func() (_ *imageResource, _ image.Image, err error) {
 		imageProcSem <- true
 		defer func() {
 			<-imageProcSem
@@ -281,6 +348,14 @@ func (i *imageResource) doWithImageConfig(conf images.ImageConfig, f func(src im
 		errOp := conf.Action
 		errPath := i.getSourceFilename()
 
+		span := htrace.StartInBuild("image."+conf.Action,
+			attribute.String("image.source", errPath),
+			attribute.String("image.spec", conf.Key),
+		)
+		defer func() {
+			htrace.End(span, err)
+		}()
+
 		src, err := i.DecodeImage()
 		if err != nil {
 			return nil, nil, &os.PathError{Op: errOp, Path: errPath, Err: err}
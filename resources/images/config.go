@@ -171,6 +171,11 @@ func DecodeConfig(m map[string]any) (ImagingConfig, error) {
 	}
 	i.ResampleFilter = filter
 
+	i.Presets, i.SectionPresets, err = decodePresets(i.Cfg.Presets)
+	if err != nil {
+		return i, err
+	}
+
 	if strings.TrimSpace(i.Cfg.Exif.IncludeFields) == "" && strings.TrimSpace(i.Cfg.Exif.ExcludeFields) == "" {
 		// Don't change this for no good reason. Please don't.
 		i.Cfg.Exif.ExcludeFields = "GPS|Exif|Exposure[M|P|B]|Contrast|Resolution|Sharp|JPEG|Metering|Sensing|Saturation|ColorSpace|Flash|WhiteBalance"
@@ -399,6 +404,12 @@ type ImagingConfig struct {
 
 	// Hash of the config map provided by the user.
 	CfgHash string
+
+	// The steps of the presets by preset name.
+	Presets map[string][]ImageStep
+
+	// The preset names by section.
+	SectionPresets map[string]string
 }
 
 // Imaging contains default image processing configuration. This will be fetched
@@ -423,6 +434,9 @@ type Imaging struct {
 	BgColor string
 
 	Exif ExifConfig
+
+	// Named image processing presets, see ImagePreset.
+	Presets map[string]ImagePreset
 }
 
 func (cfg *Imaging) init() error {
@@ -16,6 +16,7 @@ package images
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gohugoio/hugo/common/hugio"
 	"github.com/gohugoio/hugo/common/maps"
@@ -32,10 +33,31 @@ type Filters struct {
 }
 
 // Overlay creates a filter that overlays src at position x y.
-func (*Filters) Overlay(src ImageSource, x, y any) gift.Filter {
+// The supported options are opacity, in range (0, 1).
+func (*Filters) Overlay(src ImageSource, x, y any, options ...any) gift.Filter {
+	of := overlayFilter{src: src, x: cast.ToInt(x), y: cast.ToInt(y), opacity: 1}
+
+	if len(options) == 0 {
+		return filter{
+			Options: newFilterOpts(src.Key(), x, y),
+			Filter:  of,
+		}
+	}
+
+	opt := maps.MustToParamsAndPrepare(options[0])
+	for option, v := range opt {
+		switch option {
+		case "opacity":
+			of.opacity = cast.ToFloat64(v)
+			if of.opacity < 0 || of.opacity > 1 {
+				panic(fmt.Sprintf("overlay opacity must be in range (0, 1), got %v", v))
+			}
+		}
+	}
+
 	return filter{
-		Options: newFilterOpts(src.Key(), x, y),
-		Filter:  overlayFilter{src: src, x: cast.ToInt(x), y: cast.ToInt(y)},
+		Options: newFilterOpts(src.Key(), x, y, opt),
+		Filter:  of,
 	}
 }
 
@@ -48,6 +70,7 @@ func (*Filters) Text(text string, options ...any) gift.Filter {
 		x:           10,
 		y:           10,
 		linespacing: 2,
+		alignx:      "left",
 	}
 
 	var opt maps.Params
@@ -65,24 +88,70 @@ func (*Filters) Text(text string, options ...any) gift.Filter {
 				tf.y = cast.ToInt(v)
 			case "linespacing":
 				tf.linespacing = cast.ToInt(v)
-			case "font":
-				if err, ok := v.(error); ok {
-					panic(fmt.Sprintf("invalid font source: %s", err))
-				}
-				fontSource, ok1 := v.(hugio.ReadSeekCloserProvider)
-				identifier, ok2 := v.(resource.Identifier)
-
-				if !(ok1 && ok2) {
-					panic(fmt.Sprintf("invalid text font source: %T", v))
+			case "alignx":
+				tf.alignx = strings.ToLower(cast.ToString(v))
+				if tf.alignx != "left" && tf.alignx != "center" && tf.alignx != "right" {
+					panic(fmt.Sprintf("invalid text alignx %q, must be one of left, center or right", v))
 				}
-
+			case "width":
+				tf.width = cast.ToInt(v)
+			case "font":
+				fontSource, key := toFontSource(v)
 				tf.fontSource = fontSource
 
 				// The input value isn't hashable and will not make a stable key.
 				// Replace it with a string in the map used as basis for the
 				// hash string.
-				opt["font"] = identifier.Key()
-
+				opt["font"] = key
+			case "spans":
+				spans, err := cast.ToSliceE(v)
+				if err != nil {
+					panic(fmt.Sprintf("invalid text spans: %s", err))
+				}
+				// Replaces the fonts with their keys, see above.
+				spansOpt := make([]any, len(spans))
+				for i, sv := range spans {
+					sopt := maps.MustToParamsAndPrepare(sv)
+					span := textSpan{
+						text:  cast.ToString(sopt["text"]),
+						color: cast.ToString(sopt["color"]),
+						size:  cast.ToFloat64(sopt["size"]),
+					}
+					if fv, found := sopt["font"]; found {
+						fontSource, key := toFontSource(fv)
+						span.fontSource = fontSource
+						sopt = maps.Params{"text": sopt["text"], "color": sopt["color"], "size": sopt["size"], "font": key}
+					}
+					tf.spans = append(tf.spans, span)
+					spansOpt[i] = sopt
+				}
+				opt["spans"] = spansOpt
+			case "shadow":
+				sopt := maps.MustToParamsAndPrepare(v)
+				tf.shadow = &textShadow{color: "#000000", x: 2, y: 2}
+				if c, found := sopt["color"]; found {
+					tf.shadow.color = cast.ToString(c)
+				}
+				if x, found := sopt["x"]; found {
+					tf.shadow.x = cast.ToInt(x)
+				}
+				if y, found := sopt["y"]; found {
+					tf.shadow.y = cast.ToInt(y)
+				}
+				tf.shadow.blur = cast.ToFloat32(sopt["blur"])
+			case "gradient":
+				gopt := maps.MustToParamsAndPrepare(v)
+				tf.gradient = &textGradient{
+					from: cast.ToString(gopt["from"]),
+					to:   cast.ToString(gopt["to"]),
+				}
+				switch direction := strings.ToLower(cast.ToString(gopt["direction"])); direction {
+				case "", "vertical":
+				case "horizontal":
+					tf.gradient.horizontal = true
+				default:
+					panic(fmt.Sprintf("invalid text gradient direction %q, must be one of vertical or horizontal", direction))
+				}
 			}
 		}
 	}
@@ -93,6 +162,21 @@ func (*Filters) Text(text string, options ...any) gift.Filter {
 	}
 }
 
+// toFontSource returns v, a font resource, as a font source and its key.
+func toFontSource(v any) (hugio.ReadSeekCloserProvider, string) {
+	if err, ok := v.(error); ok {
+		panic(fmt.Sprintf("invalid font source: %s", err))
+	}
+	fontSource, ok1 := v.(hugio.ReadSeekCloserProvider)
+	identifier, ok2 := v.(resource.Identifier)
+
+	if !(ok1 && ok2) {
+		panic(fmt.Sprintf("invalid text font source: %T", v))
+	}
+
+	return fontSource, identifier.Key()
+}
+
 // Brightness creates a filter that changes the brightness of an image.
 // The percentage parameter must be in range (-100, 100).
 func (*Filters) Brightness(percentage any) gift.Filter {
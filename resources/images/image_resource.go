@@ -41,6 +41,13 @@ type ImageResourceOps interface {
 	Fit(spec string) (ImageResource, error)
 	Resize(spec string) (ImageResource, error)
 
+	// Process processes the image with the given spec, an action (crop,
+	// fill, fit, resize, filter or format) followed by its options, or
+	// "preset:" followed by the name of an image preset in site config.
+	//    {{ $image := $image.Process "fill 300x200 webp" }}
+	//    {{ $image := $image.Process "preset:thumbnail" }}
+	Process(spec string) (ImageResource, error)
+
 	// Filter applies one or more filters to an Image.
 	//    {{ $image := $image.Filter (images.GaussianBlur 6) (images.Pixelate 8) }}
 	Filter(filters ...any) (ImageResource, error)
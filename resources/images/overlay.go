@@ -16,6 +16,7 @@ package images
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"image/draw"
 
 	"github.com/disintegration/gift"
@@ -24,8 +25,9 @@ import (
 var _ gift.Filter = (*overlayFilter)(nil)
 
 type overlayFilter struct {
-	src  ImageSource
-	x, y int
+	src     ImageSource
+	x, y    int
+	opacity float64
 }
 
 func (f overlayFilter) Draw(dst draw.Image, src image.Image, options *gift.Options) {
@@ -35,6 +37,14 @@ func (f overlayFilter) Draw(dst draw.Image, src image.Image, options *gift.Optio
 	}
 
 	gift.New().Draw(dst, src)
+
+	if f.opacity < 1 {
+		r := overlaySrc.Bounds().Sub(overlaySrc.Bounds().Min).Add(image.Pt(f.x, f.y))
+		mask := image.NewUniform(color.Alpha{A: uint8(f.opacity*255 + 0.5)})
+		draw.DrawMask(dst, r, overlaySrc, overlaySrc.Bounds().Min, mask, image.Point{}, draw.Over)
+		return
+	}
+
 	gift.New().DrawAt(dst, overlaySrc, image.Pt(f.x, f.y), gift.OverOperator)
 }
 
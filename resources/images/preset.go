@@ -0,0 +1,164 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/disintegration/gift"
+)
+
+// PresetPrefix is the prefix of the Process spec applying a preset,
+// e.g. "preset:thumbnail".
+const PresetPrefix = "preset:"
+
+// ImagePreset is a named chain of image processing steps, configured in
+// imaging.presets in site config.
+type ImagePreset struct {
+	// The processing steps, each an action followed by its options, e.g.
+	// "fill 300x200 center", "filter grayscale" or "format webp q75".
+	Steps []string
+
+	// The sections whose pages get their image resources processed with this
+	// preset.
+	Sections []string
+}
+
+// ImageStep is a step in image processing.
+type ImageStep struct {
+	// One of crop, fill, fit, resize, filter or format.
+	Action string
+
+	// The options of the action, e.g. "300x200 center".
+	Options string
+
+	// The filter to apply if Action is filter.
+	Filter gift.Filter
+}
+
+// ParseImageStep parses s, an action followed by its options, e.g.
+// "fill 300x200 center" or "filter gaussianblur 6".
+func ParseImageStep(s string) (ImageStep, error) {
+	action, options, _ := strings.Cut(strings.TrimSpace(s), " ")
+	step := ImageStep{Action: strings.ToLower(action), Options: strings.TrimSpace(options)}
+
+	switch step.Action {
+	case "crop", "fill", "fit", "resize", "format":
+	case "filter":
+		fields := strings.Fields(step.Options)
+		if len(fields) == 0 {
+			return step, fmt.Errorf("image step %q: missing filter name", s)
+		}
+		var err error
+		step.Filter, err = filterByName(fields[0], fields[1:]...)
+		if err != nil {
+			return step, fmt.Errorf("image step %q: %w", s, err)
+		}
+	default:
+		return step, fmt.Errorf("invalid image step %q, must start with one of crop, fill, fit, resize, filter or format", s)
+	}
+
+	return step, nil
+}
+
+// decodePresets parses the steps of the presets in cfg and returns them
+// by preset name, and the preset names by section.
+func decodePresets(cfg map[string]ImagePreset) (map[string][]ImageStep, map[string]string, error) {
+	presets := make(map[string][]ImageStep)
+	sections := make(map[string]string)
+
+	names := make([]string, 0, len(cfg))
+	for name := range cfg {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		preset := cfg[name]
+		name = strings.ToLower(name)
+		if len(preset.Steps) == 0 {
+			return nil, nil, fmt.Errorf("image preset %q has no steps", name)
+		}
+		for _, s := range preset.Steps {
+			step, err := ParseImageStep(s)
+			if err != nil {
+				return nil, nil, fmt.Errorf("image preset %q: %w", name, err)
+			}
+			presets[name] = append(presets[name], step)
+		}
+		for _, section := range preset.Sections {
+			section = strings.ToLower(section)
+			if other, found := sections[section]; found {
+				return nil, nil, fmt.Errorf("section %q is set in both image preset %q and %q", section, other, name)
+			}
+			sections[section] = name
+		}
+	}
+
+	return presets, sections, nil
+}
+
+// filterByName creates the filter with the given name, e.g. "gaussianblur",
+// case insensitive, with the string arguments converted as in templates.
+func filterByName(name string, args ...string) (f gift.Filter, err error) {
+	defer func() {
+		// The filter constructors panic on invalid options.
+		if r := recover(); r != nil {
+			err = fmt.Errorf("filter %q: %v", name, r)
+		}
+	}()
+
+	v := reflect.ValueOf(&Filters{})
+	t := v.Type()
+
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if !strings.EqualFold(m.Name, name) {
+			continue
+		}
+
+		// The first argument is the receiver.
+		mt := m.Type
+		numIn := mt.NumIn() - 1
+		required := numIn
+		if mt.IsVariadic() {
+			required--
+		}
+		if len(args) < required || !mt.IsVariadic() && len(args) > numIn {
+			return nil, fmt.Errorf("filter %q takes %d arguments, got %d", name, required, len(args))
+		}
+
+		in := make([]reflect.Value, len(args))
+		for j, arg := range args {
+			var argType reflect.Type
+			if mt.IsVariadic() && j >= numIn-1 {
+				argType = mt.In(numIn).Elem()
+			} else {
+				argType = mt.In(j + 1)
+			}
+			av := reflect.ValueOf(arg)
+			if !av.Type().AssignableTo(argType) {
+				return nil, fmt.Errorf("filter %q is not supported in image steps", name)
+			}
+			in[j] = av
+		}
+
+		return v.Method(i).Call(in)[0].Interface().(gift.Filter), nil
+	}
+
+	return nil, fmt.Errorf("unknown filter %q", name)
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestParseImageStep(t *testing.T) {
+	c := qt.New(t)
+
+	step, err := ParseImageStep(" Fill 300x200 center ")
+	c.Assert(err, qt.IsNil)
+	c.Assert(step.Action, qt.Equals, "fill")
+	c.Assert(step.Options, qt.Equals, "300x200 center")
+	c.Assert(step.Filter, qt.IsNil)
+
+	step, err = ParseImageStep("filter gaussianblur 6")
+	c.Assert(err, qt.IsNil)
+	c.Assert(step.Filter.(filter).Options, qt.DeepEquals, (&Filters{}).GaussianBlur("6").(filter).Options)
+
+	step, err = ParseImageStep("filter Grayscale")
+	c.Assert(err, qt.IsNil)
+	c.Assert(step.Filter, qt.Not(qt.IsNil))
+
+	_, err = ParseImageStep("rotate 90")
+	c.Assert(err, qt.ErrorMatches, ".*must start with one of.*")
+	_, err = ParseImageStep("filter")
+	c.Assert(err, qt.ErrorMatches, ".*missing filter name")
+	_, err = ParseImageStep("filter nope")
+	c.Assert(err, qt.ErrorMatches, `.*unknown filter "nope"`)
+	_, err = ParseImageStep("filter grayscale 3")
+	c.Assert(err, qt.ErrorMatches, `.*takes 0 arguments, got 1`)
+}
+
+func TestDecodePresets(t *testing.T) {
+	c := qt.New(t)
+
+	presets, sections, err := decodePresets(map[string]ImagePreset{
+		"Thumbnail": {Steps: []string{"fill 100x100", "format webp"}, Sections: []string{"Blog", "news"}},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(presets["thumbnail"], qt.HasLen, 2)
+	c.Assert(sections, qt.DeepEquals, map[string]string{"blog": "thumbnail", "news": "thumbnail"})
+
+	_, _, err = decodePresets(map[string]ImagePreset{"empty": {}})
+	c.Assert(err, qt.ErrorMatches, `.*has no steps`)
+
+	_, _, err = decodePresets(map[string]ImagePreset{
+		"a": {Steps: []string{"resize 10x"}, Sections: []string{"blog"}},
+		"b": {Steps: []string{"resize 20x"}, Sections: []string{"blog"}},
+	})
+	c.Assert(err, qt.ErrorMatches, `section "blog" is set in both image preset "a" and "b"`)
+}
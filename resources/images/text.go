@@ -15,9 +15,11 @@ package images
 
 import (
 	"image"
+	"image/color"
 	"image/draw"
 	"io"
 	"strings"
+	"unicode"
 
 	"github.com/disintegration/gift"
 	"github.com/gohugoio/hugo/common/hugio"
@@ -36,18 +38,271 @@ type textFilter struct {
 	size        float64
 	linespacing int
 	fontSource  hugio.ReadSeekCloserProvider
+
+	// The horizontal alignment of the lines relative to x,
+	// one of left, center or right.
+	alignx string
+
+	// The width to wrap the lines at. If not set, the lines are wrapped
+	// at the image border.
+	width int
+
+	// Text drawn after text, each with its own style.
+	spans []textSpan
+
+	shadow   *textShadow
+	gradient *textGradient
+}
+
+// textSpan is a part of the text with its own style. The unset fields
+// default to those of the text filter.
+type textSpan struct {
+	text, color string
+	size        float64
+	fontSource  hugio.ReadSeekCloserProvider
+}
+
+type textShadow struct {
+	color string
+	x, y  int
+	blur  float32
+}
+
+// textGradient fills the text, except the spans with a color of their own,
+// with a linear gradient across the text bounds.
+type textGradient struct {
+	from, to   string
+	horizontal bool
+}
+
+// textRun is a part of a line drawn with the font face of one span.
+type textRun struct {
+	span int
+	text string
+
+	// The position relative to the start of the line.
+	x int
+}
+
+type textLine struct {
+	runs            []textRun
+	width           int
+	ascent, descent int
+
+	// The position of the start of the line's baseline.
+	x, y int
 }
 
 func (f textFilter) Draw(dst draw.Image, src image.Image, options *gift.Options) {
-	color, err := hexStringToColor(f.color)
-	if err != nil {
-		panic(err)
+	gift.New().Draw(dst, src)
+
+	spans := append([]textSpan{{text: f.text}}, f.spans...)
+	texts := make([]string, len(spans))
+	faces := make([]font.Face, len(spans))
+	fills := make([]image.Image, len(spans))
+	for i, span := range spans {
+		if span.size == 0 {
+			span.size = f.size
+		}
+		if span.fontSource == nil {
+			span.fontSource = f.fontSource
+		}
+		if span.color == "" && f.gradient == nil {
+			span.color = f.color
+		}
+		if span.color != "" {
+			fills[i] = image.NewUniform(mustHexStringToColor(span.color))
+		}
+		texts[i] = span.text
+		faces[i] = newFontFace(span.fontSource, span.size)
+	}
+
+	lines := layoutText(texts, faces, f.maxWidth(dst.Bounds()))
+
+	// Position the lines.
+	y := f.y
+	for i := range lines {
+		line := &lines[i]
+		y += line.ascent
+		line.y = y
+		switch f.alignx {
+		case "center":
+			line.x = f.x - line.width/2
+		case "right":
+			line.x = f.x - line.width
+		default:
+			line.x = f.x
+		}
+		y += f.linespacing
+	}
+
+	drawRuns := func(dst draw.Image, dx, dy int, fill func(span int) image.Image) {
+		for _, line := range lines {
+			for _, run := range line.runs {
+				src := fill(run.span)
+				if src == nil {
+					continue
+				}
+				d := font.Drawer{
+					Dst:  dst,
+					Src:  src,
+					Face: faces[run.span],
+					Dot:  fixed.P(line.x+run.x+dx, line.y+dy),
+				}
+				d.DrawString(run.text)
+			}
+		}
+	}
+
+	if f.shadow != nil {
+		shadowColor := image.NewUniform(mustHexStringToColor(f.shadow.color))
+		var shadow draw.Image = image.NewNRGBA(dst.Bounds())
+		drawRuns(shadow, f.shadow.x, f.shadow.y, func(int) image.Image { return shadowColor })
+		if f.shadow.blur > 0 {
+			g := gift.New(gift.GaussianBlur(f.shadow.blur))
+			blurred := image.NewNRGBA(g.Bounds(shadow.Bounds()))
+			g.Draw(blurred, shadow)
+			shadow = blurred
+		}
+		draw.Draw(dst, dst.Bounds(), shadow, dst.Bounds().Min, draw.Over)
+	}
+
+	if f.gradient != nil {
+		mask := image.NewAlpha(dst.Bounds())
+		drawRuns(mask, 0, 0, func(span int) image.Image {
+			if fills[span] != nil {
+				return nil
+			}
+			return image.Opaque
+		})
+		gradient := linearGradient{
+			bounds:     textBounds(lines),
+			from:       color.NRGBAModel.Convert(mustHexStringToColor(f.gradient.from)).(color.NRGBA),
+			to:         color.NRGBAModel.Convert(mustHexStringToColor(f.gradient.to)).(color.NRGBA),
+			horizontal: f.gradient.horizontal,
+		}
+		draw.DrawMask(dst, dst.Bounds(), gradient, dst.Bounds().Min, mask, dst.Bounds().Min, draw.Over)
+	}
+
+	drawRuns(dst, 0, 0, func(span int) image.Image {
+		return fills[span]
+	})
+}
+
+func (f textFilter) Bounds(srcBounds image.Rectangle) image.Rectangle {
+	return image.Rect(0, 0, srcBounds.Dx(), srcBounds.Dy())
+}
+
+// maxWidth returns the width to wrap the lines at, by default leaving a
+// margin of 20 pixels to the image border.
+func (f textFilter) maxWidth(bounds image.Rectangle) int {
+	if f.width > 0 {
+		return f.width
+	}
+
+	const margin = 20
+	var w int
+	switch f.alignx {
+	case "center":
+		w = 2 * (f.x - margin)
+		if right := 2 * (bounds.Dx() - margin - f.x); right < w {
+			w = right
+		}
+	case "right":
+		w = f.x - margin
+	default:
+		w = bounds.Dx() - margin - f.x
 	}
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
+// layoutText breaks the texts, each drawn with the face at the same index,
+// into lines no wider than maxWidth, if possible, and at line breaks.
+// The texts follow each other on the same line, separated by a space only
+// if there is whitespace between them.
+func layoutText(texts []string, faces []font.Face, maxWidth int) []textLine {
+	var (
+		lines     []textLine
+		line      textLine
+		needSpace bool
+		lastSpace bool
+	)
+
+	newLine := func(face font.Face) {
+		if line.ascent == 0 {
+			// An empty line.
+			line.ascent = face.Metrics().Ascent.Ceil()
+		}
+		lines = append(lines, line)
+		line = textLine{}
+		needSpace = false
+	}
+
+	for i, text := range texts {
+		face := faces[i]
+		metrics := face.Metrics()
+		spaceWidth := font.MeasureString(face, " ").Ceil()
+
+		needSpace = len(line.runs) > 0 && (lastSpace || startsWithSpace(text))
+
+		for j, paragraph := range strings.Split(text, "\n") {
+			if j > 0 {
+				newLine(face)
+			}
+			for _, word := range strings.Fields(paragraph) {
+				wordWidth := font.MeasureString(face, word).Ceil()
+				if needSpace {
+					if line.width+spaceWidth+wordWidth >= maxWidth {
+						newLine(face)
+					} else {
+						word = " " + word
+						wordWidth += spaceWidth
+					}
+				}
 
+				if n := len(line.runs); n > 0 && line.runs[n-1].span == i {
+					line.runs[n-1].text += word
+				} else {
+					line.runs = append(line.runs, textRun{span: i, text: word, x: line.width})
+				}
+				line.width += wordWidth
+				if a := metrics.Ascent.Ceil(); a > line.ascent {
+					line.ascent = a
+				}
+				if d := metrics.Descent.Ceil(); d > line.descent {
+					line.descent = d
+				}
+				needSpace = true
+			}
+		}
+
+		lastSpace = endsWithSpace(text)
+	}
+
+	if len(line.runs) > 0 {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// textBounds returns the bounds of the positioned lines.
+func textBounds(lines []textLine) image.Rectangle {
+	var r image.Rectangle
+	for _, line := range lines {
+		r = r.Union(image.Rect(line.x, line.y-line.ascent, line.x+line.width, line.y+line.descent))
+	}
+	return r
+}
+
+func newFontFace(fontSource hugio.ReadSeekCloserProvider, size float64) font.Face {
 	// Load and parse font
 	ttf := goregular.TTF
-	if f.fontSource != nil {
-		rs, err := f.fontSource.ReadSeekCloser()
+	if fontSource != nil {
+		rs, err := fontSource.ReadSeekCloser()
 		if err != nil {
 			panic(err)
 		}
@@ -64,7 +319,7 @@ func (f textFilter) Draw(dst draw.Image, src image.Image, options *gift.Options)
 
 	// Set font options
 	face, err := opentype.NewFace(otf, &opentype.FaceOptions{
-		Size:    f.size,
+		Size:    size,
 		DPI:     72,
 		Hinting: font.HintingNone,
 	})
@@ -72,37 +327,68 @@ func (f textFilter) Draw(dst draw.Image, src image.Image, options *gift.Options)
 		panic(err)
 	}
 
-	d := font.Drawer{
-		Dst:  dst,
-		Src:  image.NewUniform(color),
-		Face: face,
+	return face
+}
+
+func mustHexStringToColor(s string) color.Color {
+	c, err := hexStringToColor(s)
+	if err != nil {
+		panic(err)
 	}
+	return c
+}
 
-	gift.New().Draw(dst, src)
+func startsWithSpace(s string) bool {
+	for _, r := range s {
+		return unicode.IsSpace(r)
+	}
+	return false
+}
 
-	// Draw text, consider and include linebreaks
-	maxWidth := dst.Bounds().Dx() - 20
-	fontHeight := face.Metrics().Ascent.Ceil()
+func endsWithSpace(s string) bool {
+	return s != "" && unicode.IsSpace(rune(s[len(s)-1]))
+}
 
-	// Correct y position based on font and size
-	f.y = f.y + fontHeight
+// linearGradient is an infinite image with a linear gradient between two
+// colors across bounds, vertical unless horizontal is set.
+type linearGradient struct {
+	bounds     image.Rectangle
+	from, to   color.NRGBA
+	horizontal bool
+}
 
-	// Start position
-	y := f.y
-	d.Dot = fixed.P(f.x, f.y)
-
-	// Draw text and break line at max width
-	parts := strings.Fields(f.text)
-	for _, str := range parts {
-		strWith := font.MeasureString(face, str)
-		if (d.Dot.X.Ceil() + strWith.Ceil()) >= maxWidth {
-			y = y + fontHeight + f.linespacing
-			d.Dot = fixed.P(f.x, y)
-		}
-		d.DrawString(str + " ")
-	}
+func (g linearGradient) ColorModel() color.Model {
+	return color.NRGBAModel
 }
 
-func (f textFilter) Bounds(srcBounds image.Rectangle) image.Rectangle {
-	return image.Rect(0, 0, srcBounds.Dx(), srcBounds.Dy())
+func (g linearGradient) Bounds() image.Rectangle {
+	return image.Rectangle{Min: image.Point{X: -1e9, Y: -1e9}, Max: image.Point{X: 1e9, Y: 1e9}}
+}
+
+func (g linearGradient) At(x, y int) color.Color {
+	pos, size := y-g.bounds.Min.Y, g.bounds.Dy()
+	if g.horizontal {
+		pos, size = x-g.bounds.Min.X, g.bounds.Dx()
+	}
+
+	var t float64
+	if size > 1 {
+		t = float64(pos) / float64(size-1)
+	}
+	if t < 0 {
+		t = 0
+	} else if t > 1 {
+		t = 1
+	}
+
+	lerp := func(a, b uint8) uint8 {
+		return uint8(float64(a) + t*(float64(b)-float64(a)) + 0.5)
+	}
+
+	return color.NRGBA{
+		R: lerp(g.from.R, g.to.R),
+		G: lerp(g.from.G, g.to.G),
+		B: lerp(g.from.B, g.to.B),
+		A: lerp(g.from.A, g.to.A),
+	}
 }
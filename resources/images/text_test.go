@@ -0,0 +1,156 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package images
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/disintegration/gift"
+	"golang.org/x/image/font"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestLayoutText(t *testing.T) {
+	c := qt.New(t)
+
+	face := newFontFace(nil, 20)
+	big := newFontFace(nil, 40)
+	width := func(s string) int {
+		return font.MeasureString(face, s).Ceil()
+	}
+
+	lineTexts := func(lines []textLine) [][]string {
+		var texts [][]string
+		for _, line := range lines {
+			var runs []string
+			for _, run := range line.runs {
+				runs = append(runs, run.text)
+			}
+			texts = append(texts, runs)
+		}
+		return texts
+	}
+
+	c.Run("Wrap", func(c *qt.C) {
+		lines := layoutText([]string{"Hugo rocks hard"}, []font.Face{face}, width("Hugo rocks")+1)
+		c.Assert(lineTexts(lines), qt.DeepEquals, [][]string{{"Hugo rocks"}, {"hard"}})
+		c.Assert(lines[0].width, qt.Equals, width("Hugo")+width(" ")+width("rocks"))
+	})
+
+	c.Run("Line breaks", func(c *qt.C) {
+		lines := layoutText([]string{"Hugo\n\nrocks"}, []font.Face{face}, 1000)
+		c.Assert(lineTexts(lines), qt.DeepEquals, [][]string{{"Hugo"}, nil, {"rocks"}})
+		c.Assert(lines[1].ascent, qt.Equals, face.Metrics().Ascent.Ceil())
+	})
+
+	c.Run("Spans", func(c *qt.C) {
+		lines := layoutText([]string{"Hu", "go ", "rocks"}, []font.Face{face, big, face}, 1000)
+		c.Assert(lineTexts(lines), qt.DeepEquals, [][]string{{"Hu", "go", " rocks"}})
+		c.Assert(lines[0].runs[1].x, qt.Equals, width("Hu"))
+		c.Assert(lines[0].ascent, qt.Equals, big.Metrics().Ascent.Ceil())
+	})
+}
+
+func TestTextFilterDraw(t *testing.T) {
+	c := qt.New(t)
+
+	red := color.NRGBA{R: 255, A: 255}
+	draw := func(f gift.Filter) *image.NRGBA {
+		src := image.NewNRGBA(image.Rect(0, 0, 400, 100))
+		dst := image.NewNRGBA(src.Bounds())
+		f.Draw(dst, src, nil)
+		return dst
+	}
+
+	// colored returns the bounds of the pixels with the given color.
+	colored := func(img *image.NRGBA, cc color.NRGBA) image.Rectangle {
+		var r image.Rectangle
+		for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+			for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+				if img.NRGBAAt(x, y) == cc {
+					r = r.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+		return r
+	}
+
+	f := &Filters{}
+
+	left := colored(draw(f.Text("Hugo", map[string]any{"color": "#ff0000", "x": 200})), red)
+	c.Assert(left.Empty(), qt.IsFalse)
+	c.Assert(left.Min.X >= 200, qt.IsTrue)
+
+	right := colored(draw(f.Text("Hugo", map[string]any{"color": "#ff0000", "x": 200, "alignx": "right"})), red)
+	c.Assert(right.Max.X <= 201, qt.IsTrue)
+
+	center := colored(draw(f.Text("Hugo", map[string]any{"color": "#ff0000", "x": 200, "alignx": "center"})), red)
+	c.Assert(center.Min.X < 200 && center.Max.X > 200, qt.IsTrue)
+
+	shadowed := draw(f.Text("Hugo", map[string]any{"color": "#ff0000", "shadow": map[string]any{"color": "#0000ff", "x": 3, "y": 3}}))
+	shadow := colored(shadowed, color.NRGBA{B: 255, A: 255})
+	c.Assert(shadow.Empty(), qt.IsFalse)
+	c.Assert(shadow.Max.X > colored(shadowed, red).Max.X, qt.IsTrue)
+
+	gradient := draw(f.Text("Hugo", map[string]any{"size": 60, "gradient": map[string]any{"from": "#ff0000", "to": "#0000ff"}}))
+	var top, bottom color.NRGBA
+	for y := gradient.Bounds().Min.Y; y < gradient.Bounds().Max.Y; y++ {
+		for x := gradient.Bounds().Min.X; x < gradient.Bounds().Max.X; x++ {
+			if p := gradient.NRGBAAt(x, y); p.A == 255 {
+				if top.A == 0 {
+					top = p
+				}
+				bottom = p
+			}
+		}
+	}
+	c.Assert(top.R > top.B, qt.IsTrue)
+	c.Assert(bottom.B > bottom.R, qt.IsTrue)
+
+	c.Assert(func() { f.Text("Hugo", map[string]any{"alignx": "middle"}) }, qt.PanicMatches, `invalid text alignx.*`)
+}
+
+func TestOverlayFilterOpacity(t *testing.T) {
+	c := qt.New(t)
+
+	overlay := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(overlay, overlay.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	src := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	draw.Draw(src, src.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	dst := image.NewNRGBA(src.Bounds())
+
+	f := overlayFilter{src: testImageSource{overlay}, x: 5, y: 5, opacity: 0.5}
+	f.Draw(dst, src, nil)
+
+	c.Assert(dst.NRGBAAt(0, 0), qt.Equals, color.NRGBA{A: 255})
+	c.Assert(dst.NRGBAAt(10, 10), qt.Equals, color.NRGBA{R: 128, G: 128, B: 128, A: 255})
+	c.Assert(dst.NRGBAAt(15, 15), qt.Equals, color.NRGBA{A: 255})
+}
+
+type testImageSource struct {
+	img image.Image
+}
+
+func (s testImageSource) DecodeImage() (image.Image, error) {
+	return s.img, nil
+}
+
+func (s testImageSource) Key() string {
+	return "test"
+}
@@ -94,3 +94,63 @@ Width: {{ $svg.Width }}
 	b.Assert(err.Error(), qt.Contains, `error calling Width: this method is only available for raster images. To determine if an image is SVG, you can do {{ if eq .MediaType.SubType "svg" }}{{ end }}`)
 
 }
+
+func TestImagePresets(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+[imaging.presets.thumbnail]
+steps = ["resize 2x2", "filter grayscale", "format gif"]
+[imaging.presets.gallery]
+steps = ["resize 3x3"]
+sections = ["galleries"]
+-- content/mybundle/index.md --
+---
+title: "My Bundle"
+---
+-- content/mybundle/pixel.png --
+iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==
+-- content/galleries/mygallery/index.md --
+---
+title: "My Gallery"
+---
+-- content/galleries/mygallery/pixel.png --
+iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==
+-- layouts/index.html --
+{{ $img := (site.GetPage "mybundle").Resources.Get "pixel.png" }}
+{{ $thumb := $img.Process "preset:thumbnail" }}
+{{ $bmp := $img.Process "fill 4x4 bmp" }}
+{{ $gallery := (site.GetPage "galleries/mygallery").Resources.Get "pixel.png" }}
+Original: {{ $img.Width }}x{{ $img.Height }}|
+Thumb: {{ $thumb.Width }}x{{ $thumb.Height }}|{{ $thumb.MediaType }}|
+Bmp: {{ $bmp.Width }}x{{ $bmp.Height }}|{{ $bmp.MediaType }}|
+Gallery: {{ $gallery.Width }}x{{ $gallery.Height }}|
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			NeedsOsFS:   true,
+		}).Build()
+
+	b.AssertFileContent("public/index.html", `
+Original: 1x1|
+Thumb: 2x2|image/gif|
+Bmp: 4x4|image/bmp|
+Gallery: 3x3|
+`)
+
+	files = strings.Replace(files, "{{ $thumb := $img.Process \"preset:thumbnail\" }}", "{{ $thumb := $img.Process \"preset:nope\" }}", 1)
+	b, err := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			NeedsOsFS:   true,
+		}).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, `image preset "nope" not found`)
+}
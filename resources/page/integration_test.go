@@ -136,3 +136,52 @@ Sort: [éclair emma xylophone zulu]
 ByWeight: alpha|émotion|zulu|
 `)
 }
+
+func TestRelatedExplain(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "rss", "sitemap", "robotsTXT", "404"]
+[related]
+threshold = 0
+boostParam = "relatedBoost"
+[related.recency]
+decay = "exponential"
+scale = "8760h"
+[[related.indices]]
+name = "tags"
+weight = 100
+overlapWeight = 10
+-- content/p1.md --
+---
+title: "P1"
+date: "2022-01-01"
+tags: ["a", "b"]
+---
+-- content/p2.md --
+---
+title: "P2"
+date: "2021-01-01"
+tags: ["a", "b"]
+---
+-- content/p3.md --
+---
+title: "P3"
+date: "2021-01-01"
+tags: ["a"]
+relatedBoost: 4
+---
+-- layouts/_default/single.html --
+{{ range .Site.RegularPages.RelatedExplain . }}{{ .Page.Title }}: {{ .Explain }}|{{ end }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		}).Build()
+
+	b.AssertFileContent("public/p1/index.html", "P3: tags: a; weight 100, decay 0.50, boost 4, score 200.00|P2: tags: a, b; weight 100, overlap 10, decay 0.50, score 55.00|")
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"fmt"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/spf13/cast"
+)
+
+// LLMs configures the pages listed in llms.txt and exported to Markdown,
+// see the llms and Markdown output formats, e.g.:
+//
+//	[llms]
+//	[[llms.include]]
+//	path = "/docs/**"
+//	[[llms.exclude]]
+//	path = "/docs/internal/**"
+//
+// A page can also set llms = false or true in front matter, which takes
+// precedence over the rules.
+type LLMs struct {
+	// Include the pages matching any of these, all if empty.
+	Include []PageMatcher
+
+	// Exclude the pages matching any of these.
+	Exclude []PageMatcher
+}
+
+// DecodeLLMs decodes the llms section in site config.
+func DecodeLLMs(in map[string]any) (LLMs, error) {
+	var c LLMs
+	if in == nil {
+		return c, nil
+	}
+
+	decode := func(key string) ([]PageMatcher, error) {
+		v, found := in[key]
+		if !found {
+			return nil, nil
+		}
+		ms, err := maps.ToSliceStringMap(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode llms.%s config: %w", key, err)
+		}
+		matchers := make([]PageMatcher, len(ms))
+		for i, m := range ms {
+			if err := DecodePageMatcher(m, &matchers[i]); err != nil {
+				return nil, fmt.Errorf("failed to decode llms.%s config: %w", key, err)
+			}
+		}
+		return matchers, nil
+	}
+
+	var err error
+	if c.Include, err = decode("include"); err != nil {
+		return c, err
+	}
+	if c.Exclude, err = decode("exclude"); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// Includes returns whether p should be listed in llms.txt and exported to
+// Markdown.
+func (c LLMs) Includes(p Page) bool {
+	if v, found := p.Params()["llms"]; found {
+		return cast.ToBool(v)
+	}
+
+	for _, m := range c.Exclude {
+		if m.Matches(p) {
+			return false
+		}
+	}
+
+	if len(c.Include) == 0 {
+		return true
+	}
+	for _, m := range c.Include {
+		if m.Matches(p) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -48,6 +48,11 @@ type AlternativeOutputFormatsProvider interface {
 	// Note that we use the term "alternative" and not "alternate" here, as it
 	// does not necessarily replace the other format, it is an alternative representation.
 	AlternativeOutputFormats() OutputFormats
+
+	// Alternates returns the alternative output formats of the Page followed
+	// by the output formats of its translations.
+	// This is what is needed for the <link rel="alternate"> elements in head.
+	Alternates() Alternates
 }
 
 // AuthorProvider provides author information.
@@ -73,12 +78,21 @@ type ChildCareProvider interface {
 	RegularPagesRecursive() Pages
 
 	Resources() resource.Resources
+
+	// Gallery returns the page's image resources with thumbnail and large
+	// variants, ordered and grouped as configured in gallery in site config
+	// and front matter. An optional map overrides the configuration.
+	Gallery(options ...any) (*Gallery, error)
 }
 
 // ContentProvider provides the content related values for a Page.
 type ContentProvider interface {
 	Content() (any, error)
 
+	// ContentBlocks returns the named content blocks, rendered, that follow
+	// the main content, marked with e.g. <!--block: sidebar--> in the source.
+	ContentBlocks() map[string]template.HTML
+
 	// Plain returns the Page Content stripped of HTML markup.
 	Plain() string
 
@@ -135,6 +149,17 @@ type InSectionPositioner interface {
 	PrevInSection() Page
 }
 
+// InCollectionPositioner provides navigation in any page collection.
+type InCollectionPositioner interface {
+	// NextInCollection returns the next page relative to this in pages,
+	// nil if not found or at the start. Same as pages.Next.
+	NextInCollection(pages Pages) Page
+
+	// PrevInCollection returns the previous page relative to this in pages,
+	// nil if not found or at the end. Same as pages.Prev.
+	PrevInCollection(pages Pages) Page
+}
+
 // InternalDependencies is considered an internal interface.
 type InternalDependencies interface {
 	// GetRelatedDocsHandler is for internal use only.
@@ -158,6 +183,9 @@ type PageMetaProvider interface {
 	// The 4 page dates
 	resource.Dated
 
+	// The precision of the date.
+	resource.DatePrecisionProvider
+
 	// Aliases forms the base for redirects generation.
 	Aliases() []string
 
@@ -238,6 +266,19 @@ type PageMetaProvider interface {
 type PageRenderProvider interface {
 	Render(layout ...string) (template.HTML, error)
 	RenderString(args ...any) (template.HTML, error)
+
+	// RenderShortcode renders the top level shortcode with the given name
+	// in the content, the first if index is not set.
+	RenderShortcode(name string, index ...int) (template.HTML, error)
+
+	// RenderFragment renders the content below the heading with the given
+	// id, up to the next heading of the same or a higher level.
+	RenderFragment(id string) (template.HTML, error)
+
+	// Include renders the content of another page, or the fragment below the
+	// heading with the id given after a #, e.g. "/docs/install.md#requirements",
+	// with relative links and footnotes rewritten.
+	Include(ref string) (template.HTML, error)
 }
 
 // PageWithoutContent is the Page without any of the content methods.
@@ -262,6 +303,7 @@ type PageWithoutContent interface {
 
 	// Horizontal navigation
 	InSectionPositioner
+	InCollectionPositioner
 	PageRenderProvider
 	PaginatorProvider
 	Positioner
@@ -273,9 +315,11 @@ type PageWithoutContent interface {
 	// Page lookups/refs
 	GetPageProvider
 	RefProvider
+	NumberingProvider
 
 	resource.TranslationKeyProvider
 	TranslationsProvider
+	CanonicalProvider
 
 	SitesProvider
 
@@ -291,6 +335,11 @@ type PageWithoutContent interface {
 	// In contrast to Scratch(), this Scratch is not reset on server rebuilds.
 	Store() *maps.Scratch
 
+	// RenderScratch returns a Scratch that can be used to store temporary state
+	// while rendering the page in the current output format.
+	// It gets reset every time the page is rendered.
+	RenderScratch() *maps.Scratch
+
 	RelatedKeywordsProvider
 
 	// GetTerms gets the terms of a given taxonomy,
@@ -318,6 +367,27 @@ type Positioner interface {
 // RawContentProvider provides the raw, unprocessed content of the page.
 type RawContentProvider interface {
 	RawContent() string
+
+	// RawContentSource returns the source of the page as read from file,
+	// including any front matter.
+	RawContentSource() string
+
+	// RawFrontMatter returns the front matter as written in the source.
+	RawFrontMatter() RawFrontMatter
+}
+
+// RawFrontMatter is the front matter of a page as written in the source.
+type RawFrontMatter struct {
+	// The front matter format, one of yaml, toml, json or org.
+	// Empty if the page has no front matter.
+	Format string
+
+	// The decoded front matter with the keys in their original case and
+	// before any defaults, cascades or enrichments are applied.
+	Map map[string]any
+
+	// The front matter source without the delimiters.
+	Source string
 }
 
 // RefProvider provides the methods needed to create reflinks to pages.
@@ -333,6 +403,19 @@ type RefProvider interface {
 	RelRefFrom(argsm map[string]any, source any) (string, error)
 }
 
+// NumberingProvider provides the numbered figures, tables, listings and
+// equations in a Page's content.
+type NumberingProvider interface {
+	// Numbering returns the numbers assigned while rendering the content
+	// for the current output format.
+	Numbering() *Numbering
+
+	// NumberRef returns a link to the numbered element with the given ID,
+	// e.g. "fig-1", or in another page, e.g. "other.md#fig-1", labeled
+	// with its number, e.g. Figure 3.
+	NumberRef(ref string) (template.HTML, error)
+}
+
 // RelatedKeywordsProvider allows a Page to be indexed.
 type RelatedKeywordsProvider interface {
 	// Make it indexable as a related.Document
@@ -373,9 +456,28 @@ type TranslationsProvider interface {
 	Translations() Pages
 }
 
+// CanonicalProvider provides the canonical URL and the hreflang alternates
+// of a Page, see the canonical section in site config.
+type CanonicalProvider interface {
+	// Canonical returns the canonical URL of the page: the canonical front
+	// matter param if set, else the Permalink with the configured canonical
+	// host.
+	Canonical() string
+
+	// Hreflangs returns the canonical URLs of all translations, including
+	// the current Page, followed by the x-default entry if any.
+	// It is empty if the Page is not translated.
+	Hreflangs() []Hreflang
+}
+
 // TreeProvider provides section tree navigation.
 type TreeProvider interface {
 
+	// Breadcrumbs returns the trail from the home page, through the page's
+	// ancestor sections, down to the page itself, truncated as configured
+	// in breadcrumbs.maxEntries.
+	Breadcrumbs() Breadcrumbs
+
 	// IsAncestor returns whether the current page is an ancestor of the given
 	// Note that this method is not relevant for taxonomy lists and taxonomy terms pages.
 	IsAncestor(other any) (bool, error)
@@ -0,0 +1,115 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// DefaultBreadcrumbsConfig holds the default breadcrumbs configuration.
+var DefaultBreadcrumbsConfig = BreadcrumbsConfig{
+	Home: true,
+}
+
+// BreadcrumbsConfig configures the Page's Breadcrumbs.
+type BreadcrumbsConfig struct {
+	// Whether to start the trail with the home page.
+	Home bool
+
+	// The maximum number of entries in the trail, 0 means no limit.
+	// When truncated, the first entry is kept, followed by an ellipsis
+	// entry and the last entries.
+	MaxEntries int
+}
+
+// DecodeBreadcrumbsConfig decodes the breadcrumbs section in site config.
+func DecodeBreadcrumbsConfig(in any) (BreadcrumbsConfig, error) {
+	c := DefaultBreadcrumbsConfig
+	if in == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode breadcrumbs config: %w", err)
+	}
+
+	if c.MaxEntries < 0 || c.MaxEntries == 1 || c.MaxEntries == 2 {
+		return c, fmt.Errorf("breadcrumbs.maxEntries must be 0 (no limit) or at least 3, got %d", c.MaxEntries)
+	}
+
+	return c, nil
+}
+
+// Breadcrumb is an entry in a breadcrumb trail.
+type Breadcrumb struct {
+	// The Page, nil if this is an ellipsis entry.
+	Page Page
+
+	// The Page's LinkTitle.
+	Name string
+
+	RelPermalink string
+	Permalink    string
+
+	// The 1-based position in the full trail, as used in
+	// schema.org's BreadcrumbList. 0 for the ellipsis entry.
+	Position int
+
+	// Whether this is the current page, i.e. the last entry in the trail.
+	IsCurrent bool
+
+	// Whether this entry represents the entries removed by truncation.
+	IsEllipsis bool
+}
+
+// Breadcrumbs is a breadcrumb trail, from the top level down to the current page.
+type Breadcrumbs []Breadcrumb
+
+// NewBreadcrumbs creates a trail for p given its ancestors, nearest last.
+func NewBreadcrumbs(cfg BreadcrumbsConfig, ancestors Pages, p Page) Breadcrumbs {
+	var trail Pages
+	for _, a := range ancestors {
+		if !cfg.Home && a.IsHome() {
+			continue
+		}
+		trail = append(trail, a)
+	}
+	trail = append(trail, p)
+
+	crumbs := make(Breadcrumbs, len(trail))
+	for i, pp := range trail {
+		crumbs[i] = Breadcrumb{
+			Page:         pp,
+			Name:         pp.LinkTitle(),
+			RelPermalink: pp.RelPermalink(),
+			Permalink:    pp.Permalink(),
+			Position:     i + 1,
+			IsCurrent:    i == len(trail)-1,
+		}
+	}
+
+	if cfg.MaxEntries == 0 || len(crumbs) <= cfg.MaxEntries {
+		return crumbs
+	}
+
+	// Keep the first entry and the last MaxEntries-2 entries.
+	truncated := make(Breadcrumbs, 0, cfg.MaxEntries)
+	truncated = append(truncated, crumbs[0])
+	truncated = append(truncated, Breadcrumb{Name: "…", IsEllipsis: true})
+	truncated = append(truncated, crumbs[len(crumbs)-(cfg.MaxEntries-2):]...)
+
+	return truncated
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// XDefaultNone disables the x-default hreflang entry.
+const XDefaultNone = "none"
+
+// CanonicalConfig configures the canonical URLs and the hreflang alternates
+// of the pages, e.g.:
+//
+//	[canonical]
+//	host = "https://www.example.org"
+//	xDefault = "en"
+//
+// Both can be set per language and, as any other setting, per environment.
+type CanonicalConfig struct {
+	// The scheme and host, e.g. https://www.example.org, replacing those of
+	// the permalinks. Empty means the host in baseURL.
+	Host string
+
+	// The language of the x-default hreflang entry: a language code, empty
+	// for the defaultContentLanguage, or "none" to leave it out.
+	XDefault string
+}
+
+// DecodeCanonicalConfig decodes the canonical section in site config.
+func DecodeCanonicalConfig(in any) (CanonicalConfig, error) {
+	var c CanonicalConfig
+	if in == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode canonical config: %w", err)
+	}
+
+	if c.Host != "" {
+		u, err := url.Parse(c.Host)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return c, fmt.Errorf("canonical.host must be an absolute URL with a scheme and a host, got %q", c.Host)
+		}
+		if u.Path != "" && u.Path != "/" {
+			return c, fmt.Errorf("canonical.host must not have a path, got %q", c.Host)
+		}
+		c.Host = u.Scheme + "://" + u.Host
+	}
+
+	c.XDefault = strings.ToLower(c.XDefault)
+
+	return c, nil
+}
+
+// CanonicalURL returns the URL with its scheme and host replaced with host,
+// or the URL unchanged if host is empty.
+func CanonicalURL(u, host string) string {
+	if host == "" {
+		return u
+	}
+	pu, err := url.Parse(u)
+	if err != nil {
+		return u
+	}
+	if !pu.IsAbs() {
+		return strings.TrimSuffix(host, "/") + "/" + strings.TrimPrefix(u, "/")
+	}
+	hu, err := url.Parse(host)
+	if err != nil {
+		return u
+	}
+	pu.Scheme, pu.Host = hu.Scheme, hu.Host
+	return pu.String()
+}
+
+// Hreflang is an alternate language version of a page.
+type Hreflang struct {
+	// The hreflang value: the languageCode of the language if set,
+	// else its Lang, or x-default.
+	Lang string
+
+	// The canonical URL of the translation.
+	URL string
+}
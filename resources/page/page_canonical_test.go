@@ -0,0 +1,46 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeCanonicalConfig(t *testing.T) {
+	c := qt.New(t)
+
+	conf, err := DecodeCanonicalConfig(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf, qt.Equals, CanonicalConfig{})
+
+	conf, err = DecodeCanonicalConfig(map[string]any{"host": "https://www.example.org/", "xDefault": "EN"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf, qt.Equals, CanonicalConfig{Host: "https://www.example.org", XDefault: "en"})
+
+	_, err = DecodeCanonicalConfig(map[string]any{"host": "www.example.org"})
+	c.Assert(err, qt.ErrorMatches, ".*absolute URL.*")
+	_, err = DecodeCanonicalConfig(map[string]any{"host": "https://www.example.org/blog/"})
+	c.Assert(err, qt.ErrorMatches, ".*must not have a path.*")
+}
+
+func TestCanonicalURL(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(CanonicalURL("https://example.org/a/b/", ""), qt.Equals, "https://example.org/a/b/")
+	c.Assert(CanonicalURL("https://example.org/a/b/?q=1", "https://www.example.org"), qt.Equals, "https://www.example.org/a/b/?q=1")
+	c.Assert(CanonicalURL("http://localhost:1313/a/", "https://www.example.org"), qt.Equals, "https://www.example.org/a/")
+	c.Assert(CanonicalURL("/a/", "https://www.example.org"), qt.Equals, "https://www.example.org/a/")
+}
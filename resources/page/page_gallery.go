@@ -0,0 +1,235 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/resources/images"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+// DefaultGalleryConfig holds the default gallery configuration.
+var DefaultGalleryConfig = GalleryConfig{
+	Match:     "**",
+	Thumbnail: "fill 300x300",
+	Large:     "fit 1600x1600",
+}
+
+// GalleryConfig configures the Page's Gallery.
+// It can be set in site config and overridden in front matter.
+type GalleryConfig struct {
+	// A glob matching the names of the image resources to include,
+	// e.g. "gallery/**".
+	Match string
+
+	// How to order the images: by "name", by Exif "date" or, if not set,
+	// in the order of the page's resources.
+	Sort string
+
+	// Whether to reverse the order.
+	Reverse bool
+
+	// How to group the images: by "dir", the directory of the resource, or
+	// by the "year" or "month" of the Exif date. Not set gives one group.
+	GroupBy string
+
+	// The image processing of the thumbnail and large variants, the method
+	// (crop, fill, fit or resize) followed by its options, e.g. "fill 300x300 center".
+	Thumbnail string
+	Large     string
+}
+
+// DecodeGalleryConfig decodes the gallery section in site config or front
+// matter into a copy of base.
+func DecodeGalleryConfig(base GalleryConfig, in any) (GalleryConfig, error) {
+	c := base
+	if in == nil {
+		return c, nil
+	}
+
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode gallery config: %w", err)
+	}
+
+	switch c.Sort {
+	case "", "name", "date":
+	default:
+		return c, fmt.Errorf("gallery.sort must be one of name or date, got %q", c.Sort)
+	}
+
+	switch c.GroupBy {
+	case "", "dir", "year", "month":
+	default:
+		return c, fmt.Errorf("gallery.groupBy must be one of dir, year or month, got %q", c.GroupBy)
+	}
+
+	for _, spec := range []string{c.Thumbnail, c.Large} {
+		if _, _, err := parseGalleryProcessing(spec); err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
+}
+
+func parseGalleryProcessing(s string) (method, spec string, err error) {
+	method, spec, _ = strings.Cut(strings.TrimSpace(s), " ")
+	switch strings.ToLower(method) {
+	case "crop", "fill", "fit", "resize":
+		return strings.ToLower(method), strings.TrimSpace(spec), nil
+	}
+	return "", "", fmt.Errorf("gallery: invalid image processing %q, must start with one of crop, fill, fit or resize", s)
+}
+
+// GalleryImage is an image in a Gallery.
+type GalleryImage struct {
+	// The original image.
+	Image images.ImageResource
+
+	// The processed variants as configured in gallery.thumbnail and gallery.large.
+	Thumbnail images.ImageResource
+	Large     images.ImageResource
+
+	// The Exif ImageDescription, else the resource title if set.
+	Caption string
+
+	// The Exif date, if any.
+	Date time.Time
+}
+
+// GalleryGroup is a group of images in a Gallery.
+type GalleryGroup struct {
+	// The directory, year or month the images are grouped by.
+	// Empty if not grouped.
+	Key string
+
+	Images []GalleryImage
+}
+
+// Gallery holds a page's images ordered and grouped as configured.
+type Gallery struct {
+	// All images.
+	Images []GalleryImage
+
+	// The images grouped as configured in gallery.groupBy, a single group if not set.
+	Groups []GalleryGroup
+}
+
+// NewGallery creates a Gallery from the image resources in resources as configured in cfg.
+func NewGallery(cfg GalleryConfig, resources resource.Resources) (*Gallery, error) {
+	thumbMethod, thumbSpec, err := parseGalleryProcessing(cfg.Thumbnail)
+	if err != nil {
+		return nil, err
+	}
+	largeMethod, largeSpec, err := parseGalleryProcessing(cfg.Large)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Gallery{}
+
+	for _, r := range resources.Match(cfg.Match) {
+		img, ok := r.(images.ImageResource)
+		if !ok {
+			continue
+		}
+
+		gi := GalleryImage{Image: img}
+		if x := img.Exif(); x != nil {
+			gi.Date = x.Date
+			gi.Caption = strings.TrimSpace(cast.ToString(x.Tags["ImageDescription"]))
+		}
+		if gi.Caption == "" && img.Title() != img.Name() {
+			gi.Caption = img.Title()
+		}
+
+		if gi.Thumbnail, err = processGalleryImage(img, thumbMethod, thumbSpec); err != nil {
+			return nil, err
+		}
+		if gi.Large, err = processGalleryImage(img, largeMethod, largeSpec); err != nil {
+			return nil, err
+		}
+
+		g.Images = append(g.Images, gi)
+	}
+
+	switch cfg.Sort {
+	case "name":
+		sort.SliceStable(g.Images, func(i, j int) bool {
+			return g.Images[i].Image.Name() < g.Images[j].Image.Name()
+		})
+	case "date":
+		sort.SliceStable(g.Images, func(i, j int) bool {
+			return g.Images[i].Date.Before(g.Images[j].Date)
+		})
+	}
+
+	if cfg.Reverse {
+		for i, j := 0, len(g.Images)-1; i < j; i, j = i+1, j-1 {
+			g.Images[i], g.Images[j] = g.Images[j], g.Images[i]
+		}
+	}
+
+	groupKey := func(gi GalleryImage) string {
+		switch cfg.GroupBy {
+		case "dir":
+			if dir := path.Dir(gi.Image.Name()); dir != "." {
+				return dir
+			}
+		case "year":
+			if !gi.Date.IsZero() {
+				return gi.Date.Format("2006")
+			}
+		case "month":
+			if !gi.Date.IsZero() {
+				return gi.Date.Format("2006-01")
+			}
+		}
+		return ""
+	}
+
+	groups := make(map[string]int)
+	for _, gi := range g.Images {
+		key := groupKey(gi)
+		i, found := groups[key]
+		if !found {
+			i = len(g.Groups)
+			groups[key] = i
+			g.Groups = append(g.Groups, GalleryGroup{Key: key})
+		}
+		g.Groups[i].Images = append(g.Groups[i].Images, gi)
+	}
+
+	return g, nil
+}
+
+func processGalleryImage(img images.ImageResource, method, spec string) (images.ImageResource, error) {
+	switch method {
+	case "crop":
+		return img.Crop(spec)
+	case "fill":
+		return img.Fill(spec)
+	case "fit":
+		return img.Fit(spec)
+	default:
+		return img.Resize(spec)
+	}
+}
@@ -13,7 +13,14 @@
 
 package page
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/resources/page/pagemeta"
+	"github.com/mitchellh/mapstructure"
+)
 
 const (
 	KindPage = "page"
@@ -45,3 +52,83 @@ var kindMap = map[string]string{
 func GetKind(s string) string {
 	return kindMap[strings.ToLower(s)]
 }
+
+// The kinds that can not be used as custom kinds, in addition to the
+// page kinds above.
+var reservedKinds = map[string]bool{
+	"rss":       true,
+	"sitemap":   true,
+	"robotstxt": true,
+	"404":       true,
+}
+
+// KindConfig configures a custom page kind, set in front matter
+// with e.g. kind: author.
+type KindConfig struct {
+	// The layouts to try after the kind's own, e.g. author.html, before
+	// falling back to the single or list layouts.
+	Layouts []string
+
+	// The permalink pattern for the pages of this kind, with the same
+	// syntax as in the permalinks config.
+	Permalink string
+
+	// Where the pages of this kind are listed, as in _build.list.
+	// Note that they are never included in .Site.RegularPages.
+	List string
+
+	// The output formats to render the pages of this kind in.
+	// Defaults to the output formats of its regular kind.
+	Outputs []string
+}
+
+// KindsConfig holds the custom page kinds keyed by their name, e.g.:
+//
+//	[kinds.author]
+//	layouts = ["person"]
+//	permalink = "/people/:slug/"
+//	list = "local"
+type KindsConfig map[string]KindConfig
+
+// DecodeKindsConfig decodes the kinds section in site config.
+func DecodeKindsConfig(in any) (KindsConfig, error) {
+	c := make(KindsConfig)
+	if in == nil {
+		return c, nil
+	}
+
+	m, err := maps.ToStringMapE(in)
+	if err != nil {
+		return c, fmt.Errorf("failed to decode kinds config: %w", err)
+	}
+
+	for k, v := range m {
+		name := strings.ToLower(k)
+		if GetKind(name) != "" || reservedKinds[name] {
+			return c, fmt.Errorf("kinds: %q is a built-in kind", k)
+		}
+
+		var kc KindConfig
+		if err := mapstructure.WeakDecode(v, &kc); err != nil {
+			return c, fmt.Errorf("failed to decode kind %q: %w", k, err)
+		}
+
+		switch kc.List {
+		case "":
+			kc.List = pagemeta.Always
+		case pagemeta.Always, pagemeta.Never, pagemeta.ListLocally:
+		default:
+			return c, fmt.Errorf("kind %q: invalid list value %q, must be one of always, local or never", k, kc.List)
+		}
+
+		c[name] = kc
+	}
+
+	return c, nil
+}
+
+// Get returns the config for the custom kind, if configured.
+func (c KindsConfig) Get(kind string) (KindConfig, bool) {
+	kc, found := c[strings.ToLower(kind)]
+	return kc, found
+}
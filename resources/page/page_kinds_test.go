@@ -35,3 +35,42 @@ func TestKind(t *testing.T) {
 	c.Assert(GetKind("Home"), qt.Equals, KindHome)
 	c.Assert(GetKind("SEction"), qt.Equals, KindSection)
 }
+
+func TestDecodeKindsConfig(t *testing.T) {
+	t.Parallel()
+	c := qt.New(t)
+
+	kinds, err := DecodeKindsConfig(map[string]any{
+		"Author": map[string]any{
+			"layouts":   []any{"person"},
+			"permalink": "/people/:slug/",
+		},
+		"changelog": map[string]any{
+			"list":    "local",
+			"outputs": []any{"HTML", "RSS"},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+
+	author, found := kinds.Get("author")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(author, qt.DeepEquals, KindConfig{
+		Layouts:   []string{"person"},
+		Permalink: "/people/:slug/",
+		List:      "always",
+	})
+
+	changelog, found := kinds.Get("Changelog")
+	c.Assert(found, qt.IsTrue)
+	c.Assert(changelog.List, qt.Equals, "local")
+	c.Assert(changelog.Outputs, qt.DeepEquals, []string{"HTML", "RSS"})
+
+	_, found = kinds.Get("page")
+	c.Assert(found, qt.IsFalse)
+
+	_, err = DecodeKindsConfig(map[string]any{"section": map[string]any{}})
+	c.Assert(err, qt.ErrorMatches, `kinds: "section" is a built-in kind`)
+
+	_, err = DecodeKindsConfig(map[string]any{"author": map[string]any{"list": "sometimes"}})
+	c.Assert(err, qt.ErrorMatches, `kind "author": invalid list value "sometimes".*`)
+}
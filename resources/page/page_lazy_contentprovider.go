@@ -68,6 +68,11 @@ func (lcp *LazyContentProvider) Content() (any, error) {
 	return lcp.cp.Content()
 }
 
+func (lcp *LazyContentProvider) ContentBlocks() map[string]template.HTML {
+	lcp.init.Do()
+	return lcp.cp.ContentBlocks()
+}
+
 func (lcp *LazyContentProvider) Plain() string {
 	lcp.init.Do()
 	return lcp.cp.Plain()
@@ -118,6 +123,21 @@ func (lcp *LazyContentProvider) RenderString(args ...any) (template.HTML, error)
 	return lcp.cp.RenderString(args...)
 }
 
+func (lcp *LazyContentProvider) RenderShortcode(name string, index ...int) (template.HTML, error) {
+	lcp.init.Do()
+	return lcp.cp.RenderShortcode(name, index...)
+}
+
+func (lcp *LazyContentProvider) RenderFragment(id string) (template.HTML, error) {
+	lcp.init.Do()
+	return lcp.cp.RenderFragment(id)
+}
+
+func (lcp *LazyContentProvider) Include(ref string) (template.HTML, error) {
+	lcp.init.Do()
+	return lcp.cp.Include(ref)
+}
+
 func (lcp *LazyContentProvider) TableOfContents() template.HTML {
 	lcp.init.Do()
 	return lcp.cp.TableOfContents()
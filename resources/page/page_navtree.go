@@ -0,0 +1,147 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+// NavTree is the navigation tree of a section, e.g. the table of contents
+// of a documentation site spanning many pages.
+type NavTree struct {
+	// The section.
+	Root *NavNode
+
+	// All nodes below the root in depth-first order.
+	nodes []*NavNode
+
+	index map[Page]*NavNode
+}
+
+// NavNode is a page in a NavTree.
+type NavNode struct {
+	Page Page
+
+	// The parent node, nil for the root.
+	Parent *NavNode
+
+	// The pages and sections of a section in the default sort order,
+	// i.e. by weight.
+	Children []*NavNode
+
+	// The depth below the root, 0 for the root.
+	Level int
+
+	tree *NavTree
+}
+
+// NewNavTree creates the navigation tree of the section root.
+// pages returns the pages and sections of a section.
+func NewNavTree(root Page, pages func(section Page) Pages) *NavTree {
+	t := &NavTree{index: make(map[Page]*NavNode)}
+	t.Root = t.add(nil, root, pages)
+	return t
+}
+
+func (t *NavTree) add(parent *NavNode, p Page, pages func(section Page) Pages) *NavNode {
+	n := &NavNode{Page: p, Parent: parent, tree: t}
+	if parent != nil {
+		n.Level = parent.Level + 1
+		t.nodes = append(t.nodes, n)
+	}
+	t.index[p] = n
+
+	if !p.IsNode() {
+		return n
+	}
+	for _, child := range pages(p) {
+		if _, found := t.index[child]; found {
+			continue
+		}
+		n.Children = append(n.Children, t.add(n, child, pages))
+	}
+
+	return n
+}
+
+// Nodes returns all nodes below the root in depth-first order, so the tree
+// can be rendered without recursion using the Level of each node.
+func (t *NavTree) Nodes() []*NavNode {
+	return t.nodes
+}
+
+// Visible returns the nodes below the root in depth-first order that are
+// not inside a section collapsed when p is the current page.
+func (t *NavTree) Visible(p Page) []*NavNode {
+	var visible []*NavNode
+	var collapsed *NavNode
+	for _, n := range t.nodes {
+		if collapsed != nil {
+			if n.Level > collapsed.Level {
+				continue
+			}
+			collapsed = nil
+		}
+		visible = append(visible, n)
+		if n.IsCollapsed(p) {
+			collapsed = n
+		}
+	}
+	return visible
+}
+
+// Find returns the node of p, nil if p is not in the tree.
+func (t *NavTree) Find(p Page) *NavNode {
+	if p == nil {
+		return nil
+	}
+	return t.index[p]
+}
+
+// ActiveTrail returns the nodes from the root down to the node of p,
+// empty if p is not in the tree.
+func (t *NavTree) ActiveTrail(p Page) []*NavNode {
+	n := t.Find(p)
+	if n == nil {
+		return nil
+	}
+	trail := make([]*NavNode, n.Level+1)
+	for ; n != nil; n = n.Parent {
+		trail[n.Level] = n
+	}
+	return trail
+}
+
+// HasChildren returns whether the node has any children.
+func (n *NavNode) HasChildren() bool {
+	return len(n.Children) > 0
+}
+
+// IsActive returns whether the node is the one of p.
+func (n *NavNode) IsActive(p Page) bool {
+	return p != nil && n.Page == p
+}
+
+// InActiveTrail returns whether the node is the one of p or one of its
+// ancestors in the tree.
+func (n *NavNode) InActiveTrail(p Page) bool {
+	for c := n.tree.Find(p); c != nil; c = c.Parent {
+		if c == n {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCollapsed returns whether the node has children that are hidden when
+// p is the current page, i.e. it is not in the active trail of p.
+func (n *NavNode) IsCollapsed(p Page) bool {
+	return n.HasChildren() && !n.InActiveTrail(p)
+}
@@ -80,10 +80,22 @@ func (p *nopPage) AllTranslations() Pages {
 	return nil
 }
 
+func (p *nopPage) Canonical() string {
+	return ""
+}
+
+func (p *nopPage) Hreflangs() []Hreflang {
+	return nil
+}
+
 func (p *nopPage) LanguagePrefix() string {
 	return ""
 }
 
+func (p *nopPage) Alternates() Alternates {
+	return nil
+}
+
 func (p *nopPage) AlternativeOutputFormats() OutputFormats {
 	return nil
 }
@@ -100,10 +112,18 @@ func (p *nopPage) Content() (any, error) {
 	return "", nil
 }
 
+func (p *nopPage) ContentBlocks() map[string]template.HTML {
+	return nil
+}
+
 func (p *nopPage) ContentBaseName() string {
 	return ""
 }
 
+func (p *nopPage) Breadcrumbs() Breadcrumbs {
+	return nil
+}
+
 func (p *nopPage) CurrentSection() Page {
 	return nil
 }
@@ -116,6 +136,10 @@ func (p *nopPage) Date() (t time.Time) {
 	return
 }
 
+func (p *nopPage) DatePrecision() string {
+	return ""
+}
+
 func (p *nopPage) Description() string {
 	return ""
 }
@@ -374,6 +398,14 @@ func (p *nopPage) NextInSection() Page {
 	return nil
 }
 
+func (p *nopPage) PrevInCollection(pages Pages) Page {
+	return nil
+}
+
+func (p *nopPage) NextInCollection(pages Pages) Page {
+	return nil
+}
+
 func (p *nopPage) PrevPage() Page {
 	return nil
 }
@@ -386,10 +418,26 @@ func (p *nopPage) RawContent() string {
 	return ""
 }
 
+func (p *nopPage) RawContentSource() string {
+	return ""
+}
+
+func (p *nopPage) RawFrontMatter() RawFrontMatter {
+	return RawFrontMatter{}
+}
+
 func (p *nopPage) ReadingTime() int {
 	return 0
 }
 
+func (p *nopPage) Numbering() *Numbering {
+	return NewNumbering(DefaultNumberingConfig, "")
+}
+
+func (p *nopPage) NumberRef(ref string) (template.HTML, error) {
+	return "", nil
+}
+
 func (p *nopPage) Ref(argsm map[string]any) (string, error) {
 	return "", nil
 }
@@ -410,6 +458,18 @@ func (p *nopPage) RenderString(args ...any) (template.HTML, error) {
 	return "", nil
 }
 
+func (p *nopPage) RenderShortcode(name string, index ...int) (template.HTML, error) {
+	return "", nil
+}
+
+func (p *nopPage) RenderFragment(id string) (template.HTML, error) {
+	return "", nil
+}
+
+func (p *nopPage) Include(ref string) (template.HTML, error) {
+	return "", nil
+}
+
 func (p *nopPage) ResourceType() string {
 	return ""
 }
@@ -418,6 +478,10 @@ func (p *nopPage) Resources() resource.Resources {
 	return nil
 }
 
+func (p *nopPage) Gallery(options ...any) (*Gallery, error) {
+	return nil, nil
+}
+
 func (p *nopPage) Scratch() *maps.Scratch {
 	return nil
 }
@@ -426,6 +490,10 @@ func (p *nopPage) Store() *maps.Scratch {
 	return nil
 }
 
+func (p *nopPage) RenderScratch() *maps.Scratch {
+	return nil
+}
+
 func (p *nopPage) RelatedKeywords(cfg related.IndexConfig) ([]related.Keyword, error) {
 	return nil, nil
 }
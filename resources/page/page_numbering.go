@@ -0,0 +1,187 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// DefaultNumberingConfig holds the default numbering configuration.
+var DefaultNumberingConfig = NumberingConfig{
+	Scope: "page",
+	Labels: map[string]string{
+		"figure":   "Figure",
+		"table":    "Table",
+		"listing":  "Listing",
+		"equation": "Equation",
+	},
+}
+
+// NumberingConfig configures the numbering of figures, tables, listings
+// and equations.
+type NumberingConfig struct {
+	// The scope of the counters: "page" numbers from 1 in every page,
+	// "section" prefixes the numbers with the position of the page in its
+	// section, e.g. Figure 2.3.
+	Scope string
+
+	// The labels by kind, e.g. Figure for figure.
+	Labels map[string]string
+}
+
+// DecodeNumberingConfig decodes the numbering section in site config.
+func DecodeNumberingConfig(in any) (NumberingConfig, error) {
+	c := DefaultNumberingConfig
+	c.Labels = make(map[string]string)
+	for k, v := range DefaultNumberingConfig.Labels {
+		c.Labels[k] = v
+	}
+
+	if in == nil {
+		return c, nil
+	}
+
+	var labels map[string]string
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode numbering config: %w", err)
+	}
+	labels, c.Labels = c.Labels, make(map[string]string)
+	for k, v := range labels {
+		c.Labels[strings.ToLower(k)] = v
+	}
+
+	switch c.Scope {
+	case "page", "section":
+	default:
+		return c, fmt.Errorf("numbering.scope must be one of page or section, got %q", c.Scope)
+	}
+
+	return c, nil
+}
+
+// Number is the number of a figure, table, listing or equation.
+type Number struct {
+	// The kind, e.g. figure.
+	Kind string
+
+	// The ID of the numbered element, may be empty.
+	ID string
+
+	// The number within the page for its kind, starting at 1.
+	Number int
+
+	// The number as shown, e.g. 3, or 2.3 in section scope.
+	Text string
+
+	// The label and the number, e.g. Figure 3.
+	Label string
+}
+
+// Numbering assigns numbers to the figures, tables, listings and equations
+// in the content of a page, typically from shortcodes and render hooks.
+type Numbering struct {
+	cfg    NumberingConfig
+	prefix string
+
+	mu       sync.Mutex
+	counters map[string]int
+	ids      map[string]Number
+	numbers  []Number
+}
+
+// NewNumbering creates a new Numbering where all numbers are prefixed with
+// prefix, e.g. "2.".
+func NewNumbering(cfg NumberingConfig, prefix string) *Numbering {
+	return &Numbering{
+		cfg:      cfg,
+		prefix:   prefix,
+		counters: make(map[string]int),
+		ids:      make(map[string]Number),
+	}
+}
+
+// Next assigns the next number of kind, e.g. figure, to the element with
+// the given optional ID.
+func (n *Numbering) Next(kind string, id ...string) (Number, error) {
+	kind = strings.ToLower(kind)
+	if kind == "" {
+		return Number{}, fmt.Errorf("numbering: kind must be set")
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var nid string
+	if len(id) > 0 {
+		nid = id[0]
+	}
+	if nid != "" {
+		if _, found := n.ids[nid]; found {
+			return Number{}, fmt.Errorf("numbering: duplicate ID %q", nid)
+		}
+	}
+
+	n.counters[kind]++
+	num := Number{
+		Kind:   kind,
+		ID:     nid,
+		Number: n.counters[kind],
+	}
+	num.Text = n.prefix + strconv.Itoa(num.Number)
+	num.Label = n.label(kind) + " " + num.Text
+
+	if nid != "" {
+		n.ids[nid] = num
+	}
+	n.numbers = append(n.numbers, num)
+
+	return num, nil
+}
+
+// Get returns the number of the element with the given ID and whether
+// it was found.
+func (n *Numbering) Get(id string) (Number, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	num, found := n.ids[id]
+	return num, found
+}
+
+// Of returns the numbers of kind in order, e.g. for a list of figures.
+func (n *Numbering) Of(kind string) []Number {
+	kind = strings.ToLower(kind)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var numbers []Number
+	for _, num := range n.numbers {
+		if num.Kind == kind {
+			numbers = append(numbers, num)
+		}
+	}
+	return numbers
+}
+
+func (n *Numbering) label(kind string) string {
+	if l, found := n.cfg.Labels[kind]; found {
+		return l
+	}
+	return strings.ToUpper(kind[:1]) + kind[1:]
+}
@@ -65,6 +65,19 @@ func (o OutputFormat) RelPermalink() string {
 	return o.relPermalink
 }
 
+// Alternates is a list of alternate versions of a Page.
+type Alternates []Alternate
+
+// Alternate is a version of a Page in another output format or language.
+type Alternate struct {
+	// The output format, with Rel set to "alternate" for the translations.
+	OutputFormat
+
+	// The hreflang value of a translation: the languageCode of its language
+	// if set, else its Lang. Empty for the output formats of the Page itself.
+	Lang string
+}
+
 func NewOutputFormat(relPermalink, permalink string, isCanonical bool, f output.Format) OutputFormat {
 	isUserConfigured := true
 	for _, d := range output.DefaultFormats {
@@ -69,6 +69,10 @@ type TargetPathDescriptor struct {
 
 	// Some types cannot have uglyURLs, even if globally enabled, RSS being one example.
 	UglyURLs bool
+
+	// Whether to link to index files without a trailing slash, in addition
+	// to the NoTrailingSlash setting of the output format.
+	NoTrailingSlash bool
 }
 
 // TODO(bep) move this type.
@@ -140,7 +144,7 @@ func CreateTargetPaths(d TargetPathDescriptor) (tp TargetPaths) {
 	// the index base even when uglyURLs is enabled.
 	needsBase := true
 
-	isUgly := d.UglyURLs && !d.Type.NoUgly
+	isUgly := (d.UglyURLs || d.Type.Ugly) && !d.Type.NoUgly
 	baseNameSameAsType := d.BaseName != "" && d.BaseName == d.Type.BaseName
 
 	if d.ExpandedPermalink == "" && baseNameSameAsType {
@@ -320,6 +324,9 @@ func CreateTargetPaths(d TargetPathDescriptor) (tp TargetPaths) {
 	tp.SubResourceBaseTarget = filepath.FromSlash(pagePathDir)
 	tp.SubResourceBaseLink = linkDir
 	tp.Link = d.PathSpec.URLizeFilename(link)
+	if (d.NoTrailingSlash || d.Type.NoTrailingSlash) && tp.Link != slash {
+		tp.Link = strings.TrimSuffix(tp.Link, slash)
+	}
 	if tp.Link == "" {
 		tp.Link = slash
 	}
@@ -276,6 +276,64 @@ func TestPageTargetPathPrefix(t *testing.T) {
 	}
 }
 
+func TestPageTargetPathURLStyle(t *testing.T) {
+	pathSpec := newTestPathSpec()
+
+	uglyJSON := output.JSONFormat
+	uglyJSON.Ugly = true
+	noSlashHTML := output.HTMLFormat
+	noSlashHTML.NoTrailingSlash = true
+
+	tests := []struct {
+		name     string
+		d        TargetPathDescriptor
+		expected TargetPaths
+	}{
+		{
+			"Ugly format",
+			TargetPathDescriptor{Kind: KindPage, Dir: "/a", BaseName: "p1", Type: uglyJSON},
+			TargetPaths{TargetFilename: "/a/p1.json", SubResourceBaseTarget: "/a/p1", SubResourceBaseLink: "/a/p1", Link: "/a/p1.json"},
+		},
+		{
+			"Ugly format, NoUgly",
+			TargetPathDescriptor{Kind: KindSection, Sections: []string{"a"}, BaseName: "_index", Type: output.RSSFormat, UglyURLs: true},
+			TargetPaths{TargetFilename: "/a/index.xml", SubResourceBaseTarget: "/a", SubResourceBaseLink: "/a", Link: "/a/index.xml"},
+		},
+		{
+			"No trailing slash format",
+			TargetPathDescriptor{Kind: KindPage, Dir: "/a", BaseName: "p1", Type: noSlashHTML},
+			TargetPaths{TargetFilename: "/a/p1/index.html", SubResourceBaseTarget: "/a/p1", SubResourceBaseLink: "/a/p1", Link: "/a/p1"},
+		},
+		{
+			"No trailing slash page",
+			TargetPathDescriptor{Kind: KindSection, Sections: []string{"a"}, BaseName: "_index", Type: output.HTMLFormat, NoTrailingSlash: true},
+			TargetPaths{TargetFilename: "/a/index.html", SubResourceBaseTarget: "/a", SubResourceBaseLink: "/a", Link: "/a"},
+		},
+		{
+			"No trailing slash home",
+			TargetPathDescriptor{Kind: KindHome, BaseName: "_index", Type: output.HTMLFormat, NoTrailingSlash: true},
+			TargetPaths{TargetFilename: "/index.html", SubResourceBaseTarget: "", SubResourceBaseLink: "", Link: "/"},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name,
+			func(t *testing.T) {
+				test.d.PathSpec = pathSpec
+				test.d.Dir = filepath.FromSlash(test.d.Dir)
+				expected := test.expected
+				expected.TargetFilename = filepath.FromSlash(expected.TargetFilename)
+				expected.SubResourceBaseTarget = filepath.FromSlash(expected.SubResourceBaseTarget)
+
+				pagePath := CreateTargetPaths(test.d)
+
+				if pagePath != expected {
+					t.Fatalf("[%d] [%s] targetPath expected\n%#v, got:\n%#v", i, test.name, expected, pagePath)
+				}
+			})
+	}
+}
+
 func eqTargetPaths(p1, p2 TargetPaths) bool {
 	if p1.Link != p2.Link {
 		return false
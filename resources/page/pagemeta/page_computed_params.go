@@ -0,0 +1,224 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagemeta
+
+import (
+	"fmt"
+	"strings"
+	"text/template/parse"
+	"time"
+
+	"github.com/gohugoio/hugo/common/htime"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/spf13/cast"
+)
+
+// ComputedParam is a page param computed from a template expression
+// evaluated with the Page as context, e.g. in cascade:
+//
+//	[cascade]
+//	year = { expr = ".Date.Year", type = "int" }
+//
+// Computed params can only be set in the cascade in site config,
+// see ResolveComputedParams.
+type ComputedParam struct {
+	// The template expression, without the surrounding delimiters.
+	Expr string
+
+	// The type to convert the result to, one of string (default), int, float, bool or date.
+	Type string
+}
+
+// DecodeComputedParam returns the ComputedParam in v and true if v is a map
+// with an expr key and, optionally, a type key.
+func DecodeComputedParam(v any) (ComputedParam, bool) {
+	m, err := maps.ToStringMapE(v)
+	if err != nil || len(m) == 0 || len(m) > 2 {
+		return ComputedParam{}, false
+	}
+
+	var c ComputedParam
+	for k, vv := range m {
+		s, ok := vv.(string)
+		if !ok {
+			return ComputedParam{}, false
+		}
+		switch strings.ToLower(k) {
+		case "expr":
+			c.Expr = strings.TrimSpace(s)
+		case "type":
+			c.Type = strings.ToLower(s)
+		default:
+			return ComputedParam{}, false
+		}
+	}
+
+	if c.Expr == "" {
+		return ComputedParam{}, false
+	}
+	if c.Type == "" {
+		c.Type = SchemaTypeString
+	}
+
+	return c, true
+}
+
+// contentMethods are the Page methods depending on the rendered content,
+// which is not available when the computed params are evaluated.
+var contentMethods = map[string]bool{
+	"Content":               true,
+	"ContentWithoutSummary": true,
+	"Fragments":             true,
+	"FuzzyWordCount":        true,
+	"Len":                   true,
+	"Plain":                 true,
+	"PlainWords":            true,
+	"ReadingTime":           true,
+	"Summary":               true,
+	"TableOfContents":       true,
+	"Truncated":             true,
+	"WordCount":             true,
+}
+
+// ResolveComputedParams replaces the expression maps in m, a cascade from
+// site config, with their ComputedParam, including those in a sitemap map.
+// Only these are evaluated, a map with an expr key in the front matter of
+// a content file is a regular param.
+func ResolveComputedParams(m map[string]any) error {
+	for k, v := range m {
+		if strings.EqualFold(k, "sitemap") {
+			sm, err := maps.ToStringMapE(v)
+			if err != nil {
+				continue
+			}
+			// Don't modify the config.
+			sm2 := make(map[string]any)
+			for kk, vv := range sm {
+				sm2[kk] = vv
+			}
+			if err := ResolveComputedParams(sm2); err != nil {
+				return err
+			}
+			m[k] = sm2
+			continue
+		}
+		cp, ok := DecodeComputedParam(v)
+		if !ok {
+			continue
+		}
+		if err := cp.validate(); err != nil {
+			return fmt.Errorf("computed param %q: %w", k, err)
+		}
+		m[k] = cp
+	}
+	return nil
+}
+
+// validate checks that the expression parses and does not use any of the
+// content dependent Page methods.
+func (c ComputedParam) validate() error {
+	t := parse.New("expr")
+	t.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+	if _, err := t.Parse(c.Template(), "", "", trees); err != nil {
+		return err
+	}
+
+	var found string
+	var walk func(n parse.Node)
+	checkIdents := func(idents []string) {
+		for _, ident := range idents {
+			if found == "" && contentMethods[ident] {
+				found = ident
+			}
+		}
+	}
+	walk = func(n parse.Node) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, nn := range n.Nodes {
+				walk(nn)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.ChainNode:
+			walk(n.Node)
+			checkIdents(n.Field)
+		case *parse.FieldNode:
+			checkIdents(n.Ident)
+		case *parse.VariableNode:
+			checkIdents(n.Ident[1:])
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		}
+	}
+
+	for _, tree := range trees {
+		walk(tree.Root)
+	}
+
+	if found != "" {
+		return fmt.Errorf(".%s depends on the rendered content, which is not available when computed params are evaluated", found)
+	}
+
+	return nil
+}
+
+// Template returns the template source for the expression.
+func (c ComputedParam) Template() string {
+	return "{{ " + c.Expr + " }}"
+}
+
+// Convert converts s, the result of executing the expression, to the configured type.
+func (c ComputedParam) Convert(s string) (any, error) {
+	s = strings.TrimSpace(s)
+	switch c.Type {
+	case SchemaTypeString, SchemaTypeAny:
+		return s, nil
+	case SchemaTypeInt:
+		return cast.ToIntE(s)
+	case SchemaTypeFloat:
+		return cast.ToFloat64E(s)
+	case SchemaTypeBool:
+		return cast.ToBoolE(s)
+	case SchemaTypeDate:
+		return htime.ToTimeInDefaultLocationE(s, time.UTC)
+	default:
+		return nil, fmt.Errorf("unsupported type %q for computed param", c.Type)
+	}
+}
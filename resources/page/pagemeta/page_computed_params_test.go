@@ -0,0 +1,71 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagemeta
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeComputedParam(t *testing.T) {
+	c := qt.New(t)
+
+	cp, ok := DecodeComputedParam(map[string]any{"expr": " .Date.Year ", "type": "Int"})
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(cp, qt.DeepEquals, ComputedParam{Expr: ".Date.Year", Type: SchemaTypeInt})
+	c.Assert(cp.Template(), qt.Equals, "{{ .Date.Year }}")
+	v, err := cp.Convert("2022\n")
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, 2022)
+
+	cp, ok = DecodeComputedParam(map[string]any{"expr": ".Title"})
+	c.Assert(ok, qt.IsTrue)
+	v, err = cp.Convert("My Title")
+	c.Assert(err, qt.IsNil)
+	c.Assert(v, qt.Equals, "My Title")
+
+	_, ok = DecodeComputedParam(map[string]any{"expr": ".Title", "name": "foo"})
+	c.Assert(ok, qt.IsFalse)
+	_, ok = DecodeComputedParam(map[string]any{"type": "int"})
+	c.Assert(ok, qt.IsFalse)
+	_, ok = DecodeComputedParam("expr")
+	c.Assert(ok, qt.IsFalse)
+
+	cp, _ = DecodeComputedParam(map[string]any{"expr": ".Draft", "type": "bool"})
+	_, err = cp.Convert("maybe")
+	c.Assert(err, qt.IsNotNil)
+}
+
+func TestResolveComputedParams(t *testing.T) {
+	c := qt.New(t)
+
+	m := map[string]any{
+		"year":    map[string]any{"expr": ".Date.Year", "type": "int"},
+		"author":  map[string]any{"name": "Jo"},
+		"sitemap": map[string]any{"priority": map[string]any{"expr": "0.5", "type": "float"}, "changefreq": "weekly"},
+	}
+	c.Assert(ResolveComputedParams(m), qt.IsNil)
+	c.Assert(m["year"], qt.DeepEquals, ComputedParam{Expr: ".Date.Year", Type: SchemaTypeInt})
+	c.Assert(m["author"], qt.DeepEquals, map[string]any{"name": "Jo"})
+	c.Assert(m["sitemap"], qt.DeepEquals, map[string]any{"priority": ComputedParam{Expr: "0.5", Type: SchemaTypeFloat}, "changefreq": "weekly"})
+
+	for _, expr := range []string{".WordCount", "div .Page.ReadingTime 2", "with .Parent }}{{ len .Plain }}{{ end", "$.Summary"} {
+		err := ResolveComputedParams(map[string]any{"v": map[string]any{"expr": expr}})
+		c.Assert(err, qt.ErrorMatches, `computed param "v": .* depends on the rendered content.*`, qt.Commentf(expr))
+	}
+
+	c.Assert(ResolveComputedParams(map[string]any{"v": map[string]any{"expr": ".Title | len"}}), qt.IsNil)
+	c.Assert(ResolveComputedParams(map[string]any{"v": map[string]any{"expr": "if"}}), qt.IsNotNil)
+}
@@ -114,6 +114,11 @@ func (f FrontMatterHandler) HandleDates(d *FrontMatterDescriptor) error {
 	return nil
 }
 
+// Schemas returns the front matter schemas configured in frontmatter.schemas.
+func (f FrontMatterHandler) Schemas() FrontMatterSchemas {
+	return f.fmConfig.schemas
+}
+
 // IsDateKey returns whether the given front matter key is considered a date by the current
 // configuration.
 func (f FrontMatterHandler) IsDateKey(key string) bool {
@@ -164,6 +169,11 @@ type frontmatterConfig struct {
 	lastmod     []string
 	publishDate []string
 	expiryDate  []string
+
+	// Additional Go layouts to parse the dates with, e.g. "January 2006".
+	dateFormats []string
+
+	schemas FrontMatterSchemas
 }
 
 const (
@@ -182,6 +192,12 @@ const (
 
 	// Gets date from Git
 	fmGitAuthorDate = ":git"
+
+	// Not a date, but the front matter schemas per content type.
+	fmSchemas = "schemas"
+
+	// Not a date, but the additional layouts to parse the dates with.
+	fmDateFormats = "dateformats"
 )
 
 // This is the config you get when doing nothing.
@@ -211,6 +227,14 @@ func newFrontmatterConfig(cfg config.Provider) (frontmatterConfig, error) {
 				c.lastmod = toLowerSlice(v)
 			case fmExpiryDate:
 				c.expiryDate = toLowerSlice(v)
+			case fmDateFormats:
+				c.dateFormats = cast.ToStringSlice(v)
+			case fmSchemas:
+				schemas, err := DecodeFrontMatterSchemas(v)
+				if err != nil {
+					return c, err
+				}
+				c.schemas = schemas
 			}
 		}
 	}
@@ -301,15 +325,16 @@ func (f *FrontMatterHandler) createHandlers() error {
 	var err error
 
 	if f.dateHandler, err = f.createDateHandler(f.fmConfig.date,
-		func(d *FrontMatterDescriptor, t time.Time) {
+		func(d *FrontMatterDescriptor, t time.Time, precision string) {
 			d.Dates.FDate = t
+			d.Dates.FDatePrecision = precision
 			setParamIfNotSet(fmDate, t, d)
 		}); err != nil {
 		return err
 	}
 
 	if f.lastModHandler, err = f.createDateHandler(f.fmConfig.lastmod,
-		func(d *FrontMatterDescriptor, t time.Time) {
+		func(d *FrontMatterDescriptor, t time.Time, precision string) {
 			setParamIfNotSet(fmLastmod, t, d)
 			d.Dates.FLastmod = t
 		}); err != nil {
@@ -317,7 +342,7 @@ func (f *FrontMatterHandler) createHandlers() error {
 	}
 
 	if f.publishDateHandler, err = f.createDateHandler(f.fmConfig.publishDate,
-		func(d *FrontMatterDescriptor, t time.Time) {
+		func(d *FrontMatterDescriptor, t time.Time, precision string) {
 			setParamIfNotSet(fmPubDate, t, d)
 			d.Dates.FPublishDate = t
 		}); err != nil {
@@ -325,7 +350,7 @@ func (f *FrontMatterHandler) createHandlers() error {
 	}
 
 	if f.expiryDateHandler, err = f.createDateHandler(f.fmConfig.expiryDate,
-		func(d *FrontMatterDescriptor, t time.Time) {
+		func(d *FrontMatterDescriptor, t time.Time, precision string) {
 			setParamIfNotSet(fmExpiryDate, t, d)
 			d.Dates.FExpiryDate = t
 		}); err != nil {
@@ -342,7 +367,7 @@ func setParamIfNotSet(key string, value any, d *FrontMatterDescriptor) {
 	d.Params[key] = value
 }
 
-func (f FrontMatterHandler) createDateHandler(identifiers []string, setter func(d *FrontMatterDescriptor, t time.Time)) (frontMatterFieldHandler, error) {
+func (f FrontMatterHandler) createDateHandler(identifiers []string, setter func(d *FrontMatterDescriptor, t time.Time, precision string)) (frontMatterFieldHandler, error) {
 	var h *frontmatterFieldHandlers
 	var handlers []frontMatterFieldHandler
 
@@ -355,7 +380,7 @@ func (f FrontMatterHandler) createDateHandler(identifiers []string, setter func(
 		case fmGitAuthorDate:
 			handlers = append(handlers, h.newDateGitAuthorDateHandler(setter))
 		default:
-			handlers = append(handlers, h.newDateFieldHandler(identifier, setter))
+			handlers = append(handlers, h.newDateFieldHandler(identifier, f.fmConfig.dateFormats, setter))
 		}
 	}
 
@@ -364,7 +389,7 @@ func (f FrontMatterHandler) createDateHandler(identifiers []string, setter func(
 
 type frontmatterFieldHandlers int
 
-func (f *frontmatterFieldHandlers) newDateFieldHandler(key string, setter func(d *FrontMatterDescriptor, t time.Time)) frontMatterFieldHandler {
+func (f *frontmatterFieldHandlers) newDateFieldHandler(key string, layouts []string, setter func(d *FrontMatterDescriptor, t time.Time, precision string)) frontMatterFieldHandler {
 	return func(d *FrontMatterDescriptor) (bool, error) {
 		v, found := d.Frontmatter[key]
 
@@ -372,14 +397,14 @@ func (f *frontmatterFieldHandlers) newDateFieldHandler(key string, setter func(d
 			return false, nil
 		}
 
-		date, err := htime.ToTimeInDefaultLocationE(v, d.Location)
+		date, precision, err := htime.ToTimeWithPrecisionE(v, d.Location, layouts)
 		if err != nil {
 			return false, nil
 		}
 
 		// We map several date keys to one, so, for example,
 		// "expirydate", "unpublishdate" will all set .ExpiryDate (first found).
-		setter(d, date)
+		setter(d, date, precision)
 
 		// This is the params key as set in front matter.
 		d.Params[key] = date
@@ -388,14 +413,14 @@ func (f *frontmatterFieldHandlers) newDateFieldHandler(key string, setter func(d
 	}
 }
 
-func (f *frontmatterFieldHandlers) newDateFilenameHandler(setter func(d *FrontMatterDescriptor, t time.Time)) frontMatterFieldHandler {
+func (f *frontmatterFieldHandlers) newDateFilenameHandler(setter func(d *FrontMatterDescriptor, t time.Time, precision string)) frontMatterFieldHandler {
 	return func(d *FrontMatterDescriptor) (bool, error) {
 		date, slug := dateAndSlugFromBaseFilename(d.Location, d.BaseFilename)
 		if date.IsZero() {
 			return false, nil
 		}
 
-		setter(d, date)
+		setter(d, date, htime.PrecisionDay)
 
 		if _, found := d.Frontmatter["slug"]; !found {
 			// Use slug from filename
@@ -406,22 +431,22 @@ func (f *frontmatterFieldHandlers) newDateFilenameHandler(setter func(d *FrontMa
 	}
 }
 
-func (f *frontmatterFieldHandlers) newDateModTimeHandler(setter func(d *FrontMatterDescriptor, t time.Time)) frontMatterFieldHandler {
+func (f *frontmatterFieldHandlers) newDateModTimeHandler(setter func(d *FrontMatterDescriptor, t time.Time, precision string)) frontMatterFieldHandler {
 	return func(d *FrontMatterDescriptor) (bool, error) {
 		if d.ModTime.IsZero() {
 			return false, nil
 		}
-		setter(d, d.ModTime)
+		setter(d, d.ModTime, htime.PrecisionTime)
 		return true, nil
 	}
 }
 
-func (f *frontmatterFieldHandlers) newDateGitAuthorDateHandler(setter func(d *FrontMatterDescriptor, t time.Time)) frontMatterFieldHandler {
+func (f *frontmatterFieldHandlers) newDateGitAuthorDateHandler(setter func(d *FrontMatterDescriptor, t time.Time, precision string)) frontMatterFieldHandler {
 	return func(d *FrontMatterDescriptor) (bool, error) {
 		if d.GitAuthorDate.IsZero() {
 			return false, nil
 		}
-		setter(d, d.GitAuthorDate)
+		setter(d, d.GitAuthorDate, htime.PrecisionTime)
 		return true, nil
 	}
 }
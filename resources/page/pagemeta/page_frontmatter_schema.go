@@ -0,0 +1,271 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagemeta
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/common/htime"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+// The field types supported in a front matter schema.
+const (
+	SchemaTypeAny    = "any"
+	SchemaTypeString = "string"
+	SchemaTypeInt    = "int"
+	SchemaTypeFloat  = "float"
+	SchemaTypeBool   = "bool"
+	SchemaTypeDate   = "date"
+	SchemaTypeSlice  = "slice"
+	SchemaTypeMap    = "map"
+)
+
+var schemaTypes = map[string]bool{
+	SchemaTypeAny:    true,
+	SchemaTypeString: true,
+	SchemaTypeInt:    true,
+	SchemaTypeFloat:  true,
+	SchemaTypeBool:   true,
+	SchemaTypeDate:   true,
+	SchemaTypeSlice:  true,
+	SchemaTypeMap:    true,
+}
+
+// FrontMatterSchemas holds the front matter schemas keyed by content type
+// (which defaults to the section name).
+type FrontMatterSchemas map[string]FrontMatterSchema
+
+// FrontMatterSchema maps a front matter key to its field definition.
+type FrontMatterSchema map[string]FrontMatterField
+
+// FrontMatterField describes the constraints on a single front matter field.
+type FrontMatterField struct {
+	// The value type, one of any (default), string, int, float, bool, date, slice or map.
+	Type string
+
+	// Whether the field must be set.
+	Required bool
+
+	// If set, the value (or, for slices, every element of it) must be one of these.
+	Values []any
+}
+
+// FrontMatterViolation describes a front matter value not matching its schema.
+type FrontMatterViolation struct {
+	// The lower case front matter key.
+	Key string
+
+	// Whether the key was missing from front matter.
+	Missing bool
+
+	Message string
+}
+
+func (v FrontMatterViolation) Error() string {
+	return v.Message
+}
+
+// DecodeFrontMatterSchemas decodes the schemas defined in frontmatter.schemas, e.g.:
+//
+//	[frontmatter.schemas.posts]
+//	title = { type = "string", required = true }
+//	category = { values = ["news", "releases"] }
+func DecodeFrontMatterSchemas(in any) (FrontMatterSchemas, error) {
+	m, err := maps.ToStringMapE(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode front matter schemas: %w", err)
+	}
+
+	schemas := make(FrontMatterSchemas)
+	for typ, v := range m {
+		schema, err := DecodeFrontMatterSchema(typ, v)
+		if err != nil {
+			return nil, err
+		}
+		schemas[strings.ToLower(typ)] = schema
+	}
+
+	return schemas, nil
+}
+
+// DecodeFrontMatterSchema decodes the schema for the content type typ,
+// a map of front matter keys to their field definition.
+func DecodeFrontMatterSchema(typ string, in any) (FrontMatterSchema, error) {
+	fields, err := maps.ToStringMapE(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode front matter schema for %q: %w", typ, err)
+	}
+	schema := make(FrontMatterSchema)
+	for key, vv := range fields {
+		var field FrontMatterField
+		if err := mapstructure.WeakDecode(vv, &field); err != nil {
+			return nil, fmt.Errorf("failed to decode front matter schema field %q in %q: %w", key, typ, err)
+		}
+		field.Type = strings.ToLower(field.Type)
+		if field.Type == "" {
+			field.Type = SchemaTypeAny
+		}
+		if !schemaTypes[field.Type] {
+			return nil, fmt.Errorf("front matter schema field %q in %q has invalid type %q", key, typ, field.Type)
+		}
+		schema[strings.ToLower(key)] = field
+	}
+
+	return schema, nil
+}
+
+// Merge returns a new set of schemas with the schemas in other added to s.
+// For a content type in both, the fields in s win.
+func (s FrontMatterSchemas) Merge(other FrontMatterSchemas) FrontMatterSchemas {
+	merged := make(FrontMatterSchemas)
+	for typ, schema := range other {
+		merged[typ] = schema
+	}
+	for typ, schema := range s {
+		if otherSchema, found := merged[typ]; found {
+			m := make(FrontMatterSchema)
+			for k, v := range otherSchema {
+				m[k] = v
+			}
+			for k, v := range schema {
+				m[k] = v
+			}
+			schema = m
+		}
+		merged[typ] = schema
+	}
+	return merged
+}
+
+// Validate validates frontmatter, which must have lower case keys, against
+// the schema. The violations are sorted by key.
+func (s FrontMatterSchema) Validate(frontmatter map[string]any) []FrontMatterViolation {
+	var violations []FrontMatterViolation
+
+	for key, field := range s {
+		v, found := frontmatter[key]
+		if !found || v == nil {
+			if field.Required {
+				violations = append(violations, FrontMatterViolation{Key: key, Missing: true, Message: fmt.Sprintf("front matter field %q is required", key)})
+			}
+			continue
+		}
+		if err := field.validateValue(v); err != nil {
+			violations = append(violations, FrontMatterViolation{Key: key, Message: fmt.Sprintf("front matter field %q: %s", key, err)})
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Key < violations[j].Key
+	})
+
+	return violations
+}
+
+func (f FrontMatterField) validateValue(v any) error {
+	if !schemaTypeMatches(f.Type, v) {
+		return fmt.Errorf("expected a value of type %s, got %T", f.Type, v)
+	}
+
+	if len(f.Values) == 0 {
+		return nil
+	}
+
+	if f.Type == SchemaTypeSlice || isSlice(v) {
+		for _, vv := range cast.ToSlice(v) {
+			if !f.isAllowed(vv) {
+				return fmt.Errorf("value %q is not one of %q", cast.ToString(vv), f.allowedStrings())
+			}
+		}
+		return nil
+	}
+
+	if !f.isAllowed(v) {
+		return fmt.Errorf("value %q is not one of %q", cast.ToString(v), f.allowedStrings())
+	}
+
+	return nil
+}
+
+func (f FrontMatterField) isAllowed(v any) bool {
+	s := cast.ToString(v)
+	for _, allowed := range f.Values {
+		if cast.ToString(allowed) == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (f FrontMatterField) allowedStrings() []string {
+	s := make([]string, len(f.Values))
+	for i, v := range f.Values {
+		s[i] = cast.ToString(v)
+	}
+	return s
+}
+
+func schemaTypeMatches(typ string, v any) bool {
+	switch typ {
+	case SchemaTypeString:
+		_, ok := v.(string)
+		return ok
+	case SchemaTypeInt:
+		switch vv := v.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case float64:
+			// E.g. JSON.
+			return vv == math.Trunc(vv)
+		}
+		return false
+	case SchemaTypeFloat:
+		switch v.(type) {
+		case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		}
+		return false
+	case SchemaTypeBool:
+		_, ok := v.(bool)
+		return ok
+	case SchemaTypeDate:
+		if _, ok := v.(time.Time); ok {
+			return true
+		}
+		if s, ok := v.(string); ok {
+			_, err := htime.ToTimeInDefaultLocationE(s, time.UTC)
+			return err == nil
+		}
+		return false
+	case SchemaTypeSlice:
+		return isSlice(v)
+	case SchemaTypeMap:
+		_, err := maps.ToStringMapE(v)
+		return err == nil
+	}
+	return true
+}
+
+func isSlice(v any) bool {
+	k := reflect.ValueOf(v).Kind()
+	return k == reflect.Slice || k == reflect.Array
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagemeta
+
+import (
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodeFrontMatterSchemas(t *testing.T) {
+	c := qt.New(t)
+
+	schemas, err := DecodeFrontMatterSchemas(map[string]any{
+		"Posts": map[string]any{
+			"Title": map[string]any{"type": "String", "required": true},
+			"tags":  map[string]any{"values": []any{"a", "b"}},
+		},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(schemas["posts"]["title"], qt.DeepEquals, FrontMatterField{Type: SchemaTypeString, Required: true})
+	c.Assert(schemas["posts"]["tags"].Type, qt.Equals, SchemaTypeAny)
+
+	_, err = DecodeFrontMatterSchemas(map[string]any{
+		"posts": map[string]any{
+			"title": map[string]any{"type": "text"},
+		},
+	})
+	c.Assert(err, qt.ErrorMatches, `.*invalid type "text"`)
+}
+
+func TestFrontMatterSchemaValidate(t *testing.T) {
+	c := qt.New(t)
+
+	schema := FrontMatterSchema{
+		"title":   {Type: SchemaTypeString, Required: true},
+		"weight":  {Type: SchemaTypeInt},
+		"rating":  {Type: SchemaTypeFloat},
+		"draft":   {Type: SchemaTypeBool},
+		"date":    {Type: SchemaTypeDate},
+		"tags":    {Type: SchemaTypeSlice, Values: []any{"a", "b"}},
+		"author":  {Type: SchemaTypeMap},
+		"section": {Values: []any{"news", 32}},
+	}
+
+	c.Assert(schema.Validate(map[string]any{
+		"title":   "Title",
+		"weight":  float64(3),
+		"rating":  3,
+		"draft":   true,
+		"date":    "2022-01-03",
+		"tags":    []any{"a", "b"},
+		"author":  map[string]any{"name": "Joe"},
+		"section": 32,
+	}), qt.HasLen, 0)
+
+	c.Assert(schema.Validate(map[string]any{
+		"title": "Title",
+		"date":  time.Now(),
+	}), qt.HasLen, 0)
+
+	violations := schema.Validate(map[string]any{
+		"weight":  3.5,
+		"draft":   "yes",
+		"date":    "not a date",
+		"tags":    []any{"a", "c"},
+		"author":  "Joe",
+		"section": "blog",
+	})
+
+	var keys []string
+	for _, v := range violations {
+		keys = append(keys, v.Key)
+	}
+	c.Assert(keys, qt.DeepEquals, []string{"author", "date", "draft", "section", "tags", "title", "weight"})
+	c.Assert(violations[5].Missing, qt.IsTrue)
+	c.Assert(violations[4].Error(), qt.Equals, `front matter field "tags": value "c" is not one of ["a" "b"]`)
+}
+
+func TestFrontMatterSchemasMerge(t *testing.T) {
+	c := qt.New(t)
+
+	config := FrontMatterSchemas{
+		"posts": {"rating": {Type: SchemaTypeFloat}},
+	}
+	archetypes := FrontMatterSchemas{
+		"posts": {"rating": {Type: SchemaTypeInt}, "title": {Type: SchemaTypeString, Required: true}},
+		"docs":  {"weight": {Type: SchemaTypeInt}},
+	}
+
+	merged := config.Merge(archetypes)
+	c.Assert(merged, qt.DeepEquals, FrontMatterSchemas{
+		"posts": {"rating": {Type: SchemaTypeFloat}, "title": {Type: SchemaTypeString, Required: true}},
+		"docs":  {"weight": {Type: SchemaTypeInt}},
+	})
+	c.Assert(archetypes["posts"]["rating"].Type, qt.Equals, SchemaTypeInt)
+}
@@ -248,10 +248,44 @@ func TestFrontMatterDateFieldHandler(t *testing.T) {
 	fd := newTestFd()
 	d, _ := time.Parse("2006-01-02", "2018-02-01")
 	fd.Frontmatter["date"] = d
-	h := handlers.newDateFieldHandler("date", func(d *FrontMatterDescriptor, t time.Time) { d.Dates.FDate = t })
+	h := handlers.newDateFieldHandler("date", nil, func(d *FrontMatterDescriptor, t time.Time, precision string) { d.Dates.FDate = t })
 
 	handled, err := h(fd)
 	c.Assert(handled, qt.Equals, true)
 	c.Assert(err, qt.IsNil)
 	c.Assert(fd.Dates.FDate, qt.Equals, d)
 }
+
+func TestFrontMatterDatePrecision(t *testing.T) {
+	t.Parallel()
+
+	c := qt.New(t)
+
+	cfg := config.New()
+	cfg.Set("frontmatter", map[string]any{
+		"dateFormats": []string{"02/01/2006"},
+	})
+
+	handler, err := NewFrontmatterHandler(nil, cfg)
+	c.Assert(err, qt.IsNil)
+
+	for _, test := range []struct {
+		in        any
+		expect    string
+		precision string
+	}{
+		{2024, "2024-01-01", "year"},
+		{"2024", "2024-01-01", "year"},
+		{"2024-05", "2024-05-01", "month"},
+		{"May 2024", "2024-05-01", "month"},
+		{"17/05/2024", "2024-05-17", "day"},
+		{"2024-05-17", "2024-05-17", "day"},
+		{"2024-05-17T10:00:00Z", "2024-05-17", "time"},
+	} {
+		d := newTestFd()
+		d.Frontmatter["date"] = test.in
+		c.Assert(handler.HandleDates(d), qt.IsNil)
+		c.Assert(d.Dates.FDate.Format("2006-01-02"), qt.Equals, test.expect, qt.Commentf("%v", test.in))
+		c.Assert(d.Dates.DatePrecision(), qt.Equals, test.precision, qt.Commentf("%v", test.in))
+	}
+}
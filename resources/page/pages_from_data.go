@@ -0,0 +1,219 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+// PagesFromData configures the pages created from the rows in a data file,
+// one page per row, e.g.:
+//
+//	[[pagesFromData]]
+//	data = "books"
+//	section = "books"
+//	name = "isbn"
+//	permalink = "/books/:year/:slug/"
+//	content = "description"
+//	[pagesFromData.params]
+//	type = "book"
+type PagesFromData struct {
+	// The data key, e.g. books for data/books.json, dot separated for
+	// nested keys. The value must be a list of maps or a map of maps.
+	Data string
+
+	// The section to create the pages in, relative to the content dir.
+	// Defaults to the last part of Data.
+	Section string
+
+	// The row key to create the file name of each page from, defaulting
+	// to slug, then title, then the key or the index of the row.
+	Name string
+
+	// The permalink pattern of the pages where :key is replaced with the
+	// URLized value of key in the row and :slug with the file name.
+	Permalink string
+
+	// The row key with the Markdown content of the page.
+	Content string
+
+	// The language of the pages, defaulting to defaultContentLanguage.
+	Lang string
+
+	// The default params, the values in the row take precedence.
+	Params maps.Params
+}
+
+// DataPage is a page created from a data row.
+type DataPage struct {
+	// The path relative to the content dir, e.g. books/the-hobbit.md.
+	Path string
+
+	// The front matter and the Markdown content.
+	FrontMatter map[string]any
+	Content     string
+}
+
+// DecodePagesFromData decodes the pagesFromData section in site config.
+func DecodePagesFromData(in any) ([]PagesFromData, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	ms, err := maps.ToSliceStringMap(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode pagesFromData config: %w", err)
+	}
+
+	var c []PagesFromData
+	for i, m := range ms {
+		var pc PagesFromData
+		if err := mapstructure.WeakDecode(m, &pc); err != nil {
+			return nil, fmt.Errorf("failed to decode pagesFromData config: %w", err)
+		}
+		if pc.Data == "" {
+			return nil, fmt.Errorf("pagesFromData %d: data is required", i+1)
+		}
+		if pc.Section == "" {
+			parts := strings.FieldsFunc(pc.Data, isDataKeySeparator)
+			pc.Section = parts[len(parts)-1]
+		}
+		pc.Section = strings.Trim(path.Clean("/"+pc.Section), "/")
+		if pc.Name == "" {
+			pc.Name = "slug"
+		}
+		maps.PrepareParams(pc.Params)
+		c = append(c, pc)
+	}
+
+	return c, nil
+}
+
+func isDataKeySeparator(r rune) bool {
+	return r == '.' || r == '/'
+}
+
+// Lookup returns the configured value in data.
+func (c PagesFromData) Lookup(data map[string]any) any {
+	var v any = data
+	for _, key := range strings.FieldsFunc(c.Data, isDataKeySeparator) {
+		m, err := maps.ToStringMapE(v)
+		if err != nil {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+var pagesFromDataPermalinkRe = regexp.MustCompile(`:(\w+)`)
+
+// Pages creates the pages from the rows in rows, the value returned by Lookup.
+// urlize is used to create the file names and the permalinks.
+func (c PagesFromData) Pages(rows any, urlize func(string) string) ([]DataPage, error) {
+	if rows == nil {
+		return nil, fmt.Errorf("pagesFromData: data %q not found", c.Data)
+	}
+
+	type row struct {
+		key string
+		m   map[string]any
+	}
+
+	var all []row
+	if l, err := maps.ToSliceStringMap(rows); err == nil {
+		for i, m := range l {
+			all = append(all, row{key: strconv.Itoa(i + 1), m: m})
+		}
+	} else {
+		m, err := maps.ToStringMapE(rows)
+		if err != nil {
+			return nil, fmt.Errorf("pagesFromData: data %q must be a list or a map of maps", c.Data)
+		}
+		for k, v := range m {
+			vm, err := maps.ToStringMapE(v)
+			if err != nil {
+				return nil, fmt.Errorf("pagesFromData: row %q in data %q is not a map", k, c.Data)
+			}
+			all = append(all, row{key: k, m: vm})
+		}
+		sort.Slice(all, func(i, j int) bool { return all[i].key < all[j].key })
+	}
+
+	pages := make([]DataPage, 0, len(all))
+	seen := make(map[string]bool)
+
+	for _, r := range all {
+		// The rows are shared with .Site.Data, so only lower case the
+		// top level keys here.
+		fm := make(map[string]any)
+		for k, v := range c.Params {
+			fm[k] = v
+		}
+		for k, v := range r.m {
+			fm[strings.ToLower(k)] = v
+		}
+
+		var content string
+		if c.Content != "" {
+			key := strings.ToLower(c.Content)
+			content = cast.ToString(fm[key])
+			delete(fm, key)
+		}
+
+		var name string
+		for _, key := range []string{strings.ToLower(c.Name), "title"} {
+			if name = urlize(cast.ToString(fm[key])); name != "" {
+				break
+			}
+		}
+		if name == "" {
+			name = urlize(r.key)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("pagesFromData: duplicate page name %q in data %q", name, c.Data)
+		}
+		seen[name] = true
+
+		if _, found := fm["url"]; !found && c.Permalink != "" {
+			fm["url"] = pagesFromDataPermalinkRe.ReplaceAllStringFunc(c.Permalink, func(s string) string {
+				key := strings.ToLower(s[1:])
+				if v, found := fm[key]; found {
+					return urlize(cast.ToString(v))
+				}
+				if key == "slug" {
+					return name
+				}
+				return s
+			})
+		}
+
+		pages = append(pages, DataPage{
+			Path:        path.Join(c.Section, name+".md"),
+			FrontMatter: fm,
+			Content:     content,
+		})
+	}
+
+	return pages, nil
+}
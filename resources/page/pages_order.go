@@ -0,0 +1,108 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/mitchellh/mapstructure"
+)
+
+// The orderings supported by PagesOrder.
+const (
+	PagesOrderDefault = ""
+	PagesOrderWeight  = "weight"
+	PagesOrderDate    = "date"
+	PagesOrderTitle   = "title"
+	PagesOrderParam   = "param"
+)
+
+const pagesOrderParamPrefix = PagesOrderParam + ":"
+
+// PagesOrder configures the order of pages, e.g. when navigating with
+// NextInSection and PrevInSection. It can be set as a string, e.g. "date"
+// or "param:series.part", or as a map with the keys by, param and reverse.
+type PagesOrder struct {
+	// One of weight, date, title or param. Empty means the default sort,
+	// which is the same as weight.
+	By string
+
+	// The page param to sort by when By is param.
+	Param string
+
+	// Whether to reverse the order.
+	Reverse bool
+}
+
+// DecodePagesOrder decodes in, a string or a map, into a PagesOrder.
+func DecodePagesOrder(in any) (PagesOrder, error) {
+	var o PagesOrder
+	if in == nil {
+		return o, nil
+	}
+
+	if s, ok := in.(string); ok {
+		if strings.HasPrefix(s, pagesOrderParamPrefix) {
+			o.By = PagesOrderParam
+			o.Param = strings.TrimPrefix(s, pagesOrderParamPrefix)
+		} else {
+			o.By = s
+		}
+	} else {
+		m, err := maps.ToStringMapE(in)
+		if err != nil {
+			return o, fmt.Errorf("failed to decode pages order: %w", err)
+		}
+		if err := mapstructure.WeakDecode(m, &o); err != nil {
+			return o, fmt.Errorf("failed to decode pages order: %w", err)
+		}
+	}
+
+	o.By = strings.ToLower(strings.TrimSpace(o.By))
+
+	switch o.By {
+	case PagesOrderDefault, PagesOrderWeight, PagesOrderDate, PagesOrderTitle:
+	case PagesOrderParam:
+		if o.Param == "" {
+			return o, fmt.Errorf("pages order %q: param must be set", o.By)
+		}
+	default:
+		return o, fmt.Errorf("invalid pages order %q, must be one of weight, date, title or param", o.By)
+	}
+
+	return o, nil
+}
+
+// Sort returns a copy of pages sorted in this order.
+func (o PagesOrder) Sort(pages Pages) Pages {
+	var sorted Pages
+	switch o.By {
+	case PagesOrderDate:
+		sorted = pages.ByDate()
+	case PagesOrderTitle:
+		sorted = pages.ByTitle()
+	case PagesOrderParam:
+		sorted = pages.ByParam(o.Param)
+	default:
+		sorted = pages.ByWeight()
+	}
+
+	if o.Reverse {
+		sorted = sorted.Reverse()
+	}
+
+	return sorted
+}
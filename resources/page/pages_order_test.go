@@ -0,0 +1,63 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDecodePagesOrder(t *testing.T) {
+	c := qt.New(t)
+
+	o, err := DecodePagesOrder(nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(o, qt.Equals, PagesOrder{})
+
+	o, err = DecodePagesOrder("Date")
+	c.Assert(err, qt.IsNil)
+	c.Assert(o, qt.Equals, PagesOrder{By: PagesOrderDate})
+
+	o, err = DecodePagesOrder("param:series.part")
+	c.Assert(err, qt.IsNil)
+	c.Assert(o, qt.Equals, PagesOrder{By: PagesOrderParam, Param: "series.part"})
+
+	o, err = DecodePagesOrder(map[string]any{"by": "title", "reverse": true})
+	c.Assert(err, qt.IsNil)
+	c.Assert(o, qt.Equals, PagesOrder{By: PagesOrderTitle, Reverse: true})
+
+	_, err = DecodePagesOrder("foo")
+	c.Assert(err, qt.Not(qt.IsNil))
+
+	_, err = DecodePagesOrder(map[string]any{"by": "param"})
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func TestPagesOrderSort(t *testing.T) {
+	c := qt.New(t)
+
+	pages := createSortTestPages(3)
+	pages[0].(*testPage).weight = 3
+	pages[1].(*testPage).weight = 2
+	pages[2].(*testPage).weight = 1
+
+	sorted := PagesOrder{By: PagesOrderWeight}.Sort(pages)
+	c.Assert(sorted[0], qt.Equals, pages[2])
+	c.Assert(sorted[2], qt.Equals, pages[0])
+
+	sorted = PagesOrder{By: PagesOrderWeight, Reverse: true}.Sort(pages)
+	c.Assert(sorted[0], qt.Equals, pages[0])
+	c.Assert(sorted[2], qt.Equals, pages[2])
+}
@@ -43,6 +43,17 @@ type PageGenealogist interface {
 	// Template example:
 	// {{ $related := .RegularPages.RelatedTo ( keyVals "tags" "hugo", "rocks")  ( keyVals "date" .Date ) }}
 	RelatedTo(args ...types.KeyValues) (Pages, error)
+
+	// Template example:
+	// {{ range .RegularPages.RelatedExplain . }}{{ .Page.Title }}: {{ .Explain }}{{ end }}
+	RelatedExplain(doc related.Document, indices ...any) ([]RelatedMatch, error)
+}
+
+// RelatedMatch is a page found in RelatedExplain with the keywords and
+// factors that made it match.
+type RelatedMatch struct {
+	Page Page
+	*related.Match
 }
 
 // Related searches all the configured indices with the search keywords from the
@@ -80,6 +91,39 @@ func (p Pages) RelatedIndices(doc related.Document, indices ...any) (Pages, erro
 	return result, nil
 }
 
+// RelatedExplain is Related, or RelatedIndices if indices are given,
+// returning the reasons why each page was selected.
+func (p Pages) RelatedExplain(doc related.Document, indices ...any) ([]RelatedMatch, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+
+	indicesStr, err := cast.ToStringSliceE(indices)
+	if err != nil {
+		return nil, err
+	}
+
+	searchIndex, err := p.invertedIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := searchIndex.ExplainDoc(doc, indicesStr...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []RelatedMatch
+	for _, m := range matches {
+		if page, ok := doc.(Page); ok && page.Eq(m.Doc) {
+			continue
+		}
+		result = append(result, RelatedMatch{Page: m.Doc.(Page), Match: m})
+	}
+
+	return result, nil
+}
+
 // RelatedTo searches the given indices with the corresponding values.
 func (p Pages) RelatedTo(args ...types.KeyValues) (Pages, error) {
 	if len(p) == 0 {
@@ -106,14 +150,7 @@ func (p Pages) withInvertedIndex(search func(idx *related.InvertedIndex) ([]rela
 		return nil, nil
 	}
 
-	d, ok := p[0].(InternalDependencies)
-	if !ok {
-		return nil, fmt.Errorf("invalid type %T in related search", p[0])
-	}
-
-	cache := d.GetRelatedDocsHandler()
-
-	searchIndex, err := cache.getOrCreateIndex(p)
+	searchIndex, err := p.invertedIndex()
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +171,15 @@ func (p Pages) withInvertedIndex(search func(idx *related.InvertedIndex) ([]rela
 	return nil, nil
 }
 
+func (p Pages) invertedIndex() (*related.InvertedIndex, error) {
+	d, ok := p[0].(InternalDependencies)
+	if !ok {
+		return nil, fmt.Errorf("invalid type %T in related search", p[0])
+	}
+
+	return d.GetRelatedDocsHandler().getOrCreateIndex(p)
+}
+
 type cachedPostingList struct {
 	p Pages
 
@@ -0,0 +1,136 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"errors"
+)
+
+// The set operations below compare pages by identity and run in linear time.
+// The result keeps the order of the pages in p followed by the pages in
+// other, unless an order, see DecodePagesOrder, is given, e.g.
+//
+//	{{ $pages := .Pages.Union site.RegularPages "date" }}
+
+// Union returns the pages in p or other, without duplicates.
+func (p Pages) Union(other any, order ...any) (Pages, error) {
+	p2, err := ToPages(other)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[Page]bool, len(p)+len(p2))
+	result := make(Pages, 0, len(p)+len(p2))
+	for _, pages := range []Pages{p, p2} {
+		for _, pp := range pages {
+			if !seen[pp] {
+				seen[pp] = true
+				result = append(result, pp)
+			}
+		}
+	}
+
+	return sortSetResult(result, order)
+}
+
+// Intersect returns the pages in both p and other.
+func (p Pages) Intersect(other any, order ...any) (Pages, error) {
+	p2, err := ToPages(other)
+	if err != nil {
+		return nil, err
+	}
+
+	in2 := pagesSet(p2)
+	seen := make(map[Page]bool)
+	var result Pages
+	for _, pp := range p {
+		if in2[pp] && !seen[pp] {
+			seen[pp] = true
+			result = append(result, pp)
+		}
+	}
+
+	return sortSetResult(result, order)
+}
+
+// SymDiff returns the pages in either p or other, but not in both.
+func (p Pages) SymDiff(other any, order ...any) (Pages, error) {
+	p2, err := ToPages(other)
+	if err != nil {
+		return nil, err
+	}
+
+	in1, in2 := pagesSet(p), pagesSet(p2)
+	seen := make(map[Page]bool)
+	var result Pages
+	add := func(pages Pages, exclude map[Page]bool) {
+		for _, pp := range pages {
+			if !exclude[pp] && !seen[pp] {
+				seen[pp] = true
+				result = append(result, pp)
+			}
+		}
+	}
+	add(p, in2)
+	add(p2, in1)
+
+	return sortSetResult(result, order)
+}
+
+// Subtract returns the pages in p that are not in other.
+func (p Pages) Subtract(other any, order ...any) (Pages, error) {
+	p2, err := ToPages(other)
+	if err != nil {
+		return nil, err
+	}
+
+	in2 := pagesSet(p2)
+	seen := make(map[Page]bool)
+	var result Pages
+	for _, pp := range p {
+		if !in2[pp] && !seen[pp] {
+			seen[pp] = true
+			result = append(result, pp)
+		}
+	}
+
+	return sortSetResult(result, order)
+}
+
+func pagesSet(pages Pages) map[Page]bool {
+	m := make(map[Page]bool, len(pages))
+	for _, p := range pages {
+		m[p] = true
+	}
+	return m
+}
+
+func sortSetResult(pages Pages, order []any) (Pages, error) {
+	if len(order) > 1 {
+		return nil, errors.New("too many arguments, the order is the only option")
+	}
+	if len(order) == 1 {
+		o, err := DecodePagesOrder(order[0])
+		if err != nil {
+			return nil, err
+		}
+		if len(pages) > 0 {
+			pages = o.Sort(pages)
+		}
+	}
+	if pages == nil {
+		pages = Pages{}
+	}
+	return pages, nil
+}
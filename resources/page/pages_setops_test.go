@@ -0,0 +1,80 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package page
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestPagesSetOperations(t *testing.T) {
+	c := qt.New(t)
+
+	pages := createSortTestPages(5)
+	p1, p2, p3, p4, p5 := pages[0], pages[1], pages[2], pages[3], pages[4]
+	a := Pages{p3, p1, p2, p1}
+	b := Pages{p4, p2, p5, p3}
+
+	for _, test := range []struct {
+		name   string
+		fn     func(other any, order ...any) (Pages, error)
+		expect Pages
+	}{
+		{"Union", a.Union, Pages{p3, p1, p2, p4, p5}},
+		{"Intersect", a.Intersect, Pages{p3, p2}},
+		{"SymDiff", a.SymDiff, Pages{p1, p4, p5}},
+		{"Subtract", a.Subtract, Pages{p1}},
+	} {
+		c.Run(test.name, func(c *qt.C) {
+			result, err := test.fn(b)
+			c.Assert(err, qt.IsNil)
+			assertSamePages(c, result, test.expect)
+
+			result, err = test.fn([]any{}, "weight")
+			c.Assert(err, qt.IsNil)
+			c.Assert(result, qt.Not(qt.IsNil))
+
+			_, err = test.fn("foo")
+			c.Assert(err, qt.Not(qt.IsNil))
+		})
+	}
+
+	union, err := a.Union(b, map[string]any{"by": "weight", "reverse": true})
+	c.Assert(err, qt.IsNil)
+	assertSamePages(c, union, PagesOrder{By: PagesOrderWeight, Reverse: true}.Sort(Pages{p1, p2, p3, p4, p5}))
+
+	_, err = a.Union(b, "foo")
+	c.Assert(err, qt.Not(qt.IsNil))
+}
+
+func assertSamePages(c *qt.C, got, expect Pages) {
+	c.Helper()
+	c.Assert(got, qt.HasLen, len(expect))
+	for i := range expect {
+		c.Assert(got[i], qt.Equals, expect[i], qt.Commentf("page %d", i))
+	}
+}
+
+func BenchmarkPagesUnion(b *testing.B) {
+	p1 := createSortTestPages(1000)
+	p2 := append(createSortTestPages(1000), p1[500:]...)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := p1.Union(p2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -37,6 +37,9 @@ type PermalinkExpander struct {
 
 	expanders map[string]func(Page) (string, error)
 
+	// The expanders for the custom page kinds.
+	kindExpanders map[string]func(Page) (string, error)
+
 	ps *helpers.PathSpec
 }
 
@@ -86,6 +89,26 @@ func NewPermalinkExpander(ps *helpers.PathSpec) (PermalinkExpander, error) {
 		"filename":    p.pageToPermalinkFilename,
 	}
 
+	kinds, err := DecodeKindsConfig(ps.Cfg.Get("kinds"))
+	if err != nil {
+		return p, err
+	}
+
+	kindPatterns := make(map[string]string)
+	for k, v := range kinds {
+		if v.Permalink != "" {
+			kindPatterns[k] = v.Permalink
+		}
+	}
+
+	if len(kindPatterns) > 0 {
+		e, err := p.parse(kindPatterns)
+		if err != nil {
+			return p, err
+		}
+		p.kindExpanders = e
+	}
+
 	patterns := ps.Cfg.GetStringMapString("permalinks")
 	if patterns == nil {
 		return p, nil
@@ -113,6 +136,18 @@ func (l PermalinkExpander) Expand(key string, p Page) (string, error) {
 	return expand(p)
 }
 
+// ExpandKind expands the path in p according to the permalink configured
+// for the custom page kind. If none is configured, an empty string is returned.
+func (l PermalinkExpander) ExpandKind(kind string, p Page) (string, error) {
+	expand, found := l.kindExpanders[kind]
+
+	if !found {
+		return "", nil
+	}
+
+	return expand(p)
+}
+
 func (l PermalinkExpander) parse(patterns map[string]string) (map[string]func(Page) (string, error), error) {
 	expanders := make(map[string]func(Page) (string, error))
 
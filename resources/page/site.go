@@ -76,6 +76,14 @@ type Site interface {
 
 	// Returns a map of all the data inside /data.
 	Data() map[string]any
+
+	// Returns a Scratch shared by all pages in this Site that gets reset on
+	// server rebuilds.
+	Scratch() *maps.Scratch
+
+	// Returns a Scratch shared by all pages in this Site that survives
+	// server rebuilds.
+	Store() *maps.Scratch
 }
 
 // Sites represents an ordered list of sites (languages).
@@ -92,6 +100,9 @@ func (s Sites) First() Site {
 type testSite struct {
 	h hugo.Info
 	l *langs.Language
+
+	scratch *maps.Scratch
+	store   *maps.Scratch
 }
 
 func (t testSite) Hugo() hugo.Info {
@@ -158,10 +169,21 @@ func (t testSite) Data() map[string]any {
 	return nil
 }
 
+func (t testSite) Scratch() *maps.Scratch {
+	return t.scratch
+}
+
+func (t testSite) Store() *maps.Scratch {
+	return t.store
+}
+
 // NewDummyHugoSite creates a new minimal test site.
 func NewDummyHugoSite(cfg config.Provider) Site {
 	return testSite{
 		h: hugo.NewInfo(hugo.EnvironmentProduction, nil),
 		l: langs.NewLanguage("en", cfg),
+
+		scratch: maps.NewScratch(),
+		store:   maps.NewScratch(),
 	}
 }
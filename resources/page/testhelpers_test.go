@@ -134,6 +134,10 @@ func (p *testPage) AllTranslations() Pages {
 	panic("not implemented")
 }
 
+func (p *testPage) Alternates() Alternates {
+	panic("not implemented")
+}
+
 func (p *testPage) AlternativeOutputFormats() OutputFormats {
 	panic("not implemented")
 }
@@ -158,10 +162,22 @@ func (p *testPage) Content() (any, error) {
 	panic("not implemented")
 }
 
+func (p *testPage) ContentBlocks() map[string]template.HTML {
+	panic("not implemented")
+}
+
 func (p *testPage) ContentBaseName() string {
 	panic("not implemented")
 }
 
+func (p *testPage) Breadcrumbs() Breadcrumbs {
+	panic("not implemented")
+}
+
+func (p *testPage) Canonical() string {
+	panic("not implemented")
+}
+
 func (p *testPage) CurrentSection() Page {
 	return p.currentSection
 }
@@ -182,6 +198,10 @@ func (p *testPage) Date() time.Time {
 	return p.date
 }
 
+func (p *testPage) DatePrecision() string {
+	return ""
+}
+
 func (p *testPage) Description() string {
 	return ""
 }
@@ -266,6 +286,10 @@ func (p *testPage) HasShortcode(name string) bool {
 	panic("not implemented")
 }
 
+func (p *testPage) Hreflangs() []Hreflang {
+	panic("not implemented")
+}
+
 func (p *testPage) Hugo() hugo.Info {
 	panic("not implemented")
 }
@@ -372,10 +396,22 @@ func (p *testPage) NextInSection() Page {
 	return nil
 }
 
+func (p *testPage) NextInCollection(pages Pages) Page {
+	return pages.Next(p)
+}
+
 func (p *testPage) NextPage() Page {
 	return nil
 }
 
+func (p *testPage) NumberRef(ref string) (template.HTML, error) {
+	panic("not implemented")
+}
+
+func (p *testPage) Numbering() *Numbering {
+	panic("not implemented")
+}
+
 func (p *testPage) OutputFormats() OutputFormats {
 	panic("not implemented")
 }
@@ -444,6 +480,10 @@ func (p *testPage) PrevInSection() Page {
 	return nil
 }
 
+func (p *testPage) PrevInCollection(pages Pages) Page {
+	return pages.Prev(p)
+}
+
 func (p *testPage) PrevPage() Page {
 	return nil
 }
@@ -460,6 +500,14 @@ func (p *testPage) RawContent() string {
 	panic("not implemented")
 }
 
+func (p *testPage) RawContentSource() string {
+	panic("not implemented")
+}
+
+func (p *testPage) RawFrontMatter() RawFrontMatter {
+	panic("not implemented")
+}
+
 func (p *testPage) ReadingTime() int {
 	panic("not implemented")
 }
@@ -492,6 +540,18 @@ func (p *testPage) RenderString(args ...any) (template.HTML, error) {
 	panic("not implemented")
 }
 
+func (p *testPage) RenderShortcode(name string, index ...int) (template.HTML, error) {
+	panic("not implemented")
+}
+
+func (p *testPage) RenderFragment(id string) (template.HTML, error) {
+	panic("not implemented")
+}
+
+func (p *testPage) Include(ref string) (template.HTML, error) {
+	panic("not implemented")
+}
+
 func (p *testPage) ResourceType() string {
 	panic("not implemented")
 }
@@ -500,6 +560,10 @@ func (p *testPage) Resources() resource.Resources {
 	panic("not implemented")
 }
 
+func (p *testPage) Gallery(options ...any) (*Gallery, error) {
+	panic("not implemented")
+}
+
 func (p *testPage) Scratch() *maps.Scratch {
 	panic("not implemented")
 }
@@ -508,6 +572,10 @@ func (p *testPage) Store() *maps.Scratch {
 	panic("not implemented")
 }
 
+func (p *testPage) RenderScratch() *maps.Scratch {
+	panic("not implemented")
+}
+
 func (p *testPage) RelatedKeywords(cfg related.IndexConfig) ([]related.Keyword, error) {
 	v, err := p.Param(cfg.Name)
 	if err != nil {
@@ -311,6 +311,8 @@ func (l *genericResource) Publish() error {
 		}
 		defer fw.Close()
 
+		l.spec.addPublished(l.realSourceFilename(), l.getTargetFilenames()...)
+
 		_, err = io.Copy(fw, fr)
 	})
 
@@ -474,6 +476,8 @@ func (l *genericResource) openDestinationsForWriting() (w io.WriteCloser, err er
 		targetFilenames := l.getTargetFilenames()
 		var changedFilenames []string
 
+		l.getSpec().addPublished(l.realSourceFilename(), targetFilenames...)
+
 		// Fast path:
 		// This is a processed version of the original;
 		// check if it already exists at the destination.
@@ -496,7 +500,9 @@ func (l *genericResource) openDestinationsForWriting() (w io.WriteCloser, err er
 }
 
 func (r *genericResource) openPublishFileForWriting(relTargetPath string) (io.WriteCloser, error) {
-	return helpers.OpenFilesForWriting(r.spec.BaseFs.PublishFs, r.relTargetPathsFor(relTargetPath)...)
+	filenames := r.relTargetPathsFor(relTargetPath)
+	r.spec.addPublished(r.realSourceFilename(), filenames...)
+	return helpers.OpenFilesForWriting(r.spec.BaseFs.PublishFs, filenames...)
 }
 
 func (l *genericResource) permalinkFor(target string) string {
@@ -641,6 +647,15 @@ func (fi *resourceFileInfo) getSourceFilename() string {
 	return fi.sourceFilename
 }
 
+// realSourceFilename returns the filename of the source on the real
+// filesystem, or an empty string if not backed by a file.
+func (fi *resourceFileInfo) realSourceFilename() string {
+	if fi.fi == nil {
+		return ""
+	}
+	return fi.fi.Meta().Filename
+}
+
 func (fi *resourceFileInfo) setSourceFilename(s string) {
 	// Make sure it's always loaded by sourceFilename.
 	fi.openReadSeekerCloser = nil
@@ -37,23 +37,45 @@ type Dated interface {
 	ExpiryDate() time.Time
 }
 
+// DatePrecisionProvider provides the precision of the date.
+type DatePrecisionProvider interface {
+	// DatePrecision returns the precision of Date as given in front matter:
+	// year, month, day or time, empty if not known.
+	DatePrecision() string
+}
+
 // Dates holds the 4 Hugo dates.
 type Dates struct {
 	FDate        time.Time
 	FLastmod     time.Time
 	FPublishDate time.Time
 	FExpiryDate  time.Time
+
+	FDatePrecision string
 }
 
 func (d *Dates) UpdateDateAndLastmodIfAfter(in Dated) {
 	if in.Date().After(d.Date()) {
 		d.FDate = in.Date()
+		d.FDatePrecision = ""
+		if dp, ok := in.(DatePrecisionProvider); ok {
+			d.FDatePrecision = dp.DatePrecision()
+		}
 	}
 	if in.Lastmod().After(d.Lastmod()) {
 		d.FLastmod = in.Lastmod()
 	}
 }
 
+// InLocation sets the location of the non-zero dates to loc.
+func (d *Dates) InLocation(loc *time.Location) {
+	for _, t := range []*time.Time{&d.FDate, &d.FLastmod, &d.FPublishDate, &d.FExpiryDate} {
+		if !t.IsZero() {
+			*t = t.In(loc)
+		}
+	}
+}
+
 // IsFuture returns whether the argument represents the future.
 func IsFuture(d Dated) bool {
 	if d.PublishDate().IsZero() {
@@ -80,6 +102,10 @@ func (p Dates) Date() time.Time {
 	return p.FDate
 }
 
+func (p Dates) DatePrecision() string {
+	return p.FDatePrecision
+}
+
 func (p Dates) Lastmod() time.Time {
 	return p.FLastmod
 }
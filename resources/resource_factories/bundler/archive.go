@@ -0,0 +1,175 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundler
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/common/hugio"
+	"github.com/gohugoio/hugo/media"
+	"github.com/gohugoio/hugo/resources"
+	"github.com/gohugoio/hugo/resources/resource"
+)
+
+// archiveModTime is the modification time of all files in the archives, so
+// the same resources always give the same archive, and fingerprint.
+var archiveModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type archiveEntry struct {
+	name string
+	r    hugio.ReadSeekCloser
+}
+
+// Zip creates a zip archive of the list of Resource objects.
+// The files in the archive are named by the resources' names,
+// e.g. the path relative to the page bundle.
+func (c *Client) Zip(targetPath string, r resource.Resources) (resource.Resource, error) {
+	mt, _ := media.FromStringAndExt("application/zip", "zip")
+	return c.archive(targetPath, r, mt, func(w io.Writer, entries []archiveEntry) error {
+		zw := zip.NewWriter(w)
+		for _, e := range entries {
+			fw, err := zw.CreateHeader(&zip.FileHeader{
+				Name:     e.name,
+				Method:   zip.Deflate,
+				Modified: archiveModTime,
+			})
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(fw, e.r); err != nil {
+				return err
+			}
+		}
+		return zw.Close()
+	})
+}
+
+// Tar creates a tar archive of the list of Resource objects, compressed with
+// gzip if targetPath ends with .tar.gz or .tgz.
+// The files in the archive are named by the resources' names,
+// e.g. the path relative to the page bundle.
+func (c *Client) Tar(targetPath string, r resource.Resources) (resource.Resource, error) {
+	lower := strings.ToLower(targetPath)
+	gz := strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+
+	mt, _ := media.FromStringAndExt("application/x-tar", "tar")
+	if gz {
+		mt, _ = media.FromStringAndExt("application/gzip", strings.TrimPrefix(path.Ext(lower), "."))
+	}
+
+	return c.archive(targetPath, r, mt, func(w io.Writer, entries []archiveEntry) error {
+		var gzw *gzip.Writer
+		if gz {
+			gzw = gzip.NewWriter(w)
+			gzw.ModTime = archiveModTime
+			w = gzw
+		}
+
+		tw := tar.NewWriter(w)
+		for _, e := range entries {
+			var buf bytes.Buffer
+			if _, err := io.Copy(&buf, e.r); err != nil {
+				return err
+			}
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     e.name,
+				Mode:     0644,
+				Size:     int64(buf.Len()),
+				ModTime:  archiveModTime,
+				Format:   tar.FormatPAX,
+			}); err != nil {
+				return err
+			}
+			if _, err := buf.WriteTo(tw); err != nil {
+				return err
+			}
+		}
+		if err := tw.Close(); err != nil {
+			return err
+		}
+
+		if gzw != nil {
+			return gzw.Close()
+		}
+		return nil
+	})
+}
+
+func (c *Client) archive(targetPath string, r resource.Resources, mt media.Type, write func(w io.Writer, entries []archiveEntry) error) (resource.Resource, error) {
+	// The CACHE_OTHER will make sure this will be re-created and published on rebuilds.
+	return c.rs.ResourceCache.GetOrCreate(path.Join(resources.CACHE_OTHER, targetPath), func() (resource.Resource, error) {
+		names := make([]string, len(r))
+		seen := make(map[string]bool)
+		for i, rr := range r {
+			name := strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(rr.Name())), "/")
+			if name == "" {
+				return nil, fmt.Errorf("resource %q has no name to use in archive %q", rr.RelPermalink(), targetPath)
+			}
+			if seen[name] {
+				return nil, fmt.Errorf("duplicate file %q in archive %q", name, targetPath)
+			}
+			seen[name] = true
+			names[i] = name
+		}
+
+		archiver := func() (hugio.ReadSeekCloser, error) {
+			entries := make([]archiveEntry, len(r))
+			defer func() {
+				for _, e := range entries {
+					if e.r != nil {
+						e.r.Close()
+					}
+				}
+			}()
+
+			for i, s := range r {
+				rcr, ok := s.(resource.ReadSeekCloserResource)
+				if !ok {
+					return nil, fmt.Errorf("resource %T does not implement resource.ReadSeekerCloserResource", s)
+				}
+				rc, err := rcr.ReadSeekCloser()
+				if err != nil {
+					return nil, err
+				}
+				entries[i] = archiveEntry{name: names[i], r: rc}
+			}
+
+			var buf bytes.Buffer
+			if err := write(&buf, entries); err != nil {
+				return nil, fmt.Errorf("failed to create archive %q: %w", targetPath, err)
+			}
+
+			return hugio.NewReadSeekerNoOpCloser(bytes.NewReader(buf.Bytes())), nil
+		}
+
+		return c.rs.New(
+			resources.ResourceSourceDescriptor{
+				Fs:                 c.rs.FileCaches.AssetsCache().Fs,
+				LazyPublish:        true,
+				OpenReadSeekCloser: archiver,
+				RelTargetFilename:  filepath.Clean(targetPath),
+				MediaType:          mt,
+			})
+	})
+}
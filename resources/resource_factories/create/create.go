@@ -16,6 +16,7 @@
 package create
 
 import (
+	"bytes"
 	"net/http"
 	"path"
 	"path/filepath"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/gohugoio/hugo/cache/filecache"
 	"github.com/gohugoio/hugo/common/hugio"
+	"github.com/gohugoio/hugo/media"
 	"github.com/gohugoio/hugo/resources"
 	"github.com/gohugoio/hugo/resources/resource"
 )
@@ -137,13 +139,32 @@ func (c *Client) match(name, pattern string, matchFunc func(r resource.Resource)
 
 // FromString creates a new Resource from a string with the given relative target path.
 func (c *Client) FromString(targetPath, content string) (resource.Resource, error) {
+	return c.FromBytes(targetPath, []byte(content), media.Type{})
+}
+
+// FromBytes creates a new Resource from content, which may be binary, and
+// publishes it to targetPath.
+// If mediaType is zero, it is resolved from the suffix of targetPath or,
+// if that is not a known suffix, from the content.
+func (c *Client) FromBytes(targetPath string, content []byte, mediaType media.Type) (resource.Resource, error) {
+	if mediaType.IsZero() {
+		ext := strings.TrimPrefix(path.Ext(targetPath), ".")
+		if _, _, found := c.rs.MediaTypes.GetFirstBySuffix(ext); ext == "" || !found {
+			var extensionHints []string
+			if ext != "" {
+				extensionHints = []string{ext}
+			}
+			mediaType = media.FromContent(c.rs.MediaTypes, extensionHints, content)
+		}
+	}
 	return c.rs.ResourceCache.GetOrCreate(path.Join(resources.CACHE_OTHER, targetPath), func() (resource.Resource, error) {
 		return c.rs.New(
 			resources.ResourceSourceDescriptor{
 				Fs:          c.rs.FileCaches.AssetsCache().Fs,
 				LazyPublish: true,
+				MediaType:   mediaType,
 				OpenReadSeekCloser: func() (hugio.ReadSeekCloser, error) {
-					return hugio.NewReadSeekerNoOpCloserFromString(content), nil
+					return hugio.NewReadSeekerNoOpCloser(bytes.NewReader(content)), nil
 				},
 				RelTargetFilename: filepath.Clean(targetPath),
 			})
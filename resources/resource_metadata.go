@@ -139,6 +139,31 @@ func AssignMetadata(metadata []map[string]any, resources ...resource.Resource) e
 	return nil
 }
 
+// ResourceAliases returns the aliases set for r in the first entry in
+// metadata with aliases matching r, e.g.:
+//
+//	[[resources]]
+//	src = "images/logo.png"
+//	aliases = ["logo-old.png", "/images/logo.png"]
+func ResourceAliases(metadata []map[string]any, r resource.Resource) ([]string, error) {
+	resourceSrcKey := strings.ToLower(r.Name())
+	for _, meta := range metadata {
+		aliases, found := meta["aliases"]
+		if !found {
+			continue
+		}
+		srcKey := strings.ToLower(cast.ToString(meta["src"]))
+		glob, err := glob.GetGlob(srcKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to match resource with metadata: %w", err)
+		}
+		if glob.Match(resourceSrcKey) {
+			return cast.ToStringSliceE(aliases)
+		}
+	}
+	return nil, nil
+}
+
 func replaceResourcePlaceholders(in string, counter int) string {
 	return strings.Replace(in, counterPlaceHolder, strconv.Itoa(counter), -1)
 }
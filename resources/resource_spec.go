@@ -78,6 +78,16 @@ func NewSpec(
 		return nil, err
 	}
 
+	fingerprintConfig, err := config.DecodeFingerprint(s.Cfg.GetStringMap("fingerprint"))
+	if err != nil {
+		return nil, err
+	}
+
+	cacheControlConfig, err := config.DecodeCacheControl(s.Cfg.GetStringMap("cacheControl"))
+	if err != nil {
+		return nil, err
+	}
+
 	rs := &Spec{
 		PathSpec:      s,
 		Logger:        logger,
@@ -89,9 +99,13 @@ func NewSpec(
 		OutputFormats: outputFormats,
 		Permalinks:    permalinks,
 		BuildConfig:   config.DecodeBuild(s.Cfg),
+		Fingerprint:   fingerprintConfig,
+		CacheControl:  cacheControlConfig,
 		FileCaches:    fileCaches,
 		PostBuildAssets: &PostBuildAssets{
 			PostProcessResources: make(map[string]postpub.PostPublishedResource),
+			PublishedResources:   make(map[string]string),
+			Fingerprinted:        make(map[string]string),
 			JSConfigBuilder:      jsconfig.NewBuilder(),
 		},
 		imageCache: newImageCache(
@@ -119,6 +133,9 @@ type Spec struct {
 
 	Permalinks  page.PermalinkExpander
 	BuildConfig config.Build
+	Fingerprint config.Fingerprint
+
+	CacheControl config.CacheControl
 
 	// Holds default filter settings etc.
 	imaging *images.ImageProcessor
@@ -139,6 +156,65 @@ type PostBuildAssets struct {
 	postProcessMu        sync.RWMutex
 	PostProcessResources map[string]postpub.PostPublishedResource
 	JSConfigBuilder      *jsconfig.Builder
+
+	// The source filenames of the published resources keyed by their target
+	// filenames, only collected when needed, see collectPublished.
+	publishedMu        sync.Mutex
+	PublishedResources map[string]string
+
+	// The latest fingerprinted target paths keyed by the target paths before
+	// fingerprinting and the hash algorithms, e.g. /css/main.css#sha256, only
+	// collected when needed, see collectPublished.
+	Fingerprinted map[string]string
+}
+
+// AddFingerprinted records that the resource with the target path inPath
+// is published as outPath when fingerprinted with algo.
+func (r *Spec) AddFingerprinted(inPath, algo, outPath string) {
+	if !r.collectPublished() {
+		return
+	}
+	r.publishedMu.Lock()
+	defer r.publishedMu.Unlock()
+	r.Fingerprinted[inPath+"#"+algo] = outPath
+}
+
+// WalkFingerprinted calls fn with the names, see AddFingerprinted, and the
+// latest fingerprinted target paths of all the fingerprinted resources.
+func (p *PostBuildAssets) WalkFingerprinted(fn func(name, outPath string)) {
+	p.publishedMu.Lock()
+	defer p.publishedMu.Unlock()
+	for name, outPath := range p.Fingerprinted {
+		fn(name, outPath)
+	}
+}
+
+// WalkPublishedResources calls fn with the target and source filenames of
+// all the published resources.
+func (p *PostBuildAssets) WalkPublishedResources(fn func(target, source string)) {
+	p.publishedMu.Lock()
+	defer p.publishedMu.Unlock()
+	for target, source := range p.PublishedResources {
+		fn(target, source)
+	}
+}
+
+// collectPublished returns whether the published and fingerprinted resources
+// are needed after the build, for build.writeManifest, fingerprint.renameMap
+// or cacheControl.
+func (r *Spec) collectPublished() bool {
+	return r.BuildConfig.WriteManifest || r.Fingerprint.RenameMap != "" || r.CacheControl.IsEnabled()
+}
+
+func (r *Spec) addPublished(sourceFilename string, targetFilenames ...string) {
+	if !r.collectPublished() {
+		return
+	}
+	r.publishedMu.Lock()
+	defer r.publishedMu.Unlock()
+	for _, filename := range targetFilenames {
+		r.PublishedResources[filename] = sourceFilename
+	}
 }
 
 func (r *Spec) New(fd ResourceSourceDescriptor) (resource.Resource, error) {
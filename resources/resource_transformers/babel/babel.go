@@ -28,6 +28,7 @@ import (
 	"github.com/gohugoio/hugo/common/loggers"
 
 	"github.com/gohugoio/hugo/common/hugo"
+	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/resources/internal"
 
 	"github.com/mitchellh/mapstructure"
@@ -35,6 +36,7 @@ import (
 	"github.com/gohugoio/hugo/common/herrors"
 	"github.com/gohugoio/hugo/resources"
 	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/resources/resource_transformers/nodedaemon"
 )
 
 // Options from https://babeljs.io/docs/en/options
@@ -89,17 +91,20 @@ func (opts Options) toArgs() []any {
 
 // Client is the client used to do Babel transformations.
 type Client struct {
-	rs *resources.Spec
+	rs     *resources.Spec
+	daemon *nodedaemon.Daemon
 }
 
 // New creates a new Client with the given specification.
-func New(rs *resources.Spec) *Client {
-	return &Client{rs: rs}
+// If daemon is set, Babel runs in its Node.js process.
+func New(rs *resources.Spec, daemon *nodedaemon.Daemon) *Client {
+	return &Client{rs: rs, daemon: daemon}
 }
 
 type babelTransformation struct {
 	options Options
 	rs      *resources.Spec
+	daemon  *nodedaemon.Daemon
 }
 
 func (t *babelTransformation) Key() internal.ResourceTransformationKey {
@@ -116,12 +121,6 @@ func (t *babelTransformation) Key() internal.ResourceTransformationKey {
 func (t *babelTransformation) Transform(ctx *resources.ResourceTransformationCtx) error {
 	const binaryName = "babel"
 
-	ex := t.rs.ExecHelper
-
-	if err := ex.Sec().CheckAllowedExec(binaryName); err != nil {
-		return err
-	}
-
 	var configFile string
 	logger := t.rs.Logger
 
@@ -147,10 +146,23 @@ func (t *babelTransformation) Transform(ctx *resources.ResourceTransformationCtx
 
 	ctx.ReplaceOutPathExtension(".js")
 
+	if configFile != "" {
+		logger.Infoln("babel: use config file", configFile)
+	}
+
+	if t.daemon != nil {
+		return t.transformWithDaemon(ctx, configFile)
+	}
+
+	ex := t.rs.ExecHelper
+
+	if err := ex.Sec().CheckAllowedExec(binaryName); err != nil {
+		return err
+	}
+
 	var cmdArgs []any
 
 	if configFile != "" {
-		logger.Infoln("babel: use config file", configFile)
 		cmdArgs = []any{"--config-file", configFile}
 	}
 
@@ -231,9 +243,37 @@ func (t *babelTransformation) Transform(ctx *resources.ResourceTransformationCtx
 	return nil
 }
 
+func (t *babelTransformation) transformWithDaemon(ctx *resources.ResourceTransformationCtx, configFile string) error {
+	res, err := t.daemon.Execute(nodedaemon.Request{
+		Tool:       "babel",
+		Input:      helpers.ReaderToString(ctx.From),
+		Filename:   ctx.SourcePath,
+		ConfigFile: configFile,
+		Options:    t.options,
+	})
+	if err != nil {
+		if hexec.IsNotFound(err) {
+			return herrors.ErrFeatureNotAvailable
+		}
+		return err
+	}
+
+	content := res.Code
+	if res.Map != "" {
+		if err = ctx.PublishSourceMap(res.Map); err != nil {
+			return err
+		}
+		content += "\n//# sourceMappingURL=" + path.Base(ctx.OutPath) + ".map\n"
+	}
+
+	_, err = io.WriteString(ctx.To, content)
+
+	return err
+}
+
 // Process transforms the given Resource with the Babel processor.
 func (c *Client) Process(res resources.ResourceTransformer, options Options) (resource.Resource, error) {
 	return res.Transform(
-		&babelTransformation{rs: c.rs, options: options},
+		&babelTransformation{rs: c.rs, daemon: c.daemon, options: options},
 	)
 }
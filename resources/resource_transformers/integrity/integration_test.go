@@ -0,0 +1,72 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integrity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestFingerprintConfig(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404", "section", "page"]
+[fingerprint]
+algorithm = "md5"
+length = 8
+position = "POSITION"
+renameMap = "fingerprints.json"
+-- assets/css/main.css --
+body { color: red; }
+-- layouts/index.html --
+{{ $css := resources.Get "css/main.css" | fingerprint }}
+{{ $sha := resources.Get "css/main.css" | fingerprint "sha512" }}
+CSS: {{ $css.RelPermalink }}|{{ $css.Data.Integrity }}|
+SHA: {{ $sha.RelPermalink }}|
+`
+
+	for _, test := range []struct {
+		position string
+		css      string
+		newCSS   string
+	}{
+		{"suffix", "/css/main.f2b804d3.css", "/css/main.8559e101.css"},
+		{"prefix", "/css/f2b804d3.main.css", "/css/8559e101.main.css"},
+		{"dir", "/css/f2b804d3/main.css", "/css/8559e101/main.css"},
+	} {
+		test := test
+		t.Run(test.position, func(t *testing.T) {
+			t.Parallel()
+			b := hugolib.NewIntegrationTestBuilder(
+				hugolib.IntegrationTestConfig{
+					T:           t,
+					TxtarString: strings.Replace(files, "POSITION", test.position, 1),
+					Running:     true,
+				},
+			).Build()
+
+			b.AssertFileContent("public/index.html", "CSS: "+test.css+"|md5-")
+			b.AssertFileContent("public/fingerprints.json", "{}")
+
+			b.EditFiles("assets/css/main.css", "body { color: blue; }").Build()
+
+			b.AssertFileContent("public/fingerprints.json", `"`+test.css+`": "`+test.newCSS+`"`)
+		})
+	}
+}
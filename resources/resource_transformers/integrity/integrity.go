@@ -23,6 +23,9 @@ import (
 	"hash"
 	"html/template"
 	"io"
+	"path"
+
+	"github.com/gohugoio/hugo/config"
 
 	"github.com/gohugoio/hugo/resources/internal"
 
@@ -45,14 +48,19 @@ func New(rs *resources.Spec) *Client {
 
 type fingerprintTransformation struct {
 	algo string
+	rs   *resources.Spec
 }
 
 func (t *fingerprintTransformation) Key() internal.ResourceTransformationKey {
-	return internal.NewResourceTransformationKey("fingerprint", t.algo)
+	cfg := t.rs.Fingerprint
+	if cfg.Length == 0 && cfg.Position == config.FingerprintPositionSuffix {
+		return internal.NewResourceTransformationKey("fingerprint", t.algo)
+	}
+	return internal.NewResourceTransformationKey("fingerprint", t.algo, cfg.Length, cfg.Position)
 }
 
-// Transform creates a MD5 hash of the Resource content and inserts that hash before
-// the extension in the filename.
+// Transform creates a hash of the Resource content and inserts that hash in
+// the filename, by default before the extension.
 func (t *fingerprintTransformation) Transform(ctx *resources.ResourceTransformationCtx) error {
 	h, err := newHash(t.algo)
 	if err != nil {
@@ -76,7 +84,25 @@ func (t *fingerprintTransformation) Transform(ctx *resources.ResourceTransformat
 	}
 
 	ctx.Data["Integrity"] = integrity(t.algo, d)
-	ctx.AddOutPathIdentifier("." + hex.EncodeToString(d[:]))
+
+	cfg := t.rs.Fingerprint
+	id := hex.EncodeToString(d[:])
+	if cfg.Length > 0 && cfg.Length < len(id) {
+		id = id[:cfg.Length]
+	}
+
+	dir, file := path.Split(ctx.InPath)
+	switch cfg.Position {
+	case config.FingerprintPositionPrefix:
+		ctx.OutPath = path.Join(dir, id+"."+file)
+	case config.FingerprintPositionDir:
+		ctx.OutPath = path.Join(dir, id, file)
+	default:
+		ctx.AddOutPathIdentifier("." + id)
+	}
+
+	t.rs.AddFingerprinted(ctx.InPath, t.algo, ctx.OutPath)
+
 	return nil
 }
 
@@ -96,17 +122,21 @@ func newHash(algo string) (hash.Hash, error) {
 }
 
 // Fingerprint applies fingerprinting of the given resource and hash algorithm.
-// It defaults to sha256 if none given, and the options are md5, sha256 or sha512.
+// It defaults to the algorithm in the fingerprint config, sha256 if not set,
+// and the options are md5, sha256, sha384 or sha512.
 // The same algo is used for both the fingerprinting part (aka cache busting) and
 // the base64-encoded Subresource Integrity hash, so you will have to stay away from
 // md5 if you plan to use both.
 // See https://developer.mozilla.org/en-US/docs/Web/Security/Subresource_Integrity
 func (c *Client) Fingerprint(res resources.ResourceTransformer, algo string) (resource.Resource, error) {
+	if algo == "" {
+		algo = c.rs.Fingerprint.Algorithm
+	}
 	if algo == "" {
 		algo = defaultHashAlgo
 	}
 
-	return res.Transform(&fingerprintTransformation{algo: algo})
+	return res.Transform(&fingerprintTransformation{algo: algo, rs: c.rs})
 }
 
 func integrity(algo string, sum []byte) template.HTMLAttr {
@@ -16,11 +16,13 @@ package js
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"errors"
 
@@ -38,19 +40,29 @@ import (
 	"github.com/evanw/esbuild/pkg/api"
 	"github.com/gohugoio/hugo/resources"
 	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/resources/resource_factories/create"
 )
 
 // Client context for ESBuild.
 type Client struct {
 	rs  *resources.Spec
 	sfs *filesystems.SourceFilesystem
+
+	createClient *create.Client
+	httpClient   *http.Client
+	lock         *lockFile
 }
 
 // New creates a new client context.
 func New(fs *filesystems.SourceFilesystem, rs *resources.Spec) *Client {
 	return &Client{
-		rs:  rs,
-		sfs: fs,
+		rs:           rs,
+		sfs:          fs,
+		createClient: create.New(rs),
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		lock: &lockFile{filename: LockFilename},
 	}
 }
 
@@ -192,6 +204,10 @@ func (t *buildTransformation) Transform(ctx *resources.ResourceTransformationCtx
 		return errors[0]
 	}
 
+	if err := t.c.lock.save(t.c.rs.BaseFs.SourceFs); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockFilename, err)
+	}
+
 	if buildOptions.Sourcemap == api.SourceMapExternal {
 		content := string(result.OutputFiles[1].Contents)
 		symPath := path.Base(ctx.OutPath) + ".map"
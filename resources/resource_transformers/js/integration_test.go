@@ -14,6 +14,9 @@
 package js_test
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -21,6 +24,7 @@ import (
 	qt "github.com/frankban/quicktest"
 	"github.com/gohugoio/hugo/htesting"
 	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/resources/resource_transformers/js"
 )
 
 func TestBuildVariants(t *testing.T) {
@@ -259,3 +263,124 @@ JS Content:{{ $js.Content }}:End:
 	})
 
 }
+
+func TestBuildColocatedAssets(t *testing.T) {
+	c := qt.New(t)
+
+	files := `
+-- config.toml --
+disableKinds=["page", "section", "taxonomy", "term", "sitemap", "robotsTXT"]
+theme = "mytheme"
+[module]
+colocateAssets = true
+-- layouts/index.html --
+{{ partial "card/card.html" . }}
+{{ $js := resources.Get "partials/hello.js" | js.Build }}
+Hello:{{ $js.Content }}:End:
+{{ $html := resources.Get "partials/card/card.html" }}
+HTML: {{ with $html }}Found{{ else }}Not found{{ end }}|
+-- layouts/partials/hello.js --
+import { card } from './card/card';
+console.log("Hello " + card());
+-- themes/mytheme/config.toml --
+[module]
+colocateAssets = true
+-- themes/mytheme/layouts/partials/card/card.html --
+{{ $js := resources.Get "partials/card/card.js" | js.Build }}
+Card:{{ $js.Content }}:End:
+-- themes/mytheme/layouts/partials/card/card.js --
+import { util } from './util';
+export function card() {
+	return 'card ' + util();
+}
+-- themes/mytheme/layouts/partials/card/util.js --
+export function util() {
+	return 'util';
+}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(hugolib.IntegrationTestConfig{T: c, NeedsOsFS: true, TxtarString: files}).Build()
+
+	b.AssertFileContent("public/index.html", "Card:(() =&gt; {\n  // ns-hugo:", "mytheme/layouts/partials/card/card.js\n  function card()", "HTML: Not found|")
+}
+
+func TestBuildRemoteImports(t *testing.T) {
+	c := qt.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/greet@1.0.0":
+			fmt.Fprint(w, `export * from "/greet@1.0.0/es2022/greet.mjs";`)
+		case "/greet@1.0.0/es2022/greet.mjs":
+			fmt.Fprint(w, `import { exclaim } from "./exclaim.mjs"; export function greet(s) { return exclaim("Hello " + s); }`)
+		case "/greet@1.0.0/es2022/exclaim.mjs":
+			fmt.Fprint(w, `export function exclaim(s) { return s + "!"; }`)
+		case "/shout":
+			fmt.Fprint(w, `export function shout(s) { return s.toUpperCase(); }`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	files := strings.ReplaceAll(`
+-- config.toml --
+disableKinds=["page", "section", "taxonomy", "term", "sitemap", "robotsTXT"]
+-- assets/js/main.js --
+import { greet } from 'greet';
+import { shout } from 'shout';
+console.log(shout(greet("remote")));
+-- layouts/index.html --
+{{ $opts := dict "importMap" (dict "greet" "SRV/greet@1.0.0") "cdn" "SRV" }}
+{{ $js := resources.Get "js/main.js" | js.Build $opts }}
+JS Content:{{ $js.Content | safeHTML }}:End:
+`, "SRV", srv.URL)
+
+	b := hugolib.NewIntegrationTestBuilder(hugolib.IntegrationTestConfig{T: c, NeedsOsFS: true, TxtarString: files}).Build()
+
+	b.AssertFileContent("public/index.html", `return exclaim("Hello " + s);`, `return s + "!";`, `console.log(shout(greet("remote")));`)
+	b.AssertFileContent(js.LockFilename, srv.URL+`/greet@1.0.0/es2022/exclaim.mjs": "sha256-`, srv.URL+`/shout": "sha256-`)
+}
+
+func TestModules(t *testing.T) {
+	c := qt.New(t)
+
+	files := `
+-- config.toml --
+disableKinds=["page", "section", "taxonomy", "term", "sitemap", "robotsTXT"]
+-- assets/js/main.ts --
+import { hello } from './lib/hello';
+import { html } from 'lit';
+const msg: string = hello();
+import('./lib/lazy.js').then((m) => m.lazy(msg));
+-- assets/js/lib/hello.js --
+export * from '../util';
+export function hello() {
+	return 'Hello';
+}
+-- assets/js/util.js --
+import { hello } from './lib/hello.js';
+export const util = () => hello();
+-- assets/js/lib/lazy.js --
+export function lazy(s) { console.log(s); }
+-- layouts/index.html --
+{{ $mods := resources.Get "js/main.ts" | js.Modules (dict "importMap" (dict "lit" "https://esm.sh/lit@2.4.0") "fingerprint" false) }}
+Entry: {{ $mods.Entry.RelPermalink }}|
+{{ range $mods.Resources }}Module: {{ .RelPermalink }}|{{ end }}
+ImportMap: {{ $mods.ImportMap | safeHTML }}|
+{{ $fp := resources.Get "js/lib/lazy.js" | js.Modules }}
+Fingerprinted: {{ $fp.Entry.RelPermalink }}|
+`
+
+	b := hugolib.NewIntegrationTestBuilder(hugolib.IntegrationTestConfig{T: c, NeedsOsFS: true, TxtarString: files}).Build()
+
+	b.AssertFileContent("public/index.html",
+		"Entry: /js/main.js|",
+		"Module: /js/main.js|Module: /js/lib/hello.js|Module: /js/util.js|Module: /js/lib/lazy.js|",
+		`ImportMap: {"imports":{"/js/lib/hello.js":"/js/lib/hello.js","/js/lib/lazy.js":"/js/lib/lazy.js","/js/main.js":"/js/main.js","/js/util.js":"/js/util.js","lit":"https://esm.sh/lit@2.4.0"}}|`,
+		"Fingerprinted: /js/lib/lazy.82b3be2ddd6c2864c7bf73ce4975b16bcc6786ddc44b5ce388eba1d3f299c107.js|",
+	)
+	b.AssertFileContent("public/js/main.js", `import { hello } from "/js/lib/hello.js";`, `import("/js/lib/lazy.js")`, `const msg = hello();`)
+	b.AssertFileContent("public/js/lib/hello.js", `export * from "/js/util.js";`)
+	b.AssertFileContent("public/js/util.js", `import { hello } from "/js/lib/hello.js";`)
+}
@@ -0,0 +1,256 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package js
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/evanw/esbuild/pkg/api"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/mitchellh/mapstructure"
+)
+
+// ModulesOptions configures js.Modules.
+type ModulesOptions struct {
+	// Whether to minify the modules.
+	Minify bool
+
+	// The language target, see Options.
+	Target string
+
+	// Whether to add the content hash to the module filenames.
+	// Default is true.
+	Fingerprint bool
+
+	// Maps bare imports not found in /assets to URLs, added to the
+	// generated import map, e.g. "lodash" to "https://esm.sh/lodash@4.17.21".
+	ImportMap map[string]string
+}
+
+func decodeModulesOptions(m map[string]any) (ModulesOptions, error) {
+	opts := ModulesOptions{Fingerprint: true}
+
+	if err := mapstructure.WeakDecode(m, &opts); err != nil {
+		return opts, err
+	}
+
+	opts.Target = strings.ToLower(opts.Target)
+
+	return opts, nil
+}
+
+// Modules holds the native ES modules published by js.Modules.
+type Modules struct {
+	// The entry module.
+	Entry resource.Resource
+
+	// All published modules, the entry first.
+	Resources resource.Resources
+
+	// The import map as JSON, mapping the local module paths, e.g.
+	// "/js/util.js", and the configured bare imports to their URLs.
+	ImportMap string
+}
+
+// Matches the module specifiers in the esbuild output, which always uses
+// double quotes, e.g. import { a } from "./a.js", export * from "./b" and
+// import("./c").
+var moduleSpecifierRe = regexp.MustCompile(`((?:^|[;}\s])(?:import|export)\b[^"'();]*?\bfrom\s*|(?:^|[;}\s])import\s*\(?\s*)"([^"\n]+)"`)
+
+// Modules publishes r and the modules it imports from /assets as native ES
+// modules, without bundling them.
+// Local imports are rewritten to the module paths in the import map, so
+// the modules can be fingerprinted independently of each other.
+func (c *Client) Modules(r resource.Resource, optsm map[string]any) (*Modules, error) {
+	opts, err := decodeModulesOptions(optsm)
+	if err != nil {
+		return nil, err
+	}
+
+	rsr, ok := r.(resource.ReadSeekCloserResource)
+	if !ok {
+		return nil, fmt.Errorf("%T can not be used in js.Modules", r)
+	}
+	rc, err := rsr.ReadSeekCloser()
+	if err != nil {
+		return nil, err
+	}
+	src, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	importMap := make(map[string]string)
+	for k, v := range opts.ImportMap {
+		importMap[k] = v
+	}
+
+	ms := &Modules{}
+	seen := make(map[string]bool)
+
+	var publish func(name string, src []byte) (resource.Resource, error)
+	publish = func(name string, src []byte) (resource.Resource, error) {
+		seen[name] = true
+
+		var deps []string
+		content, err := c.transformModule(name, string(src), opts, func(imp string) (string, error) {
+			dep, found := c.resolveModule(name, imp)
+			if !found {
+				if strings.HasPrefix(imp, ".") {
+					return "", fmt.Errorf("%s: import %q not found", name, imp)
+				}
+				// A bare import resolved by the import map in the browser.
+				return imp, nil
+			}
+			deps = append(deps, dep)
+			return moduleKey(dep), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		targetPath := toJSPath(name)
+		if opts.Fingerprint {
+			sum := sha256.Sum256([]byte(content))
+			ext := path.Ext(targetPath)
+			targetPath = strings.TrimSuffix(targetPath, ext) + "." + hex.EncodeToString(sum[:]) + ext
+		}
+
+		mr, err := c.createClient.FromString(targetPath, content)
+		if err != nil {
+			return nil, err
+		}
+		ms.Resources = append(ms.Resources, mr)
+		importMap[moduleKey(name)] = mr.RelPermalink()
+
+		for _, dep := range deps {
+			if seen[dep] {
+				continue
+			}
+			b, err := c.readModule(dep)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := publish(dep, b); err != nil {
+				return nil, err
+			}
+		}
+
+		return mr, nil
+	}
+
+	if ms.Entry, err = publish(helpers.ToSlashTrimLeading(r.Name()), src); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(map[string]any{"imports": importMap})
+	if err != nil {
+		return nil, err
+	}
+	ms.ImportMap = string(b)
+
+	return ms, nil
+}
+
+// moduleKey returns the import map key of the module with the given path
+// relative to /assets.
+func moduleKey(name string) string {
+	return "/" + toJSPath(name)
+}
+
+// toJSPath replaces the extension of name, e.g. ".ts", with ".js".
+func toJSPath(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name)) + ".js"
+}
+
+// resolveModule resolves imp imported in the module name to the path of a
+// module relative to /assets.
+func (c *Client) resolveModule(name, imp string) (string, bool) {
+	impPath := filepath.FromSlash(imp)
+	if strings.HasPrefix(imp, ".") {
+		impPath = filepath.Join(filepath.FromSlash(path.Dir(name)), impPath)
+	}
+
+	m := resolveComponentInAssets(c.rs.Assets.Fs, impPath)
+	if m == nil {
+		return "", false
+	}
+
+	rel, found := c.rs.Assets.MakePathRelative(m.Filename)
+	if !found {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
+
+func (c *Client) readModule(name string) ([]byte, error) {
+	f, err := c.rs.Assets.Fs.Open(filepath.FromSlash(name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ioutil.ReadAll(f)
+}
+
+// transformModule transforms src, e.g. from TypeScript, to an ES module and
+// rewrites its module specifiers using rewrite.
+func (c *Client) transformModule(name, src string, opts ModulesOptions, rewrite func(imp string) (string, error)) (string, error) {
+	target, err := toTarget(opts.Target)
+	if err != nil {
+		return "", err
+	}
+
+	result := api.Transform(src, api.TransformOptions{
+		Loader:            loaderFromFilename(name),
+		Format:            api.FormatESModule,
+		Target:            target,
+		MinifyWhitespace:  opts.Minify,
+		MinifyIdentifiers: opts.Minify,
+		MinifySyntax:      opts.Minify,
+		Sourcefile:        name,
+	})
+
+	if len(result.Errors) > 0 {
+		msg := result.Errors[0]
+		if loc := msg.Location; loc != nil {
+			return "", fmt.Errorf("%s:%d:%d: %s", name, loc.Line, loc.Column, msg.Text)
+		}
+		return "", errors.New(msg.Text)
+	}
+
+	var rewriteErr error
+	content := moduleSpecifierRe.ReplaceAllStringFunc(string(result.Code), func(s string) string {
+		m := moduleSpecifierRe.FindStringSubmatch(s)
+		imp, err := rewrite(m[2])
+		if err != nil {
+			rewriteErr = err
+			return s
+		}
+		return m[1] + `"` + imp + `"`
+	})
+
+	return content, rewriteErr
+}
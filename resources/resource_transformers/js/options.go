@@ -75,6 +75,16 @@ type Options struct {
 	// Maps a component import to another.
 	Shims map[string]string
 
+	// Maps bare imports not found in /assets to URLs, e.g.
+	// "lodash" to "https://esm.sh/lodash@4.17.21". Keys ending with a slash
+	// map all imports with that prefix.
+	ImportMap map[string]string
+
+	// The CDN to fetch the remaining bare imports from, esm.sh, jsdelivr or
+	// a URL prefix. The remote modules are cached and locked to their
+	// hashes in hugo_js.lock.json.
+	CDN string
+
 	// User defined params. Will be marshaled to JSON and available as "@params", e.g.
 	//     import * as params from '@params';
 	Params any
@@ -296,30 +306,40 @@ func createBuildPlugins(c *Client, opts Options) ([]api.Plugin, error) {
 		},
 	}
 
-	return []api.Plugin{importResolver, paramsPlugin}, nil
+	plugins := []api.Plugin{importResolver, paramsPlugin}
+	if opts.ImportMap != nil || opts.CDN != "" {
+		plugins = append(plugins, createRemotePlugin(c, opts))
+	}
+
+	return plugins, nil
 }
 
-func toBuildOptions(opts Options) (buildOptions api.BuildOptions, err error) {
-	var target api.Target
-	switch opts.Target {
+func toTarget(s string) (api.Target, error) {
+	switch s {
 	case "", "esnext":
-		target = api.ESNext
+		return api.ESNext, nil
 	case "es5":
-		target = api.ES5
+		return api.ES5, nil
 	case "es6", "es2015":
-		target = api.ES2015
+		return api.ES2015, nil
 	case "es2016":
-		target = api.ES2016
+		return api.ES2016, nil
 	case "es2017":
-		target = api.ES2017
+		return api.ES2017, nil
 	case "es2018":
-		target = api.ES2018
+		return api.ES2018, nil
 	case "es2019":
-		target = api.ES2019
+		return api.ES2019, nil
 	case "es2020":
-		target = api.ES2020
+		return api.ES2020, nil
 	default:
-		err = fmt.Errorf("invalid target: %q", opts.Target)
+		return 0, fmt.Errorf("invalid target: %q", s)
+	}
+}
+
+func toBuildOptions(opts Options) (buildOptions api.BuildOptions, err error) {
+	target, err := toTarget(opts.Target)
+	if err != nil {
 		return
 	}
 
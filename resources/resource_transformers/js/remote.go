@@ -0,0 +1,266 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package js
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/evanw/esbuild/pkg/api"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/spf13/afero"
+)
+
+const (
+	nsRemote = "ns-remote"
+
+	// LockFilename is the name of the lock file in the project root with the
+	// integrity hashes of the remote modules imported in js.Build.
+	LockFilename = "hugo_js.lock.json"
+)
+
+// The CDN shorthands, mapping a bare import, e.g. "react@18", to its URL.
+var cdns = map[string]func(imp string) string{
+	"esm.sh": func(imp string) string {
+		return "https://esm.sh/" + imp
+	},
+	"jsdelivr": func(imp string) string {
+		return "https://cdn.jsdelivr.net/npm/" + imp + "/+esm"
+	},
+}
+
+// isBareImport reports whether imp is a bare import, e.g. "react" or
+// "lodash/debounce", as opposed to a relative, absolute or URL import.
+func isBareImport(imp string) bool {
+	if imp == "" || strings.HasPrefix(imp, ".") || strings.HasPrefix(imp, "/") {
+		return false
+	}
+	return !strings.Contains(imp, "://")
+}
+
+// resolveImportMap resolves imp using the import map m, where keys ending
+// with a slash match all imports with that prefix, longest prefix first.
+func resolveImportMap(m map[string]string, imp string) (string, bool) {
+	if u, found := m[imp]; found {
+		return u, true
+	}
+
+	var best string
+	for k := range m {
+		if strings.HasSuffix(k, "/") && strings.HasPrefix(imp, k) && len(k) > len(best) {
+			best = k
+		}
+	}
+	if best == "" {
+		return "", false
+	}
+
+	return m[best] + strings.TrimPrefix(imp, best), true
+}
+
+// resolveRemote resolves the bare import imp to a URL using the import map
+// and the CDN in opts.
+func resolveRemote(opts Options, imp string) (string, bool, error) {
+	if u, found := resolveImportMap(opts.ImportMap, imp); found {
+		return u, true, nil
+	}
+
+	if opts.CDN == "" {
+		return "", false, nil
+	}
+
+	if cdn, found := cdns[strings.ToLower(opts.CDN)]; found {
+		return cdn(imp), true, nil
+	}
+
+	if !strings.Contains(opts.CDN, "://") {
+		return "", false, fmt.Errorf("invalid CDN %q, must be one of esm.sh, jsdelivr or a URL", opts.CDN)
+	}
+
+	return strings.TrimSuffix(opts.CDN, "/") + "/" + imp, true, nil
+}
+
+func createRemotePlugin(c *Client, opts Options) api.Plugin {
+	return api.Plugin{
+		Name: "hugo-remote-resolver",
+		Setup: func(build api.PluginBuild) {
+			build.OnResolve(api.OnResolveOptions{Filter: `.*`},
+				func(args api.OnResolveArgs) (api.OnResolveResult, error) {
+					if args.Namespace == nsRemote && !isBareImport(args.Path) {
+						// Relative or absolute import in a remote module,
+						// e.g. "/v86/react@18.2.0/es2022/react.mjs".
+						base, err := url.Parse(args.Importer)
+						if err != nil {
+							return api.OnResolveResult{}, err
+						}
+						ref, err := url.Parse(args.Path)
+						if err != nil {
+							return api.OnResolveResult{}, err
+						}
+						return api.OnResolveResult{Path: base.ResolveReference(ref).String(), Namespace: nsRemote}, nil
+					}
+
+					if strings.HasPrefix(args.Path, "https://") || strings.HasPrefix(args.Path, "http://") {
+						return api.OnResolveResult{Path: args.Path, Namespace: nsRemote}, nil
+					}
+
+					if !isBareImport(args.Path) {
+						return api.OnResolveResult{}, nil
+					}
+
+					u, found, err := resolveRemote(opts, args.Path)
+					if err != nil || !found {
+						return api.OnResolveResult{}, err
+					}
+
+					return api.OnResolveResult{Path: u, Namespace: nsRemote}, nil
+				})
+			build.OnLoad(api.OnLoadOptions{Filter: `.*`, Namespace: nsRemote},
+				func(args api.OnLoadArgs) (api.OnLoadResult, error) {
+					b, err := c.fetchRemote(args.Path)
+					if err != nil {
+						return api.OnLoadResult{}, err
+					}
+					contents := string(b)
+					loader := api.LoaderJS
+					if u, err := url.Parse(args.Path); err == nil {
+						if l, found := extensionToLoaderMap[path.Ext(u.Path)]; found {
+							loader = l
+						}
+					}
+					return api.OnLoadResult{
+						Contents: &contents,
+						Loader:   loader,
+					}, nil
+				})
+		},
+	}
+}
+
+// fetchRemote fetches the module at uri from the file cache or the network
+// and verifies it against the lock file.
+func (c *Client) fetchRemote(uri string) ([]byte, error) {
+	_, b, err := c.rs.FileCaches.GetResourceCache().GetOrCreateBytes(helpers.HashString("jsbuild", uri), func() ([]byte, error) {
+		res, err := c.httpClient.Get(uri)
+		if err != nil {
+			return nil, err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return nil, fmt.Errorf("failed to fetch remote module %q: %s", uri, http.StatusText(res.StatusCode))
+		}
+
+		return ioutil.ReadAll(res.Body)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.lock.verify(c.rs.BaseFs.SourceFs, uri, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// lockFile holds the integrity hashes of the remote modules by URL.
+type lockFile struct {
+	filename string
+
+	mu      sync.Mutex
+	loaded  bool
+	changed bool
+	hashes  map[string]string
+}
+
+func (l *lockFile) load(fs afero.Fs) error {
+	if l.loaded {
+		return nil
+	}
+	l.loaded = true
+	l.hashes = make(map[string]string)
+
+	b, err := afero.ReadFile(fs, l.filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := json.Unmarshal(b, &l.hashes); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", LockFilename, err)
+	}
+
+	return nil
+}
+
+// verify checks b against the hash of uri in the lock file, adding it if
+// not present.
+func (l *lockFile) verify(fs afero.Fs, uri string, b []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.load(fs); err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(b)
+	hash := "sha256-" + base64.StdEncoding.EncodeToString(sum[:])
+
+	if locked, found := l.hashes[uri]; found {
+		if locked != hash {
+			return fmt.Errorf("remote module %q does not match the hash in %s; remove the entry to update it", uri, LockFilename)
+		}
+		return nil
+	}
+
+	l.hashes[uri] = hash
+	l.changed = true
+
+	return nil
+}
+
+// save writes the lock file if any hashes were added.
+func (l *lockFile) save(fs afero.Fs) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.changed {
+		return nil
+	}
+
+	// Map keys are sorted, which gives stable diffs.
+	b, err := json.MarshalIndent(l.hashes, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := afero.WriteFile(fs, l.filename, append(b, '\n'), 0666); err != nil {
+		return err
+	}
+	l.changed = false
+
+	return nil
+}
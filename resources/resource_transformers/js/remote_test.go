@@ -0,0 +1,82 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package js
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/spf13/afero"
+)
+
+func TestResolveRemote(t *testing.T) {
+	c := qt.New(t)
+
+	opts := Options{
+		ImportMap: map[string]string{
+			"lit":        "https://esm.sh/lit@2.4.0",
+			"lit/":       "https://esm.sh/lit@2.4.0/",
+			"lit/logic/": "https://example.org/logic/",
+		},
+	}
+
+	for _, test := range []struct {
+		imp    string
+		cdn    string
+		expect string
+	}{
+		{"lit", "", "https://esm.sh/lit@2.4.0"},
+		{"lit/html.js", "", "https://esm.sh/lit@2.4.0/html.js"},
+		{"lit/logic/when.js", "", "https://example.org/logic/when.js"},
+		{"react", "", ""},
+		{"react", "esm.sh", "https://esm.sh/react"},
+		{"react@18", "jsDelivr", "https://cdn.jsdelivr.net/npm/react@18/+esm"},
+		{"react", "https://cdn.example.org/", "https://cdn.example.org/react"},
+		{"lit", "esm.sh", "https://esm.sh/lit@2.4.0"},
+	} {
+		opts.CDN = test.cdn
+		u, found, err := resolveRemote(opts, test.imp)
+		c.Assert(err, qt.IsNil)
+		c.Assert(found, qt.Equals, test.expect != "", qt.Commentf(test.imp))
+		c.Assert(u, qt.Equals, test.expect)
+	}
+
+	opts.CDN = "unpkg"
+	_, _, err := resolveRemote(opts, "react")
+	c.Assert(err, qt.ErrorMatches, `invalid CDN "unpkg".*`)
+
+	c.Assert(isBareImport("react"), qt.IsTrue)
+	c.Assert(isBareImport("@scope/pkg"), qt.IsTrue)
+	c.Assert(isBareImport("./react"), qt.IsFalse)
+	c.Assert(isBareImport("/v86/react.mjs"), qt.IsFalse)
+	c.Assert(isBareImport("https://esm.sh/react"), qt.IsFalse)
+}
+
+func TestLockFile(t *testing.T) {
+	c := qt.New(t)
+
+	fs := afero.NewMemMapFs()
+	l := &lockFile{filename: LockFilename}
+
+	c.Assert(l.verify(fs, "https://esm.sh/a", []byte("a")), qt.IsNil)
+	c.Assert(l.verify(fs, "https://esm.sh/a", []byte("a")), qt.IsNil)
+	c.Assert(l.save(fs), qt.IsNil)
+
+	b, err := afero.ReadFile(fs, LockFilename)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(b), qt.Equals, "{\n  \"https://esm.sh/a\": \"sha256-ypeBEsobvcr6wjGzmiPcTaeG7/gUfE5yuYB3ha/uSLs=\"\n}\n")
+
+	l = &lockFile{filename: LockFilename}
+	c.Assert(l.verify(fs, "https://esm.sh/a", []byte("b")), qt.ErrorMatches, `remote module "https://esm.sh/a" does not match the hash in hugo_js.lock.json.*`)
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Runs PostCSS and Babel for Hugo. Reads one JSON request per line from
+// stdin and writes one JSON response per line to stdout.
+'use strict';
+
+const fs = require('fs');
+const path = require('path');
+const readline = require('readline');
+const { createRequire } = require('module');
+
+// Resolve the tools and plugins from the project, as npx does.
+const projectRequire = createRequire(path.join(process.cwd(), 'package.json'));
+
+// Stdout is reserved for the protocol.
+console.log = console.info = console.debug = console.warn = console.error;
+
+// Loads the config file, reloading it when it has changed.
+const configs = new Map();
+function loadConfig(filename) {
+	const mtime = fs.statSync(filename).mtimeMs;
+	const cached = configs.get(filename);
+	if (cached && cached.mtime === mtime) {
+		return cached.config;
+	}
+	delete require.cache[require.resolve(filename)];
+	const config = require(filename);
+	configs.set(filename, { mtime, config });
+	return config;
+}
+
+function requireOption(v) {
+	return typeof v === 'string' ? projectRequire(v) : v;
+}
+
+async function runPostCSS(req) {
+	const postcss = projectRequire('postcss');
+	const opts = req.options || {};
+
+	let plugins = [];
+	let config = {};
+	if (opts.Use) {
+		plugins = opts.Use.split(/\s+/)
+			.filter(Boolean)
+			.map((name) => projectRequire(name)());
+	} else if (req.configFile) {
+		config = loadConfig(req.configFile);
+		if (typeof config === 'function') {
+			config = config({ env: process.env.HUGO_ENVIRONMENT, file: { dirname: path.dirname(req.filename) } });
+		}
+		if (Array.isArray(config.plugins)) {
+			plugins = config.plugins.filter(Boolean);
+		} else if (config.plugins) {
+			plugins = Object.entries(config.plugins)
+				.filter(([, o]) => o !== false)
+				.map(([name, o]) => projectRequire(name)(o === true ? undefined : o));
+		}
+	}
+
+	const result = await postcss(plugins).process(req.input, {
+		from: req.filename,
+		map: opts.NoMap ? false : { inline: true },
+		parser: requireOption(opts.Parser || config.parser),
+		stringifier: requireOption(opts.Stringifier || config.stringifier),
+		syntax: requireOption(opts.Syntax || config.syntax),
+	});
+
+	for (const warning of result.warnings()) {
+		console.error(warning.toString());
+	}
+
+	return { code: result.css };
+}
+
+async function runBabel(req) {
+	const babel = projectRequire('@babel/core');
+	const opts = req.options || {};
+
+	let sourceMaps = false;
+	if (opts.SourceMap === 'inline') {
+		sourceMaps = 'inline';
+	} else if (opts.SourceMap === 'external') {
+		sourceMaps = true;
+	}
+
+	const result = await babel.transformAsync(req.input, {
+		filename: req.filename,
+		configFile: req.configFile || undefined,
+		babelrc: !opts.NoBabelrc,
+		sourceMaps: sourceMaps,
+		minified: opts.Minified,
+		comments: !opts.NoComments,
+		compact: opts.Compact === null || opts.Compact === undefined ? 'auto' : opts.Compact,
+	});
+
+	return { code: result.code, map: result.map ? JSON.stringify(result.map) : '' };
+}
+
+const tools = { postcss: runPostCSS, babel: runBabel };
+
+function errorMessage(err) {
+	if (err && err.name === 'CssSyntaxError') {
+		return `${err.name}: ${err.message}\n\n${err.showSourceCode(false)}`;
+	}
+	return String((err && err.stack) || err);
+}
+
+const rl = readline.createInterface({ input: process.stdin, terminal: false });
+rl.on('line', async (line) => {
+	const req = JSON.parse(line);
+	let res;
+	try {
+		const tool = tools[req.tool];
+		if (!tool) {
+			throw new Error(`unknown tool: ${req.tool}`);
+		}
+		res = await tool(req);
+	} catch (err) {
+		res = { error: errorMessage(err) };
+	}
+	res.id = req.id;
+	process.stdout.write(JSON.stringify(res) + '\n');
+});
@@ -0,0 +1,107 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodedaemon_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+// Uses stand-ins for PostCSS and Babel to test the protocol.
+const daemonTestFiles = `
+-- config.toml --
+disableKinds = ['taxonomy', 'term', 'page', 'section', 'sitemap', 'robotsTXT']
+[build]
+useNodeDaemon = true
+[security.exec]
+allow = ['^node$']
+-- assets/css/main.css --
+body { color: red; }
+-- assets/css/other.css --
+p { color: blue; }
+-- assets/js/main.js --
+const a = 1;
+-- postcss.config.js --
+module.exports = {
+	plugins: { upper: { suffix: '!' } },
+};
+-- node_modules/upper/index.js --
+module.exports = (opts) => (css) => css.toUpperCase() + opts.suffix;
+-- node_modules/postcss/index.js --
+module.exports = (plugins) => ({
+	process: async (css, opts) => {
+		if (css.includes('fail')) {
+			throw new Error('postcss failed');
+		}
+		for (const plugin of plugins) {
+			css = plugin(css);
+		}
+		console.log('this goes to stderr');
+		return { css: css + '/* ' + opts.from + ' map: ' + !!opts.map + ' */', warnings: () => [] };
+	},
+});
+-- node_modules/@babel/core/index.js --
+exports.transformAsync = async (code, opts) => ({
+	code: code.replace('const', 'var') + '// ' + opts.filename + ' comments: ' + opts.comments,
+	map: opts.sourceMaps === true ? { version: 3 } : null,
+});
+-- layouts/index.html --
+{{ $css := resources.Get "css/main.css" | postCSS }}
+CSS: {{ $css.Content | safeCSS }}|
+{{ $css := resources.Get "css/other.css" | postCSS (dict "noMap" true) }}
+CSS2: {{ $css.Content | safeCSS }}|
+{{ $js := resources.Get "js/main.js" | babel (dict "noComments" true "sourceMap" "external") }}
+JS: {{ $js.Content | safeJS }}|
+`
+
+func TestNodeDaemon(t *testing.T) {
+	if !hexec.InPath("node") {
+		t.Skip("node not found")
+	}
+
+	c := qt.New(t)
+
+	b := hugolib.NewIntegrationTestBuilder(hugolib.IntegrationTestConfig{T: c, NeedsOsFS: true, TxtarString: daemonTestFiles}).Build()
+
+	b.AssertFileContent("public/index.html",
+		"CSS: BODY { COLOR: RED; }\n!/* css/main.css map: true */|",
+		"CSS2: P { COLOR: BLUE; }\n!/* css/other.css map: false */|",
+		"JS: var a = 1;\n// js/main.js comments: false\n//# sourceMappingURL=main.js.map\n|",
+	)
+	b.AssertFileContent("public/js/main.js.map", `{"version":3}`)
+}
+
+func TestNodeDaemonError(t *testing.T) {
+	if !hexec.InPath("node") {
+		t.Skip("node not found")
+	}
+
+	c := qt.New(t)
+
+	files := daemonTestFiles + `
+-- assets/css/fail.css --
+fail
+-- layouts/index.html --
+{{ $css := resources.Get "css/fail.css" | postCSS }}
+CSS: {{ $css.Content | safeCSS }}|
+`
+
+	b, err := hugolib.NewIntegrationTestBuilder(hugolib.IntegrationTestConfig{T: c, NeedsOsFS: true, TxtarString: files}).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, "postcss failed")
+}
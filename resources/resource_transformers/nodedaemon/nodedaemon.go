@@ -0,0 +1,231 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nodedaemon runs PostCSS and Babel in one long-running Node.js
+// process, started on first use and communicated with over stdio, instead of
+// starting Node.js for every resource.
+package nodedaemon
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/gohugoio/hugo/common/hexec"
+	"github.com/gohugoio/hugo/common/hugo"
+	"github.com/gohugoio/hugo/common/loggers"
+	"github.com/gohugoio/hugo/resources"
+)
+
+const binaryName = "node"
+
+//go:embed daemon.js
+var daemonScript []byte
+
+// Request is a request to run one of the tools.
+type Request struct {
+	ID uint64 `json:"id"`
+
+	// One of postcss or babel.
+	Tool string `json:"tool"`
+
+	// The source to process.
+	Input string `json:"input"`
+
+	// The source filename, used in error messages and source maps.
+	Filename string `json:"filename"`
+
+	// The absolute filename of the tool's config file, if any.
+	ConfigFile string `json:"configFile"`
+
+	// The tool's options, i.e. postcss.Options or babel.Options.
+	Options any `json:"options"`
+}
+
+// Result is the result of a Request.
+type Result struct {
+	ID uint64 `json:"id"`
+
+	// The processed source.
+	Code string `json:"code"`
+
+	// The external source map, if requested.
+	Map string `json:"map"`
+
+	// Set if the tool failed.
+	Error string `json:"error"`
+}
+
+// Daemon runs the Node.js process.
+type Daemon struct {
+	rs *resources.Spec
+
+	startInit sync.Once
+	startErr  error
+
+	cmd        hexec.Runner
+	scriptFile string
+
+	mu      sync.Mutex
+	stdin   io.WriteCloser
+	id      uint64
+	pending map[uint64]chan Result
+	closed  bool
+}
+
+// New creates a new Daemon. The Node.js process is started on first use.
+func New(rs *resources.Spec) *Daemon {
+	return &Daemon{rs: rs, pending: make(map[uint64]chan Result)}
+}
+
+func (d *Daemon) start() error {
+	d.startInit.Do(func() {
+		d.startErr = d.doStart()
+	})
+	return d.startErr
+}
+
+func (d *Daemon) doStart() error {
+	ex := d.rs.ExecHelper
+	if err := ex.Sec().CheckAllowedExec(binaryName); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp("", "hugo-nodedaemon-*.js")
+	if err != nil {
+		return err
+	}
+	d.scriptFile = f.Name()
+	_, err = f.Write(daemonScript)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	infoW := loggers.LoggerToWriterWithPrefix(d.rs.Logger.Info(), "node")
+
+	cmd, err := ex.New(binaryName, d.scriptFile,
+		hexec.WithDir(d.rs.WorkingDir),
+		hexec.WithStderr(infoW),
+		hexec.WithEnviron(hugo.GetExecEnviron(d.rs.WorkingDir, d.rs.Cfg, d.rs.BaseFs.Assets.Fs)),
+	)
+	if err != nil {
+		return err
+	}
+
+	if d.stdin, err = cmd.StdinPipe(); err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	d.cmd = cmd
+
+	go d.readResults(stdout)
+
+	return nil
+}
+
+func (d *Daemon) readResults(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 512*1024*1024)
+	for scanner.Scan() {
+		var res Result
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			d.rs.Logger.Errorf("nodedaemon: failed to decode result: %s", err)
+			continue
+		}
+		d.mu.Lock()
+		ch, found := d.pending[res.ID]
+		delete(d.pending, res.ID)
+		d.mu.Unlock()
+		if found {
+			ch <- res
+		}
+	}
+
+	// The process has exited. Fail the requests waiting for a result.
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	for id, ch := range d.pending {
+		ch <- Result{ID: id, Error: "the Node.js process exited unexpectedly"}
+		delete(d.pending, id)
+	}
+}
+
+// Execute sends req to the daemon and waits for its result.
+// If the tool fails, the error message is returned as an error.
+func (d *Daemon) Execute(req Request) (Result, error) {
+	if err := d.start(); err != nil {
+		return Result{}, err
+	}
+
+	ch := make(chan Result, 1)
+
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return Result{}, errors.New("the Node.js process is closed")
+	}
+	d.id++
+	req.ID = d.id
+	d.pending[req.ID] = ch
+
+	b, err := json.Marshal(req)
+	if err == nil {
+		_, err = d.stdin.Write(append(b, '\n'))
+	}
+	if err != nil {
+		delete(d.pending, req.ID)
+		d.mu.Unlock()
+		return Result{}, fmt.Errorf("failed to send request to Node.js: %w", err)
+	}
+	d.mu.Unlock()
+
+	res := <-ch
+	if res.Error != "" {
+		return res, errors.New(res.Error)
+	}
+
+	return res, nil
+}
+
+// Close stops the Node.js process, if started.
+func (d *Daemon) Close() error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	if d.scriptFile != "" {
+		defer os.Remove(d.scriptFile)
+	}
+
+	if d.cmd == nil {
+		return nil
+	}
+
+	d.stdin.Close()
+
+	return d.cmd.Wait()
+}
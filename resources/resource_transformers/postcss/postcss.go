@@ -29,6 +29,7 @@ import (
 	"github.com/gohugoio/hugo/common/collections"
 	"github.com/gohugoio/hugo/common/hexec"
 	"github.com/gohugoio/hugo/common/text"
+	"github.com/gohugoio/hugo/helpers"
 	"github.com/gohugoio/hugo/hugofs"
 
 	"github.com/gohugoio/hugo/common/hugo"
@@ -46,6 +47,7 @@ import (
 	"github.com/gohugoio/hugo/common/herrors"
 	"github.com/gohugoio/hugo/resources"
 	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/resources/resource_transformers/nodedaemon"
 )
 
 const importIdentifier = "@import"
@@ -56,8 +58,9 @@ var (
 )
 
 // New creates a new Client with the given specification.
-func New(rs *resources.Spec) *Client {
-	return &Client{rs: rs}
+// If daemon is set, PostCSS runs in its Node.js process.
+func New(rs *resources.Spec, daemon *nodedaemon.Daemon) *Client {
+	return &Client{rs: rs, daemon: daemon}
 }
 
 func decodeOptions(m map[string]any) (opts Options, err error) {
@@ -78,12 +81,13 @@ func decodeOptions(m map[string]any) (opts Options, err error) {
 
 // Client is the client used to do PostCSS transformations.
 type Client struct {
-	rs *resources.Spec
+	rs     *resources.Spec
+	daemon *nodedaemon.Daemon
 }
 
 // Process transforms the given Resource with the PostCSS processor.
 func (c *Client) Process(res resources.ResourceTransformer, options map[string]any) (resource.Resource, error) {
-	return res.Transform(&postcssTransformation{rs: c.rs, optionsm: options})
+	return res.Transform(&postcssTransformation{rs: c.rs, daemon: c.daemon, optionsm: options})
 }
 
 // Some of the options from https://github.com/postcss/postcss-cli
@@ -139,6 +143,7 @@ func (opts Options) toArgs() []string {
 type postcssTransformation struct {
 	optionsm map[string]any
 	rs       *resources.Spec
+	daemon   *nodedaemon.Daemon
 }
 
 func (t *postcssTransformation) Key() internal.ResourceTransformationKey {
@@ -183,10 +188,48 @@ func (t *postcssTransformation) Transform(ctx *resources.ResourceTransformationC
 		}
 	}
 
-	var cmdArgs []any
+	src := ctx.From
+
+	imp := newImportResolver(
+		ctx.From,
+		ctx.InPath,
+		options,
+		t.rs.Assets.Fs, t.rs.Logger,
+	)
+
+	if options.InlineImports {
+		var err error
+		src, err = imp.resolve()
+		if err != nil {
+			return err
+		}
+	}
 
 	if configFile != "" {
 		logger.Infoln("postcss: use config file", configFile)
+	}
+
+	if t.daemon != nil {
+		res, err := t.daemon.Execute(nodedaemon.Request{
+			Tool:       "postcss",
+			Input:      helpers.ReaderToString(src),
+			Filename:   ctx.SourcePath,
+			ConfigFile: configFile,
+			Options:    options,
+		})
+		if err != nil {
+			if hexec.IsNotFound(err) {
+				return herrors.ErrFeatureNotAvailable
+			}
+			return imp.toFileError(err.Error())
+		}
+		_, err = io.WriteString(ctx.To, res.Code)
+		return err
+	}
+
+	var cmdArgs []any
+
+	if configFile != "" {
 		cmdArgs = []any{"--config", configFile}
 	}
 
@@ -216,23 +259,6 @@ func (t *postcssTransformation) Transform(ctx *resources.ResourceTransformationC
 		return err
 	}
 
-	src := ctx.From
-
-	imp := newImportResolver(
-		ctx.From,
-		ctx.InPath,
-		options,
-		t.rs.Assets.Fs, t.rs.Logger,
-	)
-
-	if options.InlineImports {
-		var err error
-		src, err = imp.resolve()
-		if err != nil {
-			return err
-		}
-	}
-
 	go func() {
 		defer stdin.Close()
 		io.Copy(stdin, src)
@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/media"
 	"github.com/gohugoio/hugo/resources"
 	"github.com/gohugoio/hugo/resources/internal"
 	"github.com/gohugoio/hugo/resources/resource"
@@ -45,11 +46,15 @@ type executeAsTemplateTransform struct {
 	rs         *resources.Spec
 	t          tpl.TemplatesProvider
 	targetPath string
+	mediaType  media.Type
 	data       any
 }
 
 func (t *executeAsTemplateTransform) Key() internal.ResourceTransformationKey {
-	return internal.NewResourceTransformationKey("execute-as-template", t.targetPath)
+	if t.mediaType.IsZero() {
+		return internal.NewResourceTransformationKey("execute-as-template", t.targetPath)
+	}
+	return internal.NewResourceTransformationKey("execute-as-template", t.targetPath, t.mediaType.Type())
 }
 
 func (t *executeAsTemplateTransform) Transform(ctx *resources.ResourceTransformationCtx) error {
@@ -60,14 +65,20 @@ func (t *executeAsTemplateTransform) Transform(ctx *resources.ResourceTransforma
 	}
 
 	ctx.OutPath = t.targetPath
+	if !t.mediaType.IsZero() {
+		ctx.OutMediaType = t.mediaType
+	}
 
 	return t.t.Tmpl().Execute(templ, ctx.To, t.data)
 }
 
-func (c *Client) ExecuteAsTemplate(res resources.ResourceTransformer, targetPath string, data any) (resource.Resource, error) {
+// ExecuteAsTemplate executes res as a template with data and publishes the
+// result to targetPath. If mediaType is zero, the media type of res is kept.
+func (c *Client) ExecuteAsTemplate(res resources.ResourceTransformer, targetPath string, mediaType media.Type, data any) (resource.Resource, error) {
 	return res.Transform(&executeAsTemplateTransform{
 		rs:         c.rs,
 		targetPath: helpers.ToSlashTrimLeading(targetPath),
+		mediaType:  mediaType,
 		t:          c.t,
 		data:       data,
 	})
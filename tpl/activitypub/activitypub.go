@@ -0,0 +1,330 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package activitypub provides template functions to create the
+// ActivityStreams and WebFinger documents of a static ActivityPub actor.
+package activitypub
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+// The names of the ActivityPub output formats, see the output package.
+const (
+	actorFormatName  = "ActivityPub"
+	outboxFormatName = "ActivityPubOutbox"
+)
+
+const (
+	activityStreamsContext = "https://www.w3.org/ns/activitystreams"
+	securityContext        = "https://w3id.org/security/v1"
+	publicAddress          = "https://www.w3.org/ns/activitystreams#Public"
+)
+
+// DefaultConfig holds the default ActivityPub configuration.
+var DefaultConfig = Config{
+	Type:       "Person",
+	Inbox:      "/inbox",
+	ObjectType: "Article",
+	Limit:      20,
+}
+
+// Config configures the site's ActivityPub actor, e.g.:
+//
+//	[activityPub]
+//	username = "blog"
+//	icon = "/images/avatar.png"
+type Config struct {
+	// The preferred username of the actor, e.g. blog for @blog@example.org.
+	Username string
+
+	// The actor type, e.g. Person, Service or Organization.
+	Type string
+
+	// The name and summary of the actor, defaulting to the site title and
+	// the description param.
+	Name    string
+	Summary string
+
+	// The URL of the avatar, relative to baseURL unless absolute.
+	Icon string
+
+	// The URLs of the inbox and the followers collection, relative to
+	// baseURL unless absolute. These must be served by a service, as a
+	// static site can not receive follows.
+	Inbox     string
+	Followers string
+
+	// The public key of the actor in PEM format, used by the service
+	// handling the inbox to sign its requests.
+	PublicKey string
+
+	// The type of the objects created from the pages, e.g. Article or Note.
+	ObjectType string
+
+	// The maximum number of activities in the outbox, 0 for all.
+	Limit int
+}
+
+// DecodeConfig decodes the activityPub section in site config.
+func DecodeConfig(in any) (Config, error) {
+	c := DefaultConfig
+	if in == nil {
+		return c, nil
+	}
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode activityPub config: %w", err)
+	}
+	return c, nil
+}
+
+// New returns a new instance of the activitypub-namespaced template functions.
+func New(d *deps.Deps) *Namespace {
+	cfg, err := DecodeConfig(d.Cfg.Get("activityPub"))
+	return &Namespace{deps: d, cfg: cfg, cfgErr: err}
+}
+
+// Namespace provides template functions for the "activitypub" namespace.
+type Namespace struct {
+	deps   *deps.Deps
+	cfg    Config
+	cfgErr error
+}
+
+// Actor returns the actor document of the site, rendered by the
+// ActivityPub output format of the home page.
+func (ns *Namespace) Actor() (map[string]any, error) {
+	actorURL, err := ns.actorURL()
+	if err != nil {
+		return nil, err
+	}
+
+	site := ns.deps.Site
+	name := ns.cfg.Name
+	if name == "" {
+		name = site.Title()
+	}
+	summary := ns.cfg.Summary
+	if summary == "" {
+		summary = cast.ToString(site.Params()["description"])
+	}
+
+	actor := map[string]any{
+		"@context":          []string{activityStreamsContext, securityContext},
+		"id":                actorURL,
+		"type":              ns.cfg.Type,
+		"preferredUsername": ns.cfg.Username,
+		"name":              name,
+		"url":               site.Home().Permalink(),
+		"inbox":             ns.absURL(ns.cfg.Inbox),
+	}
+	if summary != "" {
+		actor["summary"] = summary
+	}
+	if outbox := site.Home().OutputFormats().Get(outboxFormatName); outbox != nil {
+		actor["outbox"] = outbox.Permalink()
+	}
+	if ns.cfg.Followers != "" {
+		actor["followers"] = ns.absURL(ns.cfg.Followers)
+	}
+	if ns.cfg.Icon != "" {
+		actor["icon"] = map[string]any{
+			"type": "Image",
+			"url":  ns.absURL(ns.cfg.Icon),
+		}
+	}
+	if ns.cfg.PublicKey != "" {
+		actor["publicKey"] = map[string]any{
+			"id":           actorURL + "#main-key",
+			"owner":        actorURL,
+			"publicKeyPem": strings.TrimSpace(ns.cfg.PublicKey) + "\n",
+		}
+	}
+
+	return actor, nil
+}
+
+// Object returns the ActivityStreams object of p, e.g. an Article.
+func (ns *Namespace) Object(p page.Page) (map[string]any, error) {
+	actorURL, err := ns.actorURL()
+	if err != nil {
+		return nil, err
+	}
+	return ns.object(p, actorURL, true)
+}
+
+func (ns *Namespace) object(p page.Page, actorURL string, withContext bool) (map[string]any, error) {
+	content, err := p.Content()
+	if err != nil {
+		return nil, err
+	}
+
+	obj := map[string]any{
+		"id":           objectURL(p),
+		"type":         ns.cfg.ObjectType,
+		"attributedTo": actorURL,
+		"name":         p.Title(),
+		"content":      cast.ToString(content),
+		"url":          p.Permalink(),
+		"to":           []string{publicAddress},
+	}
+	if withContext {
+		obj["@context"] = activityStreamsContext
+	}
+	if !p.Date().IsZero() {
+		obj["published"] = p.Date().UTC().Format(time.RFC3339)
+	}
+	if !p.Lastmod().IsZero() && !p.Lastmod().Equal(p.Date()) {
+		obj["updated"] = p.Lastmod().UTC().Format(time.RFC3339)
+	}
+	if p.Description() != "" && ns.cfg.ObjectType != "Note" {
+		// Notes with a summary are shown behind a content warning.
+		obj["summary"] = p.Description()
+	}
+	if lang := p.Language().Lang; lang != "" {
+		obj["contentMap"] = map[string]any{lang: obj["content"]}
+	}
+
+	var tags []map[string]any
+	for _, tag := range cast.ToStringSlice(p.Params()["tags"]) {
+		tags = append(tags, map[string]any{
+			"type": "Hashtag",
+			"name": "#" + strings.ReplaceAll(tag, " ", ""),
+		})
+	}
+	if len(tags) > 0 {
+		obj["tag"] = tags
+	}
+
+	return obj, nil
+}
+
+// Outbox returns the outbox collection with a Create activity per page,
+// rendered by the ActivityPubOutbox output format of the home page.
+func (ns *Namespace) Outbox(pages any) (map[string]any, error) {
+	actorURL, err := ns.actorURL()
+	if err != nil {
+		return nil, err
+	}
+
+	pp, err := page.ToPages(pages)
+	if err != nil {
+		return nil, err
+	}
+	total := len(pp)
+	if ns.cfg.Limit > 0 && len(pp) > ns.cfg.Limit {
+		pp = pp[:ns.cfg.Limit]
+	}
+
+	items := make([]map[string]any, 0, len(pp))
+	for _, p := range pp {
+		obj, err := ns.object(p, actorURL, false)
+		if err != nil {
+			return nil, err
+		}
+		activity := map[string]any{
+			"id":     obj["id"].(string) + "#create",
+			"type":   "Create",
+			"actor":  actorURL,
+			"to":     obj["to"],
+			"object": obj,
+		}
+		if published, found := obj["published"]; found {
+			activity["published"] = published
+		}
+		items = append(items, activity)
+	}
+
+	outbox := map[string]any{
+		"@context":     activityStreamsContext,
+		"type":         "OrderedCollection",
+		"totalItems":   total,
+		"orderedItems": items,
+	}
+	if f := ns.deps.Site.Home().OutputFormats().Get(outboxFormatName); f != nil {
+		outbox["id"] = f.Permalink()
+	}
+
+	return outbox, nil
+}
+
+// WebFinger returns the WebFinger document for the actor, rendered by the
+// WebFinger output format of the home page to /.well-known/webfinger.
+func (ns *Namespace) WebFinger() (map[string]any, error) {
+	actorURL, err := ns.actorURL()
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(string(ns.deps.Site.BaseURL()))
+	if err != nil {
+		return nil, err
+	}
+	home := ns.deps.Site.Home().Permalink()
+
+	return map[string]any{
+		"subject": fmt.Sprintf("acct:%s@%s", ns.cfg.Username, u.Hostname()),
+		"aliases": []string{actorURL, home},
+		"links": []map[string]any{
+			{
+				"rel":  "self",
+				"type": "application/activity+json",
+				"href": actorURL,
+			},
+			{
+				"rel":  "http://webfinger.net/rel/profile-page",
+				"type": "text/html",
+				"href": home,
+			},
+		},
+	}, nil
+}
+
+func (ns *Namespace) actorURL() (string, error) {
+	if ns.cfgErr != nil {
+		return "", ns.cfgErr
+	}
+	if ns.cfg.Username == "" {
+		return "", errors.New("activityPub.username must be set in site config")
+	}
+	f := ns.deps.Site.Home().OutputFormats().Get(actorFormatName)
+	if f == nil {
+		return "", errors.New("the ActivityPub output format must be enabled for the home page")
+	}
+	return f.Permalink(), nil
+}
+
+func (ns *Namespace) absURL(s string) string {
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+		return s
+	}
+	return ns.deps.PathSpec.AbsURL(s, false)
+}
+
+// objectURL returns the URL of the ActivityPub document of p if rendered,
+// else the permalink.
+func objectURL(p page.Page) string {
+	if f := p.OutputFormats().Get(actorFormatName); f != nil {
+		return f.Permalink()
+	}
+	return p.Permalink()
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activitypub_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestActivityPub(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+title = "My Blog"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT"]
+[params]
+description = "Posts about Hugo."
+[outputs]
+home = ["HTML", "ActivityPub", "ActivityPubOutbox", "WebFinger"]
+page = ["HTML", "ActivityPub"]
+[activityPub]
+username = "blog"
+icon = "/avatar.png"
+publicKey = """
+-----BEGIN PUBLIC KEY-----
+MIIB
+-----END PUBLIC KEY-----
+"""
+limit = 1
+-- content/posts/first.md --
+---
+title: "First"
+date: 2022-05-01T12:00:00Z
+tags: ["Static Sites"]
+---
+First post.
+-- content/posts/second.md --
+---
+title: "Second"
+date: 2022-06-01T12:00:00Z
+description: "The second one."
+---
+Second post.
+-- layouts/_default/single.html --
+{{ .Title }}
+-- layouts/_default/list.html --
+{{ .Title }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/activity.json",
+		`"id": "https://example.org/activity.json"`,
+		`"type": "Person"`,
+		`"preferredUsername": "blog"`,
+		`"name": "My Blog"`,
+		`"summary": "Posts about Hugo."`,
+		`"inbox": "https://example.org/inbox"`,
+		`"outbox": "https://example.org/outbox.json"`,
+		`"url": "https://example.org/avatar.png"`,
+		`"id": "https://example.org/activity.json#main-key"`,
+		`"publicKeyPem": "-----BEGIN PUBLIC KEY-----\nMIIB\n-----END PUBLIC KEY-----\n"`,
+	)
+
+	b.AssertFileContent("public/posts/first/activity.json",
+		`"id": "https://example.org/posts/first/activity.json"`,
+		`"type": "Article"`,
+		`"attributedTo": "https://example.org/activity.json"`,
+		`"content": "\u003cp\u003eFirst post.\u003c/p\u003e\n"`,
+		`"published": "2022-05-01T12:00:00Z"`,
+		`"url": "https://example.org/posts/first/"`,
+		`"name": "#StaticSites"`,
+		`"https://www.w3.org/ns/activitystreams#Public"`,
+	)
+
+	b.AssertFileContent("public/posts/second/activity.json",
+		`"summary": "The second one."`,
+	)
+
+	b.AssertFileContent("public/outbox.json",
+		`"type": "OrderedCollection"`,
+		`"totalItems": 2`,
+		`"id": "https://example.org/posts/second/activity.json#create"`,
+		`"type": "Create"`,
+	)
+	b.Assert(b.FileContent("public/outbox.json"), qt.Not(qt.Contains), "first")
+
+	b.AssertFileContent("public/.well-known/webfinger",
+		`"subject": "acct:blog@example.org"`,
+		`"href": "https://example.org/activity.json"`,
+		`"type": "application/activity+json"`,
+		`"href": "https://example.org/"`,
+	)
+}
+
+func TestActivityPubMissingUsername(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT"]
+[outputs]
+home = ["HTML", "ActivityPub"]
+-- layouts/index.html --
+Home.
+`
+
+	b, err := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, "activityPub.username must be set")
+}
@@ -196,7 +196,7 @@ func newDeps(cfg config.Provider) *deps.Deps {
 
 	ex := hexec.New(security.DefaultConfig)
 
-	logger := loggers.NewIgnorableLogger(loggers.NewErrorLogger(), "none")
+	logger := loggers.NewIgnorableLogger(loggers.NewErrorLogger(), []string{"none"}, nil)
 	cs, err := helpers.NewContentSpec(cfg, logger, afero.NewMemMapFs(), ex)
 	if err != nil {
 		panic(err)
@@ -0,0 +1,438 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/types"
+	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+type csvOptions struct {
+	// The field delimiter, default , or a tab for .tsv files.
+	Delimiter string
+
+	// Lines starting with this character are ignored when reading.
+	Comment string
+
+	// The columns to write or read, default all.
+	Columns []string
+
+	// Whether the first row is a header row, default true.
+	// Without a header, CSVRead returns the rows as slices.
+	Header bool
+
+	// Convert numbers and booleans when reading.
+	InferTypes bool
+
+	// The number of rows to skip and the max number of rows to read,
+	// 0 for all.
+	Offset int
+	Limit  int
+
+	// The target path of the resource created by CSVWrite.
+	Path string
+}
+
+func decodeCSVOptions(m any) (csvOptions, error) {
+	opts := csvOptions{Header: true, Path: "data.csv"}
+	if m != nil {
+		if err := mapstructure.WeakDecode(m, &opts); err != nil {
+			return opts, fmt.Errorf("failed to decode options: %w", err)
+		}
+	}
+	return opts, nil
+}
+
+// runes returns the delimiter and comment characters, with the delimiter
+// defaulting to a tab for .tsv files.
+func (o csvOptions) runes(filename string) (delimiter, comment rune, err error) {
+	if o.Delimiter == "" {
+		o.Delimiter = ","
+		if path.Ext(filename) == ".tsv" {
+			o.Delimiter = "\t"
+		}
+	}
+	toRune := func(name, s string) (rune, error) {
+		if s == "" {
+			return 0, nil
+		}
+		r := []rune(s)
+		if len(r) != 1 {
+			return 0, fmt.Errorf("%s must be a single character, got %q", name, s)
+		}
+		return r[0], nil
+	}
+	if delimiter, err = toRune("delimiter", o.Delimiter); err != nil {
+		return
+	}
+	comment, err = toRune("comment", o.Comment)
+	return
+}
+
+// CSVWrite creates a CSV resource from data, a slice of maps or structs,
+// with a header row with the columns.
+// You can optionally provide an options map as the first argument with
+// path (the target path, default data.csv), delimiter (default , or tab
+// for .tsv files), columns (default all keys in sorted order) and header
+// (default true).
+func (ns *Namespace) CSVWrite(args ...any) (resource.Resource, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, errors.New("CSVWrite takes 1 or 2 arguments")
+	}
+	var optsm any
+	if len(args) == 2 {
+		optsm = args[0]
+	}
+	opts, err := decodeCSVOptions(optsm)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := toCSVRows(args[len(args)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	columns := opts.Columns
+	if columns == nil {
+		seen := make(map[string]bool)
+		for _, row := range rows {
+			for k := range row {
+				if !seen[k] {
+					seen[k] = true
+					columns = append(columns, k)
+				}
+			}
+		}
+		sort.Strings(columns)
+	}
+
+	delimiter, _, err := opts.runes(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	w.Comma = delimiter
+
+	if opts.Header {
+		if err := w.Write(columns); err != nil {
+			return nil, err
+		}
+	}
+
+	record := make([]string, len(columns))
+	for _, row := range rows {
+		for i, col := range columns {
+			if record[i], err = toCSVField(row[col]); err != nil {
+				return nil, fmt.Errorf("column %q: %w", col, err)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return ns.createClient.FromString(opts.Path, b.String())
+}
+
+// toCSVRows converts v, a slice of maps or structs, to a slice of maps.
+func toCSVRows(v any) ([]map[string]any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a slice of maps or structs, got %T", v)
+	}
+
+	rows := make([]map[string]any, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		item := reflect.Indirect(rv.Index(i))
+		if item.Kind() == reflect.Interface {
+			item = reflect.Indirect(item.Elem())
+		}
+		switch item.Kind() {
+		case reflect.Map:
+			m := make(map[string]any, item.Len())
+			iter := item.MapRange()
+			for iter.Next() {
+				m[cast.ToString(iter.Key().Interface())] = iter.Value().Interface()
+			}
+			rows[i] = m
+		case reflect.Struct:
+			// Use the JSON field names.
+			b, err := json.Marshal(item.Interface())
+			if err != nil {
+				return nil, err
+			}
+			var m map[string]any
+			if err := json.Unmarshal(b, &m); err != nil {
+				return nil, err
+			}
+			rows[i] = m
+		default:
+			return nil, fmt.Errorf("row %d: expected a map or a struct, got %s", i, item.Kind())
+		}
+	}
+
+	return rows, nil
+}
+
+func toCSVField(v any) (string, error) {
+	switch vv := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return vv, nil
+	case float64:
+		return strconv.FormatFloat(vv, 'f', -1, 64), nil
+	}
+	switch reflect.Indirect(reflect.ValueOf(v)).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		if s, ok := v.(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+		b, err := json.Marshal(v)
+		return string(b), err
+	}
+	return cast.ToStringE(v)
+}
+
+// CSVRead reads CSV data, a resource or a string, row by row.
+// With a header row, the default, the rows are returned as maps keyed by
+// the column names, else as slices.
+// You can optionally provide an options map as the first argument with
+// delimiter, comment, columns (the columns to keep), inferTypes (convert
+// numbers and booleans), header, and offset and limit to read a part of
+// a large file without loading all of it.
+func (ns *Namespace) CSVRead(args ...any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, errors.New("CSVRead takes 1 or 2 arguments")
+	}
+	var optsm any
+	if len(args) == 2 {
+		optsm = args[0]
+	}
+	opts, err := decodeCSVOptions(optsm)
+	if err != nil {
+		return nil, err
+	}
+	data := args[len(args)-1]
+
+	var key, filename string
+	var open func() (io.ReadCloser, error)
+
+	if r, ok := data.(resource.ReadSeekCloserResource); ok {
+		if r, ok := data.(resource.Identifier); ok {
+			key = r.Key()
+			filename = key
+		}
+		open = func() (io.ReadCloser, error) {
+			return r.ReadSeekCloser()
+		}
+	} else {
+		s, err := types.ToStringE(data)
+		if err != nil {
+			return nil, fmt.Errorf("type %T not supported", data)
+		}
+		key = helpers.MD5String(s)
+		open = func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(s)), nil
+		}
+	}
+
+	delimiter, comment, err := opts.runes(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	read := func() (any, error) {
+		rc, err := open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		r := csv.NewReader(rc)
+		r.Comma = delimiter
+		r.Comment = comment
+		r.FieldsPerRecord = -1
+		r.ReuseRecord = true
+
+		return readCSV(r, opts)
+	}
+
+	if key == "" {
+		return read()
+	}
+
+	optsKey, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.cache.GetOrCreate("csvread"+key+string(optsKey), read)
+}
+
+func readCSV(r *csv.Reader, opts csvOptions) (any, error) {
+	var (
+		header  []string
+		indices []int
+		maps    []any
+		slices  []any
+	)
+
+	if opts.Header {
+		record, err := r.Read()
+		if err == io.EOF {
+			return []any{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		header = append(header, record...)
+	}
+
+	if opts.Columns != nil {
+		if !opts.Header {
+			for _, c := range opts.Columns {
+				i, err := strconv.Atoi(c)
+				if err != nil {
+					return nil, fmt.Errorf("columns must be indices without a header row, got %q", c)
+				}
+				indices = append(indices, i)
+			}
+		} else {
+			for _, c := range opts.Columns {
+				i := indexOf(header, c)
+				if i == -1 {
+					return nil, fmt.Errorf("column %q not found", c)
+				}
+				indices = append(indices, i)
+			}
+		}
+	}
+
+	value := func(record []string, i int) any {
+		if i >= len(record) {
+			return ""
+		}
+		if opts.InferTypes {
+			return inferCSVType(record[i])
+		}
+		return record[i]
+	}
+
+	for n := 0; opts.Limit <= 0 || n < opts.Offset+opts.Limit; n++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n < opts.Offset {
+			continue
+		}
+
+		cols := indices
+		if cols == nil {
+			size := len(record)
+			if header != nil {
+				size = len(header)
+			}
+			cols = make([]int, size)
+			for i := range cols {
+				cols[i] = i
+			}
+		}
+
+		if header != nil {
+			m := make(map[string]any, len(cols))
+			for _, i := range cols {
+				m[header[i]] = value(record, i)
+			}
+			maps = append(maps, m)
+		} else {
+			row := make([]any, len(cols))
+			for j, i := range cols {
+				row[j] = value(record, i)
+			}
+			slices = append(slices, row)
+		}
+	}
+
+	if header != nil {
+		if maps == nil {
+			return []any{}, nil
+		}
+		return maps, nil
+	}
+	if slices == nil {
+		return []any{}, nil
+	}
+	return slices, nil
+}
+
+// inferCSVType converts s to an int, a float or a bool if possible.
+// Numbers with leading zeros, e.g. zip codes, are kept as strings.
+func inferCSVType(s string) any {
+	if s == "" {
+		return s
+	}
+	if s == "true" || s == "false" {
+		return s == "true"
+	}
+	digits := strings.TrimPrefix(s, "-")
+	if len(digits) > 1 && digits[0] == '0' && digits[1] != '.' {
+		return s
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return int(i)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+func indexOf(s []string, v string) int {
+	for i, vv := range s {
+		if vv == v {
+			return i
+		}
+	}
+	return -1
+}
@@ -20,17 +20,31 @@ import (
 	"errors"
 	"html/template"
 
+	"github.com/gohugoio/hugo/cache/namedmemcache"
 	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/resources/resource_factories/create"
 	"github.com/spf13/cast"
 )
 
 // New returns a new instance of the encoding-namespaced template functions.
-func New() *Namespace {
-	return &Namespace{}
+func New(deps *deps.Deps) *Namespace {
+	ns := &Namespace{cache: namedmemcache.New()}
+	if deps != nil {
+		ns.createClient = create.New(deps.ResourceSpec)
+		deps.BuildStartListeners.Add(
+			func() {
+				ns.cache.Clear()
+			})
+	}
+	return ns
 }
 
 // Namespace provides template functions for the "encoding" namespace.
-type Namespace struct{}
+type Namespace struct {
+	cache        *namedmemcache.Cache
+	createClient *create.Client
+}
 
 // Base64Decode returns the base64 decoding of the given content.
 func (ns *Namespace) Base64Decode(content any) (string, error) {
@@ -27,7 +27,7 @@ func TestBase64Decode(t *testing.T) {
 	t.Parallel()
 	c := qt.New(t)
 
-	ns := New()
+	ns := New(nil)
 
 	for _, test := range []struct {
 		v      any
@@ -54,7 +54,7 @@ func TestBase64Encode(t *testing.T) {
 	t.Parallel()
 	c := qt.New(t)
 
-	ns := New()
+	ns := New(nil)
 
 	for _, test := range []struct {
 		v      any
@@ -80,7 +80,7 @@ func TestBase64Encode(t *testing.T) {
 func TestJsonify(t *testing.T) {
 	t.Parallel()
 	c := qt.New(t)
-	ns := New()
+	ns := New(nil)
 
 	for _, test := range []struct {
 		opts   any
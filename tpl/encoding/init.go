@@ -22,7 +22,7 @@ const name = "encoding"
 
 func init() {
 	f := func(d *deps.Deps) *internal.TemplateFuncsNamespace {
-		ctx := New()
+		ctx := New(d)
 
 		ns := &internal.TemplateFuncsNamespace{
 			Name:    name,
@@ -52,6 +52,18 @@ func init() {
 			},
 		)
 
+		ns.AddMethodMapping(ctx.CSVRead,
+			nil,
+			[][2]string{
+				{`{{ range "name,age\nAnna,42\n" | encoding.CSVRead (dict "inferTypes" true) }}{{ .name }}: {{ add .age 1 }}{{ end }}`, `Anna: 43`},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.CSVWrite,
+			nil,
+			[][2]string{},
+		)
+
 		return ns
 	}
 
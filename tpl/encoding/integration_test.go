@@ -0,0 +1,82 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encoding_test
+
+import (
+	"testing"
+
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "section", "sitemap", "robotsTXT", "404", "rss"]
+-- assets/people.csv --
+# A comment
+name;age;zip;member
+Anna;42;01234;true
+Bob;37;98765;false
+Carl;51;55555;true
+-- content/p1.md --
+---
+title: "P1"
+weight: 1
+---
+-- content/p2.md --
+---
+title: "P2, \"quoted\""
+weight: 2
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+-- layouts/index.html --
+{{ $opts := dict "delimiter" ";" "comment" "#" "inferTypes" true }}
+{{ $people := resources.Get "people.csv" | encoding.CSVRead $opts }}
+{{ range $people }}{{ .name }}: {{ add .age 1 }}|{{ .zip }}|{{ if .member }}member{{ end }}|{{ end }}
+{{ $page := resources.Get "people.csv" | encoding.CSVRead (merge $opts (dict "offset" 1 "limit" 1 "columns" (slice "name"))) }}
+Page: {{ $page }}
+{{ $rows := resources.Get "people.csv" | encoding.CSVRead (dict "delimiter" ";" "comment" "#" "header" false "offset" 1 "limit" 1) }}
+Rows: {{ $rows }}
+{{ $data := slice }}
+{{ range site.RegularPages }}{{ $data = $data | append (dict "title" .Title "weight" .Weight "url" .RelPermalink) }}{{ end }}
+{{ $csv := encoding.CSVWrite (dict "path" "pages.csv") $data }}
+CSV: {{ $csv.RelPermalink }}|{{ $csv.MediaType }}
+{{ $tsv := encoding.CSVWrite (dict "path" "pages.tsv" "columns" (slice "url" "title")) $data }}
+TSV: {{ $tsv.RelPermalink }}
+{{ range encoding.CSVRead $tsv }}TSV row: {{ .url }}|{{ end }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html",
+		"Anna: 43|01234|member|Bob: 38|98765||Carl: 52|55555|member|",
+		"Page: [map[name:Bob]]",
+		"Rows: [[Anna 42 01234 true]]",
+		"CSV: /pages.csv|text/csv",
+		"TSV: /pages.tsv",
+		"TSV row: /p1/|TSV row: /p2/|",
+	)
+
+	b.AssertFileContentExact("public/pages.csv", "title,url,weight\nP1,/p1/,1\n\"P2, \"\"quoted\"\"\",/p2/,2\n")
+	b.AssertFileContentExact("public/pages.tsv", "url\ttitle\n/p1/\tP1\n/p2/\t\"P2, \"\"quoted\"\"\"\n")
+}
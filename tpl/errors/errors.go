@@ -0,0 +1,196 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package errors provides template functions to create and raise errors
+// that tell where in the templates and for which page they happened.
+package errors
+
+import (
+	"context"
+	_errors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/spf13/cast"
+)
+
+// New returns a new instance of the errors-namespaced template functions.
+func New(deps *deps.Deps) *Namespace {
+	return &Namespace{deps: deps}
+}
+
+// Namespace provides template functions for the "errors" namespace.
+type Namespace struct {
+	deps *deps.Deps
+}
+
+// Error is an error created in a template.
+type Error struct {
+	msg   string
+	cause error
+
+	// The path of the page being rendered when the error was created,
+	// e.g. blog/post.md.
+	Page string
+
+	// The templates being executed when the error was created, outermost
+	// first, e.g. _default/single.html and partials/image.html.
+	Templates []string
+
+	// The key/value pairs added with errors.Annotate.
+	Annotations []Annotation
+}
+
+// Annotation is a key/value pair added to an Error.
+type Annotation struct {
+	Key   string
+	Value any
+}
+
+// Message returns the error message without the page, templates and
+// annotations.
+func (e *Error) Message() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	var cause string
+	if ce, ok := e.cause.(*Error); ok {
+		cause = ce.Message()
+	} else {
+		cause = e.cause.Error()
+	}
+	if e.msg == "" {
+		return cause
+	}
+	return e.msg + ": " + cause
+}
+
+func (e *Error) Error() string {
+	var context []string
+	for _, a := range e.Annotations {
+		context = append(context, fmt.Sprintf("%s=%v", a.Key, a.Value))
+	}
+	if e.Page != "" {
+		context = append(context, fmt.Sprintf("page %q", e.Page))
+	}
+	if len(e.Templates) > 0 {
+		context = append(context, "in "+strings.Join(e.Templates, " > "))
+	}
+	if len(context) == 0 {
+		return e.Message()
+	}
+	return e.Message() + " (" + strings.Join(context, ", ") + ")"
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// New creates an error with msg, including the page and templates being
+// rendered. The error is returned, not raised; see Raise.
+func (ns *Namespace) New(ctx context.Context, msg any) (*Error, error) {
+	s, err := cast.ToStringE(msg)
+	if err != nil {
+		return nil, err
+	}
+	return newError(ctx, s, nil), nil
+}
+
+// Wrapf wraps the error, the last argument so it can be piped, in a new
+// error with the message formatted from format and the other args, e.g.
+// {{ $err | errors.Wrapf "failed to process %s" $src }}.
+func (ns *Namespace) Wrapf(ctx context.Context, format string, args ...any) (*Error, error) {
+	if len(args) == 0 {
+		return nil, _errors.New("missing error to wrap")
+	}
+	cause, err := toError(ctx, args[len(args)-1])
+	if err != nil {
+		return nil, err
+	}
+	e := &Error{
+		msg:         fmt.Sprintf(format, args[:len(args)-1]...),
+		cause:       cause,
+		Page:        cause.Page,
+		Templates:   cause.Templates,
+		Annotations: cause.Annotations,
+	}
+	return e, nil
+}
+
+// Annotate returns a copy of the error, the last argument so it can be
+// piped, with the key/value pair added, e.g.
+// {{ $err | errors.Annotate "src" $src }}.
+func (ns *Namespace) Annotate(ctx context.Context, key string, value, err any) (*Error, error) {
+	e, err2 := toError(ctx, err)
+	if err2 != nil {
+		return nil, err2
+	}
+	ee := *e
+	ee.Annotations = append(e.Annotations[:len(e.Annotations):len(e.Annotations)], Annotation{Key: key, Value: value})
+	return &ee, nil
+}
+
+// Raise fails the build with err, an error or a message, e.g.
+// {{ errors.New "image not found" | errors.Annotate "src" $src | errors.Raise }}.
+func (ns *Namespace) Raise(ctx context.Context, err any) (string, error) {
+	e, err2 := toError(ctx, err)
+	if err2 != nil {
+		return "", err2
+	}
+	return "", e
+}
+
+func newError(ctx context.Context, msg string, cause error) *Error {
+	e := &Error{
+		msg:       msg,
+		cause:     cause,
+		Templates: tpl.GetTemplateChainFromContext(ctx),
+	}
+
+	switch p := tpl.GetDataFromContext(ctx).(type) {
+	case interface{ Pathc() string }:
+		e.Page = p.Pathc()
+		if e.Page == "" {
+			if pp, ok := p.(interface{ RelPermalink() string }); ok {
+				e.Page = pp.RelPermalink()
+			}
+		}
+	case interface{ RelPermalink() string }:
+		e.Page = p.RelPermalink()
+	}
+
+	return e
+}
+
+// toError converts v, an error or a message, to an *Error.
+func toError(ctx context.Context, v any) (*Error, error) {
+	switch vv := v.(type) {
+	case *Error:
+		if vv == nil {
+			return nil, _errors.New("error is nil")
+		}
+		return vv, nil
+	case error:
+		return newError(ctx, "", vv), nil
+	case nil:
+		return nil, _errors.New("error is nil")
+	}
+
+	s, err := cast.ToStringE(v)
+	if err != nil {
+		return nil, fmt.Errorf("expected an error, got %T", v)
+	}
+	return newError(ctx, s, nil), nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/tpl/internal"
+)
+
+const name = "errors"
+
+func init() {
+	f := func(d *deps.Deps) *internal.TemplateFuncsNamespace {
+		ctx := New(d)
+
+		ns := &internal.TemplateFuncsNamespace{
+			Name:    name,
+			Context: func(args ...any) (any, error) { return ctx, nil },
+		}
+
+		ns.AddMethodMapping(ctx.New,
+			nil,
+			[][2]string{
+				{`{{ (errors.New "image not found").Message }}`, `image not found`},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.Wrapf,
+			nil,
+			[][2]string{
+				{`{{ (errors.New "not found" | errors.Wrapf "image %s" "a.png").Message }}`, `image a.png: not found`},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.Annotate,
+			nil,
+			[][2]string{
+				{`{{ range (errors.New "not found" | errors.Annotate "src" "a.png").Annotations }}{{ .Key }}={{ .Value }}{{ end }}`, `src=a.png`},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.Raise,
+			nil,
+			nil,
+		)
+
+		return ns
+	}
+
+	internal.AddTemplateFuncsNamespace(f)
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors_test
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestErrorValues(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "section", "sitemap", "robotsTXT", "RSS"]
+-- content/p1.md --
+---
+title: "p1"
+---
+-- layouts/index.html --
+home
+-- layouts/_default/single.html --
+{{ $err := partial "image.html" "a.png" }}
+Error: {{ $err }}
+Message: {{ $err.Message }}|Page: {{ $err.Page }}|Templates: {{ delimit $err.Templates ", " }}|
+{{ $wrapped := $err | errors.Wrapf "render %s" .Title }}
+Wrapped: {{ $wrapped }}
+-- layouts/partials/image.html --
+{{ $err := errors.New "not found" | errors.Annotate "src" . }}
+{{ return $err }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/p1/index.html",
+		`Error: not found (src=a.png, page &#34;p1.md&#34;, in _default/single.html &gt; partials/image.html)`,
+		`Message: not found|Page: p1.md|Templates: _default/single.html, partials/image.html|`,
+		`Wrapped: render p1: not found (src=a.png, page &#34;p1.md&#34;, in _default/single.html &gt; partials/image.html)`,
+	)
+}
+
+func TestRaise(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+disableKinds = ["taxonomy", "term", "section", "sitemap", "robotsTXT", "RSS"]
+-- content/p1.md --
+---
+title: "p1"
+---
+-- layouts/index.html --
+home
+-- layouts/_default/single.html --
+{{ partial "image.html" "a.png" }}
+-- layouts/partials/image.html --
+{{ errors.New "not found" | errors.Annotate "src" . | errors.Raise }}
+`
+
+	b, err := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.Not(qt.IsNil))
+	b.Assert(err.Error(), qt.Contains, `not found (src=a.png, page "p1.md", in _default/single.html > partials/image.html)`)
+	b.Assert(err.Error(), qt.Contains, `partials/image.html:1:`)
+}
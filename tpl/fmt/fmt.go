@@ -27,7 +27,7 @@ import (
 func New(d *deps.Deps) *Namespace {
 	ignorableLogger, ok := d.Log.(loggers.IgnorableLogger)
 	if !ok {
-		ignorableLogger = loggers.NewIgnorableLogger(d.Log)
+		ignorableLogger = loggers.NewIgnorableLogger(d.Log, nil, nil)
 	}
 
 	distinctLogger := helpers.NewDistinctLogger(d.Log)
@@ -83,3 +83,27 @@ func (ns *Namespace) Warnf(format string, args ...any) string {
 	ns.distinctLogger.Warnf(format, args...)
 	return ""
 }
+
+// Warnidf formats args according to a format specifier and logs a WARNING and
+// an information text that the warning with the given ID can be suppressed in config.
+// It returns an empty string.
+func (ns *Namespace) Warnidf(id, format string, args ...any) string {
+	ns.distinctLogger.Warnsf(id, format, args...)
+	return ""
+}
+
+// Infof formats args according to a format specifier and logs an INFO,
+// shown when running with --verbose.
+// It returns an empty string.
+func (ns *Namespace) Infof(format string, args ...any) string {
+	ns.distinctLogger.Infof(format, args...)
+	return ""
+}
+
+// Debugf formats args according to a format specifier and logs a DEBUG,
+// shown when running with --debug.
+// It returns an empty string.
+func (ns *Namespace) Debugf(format string, args ...any) string {
+	ns.distinctLogger.Debugf(format, args...)
+	return ""
+}
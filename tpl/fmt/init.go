@@ -70,6 +70,27 @@ func init() {
 				{`{{ warnf "%s." "warning" }}`, ``},
 			},
 		)
+
+		ns.AddMethodMapping(ctx.Warnidf,
+			[]string{"warnidf"},
+			[][2]string{
+				{`{{ warnidf "my-warn-id" "%s." "warning" }}`, ``},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.Infof,
+			[]string{"infof"},
+			[][2]string{
+				{`{{ infof "%s." "info" }}`, ``},
+			},
+		)
+
+		ns.AddMethodMapping(ctx.Debugf,
+			[]string{"debugf"},
+			[][2]string{
+				{`{{ debugf "%s." "debug" }}`, ``},
+			},
+		)
 		return ns
 	}
 
@@ -0,0 +1,67 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fmt_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+	jww "github.com/spf13/jwalterweatherman"
+)
+
+func TestLogFunctions(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org"
+disableKinds = ["taxonomy", "term", "rss", "sitemap", "robotsTXT", "404"]
+ignoreWarnings = ["Theme-Ignored"]
+-- content/p1.md --
+---
+title: "P1"
+---
+-- content/p2.md --
+---
+title: "P2"
+---
+-- layouts/index.html --
+Home.
+-- layouts/_default/single.html --
+{{ warnf "Same for all pages." }}
+{{ warnidf "theme-old-shortcode" "Old shortcode in %s." .Title }}
+{{ warnidf "theme-ignored" "Ignored." }}
+{{ infof "Info for %s." .Title }}
+{{ debugf "Debug." }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			LogLevel:    jww.LevelInfo,
+		},
+	).Build()
+
+	b.AssertLogContains("Old shortcode in P1.\nYou can suppress this warning by adding this to your site config:\nignoreWarnings = [\"theme-old-shortcode\"]")
+	b.AssertLogContains("Old shortcode in P2.")
+	b.AssertLogContains("INFO")
+	b.AssertLogContains("Info for P1.")
+
+	b.Assert(strings.Count(b.LogString(), "Same for all pages."), qt.Equals, 1)
+	b.Assert(b.LogString(), qt.Not(qt.Contains), "Ignored.")
+	b.Assert(b.LogString(), qt.Not(qt.Contains), "Debug.")
+}
@@ -0,0 +1,307 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ical provides template functions to create iCalendar files.
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gohugoio/hugo/common/htime"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/langs"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+// DefaultConfig holds the default calendar configuration.
+var DefaultConfig = Config{
+	Start:    "start",
+	End:      "end",
+	AllDay:   "allDay",
+	Location: "location",
+	RRule:    "rrule",
+}
+
+// Config configures the front matter fields the events are read from.
+// Nested fields are separated by a dot, e.g. "event.start".
+type Config struct {
+	// The start of the event. Pages without it are not events.
+	Start string
+
+	// The end of the event, for all-day events the last day.
+	End string
+
+	// Whether the event lasts all day. Also set if the start is a date
+	// without time, e.g. 2022-06-01.
+	AllDay string
+
+	Location string
+
+	// The recurrence rule, e.g. FREQ=WEEKLY;COUNT=4.
+	RRule string
+}
+
+// DecodeConfig decodes the calendar section in site config.
+func DecodeConfig(in any) (Config, error) {
+	c := DefaultConfig
+	if in == nil {
+		return c, nil
+	}
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode calendar config: %w", err)
+	}
+	return c, nil
+}
+
+// New returns a new instance of the ical-namespaced template functions.
+func New(d *deps.Deps) *Namespace {
+	cfg, err := DecodeConfig(d.Cfg.Get("calendar"))
+	loc := time.UTC
+	if d.Language != nil {
+		loc = langs.GetLocation(d.Language)
+	}
+	return &Namespace{cfg: cfg, cfgErr: err, loc: loc}
+}
+
+// Namespace provides template functions for the "ical" namespace.
+type Namespace struct {
+	cfg    Config
+	cfgErr error
+	loc    *time.Location
+}
+
+// Event is a calendar event.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	RRule       string
+
+	Start  time.Time
+	End    time.Time
+	AllDay bool
+
+	// The last modification of the event.
+	Stamp time.Time
+}
+
+// Event returns the event in the front matter of p, nil if p has no start.
+func (ns *Namespace) Event(p page.Page) (*Event, error) {
+	if ns.cfgErr != nil {
+		return nil, ns.cfgErr
+	}
+
+	params := p.Params()
+	param := func(key string) any {
+		if key == "" {
+			return nil
+		}
+		v, _ := maps.GetNestedParam(key, ".", params)
+		return v
+	}
+
+	startv := param(ns.cfg.Start)
+	if startv == nil {
+		return nil, nil
+	}
+
+	start, err := htime.ToTimeInDefaultLocationE(startv, ns.loc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid event start: %w", p.File().Path(), err)
+	}
+
+	e := &Event{
+		UID:         p.Permalink(),
+		Summary:     p.Title(),
+		Description: p.Description(),
+		Location:    cast.ToString(param(ns.cfg.Location)),
+		URL:         p.Permalink(),
+		RRule:       cast.ToString(param(ns.cfg.RRule)),
+		Start:       start,
+		AllDay:      cast.ToBool(param(ns.cfg.AllDay)) || isDate(startv),
+		Stamp:       p.Lastmod(),
+	}
+
+	if e.Description == "" {
+		e.Description = strings.TrimSpace(tpl.StripHTML(string(p.Summary())))
+	}
+
+	if endv := param(ns.cfg.End); endv != nil {
+		if e.End, err = htime.ToTimeInDefaultLocationE(endv, ns.loc); err != nil {
+			return nil, fmt.Errorf("%s: invalid event end: %w", p.File().Path(), err)
+		}
+		if e.End.Before(e.Start) {
+			return nil, fmt.Errorf("%s: event ends before it starts", p.File().Path())
+		}
+	}
+
+	if e.Stamp.IsZero() {
+		e.Stamp = p.Date()
+	}
+	if e.Stamp.IsZero() {
+		e.Stamp = e.Start
+	}
+
+	return e, nil
+}
+
+func isDate(v any) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	_, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+	return err == nil
+}
+
+// Calendar returns an iCalendar file named name with the events in the page
+// or pages in pages, ordered by start.
+func (ns *Namespace) Calendar(name any, pages any) (string, error) {
+	title, err := cast.ToStringE(name)
+	if err != nil {
+		return "", err
+	}
+
+	var ps page.Pages
+	switch v := pages.(type) {
+	case page.Page:
+		ps = page.Pages{v}
+	default:
+		if ps, err = page.ToPages(pages); err != nil {
+			return "", err
+		}
+	}
+
+	var events []*Event
+	for _, p := range ps {
+		e, err := ns.Event(p)
+		if err != nil {
+			return "", err
+		}
+		if e != nil {
+			events = append(events, e)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+
+	var w calendarWriter
+	w.prop("BEGIN", "VCALENDAR")
+	w.prop("VERSION", "2.0")
+	w.prop("PRODID", "-//gohugo.io//Hugo//EN")
+	w.prop("CALSCALE", "GREGORIAN")
+	if title != "" {
+		w.prop("X-WR-CALNAME", escapeText(title))
+	}
+	for _, e := range events {
+		w.event(e)
+	}
+	w.prop("END", "VCALENDAR")
+
+	return w.String(), nil
+}
+
+type calendarWriter struct {
+	strings.Builder
+}
+
+func (w *calendarWriter) event(e *Event) {
+	w.prop("BEGIN", "VEVENT")
+	w.prop("UID", e.UID)
+	w.prop("DTSTAMP", formatTime(e.Stamp))
+	if e.AllDay {
+		w.prop("DTSTART;VALUE=DATE", formatDate(e.Start))
+		end := e.Start
+		if !e.End.IsZero() {
+			end = e.End
+		}
+		// The end date is exclusive.
+		w.prop("DTEND;VALUE=DATE", formatDate(end.AddDate(0, 0, 1)))
+	} else {
+		w.prop("DTSTART", formatTime(e.Start))
+		if !e.End.IsZero() {
+			w.prop("DTEND", formatTime(e.End))
+		}
+	}
+	if e.RRule != "" {
+		w.prop("RRULE", strings.ToUpper(strings.Join(strings.Fields(e.RRule), "")))
+	}
+	w.prop("SUMMARY", escapeText(e.Summary))
+	if e.Description != "" {
+		w.prop("DESCRIPTION", escapeText(e.Description))
+	}
+	if e.Location != "" {
+		w.prop("LOCATION", escapeText(e.Location))
+	}
+	if e.URL != "" {
+		w.prop("URL", e.URL)
+	}
+	w.prop("END", "VEVENT")
+}
+
+// prop writes a content line, folded to lines of at most 75 octets.
+func (w *calendarWriter) prop(name, value string) {
+	line := name + ":" + value
+	for first := true; ; first = false {
+		max := 75
+		if !first {
+			max = 74
+			w.WriteString(" ")
+		}
+		if len(line) <= max {
+			w.WriteString(line)
+			w.WriteString("\r\n")
+			return
+		}
+		i := max
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+		w.WriteString(line[:i])
+		w.WriteString("\r\n")
+		line = line[i:]
+	}
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("20060102")
+}
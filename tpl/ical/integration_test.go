@@ -0,0 +1,109 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ical_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestCalendar(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+title = "My Site"
+timeZone = "Europe/Oslo"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT"]
+[outputs]
+page = ["HTML", "Calendar"]
+section = ["HTML", "Calendar"]
+[calendar]
+start = "event.start"
+end = "event.end"
+location = "event.where"
+-- content/events/meetup.md --
+---
+title: "Meetup"
+description: "Talks, pizza; and more."
+lastmod: 2022-05-01T12:00:00Z
+event:
+  start: 2022-06-01T18:00:00
+  end: 2022-06-01T21:00:00
+  where: "Oslo, Norway"
+rrule: "freq=monthly; count=3"
+---
+-- content/events/conference.md --
+---
+title: "A very long conference title that needs to be folded over multiple lines"
+lastmod: 2022-05-02T12:00:00Z
+event:
+  start: "2022-05-20"
+  end: "2022-05-21"
+---
+Two days of Hugo.
+-- content/events/news.md --
+---
+title: "News"
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+-- layouts/_default/list.html --
+{{ .Title }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	meetup := strings.ReplaceAll(`BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//gohugo.io//Hugo//EN
+CALSCALE:GREGORIAN
+X-WR-CALNAME:Meetup - My Site
+BEGIN:VEVENT
+UID:https://example.org/events/meetup/
+DTSTAMP:20220501T120000Z
+DTSTART:20220601T160000Z
+DTEND:20220601T190000Z
+RRULE:FREQ=MONTHLY;COUNT=3
+SUMMARY:Meetup
+DESCRIPTION:Talks\, pizza\; and more.
+LOCATION:Oslo\, Norway
+URL:https://example.org/events/meetup/
+END:VEVENT
+END:VCALENDAR
+`, "\n", "\r\n")
+
+	b.Assert(b.FileContent("public/events/meetup/index.ics"), qt.Equals, meetup)
+
+	// The section calendar has the events of the section ordered by start.
+	b.AssertFileContent("public/events/index.ics", "X-WR-CALNAME:Events - My Site")
+	section := b.FileContent("public/events/index.ics")
+	b.Assert(strings.Count(section, "BEGIN:VEVENT"), qt.Equals, 2)
+	b.Assert(section, qt.Contains, "\r\nSUMMARY:A very long conference title that needs to be folded over multiple \r\n lines\r\n")
+	b.Assert(section, qt.Contains, "\r\nDTSTART;VALUE=DATE:20220520\r\nDTEND;VALUE=DATE:20220522\r\n")
+	b.Assert(section, qt.Contains, "\r\nDESCRIPTION:Two days of Hugo.\r\n")
+	b.Assert(strings.Index(section, "20220520") < strings.Index(section, "20220601"), qt.IsTrue)
+
+	// Not an event.
+	b.AssertFileContent("public/events/news/index.ics", "CALSCALE:GREGORIAN\r\nEND:VCALENDAR")
+}
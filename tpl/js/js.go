@@ -63,3 +63,14 @@ func (ns *Namespace) Build(args ...any) (resource.Resource, error) {
 
 	return ns.client.Process(r, m)
 }
+
+// Modules publishes the given Resource and the modules it imports from
+// /assets as native ES modules, without bundling them.
+func (ns *Namespace) Modules(args ...any) (*js.Modules, error) {
+	r, m, err := resourcehelpers.ResolveArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.client.Modules(r, m)
+}
@@ -0,0 +1,383 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi3
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/spf13/cast"
+)
+
+// Document is an OpenAPI 3.0 or 3.1 document with all $refs resolved,
+// flattened for use in templates.
+type Document struct {
+	// The OpenAPI version, e.g. 3.1.0.
+	OpenAPI string
+
+	Info    Info
+	Servers []Server
+
+	// The operations in path order, then in method order.
+	Operations []*Operation
+
+	// The webhooks (OpenAPI 3.1) by name.
+	Webhooks []*Operation
+
+	// The operations grouped by their first tag, in the order of the tags
+	// in the document, then in the order they first appear.
+	Tags []*Tag
+
+	// The schemas in components by name.
+	Schemas map[string]any
+
+	// The security schemes in components by name.
+	SecuritySchemes map[string]any
+
+	// The problems found when validating the document.
+	Errors []string
+
+	// The full document with all $refs resolved.
+	Raw map[string]any
+}
+
+// Info is the metadata about the API.
+type Info struct {
+	Title          string
+	Summary        string
+	Description    string
+	Version        string
+	TermsOfService string
+	Contact        map[string]any
+	License        License
+}
+
+// License is the license of the API.
+type License struct {
+	Name string
+
+	// An SPDX license expression (OpenAPI 3.1).
+	Identifier string
+
+	URL string
+}
+
+// Server is a server of the API.
+type Server struct {
+	URL         string
+	Description string
+	Variables   map[string]any
+}
+
+// Tag groups operations.
+type Tag struct {
+	Name        string
+	Description string
+	Operations  []*Operation
+}
+
+// Operation is an API operation on a path, or a webhook.
+type Operation struct {
+	// The operationId, if set.
+	ID string
+
+	// The HTTP method in upper case, e.g. GET.
+	Method string
+
+	// The path, e.g. /users/{id}, or the name of the webhook.
+	Path string
+
+	// A unique identifier to use in e.g. HTML anchors: the operationId if
+	// set, else the method and the path.
+	Anchor string
+
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+
+	// The path and operation parameters.
+	Parameters []Parameter
+
+	RequestBody *RequestBody
+
+	// The responses ordered by status code.
+	Responses []Response
+
+	Security []any
+
+	Raw map[string]any
+}
+
+// Parameter is a parameter of an operation.
+type Parameter struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Deprecated  bool
+	Schema      map[string]any
+	Example     any
+}
+
+// RequestBody is the request body of an operation.
+type RequestBody struct {
+	Description string
+	Required    bool
+	Content     []MediaType
+}
+
+// Response is a response of an operation.
+type Response struct {
+	// The HTTP status code, e.g. 200, or 2XX or default.
+	Status string
+
+	Description string
+	Headers     map[string]any
+	Content     []MediaType
+}
+
+// MediaType is the content of a request or response of a given media type.
+type MediaType struct {
+	// The media type, e.g. application/json.
+	Type string
+
+	Schema   map[string]any
+	Example  any
+	Examples map[string]any
+}
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+func newDocument(raw map[string]any) *Document {
+	d := &Document{
+		OpenAPI: str(raw["openapi"]),
+		Raw:     raw,
+	}
+
+	info := toMap(raw["info"])
+	license := toMap(info["license"])
+	d.Info = Info{
+		Title:          str(info["title"]),
+		Summary:        str(info["summary"]),
+		Description:    str(info["description"]),
+		Version:        str(info["version"]),
+		TermsOfService: str(info["termsOfService"]),
+		Contact:        toMap(info["contact"]),
+		License: License{
+			Name:       str(license["name"]),
+			Identifier: str(license["identifier"]),
+			URL:        str(license["url"]),
+		},
+	}
+
+	for _, v := range toSlice(raw["servers"]) {
+		s := toMap(v)
+		d.Servers = append(d.Servers, Server{
+			URL:         str(s["url"]),
+			Description: str(s["description"]),
+			Variables:   toMap(s["variables"]),
+		})
+	}
+
+	components := toMap(raw["components"])
+	d.Schemas = toMap(components["schemas"])
+	d.SecuritySchemes = toMap(components["securitySchemes"])
+
+	d.Operations = newOperations(toMap(raw["paths"]), raw["security"])
+	d.Webhooks = newOperations(toMap(raw["webhooks"]), raw["security"])
+
+	tags := make(map[string]*Tag)
+	for _, v := range toSlice(raw["tags"]) {
+		t := toMap(v)
+		tag := &Tag{Name: str(t["name"]), Description: str(t["description"])}
+		if _, found := tags[tag.Name]; !found {
+			tags[tag.Name] = tag
+			d.Tags = append(d.Tags, tag)
+		}
+	}
+	for _, op := range d.Operations {
+		name := "default"
+		if len(op.Tags) > 0 {
+			name = op.Tags[0]
+		}
+		tag, found := tags[name]
+		if !found {
+			tag = &Tag{Name: name}
+			tags[name] = tag
+			d.Tags = append(d.Tags, tag)
+		}
+		tag.Operations = append(tag.Operations, op)
+	}
+
+	return d
+}
+
+func newOperations(paths map[string]any, security any) []*Operation {
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var operations []*Operation
+	for _, p := range keys {
+		item := toMap(paths[p])
+		for _, method := range methods {
+			raw, found := item[method]
+			if !found {
+				continue
+			}
+			m := toMap(raw)
+			op := &Operation{
+				ID:          str(m["operationId"]),
+				Method:      strings.ToUpper(method),
+				Path:        p,
+				Summary:     str(m["summary"]),
+				Description: str(m["description"]),
+				Tags:        cast.ToStringSlice(m["tags"]),
+				Deprecated:  cast.ToBool(m["deprecated"]),
+				Raw:         m,
+			}
+			if op.Summary == "" {
+				op.Summary = str(item["summary"])
+			}
+			if op.Description == "" {
+				op.Description = str(item["description"])
+			}
+			op.Anchor = op.ID
+			if op.Anchor == "" {
+				op.Anchor = anchorize(method + "-" + p)
+			}
+
+			op.Security = toSlice(security)
+			if v, found := m["security"]; found {
+				op.Security = toSlice(v)
+			}
+
+			op.Parameters = newParameters(toSlice(item["parameters"]), toSlice(m["parameters"]))
+
+			if body := toMap(m["requestBody"]); len(body) > 0 {
+				op.RequestBody = &RequestBody{
+					Description: str(body["description"]),
+					Required:    cast.ToBool(body["required"]),
+					Content:     newMediaTypes(toMap(body["content"])),
+				}
+			}
+
+			responses := toMap(m["responses"])
+			statuses := make([]string, 0, len(responses))
+			for k := range responses {
+				statuses = append(statuses, k)
+			}
+			sort.Slice(statuses, func(i, j int) bool {
+				// default last.
+				if (statuses[i] == "default") != (statuses[j] == "default") {
+					return statuses[j] == "default"
+				}
+				return statuses[i] < statuses[j]
+			})
+			for _, status := range statuses {
+				r := toMap(responses[status])
+				op.Responses = append(op.Responses, Response{
+					Status:      status,
+					Description: str(r["description"]),
+					Headers:     toMap(r["headers"]),
+					Content:     newMediaTypes(toMap(r["content"])),
+				})
+			}
+
+			operations = append(operations, op)
+		}
+	}
+
+	return operations
+}
+
+// newParameters merges the path item parameters with those of the
+// operation, which override them.
+func newParameters(pathParams, opParams []any) []Parameter {
+	var params []Parameter
+	index := make(map[string]int)
+	for _, v := range append(pathParams, opParams...) {
+		m := toMap(v)
+		p := Parameter{
+			Name:        str(m["name"]),
+			In:          str(m["in"]),
+			Description: str(m["description"]),
+			Required:    cast.ToBool(m["required"]),
+			Deprecated:  cast.ToBool(m["deprecated"]),
+			Schema:      toMap(m["schema"]),
+			Example:     m["example"],
+		}
+		key := p.In + ":" + p.Name
+		if i, found := index[key]; found {
+			params[i] = p
+			continue
+		}
+		index[key] = len(params)
+		params = append(params, p)
+	}
+	return params
+}
+
+func newMediaTypes(content map[string]any) []MediaType {
+	var mediaTypes []MediaType
+	for k, v := range content {
+		m := toMap(v)
+		mediaTypes = append(mediaTypes, MediaType{
+			Type:     k,
+			Schema:   toMap(m["schema"]),
+			Example:  m["example"],
+			Examples: toMap(m["examples"]),
+		})
+	}
+	sort.Slice(mediaTypes, func(i, j int) bool {
+		return mediaTypes[i].Type < mediaTypes[j].Type
+	})
+	return mediaTypes
+}
+
+func anchorize(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func str(v any) string {
+	return cast.ToString(v)
+}
+
+func toMap(v any) map[string]any {
+	m, err := maps.ToStringMapE(v)
+	if err != nil {
+		return nil
+	}
+	return m
+}
+
+func toSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
@@ -34,6 +34,16 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.Load,
+			nil,
+			[][2]string{},
+		)
+
+		ns.AddMethodMapping(ctx.Validate,
+			nil,
+			[][2]string{},
+		)
+
 		return ns
 	}
 
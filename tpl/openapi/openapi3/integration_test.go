@@ -17,6 +17,7 @@ import (
 	"strings"
 	"testing"
 
+	qt "github.com/frankban/quicktest"
 	"github.com/gohugoio/hugo/hugolib"
 )
 
@@ -72,3 +73,177 @@ API: {{ $api.Info.Title | safeHTML }}
 
 	b.AssertFileContent("public/index.html", `API: Hugo API`)
 }
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- assets/api/myapi.yaml --
+openapi: 3.1.0
+info:
+  title: Sample API
+  version: 0.1.9
+  license:
+    name: Apache 2.0
+    identifier: Apache-2.0
+tags:
+  - name: users
+    description: User operations.
+paths:
+  /users/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: string
+    get:
+      operationId: getUser
+      tags: [users]
+      summary: Returns a user.
+      responses:
+        '200':
+          description: A user.
+          content:
+            application/json:
+              schema:
+                $ref: 'schemas/user.yaml'
+        '404':
+          $ref: '#/components/responses/NotFound'
+          description: No such user.
+webhooks:
+  newUser:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: 'schemas/user.yaml#/'
+components:
+  responses:
+    NotFound:
+      description: Not found.
+-- assets/api/schemas/user.yaml --
+type: object
+properties:
+  name:
+    type: [string, "null"]
+-- assets/api/invalid.yaml --
+openapi: 3.1.0
+info:
+  title: Invalid API
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+      responses:
+        '600':
+          description: Bad.
+    post:
+      operationId: getUser
+      parameters:
+        - $ref: 'missing.yaml'
+-- config.toml --
+baseURL = 'http://example.com/'
+-- layouts/index.html --
+{{ $api := resources.Get "api/myapi.yaml" | openapi3.Load }}
+API: {{ $api.Info.Title }}|{{ $api.OpenAPI }}|{{ $api.Info.License.Identifier }}|Errors: {{ len $api.Errors }}
+{{ range $api.Tags }}Tag: {{ .Name }}: {{ .Description }}
+{{ range .Operations }}Op: {{ .Anchor }}|{{ .Method }}|{{ .Path }}|{{ .Summary }}|{{ range .Parameters }}{{ .Name }}:{{ .In }}{{ end }}
+{{ range .Responses }}{{ .Status }}: {{ .Description }}{{ range .Content }}|{{ .Type }}|{{ .Schema.properties.name.type }}{{ end }}
+{{ end }}{{ end }}{{ end }}
+{{ range $api.Webhooks }}Webhook: {{ .Method }}|{{ .Path }}|{{ .Anchor }}|{{ range .RequestBody.Content }}{{ .Schema.type }}{{ end }}{{ end }}
+{{ openapi3.Validate $api }}
+{{ $invalid := resources.Get "api/invalid.yaml" | openapi3.Load }}
+{{ range $invalid.Errors }}Error: {{ . | safeHTML }}
+{{ end }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html",
+		"API: Sample API|3.1.0|Apache-2.0|Errors: 0",
+		"Tag: users: User operations.",
+		"Op: getUser|GET|/users/{id}|Returns a user.|id:path",
+		"200: A user.|application/json|[string null]",
+		"404: No such user.",
+		"Webhook: POST|newUser|post-newuser|object",
+		`Error: failed to resolve $ref "missing.yaml": assets/api/missing.yaml: file does not exist`,
+		"Error: info.version: missing",
+		`Error: GET /users/{id}: responses: invalid status code "600"`,
+		`Error: GET /users/{id}: path parameter "id" not defined`,
+		`Error: POST /users/{id}: operationId "getUser" is also used by GET /users/{id}`,
+	)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- assets/api/invalid.yaml --
+openapi: 3.0.3
+info:
+  title: Invalid API
+  version: 1.0.0
+-- config.toml --
+baseURL = 'http://example.com/'
+-- layouts/index.html --
+{{ resources.Get "api/invalid.yaml" | openapi3.Validate }}
+`
+
+	b, err := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, "invalid OpenAPI document \"Invalid API\":\npaths: missing")
+}
+
+func TestUnmarshalExternalRefs(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- assets/api/myapi.yaml --
+openapi: 3.0.0
+info:
+  title: Sample API
+  version: 0.1.9
+paths:
+  /users:
+    get:
+      responses:
+        '200':
+          description: A list of users.
+          content:
+            application/json:
+              schema:
+                $ref: 'schemas/users.yaml'
+-- assets/api/schemas/users.yaml --
+type: array
+description: The users.
+items:
+  type: string
+-- config.toml --
+baseURL = 'http://example.com/'
+-- layouts/index.html --
+{{ $api := resources.Get "api/myapi.yaml" | openapi3.Unmarshal }}
+{{ with $api.Paths.Find "/users" }}{{ with (index .Get.Responses "200").Value }}Schema: {{ (index .Content "application/json").Schema.Value.Description }}{{ end }}{{ end }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html", "Schema: The users.")
+}
@@ -14,18 +14,20 @@
 package openapi3
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/url"
+	"strings"
 
 	gyaml "github.com/ghodss/yaml"
 
-	"errors"
-
 	kopenapi3 "github.com/getkin/kin-openapi/openapi3"
 	"github.com/gohugoio/hugo/cache/namedmemcache"
 	"github.com/gohugoio/hugo/deps"
 	"github.com/gohugoio/hugo/parser/metadecoders"
 	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/resources/resource_factories/create"
 )
 
 // New returns a new instance of the openapi3-namespaced template functions.
@@ -38,17 +40,22 @@ func New(deps *deps.Deps) *Namespace {
 		})
 
 	return &Namespace{
-		cache: cache,
-		deps:  deps,
+		cache:        cache,
+		deps:         deps,
+		createClient: create.New(deps.ResourceSpec),
 	}
 }
 
 // Namespace provides template functions for the "openapi3".
 type Namespace struct {
-	cache *namedmemcache.Cache
-	deps  *deps.Deps
+	cache        *namedmemcache.Cache
+	deps         *deps.Deps
+	createClient *create.Client
 }
 
+// Unmarshal unmarshals the OpenAPI 3.0 document in r and resolves its $refs,
+// including those to files in /assets and remote URLs.
+// Use Load for OpenAPI 3.1.
 func (ns *Namespace) Unmarshal(r resource.UnmarshableResource) (*kopenapi3.T, error) {
 	key := r.Key()
 	if key == "" {
@@ -56,18 +63,7 @@ func (ns *Namespace) Unmarshal(r resource.UnmarshableResource) (*kopenapi3.T, er
 	}
 
 	v, err := ns.cache.GetOrCreate(key, func() (any, error) {
-		f := metadecoders.FormatFromMediaType(r.MediaType())
-		if f == "" {
-			return nil, fmt.Errorf("MIME %q not supported", r.MediaType())
-		}
-
-		reader, err := r.ReadSeekCloser()
-		if err != nil {
-			return nil, err
-		}
-		defer reader.Close()
-
-		b, err := ioutil.ReadAll(reader)
+		f, b, err := ns.read(r)
 		if err != nil {
 			return nil, err
 		}
@@ -80,10 +76,22 @@ func (ns *Namespace) Unmarshal(r resource.UnmarshableResource) (*kopenapi3.T, er
 			err = metadecoders.Default.UnmarshalTo(b, f, s)
 		}
 		if err != nil {
+			if strings.HasPrefix(s.OpenAPI, "3.1") {
+				return nil, fmt.Errorf("failed to unmarshal OpenAPI %s document, use openapi3.Load: %w", s.OpenAPI, err)
+			}
 			return nil, err
 		}
+		if strings.HasPrefix(s.OpenAPI, "3.1") {
+			return nil, fmt.Errorf("OpenAPI %s is not supported by openapi3.Unmarshal, use openapi3.Load", s.OpenAPI)
+		}
 
-		err = kopenapi3.NewLoader().ResolveRefsIn(s, nil)
+		loader := kopenapi3.NewLoader()
+		loader.IsExternalRefsAllowed = true
+		loader.ReadFromURIFunc = func(loader *kopenapi3.Loader, location *url.URL) ([]byte, error) {
+			return ns.readRef(location)
+		}
+
+		err = loader.ResolveRefsIn(s, ns.location(r))
 
 		return s, err
 	})
@@ -93,3 +101,93 @@ func (ns *Namespace) Unmarshal(r resource.UnmarshableResource) (*kopenapi3.T, er
 
 	return v.(*kopenapi3.T), nil
 }
+
+// Load loads the OpenAPI 3.0 or 3.1 document in r, resolves its $refs,
+// including those to files in /assets and remote URLs, and validates it.
+// The problems found are available in the Errors field.
+func (ns *Namespace) Load(r resource.UnmarshableResource) (*Document, error) {
+	key := r.Key()
+	if key == "" {
+		return nil, errors.New("no Key set in Resource")
+	}
+
+	v, err := ns.cache.GetOrCreate(key+"/load", func() (any, error) {
+		f, b, err := ns.read(r)
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err := metadecoders.Default.Unmarshal(b, f)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := raw.(map[string]any); !ok {
+			return nil, errors.New("not an OpenAPI document")
+		}
+
+		location := ns.location(r)
+		res := newResolver(ns)
+		res.docs[location.String()] = raw
+		resolved := res.resolve(raw, location).(map[string]any)
+
+		d := newDocument(resolved)
+		d.Errors = append(res.errors, validate(d)...)
+
+		return d, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*Document), nil
+}
+
+// Validate fails with the problems found in the OpenAPI document in r,
+// a resource or a Document, if any.
+func (ns *Namespace) Validate(r any) (string, error) {
+	var d *Document
+	switch v := r.(type) {
+	case *Document:
+		d = v
+	case resource.UnmarshableResource:
+		var err error
+		if d, err = ns.Load(v); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("%T is not an OpenAPI document", r)
+	}
+
+	if len(d.Errors) > 0 {
+		return "", fmt.Errorf("invalid OpenAPI document %q:\n%s", d.Info.Title, strings.Join(d.Errors, "\n"))
+	}
+
+	return "", nil
+}
+
+func (ns *Namespace) read(r resource.UnmarshableResource) (metadecoders.Format, []byte, error) {
+	f := metadecoders.FormatFromMediaType(r.MediaType())
+	if f == "" {
+		return "", nil, fmt.Errorf("MIME %q not supported", r.MediaType())
+	}
+
+	reader, err := r.ReadSeekCloser()
+	if err != nil {
+		return "", nil, err
+	}
+	defer reader.Close()
+
+	b, err := ioutil.ReadAll(reader)
+
+	return f, b, err
+}
+
+// location returns the location relative $refs in r are resolved against,
+// the path of r in /assets.
+func (ns *Namespace) location(r resource.UnmarshableResource) *url.URL {
+	name := "/"
+	if rr, ok := r.(resource.ResourceMetaProvider); ok {
+		name += strings.TrimPrefix(rr.Name(), "/")
+	}
+	return &url.URL{Path: name}
+}
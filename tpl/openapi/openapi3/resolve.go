@@ -0,0 +1,234 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi3
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/spf13/afero"
+)
+
+// readRef reads the document at location, a URL with a http(s) scheme or
+// a path in the assets file system.
+func (ns *Namespace) readRef(location *url.URL) ([]byte, error) {
+	if location.Scheme == "http" || location.Scheme == "https" {
+		u := *location
+		u.Fragment = ""
+		r, err := ns.createClient.FromRemote(u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			return nil, fmt.Errorf("%s not found", u.String())
+		}
+		rr, ok := r.(resource.ReadSeekCloserResource)
+		if !ok {
+			return nil, fmt.Errorf("%s: not readable", u.String())
+		}
+		rc, err := rr.ReadSeekCloser()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return ioutil.ReadAll(rc)
+	}
+
+	if location.Scheme != "" && location.Scheme != "file" {
+		return nil, fmt.Errorf("unsupported $ref scheme %q", location.Scheme)
+	}
+
+	filename := strings.TrimPrefix(path.Clean("/"+location.Path), "/")
+	b, err := afero.ReadFile(ns.deps.BaseFs.Assets.Fs, filename)
+	if err != nil {
+		return nil, fmt.Errorf("assets/%s: %w", filename, err)
+	}
+	return b, nil
+}
+
+// resolver replaces the $refs in an OpenAPI document with their targets.
+type resolver struct {
+	ns *Namespace
+
+	// The documents by location without fragment.
+	docs map[string]any
+
+	// The resolved targets by location with fragment.
+	resolved map[string]any
+
+	// The locations being resolved, to detect circular references.
+	resolving map[string]bool
+
+	errors []string
+}
+
+func newResolver(ns *Namespace) *resolver {
+	return &resolver{
+		ns:        ns,
+		docs:      make(map[string]any),
+		resolved:  make(map[string]any),
+		resolving: make(map[string]bool),
+	}
+}
+
+func (r *resolver) errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// resolve returns a copy of v, part of the document at base, with all
+// $refs replaced by their targets.
+// Circular references are left as is.
+func (r *resolver) resolve(v any, base *url.URL) any {
+	switch vv := v.(type) {
+	case map[string]any:
+		if ref, ok := vv["$ref"].(string); ok {
+			return r.resolveRef(vv, ref, base)
+		}
+		m := make(map[string]any, len(vv))
+		for k, v := range vv {
+			m[k] = r.resolve(v, base)
+		}
+		return m
+	case []any:
+		s := make([]any, len(vv))
+		for i, v := range vv {
+			s[i] = r.resolve(v, base)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+func (r *resolver) resolveRef(m map[string]any, ref string, base *url.URL) any {
+	u, err := url.Parse(ref)
+	if err != nil {
+		r.errorf("invalid $ref %q: %s", ref, err)
+		return m
+	}
+	target := base.ResolveReference(u)
+	key := target.String()
+
+	if r.resolving[key] {
+		// Circular.
+		return m
+	}
+
+	resolved, found := r.resolved[key]
+	if !found {
+		docLocation := *target
+		docLocation.Fragment = ""
+		doc, err := r.load(&docLocation)
+		if err != nil {
+			r.errorf("failed to resolve $ref %q: %s", ref, err)
+			return m
+		}
+
+		v, err := jsonPointer(doc, target.Fragment)
+		if err != nil {
+			r.errorf("failed to resolve $ref %q: %s", ref, err)
+			return m
+		}
+
+		r.resolving[key] = true
+		resolved = r.resolve(v, &docLocation)
+		delete(r.resolving, key)
+		r.resolved[key] = resolved
+	}
+
+	// In OpenAPI 3.1, e.g. summary and description next to $ref override
+	// those of the target.
+	if len(m) > 1 {
+		if rm, ok := resolved.(map[string]any); ok {
+			merged := make(map[string]any, len(rm)+len(m))
+			for k, v := range rm {
+				merged[k] = v
+			}
+			for k, v := range m {
+				if k != "$ref" {
+					merged[k] = r.resolve(v, base)
+				}
+			}
+			return merged
+		}
+	}
+
+	return resolved
+}
+
+func (r *resolver) load(location *url.URL) (any, error) {
+	key := location.String()
+	if doc, found := r.docs[key]; found {
+		return doc, nil
+	}
+
+	b, err := r.ns.readRef(location)
+	if err != nil {
+		return nil, err
+	}
+
+	f := metadecoders.FormatFromString(path.Ext(location.Path))
+	if f == "" || f == metadecoders.JSON {
+		// YAML is a superset of JSON.
+		f = metadecoders.YAML
+	}
+
+	doc, err := metadecoders.Default.Unmarshal(b, f)
+	if err != nil {
+		return nil, err
+	}
+	r.docs[key] = doc
+
+	return doc, nil
+}
+
+// jsonPointer returns the value in doc the JSON pointer p, e.g.
+// /components/schemas/User, points to.
+func jsonPointer(doc any, p string) (any, error) {
+	if p == "" || p == "/" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(p, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", p)
+	}
+
+	v := doc
+	for _, token := range strings.Split(p[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch vv := v.(type) {
+		case map[string]any:
+			var found bool
+			if v, found = vv[token]; !found {
+				return nil, fmt.Errorf("%q not found", p)
+			}
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(vv) {
+				return nil, fmt.Errorf("%q not found", p)
+			}
+			v = vv[i]
+		default:
+			return nil, errors.New("JSON pointer " + strconv.Quote(p) + " points into a scalar")
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi3
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	statusRe        = regexp.MustCompile(`^([1-5][0-9][0-9]|[1-5]XX|default)$`)
+	pathTemplateRe  = regexp.MustCompile(`\{([^}]+)\}`)
+	parameterInEnum = map[string]bool{"query": true, "header": true, "path": true, "cookie": true}
+)
+
+// validate returns the problems found in d.
+func validate(d *Document) []string {
+	var errors []string
+	errorf := func(format string, args ...any) {
+		errors = append(errors, fmt.Sprintf(format, args...))
+	}
+
+	is31 := strings.HasPrefix(d.OpenAPI, "3.1.")
+	switch {
+	case d.OpenAPI == "":
+		errorf("openapi: missing")
+	case !is31 && !strings.HasPrefix(d.OpenAPI, "3.0."):
+		errorf("openapi: unsupported version %q, must be 3.0.x or 3.1.x", d.OpenAPI)
+	}
+
+	if d.Raw["info"] == nil {
+		errorf("info: missing")
+	} else {
+		if d.Info.Title == "" {
+			errorf("info.title: missing")
+		}
+		if d.Info.Version == "" {
+			errorf("info.version: missing")
+		}
+		if d.Info.License.Identifier != "" && d.Info.License.URL != "" {
+			errorf("info.license: identifier and url are mutually exclusive")
+		}
+	}
+
+	if is31 {
+		if d.Raw["paths"] == nil && d.Raw["components"] == nil && d.Raw["webhooks"] == nil {
+			errorf("one of paths, components or webhooks is required")
+		}
+	} else if d.Raw["paths"] == nil {
+		errorf("paths: missing")
+	}
+
+	ids := make(map[string]string)
+	for _, op := range append(append([]*Operation{}, d.Operations...), d.Webhooks...) {
+		where := op.Method + " " + op.Path
+
+		if op.ID != "" {
+			if other, found := ids[op.ID]; found {
+				errorf("%s: operationId %q is also used by %s", where, op.ID, other)
+			}
+			ids[op.ID] = where
+		}
+
+		if !is31 && len(op.Responses) == 0 {
+			errorf("%s: responses: missing", where)
+		}
+		for _, r := range op.Responses {
+			if !statusRe.MatchString(r.Status) {
+				errorf("%s: responses: invalid status code %q", where, r.Status)
+			}
+			if r.Description == "" && !is31 {
+				errorf("%s: responses.%s.description: missing", where, r.Status)
+			}
+		}
+
+		pathParams := make(map[string]bool)
+		for _, p := range op.Parameters {
+			if p.Name == "" {
+				errorf("%s: parameter without name", where)
+				continue
+			}
+			if !parameterInEnum[p.In] {
+				errorf("%s: parameter %q: in must be one of query, header, path or cookie, got %q", where, p.Name, p.In)
+			}
+			if p.In == "path" {
+				pathParams[p.Name] = true
+				if !p.Required {
+					errorf("%s: path parameter %q must be required", where, p.Name)
+				}
+			}
+		}
+
+		for _, m := range pathTemplateRe.FindAllStringSubmatch(op.Path, -1) {
+			if !pathParams[m[1]] && !isWebhook(d, op) {
+				errorf("%s: path parameter %q not defined", where, m[1])
+			}
+		}
+	}
+
+	return errors
+}
+
+func isWebhook(d *Document, op *Operation) bool {
+	for _, w := range d.Webhooks {
+		if w == op {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package partials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/gohugoio/hugo/tpl"
+)
+
+// Future is a partial executing in the background, see IncludeAsync.
+type Future struct {
+	name    string
+	done    chan struct{}
+	awaited int32
+	result  any
+	err     error
+}
+
+// Wait waits for the partial to finish and returns its result, as
+// returned by Include.
+func (f *Future) Wait() (any, error) {
+	<-f.done
+	return f.result, f.err
+}
+
+// checkAwaited waits for the partial to finish and fails if it was never
+// awaited, as any error from it would otherwise be lost.
+func (f *Future) checkAwaited() error {
+	<-f.done
+	if atomic.LoadInt32(&f.awaited) == 1 {
+		return nil
+	}
+	if f.err != nil {
+		return fmt.Errorf("partial %q started with partials.IncludeAsync was never awaited and failed: %w", f.name, f.err)
+	}
+	return fmt.Errorf("partial %q started with partials.IncludeAsync was never awaited, use partials.Await to get its result", f.name)
+}
+
+// String is used when a Future is printed without waiting for it, which
+// is always a mistake.
+func (f *Future) String() string {
+	return fmt.Sprintf("partials.Future(%q)", f.name)
+}
+
+// IncludeAsync starts executing the named partial in the background and
+// returns a Future to wait for its result with Await.
+// The number of partials executing in parallel is limited; when at the limit,
+// the partial is executed before IncludeAsync returns.
+// The render IncludeAsync is called in waits for the partial to finish and
+// fails if it was never awaited.
+// Note that ctx is provided by Hugo, not the end user.
+func (ns *Namespace) IncludeAsync(ctx context.Context, name string, contextList ...any) (*Future, error) {
+	f := &Future{name: name, done: make(chan struct{})}
+	tpl.OnRenderDone(ctx, f.checkAwaited)
+
+	run := func() {
+		defer close(f.done)
+		defer func() {
+			if r := recover(); r != nil {
+				f.err = fmt.Errorf("partial %q panicked: %v", name, r)
+			}
+		}()
+		f.result, f.err = ns.Include(ctx, name, contextList...)
+	}
+
+	select {
+	case ns.asyncSlots <- struct{}{}:
+		go func() {
+			defer func() { <-ns.asyncSlots }()
+			run()
+		}()
+	default:
+		// All slots are taken, possibly by partials waiting for the one
+		// we're about to start, so run it here to avoid a deadlock.
+		run()
+	}
+
+	return f, nil
+}
+
+// Await waits for the given futures created with IncludeAsync and returns
+// their results in the order given, the result itself if only one.
+// It fails with the first error, in the order given.
+func (ns *Namespace) Await(futures ...any) (any, error) {
+	if len(futures) == 0 {
+		return nil, errors.New("partials.Await: no futures given")
+	}
+
+	results := make([]any, len(futures))
+	for i, v := range futures {
+		f, ok := v.(*Future)
+		if !ok {
+			return nil, fmt.Errorf("partials.Await: expected a future from partials.IncludeAsync, got %T", v)
+		}
+		atomic.StoreInt32(&f.awaited, 1)
+		result, err := f.Wait()
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+
+	if len(results) == 1 {
+		return results[0], nil
+	}
+
+	return results, nil
+}
@@ -48,6 +48,16 @@ func init() {
 			[][2]string{},
 		)
 
+		ns.AddMethodMapping(ctx.IncludeAsync,
+			nil,
+			[][2]string{},
+		)
+
+		ns.AddMethodMapping(ctx.Await,
+			nil,
+			[][2]string{},
+		)
+
 		return ns
 	}
 
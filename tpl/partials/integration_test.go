@@ -21,6 +21,7 @@ import (
 	"strings"
 	"testing"
 
+	qt "github.com/frankban/quicktest"
 	"github.com/gohugoio/hugo/htesting/hqt"
 	"github.com/gohugoio/hugo/hugolib"
 )
@@ -73,6 +74,10 @@ foo
 partialCached: foo
 partialCached: foo
 `)
+
+	hits, misses := b.H.BuildState.PartialCacheStats()
+	b.Assert(hits, qt.Equals, uint64(1))
+	b.Assert(misses, qt.Equals, uint64(1))
 }
 
 // Issue 9519
@@ -103,6 +108,117 @@ P2
 `)
 }
 
+func TestIncludeAsync(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = 'http://example.com/'
+-- layouts/index.html --
+{{ $a := partials.IncludeAsync "a.html" "A" }}
+{{ $b := partials.IncludeAsync "b.html" 32 }}
+{{ $nested := slice }}
+{{ range seq 30 }}
+{{ $nested = $nested | append (partials.IncludeAsync "nested.html" .) }}
+{{ end }}
+a: {{ partials.Await $a }}|
+b: {{ partials.Await $b }}|
+both: {{ range partials.Await $a $b }}{{ . }}|{{ end }}
+nested: {{ range $nested }}{{ partials.Await . }}{{ end }}|
+-- layouts/partials/a.html --
+<b>{{ . }}</b>
+-- layouts/partials/b.html --
+{{ return add . 10 }}
+-- layouts/partials/nested.html --
+{{ $child := partials.IncludeAsync "child.html" . }}
+{{- return printf "%d:%d," . (partials.Await $child) -}}
+-- layouts/partials/child.html --
+{{ return (mul . 2) }}
+  `
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	var nested strings.Builder
+	for i := 1; i <= 30; i++ {
+		fmt.Fprintf(&nested, "%d:%d,", i, i*2)
+	}
+
+	b.AssertFileContent("public/index.html",
+		"a: <b>A</b>\n|",
+		"b: 42|",
+		"both: <b>A</b>\n|42|",
+		"nested: "+nested.String()+"|",
+	)
+}
+
+func TestIncludeAsyncError(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = 'http://example.com/'
+-- layouts/index.html --
+{{ $a := partials.IncludeAsync "a.html" . }}
+{{ partials.Await $a }}
+-- layouts/partials/a.html --
+{{ .Foo }}
+  `
+
+	b, err := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, `partials/a.html:1:3": execute of template failed`)
+}
+
+func TestIncludeAsyncNotAwaited(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = 'http://example.com/'
+-- layouts/index.html --
+{{ $a := partials.IncludeAsync "a.html" . }}
+Home.
+-- layouts/partials/a.html --
+{{ .Title }}
+-- layouts/partials/b.html --
+{{ .Foo }}
+  `
+
+	b, err := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, `partial "a.html" started with partials.IncludeAsync was never awaited`)
+
+	files = strings.Replace(files, `"a.html"`, `"b.html"`, 1)
+
+	b, err = hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, `partial "b.html" started with partials.IncludeAsync was never awaited and failed`)
+	b.Assert(err.Error(), qt.Contains, `partials/b.html:1:3": execute of template failed`)
+}
+
 // Issue #588
 func TestIncludeCachedRecursionShortcode(t *testing.T) {
 	t.Parallel()
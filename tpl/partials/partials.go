@@ -34,6 +34,7 @@ import (
 	"github.com/gohugoio/hugo/tpl"
 
 	bp "github.com/gohugoio/hugo/bufferpool"
+	"github.com/gohugoio/hugo/config"
 	"github.com/gohugoio/hugo/deps"
 )
 
@@ -76,6 +77,7 @@ func New(deps *deps.Deps) *Namespace {
 	return &Namespace{
 		deps:           deps,
 		cachedPartials: cache,
+		asyncSlots:     make(chan struct{}, config.GetNumWorkerMultiplier()),
 	}
 }
 
@@ -83,6 +85,9 @@ func New(deps *deps.Deps) *Namespace {
 type Namespace struct {
 	deps           *deps.Deps
 	cachedPartials *partialCache
+
+	// Limits the number of partials executing in the background.
+	asyncSlots chan struct{}
 }
 
 // contextWrapper makes room for a return value in a partial invocation.
@@ -238,6 +243,7 @@ func (ns *Namespace) getOrCreate(ctx context.Context, key partialCacheKey, conte
 	ns.cachedPartials.RUnlock()
 
 	if ok {
+		ns.deps.BuildState.IncrPartialCache(true)
 		if ns.deps.Metrics != nil {
 			ns.deps.Metrics.TrackValue(key.templateName(), p, true)
 			// The templates that gets executed is measured in Execute.
@@ -260,6 +266,7 @@ func (ns *Namespace) getOrCreate(ctx context.Context, key partialCacheKey, conte
 	defer ns.cachedPartials.Unlock()
 	// Double-check.
 	if p2, ok := ns.cachedPartials.p[key]; ok {
+		ns.deps.BuildState.IncrPartialCache(true)
 		if ns.deps.Metrics != nil {
 			ns.deps.Metrics.TrackValue(key.templateName(), p, true)
 			ns.deps.Metrics.MeasureSince(key.templateName(), start)
@@ -267,6 +274,7 @@ func (ns *Namespace) getOrCreate(ctx context.Context, key partialCacheKey, conte
 		return p2, nil
 
 	}
+	ns.deps.BuildState.IncrPartialCache(false)
 	if ns.deps.Metrics != nil {
 		ns.deps.Metrics.TrackValue(key.templateName(), p, false)
 	}
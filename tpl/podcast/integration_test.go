@@ -0,0 +1,211 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podcast_test
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+// A CBR MP3 file with one second of 128 kbit/s audio.
+var mp3 = "\xff\xfb\x90\x00" + strings.Repeat("x", 15996)
+
+func TestPodcast(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+title = "My Site"
+languageCode = "en-us"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT"]
+[outputs]
+home = ["HTML", "Podcast"]
+section = ["HTML", "Podcast"]
+[podcast]
+author = "Jane Doe"
+image = "/cover.jpg"
+categories = ["Technology"]
+[podcast.owner]
+name = "Jane Doe"
+email = "jane@example.org"
+[podcast.spotify]
+limit = 10
+countryOfOrigin = ["NO", "se"]
+-- content/episodes/_index.md --
+---
+title: "The Show"
+description: "All about Hugo."
+podcast:
+  image: "show.jpg"
+  categories: ["Society & Culture > Documentary", "Technology"]
+  type: serial
+  explicit: "clean"
+---
+-- content/episodes/show.jpg --
+aW1hZ2U=
+-- content/episodes/e1/index.md --
+---
+title: "Episode 1"
+date: 2022-05-01T12:00:00Z
+description: "The first one."
+podcast:
+  season: 1
+  episode: 1
+---
+-- content/episodes/e1/audio.mp3 --
+` + mp3 + `
+-- content/episodes/e1/chapters.json --
+{"version": "1.2.0", "chapters": [{"startTime": 0, "title": "Intro"}]}
+-- content/episodes/e2.md --
+---
+title: "Episode 2"
+date: 2022-06-01T12:00:00Z
+podcast:
+  audio: "https://cdn.example.org/e2.m4a"
+  length: 1234
+  duration: "1:02:03"
+  episodeType: bonus
+  explicit: true
+  guid: "urn:example:e2"
+---
+Second episode.
+-- content/episodes/notes.md --
+---
+title: "Show Notes"
+---
+-- layouts/_default/single.html --
+{{ .Title }}
+-- layouts/_default/list.html --
+{{ .Title }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/episodes/podcast.xml",
+		`<title>The Show</title>`,
+		`<description>All about Hugo.</description>`,
+		`<language>en-us</language>`,
+		`<atom:link href="https://example.org/episodes/podcast.xml" rel="self" type="application/rss+xml" />`,
+		`<itunes:image href="https://example.org/episodes/show.jpg" />`,
+		`<itunes:category text="Society &amp; Culture"><itunes:category text="Documentary" /></itunes:category>`,
+		`<itunes:category text="Technology" />`,
+		`<itunes:explicit>false</itunes:explicit>`,
+		`<itunes:type>serial</itunes:type>`,
+		`<itunes:author>Jane Doe</itunes:author>`,
+		`<itunes:email>jane@example.org</itunes:email>`,
+		`<spotify:limit recentCount="10" />`,
+		`<spotify:countryOfOrigin>no se</spotify:countryOfOrigin>`,
+		`<enclosure url="https://example.org/episodes/e1/audio.mp3" length="16000" type="audio/mpeg" />`,
+		`<itunes:duration>1</itunes:duration>`,
+		`<itunes:season>1</itunes:season>`,
+		`<podcast:chapters url="https://example.org/episodes/e1/chapters.json" type="application/json+chapters" />`,
+		`<guid isPermaLink="false">urn:example:e2</guid>`,
+		`<description>Second episode.</description>`,
+		`<enclosure url="https://cdn.example.org/e2.m4a" length="1234" type="audio/mp4" />`,
+		`<itunes:duration>3723</itunes:duration>`,
+		`<itunes:episodeType>bonus</itunes:episodeType>`,
+		`<itunes:explicit>true</itunes:explicit>`,
+	)
+
+	content := b.FileContent("public/episodes/podcast.xml")
+	b.Assert(content, qt.Not(qt.Contains), "Show Notes")
+	b.Assert(strings.Index(content, "Episode 2") < strings.Index(content, "Episode 1"), qt.IsTrue)
+
+	b.AssertFileContent("public/podcast.xml",
+		`<title>My Site</title>`,
+		`<itunes:image href="https://example.org/cover.jpg" />`,
+		`<itunes:type>episodic</itunes:type>`,
+		`<title>Episode 1</title>`,
+	)
+}
+
+func TestPodcastValidation(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name      string
+		config    string
+		expectErr string
+	}{
+		{"no image", `categories = ["Technology"]`, "podcast image must be set"},
+		{"category", "image = \"/cover.jpg\"\ncategories = [\"Cooking\"]", `category "Cooking" is not an Apple Podcasts category`},
+		{"type", "image = \"/cover.jpg\"\ncategories = [\"Arts\"]\ntype = \"weekly\"", `type "weekly" must be episodic or serial`},
+		{"country", "image = \"/cover.jpg\"\ncategories = [\"Arts\"]\n[podcast.spotify]\ncountryOfOrigin = [\"norway\"]", `"norway" is not an ISO 3166 country code`},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "section"]
+[outputs]
+home = ["Podcast"]
+[podcast]
+` + test.config + `
+`
+
+			b, err := hugolib.NewIntegrationTestBuilder(
+				hugolib.IntegrationTestConfig{
+					T:           t,
+					TxtarString: files,
+				},
+			).BuildE()
+
+			b.Assert(err, qt.IsNotNil)
+			b.Assert(err.Error(), qt.Contains, test.expectErr)
+		})
+	}
+}
+
+func TestPodcastEpisodeValidation(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "section"]
+[outputs]
+home = ["Podcast"]
+[podcast]
+image = "/cover.jpg"
+categories = ["Arts"]
+-- content/e1.md --
+---
+title: "Episode 1"
+podcast:
+  audio: "https://cdn.example.org/e1.mp3"
+---
+`
+
+	b, err := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.IsNotNil)
+	b.Assert(err.Error(), qt.Contains, `e1.md: podcast length must be set for audio URL "https://cdn.example.org/e1.mp3"`)
+}
@@ -0,0 +1,588 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package podcast provides template functions to create podcast feeds.
+package podcast
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/resources/audio"
+	"github.com/gohugoio/hugo/resources/page"
+	"github.com/gohugoio/hugo/resources/resource"
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+// The front matter and site config key.
+const configKey = "podcast"
+
+// The Apple Podcasts categories, see
+// https://podcasters.apple.com/support/1691-apple-podcasts-categories
+var categories = map[string]bool{
+	"Arts":                    true,
+	"Business":                true,
+	"Comedy":                  true,
+	"Education":               true,
+	"Fiction":                 true,
+	"Government":              true,
+	"Health & Fitness":        true,
+	"History":                 true,
+	"Kids & Family":           true,
+	"Leisure":                 true,
+	"Music":                   true,
+	"News":                    true,
+	"Religion & Spirituality": true,
+	"Science":                 true,
+	"Society & Culture":       true,
+	"Sports":                  true,
+	"TV & Film":               true,
+	"Technology":              true,
+	"True Crime":              true,
+}
+
+var countryCodeRe = regexp.MustCompile(`^[a-z]{2}$`)
+
+// Config configures a show. The podcast section in site config holds the
+// defaults, the podcast section in the front matter of a section or the
+// home page those of its feed.
+type Config struct {
+	Author string
+	Owner  Owner
+
+	// The show artwork, a page resource of the section or home page or a
+	// URL relative to baseURL.
+	Image string
+
+	// The categories, e.g. "Technology" or "Society & Culture > Documentary".
+	Categories []string
+
+	Explicit any
+
+	// Either episodic or serial.
+	Type string
+
+	Copyright string
+
+	Spotify Spotify
+}
+
+// Owner is the contact of the show, not shown to listeners.
+type Owner struct {
+	Name  string
+	Email string
+}
+
+// Spotify holds the Spotify specific settings.
+type Spotify struct {
+	// The number of episodes shown in the Spotify app, 0 for all.
+	Limit int
+
+	// The ISO 3166 country codes of the intended audience.
+	CountryOfOrigin []string
+}
+
+// DecodeConfig decodes a podcast section in site config or front matter.
+func DecodeConfig(in any) (Config, error) {
+	var c Config
+	if in == nil {
+		return c, nil
+	}
+	if err := mapstructure.WeakDecode(in, &c); err != nil {
+		return c, fmt.Errorf("failed to decode podcast config: %w", err)
+	}
+	return c, nil
+}
+
+// New returns a new instance of the podcast-namespaced template functions.
+func New(d *deps.Deps) *Namespace {
+	ns := &Namespace{deps: d}
+	if v := d.Cfg.Get(configKey); v != nil {
+		if ns.cfg, ns.cfgErr = maps.ToStringMapE(v); ns.cfgErr == nil {
+			_, ns.cfgErr = DecodeConfig(ns.cfg)
+		}
+	}
+	return ns
+}
+
+// Namespace provides template functions for the "podcast" namespace.
+type Namespace struct {
+	deps   *deps.Deps
+	cfg    map[string]any
+	cfgErr error
+}
+
+// Show is the channel of a podcast feed.
+type Show struct {
+	Title       string
+	Description string
+	Link        string
+	Language    string
+	Copyright   string
+
+	Author string
+	Owner  Owner
+
+	// The absolute URL of the artwork.
+	Image string
+
+	Categories []Category
+	Explicit   bool
+	Type       string
+
+	Spotify Spotify
+}
+
+// Category is an Apple Podcasts category with an optional subcategory.
+type Category struct {
+	Name        string
+	Subcategory string
+}
+
+// Show returns the show of the feed of p, the home page or a section, with
+// the config in its front matter merged with the site config.
+func (ns *Namespace) Show(p page.Page) (*Show, error) {
+	if ns.cfgErr != nil {
+		return nil, ns.cfgErr
+	}
+
+	m := make(map[string]any)
+	for k, v := range ns.cfg {
+		m[k] = v
+	}
+	if pm, err := maps.ToStringMapE(p.Params()[configKey]); err == nil {
+		for k, v := range pm {
+			m[k] = v
+		}
+	}
+	cfg, err := DecodeConfig(m)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pagePath(p), err)
+	}
+
+	site := ns.deps.Site
+	s := &Show{
+		Title:       p.Title(),
+		Description: p.Description(),
+		Link:        p.Permalink(),
+		Language:    site.Language().GetString("languageCode"),
+		Copyright:   cfg.Copyright,
+		Author:      cfg.Author,
+		Owner:       cfg.Owner,
+		Type:        strings.ToLower(cfg.Type),
+		Spotify:     cfg.Spotify,
+	}
+	if s.Title == "" {
+		s.Title = site.Title()
+	}
+	if s.Description == "" {
+		s.Description = cast.ToString(site.Params()["description"])
+	}
+	if s.Description == "" {
+		s.Description = s.Title
+	}
+	if s.Language == "" {
+		s.Language = site.Language().Lang
+	}
+	if s.Copyright == "" {
+		s.Copyright = site.Language().GetString("copyright")
+	}
+
+	errorf := func(format string, a ...any) error {
+		return fmt.Errorf("%s: podcast %s", pagePath(p), fmt.Sprintf(format, a...))
+	}
+
+	if cfg.Image == "" {
+		return nil, errorf("image must be set")
+	}
+	s.Image = ns.resourceOrAbsURL(p, cfg.Image)
+
+	if len(cfg.Categories) == 0 {
+		return nil, errorf("categories must be set")
+	}
+	for _, c := range cfg.Categories {
+		name, sub, _ := strings.Cut(c, ">")
+		category := Category{Name: strings.TrimSpace(name), Subcategory: strings.TrimSpace(sub)}
+		if !categories[category.Name] {
+			return nil, errorf("category %q is not an Apple Podcasts category", category.Name)
+		}
+		s.Categories = append(s.Categories, category)
+	}
+
+	if s.Explicit, err = toExplicit(cfg.Explicit); err != nil {
+		return nil, errorf("%s", err)
+	}
+
+	switch s.Type {
+	case "":
+		s.Type = "episodic"
+	case "episodic", "serial":
+	default:
+		return nil, errorf("type %q must be episodic or serial", cfg.Type)
+	}
+
+	if s.Owner.Email != "" && !strings.Contains(s.Owner.Email, "@") {
+		return nil, errorf("owner email %q is invalid", s.Owner.Email)
+	}
+
+	if s.Spotify.Limit < 0 {
+		return nil, errorf("spotify limit must not be negative")
+	}
+	for i, cc := range s.Spotify.CountryOfOrigin {
+		cc = strings.ToLower(cc)
+		if !countryCodeRe.MatchString(cc) {
+			return nil, errorf("spotify country of origin %q is not an ISO 3166 country code", cc)
+		}
+		s.Spotify.CountryOfOrigin[i] = cc
+	}
+
+	return s, nil
+}
+
+// Episode is an item in a podcast feed.
+type Episode struct {
+	GUID        string
+	Title       string
+	Link        string
+	Description string
+	Date        time.Time
+
+	Enclosure Enclosure
+
+	Duration time.Duration
+
+	// The bitrate in bits per second, 0 if unknown.
+	Bitrate int
+
+	Season      int
+	Episode     int
+	EpisodeType string
+	Explicit    bool
+
+	// The absolute URL of the episode artwork.
+	Image string
+
+	// The chapters file, nil if none.
+	Chapters *Chapters
+}
+
+// Seconds returns the duration in whole seconds.
+func (e *Episode) Seconds() int {
+	return int(e.Duration.Round(time.Second) / time.Second)
+}
+
+// Enclosure is the audio file of an episode.
+type Enclosure struct {
+	URL    string
+	Length int64
+	Type   string
+}
+
+// Chapters is a Podcasting 2.0 JSON chapters file.
+type Chapters struct {
+	URL  string
+	Type string
+}
+
+// EpisodeConfig is the podcast section in the front matter of an episode.
+type EpisodeConfig struct {
+	// The audio file, a page resource or an absolute URL. Defaults to the
+	// first audio page resource.
+	Audio string
+
+	// The size in bytes and the duration of an audio URL. The duration, in
+	// seconds, as HH:MM:SS or as e.g. 1h2m3s, overrides the one read from
+	// the audio resource.
+	Length   int64
+	Duration any
+
+	Season      int
+	Episode     int
+	EpisodeType string
+	Explicit    any
+	Image       string
+
+	// The JSON chapters file, a page resource or an absolute URL.
+	// Defaults to a page resource named chapters.json.
+	Chapters string
+
+	// The GUID of the episode, defaulting to the permalink. Set it to keep
+	// the GUIDs of episodes moved from another host.
+	GUID string
+}
+
+// Episode returns the episode of p, nil if p has no audio.
+func (ns *Namespace) Episode(p page.Page) (*Episode, error) {
+	if ns.cfgErr != nil {
+		return nil, ns.cfgErr
+	}
+
+	var cfg EpisodeConfig
+	if v, found := p.Params()[configKey]; found {
+		if err := mapstructure.WeakDecode(v, &cfg); err != nil {
+			return nil, fmt.Errorf("%s: failed to decode podcast front matter: %w", pagePath(p), err)
+		}
+	}
+
+	errorf := func(format string, a ...any) error {
+		return fmt.Errorf("%s: podcast %s", pagePath(p), fmt.Sprintf(format, a...))
+	}
+
+	e := &Episode{
+		GUID:        cfg.GUID,
+		Title:       p.Title(),
+		Link:        p.Permalink(),
+		Description: p.Description(),
+		Date:        p.Date(),
+		Season:      cfg.Season,
+		Episode:     cfg.Episode,
+		EpisodeType: strings.ToLower(cfg.EpisodeType),
+	}
+	if e.GUID == "" {
+		e.GUID = e.Link
+	}
+	if e.Description == "" {
+		e.Description = strings.TrimSpace(tpl.StripHTML(string(p.Summary())))
+	}
+
+	if isURL(cfg.Audio) {
+		if cfg.Length <= 0 {
+			return nil, errorf("length must be set for audio URL %q", cfg.Audio)
+		}
+		e.Enclosure = Enclosure{URL: cfg.Audio, Length: cfg.Length, Type: ns.mediaType(cfg.Audio)}
+	} else {
+		var r resource.Resource
+		if cfg.Audio != "" {
+			if r = p.Resources().GetMatch(cfg.Audio); r == nil {
+				return nil, errorf("audio %q not found", cfg.Audio)
+			}
+		} else if audios := p.Resources().ByType("audio"); len(audios) > 0 {
+			r = audios[0]
+		} else {
+			return nil, nil
+		}
+
+		info, err := decodeAudio(r)
+		if err != nil {
+			return nil, errorf("audio %q: %s", r.Name(), err)
+		}
+		e.Enclosure = Enclosure{URL: r.Permalink(), Length: info.Size, Type: r.MediaType().Type()}
+		e.Duration = info.Duration
+		e.Bitrate = info.Bitrate
+	}
+
+	if cfg.Duration != nil {
+		d, err := toDuration(cfg.Duration)
+		if err != nil {
+			return nil, errorf("%s", err)
+		}
+		e.Duration = d
+	}
+
+	if e.Season < 0 || e.Episode < 0 {
+		return nil, errorf("season and episode must not be negative")
+	}
+	switch e.EpisodeType {
+	case "":
+		e.EpisodeType = "full"
+	case "full", "trailer", "bonus":
+	default:
+		return nil, errorf("episode type %q must be full, trailer or bonus", cfg.EpisodeType)
+	}
+
+	var err error
+	if e.Explicit, err = toExplicit(cfg.Explicit); err != nil {
+		return nil, errorf("%s", err)
+	}
+
+	if cfg.Image != "" {
+		e.Image = ns.resourceOrAbsURL(p, cfg.Image)
+	}
+
+	if e.Chapters, err = ns.chapters(p, cfg.Chapters); err != nil {
+		return nil, errorf("chapters: %s", err)
+	}
+
+	return e, nil
+}
+
+// Episodes returns the episodes of the pages in pages with audio, newest
+// first.
+func (ns *Namespace) Episodes(pages any) ([]*Episode, error) {
+	ps, err := page.ToPages(pages)
+	if err != nil {
+		return nil, err
+	}
+
+	var episodes []*Episode
+	for _, p := range ps {
+		e, err := ns.Episode(p)
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			episodes = append(episodes, e)
+		}
+	}
+
+	sort.SliceStable(episodes, func(i, j int) bool {
+		return episodes[i].Date.After(episodes[j].Date)
+	})
+
+	return episodes, nil
+}
+
+const chaptersType = "application/json+chapters"
+
+func (ns *Namespace) chapters(p page.Page, name string) (*Chapters, error) {
+	if isURL(name) {
+		return &Chapters{URL: name, Type: chaptersType}, nil
+	}
+
+	var r resource.Resource
+	if name != "" {
+		if r = p.Resources().GetMatch(name); r == nil {
+			return nil, fmt.Errorf("%q not found", name)
+		}
+	} else if r = p.Resources().GetMatch("chapters.json"); r == nil {
+		return nil, nil
+	}
+
+	cr, ok := r.(resource.ContentResource)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a JSON file", r.Name())
+	}
+	content, err := cr.Content()
+	if err != nil {
+		return nil, err
+	}
+
+	var v struct {
+		Version  string
+		Chapters []struct {
+			StartTime *float64
+			Title     string
+		}
+	}
+	if err := json.Unmarshal([]byte(cast.ToString(content)), &v); err != nil {
+		return nil, fmt.Errorf("%q: %w", r.Name(), err)
+	}
+	if v.Version == "" || len(v.Chapters) == 0 {
+		return nil, fmt.Errorf("%q must have a version and chapters", r.Name())
+	}
+	for i, c := range v.Chapters {
+		if c.StartTime == nil {
+			return nil, fmt.Errorf("%q: chapter %d has no startTime", r.Name(), i+1)
+		}
+	}
+
+	return &Chapters{URL: r.Permalink(), Type: chaptersType}, nil
+}
+
+func decodeAudio(r resource.Resource) (audio.Info, error) {
+	rr, ok := r.(resource.ReadSeekCloserResource)
+	if !ok {
+		return audio.Info{}, errors.New("not a file")
+	}
+	f, err := rr.ReadSeekCloser()
+	if err != nil {
+		return audio.Info{}, err
+	}
+	defer f.Close()
+
+	info, err := audio.Decode(f, rr.MediaType().FirstSuffix.Suffix)
+	if err == audio.ErrUnsupported {
+		// Publish it without duration and bitrate.
+		info.Size, err = f.Seek(0, io.SeekEnd)
+	}
+
+	return info, err
+}
+
+func (ns *Namespace) mediaType(url string) string {
+	suffix := strings.TrimPrefix(path.Ext(strings.SplitN(url, "?", 2)[0]), ".")
+	if mt, _, found := ns.deps.ResourceSpec.MediaTypes.GetFirstBySuffix(suffix); found {
+		return mt.Type()
+	}
+	return "audio/mpeg"
+}
+
+func (ns *Namespace) resourceOrAbsURL(p page.Page, s string) string {
+	if isURL(s) {
+		return s
+	}
+	if r := p.Resources().GetMatch(s); r != nil {
+		return r.Permalink()
+	}
+	return ns.deps.PathSpec.AbsURL(s, false)
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+func toExplicit(v any) (bool, error) {
+	if v == nil {
+		return false, nil
+	}
+	switch strings.ToLower(cast.ToString(v)) {
+	case "true", "yes", "explicit":
+		return true, nil
+	case "false", "no", "clean", "":
+		return false, nil
+	}
+	return false, fmt.Errorf("explicit %v must be true or false", v)
+}
+
+// toDuration converts seconds, HH:MM:SS, MM:SS or a Go duration, e.g. 1h2m3s.
+func toDuration(v any) (time.Duration, error) {
+	s := strings.TrimSpace(cast.ToString(v))
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(n * float64(time.Second)), nil
+	}
+	if strings.Contains(s, ":") {
+		var d time.Duration
+		for _, part := range strings.Split(s, ":") {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			d = d*60 + time.Duration(n)
+		}
+		return d * time.Second, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+func pagePath(p page.Page) string {
+	if p.File() != nil {
+		return p.File().Path()
+	}
+	return p.Path()
+}
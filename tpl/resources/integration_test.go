@@ -14,6 +14,12 @@
 package resources_test
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
 	"testing"
 
 	qt "github.com/frankban/quicktest"
@@ -98,3 +104,128 @@ func TestCopyPageShouldFail(t *testing.T) {
 	b.Assert(err, qt.IsNotNil)
 
 }
+
+func TestZipAndTar(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "http://example.com/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "section"]
+-- content/samples/index.md --
+---
+title: "Samples"
+---
+-- content/samples/hello/main.go --
+package main
+-- content/samples/README.txt --
+Read me.
+-- layouts/_default/single.html --
+{{ $zip := resources.Zip "samples.zip" . | fingerprint }}
+{{ $tar := resources.Tar "downloads/samples.tar.gz" .Resources }}
+{{ $txt := resources.Tar "text.tar" (slice ("a" | resources.FromString "a.txt") ("b" | resources.FromString "b/b.txt")) }}
+Zip: {{ $zip.RelPermalink }}|{{ $zip.MediaType }}|
+Tar: {{ $tar.RelPermalink }}|{{ $tar.MediaType }}|
+Txt: {{ $txt.RelPermalink }}|{{ $txt.MediaType }}|
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		}).Build()
+
+	b.AssertFileContent("public/samples/index.html",
+		"Zip: /samples.",
+		".zip|application/zip|",
+		"Tar: /downloads/samples.tar.gz|application/gzip|",
+		"Txt: /text.tar|application/x-tar|",
+	)
+
+	content := b.FileContent("public/samples/index.html")
+	zipPath := content[strings.Index(content, "Zip: ")+5 : strings.Index(content, "|application/zip")]
+
+	zr, err := zip.NewReader(bytes.NewReader([]byte(b.FileContent("public"+zipPath))), int64(len(b.FileContent("public"+zipPath))))
+	b.Assert(err, qt.IsNil)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	b.Assert(names, qt.DeepEquals, []string{"README.txt", "hello/main.go"})
+
+	readTar := func(r io.Reader) map[string]string {
+		m := make(map[string]string)
+		tr := tar.NewReader(r)
+		for {
+			h, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			b.Assert(err, qt.IsNil)
+			c, err := io.ReadAll(tr)
+			b.Assert(err, qt.IsNil)
+			m[h.Name] = string(c)
+		}
+		return m
+	}
+
+	gzr, err := gzip.NewReader(strings.NewReader(b.FileContent("public/downloads/samples.tar.gz")))
+	b.Assert(err, qt.IsNil)
+	b.Assert(readTar(gzr), qt.DeepEquals, map[string]string{"README.txt": "Read me.", "hello/main.go": "package main"})
+
+	b.Assert(readTar(strings.NewReader(b.FileContent("public/text.tar"))), qt.DeepEquals, map[string]string{"a.txt": "a", "b/b.txt": "b"})
+
+	// The same resources give the same archive.
+	b2 := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		}).Build()
+	b2.AssertFileContent("public/samples/index.html", "Zip: "+zipPath+"|")
+}
+
+func TestFromStringBinary(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "http://example.com/"
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "section", "404"]
+-- assets/pixel.txt --
+iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg==
+-- assets/event.ics --
+BEGIN:VEVENT
+SUMMARY:{{ .Title }}
+END:VEVENT
+-- layouts/index.html --
+{{ $b64 := (resources.Get "pixel.txt").Content }}
+{{ $png := resources.FromString "images/pixel.png" (dict "base64" true) $b64 }}
+{{ $detected := resources.FromString "images/pixel" (dict "base64" true) $b64 }}
+{{ $decoded := $b64 | base64Decode | resources.FromString "images/decoded.png" }}
+{{ $icon := resources.FromString "favicon.ico" (dict "base64" true "mediaType" "image/x-icon") $b64 }}
+{{ $ics := resources.Get "event.ics" | resources.ExecuteAsTemplate (dict "targetPath" "event.ics" "mediaType" "text/calendar") . }}
+PNG: {{ $png.RelPermalink }}|{{ $png.MediaType }}|{{ $png.Width }}|
+Detected: {{ $detected.RelPermalink }}|{{ $detected.MediaType }}|
+Decoded: {{ $decoded.RelPermalink }}|{{ $decoded.MediaType }}|{{ $decoded.Width }}|
+Icon: {{ $icon.RelPermalink }}|{{ $icon.MediaType }}|
+ICS: {{ $ics.RelPermalink }}|{{ $ics.MediaType }}|
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		}).Build()
+
+	b.AssertFileContent("public/index.html",
+		"PNG: /images/pixel.png|image/png|1|",
+		"Detected: /images/pixel|image/png|",
+		"Decoded: /images/decoded.png|image/png|1|",
+		"Icon: /favicon.ico|image/x-icon|",
+		"ICS: /event.ics|text/calendar|",
+	)
+
+	b.AssertFileContent("public/event.ics", "SUMMARY:")
+	b.AssertDestinationExists("images/pixel.png", true)
+	b.AssertDestinationExists("favicon.ico", true)
+}
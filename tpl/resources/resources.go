@@ -15,7 +15,10 @@
 package resources
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"path"
 	"sync"
 
 	"github.com/gohugoio/hugo/common/herrors"
@@ -27,6 +30,7 @@ import (
 	"github.com/gohugoio/hugo/tpl/internal/resourcehelpers"
 
 	"github.com/gohugoio/hugo/helpers"
+	"github.com/gohugoio/hugo/media"
 	"github.com/gohugoio/hugo/resources/postpub"
 
 	"github.com/gohugoio/hugo/deps"
@@ -38,11 +42,13 @@ import (
 	"github.com/gohugoio/hugo/resources/resource_transformers/babel"
 	"github.com/gohugoio/hugo/resources/resource_transformers/integrity"
 	"github.com/gohugoio/hugo/resources/resource_transformers/minifier"
+	"github.com/gohugoio/hugo/resources/resource_transformers/nodedaemon"
 	"github.com/gohugoio/hugo/resources/resource_transformers/postcss"
 	"github.com/gohugoio/hugo/resources/resource_transformers/templates"
 	"github.com/gohugoio/hugo/resources/resource_transformers/tocss/dartsass"
 	"github.com/gohugoio/hugo/resources/resource_transformers/tocss/scss"
 
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/cast"
 )
 
@@ -62,6 +68,12 @@ func New(deps *deps.Deps) (*Namespace, error) {
 		return nil, err
 	}
 
+	var daemon *nodedaemon.Daemon
+	if deps.ResourceSpec.BuildConfig.UseNodeDaemon {
+		daemon = nodedaemon.New(deps.ResourceSpec)
+		deps.BuildClosers.Add(daemon)
+	}
+
 	return &Namespace{
 		deps:              deps,
 		scssClientLibSass: scssClient,
@@ -69,9 +81,9 @@ func New(deps *deps.Deps) (*Namespace, error) {
 		bundlerClient:     bundler.New(deps.ResourceSpec),
 		integrityClient:   integrity.New(deps.ResourceSpec),
 		minifyClient:      minifyClient,
-		postcssClient:     postcss.New(deps.ResourceSpec),
+		postcssClient:     postcss.New(deps.ResourceSpec, daemon),
 		templatesClient:   templates.New(deps.ResourceSpec, deps),
-		babelClient:       babel.New(deps.ResourceSpec),
+		babelClient:       babel.New(deps.ResourceSpec, daemon),
 	}, nil
 }
 
@@ -168,6 +180,10 @@ func (ns *Namespace) GetRemote(args ...any) resource.Resource {
 
 	r, err := get(args...)
 	if err != nil {
+		if ns.deps.Strict.RemoteFetch {
+			// Fail the build even if the error is handled in the template.
+			ns.deps.Log.Errorf("Failed to get remote resource: %s", err)
+		}
 		switch v := err.(type) {
 		case *create.HTTPError:
 			return resources.NewErrorResource(resource.NewResourceError(v, v.Data))
@@ -261,29 +277,150 @@ func (ns *Namespace) Concat(targetPathIn any, r any) (resource.Resource, error)
 	return ns.bundlerClient.Concat(targetPath, rr)
 }
 
-// FromString creates a Resource from a string published to the relative target path.
-func (ns *Namespace) FromString(targetPathIn, contentIn any) (resource.Resource, error) {
+// Zip creates a zip archive of a slice of Resource objects or of the
+// resources of a page bundle, published to the relative target path.
+func (ns *Namespace) Zip(targetPathIn any, r any) (resource.Resource, error) {
+	targetPath, rr, err := ns.archiveArgs("zip", targetPathIn, r)
+	if err != nil {
+		return nil, err
+	}
+	return ns.bundlerClient.Zip(targetPath, rr)
+}
+
+// Tar creates a tar archive of a slice of Resource objects or of the
+// resources of a page bundle, published to the relative target path.
+// The archive is compressed with gzip if the target path ends with .tar.gz or .tgz.
+func (ns *Namespace) Tar(targetPathIn any, r any) (resource.Resource, error) {
+	targetPath, rr, err := ns.archiveArgs("tar", targetPathIn, r)
+	if err != nil {
+		return nil, err
+	}
+	return ns.bundlerClient.Tar(targetPath, rr)
+}
+
+func (ns *Namespace) archiveArgs(fn string, targetPathIn any, r any) (string, resource.Resources, error) {
 	targetPath, err := cast.ToStringE(targetPathIn)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var rr resource.Resources
+
+	switch v := r.(type) {
+	case resource.Resources:
+		rr = v
+	case resource.ResourcesConverter:
+		rr = v.ToResources()
+	case interface{ Resources() resource.Resources }:
+		// A page bundle.
+		rr = v.Resources()
+	default:
+		return "", nil, fmt.Errorf("%T not supported in %s", r, fn)
+	}
+
+	if len(rr) == 0 {
+		return "", nil, fmt.Errorf("must provide one or more Resource objects to %s", fn)
+	}
+
+	return targetPath, rr, nil
+}
+
+// FromString creates a Resource from a string published to the relative target path.
+// You can optionally provide an options map before the content with
+// mediaType (default resolved from the target path or the content) and
+// base64 (decode the content from base64, for binary content).
+func (ns *Namespace) FromString(args ...any) (resource.Resource, error) {
+	if len(args) < 2 || len(args) > 3 {
+		return nil, errors.New("must provide a target path, optional options and the content")
+	}
+	targetPath, err := cast.ToStringE(args[0])
 	if err != nil {
 		return nil, err
 	}
-	content, err := cast.ToStringE(contentIn)
+
+	var opts fromStringOptions
+	if len(args) == 3 {
+		if err := mapstructure.WeakDecode(args[1], &opts); err != nil {
+			return nil, fmt.Errorf("failed to decode options: %w", err)
+		}
+	}
+
+	var content []byte
+	switch v := args[len(args)-1].(type) {
+	case []byte:
+		content = v
+	default:
+		s, err := cast.ToStringE(v)
+		if err != nil {
+			return nil, err
+		}
+		content = []byte(s)
+	}
+
+	if opts.Base64 {
+		b := make([]byte, base64.StdEncoding.DecodedLen(len(content)))
+		n, err := base64.StdEncoding.Decode(b, bytes.TrimSpace(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64 content: %w", err)
+		}
+		content = b[:n]
+	}
+
+	mediaType, err := ns.resolveMediaType(opts.MediaType, targetPath)
 	if err != nil {
 		return nil, err
 	}
 
-	return ns.createClient.FromString(targetPath, content)
+	return ns.createClient.FromBytes(targetPath, content, mediaType)
+}
+
+type fromStringOptions struct {
+	// The media type, e.g. image/png.
+	MediaType string
+
+	// Whether the content is base64 encoded.
+	Base64 bool
+}
+
+// resolveMediaType resolves s, a media type such as image/png, using
+// the configured media types. An empty s resolves to the zero type.
+func (ns *Namespace) resolveMediaType(s, targetPath string) (media.Type, error) {
+	if s == "" {
+		return media.Type{}, nil
+	}
+	if m, found := ns.deps.ResourceSpec.MediaTypes.GetByType(s); found {
+		return m, nil
+	}
+	m, err := media.FromStringAndExt(s, path.Ext(targetPath))
+	if err != nil {
+		return media.Type{}, err
+	}
+	return m, nil
 }
 
 // ExecuteAsTemplate creates a Resource from a Go template, parsed and executed with
 // the given data, and published to the relative target path.
+// Instead of the target path you can provide an options map with
+// targetPath and mediaType (default the media type of the template Resource).
 func (ns *Namespace) ExecuteAsTemplate(args ...any) (resource.Resource, error) {
 	if len(args) != 3 {
 		return nil, fmt.Errorf("must provide targetPath, the template data context and a Resource object")
 	}
-	targetPath, err := cast.ToStringE(args[0])
-	if err != nil {
-		return nil, err
+
+	var opts executeAsTemplateOptions
+	if m, err := maps.ToStringMapE(args[0]); err == nil {
+		if err := mapstructure.WeakDecode(m, &opts); err != nil {
+			return nil, fmt.Errorf("failed to decode options: %w", err)
+		}
+		if opts.TargetPath == "" {
+			return nil, errors.New("must provide a targetPath")
+		}
+	} else {
+		targetPath, err := cast.ToStringE(args[0])
+		if err != nil {
+			return nil, err
+		}
+		opts.TargetPath = targetPath
 	}
 	data := args[1]
 
@@ -292,7 +429,17 @@ func (ns *Namespace) ExecuteAsTemplate(args ...any) (resource.Resource, error) {
 		return nil, fmt.Errorf("type %T not supported in Resource transformations", args[2])
 	}
 
-	return ns.templatesClient.ExecuteAsTemplate(r, targetPath, data)
+	mediaType, err := ns.resolveMediaType(opts.MediaType, opts.TargetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return ns.templatesClient.ExecuteAsTemplate(r, opts.TargetPath, mediaType, data)
+}
+
+type executeAsTemplateOptions struct {
+	TargetPath string
+	MediaType  string
 }
 
 // Fingerprint transforms the given Resource with a MD5 hash of the content in
@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"unicode"
 
 	bp "github.com/gohugoio/hugo/bufferpool"
@@ -55,6 +56,117 @@ type UnusedTemplatesProvider interface {
 	UnusedTemplates() []FileInfo
 }
 
+// TemplatesLister lists the loaded templates and shortcodes.
+type TemplatesLister interface {
+	// Templates returns the templates loaded from files, sorted by name.
+	Templates() []FileInfo
+
+	// Shortcodes returns the shortcodes, sorted by name.
+	Shortcodes() []ShortcodeInfo
+
+	// Partials returns the partials loaded from files, sorted by name.
+	Partials() []PartialInfo
+}
+
+// LayoutExplainer explains the template lookup of the layouts.
+type LayoutExplainer interface {
+	// ExplainLayout returns the templates evaluated for d and f, in lookup
+	// order. The first found is the one used.
+	ExplainLayout(d output.LayoutDescriptor, f output.Format) []LayoutCandidate
+}
+
+// LayoutCandidate is a template evaluated in a layout lookup.
+type LayoutCandidate struct {
+	// The template name, e.g. posts/single.html.
+	Name string
+
+	// Whether the template exists.
+	Found bool
+
+	// The filename of the template, empty for the embedded templates.
+	Filename string
+
+	// The path of the module the template comes from, empty for the
+	// project and the embedded templates.
+	Module string
+
+	// The filename of the base template applied, if any.
+	Baseof string
+}
+
+// The sources of the values passed to the functions disabling the escaping,
+// from the most to the least risky.
+const (
+	EscapeSourceRemote  = "remote"
+	EscapeSourceParam   = "param"
+	EscapeSourceData    = "data"
+	EscapeSourceContent = "content"
+	EscapeSourceOther   = "other"
+	EscapeSourceLiteral = "literal"
+)
+
+// EscapeAuditor reports the uses of the functions disabling the context-aware
+// escaping of the HTML templates, e.g. safeHTML.
+type EscapeAuditor interface {
+	// EscapeAudit returns the uses in the project and module templates,
+	// sorted by the risk of their source, then by filename and position.
+	EscapeAudit() ([]EscapeAuditEntry, error)
+}
+
+// EscapeAuditEntry is a use of safeHTML, safeJS, safeURL etc. in a template.
+type EscapeAuditEntry struct {
+	// The filename of the template.
+	Filename string
+
+	Line   int
+	Column int
+
+	// The function used, e.g. safeHTML.
+	Func string
+
+	// Where the value comes from, one of remote, param, data, content, other
+	// or literal.
+	Source string
+
+	// The template expression, e.g. .Params.banner | safeHTML.
+	Expr string
+}
+
+// ShortcodeInfo describes a shortcode.
+type ShortcodeInfo struct {
+	Name string
+
+	// The filename of the template, empty for the embedded shortcodes.
+	Filename string
+
+	// The documentation, i.e. the text of the comment the template starts with.
+	Doc string
+
+	// The named parameters read by the template, sorted.
+	Params []string
+
+	// The number of positional parameters read by the template.
+	PositionalParams int
+
+	// Whether the template uses .Inner.
+	Inner bool
+}
+
+// PartialInfo describes a partial.
+type PartialInfo struct {
+	// The name without the partials/ prefix, e.g. header.html.
+	Name string
+
+	Filename string
+
+	// The documentation, i.e. the text of the comment the template starts with.
+	Doc string
+
+	// The fields of the context read by the template, sorted, e.g. the keys
+	// of the dict passed to it.
+	Params []string
+}
+
 // TemplateHandler finds and executes templates.
 type TemplateHandler interface {
 	TemplateFinder
@@ -169,6 +281,26 @@ func SetHasLockInContext(ctx context.Context, hasLock bool) context.Context {
 	return context.WithValue(ctx, texttemplate.HasLockContextKey, hasLock)
 }
 
+type templateChainContextKeyType string
+
+const templateChainContextKey = templateChainContextKeyType("templateChain")
+
+// AddTemplateToContext returns a copy of ctx with the template name appended
+// to the chain of templates being executed.
+func AddTemplateToContext(ctx context.Context, name string) context.Context {
+	chain := GetTemplateChainFromContext(ctx)
+	return context.WithValue(ctx, templateChainContextKey, append(chain[:len(chain):len(chain)], name))
+}
+
+// GetTemplateChainFromContext returns the names of the templates being
+// executed in ctx, outermost first, e.g. the layout and the partials it calls.
+func GetTemplateChainFromContext(ctx context.Context) []string {
+	if v := ctx.Value(templateChainContextKey); v != nil {
+		return v.([]string)
+	}
+	return nil
+}
+
 const hugoNewLinePlaceholder = "___hugonl_"
 
 var (
@@ -209,3 +341,59 @@ func StripHTML(s string) string {
 
 	return s
 }
+
+type renderDoneContextKeyType string
+
+const renderDoneContextKey = renderDoneContextKeyType("renderDone")
+
+// renderDone holds the funcs to run when the outermost template in a
+// render has finished executing.
+type renderDone struct {
+	mu    sync.Mutex
+	funcs []func() error
+}
+
+// WithRenderDone returns a copy of ctx to execute the outermost template of
+// a render in and a func to call when it has finished executing. The func
+// runs the funcs registered with OnRenderDone, in order, and returns the
+// first error. If ctx already belongs to a render, it is returned as is
+// together with a no-op func.
+func WithRenderDone(ctx context.Context) (context.Context, func() error) {
+	if ctx.Value(renderDoneContextKey) != nil {
+		return ctx, func() error { return nil }
+	}
+
+	rd := &renderDone{}
+
+	return context.WithValue(ctx, renderDoneContextKey, rd), func() error {
+		var firstErr error
+		for i := 0; ; i++ {
+			// The funcs may register new funcs, e.g. by waiting for
+			// partials that start other partials.
+			rd.mu.Lock()
+			if i >= len(rd.funcs) {
+				rd.mu.Unlock()
+				return firstErr
+			}
+			fn := rd.funcs[i]
+			rd.mu.Unlock()
+
+			if err := fn(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+}
+
+// OnRenderDone registers fn to be called when the render ctx belongs to has
+// finished executing. It returns false if ctx does not belong to a render.
+func OnRenderDone(ctx context.Context, fn func() error) bool {
+	rd, ok := ctx.Value(renderDoneContextKey).(*renderDone)
+	if !ok {
+		return false
+	}
+	rd.mu.Lock()
+	rd.funcs = append(rd.funcs, fn)
+	rd.mu.Unlock()
+	return true
+}
@@ -6,6 +6,7 @@ import (
 
 	qt "github.com/frankban/quicktest"
 	"github.com/gohugoio/hugo/hugolib"
+	"github.com/gohugoio/hugo/identity"
 	"github.com/gohugoio/hugo/tpl"
 )
 
@@ -60,6 +61,63 @@ title: "P1"
 	b.Assert(unused[0].Filename(), qt.Equals, filepath.Join(b.Cfg.WorkingDir, "layouts/_default/single.json"))
 }
 
+func TestTemplatesAndShortcodes(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = 'http://example.com/'
+-- layouts/index.html --
+Home.
+-- layouts/partials/p.html --
+Partial.
+-- layouts/shortcodes/note.html --
+{{/*
+  Renders a note.
+
+  Usage: {{< note >}}Text{{< /note >}}
+*/}}
+<div class="note">{{ .Inner }}</div>
+-- layouts/shortcodes/note.en.html --
+{{/* Renders an English note. */}}
+-- layouts/shortcodes/plain.html --
+Plain.
+	`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+			NeedsOsFS:   true,
+		},
+	).Build()
+
+	lister := b.H.Tmpl().(tpl.TemplatesLister)
+
+	var names []string
+	for _, tmpl := range lister.Templates() {
+		names = append(names, tmpl.Name())
+	}
+	b.Assert(names, qt.DeepEquals, []string{"index.html", "partials/p.html", "shortcodes/note.en.html", "shortcodes/note.html", "shortcodes/plain.html"})
+
+	shortcodes := make(map[string]tpl.ShortcodeInfo)
+	for _, sc := range lister.Shortcodes() {
+		shortcodes[sc.Name] = sc
+	}
+	b.Assert(shortcodes["note"].Doc, qt.Equals, "Renders a note.\n\n  Usage: {{< note >}}Text{{< /note >}}")
+	b.Assert(shortcodes["note"].Filename, qt.Equals, filepath.Join(b.Cfg.WorkingDir, "layouts/shortcodes/note.html"))
+	b.Assert(shortcodes["plain"].Doc, qt.Equals, "")
+	youtube, found := shortcodes["youtube"]
+	b.Assert(found, qt.IsTrue)
+	b.Assert(youtube.Filename, qt.Equals, "")
+	b.Assert(youtube.Params, qt.Contains, "id")
+	b.Assert(youtube.PositionalParams, qt.Equals, 2)
+
+	partials := lister.Partials()
+	b.Assert(partials, qt.HasLen, 1)
+	b.Assert(partials[0].Name, qt.Equals, "p.html")
+}
+
 // Verify that the new keywords in Go 1.18 is available.
 func TestGo18Constructs(t *testing.T) {
 	t.Parallel()
@@ -115,3 +173,56 @@ counter2: 3
 `)
 
 }
+
+func TestDeadBranchElimination(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = 'http://example.com/'
+disableKinds = ["taxonomy", "term", "rss", "sitemap", "robotsTXT", "404", "section"]
+defaultContentLanguage = "en"
+[params]
+comments = false
+theme = "dark"
+[languages]
+[languages.en]
+weight = 1
+[languages.en.params]
+banner = true
+[languages.nn]
+weight = 2
+-- content/p1.md --
+---
+title: "P1"
+---
+-- layouts/index.html --
+{{ $v := "outer" }}
+{{ if site.Params.comments }}{{ partial "comments.html" . }}{{ else }}{{ $v := "inner" }}No comments: {{ $v }}|{{ end }}
+v: {{ $v }}|
+{{ if eq site.Params.theme "dark" }}{{ partial "dark.html" . }}{{ end }}
+{{ with site.Params.missing }}{{ partial "does-not-exist.html" . }}{{ else }}Missing: {{ .Kind }}|{{ end }}
+{{ if site.Params.banner }}Banner|{{ else }}No banner|{{ end }}
+-- layouts/partials/comments.html --
+Comments|
+-- layouts/partials/dark.html --
+Dark|
+	`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html", "No comments: inner|", "v: outer|", "Dark|", "Missing: home|", "Banner|")
+	b.AssertFileContent("public/nn/index.html", "No comments: inner|", "v: outer|", "Dark|", "Missing: home|", "No banner|")
+
+	// The partials in dead branches are not dependencies of the template.
+	templ, found := b.H.Tmpl().Lookup("index.html")
+	b.Assert(found, qt.IsTrue)
+	deps := templ.(identity.Manager)
+	b.Assert(deps.Search(identity.NewPathIdentity("layouts", "partials/dark.html")), qt.IsNotNil)
+	b.Assert(deps.Search(identity.NewPathIdentity("layouts", "partials/comments.html")), qt.IsNil)
+}
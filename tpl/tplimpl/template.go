@@ -31,6 +31,7 @@ import (
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/gohugoio/hugo/common/maps"
 	"github.com/gohugoio/hugo/common/types"
 
 	"github.com/gohugoio/hugo/helpers"
@@ -74,6 +75,8 @@ var (
 	_ tpl.TemplateFuncGetter      = (*templateExec)(nil)
 	_ tpl.TemplateFinder          = (*templateExec)(nil)
 	_ tpl.UnusedTemplatesProvider = (*templateExec)(nil)
+	_ tpl.TemplatesLister         = (*templateExec)(nil)
+	_ tpl.LayoutExplainer         = (*templateExec)(nil)
 
 	_ tpl.Template = (*templateState)(nil)
 	_ tpl.Info     = (*templateState)(nil)
@@ -127,12 +130,22 @@ func newStandaloneTextTemplate(funcs map[string]any) tpl.TemplateParseFinder {
 }
 
 func newTemplateExec(d *deps.Deps) (*templateExec, error) {
-	exec, funcs := newTemplateExecuter(d)
+	plugins, err := loadFuncsPlugins(d)
+	if err != nil {
+		return nil, err
+	}
+
+	exec, funcs := newTemplateExecuter(d, plugins)
 	funcMap := make(map[string]any)
 	for k, v := range funcs {
 		funcMap[k] = v.Interface()
 	}
 
+	layoutRules, err := output.DecodeLayoutRulesConfig(d.Cfg.Get("layoutLookup"))
+	if err != nil {
+		return nil, err
+	}
+
 	var templateUsageTracker map[string]templateInfo
 	if d.Cfg.GetBool("printUnusedTemplates") {
 		templateUsageTracker = make(map[string]templateInfo)
@@ -151,11 +164,13 @@ func newTemplateExec(d *deps.Deps) (*templateExec, error) {
 		main: newTemplateNamespace(funcMap),
 
 		Deps:                d,
-		layoutHandler:       output.NewLayoutHandler(),
+		layoutHandler:       output.NewLayoutHandlerWithRules(layoutRules),
 		layoutsFs:           d.BaseFs.Layouts.Fs,
 		layoutTemplateCache: make(map[layoutCacheKey]tpl.Template),
 
 		templateUsageTracker: templateUsageTracker,
+
+		siteParams: siteParamsForConstantFolding(d),
 	}
 
 	if err := h.loadEmbedded(); err != nil {
@@ -170,6 +185,7 @@ func newTemplateExec(d *deps.Deps) (*templateExec, error) {
 		d:               d,
 		executor:        exec,
 		funcs:           funcs,
+		funcsPlugins:    plugins,
 		templateHandler: h,
 	}
 
@@ -215,11 +231,14 @@ type templateExec struct {
 	executor texttemplate.Executer
 	funcs    map[string]reflect.Value
 
+	// The template func namespaces loaded from the modules' plugins.
+	funcsPlugins []funcsPlugin
+
 	*templateHandler
 }
 
 func (t templateExec) Clone(d *deps.Deps) *templateExec {
-	exec, funcs := newTemplateExecuter(d)
+	exec, funcs := newTemplateExecuter(d, t.funcsPlugins)
 	t.executor = exec
 	t.funcs = funcs
 	t.d = d
@@ -255,13 +274,35 @@ func (t *templateExec) ExecuteWithContext(ctx context.Context, templ tpl.Templat
 		}
 	}
 
+	ctx, renderDone := tpl.WithRenderDone(ctx)
+	ctx = tpl.AddTemplateToContext(ctx, templ.Name())
+
 	execErr := t.executor.ExecuteWithContext(ctx, templ, wr, data)
 	if execErr != nil {
 		execErr = t.addFileContext(templ, execErr)
 	}
+	if err := renderDone(); err != nil && execErr == nil {
+		execErr = err
+	}
 	return execErr
 }
 
+func (t *templateExec) Templates() []tpl.FileInfo {
+	var templates []tpl.FileInfo
+	for _, ts := range t.main.templates {
+		if ts.info.realFilename == "" {
+			continue
+		}
+		templates = append(templates, ts.info)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name() < templates[j].Name()
+	})
+
+	return templates
+}
+
 func (t *templateExec) UnusedTemplates() []tpl.FileInfo {
 	if t.templateUsageTracker == nil {
 		return nil
@@ -355,6 +396,10 @@ type templateHandler struct {
 	// May be nil.
 	templateUsageTracker   map[string]templateInfo
 	templateUsageTrackerMu sync.Mutex
+
+	// The site.Params of all languages, used to remove the template
+	// branches that are never executed.
+	siteParams []maps.Params
 }
 
 // AddTemplate parses and adds a template to the collection.
@@ -498,6 +543,34 @@ func (t *templateHandler) findLayout(d output.LayoutDescriptor, f output.Format)
 	return nil, false, nil
 }
 
+func (t *templateHandler) ExplainLayout(d output.LayoutDescriptor, f output.Format) []tpl.LayoutCandidate {
+	layouts, _ := t.layoutHandler.For(d, f)
+	candidates := make([]tpl.LayoutCandidate, len(layouts))
+	for i, name := range layouts {
+		c := tpl.LayoutCandidate{Name: name}
+		if templ, found := t.main.Lookup(name); found {
+			ts := templ.(*templateState)
+			c.Found = true
+			c.Filename = ts.info.realFilename
+			c.Module = ts.info.module
+		} else if overlay, found := t.needsBaseof[name]; found {
+			c.Found = true
+			c.Filename = overlay.realFilename
+			c.Module = overlay.module
+			d.Baseof = true
+			baseLayouts, _ := t.layoutHandler.For(d, f)
+			for _, l := range baseLayouts {
+				if base, found := t.baseof[l]; found {
+					c.Baseof = base.realFilename
+					break
+				}
+			}
+		}
+		candidates[i] = c
+	}
+	return candidates
+}
+
 func (t *templateHandler) findTemplate(name string) *templateState {
 	if templ, found := t.Lookup(name); found {
 		return templ.(*templateState)
@@ -620,9 +693,14 @@ func (t *templateHandler) addTemplateFile(name, path string) error {
 		s := removeLeadingBOM(string(b))
 
 		realFilename := filename
+		var module string
 		if fi, err := fs.Stat(filename); err == nil {
 			if fim, ok := fi.(hugofs.FileMetaInfo); ok {
-				realFilename = fim.Meta().Filename
+				meta := fim.Meta()
+				realFilename = meta.Filename
+				if !meta.IsProject {
+					module = meta.Module
+				}
 			}
 		}
 
@@ -635,6 +713,7 @@ func (t *templateHandler) addTemplateFile(name, path string) error {
 			template:     s,
 			filename:     filename,
 			realFilename: realFilename,
+			module:       module,
 			fs:           fs,
 		}, nil
 	}
@@ -722,7 +801,7 @@ func (t *templateHandler) applyBaseTemplate(overlay, base templateInfo) (tpl.Tem
 }
 
 func (t *templateHandler) applyTemplateTransformers(ns *templateNamespace, ts *templateState) (*templateContext, error) {
-	c, err := applyTemplateTransformers(ts, ns.newTemplateLookup(ts))
+	c, err := applyTemplateTransformers(ts, ns.newTemplateLookup(ts), t.siteParams)
 	if err != nil {
 		return nil, err
 	}
@@ -769,7 +848,11 @@ func (t *templateHandler) loadEmbedded() error {
 		}
 
 		if _, found := t.Lookup(templateName); !found {
-			if err := t.AddTemplate(templateName, templ); err != nil {
+			addName := templateName
+			if outputFormat, found := t.OutputFormatsConfig.FromFilename(filepath.Base(name)); found && outputFormat.IsPlainText {
+				addName = textTmplNamePrefix + templateName
+			}
+			if err := t.AddTemplate(addName, templ); err != nil {
 				return err
 			}
 		}
@@ -855,7 +938,7 @@ func (t *templateHandler) extractPartials(templ tpl.Template) error {
 		if !found {
 			t.main.mu.Lock()
 			// This is a template defined inline.
-			_, err := applyTemplateTransformers(ts, t.main.newTemplateLookup(ts))
+			_, err := applyTemplateTransformers(ts, t.main.newTemplateLookup(ts), t.siteParams)
 			if err != nil {
 				t.main.mu.Unlock()
 				return err
@@ -904,7 +987,7 @@ func (t *templateHandler) postTransform() error {
 		lookup := t.main.newTemplateLookup(source)
 		templ := lookup(name)
 		if templ != nil {
-			_, err := applyTemplateTransformers(templ, lookup)
+			_, err := applyTemplateTransformers(templ, lookup, t.siteParams)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,206 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tplimpl
+
+import (
+	"github.com/gohugoio/hugo/common/hreflect"
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/langs"
+	"github.com/gohugoio/hugo/tpl/compare"
+	texttemplate "github.com/gohugoio/hugo/tpl/internal/go_templates/texttemplate"
+	"github.com/gohugoio/hugo/tpl/internal/go_templates/texttemplate/parse"
+)
+
+// truePipe is the pipeline of an if whose condition is always true.
+var truePipe *parse.PipeNode
+
+var constCompare = compare.New(false)
+
+func init() {
+	templ, err := texttemplate.New("").Parse(`{{ if true }}{{ end }}`)
+	if err != nil {
+		panic(err)
+	}
+	truePipe = templ.Tree.Root.Nodes[0].(*parse.IfNode).Pipe
+}
+
+// siteParamsForConstantFolding returns the params of all the languages,
+// which are all the possible values of site.Params in the templates.
+func siteParamsForConstantFolding(d *deps.Deps) []maps.Params {
+	if languages, ok := d.Cfg.Get("languagesSorted").(langs.Languages); ok && len(languages) > 0 {
+		params := make([]maps.Params, len(languages))
+		for i, l := range languages {
+			params[i] = l.Params()
+		}
+		return params
+	}
+	if d.Language != nil {
+		return []maps.Params{d.Language.Params()}
+	}
+	return nil
+}
+
+// foldConstantBranches removes the dead branches of the if and with
+// statements in nodes whose condition only depends on site.Params,
+// with the same outcome in all languages, e.g. {{ if site.Params.featureX }}.
+// The live branch of an if is kept inside an {{ if true }} to keep the
+// scope of its variables.
+func (c *templateContext) foldConstantBranches(nodes []parse.Node) []parse.Node {
+	if len(c.siteParams) == 0 {
+		return nodes
+	}
+
+	folded := nodes[:0]
+	for _, n := range nodes {
+		var branch *parse.BranchNode
+		switch x := n.(type) {
+		case *parse.IfNode:
+			branch = &x.BranchNode
+		case *parse.WithNode:
+			branch = &x.BranchNode
+		default:
+			folded = append(folded, n)
+			continue
+		}
+
+		truth, ok := c.constTruth(branch.Pipe)
+		if !ok {
+			folded = append(folded, n)
+			continue
+		}
+
+		switch {
+		case truth && n.Type() == parse.NodeWith:
+			// with sets the dot to the value, keep it as is.
+			folded = append(folded, n)
+		case truth:
+			branch.Pipe = truePipe.CopyPipe()
+			branch.ElseList = nil
+			folded = append(folded, n)
+		case branch.ElseList != nil:
+			folded = append(folded, &parse.IfNode{
+				BranchNode: parse.BranchNode{
+					NodeType: parse.NodeIf,
+					Pos:      branch.Pos,
+					Line:     branch.Line,
+					Pipe:     truePipe.CopyPipe(),
+					List:     branch.ElseList,
+				},
+			})
+		}
+	}
+
+	return folded
+}
+
+// constTruth returns the truth value of the condition pipe if it's the same
+// for all the site params.
+func (c *templateContext) constTruth(pipe *parse.PipeNode) (truth, ok bool) {
+	for i, params := range c.siteParams {
+		t, ok := evalConstTruth(pipe, params)
+		if !ok || (i > 0 && t != truth) {
+			return false, false
+		}
+		truth = t
+	}
+	return truth, true
+}
+
+// evalConstTruth evaluates the truth value of n, which must be built from
+// site.Params values, literals and the not, and, or, eq and ne functions.
+func evalConstTruth(n parse.Node, params maps.Params) (truth, ok bool) {
+	switch x := n.(type) {
+	case *parse.PipeNode:
+		if len(x.Decl) > 0 || len(x.Cmds) != 1 {
+			return false, false
+		}
+		return evalConstTruth(x.Cmds[0], params)
+	case *parse.CommandNode:
+		if len(x.Args) == 1 {
+			return evalConstTruth(x.Args[0], params)
+		}
+		ident, isIdent := x.Args[0].(*parse.IdentifierNode)
+		if !isIdent {
+			return false, false
+		}
+		args := x.Args[1:]
+		switch ident.Ident {
+		case "not":
+			if len(args) != 1 {
+				return false, false
+			}
+			truth, ok = evalConstTruth(args[0], params)
+			return !truth, ok
+		case "and", "or":
+			if len(args) == 0 {
+				return false, false
+			}
+			// Both stop at the first deciding argument, so all
+			// arguments before it must be constant.
+			for _, arg := range args {
+				truth, ok = evalConstTruth(arg, params)
+				if !ok {
+					return false, false
+				}
+				if truth == (ident.Ident == "or") {
+					return truth, true
+				}
+			}
+			return truth, true
+		case "eq", "ne":
+			if len(args) != 2 {
+				return false, false
+			}
+			v1, ok1 := evalConstValue(args[0], params)
+			v2, ok2 := evalConstValue(args[1], params)
+			if !ok1 || !ok2 {
+				return false, false
+			}
+			eq := constCompare.Eq(v1, v2)
+			return eq == (ident.Ident == "eq"), true
+		}
+		return false, false
+	default:
+		v, ok := evalConstValue(n, params)
+		if !ok {
+			return false, false
+		}
+		return hreflect.IsTruthful(v), true
+	}
+}
+
+// evalConstValue returns the value of the literal or site.Params value n.
+func evalConstValue(n parse.Node, params maps.Params) (any, bool) {
+	switch x := n.(type) {
+	case *parse.ChainNode:
+		ident, ok := x.Node.(*parse.IdentifierNode)
+		if !ok || ident.Ident != "site" || len(x.Field) < 2 || x.Field[0] != "Params" {
+			return nil, false
+		}
+		return params.Get(x.Field[1:]...), true
+	case *parse.BoolNode:
+		return x.True, true
+	case *parse.StringNode:
+		return x.Text, true
+	case *parse.NumberNode:
+		if x.IsInt {
+			return x.Int64, true
+		}
+		if x.IsFloat {
+			return x.Float64, true
+		}
+	}
+	return nil, false
+}
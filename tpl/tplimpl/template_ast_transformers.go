@@ -54,6 +54,9 @@ type templateContext struct {
 
 	// Store away the return node in partials.
 	returnNode *parse.CommandNode
+
+	// The site params of all languages, used to remove dead branches.
+	siteParams []maps.Params
 }
 
 func (c templateContext) getIfNotVisited(name string) *templateState {
@@ -86,12 +89,14 @@ func newTemplateContext(
 
 func applyTemplateTransformers(
 	t *templateState,
-	lookupFn func(name string) *templateState) (*templateContext, error) {
+	lookupFn func(name string) *templateState,
+	siteParams []maps.Params) (*templateContext, error) {
 	if t == nil {
 		return nil, errors.New("expected template, but none provided")
 	}
 
 	c := newTemplateContext(t, lookupFn)
+	c.siteParams = siteParams
 	tree := getParseTree(t.Template)
 
 	_, err := c.applyTransformations(tree.Root)
@@ -147,13 +152,15 @@ func (c *templateContext) wrapInPartialReturnWrapper(n *parse.ListNode) *parse.L
 	return wrapper
 }
 
-// applyTransformations do 2 things:
+// applyTransformations do 3 things:
 // 1) Parses partial return statement.
-// 2) Tracks template (partial) dependencies and some other info.
+// 2) Removes dead branches depending on site.Params only.
+// 3) Tracks template (partial) dependencies and some other info.
 func (c *templateContext) applyTransformations(n parse.Node) (bool, error) {
 	switch x := n.(type) {
 	case *parse.ListNode:
 		if x != nil {
+			x.Nodes = c.foldConstantBranches(x.Nodes)
 			c.applyTransformationsToNodes(x.Nodes...)
 		}
 	case *parse.ActionNode:
@@ -279,7 +286,7 @@ func (c *templateContext) collectInner(n *parse.CommandNode) {
 	}
 }
 
-var partialRe = regexp.MustCompile(`^partial(Cached)?$|^partials\.Include(Cached)?$`)
+var partialRe = regexp.MustCompile(`^partial(Cached)?$|^partials\.Include(Cached|Async)?$`)
 
 func (c *templateContext) collectPartialInfo(x *parse.CommandNode) {
 	if len(x.Args) < 2 {
@@ -18,6 +18,7 @@ import (
 	template "github.com/gohugoio/hugo/tpl/internal/go_templates/htmltemplate"
 
 	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/common/maps"
 	"github.com/gohugoio/hugo/tpl"
 )
 
@@ -158,3 +159,56 @@ func TestPartialReturn(t *testing.T) {
 		})
 	}
 }
+
+func TestFoldConstantBranches(t *testing.T) {
+	tests := []struct {
+		name      string
+		tplString string
+		expected  string
+	}{
+		{"If true", `{{ if site.Params.on }}A{{ else }}B{{ end }}`, `{{if true}}A{{end}}`},
+		{"If false", `{{ if site.Params.off }}A{{ end }}C`, `C`},
+		{"If false else", `{{ if site.Params.off }}A{{ else }}B{{ end }}`, `{{if true}}B{{end}}`},
+		{"Else if", `{{ if site.Params.off }}A{{ else if site.Params.on }}B{{ else }}C{{ end }}`, `{{if true}}{{if true}}B{{end}}{{end}}`},
+		{"Missing", `{{ if site.Params.nested.missing }}A{{ end }}`, ``},
+		{"Nested", `{{ if site.Params.nested.on }}A{{ end }}`, `{{if true}}A{{end}}`},
+		{"Not", `{{ if not site.Params.off }}A{{ end }}`, `{{if true}}A{{end}}`},
+		{"And", `{{ if and site.Params.on site.Params.off }}A{{ end }}`, ``},
+		{"Or", `{{ if or site.Params.off site.Params.on }}A{{ end }}`, `{{if true}}A{{end}}`},
+		{"Eq", `{{ if eq site.Params.color "blue" }}A{{ end }}{{ if ne site.Params.count 3 }}B{{ end }}`, `{{if true}}A{{end}}`},
+		{"With false", `{{ with site.Params.off }}A{{ else }}B{{ end }}`, `{{if true}}B{{end}}`},
+		{"With true", `{{ with site.Params.on }}{{ . }}{{ end }}`, `{{with site.Params.on}}{{.}}{{end}}`},
+		{"Differs per language", `{{ if site.Params.lang }}A{{ end }}`, `{{if site.Params.lang}}A{{end}}`},
+		{"Not constant", `{{ if and site.Params.on .Title }}A{{ end }}`, `{{if and site.Params.on .Title}}A{{end}}`},
+		{"Declaration", `{{ if $v := site.Params.off }}A{{ end }}`, `{{if $v := site.Params.off}}A{{end}}`},
+		{"Inside range", `{{ range .Pages }}{{ if site.Params.off }}A{{ end }}{{ end }}`, `{{range .Pages}}{{end}}`},
+	}
+
+	funcs := template.FuncMap{
+		"site": func() any { return nil },
+	}
+
+	siteParams := []maps.Params{
+		{"on": true, "off": false, "color": "blue", "count": 3, "nested": maps.Params{"on": "yes"}, "lang": true},
+		{"on": true, "off": "", "color": "blue", "count": 3, "nested": maps.Params{"on": "yes"}, "lang": false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := qt.New(t)
+
+			templ, err := template.New("foo").Funcs(funcs).Parse(test.tplString)
+			c.Assert(err, qt.IsNil)
+			ts := newTestTemplate(templ)
+			ctx := newTemplateContext(
+				ts,
+				newTestTemplateLookup(ts),
+			)
+			ctx.siteParams = siteParams
+
+			_, err = ctx.applyTransformations(templ.Tree.Root)
+			c.Assert(err, qt.IsNil)
+			c.Assert(templ.Tree.Root.String(), qt.Equals, test.expected)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tplimpl
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/gohugoio/hugo/tpl/internal/go_templates/texttemplate/parse"
+)
+
+// templateDocRe matches the comment a shortcode or partial template starts with.
+var templateDocRe = regexp.MustCompile(`(?s)^\s*{{-?\s*/\*(.*?)\*/\s*-?}}`)
+
+func templateDoc(src string) string {
+	if m := templateDocRe.FindStringSubmatch(src); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	return ""
+}
+
+func (t *templateExec) Shortcodes() []tpl.ShortcodeInfo {
+	var shortcodes []tpl.ShortcodeInfo
+	for name, templs := range t.shortcodes {
+		if len(templs.variants) == 0 {
+			continue
+		}
+		// Prefer the variant without language and output format,
+		// e.g. note.html.
+		ts := templs.variants[0].ts
+		for _, v := range templs.variants {
+			if v.variants[0] == "" && v.variants[1] == v.variants[2] {
+				ts = v.ts
+				break
+			}
+		}
+
+		params := collectTemplateParams(ts.info.name, ts.info.template, true)
+
+		shortcodes = append(shortcodes, tpl.ShortcodeInfo{
+			Name:             name,
+			Filename:         ts.info.realFilename,
+			Doc:              templateDoc(ts.info.template),
+			Params:           params.namedSorted(),
+			PositionalParams: params.positional,
+			Inner:            ts.parseInfo.IsInner,
+		})
+	}
+
+	sort.Slice(shortcodes, func(i, j int) bool {
+		return shortcodes[i].Name < shortcodes[j].Name
+	})
+
+	return shortcodes
+}
+
+func (t *templateExec) Partials() []tpl.PartialInfo {
+	var partials []tpl.PartialInfo
+	for name, ts := range t.main.templates {
+		if !strings.HasPrefix(name, "partials/") || ts.info.realFilename == "" {
+			continue
+		}
+
+		params := collectTemplateParams(ts.info.name, ts.info.template, false)
+
+		partials = append(partials, tpl.PartialInfo{
+			Name:     strings.TrimPrefix(name, "partials/"),
+			Filename: ts.info.realFilename,
+			Doc:      templateDoc(ts.info.template),
+			Params:   params.namedSorted(),
+		})
+	}
+
+	sort.Slice(partials, func(i, j int) bool {
+		return partials[i].Name < partials[j].Name
+	})
+
+	return partials
+}
+
+// templateParams holds the parameters a shortcode or partial template reads.
+type templateParams struct {
+	shortcode bool
+
+	named map[string]bool
+
+	// The highest positional shortcode parameter read plus one.
+	positional int
+}
+
+func (p *templateParams) namedSorted() []string {
+	var names []string
+	for name := range p.named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectTemplateParams collects the parameters the template source reads
+// from its context: .Get, .Params.name and index .Params for shortcodes,
+// the fields of the context, e.g. a dict, for partials.
+// The source is parsed again as the parsed templates are modified by the
+// AST transformers.
+func collectTemplateParams(name, src string, shortcode bool) *templateParams {
+	p := &templateParams{shortcode: shortcode, named: make(map[string]bool)}
+
+	trees := make(map[string]*parse.Tree)
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(src, "", "", trees); err != nil {
+		// Errors are reported when the template is parsed for real.
+		return p
+	}
+
+	for _, tree := range trees {
+		if tree.Root != nil {
+			p.walk(tree.Root, true)
+		}
+	}
+
+	return p
+}
+
+// walk collects the parameters read in n.
+// dotIsContext is false in with and range blocks, where dot is rebound.
+func (p *templateParams) walk(n parse.Node, dotIsContext bool) {
+	switch nt := n.(type) {
+	case *parse.ListNode:
+		if nt == nil {
+			return
+		}
+		for _, n := range nt.Nodes {
+			p.walk(n, dotIsContext)
+		}
+	case *parse.ActionNode:
+		p.walk(nt.Pipe, dotIsContext)
+	case *parse.TemplateNode:
+		if nt.Pipe != nil {
+			p.walk(nt.Pipe, dotIsContext)
+		}
+	case *parse.IfNode:
+		p.walkBranch(&nt.BranchNode, dotIsContext, dotIsContext)
+	case *parse.WithNode:
+		p.walkBranch(&nt.BranchNode, dotIsContext, false)
+	case *parse.RangeNode:
+		p.walkBranch(&nt.BranchNode, dotIsContext, false)
+	case *parse.PipeNode:
+		if nt == nil {
+			return
+		}
+		for _, cmd := range nt.Cmds {
+			p.walk(cmd, dotIsContext)
+		}
+	case *parse.CommandNode:
+		p.collectCommand(nt, dotIsContext)
+		for _, arg := range nt.Args {
+			p.walk(arg, dotIsContext)
+		}
+	case *parse.ChainNode:
+		p.walk(nt.Node, dotIsContext)
+	case *parse.FieldNode:
+		if dotIsContext {
+			p.collectField(nt.Ident)
+		}
+	case *parse.VariableNode:
+		if len(nt.Ident) > 1 && nt.Ident[0] == "$" {
+			p.collectField(nt.Ident[1:])
+		}
+	}
+}
+
+func (p *templateParams) walkBranch(n *parse.BranchNode, dotIsContext, listDotIsContext bool) {
+	p.walk(n.Pipe, dotIsContext)
+	p.walk(n.List, listDotIsContext)
+	p.walk(n.ElseList, dotIsContext)
+}
+
+func (p *templateParams) collectField(idents []string) {
+	if !p.shortcode {
+		p.named[idents[0]] = true
+		return
+	}
+	if len(idents) > 1 && idents[0] == "Params" {
+		p.named[idents[1]] = true
+	}
+}
+
+// collectCommand collects the shortcode parameters read with
+// .Get and index .Params, and the partial parameters read with index.
+func (p *templateParams) collectCommand(n *parse.CommandNode, dotIsContext bool) {
+	if len(n.Args) < 2 {
+		return
+	}
+
+	var key parse.Node
+	if p.shortcode && p.isContext(n.Args[0], dotIsContext, "Get") {
+		key = n.Args[1]
+	} else if id, ok := n.Args[0].(*parse.IdentifierNode); ok && id.Ident == "index" && len(n.Args) > 2 {
+		if p.shortcode && p.isContext(n.Args[1], dotIsContext, "Params") || !p.shortcode && p.isContext(n.Args[1], dotIsContext) {
+			key = n.Args[2]
+		}
+	}
+
+	switch kt := key.(type) {
+	case *parse.StringNode:
+		p.named[kt.Text] = true
+	case *parse.NumberNode:
+		if p.shortcode && kt.IsInt {
+			if i, err := strconv.Atoi(kt.Text); err == nil && i >= p.positional {
+				p.positional = i + 1
+			}
+		}
+	}
+}
+
+// isContext reports whether n is the template context followed by idents,
+// e.g. .Get or $.Get.
+func (p *templateParams) isContext(n parse.Node, dotIsContext bool, idents ...string) bool {
+	switch nt := n.(type) {
+	case *parse.DotNode:
+		return dotIsContext && len(idents) == 0
+	case *parse.FieldNode:
+		return dotIsContext && equalIdents(nt.Ident, idents)
+	case *parse.VariableNode:
+		return len(nt.Ident) > 0 && nt.Ident[0] == "$" && equalIdents(nt.Ident[1:], idents)
+	}
+	return false
+}
+
+func equalIdents(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -33,6 +33,9 @@ type templateInfo struct {
 
 	// The real filename (if possible). Used for logging.
 	realFilename string
+
+	// The path of the module the file comes from, empty for the project.
+	module string
 }
 
 func (t templateInfo) Name() string {
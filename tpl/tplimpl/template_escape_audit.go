@@ -0,0 +1,343 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tplimpl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/tpl"
+	"github.com/gohugoio/hugo/tpl/internal/go_templates/texttemplate/parse"
+)
+
+var _ tpl.EscapeAuditor = (*templateExec)(nil)
+
+// The functions disabling the escaping, see the safe namespace.
+var escapeAuditFuncs = map[string]bool{
+	"safeCSS":       true,
+	"safeHTML":      true,
+	"safeHTMLAttr":  true,
+	"safeJS":        true,
+	"safeJSStr":     true,
+	"safeURL":       true,
+	"safe.CSS":      true,
+	"safe.HTML":     true,
+	"safe.HTMLAttr": true,
+	"safe.JS":       true,
+	"safe.JSStr":    true,
+	"safe.URL":      true,
+}
+
+// The sources by field or method name, e.g. .Params.
+var escapeAuditFieldSources = map[string]string{
+	"GetRemote":        tpl.EscapeSourceRemote,
+	"Params":           tpl.EscapeSourceParam,
+	"Param":            tpl.EscapeSourceParam,
+	"RawFrontMatter":   tpl.EscapeSourceParam,
+	"Title":            tpl.EscapeSourceParam,
+	"LinkTitle":        tpl.EscapeSourceParam,
+	"Description":      tpl.EscapeSourceParam,
+	"Get":              tpl.EscapeSourceParam,
+	"Data":             tpl.EscapeSourceData,
+	"Content":          tpl.EscapeSourceContent,
+	"RawContent":       tpl.EscapeSourceContent,
+	"RawContentSource": tpl.EscapeSourceContent,
+	"Summary":          tpl.EscapeSourceContent,
+	"Plain":            tpl.EscapeSourceContent,
+	"TableOfContents":  tpl.EscapeSourceContent,
+	"Inner":            tpl.EscapeSourceContent,
+	"RenderString":     tpl.EscapeSourceContent,
+}
+
+// The sources by function name.
+var escapeAuditFuncSources = map[string]string{
+	"getJSON": tpl.EscapeSourceRemote,
+	"getCSV":  tpl.EscapeSourceRemote,
+}
+
+var escapeSourceRisk = map[string]int{
+	tpl.EscapeSourceRemote:  5,
+	tpl.EscapeSourceParam:   4,
+	tpl.EscapeSourceData:    3,
+	tpl.EscapeSourceContent: 2,
+	tpl.EscapeSourceOther:   1,
+	tpl.EscapeSourceLiteral: 0,
+}
+
+// mostRisky returns the most risky of the sources a and b, where the empty
+// string means no source.
+func mostRisky(a, b string) string {
+	if a == "" {
+		return b
+	}
+	if b == "" {
+		return a
+	}
+	if escapeSourceRisk[b] > escapeSourceRisk[a] {
+		return b
+	}
+	return a
+}
+
+func (t *templateExec) EscapeAudit() ([]tpl.EscapeAuditEntry, error) {
+	seen := make(map[string]bool)
+	var infos []templateInfo
+	add := func(ti templateInfo) {
+		if ti.realFilename == "" || ti.isText || seen[ti.realFilename] {
+			// Embedded or not an HTML template.
+			return
+		}
+		seen[ti.realFilename] = true
+		infos = append(infos, ti)
+	}
+
+	for _, ts := range t.main.templates {
+		add(ts.info)
+	}
+	for _, ti := range t.needsBaseof {
+		add(ti)
+	}
+	for _, ti := range t.baseof {
+		add(ti)
+	}
+
+	var entries []tpl.EscapeAuditEntry
+	for _, ti := range infos {
+		e, err := escapeAudit(ti.realFilename, ti.template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to audit %q: %w", ti.realFilename, err)
+		}
+		entries = append(entries, e...)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		ei, ej := entries[i], entries[j]
+		if ri, rj := escapeSourceRisk[ei.Source], escapeSourceRisk[ej.Source]; ri != rj {
+			return ri > rj
+		}
+		if ei.Filename != ej.Filename {
+			return ei.Filename < ej.Filename
+		}
+		if ei.Line != ej.Line {
+			return ei.Line < ej.Line
+		}
+		return ei.Column < ej.Column
+	})
+
+	return entries, nil
+}
+
+// escapeAudit parses the template source and returns the uses of the
+// functions disabling the escaping.
+func escapeAudit(filename, src string) ([]tpl.EscapeAuditEntry, error) {
+	tree := parse.New(filename)
+	tree.Mode = parse.SkipFuncCheck
+	treeSet := make(map[string]*parse.Tree)
+	if _, err := tree.Parse(src, "", "", treeSet); err != nil {
+		return nil, err
+	}
+
+	a := &escapeAuditor{filename: filename, src: src}
+
+	names := make([]string, 0, len(treeSet))
+	for name := range treeSet {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if root := treeSet[name].Root; root != nil {
+			a.vars = map[string]string{"$": tpl.EscapeSourceOther}
+			a.walk(root, tpl.EscapeSourceOther)
+		}
+	}
+
+	return a.entries, nil
+}
+
+type escapeAuditor struct {
+	filename string
+	src      string
+
+	// The sources of the variables, block scopes are not tracked.
+	vars map[string]string
+
+	entries []tpl.EscapeAuditEntry
+}
+
+// walk walks n, dot is the source of the value of dot.
+func (a *escapeAuditor) walk(n parse.Node, dot string) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, nn := range n.Nodes {
+			a.walk(nn, dot)
+		}
+	case *parse.ActionNode:
+		a.pipe(n.Pipe, dot)
+	case *parse.TemplateNode:
+		a.pipe(n.Pipe, dot)
+	case *parse.IfNode:
+		a.pipe(n.Pipe, dot)
+		a.walk(n.List, dot)
+		a.walk(n.ElseList, dot)
+	case *parse.RangeNode:
+		a.pipe(n.Pipe, dot)
+		a.walk(n.List, a.source(n.Pipe, dot))
+		a.walk(n.ElseList, dot)
+	case *parse.WithNode:
+		a.pipe(n.Pipe, dot)
+		a.walk(n.List, a.source(n.Pipe, dot))
+		a.walk(n.ElseList, dot)
+	}
+}
+
+// pipe records the uses in p and the sources of the variables it declares.
+func (a *escapeAuditor) pipe(p *parse.PipeNode, dot string) {
+	if p == nil {
+		return
+	}
+
+	for i, cmd := range p.Cmds {
+		for _, arg := range cmd.Args {
+			if pp, ok := arg.(*parse.PipeNode); ok {
+				a.pipe(pp, dot)
+			}
+		}
+
+		name := escapeAuditFuncName(cmd)
+		if !escapeAuditFuncs[name] {
+			continue
+		}
+
+		var source string
+		for _, arg := range cmd.Args[1:] {
+			source = mostRisky(source, a.nodeSource(arg, dot))
+		}
+		for _, prev := range p.Cmds[:i] {
+			source = mostRisky(source, a.cmdSource(prev, dot))
+		}
+		if source == "" {
+			source = tpl.EscapeSourceOther
+		}
+
+		line, col := a.position(cmd.Position())
+		a.entries = append(a.entries, tpl.EscapeAuditEntry{
+			Filename: a.filename,
+			Line:     line,
+			Column:   col,
+			Func:     name,
+			Source:   source,
+			Expr:     strings.TrimSpace(p.String()),
+		})
+	}
+
+	if len(p.Decl) > 0 {
+		source := a.source(p, dot)
+		for _, v := range p.Decl {
+			a.vars[v.Ident[0]] = source
+		}
+	}
+}
+
+func escapeAuditFuncName(cmd *parse.CommandNode) string {
+	if len(cmd.Args) == 0 {
+		return ""
+	}
+	switch n := cmd.Args[0].(type) {
+	case *parse.IdentifierNode:
+		return n.Ident
+	case *parse.ChainNode:
+		if id, ok := n.Node.(*parse.IdentifierNode); ok && len(n.Field) == 1 {
+			return id.Ident + "." + n.Field[0]
+		}
+	}
+	return ""
+}
+
+// source returns the source of the value of p.
+func (a *escapeAuditor) source(p *parse.PipeNode, dot string) string {
+	var source string
+	for _, cmd := range p.Cmds {
+		source = mostRisky(source, a.cmdSource(cmd, dot))
+	}
+	if source == "" {
+		return tpl.EscapeSourceOther
+	}
+	return source
+}
+
+func (a *escapeAuditor) cmdSource(cmd *parse.CommandNode, dot string) string {
+	var source string
+	for _, arg := range cmd.Args {
+		source = mostRisky(source, a.nodeSource(arg, dot))
+	}
+	return source
+}
+
+// nodeSource returns the source of n, empty if n is a function with no
+// source of its own.
+func (a *escapeAuditor) nodeSource(n parse.Node, dot string) string {
+	switch n := n.(type) {
+	case *parse.StringNode, *parse.NumberNode, *parse.BoolNode, *parse.NilNode:
+		return tpl.EscapeSourceLiteral
+	case *parse.DotNode:
+		return dot
+	case *parse.FieldNode:
+		if source := fieldsSource(n.Ident); source != "" {
+			return source
+		}
+		return dot
+	case *parse.VariableNode:
+		if source := fieldsSource(n.Ident[1:]); source != "" {
+			return source
+		}
+		if source, found := a.vars[n.Ident[0]]; found {
+			return source
+		}
+		return tpl.EscapeSourceOther
+	case *parse.ChainNode:
+		if source := fieldsSource(n.Field); source != "" {
+			return source
+		}
+		return a.nodeSource(n.Node, dot)
+	case *parse.IdentifierNode:
+		return escapeAuditFuncSources[n.Ident]
+	case *parse.PipeNode:
+		return a.source(n, dot)
+	}
+	return tpl.EscapeSourceOther
+}
+
+func fieldsSource(fields []string) string {
+	var source string
+	for _, f := range fields {
+		source = mostRisky(source, escapeAuditFieldSources[f])
+	}
+	return source
+}
+
+// position returns the line and column of the byte offset pos.
+func (a *escapeAuditor) position(pos parse.Pos) (int, int) {
+	p := int(pos)
+	if p > len(a.src) {
+		p = len(a.src)
+	}
+	before := a.src[:p]
+	line := 1 + strings.Count(before, "\n")
+	col := p - strings.LastIndex(before, "\n")
+	return line, col
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tplimpl
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestEscapeAudit(t *testing.T) {
+	c := qt.New(t)
+
+	src := `{{ define "main" }}
+{{ "<b>Hi</b>" | safeHTML }}
+{{ .Params.banner | safeHTML }}
+{{ $data := getJSON "https://example.org/data.json" }}
+{{ safeURL $data.url }}
+{{ with .Site.Data.ads }}{{ .html | safe.HTML }}{{ end }}
+{{ .Content | safeHTML }}
+{{ printf "<i>%s</i>" .Title | safeHTML }}
+{{ $x := now }}{{ safeJS $x }}
+{{ len (safeCSS "a") }}
+{{ end }}`
+
+	entries, err := escapeAudit("single.html", src)
+	c.Assert(err, qt.IsNil)
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Func+"|"+e.Source+"|"+e.Expr)
+	}
+
+	c.Assert(got, qt.DeepEquals, []string{
+		`safeHTML|literal|"<b>Hi</b>" | safeHTML`,
+		`safeHTML|param|.Params.banner | safeHTML`,
+		`safeURL|remote|safeURL $data.url`,
+		`safe.HTML|data|.html | safe.HTML`,
+		`safeHTML|content|.Content | safeHTML`,
+		`safeHTML|param|printf "<i>%s</i>" .Title | safeHTML`,
+		`safeJS|other|safeJS $x`,
+		`safeCSS|literal|safeCSS "a"`,
+	})
+
+	c.Assert(entries[1].Line, qt.Equals, 3)
+	c.Assert(entries[1].Column, qt.Equals, 21)
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/gohugoio/hugo/tpl/internal"
 
 	// Init the namespaces
+	_ "github.com/gohugoio/hugo/tpl/activitypub"
 	_ "github.com/gohugoio/hugo/tpl/cast"
 	_ "github.com/gohugoio/hugo/tpl/collections"
 	_ "github.com/gohugoio/hugo/tpl/compare"
@@ -40,8 +41,10 @@ import (
 	_ "github.com/gohugoio/hugo/tpl/debug"
 	_ "github.com/gohugoio/hugo/tpl/diagrams"
 	_ "github.com/gohugoio/hugo/tpl/encoding"
+	_ "github.com/gohugoio/hugo/tpl/errors"
 	_ "github.com/gohugoio/hugo/tpl/fmt"
 	_ "github.com/gohugoio/hugo/tpl/hugo"
+	_ "github.com/gohugoio/hugo/tpl/ical"
 	_ "github.com/gohugoio/hugo/tpl/images"
 	_ "github.com/gohugoio/hugo/tpl/inflect"
 	_ "github.com/gohugoio/hugo/tpl/js"
@@ -51,6 +54,7 @@ import (
 	_ "github.com/gohugoio/hugo/tpl/os"
 	_ "github.com/gohugoio/hugo/tpl/partials"
 	_ "github.com/gohugoio/hugo/tpl/path"
+	_ "github.com/gohugoio/hugo/tpl/podcast"
 	_ "github.com/gohugoio/hugo/tpl/reflect"
 	_ "github.com/gohugoio/hugo/tpl/resources"
 	_ "github.com/gohugoio/hugo/tpl/safe"
@@ -110,6 +114,18 @@ func (t *templateExecHelper) GetMapValue(ctx context.Context, tmpl texttemplate.
 }
 
 func (t *templateExecHelper) GetMethod(ctx context.Context, tmpl texttemplate.Preparer, receiver reflect.Value, name string) (method reflect.Value, firstArg reflect.Value) {
+	if receiver.CanInterface() {
+		if ns, ok := receiver.Interface().(*pluginNamespace); ok {
+			if fn, found := ns.funcs[name]; found {
+				return fn, zero
+			}
+			if !ns.fallback.IsValid() {
+				return zero, zero
+			}
+			receiver = ns.fallback
+		}
+	}
+
 	if t.running {
 		switch name {
 		case "GetPage", "Render":
@@ -138,8 +154,8 @@ func (t *templateExecHelper) GetMethod(ctx context.Context, tmpl texttemplate.Pr
 	return fn, zero
 }
 
-func newTemplateExecuter(d *deps.Deps) (texttemplate.Executer, map[string]reflect.Value) {
-	funcs := createFuncMap(d)
+func newTemplateExecuter(d *deps.Deps, plugins []funcsPlugin) (texttemplate.Executer, map[string]reflect.Value) {
+	funcs := createFuncMap(d, plugins)
 	funcsv := make(map[string]reflect.Value)
 
 	for k, v := range funcs {
@@ -174,8 +190,25 @@ func newTemplateExecuter(d *deps.Deps) (texttemplate.Executer, map[string]reflec
 	), funcsv
 }
 
-func createFuncMap(d *deps.Deps) map[string]any {
+func createFuncMap(d *deps.Deps, plugins []funcsPlugin) map[string]any {
+	funcMap, _ := createBuiltinFuncMap(d)
+
+	addFuncsPlugins(funcMap, plugins)
+
+	if d.OverloadedTemplateFuncs != nil {
+		for k, v := range d.OverloadedTemplateFuncs {
+			funcMap[k] = v
+		}
+	}
+
+	return funcMap
+}
+
+// createBuiltinFuncMap creates the built-in template funcs and returns them
+// with the names of the namespaces among them.
+func createBuiltinFuncMap(d *deps.Deps) (map[string]any, map[string]bool) {
 	funcMap := template.FuncMap{}
+	namespaces := make(map[string]bool)
 
 	// Merge the namespace funcs
 	for _, nsf := range internal.TemplateFuncsNamespaceRegistry {
@@ -184,6 +217,7 @@ func createFuncMap(d *deps.Deps) map[string]any {
 			panic(ns.Name + " is a duplicate template func")
 		}
 		funcMap[ns.Name] = ns.Context
+		namespaces[ns.Name] = true
 		for _, mm := range ns.MethodMappings {
 			for _, alias := range mm.Aliases {
 				if _, exists := funcMap[alias]; exists {
@@ -194,11 +228,5 @@ func createFuncMap(d *deps.Deps) map[string]any {
 		}
 	}
 
-	if d.OverloadedTemplateFuncs != nil {
-		for k, v := range d.OverloadedTemplateFuncs {
-			funcMap[k] = v
-		}
-	}
-
-	return funcMap
+	return funcMap, namespaces
 }
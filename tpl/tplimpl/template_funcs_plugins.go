@@ -0,0 +1,183 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tplimpl
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"plugin"
+	"reflect"
+	"strings"
+
+	"github.com/gohugoio/hugo/deps"
+	"github.com/gohugoio/hugo/modules"
+	texttemplate "github.com/gohugoio/hugo/tpl/internal/go_templates/texttemplate"
+)
+
+var errorInterface = reflect.TypeOf((*error)(nil)).Elem()
+
+// funcsPluginLoaders load the funcs of a template func namespace plugin,
+// keyed by the plugin's file extension.
+var funcsPluginLoaders = map[string]func(filename string) (map[string]any, error){
+	".so":   loadGoFuncsPlugin,
+	".wasm": loadWasmFuncsPlugin,
+}
+
+// loadGoFuncsPlugin loads a Go plugin exporting its funcs in a
+// map[string]any or a func() map[string]any named Funcs.
+func loadGoFuncsPlugin(filename string) (map[string]any, error) {
+	p, err := plugin.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := p.Lookup("Funcs")
+	if err != nil {
+		return nil, err
+	}
+	switch v := sym.(type) {
+	case *map[string]any:
+		return *v, nil
+	case func() map[string]any:
+		return v(), nil
+	}
+	return nil, fmt.Errorf("Funcs must be a map[string]any or a func() map[string]any, got %T", sym)
+}
+
+// funcsPlugin is a template func namespace loaded from a module's plugin.
+type funcsPlugin struct {
+	modules.FuncsPlugin
+	funcs map[string]reflect.Value
+}
+
+// loadFuncsPlugins loads the template func namespace plugins declared by the
+// modules, if allowed by the security policy.
+func loadFuncsPlugins(d *deps.Deps) ([]funcsPlugin, error) {
+	mods, _ := d.Cfg.Get("allModules").(modules.Modules)
+	declared, err := modules.FuncsPlugins(mods)
+	if err != nil || len(declared) == 0 {
+		return nil, err
+	}
+
+	funcMap, namespaces := createBuiltinFuncMap(d)
+
+	var plugins []funcsPlugin
+	for _, fp := range declared {
+		if err := d.ExecHelper.Sec().CheckAllowedFuncsPlugin(fp.Namespace); err != nil {
+			return nil, err
+		}
+		if err := checkFuncsPluginNamespace(fp, funcMap, namespaces); err != nil {
+			return nil, err
+		}
+
+		load, found := funcsPluginLoaders[strings.ToLower(filepath.Ext(fp.Filename))]
+		if !found {
+			return nil, fmt.Errorf("template func namespace %q: unsupported plugin %q, must be a Go plugin (.so) or a WebAssembly module (.wasm)", fp.Namespace, fp.Plugin)
+		}
+
+		funcs, err := load(fp.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("template func namespace %q: failed to load plugin %q: %w", fp.Namespace, fp.Plugin, err)
+		}
+
+		p := funcsPlugin{FuncsPlugin: fp, funcs: make(map[string]reflect.Value)}
+		for name, fn := range funcs {
+			v, err := checkPluginFunc(name, fn)
+			if err != nil {
+				return nil, fmt.Errorf("template func namespace %q: plugin %q: %w", fp.Namespace, fp.Plugin, err)
+			}
+			p.funcs[name] = v
+		}
+
+		plugins = append(plugins, p)
+	}
+
+	return plugins, nil
+}
+
+// checkPluginFunc checks that fn can be used as a template func.
+func checkPluginFunc(name string, fn any) (reflect.Value, error) {
+	if !token.IsIdentifier(name) {
+		return zero, fmt.Errorf("invalid func name %q", name)
+	}
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return zero, fmt.Errorf("%s is a %T, not a func", name, fn)
+	}
+	typ := v.Type()
+	if typ.NumOut() == 0 || typ.NumOut() > 2 || (typ.NumOut() == 2 && typ.Out(1) != errorInterface) {
+		return zero, fmt.Errorf("%s must return one value, or a value and an error", name)
+	}
+	return v, nil
+}
+
+// checkFuncsPluginNamespace checks that the namespace of fp is either new
+// or a built-in namespace with Override set.
+func checkFuncsPluginNamespace(fp modules.FuncsPlugin, funcMap map[string]any, namespaces map[string]bool) error {
+	_, exists := funcMap[fp.Namespace]
+	if _, isGoFunc := texttemplate.GoFuncs[fp.Namespace]; isGoFunc {
+		exists = true
+	}
+	if !exists {
+		return nil
+	}
+	if !namespaces[fp.Namespace] {
+		return fmt.Errorf("template func namespace %q from %q conflicts with the built-in template func %q", fp.Namespace, moduleDisplay(fp.Module), fp.Namespace)
+	}
+	if !fp.Override {
+		return fmt.Errorf("template func namespace %q from %q conflicts with the built-in namespace; set override = true to replace its funcs", fp.Namespace, moduleDisplay(fp.Module))
+	}
+	return nil
+}
+
+// addFuncsPlugins adds the plugins to funcMap. A plugin overriding a
+// built-in namespace falls back to its funcs.
+func addFuncsPlugins(funcMap map[string]any, plugins []funcsPlugin) {
+	for _, p := range plugins {
+		p := p
+		existing, exists := funcMap[p.Namespace]
+		if !exists {
+			ns := &pluginNamespace{funcs: p.funcs}
+			funcMap[p.Namespace] = func(v ...any) (any, error) {
+				return ns, nil
+			}
+			continue
+		}
+
+		builtin := existing.(func(v ...any) (any, error))
+		funcMap[p.Namespace] = func(v ...any) (any, error) {
+			fallback, err := builtin(v...)
+			if err != nil {
+				return nil, err
+			}
+			return &pluginNamespace{funcs: p.funcs, fallback: reflect.ValueOf(fallback)}, nil
+		}
+	}
+}
+
+func moduleDisplay(mod modules.Module) string {
+	if mod.Owner() == nil {
+		return "project"
+	}
+	return mod.Path()
+}
+
+// pluginNamespace is the receiver of the template funcs loaded from a
+// plugin, e.g. sci.Gamma, see templateExecHelper.GetMethod.
+type pluginNamespace struct {
+	funcs map[string]reflect.Value
+
+	// The built-in namespace with the funcs not provided by the plugin, if any.
+	fallback reflect.Value
+}
@@ -0,0 +1,325 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tplimpl_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestFuncsPluginsErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name   string
+		config string
+		expect string
+	}{
+		{
+			"Not allowed",
+			`
+[[module.funcs]]
+namespace = "sci"
+plugin = "plugins/sci.so"
+`,
+			`(?s).*access denied: "sci" is not whitelisted in policy "security.funcs.plugins".*`,
+		},
+		{
+			"Built-in namespace",
+			`
+[security.funcs]
+plugins = ['.*']
+[[module.funcs]]
+namespace = "math"
+plugin = "plugins/math.so"
+`,
+			`.*template func namespace "math" from "project" conflicts with the built-in namespace; set override = true.*`,
+		},
+		{
+			"Built-in func",
+			`
+[security.funcs]
+plugins = ['.*']
+[[module.funcs]]
+namespace = "printf"
+plugin = "plugins/printf.so"
+`,
+			`.*template func namespace "printf" from "project" conflicts with the built-in template func "printf".*`,
+		},
+		{
+			"Unsupported plugin",
+			`
+[security.funcs]
+plugins = ['^sci$']
+[[module.funcs]]
+namespace = "sci"
+plugin = "plugins/sci.dll"
+`,
+			`.*template func namespace "sci": unsupported plugin "plugins/sci.dll", must be a Go plugin \(\.so\) or a WebAssembly module \(\.wasm\).*`,
+		},
+		{
+			"Invalid namespace",
+			`
+[[module.funcs]]
+namespace = "sci-fi"
+plugin = "plugins/sci.so"
+`,
+			`.*module "project": invalid template func namespace "sci-fi".*`,
+		},
+		{
+			"Declared twice",
+			`
+theme = "mytheme"
+[security.funcs]
+plugins = ['.*']
+[[module.funcs]]
+namespace = "sci"
+plugin = "plugins/sci.so"
+`,
+			`.*template func namespace "sci" is declared by both "project" and "mytheme".*`,
+		},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			files := `
+-- config.toml --
+baseURL = 'http://example.com/'
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+` + test.config + `
+-- themes/mytheme/config.toml --
+[[module.funcs]]
+namespace = "sci"
+plugin = "plugins/sci.so"
+-- layouts/index.html --
+Home.
+`
+
+			b, err := hugolib.NewIntegrationTestBuilder(
+				hugolib.IntegrationTestConfig{
+					T:           t,
+					TxtarString: files,
+				},
+			).BuildE()
+
+			b.Assert(err, qt.Not(qt.IsNil))
+			b.Assert(err, qt.ErrorMatches, test.expect)
+		})
+	}
+}
+
+func TestFuncsPluginsGoPlugin(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("skip building a Go plugin in short mode")
+	}
+
+	c := qt.New(t)
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+
+	pluginDir := t.TempDir()
+	c.Assert(os.WriteFile(filepath.Join(pluginDir, "go.mod"), []byte("module example.org/sci\n"), 0o666), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(pluginDir, "sci.go"), []byte(`package main
+
+import "math"
+
+var Funcs = map[string]any{
+	"Gamma": math.Gamma,
+	"Sqrt": func(v float64) string { return "plugin" },
+}
+`), 0o666), qt.IsNil)
+
+	filename := filepath.Join(pluginDir, "sci.so")
+	cmd := exec.Command(goBin, "build", "-buildmode=plugin", "-o", filename, ".")
+	cmd.Dir = pluginDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("failed to build Go plugin: %s", out)
+	}
+
+	files := `
+-- config.toml --
+baseURL = 'http://example.com/'
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+[security.funcs]
+plugins = ['^(sci|math)$']
+[[module.funcs]]
+namespace = "sci"
+plugin = "SCI"
+[[module.funcs]]
+namespace = "math"
+plugin = "SCI"
+override = true
+-- layouts/index.html --
+Gamma: {{ sci.Gamma 5.0 }}|
+Sqrt: {{ math.Sqrt 4.0 }}|
+Add: {{ math.Add 1 2 }}|
+`
+	files = strings.ReplaceAll(files, "SCI", filepath.ToSlash(filename))
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	)
+
+	_, err = b.BuildE()
+	if err != nil && strings.Contains(err.Error(), "different version of package") {
+		// E.g. when running with -race or -cover.
+		t.Skipf("the Go plugin is not compatible with the test binary: %s", err)
+	}
+	b.Assert(err, qt.IsNil)
+
+	b.AssertFileContent("public/index.html", "Gamma: 24|", "Sqrt: plugin|", "Add: 3|")
+}
+
+func TestFuncsPluginsWasm(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("skip building a WebAssembly module in short mode")
+	}
+
+	c := qt.New(t)
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go not found")
+	}
+
+	pluginDir := t.TempDir()
+	c.Assert(os.WriteFile(filepath.Join(pluginDir, "go.mod"), []byte("module example.org/sci\n\ngo 1.24\n"), 0o666), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(pluginDir, "sci.go"), []byte(`package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"unsafe"
+)
+
+func main() {}
+
+var buffers = make(map[uint32][]byte)
+
+//go:wasmexport alloc
+func alloc(size uint32) uint32 {
+	b := make([]byte, size+1)
+	ptr := uint32(uintptr(unsafe.Pointer(&b[0])))
+	buffers[ptr] = b[:size]
+	return ptr
+}
+
+//go:wasmexport free
+func free(ptr, size uint32) {
+	delete(buffers, ptr)
+}
+
+func call(ptr, size uint32, fn func(args []any) (any, error)) uint64 {
+	var args []any
+	out := make(map[string]any)
+	if err := json.Unmarshal(buffers[ptr], &args); err != nil {
+		out["error"] = err.Error()
+	} else if v, err := fn(args); err != nil {
+		out["error"] = err.Error()
+	} else {
+		out["result"] = v
+	}
+	b, _ := json.Marshal(out)
+	outPtr := alloc(uint32(len(b)))
+	copy(buffers[outPtr], b)
+	return uint64(outPtr)<<32 | uint64(len(b))
+}
+
+//go:wasmexport Gamma
+func gamma(ptr, size uint32) uint64 {
+	return call(ptr, size, func(args []any) (any, error) {
+		return math.Gamma(args[0].(float64)), nil
+	})
+}
+
+//go:wasmexport Join
+func join(ptr, size uint32) uint64 {
+	return call(ptr, size, func(args []any) (any, error) {
+		var s string
+		for _, arg := range args {
+			s += arg.(string)
+		}
+		return s, nil
+	})
+}
+
+//go:wasmexport Fail
+func fail(ptr, size uint32) uint64 {
+	return call(ptr, size, func(args []any) (any, error) {
+		return nil, errors.New("failed")
+	})
+}
+`), 0o666), qt.IsNil)
+
+	filename := filepath.Join(pluginDir, "sci.wasm")
+	cmd := exec.Command(goBin, "build", "-buildmode=c-shared", "-o", filename, ".")
+	cmd.Dir = pluginDir
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("failed to build WebAssembly module: %s", out)
+	}
+
+	files := `
+-- config.toml --
+baseURL = 'http://example.com/'
+disableKinds = ["taxonomy", "term", "RSS", "sitemap", "robotsTXT", "404"]
+[security.funcs]
+plugins = ['^sci$']
+[[module.funcs]]
+namespace = "sci"
+plugin = "SCI"
+-- layouts/index.html --
+Gamma: {{ sci.Gamma 5.0 }}|
+Join: {{ sci.Join "a" "b" "c" }}|
+`
+	files = strings.ReplaceAll(files, "SCI", filepath.ToSlash(filename))
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html", "Gamma: 24|", "Join: abc|")
+
+	files = strings.Replace(files, "Gamma: {{ sci.Gamma 5.0 }}|", "{{ sci.Fail }}", 1)
+	b, err = hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).BuildE()
+
+	b.Assert(err, qt.Not(qt.IsNil))
+	b.Assert(err.Error(), qt.Contains, "Fail: failed")
+}
@@ -0,0 +1,221 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tplimpl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// The exports of a WebAssembly template func plugin that are not template funcs.
+const (
+	wasmExportAlloc      = "alloc"
+	wasmExportFree       = "free"
+	wasmExportInitialize = "_initialize"
+	wasmExportStart      = "_start"
+)
+
+// wasmFuncsPlugins caches the loaded WebAssembly modules by filename,
+// so they are only compiled again when changed.
+var wasmFuncsPlugins = struct {
+	sync.Mutex
+	m map[string]*wasmFuncsPlugin
+}{m: make(map[string]*wasmFuncsPlugin)}
+
+// wasmFuncsPlugin is an instance of a WebAssembly module providing template funcs.
+//
+// The module must export its memory, an alloc(size i32) i32 func returning
+// a buffer of the given size and, optionally, a free(ptr, size i32) func to
+// release it. Every other exported func with the signature (ptr, size i32) i64
+// is a template func. It receives its arguments as a JSON array in the buffer
+// at ptr and returns a buffer, allocated with alloc, with the JSON object
+// {"result": value} or {"error": "message"}, as ptr << 32 | size.
+type wasmFuncsPlugin struct {
+	modTime time.Time
+	size    int64
+
+	// The module is not safe for concurrent use.
+	mu      sync.Mutex
+	runtime wazero.Runtime
+	mod     api.Module
+	alloc   api.Function
+	free    api.Function
+}
+
+// loadWasmFuncsPlugin loads a WebAssembly module, see wasmFuncsPlugin.
+// The module runs without access to the file system, the environment
+// or the network.
+func loadWasmFuncsPlugin(filename string) (map[string]any, error) {
+	fi, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	wasmFuncsPlugins.Lock()
+	defer wasmFuncsPlugins.Unlock()
+
+	p, found := wasmFuncsPlugins.m[filename]
+	if found && (!p.modTime.Equal(fi.ModTime()) || p.size != fi.Size()) {
+		p.close()
+		found = false
+	}
+	if !found {
+		p, err = newWasmFuncsPlugin(filename)
+		if err != nil {
+			return nil, err
+		}
+		p.modTime, p.size = fi.ModTime(), fi.Size()
+		wasmFuncsPlugins.m[filename] = p
+	}
+
+	funcs := make(map[string]any)
+	for name, def := range p.mod.ExportedFunctionDefinitions() {
+		switch name {
+		case wasmExportAlloc, wasmExportFree, wasmExportInitialize, wasmExportStart:
+			continue
+		}
+		if !isWasmTemplateFunc(def) {
+			continue
+		}
+		fn := p.mod.ExportedFunction(name)
+		name := name
+		funcs[name] = func(args ...any) (any, error) {
+			return p.call(name, fn, args)
+		}
+	}
+
+	return funcs, nil
+}
+
+func newWasmFuncsPlugin(filename string) (*wasmFuncsPlugin, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	r := wazero.NewRuntime(ctx)
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
+		r.Close(ctx)
+		return nil, err
+	}
+
+	compiled, err := r.CompileModule(ctx, b)
+	if err != nil {
+		r.Close(ctx)
+		return nil, err
+	}
+
+	// A WASI command's _start would run its main func and exit,
+	// so only initialize reactors.
+	mod, err := r.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithStartFunctions(wasmExportInitialize))
+	if err != nil {
+		r.Close(ctx)
+		return nil, err
+	}
+
+	p := &wasmFuncsPlugin{runtime: r, mod: mod}
+
+	if p.mod.Memory() == nil {
+		p.close()
+		return nil, errors.New("the module must export its memory")
+	}
+	if p.alloc = mod.ExportedFunction(wasmExportAlloc); p.alloc == nil {
+		p.close()
+		return nil, fmt.Errorf("the module must export an %s func", wasmExportAlloc)
+	}
+	p.free = mod.ExportedFunction(wasmExportFree)
+
+	return p, nil
+}
+
+func isWasmTemplateFunc(def api.FunctionDefinition) bool {
+	params, results := def.ParamTypes(), def.ResultTypes()
+	return len(params) == 2 && params[0] == api.ValueTypeI32 && params[1] == api.ValueTypeI32 &&
+		len(results) == 1 && results[0] == api.ValueTypeI64
+}
+
+// call calls the template func fn with args encoded as JSON.
+func (p *wasmFuncsPlugin) call(name string, fn api.Function, args []any) (any, error) {
+	if args == nil {
+		args = []any{}
+	}
+	in, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to encode the arguments: %w", name, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ctx := context.Background()
+	mem := p.mod.Memory()
+
+	res, err := p.alloc.Call(ctx, uint64(len(in)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	inPtr := uint32(res[0])
+	defer p.release(ctx, inPtr, uint32(len(in)))
+	if !mem.Write(inPtr, in) {
+		return nil, fmt.Errorf("%s: alloc returned a buffer out of range", name)
+	}
+
+	res, err = fn.Call(ctx, uint64(inPtr), uint64(len(in)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	outPtr, outSize := uint32(res[0]>>32), uint32(res[0])
+	defer p.release(ctx, outPtr, outSize)
+
+	out, ok := mem.Read(outPtr, outSize)
+	if !ok {
+		return nil, fmt.Errorf("%s: returned a buffer out of range", name)
+	}
+
+	var result struct {
+		Result any    `json:"result"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("%s: failed to decode the result: %w", name, err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s: %s", name, result.Error)
+	}
+
+	return result.Result, nil
+}
+
+func (p *wasmFuncsPlugin) release(ctx context.Context, ptr, size uint32) {
+	if p.free != nil {
+		p.free.Call(ctx, uint64(ptr), uint64(size))
+	}
+}
+
+func (p *wasmFuncsPlugin) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.runtime.Close(context.Background())
+}
@@ -110,6 +110,13 @@ func init() {
 			},
 		)
 
+		ns.AddMethodMapping(ctx.XMLMarshal,
+			nil,
+			[][2]string{
+				{`{{ dict "-id" "1" "title" "Hugo" | transform.XMLMarshal (dict "root" "book" "header" false) | safeHTML }}`, "<book id=\"1\">\n  <title>Hugo</title>\n</book>"},
+			},
+		)
+
 		return ns
 	}
 
@@ -33,7 +33,7 @@ import (
 )
 
 // Unmarshal unmarshals the data given, which can be either a string, json.RawMessage
-// or a Resource. Supported formats are JSON, TOML, YAML, and CSV.
+// or a Resource. Supported formats are JSON, TOML, YAML, XML and CSV.
 // You can optionally provide an options map as the first argument.
 func (ns *Namespace) Unmarshal(args ...any) (any, error) {
 	if len(args) < 1 || len(args) > 2 {
@@ -67,7 +67,7 @@ func (ns *Namespace) Unmarshal(args ...any) (any, error) {
 			return nil, errors.New("no Key set in Resource")
 		}
 
-		if decoder != metadecoders.Default {
+		if decoder.OptionsKey() != metadecoders.Default.OptionsKey() {
 			key += decoder.OptionsKey()
 		}
 
@@ -102,6 +102,9 @@ func (ns *Namespace) Unmarshal(args ...any) (any, error) {
 	}
 
 	key := helpers.MD5String(dataStr)
+	if decoder.OptionsKey() != metadecoders.Default.OptionsKey() {
+		key += decoder.OptionsKey()
+	}
 
 	return ns.cache.GetOrCreate(key, func() (any, error) {
 		f := decoder.FormatFromContentString(dataStr)
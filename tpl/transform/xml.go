@@ -0,0 +1,196 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gohugoio/hugo/parser/metadecoders"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+)
+
+type xmlMarshalOptions struct {
+	// The name of the root element.
+	Root string
+
+	// The indentation, empty for none.
+	Indent string
+
+	// Whether to write the XML declaration.
+	Header bool
+}
+
+var defaultXMLMarshalOptions = xmlMarshalOptions{
+	Root:   "root",
+	Indent: "  ",
+	Header: true,
+}
+
+// XMLMarshal marshals data, a map as returned by transform.Unmarshal for
+// XML, to XML. Keys starting with - are written as attributes, #text as
+// text and slices as repeated elements. The elements are written in key
+// order.
+// You can optionally provide an options map as the first argument with
+// root (default root), indent (default two spaces) and header (default true).
+func (ns *Namespace) XMLMarshal(args ...any) (string, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return "", errors.New("xmlMarshal takes 1 or 2 arguments")
+	}
+
+	opts := defaultXMLMarshalOptions
+	data := args[len(args)-1]
+	if len(args) == 2 {
+		if err := mapstructure.WeakDecode(args[0], &opts); err != nil {
+			return "", fmt.Errorf("failed to decode options: %w", err)
+		}
+		if opts.Root == "" {
+			return "", errors.New("root must be set")
+		}
+	}
+
+	w := &xmlWriter{indent: opts.Indent}
+	if opts.Header {
+		w.WriteString(xml.Header)
+	}
+	if err := w.element(opts.Root, data, 0); err != nil {
+		return "", err
+	}
+	if opts.Indent != "" {
+		w.WriteString("\n")
+	}
+
+	return w.String(), nil
+}
+
+type xmlWriter struct {
+	strings.Builder
+	indent string
+}
+
+func (w *xmlWriter) newline(depth int) {
+	if w.indent == "" {
+		return
+	}
+	if w.Len() > 0 {
+		w.WriteString("\n")
+	}
+	w.WriteString(strings.Repeat(w.indent, depth))
+}
+
+func (w *xmlWriter) escape(s string) {
+	xml.EscapeText(w, []byte(s))
+}
+
+func (w *xmlWriter) element(name string, v any, depth int) error {
+	if name == "" || strings.ContainsAny(name, " <>&\"'") {
+		return fmt.Errorf("invalid element name %q", name)
+	}
+
+	if v != nil {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			for i := 0; i < rv.Len(); i++ {
+				if err := w.element(name, rv.Index(i).Interface(), depth); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	w.newline(depth)
+	w.WriteString("<" + name)
+
+	m, isMap := toStringMap(v)
+	if !isMap {
+		s, err := cast.ToStringE(v)
+		if err != nil {
+			return fmt.Errorf("element %q: %w", name, err)
+		}
+		if s == "" {
+			w.WriteString("/>")
+			return nil
+		}
+		w.WriteString(">")
+		w.escape(s)
+		w.WriteString("</" + name + ">")
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var children []string
+	for _, k := range keys {
+		if strings.HasPrefix(k, metadecoders.XMLAttrPrefix) {
+			s, err := cast.ToStringE(m[k])
+			if err != nil {
+				return fmt.Errorf("attribute %q: %w", k, err)
+			}
+			w.WriteString(" " + strings.TrimPrefix(k, metadecoders.XMLAttrPrefix) + `="`)
+			xml.EscapeText(w, []byte(s))
+			w.WriteString(`"`)
+		} else if k != metadecoders.XMLTextKey {
+			children = append(children, k)
+		}
+	}
+
+	text, err := cast.ToStringE(m[metadecoders.XMLTextKey])
+	if err != nil {
+		return fmt.Errorf("element %q: %w", name, err)
+	}
+
+	if text == "" && len(children) == 0 {
+		w.WriteString("/>")
+		return nil
+	}
+
+	w.WriteString(">")
+	w.escape(text)
+	for _, k := range children {
+		if err := w.element(k, m[k], depth+1); err != nil {
+			return err
+		}
+	}
+	if len(children) > 0 {
+		w.newline(depth)
+	}
+	w.WriteString("</" + name + ">")
+
+	return nil
+}
+
+func toStringMap(v any) (map[string]any, bool) {
+	if v == nil {
+		return nil, false
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	m := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+	return m, true
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform_test
+
+import (
+	"testing"
+
+	"github.com/gohugoio/hugo/hugolib"
+)
+
+func TestXMLUnmarshalAndMarshal(t *testing.T) {
+	t.Parallel()
+
+	files := `
+-- config.toml --
+baseURL = "https://example.org/"
+disableKinds = ["taxonomy", "term", "section", "page", "sitemap", "robotsTXT", "404", "rss"]
+-- assets/feed.xml --
+<?xml version="1.0" encoding="utf-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+<channel>
+	<title>Feed</title>
+	<item><title>One</title><media:content url="one.jpg"/></item>
+</channel>
+</rss>
+-- layouts/index.html --
+{{ $opts := dict "xmlArrays" (slice "item") "xmlNamespaces" true }}
+{{ $feed := resources.Get "feed.xml" | transform.Unmarshal $opts }}
+{{ range $feed.channel.item }}Item: {{ .title }}|{{ index . "media:content" "-url" }}|{{ end }}
+{{ $out := transform.XMLMarshal (dict "root" "rss") $feed }}
+{{ $out | safeHTML }}
+`
+
+	b := hugolib.NewIntegrationTestBuilder(
+		hugolib.IntegrationTestConfig{
+			T:           t,
+			TxtarString: files,
+		},
+	).Build()
+
+	b.AssertFileContent("public/index.html",
+		"Item: One|one.jpg|",
+		`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <item>
+      <media:content url="one.jpg"/>
+      <title>One</title>
+    </item>
+    <title>Feed</title>
+  </channel>
+</rss>`,
+	)
+}
@@ -0,0 +1,390 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package outputprofile provides a transformer that makes HTML satisfy the
+// constraints of targets such as AMP or email and validates it.
+package outputprofile
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"regexp"
+	"strings"
+
+	"github.com/gohugoio/hugo/common/maps"
+	"github.com/gohugoio/hugo/transform"
+	"github.com/mitchellh/mapstructure"
+
+	// Importing image codecs for image.DecodeConfig
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+// The rules checked.
+const (
+	RuleScript          = "script"
+	RuleEventHandler    = "event-handler"
+	RuleStylesheet      = "stylesheet"
+	RuleCSSSize         = "css-size"
+	RuleImageDimensions = "image-dimensions"
+
+	RuleParse              = "parse"
+	RuleDuplicateID        = "duplicate-id"
+	RuleDuplicateAttribute = "duplicate-attribute"
+	RuleUnclosedElement    = "unclosed-element"
+	RuleStrayEndTag        = "stray-end-tag"
+)
+
+// DefaultConfigs holds the built-in profiles.
+var DefaultConfigs = map[string]Config{
+	"amp": {
+		NoCustomJS:      true,
+		AllowScripts:    []string{"https://cdn.ampproject.org/"},
+		InlineCSS:       true,
+		StyleAttribute:  "amp-custom",
+		MaxCSSSize:      75000,
+		ImageDimensions: true,
+		ImageElement:    "amp-img",
+	},
+	"email": {
+		NoCustomJS:      true,
+		InlineCSS:       true,
+		ImageDimensions: true,
+	},
+	"validate": {
+		ValidateHTML: true,
+	},
+}
+
+// Config configures an output profile.
+type Config struct {
+	// Remove scripts and inline event handlers, except scripts with
+	// a src in AllowScripts and JSON data.
+	NoCustomJS bool
+
+	// URL prefixes of the allowed scripts.
+	AllowScripts []string
+
+	// Inline the site's stylesheets and merge them with the style elements
+	// without attributes into a single style element in head.
+	InlineCSS bool
+
+	// An attribute to add to the merged style element, e.g. amp-custom.
+	StyleAttribute string
+
+	// The max size in bytes of the merged CSS, 0 for no limit.
+	MaxCSSSize int
+
+	// Require width and height on images, set from the image file if missing.
+	ImageDimensions bool
+
+	// The element to replace img elements with, e.g. amp-img.
+	ImageElement string
+
+	// Check the HTML for duplicate IDs and attributes, unclosed elements
+	// and end tags without a start tag.
+	ValidateHTML bool
+
+	// Fail the build on violations instead of logging warnings.
+	Strict bool
+}
+
+// DecodeConfig decodes the outputProfiles section in site config and merges
+// it with the built-in profiles.
+func DecodeConfig(in any) (map[string]Config, error) {
+	configs := make(map[string]Config)
+	for k, v := range DefaultConfigs {
+		configs[k] = v
+	}
+
+	if in == nil {
+		return configs, nil
+	}
+
+	m, err := maps.ToStringMapE(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode outputProfiles: %w", err)
+	}
+
+	for k, v := range m {
+		name := strings.ToLower(k)
+		c := configs[name]
+		if err := mapstructure.WeakDecode(v, &c); err != nil {
+			return nil, fmt.Errorf("failed to decode output profile %q: %w", k, err)
+		}
+		configs[name] = c
+	}
+
+	return configs, nil
+}
+
+// Violation is a constraint of the profile the HTML did not satisfy.
+type Violation struct {
+	Rule    string
+	Message string
+
+	// The offending element, shortened.
+	Source string
+
+	// The line in the HTML, 0 if it applies to the whole document.
+	Line int
+}
+
+func (v Violation) String() string {
+	if v.Source == "" {
+		return fmt.Sprintf("%s (%s)", v.Message, v.Rule)
+	}
+	return fmt.Sprintf("%s: %s (%s)", v.Message, v.Source, v.Rule)
+}
+
+// Options holds the site specific callbacks for a transformer.
+type Options struct {
+	// ReadFile returns the content of the site file the URL ref points to.
+	ReadFile func(ref string) ([]byte, bool)
+
+	// Report is called for every violation.
+	Report func(v Violation)
+}
+
+var (
+	elementRe   = regexp.MustCompile(`(?is)<script\b[^>]*>.*?</script\s*>|<style\b[^>]*>.*?</style\s*>|<[a-z][a-z0-9-]*\b[^>]*>`)
+	tagNameRe   = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9-]*)`)
+	startTagRe  = regexp.MustCompile(`(?is)^<[^>]*>`)
+	attrRe      = regexp.MustCompile(`(?s)\s([^\s=/>"']+)(?:\s*=\s*("[^"]*"|'[^']*'|[^\s>"']+))?`)
+	onAttrRe    = regexp.MustCompile(`(?is)\s+on[a-z]+\s*=\s*("[^"]*"|'[^']*'|[^\s>"']+)`)
+	headCloseRe = regexp.MustCompile(`(?i)</head\s*>`)
+)
+
+// New creates a transformer for the profile in cfg.
+func New(cfg Config, opts Options) transform.Transformer {
+	return func(ft transform.FromTo) error {
+		b := ft.From().Bytes()
+
+		p := &processor{cfg: cfg, opts: opts, src: b}
+		if cfg.ValidateHTML {
+			p.validate()
+		}
+		out := p.process()
+
+		_, err := ft.To().Write(out)
+		return err
+	}
+}
+
+type processor struct {
+	cfg  Config
+	opts Options
+	src  []byte
+
+	css bytes.Buffer
+}
+
+func (p *processor) report(rule string, offset int, source, format string, args ...any) {
+	if p.opts.Report == nil {
+		return
+	}
+	v := Violation{Rule: rule, Message: fmt.Sprintf(format, args...)}
+	if offset >= 0 {
+		v.Line = bytes.Count(p.src[:offset], []byte("\n")) + 1
+		v.Source = shorten(source)
+	}
+	p.opts.Report(v)
+}
+
+func (p *processor) readFile(ref string) ([]byte, bool) {
+	if p.opts.ReadFile == nil || ref == "" {
+		return nil, false
+	}
+	return p.opts.ReadFile(html.UnescapeString(ref))
+}
+
+func (p *processor) process() []byte {
+	var out bytes.Buffer
+	last := 0
+
+	for _, m := range elementRe.FindAllIndex(p.src, -1) {
+		start, end := m[0], m[1]
+		out.Write(p.src[last:start])
+		last = end
+		out.WriteString(p.element(start, string(p.src[start:end])))
+	}
+	out.Write(p.src[last:])
+
+	if p.css.Len() == 0 {
+		return out.Bytes()
+	}
+
+	if p.cfg.MaxCSSSize > 0 && p.css.Len() > p.cfg.MaxCSSSize {
+		p.report(RuleCSSSize, -1, "", "CSS is %d bytes, more than %d", p.css.Len(), p.cfg.MaxCSSSize)
+	}
+
+	style := "<style"
+	if p.cfg.StyleAttribute != "" {
+		style += " " + p.cfg.StyleAttribute
+	}
+	style += ">" + p.css.String() + "</style>"
+
+	b := out.Bytes()
+	if loc := headCloseRe.FindIndex(b); loc != nil {
+		return append(append(append([]byte{}, b[:loc[0]]...), style...), b[loc[0]:]...)
+	}
+	return append([]byte(style), b...)
+}
+
+func (p *processor) element(offset int, el string) string {
+	m := tagNameRe.FindStringSubmatch(el)
+	if m == nil {
+		return el
+	}
+	name := strings.ToLower(m[1])
+	tag := startTagRe.FindString(el)
+	attrs := parseAttrs(tag)
+
+	switch name {
+	case "script":
+		if !p.cfg.NoCustomJS {
+			return el
+		}
+		if typ := strings.ToLower(attrs["type"]); typ == "application/json" || typ == "application/ld+json" {
+			return el
+		}
+		if src := attrs["src"]; src != "" {
+			for _, prefix := range p.cfg.AllowScripts {
+				if strings.HasPrefix(src, prefix) {
+					return el
+				}
+			}
+		}
+		p.report(RuleScript, offset, el, "custom JavaScript removed")
+		return ""
+	case "style":
+		if !p.cfg.InlineCSS || len(attrs) > 0 {
+			return el
+		}
+		css := el[len(tag):]
+		css = css[:strings.LastIndex(strings.ToLower(css), "</style")]
+		p.addCSS(css)
+		return ""
+	}
+
+	if p.cfg.NoCustomJS && onAttrRe.MatchString(tag) {
+		p.report(RuleEventHandler, offset, tag, "inline event handler removed")
+		el = onAttrRe.ReplaceAllString(tag, "") + el[len(tag):]
+		tag = startTagRe.FindString(el)
+	}
+
+	switch name {
+	case "link":
+		if !p.cfg.InlineCSS || !hasToken(attrs["rel"], "stylesheet") {
+			return el
+		}
+		b, ok := p.readFile(attrs["href"])
+		if !ok {
+			p.report(RuleStylesheet, offset, el, "stylesheet could not be inlined")
+			return el
+		}
+		p.addCSS(string(b))
+		return ""
+	case "img":
+		if p.cfg.ImageDimensions && (attrs["width"] == "" || attrs["height"] == "") {
+			var add string
+			if b, ok := p.readFile(attrs["src"]); ok {
+				if c, _, err := image.DecodeConfig(bytes.NewReader(b)); err == nil {
+					if attrs["width"] == "" {
+						add += fmt.Sprintf(` width="%d"`, c.Width)
+					}
+					if attrs["height"] == "" {
+						add += fmt.Sprintf(` height="%d"`, c.Height)
+					}
+				}
+			}
+			if add == "" {
+				p.report(RuleImageDimensions, offset, el, "image without width and height")
+			} else {
+				el = insertAttrs(el, add)
+			}
+		}
+		if p.cfg.ImageElement != "" {
+			el = "<" + p.cfg.ImageElement + strings.TrimSuffix(strings.TrimSuffix(el[len("<img"):len(el)-1], "/"), " ") + "></" + p.cfg.ImageElement + ">"
+		}
+	}
+
+	return el
+}
+
+func (p *processor) addCSS(css string) {
+	css = strings.TrimSpace(css)
+	if css == "" {
+		return
+	}
+	if p.css.Len() > 0 {
+		p.css.WriteString("\n")
+	}
+	p.css.WriteString(css)
+}
+
+// insertAttrs inserts attrs, starting with a space, at the end of the start tag el.
+func insertAttrs(el, attrs string) string {
+	end := len(el) - 1
+	if strings.HasSuffix(el, "/>") {
+		end--
+		for end > 0 && el[end-1] == ' ' {
+			end--
+		}
+	}
+	return el[:end] + attrs + el[end:]
+}
+
+func parseAttrs(tag string) map[string]string {
+	attrs := make(map[string]string)
+	tag = strings.TrimSuffix(strings.TrimSuffix(tag, ">"), "/")
+	if i := strings.IndexAny(tag, " \t\r\n"); i != -1 {
+		tag = tag[i:]
+	} else {
+		return attrs
+	}
+	for _, m := range attrRe.FindAllStringSubmatch(tag, -1) {
+		v := m[2]
+		if len(v) > 1 && (v[0] == '"' || v[0] == '\'') {
+			v = v[1 : len(v)-1]
+		}
+		attrs[strings.ToLower(m[1])] = v
+	}
+	return attrs
+}
+
+func hasToken(s, token string) bool {
+	for _, f := range strings.Fields(s) {
+		if strings.EqualFold(f, token) {
+			return true
+		}
+	}
+	return false
+}
+
+func shorten(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 80 {
+		i := 77
+		for i > 0 && s[i]&0xC0 == 0x80 {
+			i--
+		}
+		s = s[:i] + "..."
+	}
+	return s
+}
@@ -0,0 +1,160 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package outputprofile
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strconv"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/transform"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	configs, err := DecodeConfig(map[string]any{
+		"AMP":   map[string]any{"maxCSSSize": 100, "strict": true},
+		"plain": map[string]any{"noCustomJS": true},
+	})
+	c.Assert(err, qt.IsNil)
+	c.Assert(configs["amp"].MaxCSSSize, qt.Equals, 100)
+	c.Assert(configs["amp"].Strict, qt.IsTrue)
+	c.Assert(configs["amp"].ImageElement, qt.Equals, "amp-img")
+	c.Assert(configs["plain"], qt.DeepEquals, Config{NoCustomJS: true})
+	c.Assert(configs["email"], qt.DeepEquals, DefaultConfigs["email"])
+	c.Assert(DefaultConfigs["amp"].MaxCSSSize, qt.Equals, 75000)
+}
+
+func TestTransformer(t *testing.T) {
+	c := qt.New(t)
+
+	var pngBuf bytes.Buffer
+	c.Assert(png.Encode(&pngBuf, image.NewRGBA(image.Rect(0, 0, 30, 20))), qt.IsNil)
+
+	files := map[string][]byte{
+		"/css/main.css": []byte("body { color: red; }\n"),
+		"/images/a.png": pngBuf.Bytes(),
+	}
+
+	var violations []string
+	tr := New(DefaultConfigs["amp"], Options{
+		ReadFile: func(ref string) ([]byte, bool) {
+			b, ok := files[ref]
+			return b, ok
+		},
+		Report: func(v Violation) {
+			violations = append(violations, strings.Join([]string{v.Rule, v.Message, v.Source}, ": ")+" @"+strconv.Itoa(v.Line))
+		},
+	})
+
+	input := `<html><head>
+<script async src="https://cdn.ampproject.org/v0.js"></script>
+<script src="/js/app.js"></script>
+<script type="application/ld+json">{"a": 1}</script>
+<style amp-boilerplate>body{visibility:hidden}</style>
+<link rel="stylesheet" href="/css/main.css">
+<link rel="stylesheet" href="https://fonts.example.com/font.css">
+<style>h1 { color: blue; }</style>
+</head>
+<body><button onclick="alert(1)" class="b">Click</button>
+<img src="/images/a.png" alt="A"/>
+<img src="/images/missing.png" alt="M" width="10">
+<img src="/images/a.png" width="5" height="5">
+</body></html>`
+
+	var out bytes.Buffer
+	chain := transform.New(tr)
+	c.Assert(chain.Apply(&out, strings.NewReader(input)), qt.IsNil)
+
+	c.Assert(out.String(), qt.Equals, `<html><head>
+<script async src="https://cdn.ampproject.org/v0.js"></script>
+
+<script type="application/ld+json">{"a": 1}</script>
+<style amp-boilerplate>body{visibility:hidden}</style>
+
+<link rel="stylesheet" href="https://fonts.example.com/font.css">
+
+<style amp-custom>body { color: red; }
+h1 { color: blue; }</style></head>
+<body><button class="b">Click</button>
+<amp-img src="/images/a.png" alt="A" width="30" height="20"></amp-img>
+<amp-img src="/images/missing.png" alt="M" width="10"></amp-img>
+<amp-img src="/images/a.png" width="5" height="5"></amp-img>
+</body></html>`)
+
+	c.Assert(violations, qt.DeepEquals, []string{
+		`script: custom JavaScript removed: <script src="/js/app.js"></script> @3`,
+		`stylesheet: stylesheet could not be inlined: <link rel="stylesheet" href="https://fonts.example.com/font.css"> @7`,
+		`event-handler: inline event handler removed: <button onclick="alert(1)" class="b"> @10`,
+		`image-dimensions: image without width and height: <img src="/images/missing.png" alt="M" width="10"> @12`,
+	})
+}
+
+func TestTransformerCSSSize(t *testing.T) {
+	c := qt.New(t)
+
+	var violations []Violation
+	tr := New(Config{InlineCSS: true, MaxCSSSize: 10}, Options{
+		Report: func(v Violation) {
+			violations = append(violations, v)
+		},
+	})
+
+	var out bytes.Buffer
+	chain := transform.New(tr)
+	c.Assert(chain.Apply(&out, strings.NewReader(`<head><style>body { color: red; }</style></head>`)), qt.IsNil)
+	c.Assert(out.String(), qt.Equals, `<head><style>body { color: red; }</style></head>`)
+	c.Assert(violations, qt.HasLen, 1)
+	c.Assert(violations[0].String(), qt.Equals, "CSS is 20 bytes, more than 10 (css-size)")
+}
+
+func TestTransformerValidateHTML(t *testing.T) {
+	c := qt.New(t)
+
+	var violations []string
+	tr := New(DefaultConfigs["validate"], Options{
+		Report: func(v Violation) {
+			violations = append(violations, v.String()+" @"+strconv.Itoa(v.Line))
+		},
+	})
+
+	input := `<html><head><title>T</title></head>
+<body>
+<h2 id="a">A</h2>
+<p>Para<br>
+<div id="a" class="x" class="y"><span>Text</div>
+<ul><li>One<li>Two</ul>
+<svg><path d="M0 0"/></svg>
+</em>
+<section>
+</body></html>`
+
+	var out bytes.Buffer
+	chain := transform.New(tr)
+	c.Assert(chain.Apply(&out, strings.NewReader(input)), qt.IsNil)
+	c.Assert(out.String(), qt.Equals, input)
+
+	c.Assert(violations, qt.DeepEquals, []string{
+		`duplicate id "a", first used on line 3: <div id="a" class="x" class="y"> (duplicate-id) @5`,
+		`duplicate attribute "class": <div id="a" class="x" class="y"> (duplicate-attribute) @5`,
+		`element <span> is not closed: <span> (unclosed-element) @5`,
+		`end tag </em> without a start tag: </em> (stray-end-tag) @8`,
+		`element <section> is not closed: <section> (unclosed-element) @9`,
+	})
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package outputprofile
+
+import (
+	"bytes"
+	"io"
+
+	"golang.org/x/net/html"
+)
+
+// Elements without an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "keygen": true, "link": true,
+	"meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// Elements whose end tag may be omitted.
+var optionalEndTagElements = map[string]bool{
+	"html": true, "head": true, "body": true, "p": true, "li": true,
+	"dt": true, "dd": true, "rt": true, "rp": true, "optgroup": true,
+	"option": true, "colgroup": true, "caption": true, "thead": true,
+	"tbody": true, "tfoot": true, "tr": true, "td": true, "th": true,
+}
+
+type openElement struct {
+	name   string
+	offset int
+	tag    string
+}
+
+// validate checks the HTML for duplicate IDs and attributes, unclosed
+// elements and end tags without a start tag, e.g. from raw HTML in Markdown.
+func (p *processor) validate() {
+	var (
+		stack  []openElement
+		ids    = make(map[string]int)
+		offset int
+	)
+
+	unclosed := func(els []openElement) {
+		for _, el := range els {
+			if !optionalEndTagElements[el.name] {
+				p.report(RuleUnclosedElement, el.offset, el.tag, "element <%s> is not closed", el.name)
+			}
+		}
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(p.src))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				p.report(RuleParse, offset, "", "failed to parse HTML: %s", err)
+			}
+			break
+		}
+
+		raw := string(z.Raw())
+		start := offset
+		offset += len(raw)
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			seen := make(map[string]bool)
+			for _, attr := range tok.Attr {
+				if seen[attr.Key] {
+					p.report(RuleDuplicateAttribute, start, raw, "duplicate attribute %q", attr.Key)
+					continue
+				}
+				seen[attr.Key] = true
+				if attr.Key == "id" && attr.Namespace == "" {
+					if line, found := ids[attr.Val]; found {
+						p.report(RuleDuplicateID, start, raw, "duplicate id %q, first used on line %d", attr.Val, line)
+					} else {
+						ids[attr.Val] = bytes.Count(p.src[:start], []byte("\n")) + 1
+					}
+				}
+			}
+			if tt == html.StartTagToken && !voidElements[tok.Data] {
+				stack = append(stack, openElement{name: tok.Data, offset: start, tag: raw})
+			}
+		case html.EndTagToken:
+			tok := z.Token()
+			i := len(stack) - 1
+			for i >= 0 && stack[i].name != tok.Data {
+				i--
+			}
+			if i < 0 {
+				p.report(RuleStrayEndTag, start, raw, "end tag </%s> without a start tag", tok.Data)
+				continue
+			}
+			unclosed(stack[i+1:])
+			stack = stack[:i]
+		}
+	}
+
+	unclosed(stack)
+}
@@ -24,6 +24,7 @@ import (
 type Batcher struct {
 	filenotify.FileWatcher
 	interval time.Duration
+	match    func(filename string) bool
 	done     chan struct{}
 
 	Events chan []fsnotify.Event // Events are returned on this channel
@@ -33,13 +34,31 @@ type Batcher struct {
 // It will fall back to a poll based watcher if native isn's supported.
 // To always use polling, set poll to true.
 func New(intervalBatcher, intervalPoll time.Duration, poll bool) (*Batcher, error) {
+	c := DefaultConfig
+	c.Debounce = intervalBatcher
+	c.Interval = intervalPoll
+	if poll {
+		c.Type = TypePoll
+	}
+	return NewFromConfig(c)
+}
+
+// NewFromConfig creates and starts a Batcher configured by c.
+func NewFromConfig(c Config) (*Batcher, error) {
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+
 	var err error
 	var watcher filenotify.FileWatcher
 
-	if poll {
-		watcher = filenotify.NewPollingWatcher(intervalPoll)
-	} else {
-		watcher, err = filenotify.New(intervalPoll)
+	switch c.Type {
+	case TypePoll:
+		watcher = filenotify.NewPollingWatcher(c.Interval)
+	case TypeNative:
+		watcher, err = filenotify.NewEventWatcher()
+	default:
+		watcher, err = filenotify.New(c.Interval)
 	}
 
 	if err != nil {
@@ -48,7 +67,8 @@ func New(intervalBatcher, intervalPoll time.Duration, poll bool) (*Batcher, erro
 
 	batcher := &Batcher{}
 	batcher.FileWatcher = watcher
-	batcher.interval = intervalBatcher
+	batcher.interval = c.Debounce
+	batcher.match = c.Match
 	batcher.done = make(chan struct{}, 1)
 	batcher.Events = make(chan []fsnotify.Event, 1)
 
@@ -66,7 +86,9 @@ OuterLoop:
 	for {
 		select {
 		case ev := <-b.FileWatcher.Events():
-			evs = append(evs, ev)
+			if b.match(ev.Name) {
+				evs = append(evs, ev)
+			}
 		case <-tick:
 			if len(evs) == 0 {
 				continue
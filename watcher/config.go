@@ -0,0 +1,134 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gohugoio/hugo/config"
+	"github.com/gohugoio/hugo/hugofs/glob"
+	"github.com/mitchellh/mapstructure"
+)
+
+// The watcher types.
+const (
+	// TypeAuto uses the native file system events, falling back to
+	// polling if they are not supported.
+	TypeAuto = "auto"
+
+	// TypeNative uses the native file system events.
+	TypeNative = "native"
+
+	// TypePoll polls the file system for changes, which also works on e.g.
+	// network file systems, Docker volumes on macOS and WSL.
+	TypePoll = "poll"
+)
+
+// DefaultConfig holds the default watcher configuration.
+var DefaultConfig = Config{
+	Type:     TypeAuto,
+	Interval: 500 * time.Millisecond,
+	Debounce: 500 * time.Millisecond,
+}
+
+// Config configures the file watcher used by the server and --watch.
+type Config struct {
+	// One of auto, native or poll.
+	Type string
+
+	// The interval to poll the file system in.
+	Interval time.Duration
+
+	// The window to collect events in before rebuilding.
+	Debounce time.Duration
+
+	// Glob patterns, relative to the working dir, of the files to watch.
+	// If set, changes to other files in the working dir are ignored.
+	Include []string
+
+	// Glob patterns, relative to the working dir, of the files to ignore
+	// changes to.
+	Exclude []string
+
+	workingDir string
+	filter     *glob.FilenameFilter
+}
+
+// DecodeConfig decodes the watcher section in cfg.
+func DecodeConfig(cfg config.Provider) (Config, error) {
+	c := DefaultConfig
+	c.workingDir = cfg.GetString("workingDir")
+
+	if m := cfg.GetStringMap("watcher"); m != nil {
+		dc := &mapstructure.DecoderConfig{
+			Result:           &c,
+			DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+			WeaklyTypedInput: true,
+		}
+		decoder, err := mapstructure.NewDecoder(dc)
+		if err != nil {
+			return c, err
+		}
+		if err := decoder.Decode(m); err != nil {
+			return c, fmt.Errorf("failed to decode watcher config: %w", err)
+		}
+	}
+
+	return c, c.init()
+}
+
+func (c *Config) init() error {
+	c.Type = strings.ToLower(c.Type)
+	switch c.Type {
+	case "":
+		c.Type = TypeAuto
+	case TypeAuto, TypeNative, TypePoll:
+	default:
+		return fmt.Errorf("invalid watcher type %q, must be one of auto, native or poll", c.Type)
+	}
+
+	if c.Interval <= 0 {
+		c.Interval = DefaultConfig.Interval
+	}
+	if c.Debounce <= 0 {
+		c.Debounce = DefaultConfig.Debounce
+	}
+
+	var err error
+	c.filter, err = glob.NewFilenameFilter(c.Include, c.Exclude)
+	if err != nil {
+		return fmt.Errorf("failed to decode watcher config: %w", err)
+	}
+
+	return nil
+}
+
+// Match returns whether changes to filename should be reported.
+func (c Config) Match(filename string) bool {
+	if c.filter == nil {
+		return true
+	}
+	if c.workingDir != "" {
+		rel, err := filepath.Rel(c.workingDir, filename)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			// E.g. a theme or a module outside the project.
+			return true
+		}
+		filename = rel
+	}
+	return c.filter.Match(filepath.ToSlash(filename), false)
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 The Hugo Authors. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watcher
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/gohugoio/hugo/config"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	c := qt.New(t)
+
+	cfg := config.New()
+	conf, err := DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Type, qt.Equals, TypeAuto)
+	c.Assert(conf.Interval, qt.Equals, 500*time.Millisecond)
+	c.Assert(conf.Match("/any/file.md"), qt.IsTrue)
+
+	workingDir := filepath.FromSlash("/my/site")
+	cfg.Set("workingDir", workingDir)
+	cfg.Set("watcher", map[string]any{
+		"type":     "POLL",
+		"interval": "2s",
+		"debounce": "1s",
+		"exclude":  []string{"**.swp", "content/drafts/**"},
+	})
+	conf, err = DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Type, qt.Equals, TypePoll)
+	c.Assert(conf.Interval, qt.Equals, 2*time.Second)
+	c.Assert(conf.Debounce, qt.Equals, time.Second)
+	c.Assert(conf.Match(filepath.Join(workingDir, "content", "post.md")), qt.IsTrue)
+	c.Assert(conf.Match(filepath.Join(workingDir, "content", ".post.md.swp")), qt.IsFalse)
+	c.Assert(conf.Match(filepath.Join(workingDir, "content", "drafts", "a.md")), qt.IsFalse)
+
+	cfg.Set("watcher", map[string]any{
+		"include": []string{"content/**", "layouts/**"},
+	})
+	conf, err = DecodeConfig(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(conf.Match(filepath.Join(workingDir, "layouts", "index.html")), qt.IsTrue)
+	c.Assert(conf.Match(filepath.Join(workingDir, "static", "a.css")), qt.IsFalse)
+	c.Assert(conf.Match(filepath.FromSlash("/themes/mytheme/layouts/index.html")), qt.IsTrue)
+
+	cfg.Set("watcher", map[string]any{"type": "inotify"})
+	_, err = DecodeConfig(cfg)
+	c.Assert(err, qt.ErrorMatches, `invalid watcher type "inotify".*`)
+}